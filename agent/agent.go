@@ -5,6 +5,7 @@ package agent
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -161,8 +162,35 @@ const (
 	MongoOplogSize         = "MONGO_OPLOG_SIZE"
 	NumaCtlPreference      = "NUMA_CTL_PREFERENCE"
 	AllowsSecureConnection = "SECURE_CONTROLLER_CONNECTION"
+
+	// SecretsKey holds, base64 encoded, the key with which
+	// controllers encrypt secrets. It is kept in the agent
+	// configuration of controller machines, and never in the
+	// database; it is included in backups along with the rest of
+	// the agent configuration.
+	SecretsKey = "SECRETS_KEY"
 )
 
+// SecretsKeyValue returns the key held in the given configuration's
+// SecretsKey value, or nil if there is none.
+func SecretsKeyValue(config Config) ([]byte, error) {
+	value := config.Value(SecretsKey)
+	if value == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode secrets key")
+	}
+	return key, nil
+}
+
+// SetSecretsKeyValue sets the given configuration's SecretsKey value
+// to hold the given key.
+func SetSecretsKeyValue(config ConfigSetter, key []byte) {
+	config.SetValue(SecretsKey, base64.StdEncoding.EncodeToString(key))
+}
+
 // The Config interface is the sole way that the agent gets access to the
 // configuration information for the machine and unit agents.  There should
 // only be one instance of a config object for any given agent, and this
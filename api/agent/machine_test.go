@@ -68,6 +68,24 @@ func (s *servingInfoSuite) TestStateServingInfoPermission(c *gc.C) {
 	})
 }
 
+func (s *servingInfoSuite) TestSecretsKey(c *gc.C) {
+	st, _ := s.OpenAPIAsNewMachine(c, state.JobManageModel)
+
+	key, err := st.Agent().SecretsKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, jc.DeepEquals, s.BackingState.SecretsKey())
+}
+
+func (s *servingInfoSuite) TestSecretsKeyPermission(c *gc.C) {
+	st, _ := s.OpenAPIAsNewMachine(c)
+
+	_, err := st.Agent().SecretsKey()
+	c.Assert(errors.Cause(err), gc.DeepEquals, &rpc.RequestError{
+		Message: "permission denied",
+		Code:    "unauthorized access",
+	})
+}
+
 func (s *servingInfoSuite) TestIsMaster(c *gc.C) {
 	calledIsMaster := false
 	var fakeMongoIsMaster = func(session *mgo.Session, m mongo.WithAddresses) (bool, error) {
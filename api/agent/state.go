@@ -6,6 +6,7 @@ package agent
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 
 	"github.com/juju/juju/api/base"
@@ -55,6 +56,18 @@ func (st *State) StateServingInfo() (params.StateServingInfo, error) {
 	return results, err
 }
 
+// SecretsKey returns the key with which the controller encrypts
+// secrets, or nil if it has none. It returns an error satisfying
+// errors.IsNotSupported if the controller cannot return it.
+func (st *State) SecretsKey() ([]byte, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("secrets key on this juju controller")
+	}
+	var result params.BytesResult
+	err := st.facade.FacadeCall("SecretsKey", nil, &result)
+	return result.Result, err
+}
+
 // IsMaster reports whether the connected machine
 // agent lives at the same network address as the primary
 // mongo server for the replica set.
@@ -61,6 +61,19 @@ func (c *Client) UnitStatusHistory(kind params.HistoryKind, unitName string, siz
 	return &results, nil
 }
 
+// UnitHookHistory returns the hooks, actions and commands recently run
+// by the unit named in args that match its filter, most recent first.
+func (c *Client) UnitHookHistory(args params.HookHistoryArgs) ([]params.HookExecution, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("hook history on this juju controller")
+	}
+	var result params.HookHistoryResult
+	if err := c.facade.FacadeCall("UnitHookHistory", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Executions, nil
+}
+
 // LegacyStatus is a stub version of Status that 1.16 introduced. Should be
 // removed along with structs when api versioning makes it safe to do so.
 func (c *Client) LegacyStatus() (*params.LegacyStatus, error) {
@@ -80,6 +93,32 @@ func (c *Client) Resolved(unit string, retry bool) error {
 	return c.facade.FacadeCall("Resolved", p, nil)
 }
 
+// TriggerHook asks the unit to run the given hook once, outside its
+// normal workflow, so that a debug-code session can catch it. The
+// relation id is -1 for hooks that do not run in a relation context.
+func (c *Client) TriggerHook(unit, hook string, relationId int, remoteUnit string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("triggering hooks on this juju controller")
+	}
+	p := params.TriggerHook{
+		UnitName:   unit,
+		Hook:       hook,
+		RelationId: relationId,
+		RemoteUnit: remoteUnit,
+	}
+	return c.facade.FacadeCall("TriggerHook", p, nil)
+}
+
+// ClearTriggeredHook removes any hook triggered for the unit by
+// TriggerHook that it has not yet run.
+func (c *Client) ClearTriggeredHook(unit string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("triggering hooks on this juju controller")
+	}
+	p := params.TriggerHook{UnitName: unit}
+	return c.facade.FacadeCall("TriggerHook", p, nil)
+}
+
 // RetryProvisioning updates the provisioning status of a machine allowing the
 // provisioner to retry.
 func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error) {
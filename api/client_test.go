@@ -280,6 +280,32 @@ func (s *clientSuite) TestDestroyEnvironment(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *clientSuite) TestUnitHookHistory(c *gc.C) {
+	client := s.APIState.Client()
+	args := params.HookHistoryArgs{Unit: "wordpress/0", Result: "failed", Size: 5}
+	expected := []params.HookExecution{{
+		Kind:       "hook",
+		Name:       "install",
+		RelationId: -1,
+		Result:     "failed",
+		ExitCode:   1,
+	}}
+	cleanup := api.PatchClientFacadeCall(client,
+		func(req string, paramsIn interface{}, response interface{}) error {
+			c.Assert(req, gc.Equals, "UnitHookHistory")
+			c.Assert(paramsIn, jc.DeepEquals, args)
+			result, ok := response.(*params.HookHistoryResult)
+			c.Assert(ok, jc.IsTrue)
+			result.Executions = expected
+			return nil
+		})
+	defer cleanup()
+
+	obtained, err := client.UnitHookHistory(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(obtained, jc.DeepEquals, expected)
+}
+
 func (s *clientSuite) TestShareEnvironmentThreeUsers(c *gc.C) {
 	client := s.APIState.Client()
 	existingUser := s.Factory.MakeModelUser(c, nil)
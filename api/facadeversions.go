@@ -17,7 +17,7 @@ import (
 var facadeVersions = map[string]int{
 	"Action":                       1,
 	"Addresser":                    2,
-	"Agent":                        2,
+	"Agent":                        3,
 	"AgentTools":                   1,
 	"AllWatcher":                   1,
 	"AllModelWatcher":              2,
@@ -26,7 +26,7 @@ var facadeVersions = map[string]int{
 	"Block":                        2,
 	"Charms":                       2,
 	"CharmRevisionUpdater":         1,
-	"Client":                       1,
+	"Client":                       3,
 	"Cleaner":                      2,
 	"Controller":                   2,
 	"Deployer":                     1,
@@ -34,7 +34,7 @@ var facadeVersions = map[string]int{
 	"DiskManager":                  2,
 	"EntityWatcher":                2,
 	"FilesystemAttachmentsWatcher": 2,
-	"Firewaller":                   2,
+	"Firewaller":                   3,
 	"HighAvailability":             2,
 	"ImageManager":                 2,
 	"ImageMetadata":                2,
@@ -43,7 +43,7 @@ var facadeVersions = map[string]int{
 	"KeyUpdater":                   1,
 	"LeadershipService":            2,
 	"Logger":                       1,
-	"MachineManager":               2,
+	"MachineManager":               5,
 	"Machiner":                     1,
 	"MetricsDebug":                 1,
 	"MetricsManager":               1,
@@ -52,13 +52,15 @@ var facadeVersions = map[string]int{
 	"ModelManager":                 2,
 	"NotifyWatcher":                1,
 	"Pinger":                       1,
-	"Provisioner":                  2,
+	"Provisioner":                  3,
 	"ProxyUpdater":                 1,
 	"Reboot":                       2,
 	"RelationUnitsWatcher":         1,
+	"RemoteRelations":              1,
 	"Resumer":                      2,
 	"RetryStrategy":                1,
-	"Service":                      3,
+	"Secrets":                      1,
+	"Service":                      8,
 	"Storage":                      2,
 	"Spaces":                       2,
 	"Subnets":                      2,
@@ -67,7 +69,7 @@ var facadeVersions = map[string]int{
 	"StringsWatcher":               1,
 	"Upgrader":                     1,
 	"UnitAssigner":                 1,
-	"Uniter":                       3,
+	"Uniter":                       10,
 	"UserManager":                  1,
 	"VolumeAttachmentsWatcher":     2,
 	"Undertaker":                   1,
@@ -115,3 +115,26 @@ func (st *State) WatchOpenedPorts() (watcher.StringsWatcher, error) {
 	w := apiwatcher.NewStringsWatcher(st.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// WatchSubnets returns a NotifyWatcher that notifies of changes to the
+// subnets and spaces of the current model, which may change the CIDRs
+// of the subnets that services are bound to.
+//
+// Controllers older than version 3 of the facade do not restrict
+// services to the subnets they are bound to, so there is nothing to
+// watch.
+func (st *State) WatchSubnets() (watcher.NotifyWatcher, error) {
+	if st.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("watching subnets")
+	}
+	var result params.NotifyWatchResult
+	err := st.facade.FacadeCall("WatchSubnets", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Error; err != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewNotifyWatcher(st.facade.RawAPICaller(), result)
+	return w, nil
+}
@@ -6,6 +6,7 @@ package firewaller
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 
 	"github.com/juju/juju/api/common"
@@ -75,3 +76,136 @@ func (s *Service) IsExposed() (bool, error) {
 	}
 	return result.Result, nil
 }
+
+// ExposedCIDRs returns the CIDRs of the addresses that may access the
+// explicitly open ports of the service, if it is exposed. If there are
+// none, the ports may be accessed from anywhere.
+//
+// Controllers older than version 3 of the facade cannot restrict
+// exposed services to CIDRs, so there are none.
+func (s *Service) ExposedCIDRs() ([]string, error) {
+	if s.st.BestAPIVersion() < 3 {
+		return nil, nil
+	}
+	var results params.StringsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetExposedCIDRs", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// BoundSubnetCIDRs returns the CIDRs of the subnets that the ports of
+// the service should be restricted to, because all of its endpoints are
+// bound to the spaces containing them. If there are none, the ports
+// need not be restricted to particular subnets.
+//
+// Controllers older than version 3 of the facade do not restrict
+// services to the subnets they are bound to, so there are none.
+func (s *Service) BoundSubnetCIDRs() ([]string, error) {
+	if s.st.BestAPIVersion() < 3 {
+		return nil, nil
+	}
+	var results params.StringsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetBoundSubnetCIDRs", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// IsLoadBalanced returns whether the service is exposed through a
+// provider load balancer.
+//
+// Controllers older than version 3 of the facade cannot expose
+// services through load balancers, so the service is not.
+func (s *Service) IsLoadBalanced() (bool, error) {
+	if s.st.BestAPIVersion() < 3 {
+		return false, nil
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetLoadBalanced", args, &results)
+	if err != nil {
+		return false, err
+	}
+	if len(results.Results) != 1 {
+		return false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
+// LoadBalancerAddress returns the recorded virtual address of the
+// provider load balancer in front of the service, or an empty string
+// if there is none.
+//
+// Controllers older than version 3 of the facade do not record load
+// balancer addresses, so there is none.
+func (s *Service) LoadBalancerAddress() (string, error) {
+	if s.st.BestAPIVersion() < 3 {
+		return "", nil
+	}
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetLoadBalancerAddresses", args, &results)
+	if err != nil {
+		return "", err
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// SetLoadBalancerAddress records the virtual address of the provider
+// load balancer in front of the service. An empty address records that
+// the service has no load balancer.
+//
+// Controllers older than version 3 of the facade do not record load
+// balancer addresses.
+func (s *Service) SetLoadBalancerAddress(address string) error {
+	if s.st.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("recording load balancer addresses")
+	}
+	var results params.ErrorResults
+	args := params.EntitiesAddresses{
+		Entities: []params.EntityAddress{{Tag: s.tag.String(), Address: address}},
+	}
+	err := s.st.facade.FacadeCall("SetLoadBalancerAddresses", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
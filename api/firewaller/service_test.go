@@ -91,3 +91,51 @@ func (s *serviceSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *serviceSuite) TestExposedCIDRs(c *gc.C) {
+	cidrs, err := s.apiService.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.HasLen, 0)
+
+	err = s.service.SetExposed("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, err = s.apiService.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.0/8"})
+}
+
+func (s *serviceSuite) TestBoundSubnetCIDRs(c *gc.C) {
+	cidrs, err := s.apiService.BoundSubnetCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.HasLen, 0)
+}
+
+func (s *serviceSuite) TestIsLoadBalanced(c *gc.C) {
+	err := s.service.SetExposedLoadBalanced()
+	c.Assert(err, jc.ErrorIsNil)
+
+	isLoadBalanced, err := s.apiService.IsLoadBalanced()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isLoadBalanced, jc.IsTrue)
+
+	err = s.service.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	isLoadBalanced, err = s.apiService.IsLoadBalanced()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isLoadBalanced, jc.IsFalse)
+}
+
+func (s *serviceSuite) TestSetLoadBalancerAddress(c *gc.C) {
+	err := s.apiService.SetLoadBalancerAddress("0.30.0.1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.LoadBalancerAddress(), gc.Equals, "0.30.0.1")
+
+	address, err := s.apiService.LoadBalancerAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(address, gc.Equals, "0.30.0.1")
+}
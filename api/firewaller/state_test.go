@@ -100,3 +100,18 @@ func (s *stateSuite) TestWatchOpenedPorts(c *gc.C) {
 	wc.AssertChange("1:juju-public")
 	wc.AssertNoChange()
 }
+
+func (s *stateSuite) TestWatchSubnets(c *gc.C) {
+	w, err := s.firewaller.WatchSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	wc := watchertest.NewNotifyWatcherC(c, w, s.BackingState.StartSync)
+	defer wc.AssertStops()
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	// Add a subnet and make sure it's detected.
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -26,6 +26,14 @@ func NewClient(st base.APICallCloser) *Client {
 
 // AddMachines adds new machines with the supplied parameters, creating any requested disks.
 func (client *Client) AddMachines(machineParams []params.AddMachineParams) ([]params.AddMachinesResult, error) {
+	for _, p := range machineParams {
+		if p.Profile != "" && client.BestAPIVersion() < 3 {
+			return nil, errors.NotSupportedf("adding machines with profiles on this juju controller")
+		}
+		if p.CloudInitUserData != "" && client.BestAPIVersion() < 4 {
+			return nil, errors.NotSupportedf("adding machines with cloud-init user data on this juju controller")
+		}
+	}
 	args := params.AddMachines{
 		MachineParams: machineParams,
 	}
@@ -36,3 +44,33 @@ func (client *Client) AddMachines(machineParams []params.AddMachineParams) ([]pa
 	}
 	return results.Machines, err
 }
+
+// AddMachineProfile adds a named machine profile to the model.
+func (client *Client) AddMachineProfile(profile params.MachineProfile) error {
+	if client.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("machine profiles on this juju controller")
+	}
+	if profile.RootDiskSource != "" && client.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("machine profiles with root-disk sources on this juju controller")
+	}
+	args := params.MachineProfiles{
+		Profiles: []params.MachineProfile{profile},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("AddMachineProfiles", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListMachineProfiles returns all the machine profiles in the model.
+func (client *Client) ListMachineProfiles() ([]params.MachineProfile, error) {
+	if client.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("machine profiles on this juju controller")
+	}
+	var result params.MachineProfilesResult
+	if err := client.facade.FacadeCall("ListMachineProfiles", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Profiles, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -116,3 +117,95 @@ func (s *MachinemanagerSuite) TestAddMachinesResultCountInvalid(c *gc.C) {
 		c.Check(err, gc.ErrorMatches, fmt.Sprintf("expected 1 result, got %d", n))
 	}
 }
+
+// versionedCaller is an APICallerFunc that reports the given version of
+// every facade.
+type versionedCaller struct {
+	testing.APICallerFunc
+	version int
+}
+
+func (c versionedCaller) BestFacadeVersion(facade string) int {
+	return c.version
+}
+
+func (s *MachinemanagerSuite) TestAddMachineProfile(c *gc.C) {
+	profile := params.MachineProfile{
+		Name:              "database",
+		Series:            "xenial",
+		Constraints:       constraints.MustParse("spaces=db"),
+		CloudInitUserData: "packages: [ca-certificates]",
+	}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(version, gc.Equals, 3)
+		c.Check(request, gc.Equals, "AddMachineProfiles")
+		c.Check(arg, jc.DeepEquals, params.MachineProfiles{
+			Profiles: []params.MachineProfile{profile},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		callCount++
+		return nil
+	})
+	st := machinemanager.NewClient(versionedCaller{apiCaller, 3})
+	err := st.AddMachineProfile(profile)
+	c.Check(err, gc.ErrorMatches, "boom")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachinemanagerSuite) TestListMachineProfiles(c *gc.C) {
+	profiles := []params.MachineProfile{{
+		Name:        "database",
+		Constraints: constraints.MustParse("mem=4G"),
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "ListMachineProfiles")
+		c.Assert(result, gc.FitsTypeOf, &params.MachineProfilesResult{})
+		*(result.(*params.MachineProfilesResult)) = params.MachineProfilesResult{
+			Profiles: profiles,
+		}
+		return nil
+	})
+	st := machinemanager.NewClient(versionedCaller{apiCaller, 3})
+	result, err := st.ListMachineProfiles()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, profiles)
+}
+
+func (s *MachinemanagerSuite) TestMachineProfilesNotSupported(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	st := machinemanager.NewClient(versionedCaller{apiCaller, 2})
+	err := st.AddMachineProfile(params.MachineProfile{Name: "database"})
+	c.Check(err, gc.ErrorMatches, "machine profiles on this juju controller not supported")
+	_, err = st.ListMachineProfiles()
+	c.Check(err, gc.ErrorMatches, "machine profiles on this juju controller not supported")
+	_, err = st.AddMachines([]params.AddMachineParams{{Profile: "database"}})
+	c.Check(err, gc.ErrorMatches, "adding machines with profiles on this juju controller not supported")
+}
+
+func (s *MachinemanagerSuite) TestAddMachinesCloudInitUserDataNotSupported(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	st := machinemanager.NewClient(versionedCaller{apiCaller, 3})
+	_, err := st.AddMachines([]params.AddMachineParams{{CloudInitUserData: "packages: [curl]"}})
+	c.Check(err, gc.ErrorMatches, "adding machines with cloud-init user data on this juju controller not supported")
+}
+
+func (s *MachinemanagerSuite) TestAddMachineProfileRootDiskSourceNotSupported(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	st := machinemanager.NewClient(versionedCaller{apiCaller, 4})
+	err := st.AddMachineProfile(params.MachineProfile{Name: "database", RootDiskSource: "ssd"})
+	c.Check(err, gc.ErrorMatches, "machine profiles with root-disk sources on this juju controller not supported")
+}
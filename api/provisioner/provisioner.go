@@ -190,16 +190,35 @@ func (st *State) GetContainerInterfaceInfo(containerTag names.MachineTag) ([]net
 func (st *State) prepareOrGetContainerInterfaceInfo(
 	containerTag names.MachineTag, allocateNewAddress bool) (
 	[]network.InterfaceInfo, error) {
-	var result params.MachineNetworkConfigResults
-	args := params.Entities{
-		Entities: []params.Entity{{Tag: containerTag.String()}},
-	}
 	facadeName := ""
 	if allocateNewAddress {
 		facadeName = "PrepareContainerInterfaceInfo"
 	} else {
 		facadeName = "GetContainerInterfaceInfo"
 	}
+	return st.containerInterfaceInfo(facadeName, containerTag)
+}
+
+// PrepareContainerSpaceInterfaces allocates a static address for the
+// container on each of the spaces it needs access to, and returns the
+// information needed to attach the container directly to the host's
+// network devices on those spaces. The result is empty if the container
+// needs no spaces. If the API server does not support this, an error
+// satisfying errors.IsNotSupported is returned.
+func (st *State) PrepareContainerSpaceInterfaces(containerTag names.MachineTag) ([]network.InterfaceInfo, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("allocating container addresses on spaces")
+	}
+	return st.containerInterfaceInfo("PrepareContainerSpaceInterfaces", containerTag)
+}
+
+// containerInterfaceInfo calls the named facade method to get the
+// network configuration of the container.
+func (st *State) containerInterfaceInfo(facadeName string, containerTag names.MachineTag) ([]network.InterfaceInfo, error) {
+	var result params.MachineNetworkConfigResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: containerTag.String()}},
+	}
 	if err := st.facade.FacadeCall(facadeName, args, &result); err != nil {
 		return nil, err
 	}
@@ -212,21 +231,22 @@ func (st *State) prepareOrGetContainerInterfaceInfo(
 	ifaceInfo := make([]network.InterfaceInfo, len(result.Results[0].Config))
 	for i, netInfo := range result.Results[0].Config {
 		ifaceInfo[i] = network.InterfaceInfo{
-			DeviceIndex:      netInfo.DeviceIndex,
-			MACAddress:       netInfo.MACAddress,
-			CIDR:             netInfo.CIDR,
-			NetworkName:      netInfo.NetworkName,
-			ProviderId:       network.Id(netInfo.ProviderId),
-			ProviderSubnetId: network.Id(netInfo.ProviderSubnetId),
-			VLANTag:          netInfo.VLANTag,
-			InterfaceName:    netInfo.InterfaceName,
-			Disabled:         netInfo.Disabled,
-			NoAutoStart:      netInfo.NoAutoStart,
-			ConfigType:       network.InterfaceConfigType(netInfo.ConfigType),
-			Address:          network.NewAddress(netInfo.Address),
-			DNSServers:       network.NewAddresses(netInfo.DNSServers...),
-			GatewayAddress:   network.NewAddress(netInfo.GatewayAddress),
-			ExtraConfig:      netInfo.ExtraConfig,
+			DeviceIndex:         netInfo.DeviceIndex,
+			MACAddress:          netInfo.MACAddress,
+			CIDR:                netInfo.CIDR,
+			NetworkName:         netInfo.NetworkName,
+			ProviderId:          network.Id(netInfo.ProviderId),
+			ProviderSubnetId:    network.Id(netInfo.ProviderSubnetId),
+			VLANTag:             netInfo.VLANTag,
+			InterfaceName:       netInfo.InterfaceName,
+			ParentInterfaceName: netInfo.ParentInterfaceName,
+			Disabled:            netInfo.Disabled,
+			NoAutoStart:         netInfo.NoAutoStart,
+			ConfigType:          network.InterfaceConfigType(netInfo.ConfigType),
+			Address:             network.NewAddress(netInfo.Address),
+			DNSServers:          network.NewAddresses(netInfo.DNSServers...),
+			GatewayAddress:      network.NewAddress(netInfo.GatewayAddress),
+			ExtraConfig:         netInfo.ExtraConfig,
 		}
 	}
 	return ifaceInfo, nil
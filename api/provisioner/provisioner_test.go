@@ -882,6 +882,44 @@ func (s *provisionerSuite) TestPrepareContainerInterfaceInfo(c *gc.C) {
 	c.Assert(ifaceInfo, jc.DeepEquals, expectInfo)
 }
 
+func (s *provisionerSuite) TestPrepareContainerSpaceInterfaces(c *gc.C) {
+	// This test exercises just the success path, all the other cases
+	// are already tested in the apiserver package.
+	_, err := s.State.AddSubnet(state.SubnetInfo{
+		ProviderId:        "dummy-private",
+		CIDR:              "0.10.0.0/24",
+		AllocatableIPLow:  "0.10.0.5",
+		AllocatableIPHigh: "0.10.0.5",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("db", "", []string{"0.10.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("spaces=db"),
+	}
+	container, err := s.State.AddMachineInsideMachine(template, s.machine.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ifaceInfo, err := s.provisioner.PrepareContainerSpaceInterfaces(container.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ifaceInfo, gc.HasLen, 1)
+	c.Assert(ifaceInfo[0].MACAddress, gc.Not(gc.Equals), "")
+	ifaceInfo[0].MACAddress = ""
+	c.Assert(ifaceInfo, jc.DeepEquals, []network.InterfaceInfo{{
+		DeviceIndex:         0,
+		CIDR:                "0.10.0.0/24",
+		ProviderSubnetId:    "dummy-private",
+		InterfaceName:       "eth0",
+		ParentInterfaceName: "eth0",
+		ConfigType:          network.ConfigStatic,
+		Address:             network.NewAddress("0.10.0.5"),
+		DNSServers:          network.NewAddresses("ns1.dummy", "ns2.dummy"),
+		GatewayAddress:      network.NewAddress("0.10.0.1"),
+	}})
+}
+
 func (s *provisionerSuite) TestReleaseContainerAddresses(c *gc.C) {
 	// This test exercises just the success path, all the other cases
 	// are already tested in the apiserver package.
@@ -0,0 +1,56 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/remoterelations"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type remoteRelationsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&remoteRelationsSuite{})
+
+func (s *remoteRelationsSuite) TestSyncRemoteRelations(c *gc.C) {
+	var called bool
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "RemoteRelations")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SyncRemoteRelations")
+		c.Check(arg, gc.IsNil)
+		c.Check(result, gc.IsNil)
+		called = true
+		return errors.New("boom")
+	})
+	api := remoterelations.NewAPI(apiCaller)
+	err := api.SyncRemoteRelations()
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *remoteRelationsSuite) TestWatchRemoteRelationsError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "RemoteRelations")
+		c.Check(request, gc.Equals, "WatchRemoteRelations")
+		c.Assert(result, gc.FitsTypeOf, &params.NotifyWatchResult{})
+		*(result.(*params.NotifyWatchResult)) = params.NotifyWatchResult{
+			Error: &params.Error{Message: "nope"},
+		}
+		return nil
+	})
+	api := remoterelations.NewAPI(apiCaller)
+	w, err := api.WatchRemoteRelations()
+	c.Assert(err, gc.ErrorMatches, "nope")
+	c.Assert(w, gc.IsNil)
+}
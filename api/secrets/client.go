@@ -0,0 +1,43 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets provides access to the Secrets API facade.
+package secrets
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the secrets API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the secrets API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Secrets")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ListSecrets returns the details of the secrets with the given IDs, or
+// of all the secrets in the model if no IDs are given. If reveal is
+// true, the content of the secrets is also returned; only
+// administrators may reveal secrets.
+func (c *Client) ListSecrets(ids []string, reveal bool) ([]params.ListSecretResult, error) {
+	args := params.ListSecretsArgs{
+		IDs:    ids,
+		Reveal: reveal,
+	}
+	var results params.ListSecretResults
+	if err := c.facade.FacadeCall("ListSecrets", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(ids) > 0 && len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d results, got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
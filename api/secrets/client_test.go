@@ -0,0 +1,75 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/secrets"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type secretsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func (s *secretsSuite) TestListSecrets(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Secrets")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ListSecrets")
+			c.Check(a, jc.DeepEquals, params.ListSecretsArgs{
+				IDs:    []string{"1"},
+				Reveal: true,
+			})
+			c.Assert(response, gc.FitsTypeOf, &params.ListSecretResults{})
+			*(response.(*params.ListSecretResults)) = params.ListSecretResults{
+				Results: []params.ListSecretResult{{
+					Result: &params.SecretDetails{
+						ID:   "1",
+						Data: map[string]string{"password": "sekrit"},
+					},
+				}},
+			}
+			return nil
+		})
+	client := secrets.NewClient(apiCaller)
+	results, err := client.ListSecrets([]string{"1"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(results, jc.DeepEquals, []params.ListSecretResult{{
+		Result: &params.SecretDetails{
+			ID:   "1",
+			Data: map[string]string{"password": "sekrit"},
+		},
+	}})
+}
+
+func (s *secretsSuite) TestListSecretsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := secrets.NewClient(apiCaller)
+	_, err := client.ListSecrets(nil, false)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
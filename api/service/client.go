@@ -8,6 +8,8 @@
 package service
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -87,6 +89,9 @@ type DeployArgs struct {
 	// Collection of resource names for the service, with the value being the
 	// unique ID of a pre-uploaded resources in storage.
 	Resources map[string]string
+	// MachineProfile names the machine profile applied to the
+	// machines created for the service's units.
+	MachineProfile string
 }
 
 // Deploy obtains the charm, either locally or from the charm store,
@@ -96,6 +101,9 @@ type DeployArgs struct {
 // using constraints. Placement directives, if provided, specify the
 // machine on which the charm is deployed.
 func (c *Client) Deploy(args DeployArgs) error {
+	if args.MachineProfile != "" && c.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("deploying with machine profiles on this juju controller")
+	}
 	deployArgs := params.ServicesDeploy{
 		Services: []params.ServiceDeploy{{
 			ServiceName:      args.ServiceName,
@@ -109,6 +117,7 @@ func (c *Client) Deploy(args DeployArgs) error {
 			Storage:          args.Storage,
 			EndpointBindings: args.EndpointBindings,
 			Resources:        args.Resources,
+			MachineProfile:   args.MachineProfile,
 		}},
 	}
 	var results params.ErrorResults
@@ -196,9 +205,32 @@ func (c *Client) SetConstraints(service string, constraints constraints.Value) e
 }
 
 // Expose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
-func (c *Client) Expose(service string) error {
-	params := params.ServiceExpose{ServiceName: service}
+// were also explicitly marked by units as open. If any CIDRs are
+// specified, the ports are only exposed to addresses within them.
+func (c *Client) Expose(service string, toCIDRs ...string) error {
+	if len(toCIDRs) > 0 && c.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("exposing to specific CIDRs on this juju controller")
+	}
+	params := params.ServiceExpose{
+		ServiceName: service,
+		ToCIDRs:     toCIDRs,
+	}
+	return c.facade.FacadeCall("Expose", params, nil)
+}
+
+// ExposeLoadBalanced exposes the service as Expose does, but through a
+// provider load balancer that forwards the service's open ports to its
+// units. The load balancer's address is reported in the service's
+// status once it has been created.
+func (c *Client) ExposeLoadBalanced(service string, toCIDRs ...string) error {
+	if c.BestAPIVersion() < 8 {
+		return errors.NotSupportedf("exposing through a load balancer on this juju controller")
+	}
+	params := params.ServiceExpose{
+		ServiceName:  service,
+		ToCIDRs:      toCIDRs,
+		LoadBalancer: true,
+	}
 	return c.facade.FacadeCall("Expose", params, nil)
 }
 
@@ -244,13 +276,62 @@ func (c *Client) CharmRelations(service string) ([]string, error) {
 }
 
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
+// An endpoint may be given as the URL of a service offer from another model.
 func (c *Client) AddRelation(endpoints ...string) (*params.AddRelationResults, error) {
+	for _, ep := range endpoints {
+		if strings.Contains(ep, "/") && c.BestAPIVersion() < 5 {
+			return nil, errors.NotSupportedf("relating to service offers on this juju controller")
+		}
+	}
 	var addRelRes params.AddRelationResults
 	params := params.AddRelation{Endpoints: endpoints}
 	err := c.facade.FacadeCall("AddRelation", params, &addRelRes)
 	return &addRelRes, err
 }
 
+// Offer makes the named endpoints of the service available for services
+// in other models on the controller to relate to, under the given offer
+// name. If no endpoints are named, all the service's endpoints that can
+// be offered are; if no offer name is given, the service name is used.
+func (c *Client) Offer(service string, endpoints []string, offerName string) error {
+	if c.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("offering services on this juju controller")
+	}
+	args := params.ServiceOffers{
+		Offers: []params.ServiceOffer{{
+			ServiceName: service,
+			Endpoints:   endpoints,
+			OfferName:   offerName,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("Offer", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// SetZonePolicy sets the policy used to place the machines created for
+// the service's units into availability zones. The policy must be one of
+// "spread", "pack" or "zones=<zone>[,<zone>...]"; an empty policy clears
+// any policy previously set.
+func (c *Client) SetZonePolicy(service, policy string) error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotSupportedf("setting zone policies on this juju controller")
+	}
+	args := params.ServiceZonePolicies{
+		Policies: []params.ServiceZonePolicy{{
+			ServiceName: service,
+			Policy:      policy,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetZonePolicy", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // DestroyRelation removes the relation between the specified endpoints.
 func (c *Client) DestroyRelation(endpoints ...string) error {
 	params := params.DestroyRelation{Endpoints: endpoints}
@@ -153,3 +153,87 @@ func (s *serviceSuite) TestServiceSetCharm(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *serviceSuite) TestServiceExposeToCIDRs(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "Expose")
+		args, ok := a.(params.ServiceExpose)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(args, jc.DeepEquals, params.ServiceExpose{
+			ServiceName: "service",
+			ToCIDRs:     []string{"10.0.0.0/8"},
+		})
+		return nil
+	})
+	err := s.client.Expose("service", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *serviceSuite) TestServiceExposeLoadBalanced(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "Expose")
+		args, ok := a.(params.ServiceExpose)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(args, jc.DeepEquals, params.ServiceExpose{
+			ServiceName:  "service",
+			ToCIDRs:      []string{"10.0.0.0/8"},
+			LoadBalancer: true,
+		})
+		return nil
+	})
+	err := s.client.ExposeLoadBalanced("service", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *serviceSuite) TestServiceOffer(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "Offer")
+		args, ok := a.(params.ServiceOffers)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(args, jc.DeepEquals, params.ServiceOffers{
+			Offers: []params.ServiceOffer{{
+				ServiceName: "mysql",
+				Endpoints:   []string{"server"},
+				OfferName:   "db",
+			}},
+		})
+		result, ok := response.(*params.ErrorResults)
+		c.Assert(ok, jc.IsTrue)
+		result.Results = []params.ErrorResult{{}}
+		return nil
+	})
+	err := s.client.Offer("mysql", []string{"server"}, "db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *serviceSuite) TestServiceSetZonePolicy(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "SetZonePolicy")
+		args, ok := a.(params.ServiceZonePolicies)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(args, jc.DeepEquals, params.ServiceZonePolicies{
+			Policies: []params.ServiceZonePolicy{{
+				ServiceName: "mysql",
+				Policy:      "spread",
+			}},
+		})
+		result, ok := response.(*params.ErrorResults)
+		c.Assert(ok, jc.IsTrue)
+		result.Results = []params.ErrorResult{{}}
+		return nil
+	})
+	err := s.client.SetZonePolicy("mysql", "spread")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
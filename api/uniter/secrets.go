@@ -0,0 +1,169 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// CreateSecret creates a secret, owned by the unit's service, with the
+// given content and returns its ID. If rotateInterval is non-zero, the
+// service's leader will be asked to rotate the secret at that interval.
+func (u *Unit) CreateSecret(description string, data map[string]string, rotateInterval time.Duration) (string, error) {
+	if err := u.checkSecretsSupported(); err != nil {
+		return "", err
+	}
+	var results params.StringResults
+	args := params.CreateSecretArgs{
+		Args: []params.CreateSecretArg{{
+			UnitTag:        u.tag.String(),
+			Description:    description,
+			Data:           data,
+			RotateInterval: rotateInterval,
+		}},
+	}
+	err := u.st.facade.FacadeCall("CreateSecrets", args, &results)
+	if err != nil {
+		return "", err
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// SecretValue returns the content and revision of the secret with the
+// given ID, if the unit may read it. If revision is non-zero, the
+// content of that revision is returned instead of the current one.
+func (u *Unit) SecretValue(id string, revision int) (map[string]string, int, error) {
+	if err := u.checkSecretsSupported(); err != nil {
+		return nil, 0, err
+	}
+	if revision != 0 && u.st.BestAPIVersion() < 10 {
+		return nil, 0, errors.NotSupportedf("reading previous secret revisions on this juju controller")
+	}
+	var results params.SecretValueResults
+	args := params.SecretArgs{
+		Args: []params.SecretArg{{
+			UnitTag:  u.tag.String(),
+			ID:       id,
+			Revision: revision,
+		}},
+	}
+	err := u.st.facade.FacadeCall("SecretValues", args, &results)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(results.Results) != 1 {
+		return nil, 0, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return result.Data, result.Revision, nil
+}
+
+// UpdateSecret replaces the content of the secret with the given ID,
+// which must be owned by the unit's service.
+func (u *Unit) UpdateSecret(id string, data map[string]string) error {
+	if err := u.checkSecretsSupported(); err != nil {
+		return err
+	}
+	var results params.ErrorResults
+	args := params.UpdateSecretArgs{
+		Args: []params.UpdateSecretArg{{
+			UnitTag: u.tag.String(),
+			ID:      id,
+			Data:    data,
+		}},
+	}
+	err := u.st.facade.FacadeCall("UpdateSecrets", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
+// GrantSecret gives the unit, or the units of the relation, with the
+// given tag access to the secret with the given ID, which must be owned
+// by the unit's service.
+func (u *Unit) GrantSecret(id string, grantee names.Tag) error {
+	if err := u.checkSecretsSupported(); err != nil {
+		return err
+	}
+	var results params.ErrorResults
+	args := params.GrantSecretArgs{
+		Args: []params.GrantSecretArg{{
+			UnitTag:    u.tag.String(),
+			ID:         id,
+			GranteeTag: grantee.String(),
+		}},
+	}
+	err := u.st.facade.FacadeCall("GrantSecrets", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
+// SecretsToRotate returns the IDs of the secrets owned by the unit's
+// service that are due to be rotated.
+func (u *Unit) SecretsToRotate() ([]string, error) {
+	if err := u.checkSecretsSupported(); err != nil {
+		return nil, err
+	}
+	var results params.StringsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("SecretsToRotate", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// SecretRotated records that the secret with the given ID, owned by the
+// unit's service, has been rotated.
+func (u *Unit) SecretRotated(id string) error {
+	if err := u.checkSecretsSupported(); err != nil {
+		return err
+	}
+	var results params.ErrorResults
+	args := params.SecretArgs{
+		Args: []params.SecretArg{{UnitTag: u.tag.String(), ID: id}},
+	}
+	err := u.st.facade.FacadeCall("SecretsRotated", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
+// checkSecretsSupported returns an error satisfying
+// errors.IsNotSupported if the controller does not support secrets.
+func (u *Unit) checkSecretsSupported() error {
+	if u.st.BestAPIVersion() < 6 {
+		return errors.NotSupportedf("secrets on this juju controller")
+	}
+	return nil
+}
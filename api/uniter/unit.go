@@ -210,6 +210,30 @@ func (u *Unit) ConfigSettings() (charm.Settings, error) {
 	return charm.Settings(result.Settings), nil
 }
 
+// GoalState returns the units expected for the unit's service, and for
+// each service related to it, with their status.
+func (u *Unit) GoalState() (*params.GoalState, error) {
+	if u.st.BestAPIVersion() < 5 {
+		return nil, errors.NotSupportedf("goal state on this juju controller")
+	}
+	var results params.GoalStateResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("GoalState", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
 // ServiceName returns the service name.
 func (u *Unit) ServiceName() string {
 	service, err := names.UnitService(u.Name())
@@ -510,6 +534,131 @@ func (u *Unit) SetCharmURL(curl *charm.URL) error {
 	return result.OneError()
 }
 
+// SetWorkloadVersion records the version of the workload software
+// running on the unit.
+func (u *Unit) SetWorkloadVersion(version string) error {
+	if u.st.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("setting workload versions on this juju controller")
+	}
+	var result params.ErrorResults
+	args := params.EntityWorkloadVersions{
+		Entities: []params.EntityWorkloadVersion{
+			{Tag: u.tag.String(), WorkloadVersion: version},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetWorkloadVersion", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// TriggeredHook returns the hook the unit has been asked to run for a
+// debug-code session, or nil if there is none.
+func (u *Unit) TriggeredHook() (*params.TriggeredHook, error) {
+	if u.st.BestAPIVersion() < 8 {
+		return nil, errors.NotSupportedf("triggered hooks on this juju controller")
+	}
+	var results params.TriggeredHookResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("TriggeredHook", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Hook, nil
+}
+
+// ClearTriggeredHook removes any hook the unit has been asked to run
+// for a debug-code session.
+func (u *Unit) ClearTriggeredHook() error {
+	if u.st.BestAPIVersion() < 8 {
+		return errors.NotSupportedf("triggered hooks on this juju controller")
+	}
+	var result params.ErrorResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("ClearTriggeredHook", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// AgentState returns the state last saved for the unit's agent, as the
+// contents of its state files keyed by path.
+func (u *Unit) AgentState() (map[string]string, error) {
+	if u.st.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("agent state on this juju controller")
+	}
+	var results params.UnitAgentStateResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("AgentState", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.Files == nil {
+		return map[string]string{}, nil
+	}
+	return result.Files, nil
+}
+
+// SetAgentState replaces the state saved for the unit's agent with the
+// given contents of its state files, keyed by path.
+func (u *Unit) SetAgentState(files map[string]string) error {
+	if u.st.BestAPIVersion() < 9 {
+		return errors.NotSupportedf("agent state on this juju controller")
+	}
+	var result params.ErrorResults
+	args := params.UnitAgentStates{
+		States: []params.UnitAgentState{
+			{Tag: u.tag.String(), Files: files},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetAgentState", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// RecordHookExecution adds the given execution to the unit's hook
+// history.
+func (u *Unit) RecordHookExecution(execution params.HookExecution) error {
+	if u.st.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("recording hook executions on this juju controller")
+	}
+	var result params.ErrorResults
+	args := params.UnitHookExecutions{
+		Executions: []params.UnitHookExecution{
+			{Tag: u.tag.String(), Execution: execution},
+		},
+	}
+	err := u.st.facade.FacadeCall("RecordHookExecutions", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // ClearResolved removes any resolved setting on the unit.
 func (u *Unit) ClearResolved() error {
 	var result params.ErrorResults
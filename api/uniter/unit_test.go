@@ -16,6 +16,7 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
+	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -375,6 +376,84 @@ func (s *unitSuite) TestGetSetCharmURL(c *gc.C) {
 	c.Assert(curl.String(), gc.Equals, s.wordpressCharm.String())
 }
 
+func (s *unitSuite) TestSetWorkloadVersion(c *gc.C) {
+	err := s.apiUnit.SetWorkloadVersion("4.5.1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.wordpressUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpressUnit.WorkloadVersion(), gc.Equals, "4.5.1")
+}
+
+func (s *unitSuite) TestAgentState(c *gc.C) {
+	files, err := s.apiUnit.AgentState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+
+	saved := map[string]string{
+		"uniter":              "op: continue\n",
+		"relations/0/mysql-0": "change-version: 1\n",
+	}
+	err = s.apiUnit.SetAgentState(saved)
+	c.Assert(err, jc.ErrorIsNil)
+
+	files, err = s.wordpressUnit.AgentState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, jc.DeepEquals, saved)
+	files, err = s.apiUnit.AgentState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, jc.DeepEquals, saved)
+}
+
+func (s *unitSuite) TestAgentStateNotSupported(c *gc.C) {
+	unit := oldControllerUnit(c)
+	_, err := unit.AgentState()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = unit.SetAgentState(map[string]string{"uniter": "op: continue\n"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *unitSuite) TestRecordHookExecution(c *gc.C) {
+	started := time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC)
+	err := s.apiUnit.RecordHookExecution(params.HookExecution{
+		Kind:       "hook",
+		Name:       "install",
+		RelationId: -1,
+		Started:    started,
+		Finished:   started.Add(time.Second),
+		Result:     "completed",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.wordpressUnit.HookHistory(state.HookHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, jc.DeepEquals, []state.HookExecution{{
+		Kind:     "hook",
+		Name:     "install",
+		Started:  started,
+		Finished: started.Add(time.Second),
+		Result:   "completed",
+	}})
+}
+
+// oldControllerUnit returns a unit talking to a controller that only
+// knows version 3 of the facade, which must not be called.
+func oldControllerUnit(c *gc.C) *uniter.Unit {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call to %s.%s", objType, request)
+		return nil
+	})
+	st := uniter.NewState(apiCaller, names.NewUnitTag("wordpress/0"))
+	c.Assert(st.BestAPIVersion(), gc.Equals, 3)
+	return uniter.CreateUnit(st, names.NewUnitTag("wordpress/0"))
+}
+
+func (s *unitSuite) TestRecordHookExecutionNotSupported(c *gc.C) {
+	unit := oldControllerUnit(c)
+	err := unit.RecordHookExecution(params.HookExecution{Kind: "hook", Name: "install"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *unitSuite) TestConfigSettings(c *gc.C) {
 	// Make sure ConfigSettings returns an error when
 	// no charm URL is set, as its state counterpart does.
@@ -404,6 +483,110 @@ func (s *unitSuite) TestConfigSettings(c *gc.C) {
 	})
 }
 
+func (s *unitSuite) TestGoalState(c *gc.C) {
+	goalState, err := s.apiUnit.GoalState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(goalState, jc.DeepEquals, &params.GoalState{
+		Units: params.UnitsGoalState{
+			"wordpress/0": {Status: params.GoalStateWaiting},
+		},
+		Relations: map[string]params.UnitsGoalState{},
+	})
+
+	err = s.wordpressUnit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	goalState, err = s.apiUnit.GoalState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(goalState.Units, jc.DeepEquals, params.UnitsGoalState{
+		"wordpress/0": {Status: params.GoalStateActive},
+	})
+}
+
+func (s *unitSuite) TestSetWorkloadVersionNotSupported(c *gc.C) {
+	err := oldControllerUnit(c).SetWorkloadVersion("4.5.1")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *unitSuite) TestTriggeredHook(c *gc.C) {
+	hook, err := s.apiUnit.TriggeredHook()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hook, gc.IsNil)
+
+	err = s.wordpressUnit.TriggerHook(state.TriggeredHook{Name: "config-changed", RelationId: -1})
+	c.Assert(err, jc.ErrorIsNil)
+	hook, err = s.apiUnit.TriggeredHook()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hook, jc.DeepEquals, &params.TriggeredHook{Name: "config-changed", RelationId: -1})
+
+	err = s.apiUnit.ClearTriggeredHook()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.wordpressUnit.TriggeredHook()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *unitSuite) TestTriggeredHookNotSupported(c *gc.C) {
+	unit := oldControllerUnit(c)
+	_, err := unit.TriggeredHook()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = unit.ClearTriggeredHook()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *unitSuite) TestGoalStateNotSupported(c *gc.C) {
+	_, err := oldControllerUnit(c).GoalState()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *unitSuite) TestSecretsNotSupported(c *gc.C) {
+	unit := oldControllerUnit(c)
+	_, err := unit.CreateSecret("db password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = unit.SecretsToRotate()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, _, err = unit.SecretValue("666", 1)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *unitSuite) TestSecrets(c *gc.C) {
+	id, err := s.apiUnit.CreateSecret("db password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	data, revision, err := s.apiUnit.SecretValue(id, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "sekrit"})
+	c.Assert(revision, gc.Equals, 1)
+
+	err = s.apiUnit.UpdateSecret(id, map[string]string{"password": "geheim"})
+	c.Assert(err, jc.ErrorIsNil)
+	data, revision, err = s.apiUnit.SecretValue(id, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "geheim"})
+	c.Assert(revision, gc.Equals, 2)
+	data, revision, err = s.apiUnit.SecretValue(id, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "sekrit"})
+	c.Assert(revision, gc.Equals, 1)
+	_, _, err = s.apiUnit.SecretValue(id, 3)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+
+	err = s.apiUnit.GrantSecret(id, names.NewUnitTag("mysql/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	secret, err := s.State.Secret(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Grants(), jc.DeepEquals, []names.Tag{names.NewUnitTag("mysql/0")})
+
+	ids, err := s.apiUnit.SecretsToRotate()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, gc.HasLen, 0)
+	err = s.apiUnit.SecretRotated(id)
+	c.Assert(err, gc.ErrorMatches, `secret ".*" is not rotated`)
+
+	_, _, err = s.apiUnit.SecretValue("666", 0)
+	c.Assert(err, gc.ErrorMatches, `secret "666" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
 func (s *unitSuite) TestWatchConfigSettings(c *gc.C) {
 	// Make sure WatchConfigSettings returns an error when
 	// no charm URL is set, as its state counterpart does.
@@ -63,18 +63,25 @@ func newStateForVersion(
 	return state
 }
 
-func newStateForVersionFn(version int) func(base.APICaller, names.UnitTag) *State {
-	return func(caller base.APICaller, authTag names.UnitTag) *State {
-		return newStateForVersion(caller, authTag, version)
-	}
+// minimumVersion is the oldest version of the Uniter facade that the
+// client can use.
+const minimumVersion = 3
+
+// newStateBestVersion creates a new client-side Uniter facade, using
+// the newest version supported by both the client and the API server.
+// Methods added in later versions report that they are not supported
+// when talking to an older server.
+func newStateBestVersion(caller base.APICaller, authTag names.UnitTag) *State {
+	version := caller.BestFacadeVersion(uniterFacade)
+	if version < minimumVersion {
+		version = minimumVersion
+	}
+	return newStateForVersion(caller, authTag, version)
 }
 
-// newStateV3 creates a new client-side Uniter facade, version 3.
-var newStateV3 = newStateForVersionFn(3)
-
 // NewState creates a new client-side Uniter facade.
 // Defined like this to allow patching during tests.
-var NewState = newStateV3
+var NewState = newStateBestVersion
 
 // BestAPIVersion returns the API version that we were able to
 // determine is supported by both the client and the API Server.
@@ -19,6 +19,9 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Agent", 2, NewAgentAPIV2)
+
+	// Version 3 adds SecretsKey.
+	common.RegisterStandardFacade("Agent", 3, NewAgentAPIV2)
 }
 
 // AgentAPIV2 implements the version 2 of the API provided to an agent.
@@ -100,6 +103,16 @@ func (api *AgentAPIV2) StateServingInfo() (result state.StateServingInfo, err er
 	return api.st.StateServingInfo()
 }
 
+// SecretsKey returns the key with which the controller encrypts
+// secrets, for controller machines to hold in their agent
+// configuration. The key is not held in the database.
+func (api *AgentAPIV2) SecretsKey() (params.BytesResult, error) {
+	if !api.auth.AuthModelManager() {
+		return params.BytesResult{}, common.ErrPerm
+	}
+	return params.BytesResult{Result: api.st.SecretsKey()}, nil
+}
+
 // MongoIsMaster is called by the IsMaster API call
 // instead of mongo.IsMaster. It exists so it can
 // be overridden by tests.
@@ -28,6 +28,12 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 1, NewClient)
+
+	// Version 2 adds UnitHookHistory.
+	common.RegisterStandardFacade("Client", 2, NewClient)
+
+	// Version 3 adds TriggerHook.
+	common.RegisterStandardFacade("Client", 3, NewClient)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.client")
@@ -98,6 +104,27 @@ func (c *Client) Resolved(p params.Resolved) error {
 	return unit.Resolve(p.Retry)
 }
 
+// TriggerHook asks a unit to run the given hook once, outside its
+// normal workflow, so that it can be caught by a debug-code session.
+// An empty hook name clears any hook previously triggered.
+func (c *Client) TriggerHook(p params.TriggerHook) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	unit, err := c.api.stateAccessor.Unit(p.UnitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if p.Hook == "" {
+		return unit.ClearTriggeredHook()
+	}
+	return unit.TriggerHook(state.TriggeredHook{
+		Name:       p.Hook,
+		RelationId: p.RelationId,
+		RemoteUnit: p.RemoteUnit,
+	})
+}
+
 // PublicAddress implements the server side of Client.PublicAddress.
 func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAddressResults, err error) {
 	switch {
@@ -349,6 +376,14 @@ func (c *Client) ModelInfo() (params.ModelInfo, error) {
 		Name:           conf.Name(),
 		UUID:           env.UUID(),
 		ControllerUUID: env.ControllerUUID(),
+		EgressEnforced: true,
+	}
+	if conf.EgressPolicy().DefaultDeny {
+		environ, err := environs.New(conf)
+		if err != nil {
+			return params.ModelInfo{}, errors.Trace(err)
+		}
+		info.EgressEnforced = environs.EgressPolicyEnforced(environ)
 	}
 	return info, nil
 }
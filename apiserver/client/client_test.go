@@ -686,6 +686,17 @@ func (s *clientSuite) TestClientModelInfo(c *gc.C) {
 	c.Assert(info.Name, gc.Equals, conf.Name())
 	c.Assert(info.UUID, gc.Equals, env.UUID())
 	c.Assert(info.ControllerUUID, gc.Equals, env.ControllerUUID())
+	c.Assert(info.EgressEnforced, jc.IsTrue)
+}
+
+func (s *clientSuite) TestClientModelInfoEgressDeny(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"egress-policy": "deny",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := s.APIState.Client().ModelInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.EgressEnforced, jc.IsTrue)
 }
 
 func assertLife(c *gc.C, entity state.Living, life state.Life) {
@@ -801,6 +812,41 @@ func (s *clientSuite) TestBlockChangeUnitResolved(c *gc.C) {
 	s.assertResolvedBlocked(c, u, "TestBlockChangeUnitResolved")
 }
 
+func (s *clientSuite) TestClientTriggerHook(c *gc.C) {
+	s.setUpScenario(c)
+	err := s.APIState.Client().TriggerHook("wordpress/0", "logging-dir-relation-joined", 1, "logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+	u, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	hook, ok := u.TriggeredHook()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(hook, jc.DeepEquals, state.TriggeredHook{
+		Name:       "logging-dir-relation-joined",
+		RelationId: 1,
+		RemoteUnit: "logging/0",
+	})
+
+	err = s.APIState.Client().ClearTriggeredHook("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = u.TriggeredHook()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *clientSuite) TestClientTriggerHookInvalid(c *gc.C) {
+	s.setUpScenario(c)
+	err := s.APIState.Client().TriggerHook("wordpress/0", "install", -1, "logging/0")
+	c.Assert(err, gc.ErrorMatches, `cannot trigger hook for unit "wordpress/0": remote unit without relation not valid`)
+}
+
+func (s *clientSuite) TestBlockChangeTriggerHook(c *gc.C) {
+	s.setUpScenario(c)
+	s.BlockAllChanges(c, "TestBlockChangeTriggerHook")
+	err := s.APIState.Client().TriggerHook("wordpress/0", "install", -1, "")
+	s.AssertBlocked(c, err, "TestBlockChangeTriggerHook")
+}
+
 type clientRepoSuite struct {
 	baseSuite
 	testing.CharmStoreSuite
@@ -0,0 +1,112 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// UnitHookHistory returns the hooks, actions and commands recently run
+// by a unit's agent that match the given filter, most recent first.
+// The end of their standard error output, which may hold anything the
+// charm logged, is only returned to users who can change the model.
+func (c *Client) UnitHookHistory(args params.HookHistoryArgs) (params.HookHistoryResult, error) {
+	if args.Size < 0 {
+		return params.HookHistoryResult{}, errors.Errorf("invalid history size: %d", args.Size)
+	}
+	unit, err := c.api.stateAccessor.Unit(args.Unit)
+	if err != nil {
+		return params.HookHistoryResult{}, errors.Trace(err)
+	}
+	filter := state.HookHistoryFilter{
+		Kind:   args.Kind,
+		Name:   args.Name,
+		Result: args.Result,
+		Limit:  args.Size,
+	}
+	if args.Since != nil {
+		filter.Since = *args.Since
+	}
+	executions, err := unit.HookHistory(filter)
+	if err != nil {
+		return params.HookHistoryResult{}, errors.Trace(err)
+	}
+	showOutput, err := c.canSeeHookOutput()
+	if err != nil {
+		return params.HookHistoryResult{}, errors.Trace(err)
+	}
+	result := params.HookHistoryResult{
+		Executions: make([]params.HookExecution, len(executions)),
+	}
+	relationIds := make(map[string]int)
+	for i, execution := range executions {
+		relationId, err := c.relationId(execution.Relation, relationIds)
+		if err != nil {
+			return params.HookHistoryResult{}, errors.Trace(err)
+		}
+		result.Executions[i] = params.HookExecution{
+			Kind:       execution.Kind,
+			Name:       execution.Name,
+			RelationId: relationId,
+			Relation:   execution.Relation,
+			RemoteUnit: execution.RemoteUnit,
+			Started:    execution.Started,
+			Finished:   execution.Finished,
+			Result:     execution.Result,
+			ExitCode:   execution.ExitCode,
+		}
+		if showOutput {
+			result.Executions[i].StderrTail = execution.StderrTail
+		}
+	}
+	return result, nil
+}
+
+// canSeeHookOutput reports whether the authenticated user may see the
+// output of hooks: controller administrators and model users without
+// read-only access may.
+func (c *Client) canSeeHookOutput() (bool, error) {
+	// Since we know this is a user tag (because AuthClient is true),
+	// we just do the type assertion to the UserTag.
+	apiUser, _ := c.api.auth.GetAuthTag().(names.UserTag)
+	isAdmin, err := c.api.stateAccessor.IsControllerAdministrator(apiUser)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if isAdmin {
+		return true, nil
+	}
+	readOnly, err := c.api.stateAccessor.ModelUserReadOnly(apiUser)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return !readOnly, nil
+}
+
+// relationId returns the id of the relation with the given key, or
+// -1 if the key is empty or the relation no longer exists. Ids that
+// have already been looked up are taken from known.
+func (c *Client) relationId(key string, known map[string]int) (int, error) {
+	if key == "" {
+		return -1, nil
+	}
+	if id, ok := known[key]; ok {
+		return id, nil
+	}
+	id := -1
+	relation, err := c.api.stateAccessor.KeyRelation(key)
+	if err == nil {
+		id = relation.Id()
+	} else if !errors.IsNotFound(err) {
+		return -1, errors.Trace(err)
+	}
+	known[key] = id
+	return id, nil
+}
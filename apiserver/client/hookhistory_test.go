@@ -0,0 +1,172 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&hookHistorySuite{})
+
+type hookHistorySuite struct {
+	testing.BaseSuite
+	st   *mockHookHistoryState
+	unit *mockHookHistoryUnit
+	api  *client.Client
+}
+
+func (s *hookHistorySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.unit = &mockHookHistoryUnit{}
+	s.st = &mockHookHistoryState{unit: s.unit}
+	client.PatchState(s, s.st)
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("user")}
+	var err error
+	s.api, err = client.NewClient(nil, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *hookHistorySuite) TestUnitHookHistory(c *gc.C) {
+	started := time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC)
+	since := started.Add(-time.Hour)
+	s.unit.history = []state.HookExecution{{
+		Kind:       "hook",
+		Name:       "db-relation-changed",
+		Relation:   "wordpress:db mysql:server",
+		RemoteUnit: "mysql/0",
+		Started:    started,
+		Finished:   started.Add(time.Second),
+		Result:     "failed",
+		ExitCode:   1,
+		StderrTail: "boom",
+	}}
+
+	result, err := s.api.UnitHookHistory(params.HookHistoryArgs{
+		Unit:   "unit/0",
+		Kind:   "hook",
+		Name:   "db-relation-changed",
+		Result: "failed",
+		Since:  &since,
+		Size:   10,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.filter, jc.DeepEquals, state.HookHistoryFilter{
+		Kind:   "hook",
+		Name:   "db-relation-changed",
+		Result: "failed",
+		Since:  since,
+		Limit:  10,
+	})
+	c.Assert(result, jc.DeepEquals, params.HookHistoryResult{
+		Executions: []params.HookExecution{{
+			Kind:       "hook",
+			Name:       "db-relation-changed",
+			RelationId: -1,
+			Relation:   "wordpress:db mysql:server",
+			RemoteUnit: "mysql/0",
+			Started:    started,
+			Finished:   started.Add(time.Second),
+			Result:     "failed",
+			ExitCode:   1,
+			StderrTail: "boom",
+		}},
+	})
+}
+
+func (s *hookHistorySuite) assertStderrTail(c *gc.C, expect string) {
+	s.unit.history = []state.HookExecution{{
+		Kind:       "hook",
+		Name:       "install",
+		Result:     "failed",
+		ExitCode:   1,
+		StderrTail: "boom",
+	}}
+	result, err := s.api.UnitHookHistory(params.HookHistoryArgs{Unit: "unit/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Executions, gc.HasLen, 1)
+	c.Assert(result.Executions[0].StderrTail, gc.Equals, expect)
+}
+
+func (s *hookHistorySuite) TestUnitHookHistoryReadOnlyUser(c *gc.C) {
+	s.st.readOnly = true
+	s.assertStderrTail(c, "")
+}
+
+func (s *hookHistorySuite) TestUnitHookHistoryNotModelUser(c *gc.C) {
+	s.st.notModelUser = true
+	s.assertStderrTail(c, "")
+}
+
+func (s *hookHistorySuite) TestUnitHookHistoryControllerAdministrator(c *gc.C) {
+	s.st.notModelUser = true
+	s.st.controllerAdmin = true
+	s.assertStderrTail(c, "boom")
+}
+
+func (s *hookHistorySuite) TestUnitHookHistoryInvalidSize(c *gc.C) {
+	_, err := s.api.UnitHookHistory(params.HookHistoryArgs{Unit: "unit/0", Size: -1})
+	c.Assert(err, gc.ErrorMatches, "invalid history size: -1")
+}
+
+func (s *hookHistorySuite) TestUnitHookHistoryUnitNotFound(c *gc.C) {
+	_, err := s.api.UnitHookHistory(params.HookHistoryArgs{Unit: "unit/1"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+type mockHookHistoryState struct {
+	client.StateInterface
+	unit            *mockHookHistoryUnit
+	controllerAdmin bool
+	notModelUser    bool
+	readOnly        bool
+}
+
+func (m *mockHookHistoryState) IsControllerAdministrator(user names.UserTag) (bool, error) {
+	return m.controllerAdmin, nil
+}
+
+func (m *mockHookHistoryState) ModelUserReadOnly(user names.UserTag) (bool, error) {
+	if m.notModelUser {
+		return false, errors.NotFoundf("model user %q", user.Canonical())
+	}
+	return m.readOnly, nil
+}
+
+func (m *mockHookHistoryState) ModelUUID() string {
+	return "uuid"
+}
+
+func (m *mockHookHistoryState) KeyRelation(key string) (*state.Relation, error) {
+	// The relations of the recorded executions have all been removed.
+	return nil, errors.NotFoundf("relation %q", key)
+}
+
+func (m *mockHookHistoryState) Unit(name string) (client.Unit, error) {
+	if name != "unit/0" {
+		return nil, errors.NotFoundf("%v", name)
+	}
+	return m.unit, nil
+}
+
+type mockHookHistoryUnit struct {
+	client.Unit
+	history []state.HookExecution
+	filter  state.HookHistoryFilter
+}
+
+func (m *mockHookHistoryUnit) HookHistory(filter state.HookHistoryFilter) ([]state.HookExecution, error) {
+	m.filter = filter
+	return m.history, nil
+}
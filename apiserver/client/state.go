@@ -8,6 +8,7 @@ import (
 	"gopkg.in/juju/charm.v6-unstable"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -24,7 +25,10 @@ type Unit interface {
 	PublicAddress() (network.Address, error)
 	PrivateAddress() (network.Address, error)
 	Resolve(retryHooks bool) error
+	TriggerHook(state.TriggeredHook) error
+	ClearTriggeredHook() error
 	AgentHistory() state.StatusHistoryGetter
+	HookHistory(state.HookHistoryFilter) ([]state.HookExecution, error)
 }
 
 // stateInterface contains the state.State methods used in this package,
@@ -36,7 +40,9 @@ type stateInterface interface {
 	Machine(string) (*state.Machine, error)
 	AllMachines() ([]*state.Machine, error)
 	AllServices() ([]*state.Service, error)
+	AllRemoteServices() ([]*state.RemoteService, error)
 	AllRelations() ([]*state.Relation, error)
+	KeyRelation(string) (*state.Relation, error)
 	AllNetworks() ([]*state.Network, error)
 	AddOneMachine(state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideMachine(state.MachineTemplate, string, instance.ContainerType) (*state.Machine, error)
@@ -46,6 +52,7 @@ type stateInterface interface {
 	UpdateModelConfig(map[string]interface{}, []string, state.ValidateConfigFunc) error
 	SetModelConstraints(constraints.Value) error
 	ModelUUID() string
+	LeadershipChecker() leadership.Checker
 	ModelTag() names.ModelTag
 	Model() (*state.Model, error)
 	ForModel(tag names.ModelTag) (*state.State, error)
@@ -62,6 +69,8 @@ type stateInterface interface {
 	Watch() *state.Multiwatcher
 	AbortCurrentUpgrade() error
 	APIHostPorts() ([][]network.HostPort, error)
+	IsControllerAdministrator(names.UserTag) (bool, error)
+	ModelUserReadOnly(names.UserTag) (bool, error)
 }
 
 type stateShim struct {
@@ -75,3 +84,13 @@ func (s *stateShim) Unit(name string) (Unit, error) {
 	}
 	return u, nil
 }
+
+// ModelUserReadOnly reports whether the given user has only read
+// access to the model.
+func (s *stateShim) ModelUserReadOnly(user names.UserTag) (bool, error) {
+	modelUser, err := s.State.ModelUser(user)
+	if err != nil {
+		return false, err
+	}
+	return modelUser.ReadOnly(), nil
+}
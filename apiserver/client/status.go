@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -91,10 +92,14 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		return params.FullStatus{}, errors.Annotate(err, "could not get environ config")
 	}
 	var noStatus params.FullStatus
-	var context statusContext
+	context := statusContext{
+		leadershipChecker: c.api.stateAccessor.LeadershipChecker(),
+	}
 	if context.services, context.units, context.latestCharms, err =
 		fetchAllServicesAndUnits(c.api.stateAccessor, len(args.Patterns) <= 0); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch services and units")
+	} else if context.remoteServices, err = fetchRemoteServices(c.api.stateAccessor); err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch remote services")
 	} else if context.machines, err = fetchMachines(c.api.stateAccessor, nil); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch machines")
 	} else if context.relations, err = fetchRelations(c.api.stateAccessor); err != nil {
@@ -203,6 +208,7 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		AvailableVersion: newToolsVersion,
 		Machines:         processMachines(context.machines),
 		Services:         context.processServices(),
+		RemoteServices:   context.processRemoteServices(),
 		Networks:         context.processNetworks(),
 		Relations:        context.processRelations(),
 	}, nil
@@ -254,11 +260,15 @@ type statusContext struct {
 	// this machine.
 	machines map[string][]*state.Machine
 	// services: service name -> service
-	services     map[string]*state.Service
-	relations    map[string][]*state.Relation
-	units        map[string]map[string]*state.Unit
-	networks     map[string]*state.Network
-	latestCharms map[charm.URL]string
+	services map[string]*state.Service
+	// remoteServices: remote service name -> remote service
+	remoteServices map[string]*state.RemoteService
+	relations      map[string][]*state.Relation
+	units          map[string]map[string]*state.Unit
+	networks       map[string]*state.Network
+	latestCharms   map[charm.URL]string
+	// leadershipChecker is used to find each service's leader.
+	leadershipChecker leadership.Checker
 }
 
 // fetchMachines returns a map from top level machine id to machines, where machines[0] is the host
@@ -340,6 +350,20 @@ func fetchAllServicesAndUnits(
 	return svcMap, unitMap, latestCharms, nil
 }
 
+// fetchRemoteServices returns a map from remote service name to remote
+// service.
+func fetchRemoteServices(st stateInterface) (map[string]*state.RemoteService, error) {
+	services, err := st.AllRemoteServices()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*state.RemoteService)
+	for _, s := range services {
+		out[s.Name()] = s
+	}
+	return out, nil
+}
+
 // fetchUnitMachineIds returns a set of IDs for machines that
 // the specified units reside on, and those machines' ancestors.
 func fetchUnitMachineIds(units map[string]map[string]*state.Unit) (set.Strings, error) {
@@ -459,6 +483,7 @@ func makeMachineStatus(machine *state.Machine) (status params.MachineStatus) {
 			logger.Debugf("error fetching public address: %q", err)
 		}
 		status.DNSName = addr.Value
+		status.IPAddresses = machineIPAddresses(machine)
 	} else {
 		if errors.IsNotProvisioned(err) {
 			status.InstanceId = "pending"
@@ -478,6 +503,23 @@ func makeMachineStatus(machine *state.Machine) (status params.MachineStatus) {
 	return
 }
 
+// machineIPAddresses returns the IPv4 and IPv6 addresses of the
+// machine that are reachable from other machines.
+func machineIPAddresses(machine *state.Machine) []string {
+	var addresses []string
+	for _, addr := range machine.Addresses() {
+		switch addr.Scope {
+		case network.ScopeMachineLocal, network.ScopeLinkLocal:
+			continue
+		}
+		switch addr.Type {
+		case network.IPv4Address, network.IPv6Address:
+			addresses = append(addresses, addr.Value)
+		}
+	}
+	return addresses
+}
+
 func (context *statusContext) processRelations() []params.RelationStatus {
 	var out []params.RelationStatus
 	relations := context.getAllRelations()
@@ -573,13 +615,21 @@ func (context *statusContext) processService(service *state.Service) (status par
 	serviceCharmURL, _ := service.CharmURL()
 	status.Charm = serviceCharmURL.String()
 	status.Exposed = service.IsExposed()
+	status.LoadBalancer = service.LoadBalancerAddress()
 	status.Life = processLife(service)
 
 	latestCharm, ok := context.latestCharms[*serviceCharmURL.WithRevision(-1)]
 	if ok && latestCharm != serviceCharmURL.String() {
 		status.CanUpgradeTo = latestCharm
 	}
-	var err error
+	zonePolicy, err := service.ZonePolicy()
+	if err != nil {
+		status.Err = err
+		return
+	}
+	if zonePolicy != nil {
+		status.ZonePolicy = zonePolicy.String()
+	}
 	status.Relations, status.SubordinateTo, err = context.processServiceRelations(service)
 	if err != nil {
 		status.Err = err
@@ -610,6 +660,7 @@ func (context *statusContext) processService(service *state.Service) (status par
 			Disabled: append(cons.IncludeNetworks(), cons.ExcludeNetworks()...),
 		}
 	}
+	status.WorkloadVersion = context.serviceWorkloadVersion(service.Name())
 	if service.IsPrincipal() {
 		status.Units = context.processUnits(context.units[service.Name()], serviceCharmURL.String())
 		serviceStatus, err := service.Status()
@@ -627,6 +678,32 @@ func (context *statusContext) processService(service *state.Service) (status par
 	return status
 }
 
+// serviceWorkloadVersion returns the workload version reported by the
+// service's leader or, failing that, the version reported by most of
+// its units. Ties are broken by choosing the lowest version string, so
+// that the result is stable.
+func (context *statusContext) serviceWorkloadVersion(serviceName string) string {
+	counts := make(map[string]int)
+	for unitName, unit := range context.units[serviceName] {
+		version := unit.WorkloadVersion()
+		if version == "" {
+			continue
+		}
+		token := context.leadershipChecker.LeadershipCheck(serviceName, unitName)
+		if err := token.Check(nil); err == nil {
+			return version
+		}
+		counts[version]++
+	}
+	var result string
+	for version, count := range counts {
+		if count > counts[result] || (count == counts[result] && version < result) {
+			result = version
+		}
+	}
+	return result
+}
+
 func isColorStatus(code state.MeterStatusCode) bool {
 	return code == state.MeterGreen || code == state.MeterAmber || code == state.MeterRed
 }
@@ -672,11 +749,13 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 	}
 	if unit.IsPrincipal() {
 		result.Machine, _ = unit.AssignedMachineId()
+		result.AvailabilityZone, _ = unit.AvailabilityZone()
 	}
 	curl, _ := unit.CharmURL()
 	if serviceCharm != "" && curl != nil && curl.String() != serviceCharm {
 		result.Charm = curl.String()
 	}
+	result.WorkloadVersion = unit.WorkloadVersion()
 	processUnitAndAgentStatus(unit, &result)
 
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
@@ -725,6 +804,51 @@ func (context *statusContext) processServiceRelations(service *state.Service) (r
 	return related, subordSet.SortedValues(), nil
 }
 
+func (context *statusContext) processRemoteServices() map[string]params.RemoteServiceStatus {
+	servicesMap := make(map[string]params.RemoteServiceStatus)
+	for _, s := range context.remoteServices {
+		servicesMap[s.Name()] = context.processRemoteService(s)
+	}
+	return servicesMap
+}
+
+func (context *statusContext) processRemoteService(service *state.RemoteService) (status params.RemoteServiceStatus) {
+	status.URL = service.URL()
+	status.Life = processLife(service)
+	eps, err := service.Endpoints()
+	if err != nil {
+		status.Err = err
+		return
+	}
+	for _, ep := range eps {
+		status.Endpoints = append(status.Endpoints, params.RemoteEndpoint{
+			Name:      ep.Name,
+			Role:      ep.Role,
+			Interface: ep.Interface,
+		})
+	}
+	status.Relations = make(map[string][]string)
+	for _, relation := range context.relations[service.Name()] {
+		ep, err := relation.Endpoint(service.Name())
+		if err != nil {
+			status.Err = err
+			return
+		}
+		related, err := relation.RelatedEndpoints(service.Name())
+		if err != nil {
+			status.Err = err
+			return
+		}
+		for _, relatedEp := range related {
+			status.Relations[ep.Name] = append(status.Relations[ep.Name], relatedEp.ServiceName)
+		}
+	}
+	for relationName, serviceNames := range status.Relations {
+		status.Relations[relationName] = set.NewStrings(serviceNames...).SortedValues()
+	}
+	return
+}
+
 type lifer interface {
 	Life() state.Life
 }
@@ -4,12 +4,18 @@
 package client_test
 
 import (
+	"time"
+
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
 
 	"github.com/juju/juju/apiserver/client"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
@@ -46,6 +52,37 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.Series, gc.Equals, machine.Series())
 }
 
+func (s *statusSuite) TestFullStatusIPAddresses(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetProvisioned(instance.Id("i-dualstack"), "fakenonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(network.NewAddresses(
+		"10.0.0.1", "2001:db8::1", "example.com", "127.0.0.1", "::1", "fe80::1",
+	)...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resultMachine, ok := status.Machines[machine.Id()]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(resultMachine.IPAddresses, jc.SameContents, []string{"10.0.0.1", "2001:db8::1"})
+}
+
+func (s *statusSuite) TestFullStatusLoadBalancer(c *gc.C) {
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := svc.SetExposedLoadBalanced()
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.SetLoadBalancerAddress("203.0.113.10")
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resultService, ok := status.Services["wordpress"]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(resultService.Exposed, jc.IsTrue)
+	c.Check(resultService.LoadBalancer, gc.Equals, "203.0.113.10")
+}
+
 func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	machine := s.addMachine(c)
 	instanceId := "i-fakeinstance"
@@ -164,3 +201,72 @@ func (s *statusUnitTestSuite) TestMeterStatus(c *gc.C) {
 		}
 	}
 }
+
+func (s *statusUnitTestSuite) TestWorkloadVersion(c *gc.C) {
+	service := s.MakeService(c, nil)
+	var units []*state.Unit
+	for _, version := range []string{"1.0", "2.0", "2.0", ""} {
+		unit, err := service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetWorkloadVersion(version)
+		c.Assert(err, jc.ErrorIsNil)
+		units = append(units, unit)
+	}
+
+	client := s.APIState.Client()
+	status, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	serviceStatus := status.Services[service.Name()]
+	c.Check(serviceStatus.WorkloadVersion, gc.Equals, "2.0")
+	c.Check(serviceStatus.Units[units[0].Name()].WorkloadVersion, gc.Equals, "1.0")
+	c.Check(serviceStatus.Units[units[3].Name()].WorkloadVersion, gc.Equals, "")
+
+	// The leader's version takes precedence.
+	err = s.State.LeadershipClaimer().ClaimLeadership(service.Name(), units[0].Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Services[service.Name()].WorkloadVersion, gc.Equals, "1.0")
+}
+
+func (s *statusUnitTestSuite) TestRemoteServices(c *gc.C) {
+	s.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	_, err := s.State.AddRemoteService(state.AddRemoteServiceArgs{
+		Name:        "mysql",
+		URL:         "admin@local/prod.mysql",
+		SourceModel: names.NewModelTag(utils.MustNewUUID().String()),
+		OfferName:   "mysql",
+		Endpoints: []charm.Relation{{
+			Name:      "server",
+			Role:      charm.RoleProvider,
+			Interface: "mysql",
+			Scope:     charm.ScopeGlobal,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	status, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Services["wordpress"].Relations, jc.DeepEquals, map[string][]string{
+		"db": []string{"mysql"},
+	})
+	c.Assert(status.RemoteServices, jc.DeepEquals, map[string]params.RemoteServiceStatus{
+		"mysql": params.RemoteServiceStatus{
+			URL: "admin@local/prod.mysql",
+			Endpoints: []params.RemoteEndpoint{{
+				Name:      "server",
+				Role:      charm.RoleProvider,
+				Interface: "mysql",
+			}},
+			Relations: map[string][]string{"server": []string{"wordpress"}},
+		},
+	})
+}
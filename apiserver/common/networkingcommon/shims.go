@@ -8,8 +8,8 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
-	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
 )
 
@@ -146,11 +146,11 @@ type availZoneShim struct{}
 func (availZoneShim) Name() string    { return "not-set" }
 func (availZoneShim) Available() bool { return true }
 
-func (s *stateShim) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+func (s *stateShim) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	// TODO(dimitern): Fix this to get them from state when available!
 	return nil, nil
 }
 
-func (s *stateShim) SetAvailabilityZones(zones []providercommon.AvailabilityZone) error {
+func (s *stateShim) SetAvailabilityZones(zones []instance.AvailabilityZone) error {
 	return nil
 }
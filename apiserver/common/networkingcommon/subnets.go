@@ -458,7 +458,7 @@ func networkingEnviron(api NetworkBacking) (environs.NetworkingEnviron, error) {
 func AllZones(api NetworkBacking) (params.ZoneResults, error) {
 	var results params.ZoneResults
 
-	zonesAsString := func(zones []providercommon.AvailabilityZone) string {
+	zonesAsString := func(zones []instance.AvailabilityZone) string {
 		results := make([]string, len(zones))
 		for i, zone := range zones {
 			results[i] = zone.Name()
@@ -497,7 +497,7 @@ func AllZones(api NetworkBacking) (params.ZoneResults, error) {
 // updateZones attempts to retrieve all availability zones from the environment
 // provider (if supported) and then updates the persisted list of zones in
 // state, returning them as well on success.
-func updateZones(api NetworkBacking) ([]providercommon.AvailabilityZone, error) {
+func updateZones(api NetworkBacking) ([]instance.AvailabilityZone, error) {
 	zoned, err := zonedEnviron(api)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -13,7 +13,6 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
-	providercommon "github.com/juju/juju/provider/common"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -48,7 +47,7 @@ func (s *SubnetsSuite) TearDownTest(c *gc.C) {
 }
 
 // AssertAllZonesResult makes it easier to verify AllZones results.
-func (s *SubnetsSuite) AssertAllZonesResult(c *gc.C, got params.ZoneResults, expected []providercommon.AvailabilityZone) {
+func (s *SubnetsSuite) AssertAllZonesResult(c *gc.C, got params.ZoneResults, expected []instance.AvailabilityZone) {
 	results := make([]params.ZoneResult, len(expected))
 	for i, zone := range expected {
 		results[i].Name = zone.Name()
@@ -619,12 +618,12 @@ func (s *SubnetsSuite) CheckAddSubnetsFails(
 
 	if !withZones && withSpaces {
 		// Set provider zones to empty for this test.
-		originalZones := make([]providercommon.AvailabilityZone, len(apiservertesting.ProviderInstance.Zones))
+		originalZones := make([]instance.AvailabilityZone, len(apiservertesting.ProviderInstance.Zones))
 		copy(originalZones, apiservertesting.ProviderInstance.Zones)
-		apiservertesting.ProviderInstance.Zones = []providercommon.AvailabilityZone{}
+		apiservertesting.ProviderInstance.Zones = []instance.AvailabilityZone{}
 
 		defer func() {
-			apiservertesting.ProviderInstance.Zones = make([]providercommon.AvailabilityZone, len(originalZones))
+			apiservertesting.ProviderInstance.Zones = make([]instance.AvailabilityZone, len(originalZones))
 			copy(apiservertesting.ProviderInstance.Zones, originalZones)
 		}()
 
@@ -8,8 +8,8 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
-	providercommon "github.com/juju/juju/provider/common"
 )
 
 // BackingSubnet defines the methods supported by a Subnet entity
@@ -108,11 +108,11 @@ type NetworkBacking interface {
 
 	// AvailabilityZones returns all cached availability zones (i.e.
 	// not from the provider, but in state).
-	AvailabilityZones() ([]providercommon.AvailabilityZone, error)
+	AvailabilityZones() ([]instance.AvailabilityZone, error)
 
 	// SetAvailabilityZones replaces the cached list of availability
 	// zones with the given zones.
-	SetAvailabilityZones([]providercommon.AvailabilityZone) error
+	SetAvailabilityZones([]instance.AvailabilityZone) error
 
 	// AddSpace creates a space
 	AddSpace(Name string, ProviderId network.Id, Subnets []string, Public bool) error
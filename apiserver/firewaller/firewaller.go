@@ -6,6 +6,7 @@ package firewaller
 import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -17,6 +18,7 @@ import (
 func init() {
 	// Version 0 is no longer supported.
 	common.RegisterStandardFacade("Firewaller", 2, NewFirewallerAPI)
+	common.RegisterStandardFacade("Firewaller", 3, NewFirewallerAPIV3)
 }
 
 // FirewallerAPI provides access to the Firewaller API facade.
@@ -107,6 +109,206 @@ func NewFirewallerAPI(
 	}, nil
 }
 
+// FirewallerAPIV3 provides access to the Firewaller API facade,
+// version 3. It adds access to the CIDRs that services are exposed to,
+// to the CIDRs of the subnets that their endpoints are bound to, and
+// to the load balancers of services exposed through one.
+type FirewallerAPIV3 struct {
+	*FirewallerAPI
+}
+
+// NewFirewallerAPIV3 creates a new server-side FirewallerAPIV3 facade.
+func NewFirewallerAPIV3(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*FirewallerAPIV3, error) {
+	api, err := NewFirewallerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV3{api}, nil
+}
+
+// GetExposedCIDRs returns the CIDRs that each given service is
+// exposed to. An empty result means the service is exposed to all
+// addresses, if it is exposed at all.
+func (f *FirewallerAPIV3) GetExposedCIDRs(args params.Entities) (params.StringsResults, error) {
+	result := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessService()
+	if err != nil {
+		return params.StringsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := f.getService(canAccess, tag)
+		if err == nil {
+			result.Results[i].Result = service.ExposedCIDRs()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// GetBoundSubnetCIDRs returns the CIDRs of the subnets in the spaces
+// that the endpoints of each given service are bound to, sorted. The
+// ports opened by a service's units are not associated with any
+// particular endpoint, so the result is empty unless every endpoint of
+// the service is bound to a space; an empty result means the ports
+// need not be restricted to particular subnets.
+func (f *FirewallerAPIV3) GetBoundSubnetCIDRs(args params.Entities) (params.StringsResults, error) {
+	result := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessService()
+	if err != nil {
+		return params.StringsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := f.getService(canAccess, tag)
+		if err == nil {
+			result.Results[i].Result, err = f.boundSubnetCIDRs(service)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (f *FirewallerAPIV3) boundSubnetCIDRs(service *state.Service) ([]string, error) {
+	bindings, err := service.EndpointBindings()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spaceNames := set.NewStrings()
+	for _, spaceName := range bindings {
+		if spaceName == "" {
+			return nil, nil
+		}
+		spaceNames.Add(spaceName)
+	}
+	cidrs := set.NewStrings()
+	for _, spaceName := range spaceNames.Values() {
+		space, err := f.st.Space(spaceName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		subnets, err := space.Subnets()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, subnet := range subnets {
+			cidrs.Add(subnet.CIDR())
+		}
+	}
+	if cidrs.IsEmpty() {
+		return nil, nil
+	}
+	return cidrs.SortedValues(), nil
+}
+
+// WatchSubnets returns a NotifyWatcher that notifies of changes to the
+// subnets and spaces of the model, which may change the CIDRs returned
+// by GetBoundSubnetCIDRs.
+func (f *FirewallerAPIV3) WatchSubnets() (params.NotifyWatchResult, error) {
+	watch := f.st.WatchSubnets()
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: f.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{
+		Error: common.ServerError(watcher.EnsureErr(watch)),
+	}, nil
+}
+
+// GetLoadBalanced returns whether each given service is exposed
+// through a provider load balancer.
+func (f *FirewallerAPIV3) GetLoadBalanced(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessService()
+	if err != nil {
+		return params.BoolResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := f.getService(canAccess, tag)
+		if err == nil {
+			result.Results[i].Result = service.IsLoadBalanced()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// GetLoadBalancerAddresses returns the virtual address of the provider
+// load balancer in front of each given service, or an empty string if
+// none has been recorded.
+func (f *FirewallerAPIV3) GetLoadBalancerAddresses(args params.Entities) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessService()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := f.getService(canAccess, tag)
+		if err == nil {
+			result.Results[i].Result = service.LoadBalancerAddress()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetLoadBalancerAddresses records the virtual address of the provider
+// load balancer in front of each given service. An empty address
+// records that the service has no load balancer.
+func (f *FirewallerAPIV3) SetLoadBalancerAddresses(args params.EntitiesAddresses) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessService()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := f.getService(canAccess, tag)
+		if err == nil {
+			err = service.SetLoadBalancerAddress(entity.Address)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // WatchOpenedPorts returns a new StringsWatcher for each given
 // environment tag.
 func (f *FirewallerAPI) WatchOpenedPorts(args params.Entities) (params.StringsWatchResults, error) {
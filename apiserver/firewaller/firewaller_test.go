@@ -24,7 +24,7 @@ type firewallerSuite struct {
 	firewallerBaseSuite
 	*commontesting.ModelWatcherTest
 
-	firewaller *firewaller.FirewallerAPI
+	firewaller *firewaller.FirewallerAPIV3
 }
 
 var _ = gc.Suite(&firewallerSuite{})
@@ -33,7 +33,7 @@ func (s *firewallerSuite) SetUpTest(c *gc.C) {
 	s.firewallerBaseSuite.setUpTest(c)
 
 	// Create a firewaller API for the machine.
-	firewallerAPI, err := firewaller.NewFirewallerAPI(
+	firewallerAPI, err := firewaller.NewFirewallerAPIV3(
 		s.State,
 		s.resources,
 		s.authorizer,
@@ -51,6 +51,14 @@ func (s *firewallerSuite) TestFirewallerFailsWithNonEnvironManagerUser(c *gc.C)
 	s.testFirewallerFailsWithNonEnvironManagerUser(c, constructor)
 }
 
+func (s *firewallerSuite) TestFirewallerV3FailsWithNonEnvironManagerUser(c *gc.C) {
+	constructor := func(st *state.State, res *common.Resources, auth common.Authorizer) error {
+		_, err := firewaller.NewFirewallerAPIV3(st, res, auth)
+		return err
+	}
+	s.testFirewallerFailsWithNonEnvironManagerUser(c, constructor)
+}
+
 func (s *firewallerSuite) TestLife(c *gc.C) {
 	s.testLife(c, s.firewaller)
 }
@@ -75,6 +83,148 @@ func (s *firewallerSuite) TestGetExposed(c *gc.C) {
 	s.testGetExposed(c, s.firewaller)
 }
 
+func (s *firewallerSuite) TestGetExposedCIDRs(c *gc.C) {
+	err := s.service.SetExposed("10.0.0.0/8", "192.168.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.service.Tag().String()},
+	}})
+	result, err := s.firewaller.GetExposedCIDRs(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{"10.0.0.0/8", "192.168.1.0/24"}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`service "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *firewallerSuite) TestGetLoadBalanced(c *gc.C) {
+	err := s.service.SetExposedLoadBalanced()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.service.Tag().String()},
+	}})
+	result, err := s.firewaller.GetLoadBalanced(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`service "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *firewallerSuite) TestSetLoadBalancerAddresses(c *gc.C) {
+	args := params.EntitiesAddresses{Entities: []params.EntityAddress{
+		{Tag: s.service.Tag().String(), Address: "0.30.0.1"},
+		{Tag: s.machines[0].Tag().String(), Address: "0.30.0.2"},
+		{Tag: "service-bar", Address: "0.30.0.3"},
+	}}
+	result, err := s.firewaller.SetLoadBalancerAddresses(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`service "bar"`)},
+		},
+	})
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.LoadBalancerAddress(), gc.Equals, "0.30.0.1")
+
+	addresses, err := s.firewaller.GetLoadBalancerAddresses(addFakeEntities(params.Entities{
+		Entities: []params.Entity{{Tag: s.service.Tag().String()}},
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: "0.30.0.1"},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`service "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *firewallerSuite) TestGetBoundSubnetCIDRs(c *gc.C) {
+	for _, cidr := range []string{"10.0.0.0/24", "10.0.1.0/24", "192.0.2.0/24"} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := s.State.AddSpace("internal", "", []string{"10.0.0.0/24", "10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("public", "", []string{"192.0.2.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	bound := s.AddTestingServiceWithBindings(c, "bound", s.charm, map[string]string{
+		"url":             "public",
+		"db":              "internal",
+		"cache":           "internal",
+		"logging-dir":     "internal",
+		"monitoring-port": "internal",
+	})
+	partlyBound := s.AddTestingServiceWithBindings(c, "partly-bound", s.charm, map[string]string{
+		"url": "public",
+	})
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: bound.Tag().String()},
+		{Tag: partlyBound.Tag().String()},
+		{Tag: s.service.Tag().String()},
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: "service-bar"},
+	}}
+	result, err := s.firewaller.GetBoundSubnetCIDRs(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{"10.0.0.0/24", "10.0.1.0/24", "192.0.2.0/24"}},
+			{},
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`service "bar"`)},
+		},
+	})
+}
+
+func (s *firewallerSuite) TestWatchSubnets(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	result, err := s.firewaller.WatchSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event ("returned" in
+	// the Watch call)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func (s *firewallerSuite) TestOpenedPortsNotImplemented(c *gc.C) {
 	apiservertesting.AssertNotImplemented(c, s.firewaller, "OpenedPorts")
 }
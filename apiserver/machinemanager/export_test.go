@@ -3,7 +3,11 @@
 
 package machinemanager
 
-import "github.com/juju/juju/state"
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
 
 type StateInterface stateInterface
 
@@ -16,3 +20,9 @@ func PatchState(p Patcher, st StateInterface) {
 		return st
 	})
 }
+
+func PatchEnviron(p Patcher, env environs.Environ) {
+	p.PatchValue(&newEnviron, func(*config.Config) (environs.Environ, error) {
+		return env, nil
+	})
+}
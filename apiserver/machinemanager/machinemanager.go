@@ -10,6 +10,8 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -17,6 +19,15 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("MachineManager", 2, NewMachineManagerAPI)
+
+	// Version 3 adds machine profiles.
+	common.RegisterStandardFacade("MachineManager", 3, NewMachineManagerAPI)
+
+	// Version 4 adds cloud-init user data to AddMachines.
+	common.RegisterStandardFacade("MachineManager", 4, NewMachineManagerAPI)
+
+	// Version 5 adds root-disk sources to machine profiles.
+	common.RegisterStandardFacade("MachineManager", 5, NewMachineManagerAPI)
 }
 
 // MachineManagerAPI provides access to the MachineManager API facade.
@@ -30,6 +41,10 @@ var getState = func(st *state.State) stateInterface {
 	return stateShim{st}
 }
 
+// newEnviron is used to validate the root-disk sources of machine
+// profiles with the model's provider.
+var newEnviron = environs.New
+
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
 func NewMachineManagerAPI(
 	st *state.State,
@@ -94,6 +109,25 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 		p.Addrs = nil
 	}
 
+	userData := p.CloudInitUserData
+	var profile MachineProfile
+	if p.Profile != "" {
+		var err error
+		profile, err = mm.st.MachineProfile(p.Profile)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if p.Series == "" {
+			p.Series = profile.Series()
+		}
+		userData, err = cloudinit.MergeUserData(profile.CloudInitUserData(), userData)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else if _, err := cloudinit.ParseUserData(userData); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	if p.Series == "" {
 		conf, err := mm.st.ModelConfig()
 		if err != nil {
@@ -148,6 +182,12 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 		HardwareCharacteristics: p.HardwareCharacteristics,
 		Addresses:               params.NetworkAddresses(p.Addrs),
 		Placement:               placementDirective,
+		CloudInitUserData:       userData,
+	}
+	if profile != nil {
+		if err := profile.ApplyTo(&template); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	if p.ContainerType == "" {
 		return mm.st.AddOneMachine(template)
@@ -157,3 +197,71 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	}
 	return mm.st.AddMachineInsideNewMachine(template, template, p.ContainerType)
 }
+
+// AddMachineProfiles adds named machine profiles to the model.
+func (mm *MachineManagerAPI) AddMachineProfiles(args params.MachineProfiles) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Profiles)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, p := range args.Profiles {
+		err := mm.addOneMachineProfile(p)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) addOneMachineProfile(p params.MachineProfile) error {
+	if _, err := cloudinit.ParseUserData(p.CloudInitUserData); err != nil {
+		return errors.Annotatef(err, "cannot add machine profile %q", p.Name)
+	}
+	if p.RootDiskSource != "" {
+		if err := mm.validateRootDiskSource(p.RootDiskSource); err != nil {
+			return errors.Annotatef(err, "cannot add machine profile %q", p.Name)
+		}
+	}
+	return mm.st.AddMachineProfile(state.AddMachineProfileArgs{
+		Name:              p.Name,
+		Series:            p.Series,
+		Constraints:       p.Constraints,
+		CloudInitUserData: p.CloudInitUserData,
+		RootDiskSource:    p.RootDiskSource,
+	})
+}
+
+// validateRootDiskSource returns an error if the model's provider
+// cannot create root disks from the named source.
+func (mm *MachineManagerAPI) validateRootDiskSource(source string) error {
+	cfg, err := mm.st.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := newEnviron(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return environs.ValidateRootDiskSource(env, source)
+}
+
+// ListMachineProfiles returns all the machine profiles in the model.
+func (mm *MachineManagerAPI) ListMachineProfiles() (params.MachineProfilesResult, error) {
+	profiles, err := mm.st.AllMachineProfiles()
+	if err != nil {
+		return params.MachineProfilesResult{}, errors.Trace(err)
+	}
+	result := params.MachineProfilesResult{
+		Profiles: make([]params.MachineProfile, len(profiles)),
+	}
+	for i, profile := range profiles {
+		result.Profiles[i] = params.MachineProfile{
+			Name:              profile.Name(),
+			Series:            profile.Series(),
+			Constraints:       profile.Constraints(),
+			CloudInitUserData: profile.CloudInitUserData(),
+			RootDiskSource:    profile.RootDiskSource(),
+		}
+	}
+	return result, nil
+}
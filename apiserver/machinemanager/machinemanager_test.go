@@ -4,8 +4,7 @@
 package machinemanager_test
 
 import (
-	"errors"
-
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -14,6 +13,8 @@ import (
 	"github.com/juju/juju/apiserver/machinemanager"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -118,12 +119,180 @@ func (s *MachineManagerSuite) TestAddMachinesStateError(c *gc.C) {
 	c.Assert(s.st.calls, gc.Equals, 1)
 }
 
+func (s *MachineManagerSuite) TestAddMachinesWithProfile(c *gc.C) {
+	s.st.profiles = []state.AddMachineProfileArgs{{
+		Name:              "database",
+		Series:            "xenial",
+		Constraints:       constraints.MustParse("mem=4G"),
+		CloudInitUserData: "packages: [ca-certificates]",
+	}}
+	results, err := s.api.AddMachines(params.AddMachines{
+		MachineParams: []params.AddMachineParams{{
+			Jobs:    []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+			Profile: "database",
+		}, {
+			Jobs:    []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+			Profile: "web",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines, gc.HasLen, 2)
+	c.Assert(results.Machines[0].Error, gc.IsNil)
+	c.Assert(results.Machines[1].Error, gc.ErrorMatches, `machine profile "web" not found`)
+	c.Assert(s.st.machines, jc.DeepEquals, []state.MachineTemplate{{
+		Series:            "xenial",
+		Constraints:       constraints.MustParse("mem=4G"),
+		Jobs:              []state.MachineJob{state.JobHostUnits},
+		Volumes:           []state.MachineVolumeParams{},
+		CloudInitUserData: "packages:\n- ca-certificates\n",
+	}})
+}
+
+func (s *MachineManagerSuite) TestAddMachinesWithCloudInitUserData(c *gc.C) {
+	s.st.profiles = []state.AddMachineProfileArgs{{
+		Name:              "database",
+		CloudInitUserData: "packages: [ca-certificates]",
+	}}
+	results, err := s.api.AddMachines(params.AddMachines{
+		MachineParams: []params.AddMachineParams{{
+			Series:            "trusty",
+			Jobs:              []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+			Profile:           "database",
+			CloudInitUserData: "runcmd: [update-ca-certificates]",
+		}, {
+			Series:            "trusty",
+			Jobs:              []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+			CloudInitUserData: "packages: [curl]",
+		}, {
+			Series:            "trusty",
+			Jobs:              []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+			CloudInitUserData: "bootcmd: [reboot]",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines, gc.HasLen, 3)
+	c.Assert(results.Machines[0].Error, gc.IsNil)
+	c.Assert(results.Machines[1].Error, gc.IsNil)
+	c.Assert(results.Machines[2].Error, gc.ErrorMatches, `cloud-init user data key "bootcmd" not supported`)
+	c.Assert(s.st.machines, jc.DeepEquals, []state.MachineTemplate{{
+		Series:            "trusty",
+		Jobs:              []state.MachineJob{state.JobHostUnits},
+		Volumes:           []state.MachineVolumeParams{},
+		CloudInitUserData: "packages:\n- ca-certificates\nruncmd:\n- update-ca-certificates\n",
+	}, {
+		Series:            "trusty",
+		Jobs:              []state.MachineJob{state.JobHostUnits},
+		Volumes:           []state.MachineVolumeParams{},
+		CloudInitUserData: "packages: [curl]",
+	}})
+}
+
+func (s *MachineManagerSuite) TestAddMachineProfiles(c *gc.C) {
+	results, err := s.api.AddMachineProfiles(params.MachineProfiles{
+		Profiles: []params.MachineProfile{{
+			Name:              "database",
+			Constraints:       constraints.MustParse("spaces=db"),
+			CloudInitUserData: "runcmd: [true]",
+		}, {
+			Name:              "web",
+			CloudInitUserData: "bootcmd: [reboot]",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot add machine profile "web": cloud-init user data key "bootcmd" not supported`)
+	c.Assert(s.st.profiles, jc.DeepEquals, []state.AddMachineProfileArgs{{
+		Name:              "database",
+		Constraints:       constraints.MustParse("spaces=db"),
+		CloudInitUserData: "runcmd: [true]",
+	}})
+}
+
+func (s *MachineManagerSuite) TestAddMachineProfilesRootDiskSource(c *gc.C) {
+	machinemanager.PatchEnviron(s, &mockEnviron{})
+	results, err := s.api.AddMachineProfiles(params.MachineProfiles{
+		Profiles: []params.MachineProfile{{
+			Name:           "database",
+			RootDiskSource: "ssd",
+		}, {
+			Name:           "web",
+			RootDiskSource: "floppy",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot add machine profile "web": root-disk source "floppy" not valid`)
+	c.Assert(s.st.profiles, jc.DeepEquals, []state.AddMachineProfileArgs{{
+		Name:           "database",
+		RootDiskSource: "ssd",
+	}})
+}
+
+func (s *MachineManagerSuite) TestAddMachineProfilesRootDiskSourceNotSupported(c *gc.C) {
+	// The environ does not implement environs.RootDiskSourceValidator.
+	machinemanager.PatchEnviron(s, &struct{ environs.Environ }{})
+	results, err := s.api.AddMachineProfiles(params.MachineProfiles{
+		Profiles: []params.MachineProfile{{
+			Name:           "database",
+			RootDiskSource: "ssd",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `cannot add machine profile "database": root-disk source "ssd" not supported`)
+	c.Assert(s.st.profiles, gc.HasLen, 0)
+}
+
+func (s *MachineManagerSuite) TestListMachineProfiles(c *gc.C) {
+	s.st.profiles = []state.AddMachineProfileArgs{{
+		Name:           "database",
+		Series:         "xenial",
+		Constraints:    constraints.MustParse("mem=4G"),
+		RootDiskSource: "ssd",
+	}}
+	result, err := s.api.ListMachineProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachineProfilesResult{
+		Profiles: []params.MachineProfile{{
+			Name:           "database",
+			Series:         "xenial",
+			Constraints:    constraints.MustParse("mem=4G"),
+			RootDiskSource: "ssd",
+		}},
+	})
+}
+
 type mockState struct {
 	calls    int
 	machines []state.MachineTemplate
+	profiles []state.AddMachineProfileArgs
 	err      error
 }
 
+func (st *mockState) AddMachineProfile(args state.AddMachineProfileArgs) error {
+	st.profiles = append(st.profiles, args)
+	return nil
+}
+
+func (st *mockState) MachineProfile(name string) (machinemanager.MachineProfile, error) {
+	for _, args := range st.profiles {
+		if args.Name == name {
+			return &mockProfile{args}, nil
+		}
+	}
+	return nil, errors.NotFoundf("machine profile %q", name)
+}
+
+func (st *mockState) AllMachineProfiles() ([]machinemanager.MachineProfile, error) {
+	var profiles []machinemanager.MachineProfile
+	for _, args := range st.profiles {
+		profiles = append(profiles, &mockProfile{args})
+	}
+	return profiles, nil
+}
+
 func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machine, error) {
 	st.calls++
 	st.machines = append(st.machines, template)
@@ -136,7 +305,9 @@ func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, erro
 }
 
 func (st *mockState) ModelConfig() (*config.Config, error) {
-	panic("not implemented")
+	// The config is only used to open an environ, which is patched
+	// by the tests that need one.
+	return nil, nil
 }
 
 func (st *mockState) Model() (*state.Model, error) {
@@ -151,6 +322,56 @@ func (st *mockState) AddMachineInsideMachine(template state.MachineTemplate, par
 	panic("not implemented")
 }
 
+type mockProfile struct {
+	args state.AddMachineProfileArgs
+}
+
+func (p *mockProfile) Name() string {
+	return p.args.Name
+}
+
+func (p *mockProfile) Series() string {
+	return p.args.Series
+}
+
+func (p *mockProfile) Constraints() constraints.Value {
+	return p.args.Constraints
+}
+
+func (p *mockProfile) CloudInitUserData() string {
+	return p.args.CloudInitUserData
+}
+
+func (p *mockProfile) RootDiskSource() string {
+	return p.args.RootDiskSource
+}
+
+func (p *mockProfile) ApplyTo(template *state.MachineTemplate) error {
+	if template.Series == "" {
+		template.Series = p.args.Series
+	}
+	template.Constraints = p.args.Constraints
+	if template.CloudInitUserData == "" {
+		template.CloudInitUserData = p.args.CloudInitUserData
+	}
+	if template.RootDiskSource == "" {
+		template.RootDiskSource = p.args.RootDiskSource
+	}
+	return nil
+}
+
+// mockEnviron accepts root-disk sources other than "floppy".
+type mockEnviron struct {
+	environs.Environ
+}
+
+func (*mockEnviron) ValidateRootDiskSource(source string) error {
+	if source == "floppy" {
+		return errors.NotValidf("root-disk source %q", source)
+	}
+	return nil
+}
+
 type mockBlock struct {
 	state.Block
 }
@@ -4,6 +4,7 @@
 package machinemanager
 
 import (
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -16,6 +17,20 @@ type stateInterface interface {
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	AddMachineProfile(args state.AddMachineProfileArgs) error
+	MachineProfile(name string) (MachineProfile, error)
+	AllMachineProfiles() ([]MachineProfile, error)
+}
+
+// MachineProfile describes the methods of *state.MachineProfile used by
+// the MachineManager facade.
+type MachineProfile interface {
+	Name() string
+	Series() string
+	Constraints() constraints.Value
+	CloudInitUserData() string
+	RootDiskSource() string
+	ApplyTo(template *state.MachineTemplate) error
 }
 
 type stateShim struct {
@@ -45,3 +60,28 @@ func (s stateShim) AddMachineInsideNewMachine(template, parentTemplate state.Mac
 func (s stateShim) AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error) {
 	return s.State.AddMachineInsideMachine(template, parentId, containerType)
 }
+
+func (s stateShim) AddMachineProfile(args state.AddMachineProfileArgs) error {
+	_, err := s.State.AddMachineProfile(args)
+	return err
+}
+
+func (s stateShim) MachineProfile(name string) (MachineProfile, error) {
+	profile, err := s.State.MachineProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s stateShim) AllMachineProfiles() ([]MachineProfile, error) {
+	profiles, err := s.State.AllMachineProfiles()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]MachineProfile, len(profiles))
+	for i, profile := range profiles {
+		result[i] = profile
+	}
+	return result, nil
+}
@@ -270,6 +270,57 @@ type EntityCharmURL struct {
 	CharmURL string
 }
 
+// EntityWorkloadVersion holds the version of the workload software
+// running on the unit with the given tag.
+type EntityWorkloadVersion struct {
+	Tag             string `json:"tag"`
+	WorkloadVersion string `json:"workload-version"`
+}
+
+// EntityWorkloadVersions holds the parameters for making a
+// SetWorkloadVersion API call.
+type EntityWorkloadVersions struct {
+	Entities []EntityWorkloadVersion `json:"entities"`
+}
+
+// UnitAgentState holds the state saved by the agent of the unit with
+// the given tag, as the contents of its state files keyed by path.
+type UnitAgentState struct {
+	Tag   string            `json:"tag"`
+	Files map[string]string `json:"files"`
+}
+
+// UnitAgentStates holds the parameters for making a SetAgentState
+// API call.
+type UnitAgentStates struct {
+	States []UnitAgentState `json:"states"`
+}
+
+// UnitAgentStateResult holds the state saved by a unit's agent, or an
+// error.
+type UnitAgentStateResult struct {
+	Files map[string]string `json:"files,omitempty"`
+	Error *Error            `json:"error,omitempty"`
+}
+
+// UnitAgentStateResults holds the results of an AgentState API call.
+type UnitAgentStateResults struct {
+	Results []UnitAgentStateResult `json:"results"`
+}
+
+// UnitHookExecution holds a hook execution to record for the unit with
+// the given tag.
+type UnitHookExecution struct {
+	Tag       string        `json:"tag"`
+	Execution HookExecution `json:"execution"`
+}
+
+// UnitHookExecutions holds the parameters for making a
+// RecordHookExecutions API call.
+type UnitHookExecutions struct {
+	Executions []UnitHookExecution `json:"executions"`
+}
+
 // EntitiesCharmURL holds the parameters for making a SetCharmURL API
 // call.
 type EntitiesCharmURL struct {
@@ -567,6 +618,19 @@ type ProvisioningInfo struct {
 	SubnetsToZones   map[string][]string
 	ImageMetadata    []CloudImageMetadata
 	EndpointBindings map[string]string
+	// ZonePolicy holds the zone policy of the service whose unit
+	// the machine is being provisioned for, if it has one, and
+	// ZonePopulation the number of the service's units already in
+	// each zone.
+	ZonePolicy        string
+	ZonePolicyService string
+	ZonePopulation    map[string]int
+	// CloudInitUserData holds operator-supplied cloud-init user data
+	// to be merged with Juju's own when starting the instance.
+	CloudInitUserData string
+	// RootDiskSource names the source from which the instance's root
+	// disk should be created, if not the provider's default.
+	RootDiskSource string
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -641,3 +705,121 @@ type SingularClaim struct {
 type SingularClaims struct {
 	Claims []SingularClaim `json:"Claims"`
 }
+
+// The statuses of units in a GoalState.
+const (
+	// GoalStateWaiting is the status of a unit whose agent has not
+	// yet started.
+	GoalStateWaiting = "waiting"
+
+	// GoalStateJoining is the status of a related unit whose agent
+	// has started, but which has not yet joined the relation.
+	GoalStateJoining = "joining"
+
+	// GoalStateActive is the status of a unit whose agent has started,
+	// and which has joined the relation if it is a related unit.
+	GoalStateActive = "active"
+
+	// GoalStateDying is the status of a unit, or a related unit of a
+	// relation, that is going away.
+	GoalStateDying = "dying"
+)
+
+// GoalStateStatus holds the status of a unit in a GoalState.
+type GoalStateStatus struct {
+	Status string
+}
+
+// UnitsGoalState holds the status of each of a set of units, keyed
+// by unit name.
+type UnitsGoalState map[string]GoalStateStatus
+
+// GoalState describes the units expected for a unit's service, and
+// for the services related to it, including those that have not yet
+// joined.
+type GoalState struct {
+	// Units holds the units of the unit's service.
+	Units UnitsGoalState
+
+	// Relations holds the units of the related services, keyed by
+	// the name of the unit's endpoint they are related on.
+	Relations map[string]UnitsGoalState
+}
+
+// GoalStateResult holds a unit's goal state or an error.
+type GoalStateResult struct {
+	Result *GoalState
+	Error  *Error
+}
+
+// GoalStateResults holds the goal states of multiple units.
+type GoalStateResults struct {
+	Results []GoalStateResult
+}
+
+// CreateSecretArg holds the details of a secret to be created by a unit.
+type CreateSecretArg struct {
+	UnitTag        string
+	Description    string
+	Data           map[string]string
+	RotateInterval time.Duration
+}
+
+// CreateSecretArgs holds the details of multiple secrets to be created.
+type CreateSecretArgs struct {
+	Args []CreateSecretArg
+}
+
+// SecretArg identifies a secret accessed by a unit.
+type SecretArg struct {
+	UnitTag string
+	ID      string
+
+	// Revision, if non-zero, identifies the revision of the secret
+	// whose content is read by SecretValues; otherwise the current
+	// revision is read.
+	Revision int `json:",omitempty"`
+}
+
+// SecretArgs holds multiple secrets accessed by units.
+type SecretArgs struct {
+	Args []SecretArg
+}
+
+// SecretValueResult holds the content of a secret, or an error.
+type SecretValueResult struct {
+	Error    *Error
+	Revision int
+	Data     map[string]string
+}
+
+// SecretValueResults holds the content of multiple secrets.
+type SecretValueResults struct {
+	Results []SecretValueResult
+}
+
+// UpdateSecretArg holds new content for a secret owned by a unit's
+// service.
+type UpdateSecretArg struct {
+	UnitTag string
+	ID      string
+	Data    map[string]string
+}
+
+// UpdateSecretArgs holds new content for multiple secrets.
+type UpdateSecretArgs struct {
+	Args []UpdateSecretArg
+}
+
+// GrantSecretArg grants access to a secret owned by a unit's service
+// to another unit or to the units of a relation.
+type GrantSecretArg struct {
+	UnitTag    string
+	ID         string
+	GranteeTag string
+}
+
+// GrantSecretArgs holds multiple secret grants.
+type GrantSecretArgs struct {
+	Args []GrantSecretArg
+}
@@ -126,6 +126,11 @@ type NetworkConfig struct {
 	// "eth1", even for a VLAN eth1.42 virtual interface).
 	InterfaceName string `json:"InterfaceName"`
 
+	// ParentInterfaceName is the name of the host's network device
+	// that a container's interface is attached to, when the container
+	// is not connected to the host through a bridge.
+	ParentInterfaceName string `json:"ParentInterfaceName,omitempty"`
+
 	// Disabled is true when the interface needs to be disabled on the
 	// machine, e.g. not to configure it at all or stop it if running.
 	Disabled bool `json:"Disabled"`
@@ -250,6 +255,18 @@ type EntitiesPortRanges struct {
 	Entities []EntityPortRange `json:"Entities"`
 }
 
+// EntityAddress holds an entity's tag and the value of an address.
+type EntityAddress struct {
+	Tag     string `json:"Tag"`
+	Address string `json:"Address"`
+}
+
+// EntitiesAddresses holds the parameters for setting an address on
+// some entities.
+type EntitiesAddresses struct {
+	Entities []EntityAddress `json:"Entities"`
+}
+
 // Address represents the location of a machine, including metadata
 // about what kind of location the address describes. It's used in
 // the API requests/responses. See also network.Address, from/to
@@ -164,6 +164,15 @@ type AddMachineParams struct {
 	Nonce                   string                           `json:"Nonce"`
 	HardwareCharacteristics instance.HardwareCharacteristics `json:"HardwareCharacteristics"`
 	Addrs                   []Address                        `json:"Addrs"`
+
+	// If Profile is non-empty, it names the machine profile whose
+	// attributes the new machine will be given. Series and
+	// constraints specified above take precedence over the profile's.
+	Profile string `json:"Profile,omitempty"`
+
+	// CloudInitUserData holds operator-supplied cloud-init user data
+	// for the new machine, merged with that of any profile.
+	CloudInitUserData string `json:"CloudInitUserData,omitempty"`
 }
 
 // AddMachines holds the parameters for making the AddMachines call.
@@ -208,6 +217,10 @@ type ServiceDeploy struct {
 	Storage          map[string]storage.Constraints
 	EndpointBindings map[string]string
 	Resources        map[string]string
+
+	// MachineProfile, if non-empty, names the machine profile
+	// applied to the machines created for the service's units.
+	MachineProfile string `json:",omitempty"`
 }
 
 // ServiceUpdate holds the parameters for making the service Update call.
@@ -233,6 +246,60 @@ type ServiceSetCharm struct {
 // ServiceExpose holds the parameters for making the service Expose call.
 type ServiceExpose struct {
 	ServiceName string
+
+	// ToCIDRs, if specified, restricts access to the service's
+	// open ports to the addresses within the given CIDRs.
+	ToCIDRs []string `json:",omitempty"`
+
+	// LoadBalancer, if true, requests that the service's open ports
+	// be forwarded to its units by a provider load balancer.
+	LoadBalancer bool `json:",omitempty"`
+}
+
+// ServiceOffer holds the parameters for offering a service's endpoints
+// to other models on the controller.
+type ServiceOffer struct {
+	ServiceName string   `json:"service-name"`
+	Endpoints   []string `json:"endpoints,omitempty"`
+	OfferName   string   `json:"offer-name,omitempty"`
+}
+
+// ServiceOffers holds the parameters for making the service Offer call.
+type ServiceOffers struct {
+	Offers []ServiceOffer `json:"offers"`
+}
+
+// ServiceZonePolicy holds the zone policy to set for a service. An
+// empty policy leaves the choice of zone to the provider.
+type ServiceZonePolicy struct {
+	ServiceName string `json:"service-name"`
+	Policy      string `json:"policy"`
+}
+
+// ServiceZonePolicies holds the parameters for setting the zone
+// policies of services.
+type ServiceZonePolicies struct {
+	Policies []ServiceZonePolicy `json:"policies"`
+}
+
+// MachineProfile holds the attributes of a named machine profile.
+type MachineProfile struct {
+	Name              string            `json:"name"`
+	Series            string            `json:"series,omitempty"`
+	Constraints       constraints.Value `json:"constraints"`
+	CloudInitUserData string            `json:"cloudinit-userdata,omitempty"`
+	RootDiskSource    string            `json:"root-disk-source,omitempty"`
+}
+
+// MachineProfiles holds the parameters for making the
+// AddMachineProfiles call.
+type MachineProfiles struct {
+	Profiles []MachineProfile `json:"profiles"`
+}
+
+// MachineProfilesResult holds the result of a ListMachineProfiles call.
+type MachineProfilesResult struct {
+	Profiles []MachineProfile `json:"profiles"`
 }
 
 // ServiceSet holds the parameters for a service Set
@@ -316,6 +383,35 @@ type Resolved struct {
 	Retry    bool
 }
 
+// TriggerHook holds the parameters for the TriggerHook call. An empty
+// Hook clears any hook previously triggered for the unit.
+type TriggerHook struct {
+	UnitName   string
+	Hook       string
+	RelationId int
+	RemoteUnit string
+}
+
+// TriggeredHook describes a hook a unit has been asked to run once,
+// outside its normal workflow.
+type TriggeredHook struct {
+	Name       string
+	RelationId int
+	RemoteUnit string
+}
+
+// TriggeredHookResult holds the hook triggered for a unit, if any, or
+// an error.
+type TriggeredHookResult struct {
+	Hook  *TriggeredHook
+	Error *Error
+}
+
+// TriggeredHookResults holds the results of the TriggeredHook call.
+type TriggeredHookResults struct {
+	Results []TriggeredHookResult
+}
+
 // ResolvedResults holds results of the Resolved call.
 type ResolvedResults struct {
 	Service  string
@@ -812,6 +908,11 @@ type ModelInfo struct {
 	// for backward compatability. The other fields also have explicit
 	// matching serialization directives for the benefit of being explicit.
 	ControllerUUID string `json:"ServerUUID"`
+
+	// EgressEnforced reports whether the model's egress policy is
+	// enforced by the provider's firewall. A policy allowing all
+	// outbound traffic is always reported as enforced.
+	EgressEnforced bool `json:"egress-enforced,omitempty"`
 }
 
 // MeterStatusParam holds meter status information to be set for the specified tag.
@@ -0,0 +1,44 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// ListSecretsArgs holds the arguments for listing secrets.
+type ListSecretsArgs struct {
+	// IDs holds the IDs of the secrets to list. If empty, all
+	// secrets in the model are listed.
+	IDs []string `json:"ids,omitempty"`
+
+	// Reveal is true if the content of the secrets should be
+	// returned. It may only be set by an administrator.
+	Reveal bool `json:"reveal"`
+}
+
+// SecretDetails holds the details of a secret.
+type SecretDetails struct {
+	ID             string            `json:"id"`
+	Owner          string            `json:"owner"`
+	Description    string            `json:"description,omitempty"`
+	Revision       int               `json:"revision"`
+	Grants         []string          `json:"grants,omitempty"`
+	RotateInterval time.Duration     `json:"rotate-interval,omitempty"`
+	NextRotateTime *time.Time        `json:"next-rotate-time,omitempty"`
+	Created        time.Time         `json:"created"`
+	Updated        time.Time         `json:"updated"`
+	Data           map[string]string `json:"data,omitempty"`
+}
+
+// ListSecretResult holds the details of a secret, or an error.
+type ListSecretResult struct {
+	Result *SecretDetails `json:"result,omitempty"`
+	Error  *Error         `json:"error,omitempty"`
+}
+
+// ListSecretResults holds the results of listing secrets.
+type ListSecretResults struct {
+	Results []ListSecretResult `json:"results"`
+}
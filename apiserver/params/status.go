@@ -28,6 +28,7 @@ type FullStatus struct {
 	AvailableVersion string
 	Machines         map[string]MachineStatus
 	Services         map[string]ServiceStatus
+	RemoteServices   map[string]RemoteServiceStatus
 	Networks         map[string]NetworkStatus
 	Relations        []RelationStatus
 }
@@ -37,6 +38,7 @@ type MachineStatus struct {
 	Agent AgentStatus
 
 	DNSName       string
+	IPAddresses   []string
 	InstanceId    instance.Id
 	InstanceState string
 	Series        string
@@ -53,6 +55,8 @@ type ServiceStatus struct {
 	Err           error
 	Charm         string
 	Exposed       bool
+	LoadBalancer  string
+	ZonePolicy    string
 	Life          string
 	Relations     map[string][]string
 	Networks      NetworksSpecification
@@ -61,6 +65,28 @@ type ServiceStatus struct {
 	Units         map[string]UnitStatus
 	MeterStatuses map[string]MeterStatus
 	Status        AgentStatus
+
+	// WorkloadVersion holds the workload version reported by the
+	// service's leader or, if the leader has not reported one, the
+	// version reported by most of its units.
+	WorkloadVersion string
+}
+
+// RemoteServiceStatus holds status info about a service offered from
+// another model, which services in this model may relate to.
+type RemoteServiceStatus struct {
+	Err       error
+	URL       string
+	Life      string
+	Endpoints []RemoteEndpoint
+	Relations map[string][]string
+}
+
+// RemoteEndpoint describes a relation endpoint of a remote service.
+type RemoteEndpoint struct {
+	Name      string
+	Role      charm.RelationRole
+	Interface string
 }
 
 // MeterStatus represents the meter status of a unit.
@@ -85,11 +111,13 @@ type UnitStatus struct {
 	Life           string
 	Err            error
 
-	Machine       string
-	OpenedPorts   []string
-	PublicAddress string
-	Charm         string
-	Subordinates  map[string]UnitStatus
+	Machine          string
+	AvailabilityZone string
+	OpenedPorts      []string
+	PublicAddress    string
+	Charm            string
+	WorkloadVersion  string
+	Subordinates     map[string]UnitStatus
 }
 
 // TODO(ericsnow) Rename to ServiceNetworksSepcification.
@@ -171,6 +199,55 @@ type UnitStatusHistory struct {
 	Statuses []AgentStatus
 }
 
+// HookExecution describes a hook, action or set of commands run by a
+// unit agent.
+type HookExecution struct {
+	// Kind is "hook", "action" or "commands".
+	Kind string `json:"kind"`
+
+	// Name holds the name of the hook or action, and is empty for
+	// commands.
+	Name string `json:"name,omitempty"`
+
+	// RelationId holds the id of the relation the hook or commands
+	// ran in the context of, or -1. Unit agents set it when recording
+	// an execution; the controller sets it from Relation when reporting
+	// history, if that relation still exists.
+	RelationId int `json:"relation-id"`
+
+	// Relation holds the key of the relation the hook or commands
+	// ran in the context of, if any. It is set only by the
+	// controller.
+	Relation string `json:"relation,omitempty"`
+
+	RemoteUnit string    `json:"remote-unit,omitempty"`
+	Started    time.Time `json:"started"`
+	Finished   time.Time `json:"finished"`
+
+	// Result is "completed", "failed" or "timed-out".
+	Result string `json:"result"`
+
+	ExitCode   int    `json:"exit-code"`
+	StderrTail string `json:"stderr-tail,omitempty"`
+}
+
+// HookHistoryArgs holds the parameters to filter a unit's hook
+// history. Zero-valued fields match any execution.
+type HookHistoryArgs struct {
+	Unit   string     `json:"unit"`
+	Kind   string     `json:"kind,omitempty"`
+	Name   string     `json:"name,omitempty"`
+	Result string     `json:"result,omitempty"`
+	Since  *time.Time `json:"since,omitempty"`
+	Size   int        `json:"size,omitempty"`
+}
+
+// HookHistoryResult holds the executions in a unit's hook history,
+// most recent first.
+type HookHistoryResult struct {
+	Executions []HookExecution `json:"executions"`
+}
+
 const (
 	// DefaultMaxLogsPerEntity is the default value for logs for each entity
 	// that should be kept at any given time.
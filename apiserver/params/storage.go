@@ -474,6 +474,22 @@ type StoragePool struct {
 
 	// Attrs are the pool's configuration attributes.
 	Attrs map[string]interface{} `json:"attrs"`
+
+	// Usage is the pool's current usage in the model, if
+	// the pool is not simply a storage provider type.
+	Usage *StoragePoolUsage `json:"usage,omitempty"`
+}
+
+// StoragePoolUsage describes the volumes and filesystems in a model
+// that have been created from a storage pool.
+type StoragePoolUsage struct {
+	// Count is the number of volumes and filesystems
+	// created from the pool.
+	Count uint64 `json:"count"`
+
+	// Size is the total size of the volumes and filesystems
+	// created from the pool, in MiB.
+	Size uint64 `json:"size"`
 }
 
 // StoragePoolFilter holds a filter for matching storage pools.
@@ -0,0 +1,237 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// PrepareContainerSpaceInterfaces allocates a static address for a
+// container on each of the spaces it needs access to, and returns the
+// information needed to attach the container directly to the host's
+// network devices on those spaces, without a bridge or NAT. The spaces
+// are those of the container's constraints, and those the endpoints of
+// its units' services are bound to. Addresses are picked from the
+// allocatable range of a subnet in each space that the host has an
+// interface on, and recorded in state. A container that needs no spaces
+// gets an empty result, without the provider being asked for the host's
+// interfaces; if the host is not on a suitable subnet of one of the
+// spaces, the result's error satisfies params.IsCodeNotFound.
+func (p *ProvisionerAPI) PrepareContainerSpaceInterfaces(args params.Entities) (params.MachineNetworkConfigResults, error) {
+	result := params.MachineNetworkConfigResults{
+		Results: make([]params.MachineNetworkConfigResult, len(args.Entities)),
+	}
+	environ, host, canAccess, err := p.prepareContainerAccessEnvironment()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	// The host's interfaces are only fetched from the provider once
+	// a container turns out to need any spaces.
+	var hostInterfaces []network.InterfaceInfo
+	getHostInterfaces := func() ([]network.InterfaceInfo, error) {
+		if hostInterfaces != nil {
+			return hostInterfaces, nil
+		}
+		instId, err := host.InstanceId()
+		if errors.IsNotProvisioned(err) {
+			return nil, errors.NotProvisionedf("cannot allocate addresses: host machine %q", host)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		interfaces, err := environ.NetworkInterfaces(instId)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get instance %q interfaces", instId)
+		}
+		hostInterfaces = interfaces
+		return hostInterfaces, nil
+	}
+
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		container, err := p.getMachine(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		} else if !container.IsContainer() {
+			err = errors.Errorf("cannot allocate addresses for %q: not a container", tag)
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		} else if ciid, cerr := container.InstanceId(); cerr == nil {
+			err = errors.Errorf("container %q already provisioned as %q", container, ciid)
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		} else if !errors.IsNotProvisioned(cerr) {
+			result.Results[i].Error = common.ServerError(cerr)
+			continue
+		}
+
+		spaces, err := containerSpaces(container)
+		if err != nil {
+			err = errors.Annotatef(err, "cannot get spaces of container %q", container)
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		} else if len(spaces) == 0 {
+			continue
+		}
+		interfaces, err := getHostInterfaces()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		config, err := p.allocateSpaceAddresses(host, container, spaces, interfaces)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Config = config
+	}
+	return result, nil
+}
+
+// containerSpaces returns the names of the spaces the container needs
+// access to, sorted.
+func containerSpaces(container *state.Machine) ([]string, error) {
+	spaces := set.NewStrings()
+	cons, err := container.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spaces = spaces.Union(set.NewStrings(cons.IncludeSpaces()...))
+
+	units, err := container.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, unit := range units {
+		service, err := unit.Service()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		bindings, err := service.EndpointBindings()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, space := range bindings {
+			if space != "" {
+				spaces.Add(space)
+			}
+		}
+	}
+	return spaces.SortedValues(), nil
+}
+
+// allocateSpaceAddresses allocates an address to the container on each
+// of the given spaces, and returns the configuration of the container's
+// interfaces. Only the first interface is given the default gateway. If
+// any address cannot be allocated, those already allocated are released.
+func (p *ProvisionerAPI) allocateSpaceAddresses(
+	host, container *state.Machine,
+	spaces []string,
+	hostInterfaces []network.InterfaceInfo,
+) (_ []params.NetworkConfig, err error) {
+	var allocated []*state.IPAddress
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, addr := range allocated {
+			if err := addr.EnsureDead(); err != nil {
+				logger.Warningf("cannot release address %q of container %q: %v", addr, container, err)
+			}
+		}
+	}()
+
+	var config []params.NetworkConfig
+	for i, spaceName := range spaces {
+		subnet, hostInterface, err := p.hostSubnetInSpace(spaceName, hostInterfaces)
+		if errors.IsNotFound(err) {
+			return nil, errors.NotFoundf(
+				"interface of host machine %q on a subnet of space %q with allocatable addresses",
+				host, spaceName,
+			)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		interfaceName := fmt.Sprintf("eth%d", i)
+		macAddress := generateMACAddress()
+		addr, err := subnet.PickNewAddress()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot allocate an address on space %q", spaceName)
+		}
+		if err := addr.AllocateTo(container.Id(), interfaceName, macAddress); err != nil {
+			if err := setAddrState(addr, state.AddressStateUnavailable); err != nil {
+				logger.Warningf("cannot mark address %q unavailable: %v", addr, err)
+			}
+			return nil, errors.Trace(err)
+		}
+		allocated = append(allocated, addr)
+		logger.Infof("assigned address %q on space %q to container %q", addr, spaceName, container)
+
+		dnsServers := make([]string, len(hostInterface.DNSServers))
+		for j, dns := range hostInterface.DNSServers {
+			dnsServers[j] = dns.Value
+		}
+		var gatewayAddress string
+		if i == 0 {
+			gatewayAddress = hostInterface.GatewayAddress.Value
+		}
+		config = append(config, params.NetworkConfig{
+			DeviceIndex:         i,
+			MACAddress:          macAddress,
+			CIDR:                subnet.CIDR(),
+			ProviderSubnetId:    string(subnet.ProviderId()),
+			VLANTag:             subnet.VLANTag(),
+			InterfaceName:       interfaceName,
+			ParentInterfaceName: hostInterface.InterfaceName,
+			ConfigType:          string(network.ConfigStatic),
+			Address:             addr.Value(),
+			DNSServers:          dnsServers,
+			GatewayAddress:      gatewayAddress,
+		})
+	}
+	return config, nil
+}
+
+// hostSubnetInSpace returns a subnet of the named space with an
+// allocatable range, along with the host interface on that subnet. It
+// returns an error satisfying errors.IsNotFound if there is none.
+func (p *ProvisionerAPI) hostSubnetInSpace(
+	spaceName string,
+	hostInterfaces []network.InterfaceInfo,
+) (*state.Subnet, network.InterfaceInfo, error) {
+	space, err := p.st.Space(spaceName)
+	if err != nil {
+		return nil, network.InterfaceInfo{}, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return nil, network.InterfaceInfo{}, errors.Trace(err)
+	}
+	for _, subnet := range subnets {
+		if subnet.AllocatableIPLow() == "" {
+			continue
+		}
+		for _, iface := range hostInterfaces {
+			if iface.Disabled || iface.CIDR != subnet.CIDR() {
+				continue
+			}
+			return subnet, iface, nil
+		}
+	}
+	return nil, network.InterfaceInfo{}, errors.NotFoundf("host subnet in space %q", spaceName)
+}
@@ -0,0 +1,187 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+// prepareSpacesSuite contains tests around the
+// PrepareContainerSpaceInterfaces method.
+type prepareSpacesSuite struct {
+	containerSuite
+}
+
+var _ = gc.Suite(&prepareSpacesSuite{})
+
+func (s *prepareSpacesSuite) SetUpTest(c *gc.C) {
+	s.containerSuite.SetUpTest(c)
+	s.newCustomAPI(c, "i-host", false, false)
+
+	// The dummy provider's host interfaces eth0 and eth1 are on
+	// 0.10.0.0/24 and 0.20.0.0/24 respectively.
+	s.addSpace(c, "db", state.SubnetInfo{
+		ProviderId:        "dummy-private",
+		CIDR:              "0.10.0.0/24",
+		AllocatableIPLow:  "0.10.0.5",
+		AllocatableIPHigh: "0.10.0.5",
+	})
+	s.addSpace(c, "dmz", state.SubnetInfo{
+		ProviderId:        "dummy-public",
+		CIDR:              "0.20.0.0/24",
+		AllocatableIPLow:  "0.20.0.7",
+		AllocatableIPHigh: "0.20.0.7",
+	})
+	s.addSpace(c, "storage", state.SubnetInfo{
+		ProviderId:        "dummy-storage",
+		CIDR:              "0.30.0.0/24",
+		AllocatableIPLow:  "0.30.0.5",
+		AllocatableIPHigh: "0.30.0.9",
+	})
+}
+
+func (s *prepareSpacesSuite) addSpace(c *gc.C, name string, subnetInfo state.SubnetInfo) {
+	_, err := s.BackingState.AddSubnet(subnetInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.BackingState.AddSpace(name, "", []string{subnetInfo.CIDR}, false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *prepareSpacesSuite) addContainer(c *gc.C, cons string) *state.Machine {
+	container, err := s.BackingState.AddMachineInsideMachine(
+		state.MachineTemplate{
+			Series:      "quantal",
+			Jobs:        []state.MachineJob{state.JobHostUnits},
+			Constraints: constraints.MustParse(cons),
+		},
+		s.machines[0].Id(),
+		instance.LXD,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return container
+}
+
+func (s *prepareSpacesSuite) TestSuccess(c *gc.C) {
+	container := s.addContainer(c, "spaces=dmz,db")
+
+	results, err := s.provAPI.PrepareContainerSpaceInterfaces(s.makeArgs(container))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	config := results.Results[0].Config
+	c.Assert(config, gc.HasLen, 2)
+	for i := range config {
+		c.Check(config[i].MACAddress, gc.Matches, regexpMACAddress)
+		config[i].MACAddress = ""
+	}
+	c.Assert(config, jc.DeepEquals, []params.NetworkConfig{{
+		DeviceIndex:         0,
+		CIDR:                "0.10.0.0/24",
+		ProviderSubnetId:    "dummy-private",
+		InterfaceName:       "eth0",
+		ParentInterfaceName: "eth0",
+		ConfigType:          "static",
+		Address:             "0.10.0.5",
+		DNSServers:          []string{"ns1.dummy", "ns2.dummy"},
+		GatewayAddress:      "0.10.0.1",
+	}, {
+		DeviceIndex:         1,
+		CIDR:                "0.20.0.0/24",
+		ProviderSubnetId:    "dummy-public",
+		InterfaceName:       "eth1",
+		ParentInterfaceName: "eth1",
+		ConfigType:          "static",
+		Address:             "0.20.0.7",
+		DNSServers:          []string{"ns1.dummy", "ns2.dummy"},
+	}})
+
+	addresses, err := s.BackingState.AllocatedIPAddresses(container.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	values := make([]string, len(addresses))
+	for i, addr := range addresses {
+		values[i] = addr.Value()
+	}
+	c.Assert(values, jc.SameContents, []string{"0.10.0.5", "0.20.0.7"})
+}
+
+func (s *prepareSpacesSuite) TestNoSpaces(c *gc.C) {
+	container := s.addContainer(c, "")
+
+	results, err := s.provAPI.PrepareContainerSpaceInterfaces(s.makeArgs(container))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.MachineNetworkConfigResults{
+		Results: []params.MachineNetworkConfigResult{{}},
+	})
+}
+
+func (s *prepareSpacesSuite) TestHostNotOnSpace(c *gc.C) {
+	container := s.addContainer(c, "spaces=storage")
+
+	results, err := s.provAPI.PrepareContainerSpaceInterfaces(s.makeArgs(container))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches,
+		`interface of host machine "0" on a subnet of space "storage" with allocatable addresses not found`,
+	)
+	c.Assert(results.Results[0].Error, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *prepareSpacesSuite) TestReleasesAddressesOnError(c *gc.C) {
+	container := s.addContainer(c, "spaces=db,storage")
+
+	results, err := s.provAPI.PrepareContainerSpaceInterfaces(s.makeArgs(container))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	// The address allocated on the db space before the failure on
+	// the storage space has been released.
+	addresses, err := s.BackingState.AllocatedIPAddresses(container.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, gc.HasLen, 1)
+	c.Assert(addresses[0].Value(), gc.Equals, "0.10.0.5")
+	c.Assert(addresses[0].Life(), gc.Equals, state.Dead)
+}
+
+func (s *prepareSpacesSuite) TestAddressesExhausted(c *gc.C) {
+	first := s.addContainer(c, "spaces=db")
+	second := s.addContainer(c, "spaces=db")
+
+	results, err := s.provAPI.PrepareContainerSpaceInterfaces(s.makeArgs(first, second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches,
+		`cannot allocate an address on space "db": allocatable IP addresses exhausted for subnet "0.10.0.0/24"`,
+	)
+}
+
+func (s *prepareSpacesSuite) TestErrors(c *gc.C) {
+	container := s.addContainer(c, "spaces=db")
+	err := container.SetProvisioned("i-foo", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.provAPI.PrepareContainerSpaceInterfaces(params.Entities{
+		Entities: []params.Entity{
+			{Tag: container.Tag().String()},
+			{Tag: s.machines[0].Tag().String()},
+			{Tag: "unit-wordpress-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.MachineNetworkConfigResults{
+		Results: []params.MachineNetworkConfigResult{
+			{Error: apiservertesting.ServerError(`container "0/lxd/0" already provisioned as "i-foo"`)},
+			{Error: apiservertesting.ServerError(`cannot allocate addresses for "machine-0": not a container`)},
+			{Error: apiservertesting.ServerError(`"unit-wordpress-0" is not a valid machine tag`)},
+		},
+	})
+}
@@ -39,6 +39,9 @@ func init() {
 	// receive this additional information; otherwise they are
 	// compatible.
 	common.RegisterStandardFacade("Provisioner", 2, NewProvisionerAPI)
+
+	// Version 3 adds PrepareContainerSpaceInterfaces.
+	common.RegisterStandardFacade("Provisioner", 3, NewProvisionerAPI)
 }
 
 // ProvisionerAPI provides access to the Provisioner API facade.
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -95,8 +96,12 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.Provisio
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot get available image metadata")
 	}
+	userData, err := p.machineCloudInitUserData(m)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot determine machine cloud-init user data")
+	}
 
-	return &params.ProvisioningInfo{
+	info := &params.ProvisioningInfo{
 		Constraints:      cons,
 		Series:           m.Series(),
 		Placement:        m.Placement(),
@@ -107,7 +112,57 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.Provisio
 		SubnetsToZones:   subnetsToZones,
 		EndpointBindings: endpointBindings,
 		ImageMetadata:    imageMetadata,
-	}, nil
+
+		CloudInitUserData: userData,
+		RootDiskSource:    m.RootDiskSource(),
+	}
+	if err := p.setMachineZonePolicy(m, info); err != nil {
+		return nil, errors.Annotate(err, "cannot determine machine zone policy")
+	}
+	return info, nil
+}
+
+// machineCloudInitUserData returns the operator-supplied cloud-init user
+// data for the machine: the model's, followed by the machine's own.
+func (p *ProvisionerAPI) machineCloudInitUserData(m *state.Machine) (string, error) {
+	cfg, err := p.st.ModelConfig()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return cloudinit.MergeUserData(cfg.CloudInitUserData(), m.CloudInitUserData())
+}
+
+// setMachineZonePolicy records in info the zone policy of the service
+// whose unit the machine is being provisioned for, along with the number
+// of the service's units already in each zone. Nothing is recorded if
+// the machine hosts no units, or the service has no zone policy.
+func (p *ProvisionerAPI) setMachineZonePolicy(m *state.Machine, info *params.ProvisioningInfo) error {
+	units, err := m.Units()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, unit := range units {
+		if !unit.IsPrincipal() {
+			continue
+		}
+		service, err := unit.Service()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		policy, err := service.ZonePolicy()
+		if err != nil || policy == nil {
+			return errors.Trace(err)
+		}
+		population, err := state.ServiceZonePopulation(p.st, service.Name())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		info.ZonePolicy = policy.String()
+		info.ZonePolicyService = service.Name()
+		info.ZonePopulation = population
+		return nil
+	}
+	return nil
 }
 
 // machineVolumeParams retrieves VolumeParams for the volumes that should be
@@ -12,6 +12,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -246,6 +247,92 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithEndpointBindings(c *gc.
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *withoutControllerSuite) TestProvisioningInfoWithZonePolicy(c *gc.C) {
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := wordpress.SetZonePolicy(&instance.ZonePolicy{Pack: true})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// One unit is on a machine in zone "a", the other awaits a machine.
+	provisioned, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+	zone := "a"
+	err = provisioned.SetProvisioned("i-a", "fake-nonce", &instance.HardwareCharacteristics{
+		AvailabilityZone: &zone,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, m := range []*state.Machine{provisioned, pending} {
+		unit, err := wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: pending.Tag().String()},
+		{Tag: s.machines[0].Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.ZonePolicy, gc.Equals, "pack")
+	c.Assert(result.Results[0].Result.ZonePolicyService, gc.Equals, "wordpress")
+	c.Assert(result.Results[0].Result.ZonePopulation, jc.DeepEquals, map[string]int{"a": 1})
+
+	// Machines not hosting units of a service with a zone policy have none.
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[1].Result.ZonePolicy, gc.Equals, "")
+	c.Assert(result.Results[1].Result.ZonePopulation, gc.IsNil)
+}
+
+func (s *withoutControllerSuite) TestProvisioningInfoWithCloudInitUserData(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"cloudinit-userdata": "packages: [curl]\nruncmd: [update-ca-certificates]",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:            "quantal",
+		Jobs:              []state.MachineJob{state.JobHostUnits},
+		CloudInitUserData: "packages: [ca-certificates]",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: m.Tag().String()}}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.CloudInitUserData, gc.Equals, ""+
+		"packages:\n"+
+		"- curl\n"+
+		"- ca-certificates\n"+
+		"runcmd:\n"+
+		"- update-ca-certificates\n",
+	)
+}
+
+func (s *withoutControllerSuite) TestProvisioningInfoWithRootDiskSource(c *gc.C) {
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:         "quantal",
+		Jobs:           []state.MachineJob{state.JobHostUnits},
+		RootDiskSource: "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: m.Tag().String()}}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.RootDiskSource, gc.Equals, "ssd")
+}
+
 func (s *withoutControllerSuite) TestProvisioningInfoWithUnsuitableSpacesConstraints(c *gc.C) {
 	// Add an empty space.
 	_, err := s.State.AddSpace("empty", "", nil, true)
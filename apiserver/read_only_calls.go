@@ -42,12 +42,17 @@ var readOnlyCalls = set.NewStrings(
 	// command for a read only user to run.
 	// Status is so old it shouldn't be used.
 	"Client.UnitStatusHistory",
+	// UnitHookHistory leaves out the output of hooks for read only
+	// users.
+	"Client.UnitHookHistory",
 	"Client.WatchAll",
 	// TODO: add controller work.
 	"KeyManager.ListKeys",
 	"Service.GetConstraints",
 	"Service.CharmRelations",
 	"Service.Get",
+	// ListSecrets only reveals the content of secrets to administrators.
+	"Secrets.ListSecrets",
 	"Spaces.ListSpaces",
 	"Storage.ListStorageDetails",
 	"Storage.ListFilesystems",
@@ -0,0 +1,14 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations
+
+import (
+	"github.com/juju/juju/state"
+)
+
+// Sync synchronises the cross-model relations of the model once.
+func Sync(st *state.State) error {
+	p := &proxy{st: st}
+	return p.sync()
+}
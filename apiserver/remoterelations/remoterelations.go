@@ -0,0 +1,69 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package remoterelations implements the API used by the remote
+// relations worker, which connects local services to services in
+// other models on the same controller. The relations are synchronised
+// by the controller, which can reach both models; the worker only
+// tells it when to do so.
+package remoterelations
+
+import (
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("RemoteRelations", 1, NewRemoteRelationsAPI)
+}
+
+var logger = loggo.GetLogger("juju.apiserver.remoterelations")
+
+// RemoteRelationsAPI implements the API used by the remote relations
+// worker.
+type RemoteRelationsAPI struct {
+	st        *state.State
+	resources *common.Resources
+}
+
+// NewRemoteRelationsAPI creates a new instance of the RemoteRelations
+// API.
+func NewRemoteRelationsAPI(
+	st *state.State,
+	res *common.Resources,
+	authorizer common.Authorizer,
+) (*RemoteRelationsAPI, error) {
+	if !authorizer.AuthModelManager() {
+		return nil, common.ErrPerm
+	}
+	return &RemoteRelationsAPI{
+		st:        st,
+		resources: res,
+	}, nil
+}
+
+// SyncRemoteRelations synchronises the relations of the model's remote
+// services with their counterparts in the models hosting the services
+// they represent.
+func (api *RemoteRelationsAPI) SyncRemoteRelations() error {
+	p := &proxy{st: api.st}
+	return p.sync()
+}
+
+// WatchRemoteRelations watches for changes to the model's remote
+// services, their relations, and their counterparts in other models.
+func (api *RemoteRelationsAPI) WatchRemoteRelations() (params.NotifyWatchResult, error) {
+	watch := api.st.WatchRemoteRelations()
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: api.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{
+		Error: common.ServerError(watcher.EnsureErr(watch)),
+	}, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/remoterelations"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type remoteRelationsAPISuite struct {
+	remoteRelationsSuite
+	resources *common.Resources
+	api       *remoterelations.RemoteRelationsAPI
+}
+
+var _ = gc.Suite(&remoteRelationsAPISuite{})
+
+func (s *remoteRelationsAPISuite) SetUpTest(c *gc.C) {
+	s.remoteRelationsSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	var err error
+	s.api, err = remoterelations.NewRemoteRelationsAPI(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		EnvironManager: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *remoteRelationsAPISuite) TestNewRemoteRelationsAPIRequiresEnvironManager(c *gc.C) {
+	api, err := remoterelations.NewRemoteRelationsAPI(s.State, s.resources, apiservertesting.FakeAuthorizer{})
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *remoteRelationsAPISuite) TestSyncRemoteRelations(c *gc.C) {
+	err := s.api.SyncRemoteRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mirrorRelation(c).Life(), gc.Equals, state.Alive)
+}
+
+func (s *remoteRelationsAPISuite) TestWatchRemoteRelations(c *gc.C) {
+	result, err := s.api.WatchRemoteRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+
+	resource := s.resources.Get(result.NotifyWatcherId)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	ru, err := s.rel.Unit(s.unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
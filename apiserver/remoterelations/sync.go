@@ -0,0 +1,438 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/state"
+)
+
+// proxy synchronises the cross-model relations of a model.
+//
+// A service consumes an offer from another model by relating to the
+// remote service created for that offer. For each such relation, the
+// proxy creates a mirror relation in the offering model, between the
+// offered service and a remote service standing in for the consumer.
+// It then copies the units in scope, and their settings, from each
+// side of the relation to the other, so that the units at either end
+// see their counterparts as they would in a relation within a model.
+//
+// Remote services are named after the service they represent and the
+// model hosting it (see state.RemoteServiceName), so that they clash
+// neither with local services nor with each other.
+type proxy struct {
+	st *state.State
+}
+
+// sync synchronises the relations of the model's remote services with
+// the models hosting the corresponding services. Failure to synchronise
+// one remote service does not hold up the others, but is returned once
+// they have all been synchronised, so that the synchronisation is
+// retried.
+func (p *proxy) sync() error {
+	remoteServices, err := p.st.AllRemoteServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	model, err := p.st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var failed []string
+	others := make(map[string]*state.State)
+	defer func() {
+		for _, st := range others {
+			st.Close()
+		}
+	}()
+	for _, rs := range remoteServices {
+		modelUUID := rs.SourceModel().Id()
+		other, ok := others[modelUUID]
+		if !ok {
+			other, err = p.st.ForModel(rs.SourceModel())
+			if err != nil {
+				logger.Errorf("cannot open model %q for remote service %q: %v", modelUUID, rs, err)
+				failed = append(failed, rs.Name())
+				continue
+			}
+			others[modelUUID] = other
+		}
+		if rs.IsConsumerProxy() {
+			err = p.syncConsumerProxy(other, rs)
+		} else {
+			err = p.syncRemoteService(other, model.Name(), rs)
+		}
+		if err != nil {
+			logger.Errorf("cannot synchronise relations of remote service %q: %v", rs, err)
+			failed = append(failed, rs.Name())
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("cannot synchronise relations of remote services %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// syncRemoteService synchronises the relations of a remote service
+// created from an offer in the other model.
+func (p *proxy) syncRemoteService(other *state.State, modelName string, rs *state.RemoteService) error {
+	rels, err := rs.Relations()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, rel := range rels {
+		if err := p.syncRelation(other, modelName, rs, rel); err != nil {
+			return errors.Annotatef(err, "relation %q", rel)
+		}
+	}
+	return nil
+}
+
+// syncRelation synchronises a relation between a local service and a
+// remote service created from an offer with its mirror relation in the
+// other model, creating or destroying the mirror relation as required.
+// The local service is represented in the other model by a remote
+// service named after it and the named local model.
+func (p *proxy) syncRelation(other *state.State, modelName string, rs *state.RemoteService, rel *state.Relation) error {
+	remoteEp, err := rel.Endpoint(rs.Name())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	localEps, err := rel.RelatedEndpoints(rs.Name())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	localEp := localEps[0]
+	consumerName, err := state.RemoteServiceName(localEp.ServiceName, modelName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	consumerEp := state.Endpoint{
+		ServiceName: consumerName,
+		Relation:    localEp.Relation,
+	}
+
+	mirrorRel, err := findMirrorRelation(other, p.st.ModelUUID(), consumerEp, remoteEp.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if mirrorRel == nil && rel.Life() == state.Alive {
+		mirrorRel, err = p.addMirrorRelation(other, rs, localEp, consumerName, remoteEp.Name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if mirrorRel == nil || rel.Life() != state.Alive || mirrorRel.Life() != state.Alive {
+		// One side of the relation is going away, so the other must
+		// follow; and neither can be removed while remote units remain
+		// in its scope.
+		if err := leaveScope(rel, rs.Name()); err != nil {
+			return errors.Trace(err)
+		}
+		if rel.Life() == state.Alive {
+			if err := rel.Destroy(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if mirrorRel == nil {
+			return nil
+		}
+		if err := leaveScope(mirrorRel, consumerName); err != nil {
+			return errors.Trace(err)
+		}
+		if mirrorRel.Life() == state.Alive {
+			return errors.Trace(mirrorRel.Destroy())
+		}
+		return nil
+	}
+
+	offeredEps, err := mirrorRel.RelatedEndpoints(consumerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := copyScope(rel, localEp.ServiceName, mirrorRel, consumerName); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(copyScope(mirrorRel, offeredEps[0].ServiceName, rel, rs.Name()))
+}
+
+// addMirrorRelation creates, in the offering model, the relation that
+// mirrors a relation between the local endpoint and the offer from
+// which the remote service was created. The consuming service is
+// represented in the offering model by the remote service with the
+// supplied name, which is created if necessary. If the offer or the
+// offered service has gone away, no relation is created and nil is
+// returned.
+func (p *proxy) addMirrorRelation(
+	other *state.State, rs *state.RemoteService, localEp state.Endpoint, consumerName, offeredEndpoint string,
+) (*state.Relation, error) {
+	offer, err := other.ServiceOffer(rs.OfferName())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	offeredSvc, err := other.Service(offer.ServiceName())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	} else if offeredSvc.Life() != state.Alive {
+		return nil, nil
+	}
+	offeredEp, err := offeredSvc.Endpoint(offeredEndpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	consumer, err := other.RemoteService(consumerName)
+	if errors.IsNotFound(err) {
+		relations, err := p.consumerEndpoints(localEp.ServiceName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		consumer, err = other.AddRemoteService(state.AddRemoteServiceArgs{
+			Name:          consumerName,
+			SourceModel:   p.st.ModelTag(),
+			SourceService: localEp.ServiceName,
+			Endpoints:     relations,
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if consumer.SourceModel().Id() != p.st.ModelUUID() ||
+		consumer.SourceService() != localEp.ServiceName ||
+		!consumer.IsConsumerProxy() {
+		return nil, errors.Errorf(
+			"remote service %q in model %q does not represent service %q",
+			consumer, other.ModelUUID(), localEp.ServiceName,
+		)
+	}
+	consumerEp, err := consumer.Endpoint(localEp.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("relating %v to %v in model %q", consumerEp, offeredEp, other.ModelUUID())
+	return other.AddRelation(offeredEp, consumerEp)
+}
+
+// consumerEndpoints returns the endpoints of the named local service
+// that may be related to across models.
+func (p *proxy) consumerEndpoints(serviceName string) ([]charm.Relation, error) {
+	svc, err := p.st.Service(serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	eps, err := svc.Endpoints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var relations []charm.Relation
+	for _, ep := range eps {
+		if ep.Role == charm.RolePeer || ep.Scope != charm.ScopeGlobal || ep.IsImplicit() {
+			continue
+		}
+		relations = append(relations, ep.Relation)
+	}
+	return relations, nil
+}
+
+// syncConsumerProxy handles a remote service created by the proxy of
+// the other model, to represent a service there that consumes an offer
+// from this model. Relations whose counterparts in the consuming model
+// have been removed are torn down, and the remote service is destroyed
+// once it has no relations left.
+func (p *proxy) syncConsumerProxy(other *state.State, rs *state.RemoteService) error {
+	rels, err := rs.Relations()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rels) == 0 {
+		if rs.Life() == state.Alive {
+			return errors.Trace(rs.Destroy())
+		}
+		return nil
+	}
+	for _, rel := range rels {
+		consumerEp, err := rel.Endpoint(rs.Name())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		offeredEps, err := rel.RelatedEndpoints(rs.Name())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// Look for the relation of the service represented by the
+		// remote service, in the model hosting it.
+		consumerEp.ServiceName = rs.SourceService()
+		counterpart, err := findMirrorRelation(other, p.st.ModelUUID(), consumerEp, offeredEps[0].Name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if counterpart != nil {
+			// The consuming model's proxy takes care of the relation.
+			continue
+		}
+		if err := leaveScope(rel, rs.Name()); err != nil {
+			return errors.Trace(err)
+		}
+		if rel.Life() == state.Alive {
+			if err := rel.Destroy(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+// findMirrorRelation returns the relation in st, if any, between the
+// endpoint ep and the named endpoint of a related service, one of which
+// is a remote service representing the model with the supplied UUID.
+func findMirrorRelation(st *state.State, modelUUID string, ep state.Endpoint, relatedEndpoint string) (*state.Relation, error) {
+	rels, err := relationsOf(st, ep.ServiceName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, rel := range rels {
+		relEp, err := rel.Endpoint(ep.ServiceName)
+		if err != nil || relEp.Name != ep.Name {
+			continue
+		}
+		related, err := rel.RelatedEndpoints(ep.ServiceName)
+		if err != nil || len(related) != 1 || related[0].Name != relatedEndpoint {
+			continue
+		}
+		for _, serviceName := range []string{ep.ServiceName, related[0].ServiceName} {
+			rs, err := st.RemoteService(serviceName)
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if rs.SourceModel().Id() == modelUUID {
+				return rel, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// relationsOf returns the relations of the named service or remote
+// service.
+func relationsOf(st *state.State, serviceName string) ([]*state.Relation, error) {
+	svc, err := st.Service(serviceName)
+	if err == nil {
+		return svc.Relations()
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	rs, err := st.RemoteService(serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return rs.Relations()
+}
+
+// copyScope makes the units of the named service in the scope of the
+// relation "from" appear, with the same settings, as units of the named
+// remote service in the scope of the relation "to". Remote units whose
+// counterparts have left the scope of "from" are removed from the scope
+// of "to".
+func copyScope(from *state.Relation, fromService string, to *state.Relation, toService string) error {
+	unitNames, err := from.UnitsInScope(fromService)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	wanted := make(map[string]bool)
+	for _, unitName := range unitNames {
+		settings, err := from.UnitSettings(unitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		remoteName := toService + strings.TrimPrefix(unitName, fromService)
+		wanted[remoteName] = true
+		ru, err := to.RemoteUnit(remoteName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := enterScope(ru, settings); err != nil {
+			return errors.Annotatef(err, "unit %q", remoteName)
+		}
+	}
+	existing, err := to.UnitsInScope(toService)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, remoteName := range existing {
+		if wanted[remoteName] {
+			continue
+		}
+		ru, err := to.RemoteUnit(remoteName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := ru.LeaveScope(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// enterScope ensures that the remote unit is in scope with the given
+// settings.
+func enterScope(ru *state.RelationUnit, settings map[string]interface{}) error {
+	inScope, err := ru.InScope()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !inScope {
+		err := ru.EnterScope(settings)
+		if err == state.ErrCannotEnterScope {
+			// The relation is going away; the next synchronisation
+			// will deal with it.
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	node, err := ru.Settings()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, key := range node.Keys() {
+		if _, ok := settings[key]; !ok {
+			node.Delete(key)
+		}
+	}
+	node.Update(settings)
+	_, err = node.Write()
+	return errors.Trace(err)
+}
+
+// leaveScope removes all the units of the named remote service from the
+// scope of the relation.
+func leaveScope(rel *state.Relation, remoteService string) error {
+	unitNames, err := rel.UnitsInScope(remoteService)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, unitName := range unitNames {
+		ru, err := rel.RemoteUnit(unitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := ru.LeaveScope(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
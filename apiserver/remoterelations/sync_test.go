@@ -0,0 +1,168 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/remoterelations"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type remoteRelationsSuite struct {
+	statetesting.StateSuite
+	offering *state.State
+	mysql    *state.Service
+	rel      *state.Relation
+	unit     *state.Unit
+}
+
+var _ = gc.Suite(&remoteRelationsSuite{})
+
+func (s *remoteRelationsSuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+
+	// Offer mysql from another model...
+	s.offering = s.Factory.MakeModel(c, &factory.ModelParams{Name: "prod"})
+	s.AddCleanup(func(*gc.C) { s.offering.Close() })
+	f := factory.NewFactory(s.offering)
+	s.mysql = f.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: f.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	_, err := s.offering.AddServiceOffer(state.AddServiceOfferArgs{
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// ...and relate wordpress to it.
+	wordpress := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	_, err = s.State.ConsumeServiceOffer("test-admin/prod.mysql", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	eps, err := s.State.InferEndpoints("wordpress", "mysql-prod")
+	c.Assert(err, jc.ErrorIsNil)
+	s.rel, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit, err = wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *remoteRelationsSuite) mirrorRelation(c *gc.C) *state.Relation {
+	rel, err := s.offering.KeyRelation("wordpress-testenv:db mysql:server")
+	c.Assert(err, jc.ErrorIsNil)
+	return rel
+}
+
+func (s *remoteRelationsSuite) TestSyncCreatesMirrorRelation(c *gc.C) {
+	err := remoterelations.Sync(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+
+	consumer, err := s.offering.RemoteService("wordpress-testenv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(consumer.IsConsumerProxy(), jc.IsTrue)
+	c.Assert(consumer.SourceModel(), gc.Equals, s.State.ModelTag())
+	c.Assert(s.mirrorRelation(c).Life(), gc.Equals, state.Alive)
+
+	// Synchronising the offering model leaves the mirror relation alone.
+	err = remoterelations.Sync(s.offering)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mirrorRelation(c).Life(), gc.Equals, state.Alive)
+}
+
+func (s *remoteRelationsSuite) TestSyncCopiesUnits(c *gc.C) {
+	ru, err := s.rel.Unit(s.unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(map[string]interface{}{"wp": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = remoterelations.Sync(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+
+	mirror := s.mirrorRelation(c)
+	units, err := mirror.UnitsInScope("wordpress-testenv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"wordpress-testenv/0"})
+	settings, err := mirror.UnitSettings("wordpress-testenv/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"wp": "1"})
+
+	// A unit of the offered service entering scope, and a change to
+	// the consumer's settings, are both propagated.
+	mysqlUnit, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	mysqlRU, err := mirror.Unit(mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysqlRU.EnterScope(map[string]interface{}{"user": "admin"})
+	c.Assert(err, jc.ErrorIsNil)
+	node, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	node.Set("wp", "2")
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	err = remoterelations.Sync(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+
+	units, err = s.rel.UnitsInScope("mysql-prod")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"mysql-prod/0"})
+	settings, err = ru.ReadSettings("mysql-prod/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"user": "admin"})
+	settings, err = mysqlRU.ReadSettings("wordpress-testenv/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"wp": "2"})
+
+	// Units leaving scope are removed from the other side.
+	err = ru.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	err = remoterelations.Sync(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = mirror.UnitsInScope("wordpress-testenv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *remoteRelationsSuite) TestSyncTearsDownDestroyedRelation(c *gc.C) {
+	err := remoterelations.Sync(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	mirror := s.mirrorRelation(c)
+	mysqlUnit, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	mysqlRU, err := mirror.Unit(mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysqlRU.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = remoterelations.Sync(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Destroying the relation in the consuming model leaves it dying
+	// while the remote unit is in scope; synchronising removes the
+	// remote unit, and destroys the mirror relation.
+	err = s.rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = remoterelations.Sync(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = mirror.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mirror.Life(), gc.Equals, state.Dying)
+
+	// Once the offered unit has left, the mirror relation goes away, and
+	// the offering model's proxy cleans up the consumer's stand-in.
+	err = mysqlRU.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	err = mirror.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = remoterelations.Sync(s.offering)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.offering.RemoteService("wordpress-testenv")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
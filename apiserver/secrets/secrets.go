@@ -0,0 +1,122 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets implements the API facade used by clients to inspect
+// the secrets stored by charms.
+package secrets
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Secrets", 1, NewAPI)
+}
+
+// API implements the Secrets API facade.
+type API struct {
+	st         *state.State
+	authorizer common.Authorizer
+}
+
+// NewAPI returns a new Secrets API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		st:         st,
+		authorizer: authorizer,
+	}, nil
+}
+
+// ListSecrets returns the details of the given secrets, or of all the
+// secrets in the model if none are given. The content of the secrets
+// is only returned if requested by a controller administrator or the
+// model owner.
+func (api *API) ListSecrets(args params.ListSecretsArgs) (params.ListSecretResults, error) {
+	if args.Reveal {
+		if err := api.checkCanReveal(); err != nil {
+			return params.ListSecretResults{}, err
+		}
+	}
+	if len(args.IDs) == 0 {
+		secrets, err := api.st.AllSecrets()
+		if err != nil {
+			return params.ListSecretResults{}, common.ServerError(err)
+		}
+		results := make([]params.ListSecretResult, len(secrets))
+		for i, secret := range secrets {
+			results[i].Result, err = secretDetails(secret, args.Reveal)
+			results[i].Error = common.ServerError(err)
+		}
+		return params.ListSecretResults{Results: results}, nil
+	}
+	results := make([]params.ListSecretResult, len(args.IDs))
+	for i, id := range args.IDs {
+		secret, err := api.st.Secret(id)
+		if err == nil {
+			results[i].Result, err = secretDetails(secret, args.Reveal)
+		}
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ListSecretResults{Results: results}, nil
+}
+
+// checkCanReveal returns an error if the authenticated user may not see
+// the content of secrets.
+func (api *API) checkCanReveal() error {
+	// Since we know this is a user tag (because AuthClient is true),
+	// we just do the type assertion to the UserTag.
+	apiUser, _ := api.authorizer.GetAuthTag().(names.UserTag)
+	isAdmin, err := api.st.IsControllerAdministrator(apiUser)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isAdmin {
+		return nil
+	}
+	model, err := api.st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if model.Owner().Canonical() == apiUser.Canonical() {
+		return nil
+	}
+	return common.ErrPerm
+}
+
+func secretDetails(secret *state.Secret, reveal bool) (*params.SecretDetails, error) {
+	details := &params.SecretDetails{
+		ID:             secret.ID(),
+		Owner:          secret.Owner().String(),
+		Description:    secret.Description(),
+		Revision:       secret.Revision(),
+		RotateInterval: secret.RotateInterval(),
+		Created:        secret.Created(),
+		Updated:        secret.Updated(),
+	}
+	for _, tag := range secret.Grants() {
+		details.Grants = append(details.Grants, tag.String())
+	}
+	if next := secret.NextRotateTime(); !next.IsZero() {
+		details.NextRotateTime = &next
+	}
+	if reveal {
+		data, err := secret.Data()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		details.Data = data
+	}
+	return details, nil
+}
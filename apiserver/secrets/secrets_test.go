@@ -0,0 +1,107 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/secrets"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type secretsSuite struct {
+	jujutesting.JujuConnSuite
+	secret *state.Secret
+}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func (s *secretsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.secret, err = s.State.AddSecret(state.SecretParams{
+		Owner:       wordpress.ServiceTag(),
+		Description: "db password",
+		Data:        map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.secret.Grant(names.NewUnitTag("mysql/0"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secretsSuite) newAPI(c *gc.C, tag names.Tag) *secrets.API {
+	api, err := secrets.NewAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: tag,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *secretsSuite) expectDetails(reveal bool) *params.SecretDetails {
+	details := &params.SecretDetails{
+		ID:          s.secret.ID(),
+		Owner:       "service-wordpress",
+		Description: "db password",
+		Revision:    1,
+		Grants:      []string{"unit-mysql-0"},
+		Created:     s.secret.Created(),
+		Updated:     s.secret.Updated(),
+	}
+	if reveal {
+		details.Data = map[string]string{"password": "sekrit"}
+	}
+	return details
+}
+
+func (s *secretsSuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := secrets.NewAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: names.NewUnitTag("wordpress/0"),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *secretsSuite) TestListSecrets(c *gc.C) {
+	api := s.newAPI(c, names.NewUserTag("bob@local"))
+	results, err := api.ListSecrets(params.ListSecretsArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ListSecretResults{
+		Results: []params.ListSecretResult{{Result: s.expectDetails(false)}},
+	})
+}
+
+func (s *secretsSuite) TestListSecretsByID(c *gc.C) {
+	api := s.newAPI(c, names.NewUserTag("bob@local"))
+	results, err := api.ListSecrets(params.ListSecretsArgs{
+		IDs: []string{s.secret.ID(), "666"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ListSecretResults{
+		Results: []params.ListSecretResult{
+			{Result: s.expectDetails(false)},
+			{Error: apiservertesting.NotFoundError(`secret "666"`)},
+		},
+	})
+}
+
+func (s *secretsSuite) TestListSecretsRevealAdmin(c *gc.C) {
+	api := s.newAPI(c, s.AdminUserTag(c))
+	results, err := api.ListSecrets(params.ListSecretsArgs{Reveal: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ListSecretResults{
+		Results: []params.ListSecretResult{{Result: s.expectDetails(true)}},
+	})
+}
+
+func (s *secretsSuite) TestListSecretsRevealNotAdmin(c *gc.C) {
+	api := s.newAPI(c, names.NewUserTag("bob@local"))
+	_, err := api.ListSecrets(params.ListSecretsArgs{Reveal: true})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	jjj "github.com/juju/juju/juju"
 	"github.com/juju/juju/state"
@@ -25,10 +26,26 @@ var (
 	logger = loggo.GetLogger("juju.apiserver.service")
 
 	newStateStorage = statestorage.NewStorage
+	newEnviron      = environs.New
 )
 
 func init() {
 	common.RegisterStandardFacade("Service", 3, NewAPI)
+
+	// Version 4 adds the ToCIDRs parameter to Expose.
+	common.RegisterStandardFacade("Service", 4, NewAPI)
+
+	// Version 5 adds Offer, and support for offer URLs in AddRelation.
+	common.RegisterStandardFacade("Service", 5, NewAPI)
+
+	// Version 6 adds SetZonePolicy.
+	common.RegisterStandardFacade("Service", 6, NewAPI)
+
+	// Version 7 adds MachineProfile to Deploy.
+	common.RegisterStandardFacade("Service", 7, NewAPI)
+
+	// Version 8 adds the LoadBalancer parameter to Expose.
+	common.RegisterStandardFacade("Service", 8, NewAPI)
 }
 
 // Service defines the methods on the service API end point.
@@ -140,6 +157,26 @@ func deployService(st *state.State, owner string, args params.ServiceDeploy) err
 		return errors.Trace(err)
 	}
 
+	// Apply any machine profile to the machines created for the
+	// service's units. Explicit constraints and series take precedence
+	// over the profile's.
+	var cloudInitUserData, rootDiskSource string
+	if args.MachineProfile != "" {
+		profile, err := st.MachineProfile(args.MachineProfile)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		args.Constraints, err = profile.MergeConstraints(args.Constraints)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if args.Series == "" && curl.Series == "" {
+			args.Series = profile.Series()
+		}
+		cloudInitUserData = profile.CloudInitUserData()
+		rootDiskSource = profile.RootDiskSource()
+	}
+
 	var settings charm.Settings
 	if len(args.ConfigYAML) > 0 {
 		settings, err = ch.Config().ParseSettingsYAML([]byte(args.ConfigYAML), args.ServiceName)
@@ -171,6 +208,9 @@ func deployService(st *state.State, owner string, args params.ServiceDeploy) err
 			Storage:          args.Storage,
 			EndpointBindings: args.EndpointBindings,
 			Resources:        args.Resources,
+
+			CloudInitUserData: cloudInitUserData,
+			RootDiskSource:    rootDiskSource,
 		})
 	return errors.Trace(err)
 }
@@ -433,7 +473,10 @@ func (api *API) CharmRelations(p params.ServiceCharmRelations) (params.ServiceCh
 }
 
 // Expose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
+// were also explicitly marked by units as open. If any CIDRs are
+// specified, the ports are only exposed to addresses within them. If
+// LoadBalancer is set, the ports are exposed through a provider load
+// balancer in front of the service's units.
 func (api *API) Expose(args params.ServiceExpose) error {
 	if err := api.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
@@ -442,7 +485,32 @@ func (api *API) Expose(args params.ServiceExpose) error {
 	if err != nil {
 		return err
 	}
-	return svc.SetExposed()
+	if args.LoadBalancer {
+		if err := api.checkLoadBalancers(); err != nil {
+			return errors.Trace(err)
+		}
+		return svc.SetExposedLoadBalanced(args.ToCIDRs...)
+	}
+	return svc.SetExposed(args.ToCIDRs...)
+}
+
+// checkLoadBalancers returns an error satisfying errors.IsNotSupported
+// if the model's provider cannot create load balancers, so that a
+// service is never exposed through a load balancer that will not be
+// created.
+func (api *API) checkLoadBalancers() error {
+	cfg, err := api.state.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := newEnviron(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := env.(environs.LoadBalancerManager); !ok {
+		return errors.NotSupportedf("load balancers on %q models", cfg.Type())
+	}
+	return nil
 }
 
 // Unexpose changes the juju-managed firewall to unexpose any ports that
@@ -551,7 +619,26 @@ func (api *API) AddRelation(args params.AddRelation) (params.AddRelationResults,
 	if err := api.check.ChangeAllowed(); err != nil {
 		return params.AddRelationResults{}, errors.Trace(err)
 	}
-	inEps, err := api.state.InferEndpoints(args.Endpoints...)
+	endpoints := make([]string, len(args.Endpoints))
+	for i, ep := range args.Endpoints {
+		if !state.IsServiceOfferURL(ep) {
+			endpoints[i] = ep
+			continue
+		}
+		// Relating to an offer from another model requires a remote
+		// service to stand in for the offered service, and access to
+		// the offering model.
+		user, ok := api.authorizer.GetAuthTag().(names.UserTag)
+		if !ok {
+			return params.AddRelationResults{}, common.ErrPerm
+		}
+		remoteService, err := api.state.ConsumeServiceOffer(ep, user)
+		if err != nil {
+			return params.AddRelationResults{}, errors.Trace(err)
+		}
+		endpoints[i] = remoteService.Name()
+	}
+	inEps, err := api.state.InferEndpoints(endpoints...)
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
@@ -570,6 +657,58 @@ func (api *API) AddRelation(args params.AddRelation) (params.AddRelationResults,
 	return params.AddRelationResults{Endpoints: outEps}, nil
 }
 
+// Offer makes the specified endpoints of services available for services
+// in other models on the controller to relate to.
+func (api *API) Offer(args params.ServiceOffers) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Offers)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Offers {
+		_, err := api.state.AddServiceOffer(state.AddServiceOfferArgs{
+			OfferName:   arg.OfferName,
+			ServiceName: arg.ServiceName,
+			Endpoints:   arg.Endpoints,
+		})
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetZonePolicy sets the policy used to place the machines created for
+// each service's units into availability zones. An empty policy clears
+// any policy previously set.
+func (api *API) SetZonePolicy(args params.ServiceZonePolicies) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Policies)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Policies {
+		err := api.setZonePolicy(arg.ServiceName, arg.Policy)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) setZonePolicy(serviceName, policy string) error {
+	svc, err := api.state.Service(serviceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if policy == "" {
+		return svc.SetZonePolicy(nil)
+	}
+	zonePolicy, err := instance.ParseZonePolicy(policy)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return svc.SetZonePolicy(&zonePolicy)
+}
+
 // DestroyRelation removes the relation between the specified endpoints.
 func (api *API) DestroyRelation(args params.DestroyRelation) error {
 	if err := api.check.RemoveAllowed(); err != nil {
@@ -24,6 +24,8 @@ import (
 	"github.com/juju/juju/apiserver/service"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
@@ -1015,6 +1017,75 @@ func (s *serviceSuite) TestServiceDeployConfig(c *gc.C) {
 	c.Assert(settings, gc.DeepEquals, charm.Settings{"username": "fred"})
 }
 
+func (s *serviceSuite) TestServiceDeployWithMachineProfile(c *gc.C) {
+	_, err := s.State.AddMachineProfile(state.AddMachineProfileArgs{
+		Name:              "database",
+		Constraints:       constraints.MustParse("mem=4G spaces=db"),
+		CloudInitUserData: "packages: [ca-certificates]",
+		RootDiskSource:    "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.UploadCharm(c, "precise/dummy-0", "dummy")
+	err = service.AddCharmWithAuthorization(s.State, params.AddCharmWithAuthorization{URL: curl.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := s.serviceApi.Deploy(params.ServicesDeploy{
+		Services: []params.ServiceDeploy{{
+			CharmUrl:       curl.String(),
+			ServiceName:    "service-name",
+			Constraints:    constraints.MustParse("mem=8G"),
+			MachineProfile: "database",
+		}, {
+			CharmUrl:       curl.String(),
+			ServiceName:    "other-service",
+			MachineProfile: "web",
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `machine profile "web" not found`)
+
+	svc, err := s.State.Service("service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err := svc.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=8G spaces=db"))
+	c.Assert(svc.CloudInitUserData(), gc.Equals, "packages: [ca-certificates]")
+	c.Assert(svc.RootDiskSource(), gc.Equals, "ssd")
+}
+
+func (s *serviceSuite) TestServiceDeployWithMachineProfileSeries(c *gc.C) {
+	_, err := s.State.AddMachineProfile(state.AddMachineProfileArgs{
+		Name:   "trusty-db",
+		Series: "trusty",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.UploadCharmMultiSeries(c, "~who/multi-series", "multi-series")
+	err = service.AddCharmWithAuthorization(s.State, params.AddCharmWithAuthorization{URL: curl.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := s.serviceApi.Deploy(params.ServicesDeploy{
+		Services: []params.ServiceDeploy{{
+			CharmUrl:       curl.String(),
+			ServiceName:    "from-profile",
+			MachineProfile: "trusty-db",
+		}, {
+			CharmUrl:       curl.String(),
+			ServiceName:    "explicit",
+			Series:         "precise",
+			MachineProfile: "trusty-db",
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+
+	svc, err := s.State.Service("from-profile")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.Series(), gc.Equals, "trusty")
+	svc, err = s.State.Service("explicit")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.Series(), gc.Equals, "precise")
+}
+
 func (s *serviceSuite) TestServiceDeployConfigError(c *gc.C) {
 	// TODO(fwereade): test Config/ConfigYAML handling directly on srvClient.
 	// Can't be done cleanly until it's extracted similarly to Machiner.
@@ -1778,7 +1849,7 @@ func (s *serviceSuite) TestServiceExpose(c *gc.C) {
 	c.Assert(svcs[1].IsExposed(), jc.IsTrue)
 	for i, t := range serviceExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err = s.serviceApi.Expose(params.ServiceExpose{t.service})
+		err = s.serviceApi.Expose(params.ServiceExpose{ServiceName: t.service})
 		if t.err != "" {
 			c.Assert(err, gc.ErrorMatches, t.err)
 		} else {
@@ -1790,6 +1861,62 @@ func (s *serviceSuite) TestServiceExpose(c *gc.C) {
 	}
 }
 
+func (s *serviceSuite) TestServiceExposeToCIDRs(c *gc.C) {
+	s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	err := s.serviceApi.Expose(params.ServiceExpose{
+		ServiceName: "dummy-service",
+		ToCIDRs:     []string{"192.168.1.0/24", "10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsTrue)
+	c.Assert(service.ExposedCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "192.168.1.0/24"})
+
+	err = s.serviceApi.Expose(params.ServiceExpose{
+		ServiceName: "dummy-service",
+		ToCIDRs:     []string{"bad"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot set exposed flag for service "dummy-service" to true: CIDR "bad" not valid`)
+}
+
+func (s *serviceSuite) TestServiceExposeLoadBalancer(c *gc.C) {
+	s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	err := s.serviceApi.Expose(params.ServiceExpose{
+		ServiceName:  "dummy-service",
+		LoadBalancer: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsTrue)
+	c.Assert(service.IsLoadBalanced(), jc.IsTrue)
+
+	err = s.serviceApi.Unexpose(params.ServiceUnexpose{ServiceName: "dummy-service"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsFalse)
+	c.Assert(service.IsLoadBalanced(), jc.IsFalse)
+}
+
+func (s *serviceSuite) TestServiceExposeLoadBalancerNotSupported(c *gc.C) {
+	s.PatchValue(service.NewEnviron, func(cfg *config.Config) (environs.Environ, error) {
+		env, err := environs.New(cfg)
+		// Hide the environ's environs.LoadBalancerManager methods.
+		return struct{ environs.Environ }{env}, err
+	})
+	s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	err := s.serviceApi.Expose(params.ServiceExpose{
+		ServiceName:  "dummy-service",
+		LoadBalancer: true,
+	})
+	c.Assert(err, gc.ErrorMatches, `load balancers on "dummy" models not supported`)
+	service, err := s.State.Service("dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsFalse)
+}
+
 func (s *serviceSuite) setupServiceExpose(c *gc.C) {
 	charm := s.AddTestingCharm(c, "dummy")
 	serviceNames := []string{"dummy-service", "exposed-service"}
@@ -1830,7 +1957,7 @@ var serviceExposeTests = []struct {
 func (s *serviceSuite) assertServiceExpose(c *gc.C) {
 	for i, t := range serviceExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err := s.serviceApi.Expose(params.ServiceExpose{t.service})
+		err := s.serviceApi.Expose(params.ServiceExpose{ServiceName: t.service})
 		if t.err != "" {
 			c.Assert(err, gc.ErrorMatches, t.err)
 		} else {
@@ -1845,7 +1972,7 @@ func (s *serviceSuite) assertServiceExpose(c *gc.C) {
 func (s *serviceSuite) assertServiceExposeBlocked(c *gc.C, msg string) {
 	for i, t := range serviceExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err := s.serviceApi.Expose(params.ServiceExpose{t.service})
+		err := s.serviceApi.Expose(params.ServiceExpose{ServiceName: t.service})
 		s.AssertBlocked(c, err, msg)
 	}
 }
@@ -2443,6 +2570,155 @@ func (s *serviceSuite) TestAddAlreadyAddedRelation(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot add relation "wordpress:db mysql:server": relation already exists`)
 }
 
+func (s *serviceSuite) TestAddRelationToServiceOffer(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	otherSt := s.Factory.MakeModel(c, &factory.ModelParams{Name: "prod"})
+	defer otherSt.Close()
+	f := factory.NewFactory(otherSt)
+	f.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: f.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	_, err := otherSt.AddServiceOffer(state.AddServiceOfferArgs{
+		OfferName:   "db",
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	url := s.AdminUserTag(c).Canonical() + "/prod.db"
+	res, err := s.serviceApi.AddRelation(params.AddRelation{
+		Endpoints: []string{"wordpress", url},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Endpoints, gc.HasLen, 2)
+	c.Assert(res.Endpoints["db-prod"].Name, gc.Equals, "server")
+
+	remote, err := s.State.RemoteService("db-prod")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remote.URL(), gc.Equals, url)
+	rels, err := remote.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+}
+
+func (s *serviceSuite) TestAddRelationToServiceOfferUnauthorized(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	otherSt := s.Factory.MakeModel(c, &factory.ModelParams{Name: "prod"})
+	defer otherSt.Close()
+	f := factory.NewFactory(otherSt)
+	f.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: f.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	_, err := otherSt.AddServiceOffer(state.AddServiceOfferArgs{
+		OfferName:   "db",
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Bob has access to this model, but not to the offering model.
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	serviceApi, err := service.NewAPI(s.State, nil, apiservertesting.FakeAuthorizer{
+		Tag: bob.UserTag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	url := s.AdminUserTag(c).Canonical() + "/prod.db"
+	_, err = serviceApi.AddRelation(params.AddRelation{
+		Endpoints: []string{"wordpress", url},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot consume service offer ".*": user "bob@local" does not have access to model "prod"`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	_, err = s.State.RemoteService("db-prod")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *serviceSuite) TestAddRelationToUnknownServiceOffer(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := s.serviceApi.AddRelation(params.AddRelation{
+		Endpoints: []string{"wordpress", "admin@local/prod.db"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot consume service offer "admin@local/prod.db": model "admin@local/prod" not found`)
+}
+
+func (s *serviceSuite) TestOffer(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	results, err := s.serviceApi.Offer(params.ServiceOffers{
+		Offers: []params.ServiceOffer{{
+			ServiceName: "wordpress",
+			OfferName:   "blog",
+			Endpoints:   []string{"url"},
+		}, {
+			ServiceName: "unknown",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot add service offer "unknown": service "unknown" not found`)
+
+	offer, err := s.State.ServiceOffer("blog")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.ServiceName(), gc.Equals, "wordpress")
+	c.Assert(offer.EndpointNames(), jc.DeepEquals, []string{"url"})
+}
+
+func (s *serviceSuite) TestBlockChangesOffer(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.BlockAllChanges(c, "TestBlockChangesOffer")
+	_, err := s.serviceApi.Offer(params.ServiceOffers{
+		Offers: []params.ServiceOffer{{ServiceName: "wordpress"}},
+	})
+	s.AssertBlocked(c, err, "TestBlockChangesOffer")
+}
+
+func (s *serviceSuite) TestSetZonePolicy(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	results, err := s.serviceApi.SetZonePolicy(params.ServiceZonePolicies{
+		Policies: []params.ServiceZonePolicy{{
+			ServiceName: "wordpress",
+			Policy:      "zones=a,b",
+		}, {
+			ServiceName: "wordpress",
+			Policy:      "scatter",
+		}, {
+			ServiceName: "unknown",
+			Policy:      "pack",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `zone policy "scatter" not valid`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `service "unknown" not found`)
+
+	err = wordpress.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err := wordpress.ZonePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, &instance.ZonePolicy{Zones: []string{"a", "b"}})
+
+	// An empty policy clears the service's policy.
+	results, err = s.serviceApi.SetZonePolicy(params.ServiceZonePolicies{
+		Policies: []params.ServiceZonePolicy{{ServiceName: "wordpress"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	err = wordpress.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = wordpress.ZonePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.IsNil)
+}
+
+func (s *serviceSuite) TestBlockChangesSetZonePolicy(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.BlockAllChanges(c, "TestBlockChangesSetZonePolicy")
+	_, err := s.serviceApi.SetZonePolicy(params.ServiceZonePolicies{
+		Policies: []params.ServiceZonePolicy{{ServiceName: "wordpress", Policy: "pack"}},
+	})
+	s.AssertBlocked(c, err, "TestBlockChangesSetZonePolicy")
+}
+
 func (s *serviceSuite) setupDestroyRelationScenario(c *gc.C, endpoints []string) *state.Relation {
 	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
 	// Add a relation between the endpoints.
@@ -42,6 +42,7 @@ type baseStorageSuite struct {
 
 	poolManager *mockPoolManager
 	pools       map[string]*jujustorage.Config
+	poolUsage   map[string]state.StoragePoolUsage
 
 	blocks map[state.BlockType]state.Block
 }
@@ -54,6 +55,7 @@ func (s *baseStorageSuite) SetUpTest(c *gc.C) {
 	s.state = s.constructState()
 
 	s.pools = make(map[string]*jujustorage.Config)
+	s.poolUsage = make(map[string]state.StoragePoolUsage)
 	s.poolManager = s.constructPoolManager()
 
 	var err error
@@ -84,6 +86,7 @@ const (
 	addStorageForUnitCall                   = "addStorageForUnit"
 	getBlockForTypeCall                     = "getBlockForType"
 	volumeAttachmentCall                    = "volumeAttachment"
+	storagePoolUsageCall                    = "storagePoolUsage"
 )
 
 func (s *baseStorageSuite) constructState() *mockState {
@@ -218,6 +221,10 @@ func (s *baseStorageSuite) constructState() *mockState {
 			return []state.Filesystem{s.filesystem}, nil
 		},
 		modelName: "storagetest",
+		storagePoolUsage: func(poolName string) (state.StoragePoolUsage, error) {
+			s.calls = append(s.calls, storagePoolUsageCall)
+			return s.poolUsage[poolName], nil
+		},
 		addStorageForUnit: func(u names.UnitTag, name string, cons state.StorageConstraints) error {
 			s.calls = append(s.calls, addStorageForUnitCall)
 			return nil
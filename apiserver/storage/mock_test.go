@@ -60,6 +60,7 @@ type mockState struct {
 	addStorageForUnit                   func(u names.UnitTag, name string, cons state.StorageConstraints) error
 	getBlockForType                     func(t state.BlockType) (state.Block, bool, error)
 	blockDevices                        func(names.MachineTag) ([]state.BlockDeviceInfo, error)
+	storagePoolUsage                    func(poolName string) (state.StoragePoolUsage, error)
 }
 
 func (st *mockState) StorageInstance(s names.StorageTag) (state.StorageInstance, error) {
@@ -114,6 +115,10 @@ func (st *mockState) ModelName() (string, error) {
 	return st.modelName, nil
 }
 
+func (st *mockState) StoragePoolUsage(poolName string) (state.StoragePoolUsage, error) {
+	return st.storagePoolUsage(poolName)
+}
+
 func (st *mockState) AllVolumes() ([]state.Volume, error) {
 	return st.allVolumes()
 }
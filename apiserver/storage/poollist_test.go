@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	apiserverstorage "github.com/juju/juju/apiserver/storage"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/storage/provider/registry"
@@ -49,6 +50,23 @@ func (s *poolSuite) TestList(c *gc.C) {
 	c.Assert(one.Result[0].Provider, gc.Equals, string(provider.LoopProviderType))
 }
 
+func (s *poolSuite) TestListUsage(c *gc.C) {
+	s.createPools(c, 1)
+	s.poolUsage["testpool0"] = state.StoragePoolUsage{Count: 2, Size: 3072}
+	results, err := s.api.ListPools(params.StoragePoolFilters{[]params.StoragePoolFilter{{
+		Names: []string{"testpool0"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result, jc.DeepEquals, []params.StoragePool{{
+		Name:     "testpool0",
+		Provider: string(provider.LoopProviderType),
+		Usage:    &params.StoragePoolUsage{Count: 2, Size: 3072},
+	}})
+	s.assertCalls(c, []string{storagePoolUsageCall})
+}
+
 func (s *poolSuite) TestListManyResults(c *gc.C) {
 	s.createPools(c, 2)
 	results, err := s.api.ListPools(params.StoragePoolFilters{[]params.StoragePoolFilter{{}}})
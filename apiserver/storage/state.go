@@ -53,6 +53,9 @@ type storageAccess interface {
 	// ModelName is required for pool functionality.
 	ModelName() (string, error)
 
+	// StoragePoolUsage is required for pool functionality.
+	StoragePoolUsage(poolName string) (state.StoragePoolUsage, error)
+
 	// AllVolumes is required for volume functionality.
 	AllVolumes() ([]state.Volume, error)
 
@@ -244,8 +244,19 @@ func (a *API) listPools(filter params.StoragePoolFilter) ([]params.StoragePool,
 		return nil, err
 	}
 	matches := buildFilter(filter)
+	matchingPools := filterPools(pools, matches)
+	for i, pool := range matchingPools {
+		usage, err := a.storage.StoragePoolUsage(pool.Name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting usage of pool %q", pool.Name)
+		}
+		matchingPools[i].Usage = &params.StoragePoolUsage{
+			Count: usage.Count,
+			Size:  usage.Size,
+		}
+	}
 	results := append(
-		filterPools(pools, matches),
+		matchingPools,
 		filterProviders(providers, matches)...,
 	)
 	return results, nil
@@ -280,6 +291,13 @@ func filterProviders(
 	all := make([]params.StoragePool, 0, len(providers))
 	for _, p := range providers {
 		ps := string(p)
+		if provider, err := registry.StorageProvider(p); err == nil {
+			if storage.RequiresConfiguredPool(provider) {
+				// The provider type cannot be used as a pool
+				// by itself, so don't list it as one.
+				continue
+			}
+		}
 		if matches(ps, ps) {
 			all = append(all, params.StoragePool{Name: ps, Provider: ps})
 		}
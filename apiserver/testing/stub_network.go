@@ -51,7 +51,7 @@ func (s StubNetwork) SetUpSuite(c *gc.C) {
 		}
 	}
 
-	ProviderInstance.Zones = []providercommon.AvailabilityZone{
+	ProviderInstance.Zones = []instance.AvailabilityZone{
 		&FakeZone{"zone1", true},
 		&FakeZone{"zone2", false},
 		&FakeZone{"zone3", true},
@@ -264,13 +264,13 @@ func CheckMethodCalls(c *gc.C, stub *testing.Stub, calls ...StubMethodCall) {
 	}
 }
 
-// FakeZone implements providercommon.AvailabilityZone for testing.
+// FakeZone implements instance.AvailabilityZone for testing.
 type FakeZone struct {
 	ZoneName      string
 	ZoneAvailable bool
 }
 
-var _ providercommon.AvailabilityZone = (*FakeZone)(nil)
+var _ instance.AvailabilityZone = (*FakeZone)(nil)
 
 func (f *FakeZone) Name() string {
 	return f.ZoneName
@@ -337,7 +337,7 @@ type StubBacking struct {
 
 	EnvConfig *config.Config
 
-	Zones   []providercommon.AvailabilityZone
+	Zones   []instance.AvailabilityZone
 	Spaces  []networkingcommon.BackingSpace
 	Subnets []networkingcommon.BackingSubnet
 }
@@ -368,9 +368,9 @@ func (sb *StubBacking) SetUp(c *gc.C, envName string, withZones, withSpaces, wit
 		"name": envName,
 	}
 	sb.EnvConfig = coretesting.CustomModelConfig(c, extraAttrs)
-	sb.Zones = []providercommon.AvailabilityZone{}
+	sb.Zones = []instance.AvailabilityZone{}
 	if withZones {
-		sb.Zones = make([]providercommon.AvailabilityZone, len(ProviderInstance.Zones))
+		sb.Zones = make([]instance.AvailabilityZone, len(ProviderInstance.Zones))
 		copy(sb.Zones, ProviderInstance.Zones)
 	}
 	sb.Spaces = []networkingcommon.BackingSpace{}
@@ -428,7 +428,7 @@ func (sb *StubBacking) ModelConfig() (*config.Config, error) {
 	return sb.EnvConfig, nil
 }
 
-func (sb *StubBacking) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+func (sb *StubBacking) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	sb.MethodCall(sb, "AvailabilityZones")
 	if err := sb.NextErr(); err != nil {
 		return nil, err
@@ -436,7 +436,7 @@ func (sb *StubBacking) AvailabilityZones() ([]providercommon.AvailabilityZone, e
 	return sb.Zones, nil
 }
 
-func (sb *StubBacking) SetAvailabilityZones(zones []providercommon.AvailabilityZone) error {
+func (sb *StubBacking) SetAvailabilityZones(zones []instance.AvailabilityZone) error {
 	sb.MethodCall(sb, "SetAvailabilityZones", zones)
 	return sb.NextErr()
 }
@@ -509,7 +509,7 @@ func (se *StubBacking) GoString() string {
 type StubProvider struct {
 	*testing.Stub
 
-	Zones   []providercommon.AvailabilityZone
+	Zones   []instance.AvailabilityZone
 	Subnets []network.SubnetInfo
 
 	environs.EnvironProvider // panic on any not implemented method call.
@@ -564,7 +564,7 @@ type StubZonedEnviron struct {
 
 var _ providercommon.ZonedEnviron = (*StubZonedEnviron)(nil)
 
-func (se *StubZonedEnviron) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+func (se *StubZonedEnviron) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	se.MethodCall(se, "AvailabilityZones")
 	if err := se.NextErr(); err != nil {
 		return nil, err
@@ -640,7 +640,7 @@ func (se *StubZonedNetworkingEnviron) Subnets(instId instance.Id, subIds []netwo
 	return ProviderInstance.Subnets, nil
 }
 
-func (se *StubZonedNetworkingEnviron) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+func (se *StubZonedNetworkingEnviron) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	se.MethodCall(se, "AvailabilityZones")
 	if err := se.NextErr(); err != nil {
 		return nil, err
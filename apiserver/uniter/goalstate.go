@@ -0,0 +1,222 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// GoalState returns, for each given unit, the units expected for its
+// service and for each service related to it, with their status.
+func (u *UniterAPIV4) GoalState(args params.Entities) (params.GoalStateResults, error) {
+	result := params.GoalStateResults{
+		Results: make([]params.GoalStateResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.GoalStateResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				result.Results[i].Result, err = u.oneGoalState(unit)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV4) oneGoalState(unit *state.Unit) (*params.GoalState, error) {
+	service, err := unit.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := service.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relations, err := service.Relations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Read the units of each related service, and of each relation's
+	// scopes, once; the statuses of all the units are then read at once.
+	serviceUnits := map[string][]*state.Unit{service.Name(): units}
+	remoteServices := make(set.Strings)
+	inScope := make(map[int]set.Strings)
+	allUnitNames := unitNames(units)
+	for _, rel := range relations {
+		relatedEps, err := rel.RelatedEndpoints(service.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, relatedEp := range relatedEps {
+			serviceName := relatedEp.ServiceName
+			if _, ok := serviceUnits[serviceName]; ok || remoteServices.Contains(serviceName) {
+				continue
+			}
+			related, err := u.st.Service(serviceName)
+			if errors.IsNotFound(err) {
+				// A remote service's units are only known once
+				// they have joined the relation.
+				remoteServices.Add(serviceName)
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			relatedUnits, err := related.AllUnits()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			serviceUnits[serviceName] = relatedUnits
+			allUnitNames = append(allUnitNames, unitNames(relatedUnits)...)
+		}
+		relInScope, err := rel.AllUnitsInScope()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		inScope[rel.Id()] = set.NewStrings(relInScope...)
+	}
+	agentStatuses, err := u.st.UnitAgentStatuses(allUnitNames)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	goalState := &params.GoalState{
+		Units:     make(params.UnitsGoalState),
+		Relations: make(map[string]params.UnitsGoalState),
+	}
+	for _, other := range units {
+		status := unitGoalStateStatus(other, agentStatuses)
+		goalState.Units[other.Name()] = params.GoalStateStatus{Status: status}
+	}
+	for _, rel := range relations {
+		ep, err := rel.Endpoint(service.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		relatedEps, err := rel.RelatedEndpoints(service.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		relUnits, ok := goalState.Relations[ep.Name]
+		if !ok {
+			relUnits = make(params.UnitsGoalState)
+			goalState.Relations[ep.Name] = relUnits
+		}
+		relInScope := inScope[rel.Id()]
+		for _, relatedEp := range relatedEps {
+			serviceName := relatedEp.ServiceName
+			if remoteServices.Contains(serviceName) {
+				addRemoteGoalState(relUnits, rel, ep, serviceName, relInScope)
+				continue
+			}
+			for _, other := range serviceUnits[serviceName] {
+				if other.Name() == unit.Name() {
+					continue
+				}
+				if ep.Scope == charm.ScopeContainer && containerName(other) != containerName(unit) {
+					continue
+				}
+				status := relatedUnitGoalStateStatus(rel, other, agentStatuses, relInScope)
+				relUnits[other.Name()] = params.GoalStateStatus{Status: status}
+			}
+		}
+	}
+	return goalState, nil
+}
+
+// addRemoteGoalState adds the units of the named remote service that
+// are in the relation's scope to relUnits.
+func addRemoteGoalState(
+	relUnits params.UnitsGoalState,
+	rel *state.Relation,
+	ep state.Endpoint,
+	serviceName string,
+	inScope set.Strings,
+) {
+	if ep.Scope != charm.ScopeGlobal {
+		return
+	}
+	for _, unitName := range inScope.Values() {
+		if unitService, err := names.UnitService(unitName); err != nil || unitService != serviceName {
+			continue
+		}
+		status := params.GoalStateActive
+		if rel.Life() != state.Alive {
+			status = params.GoalStateDying
+		}
+		relUnits[unitName] = params.GoalStateStatus{Status: status}
+	}
+}
+
+// unitGoalStateStatus returns the goal state status of the given unit
+// of the service, given the statuses of the units' agents.
+func unitGoalStateStatus(unit *state.Unit, agentStatuses map[string]state.Status) string {
+	if unit.Life() != state.Alive {
+		return params.GoalStateDying
+	}
+	agentStatus, ok := agentStatuses[unit.Name()]
+	if !ok || agentStatus == state.StatusAllocating {
+		return params.GoalStateWaiting
+	}
+	return params.GoalStateActive
+}
+
+// relatedUnitGoalStateStatus returns the goal state status of the given
+// unit of a service related by rel, given the statuses of the units'
+// agents and the names of the units in the relation's scope.
+func relatedUnitGoalStateStatus(
+	rel *state.Relation,
+	unit *state.Unit,
+	agentStatuses map[string]state.Status,
+	inScope set.Strings,
+) string {
+	if rel.Life() != state.Alive {
+		return params.GoalStateDying
+	}
+	status := unitGoalStateStatus(unit, agentStatuses)
+	if status != params.GoalStateActive {
+		return status
+	}
+	if !inScope.Contains(unit.Name()) {
+		return params.GoalStateJoining
+	}
+	return params.GoalStateActive
+}
+
+// unitNames returns the names of the given units.
+func unitNames(units []*state.Unit) []string {
+	result := make([]string, len(units))
+	for i, unit := range units {
+		result[i] = unit.Name()
+	}
+	return result
+}
+
+// containerName returns the name of the principal unit whose container
+// the given unit is deployed in.
+func containerName(unit *state.Unit) string {
+	if principal, ok := unit.PrincipalName(); ok {
+		return principal
+	}
+	return unit.Name()
+}
@@ -0,0 +1,70 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	jujuFactory "github.com/juju/juju/testing/factory"
+)
+
+func (s *uniterSuite) TestGoalState(c *gc.C) {
+	wordpressUnit1 := s.Factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.wordpress,
+	})
+	err := wordpressUnit1.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysqlUnit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	mysqlUnit1 := s.Factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.mysql,
+	})
+	rel := s.addRelation(c, "wordpress", "mysql")
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+		{Tag: "service-wordpress"},
+	}}
+	expectGoalState := func(mysql0Status string) {
+		result, err := s.uniter.GoalState(args)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(result, jc.DeepEquals, params.GoalStateResults{
+			Results: []params.GoalStateResult{
+				{Error: apiservertesting.ErrUnauthorized},
+				{Result: &params.GoalState{
+					Units: params.UnitsGoalState{
+						"wordpress/0":         {Status: params.GoalStateWaiting},
+						wordpressUnit1.Name(): {Status: params.GoalStateActive},
+					},
+					Relations: map[string]params.UnitsGoalState{
+						"db": {
+							"mysql/0":         {Status: mysql0Status},
+							mysqlUnit1.Name(): {Status: params.GoalStateWaiting},
+						},
+					},
+				}},
+				{Error: apiservertesting.ErrUnauthorized},
+				{Error: apiservertesting.ErrUnauthorized},
+			},
+		})
+	}
+
+	expectGoalState(params.GoalStateJoining)
+
+	relUnit, err := rel.Unit(s.mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	expectGoalState(params.GoalStateActive)
+
+	err = s.mysqlUnit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	expectGoalState(params.GoalStateDying)
+}
@@ -0,0 +1,222 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// CreateSecrets creates secrets owned by the services of the given
+// units, returning the ID of each new secret.
+func (u *UniterAPIV4) CreateSecrets(args params.CreateSecretArgs) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	for i, arg := range args.Args {
+		unit, err := u.authSecretUnit(canAccess, arg.UnitTag)
+		if err == nil {
+			var secret *state.Secret
+			secret, err = u.st.AddSecret(state.SecretParams{
+				Owner:          names.NewServiceTag(unit.ServiceName()),
+				Description:    arg.Description,
+				Data:           arg.Data,
+				RotateInterval: arg.RotateInterval,
+			})
+			if err == nil {
+				result.Results[i].Result = secret.ID()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SecretValues returns the content of the given secrets, if the
+// accessing units have been granted access to them. The content of
+// the current revision is returned unless another is requested; only
+// the revisions kept by the secret can be read.
+func (u *UniterAPIV4) SecretValues(args params.SecretArgs) (params.SecretValueResults, error) {
+	result := params.SecretValueResults{
+		Results: make([]params.SecretValueResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SecretValueResults{}, err
+	}
+	for i, arg := range args.Args {
+		secret, err := u.readableSecret(canAccess, arg.UnitTag, arg.ID)
+		if err == nil {
+			revision := arg.Revision
+			if revision == 0 {
+				revision = secret.Revision()
+			}
+			result.Results[i].Revision = revision
+			result.Results[i].Data, err = secret.RevisionData(revision)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// UpdateSecrets replaces the content of the given secrets, which must
+// be owned by the services of the accessing units.
+func (u *UniterAPIV4) UpdateSecrets(args params.UpdateSecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		secret, err := u.ownedSecret(canAccess, arg.UnitTag, arg.ID)
+		if err == nil {
+			err = secret.Update(arg.Data)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// GrantSecrets gives units, or the units of relations, access to the
+// given secrets, which must be owned by the services of the accessing
+// units. A relation may only be granted access if the owning service
+// takes part in it.
+func (u *UniterAPIV4) GrantSecrets(args params.GrantSecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		secret, err := u.ownedSecret(canAccess, arg.UnitTag, arg.ID)
+		if err == nil {
+			err = u.grantSecret(secret, arg.GranteeTag)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV4) grantSecret(secret *state.Secret, granteeTag string) error {
+	grantee, err := names.ParseTag(granteeTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tag, ok := grantee.(names.RelationTag); ok {
+		rel, err := u.st.KeyRelation(tag.Id())
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := rel.Endpoint(secret.Owner().Id()); err != nil {
+			return common.ErrPerm
+		}
+	}
+	return secret.Grant(grantee)
+}
+
+// SecretsToRotate returns, for each given unit, the IDs of the secrets
+// owned by its service that are due to be rotated.
+func (u *UniterAPIV4) SecretsToRotate(args params.Entities) (params.StringsResults, error) {
+	result := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		unit, err := u.authSecretUnit(canAccess, entity.Tag)
+		if err == nil {
+			var secrets []*state.Secret
+			secrets, err = u.st.SecretsToRotate(names.NewServiceTag(unit.ServiceName()))
+			for _, secret := range secrets {
+				result.Results[i].Result = append(result.Results[i].Result, secret.ID())
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SecretsRotated records that the given secrets, owned by the services
+// of the accessing units, have been rotated.
+func (u *UniterAPIV4) SecretsRotated(args params.SecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		secret, err := u.ownedSecret(canAccess, arg.UnitTag, arg.ID)
+		if err == nil {
+			err = secret.Rotated()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// authSecretUnit returns the unit with the given tag, if it may be
+// accessed.
+func (u *UniterAPIV4) authSecretUnit(canAccess common.AuthFunc, unitTag string) (*state.Unit, error) {
+	tag, err := names.ParseUnitTag(unitTag)
+	if err != nil || !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return u.getUnit(tag)
+}
+
+// readableSecret returns the secret with the given ID, if the unit with
+// the given tag may read it.
+func (u *UniterAPIV4) readableSecret(canAccess common.AuthFunc, unitTag, id string) (*state.Secret, error) {
+	unit, err := u.authSecretUnit(canAccess, unitTag)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := u.st.Secret(id)
+	if err != nil {
+		return nil, err
+	}
+	canRead, err := secret.CanRead(unit.UnitTag())
+	if err != nil {
+		return nil, err
+	}
+	if !canRead {
+		return nil, common.ErrPerm
+	}
+	return secret, nil
+}
+
+// ownedSecret returns the secret with the given ID, if it is owned by
+// the service of the unit with the given tag.
+func (u *UniterAPIV4) ownedSecret(canAccess common.AuthFunc, unitTag, id string) (*state.Secret, error) {
+	unit, err := u.authSecretUnit(canAccess, unitTag)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := u.st.Secret(id)
+	if err != nil {
+		return nil, err
+	}
+	if secret.Owner().Id() != unit.ServiceName() {
+		return nil, common.ErrPerm
+	}
+	return secret, nil
+}
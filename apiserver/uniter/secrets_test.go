@@ -0,0 +1,176 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func (s *uniterSuite) createSecret(c *gc.C, rotateInterval time.Duration) string {
+	result, err := s.uniter.CreateSecrets(params.CreateSecretArgs{
+		Args: []params.CreateSecretArg{{
+			UnitTag:        "unit-wordpress-0",
+			Description:    "db password",
+			Data:           map[string]string{"password": "sekrit"},
+			RotateInterval: rotateInterval,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	return result.Results[0].Result
+}
+
+func (s *uniterSuite) TestCreateSecrets(c *gc.C) {
+	result, err := s.uniter.CreateSecrets(params.CreateSecretArgs{
+		Args: []params.CreateSecretArg{
+			{UnitTag: "unit-wordpress-0", Data: map[string]string{"password": "sekrit"}},
+			{UnitTag: "unit-wordpress-0"},
+			{UnitTag: "unit-mysql-0", Data: map[string]string{"password": "sekrit"}},
+			{UnitTag: "service-wordpress", Data: map[string]string{"password": "sekrit"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "cannot add secret: empty secret not valid")
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[3].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	secret, err := s.State.Secret(result.Results[0].Result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Owner(), gc.Equals, s.wordpress.ServiceTag())
+}
+
+func (s *uniterSuite) TestSecretValuesAndUpdate(c *gc.C) {
+	id := s.createSecret(c, 0)
+	args := params.SecretArgs{Args: []params.SecretArg{
+		{UnitTag: "unit-wordpress-0", ID: id},
+		{UnitTag: "unit-wordpress-0", ID: "666"},
+		{UnitTag: "unit-mysql-0", ID: id},
+	}}
+	result, err := s.uniter.SecretValues(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SecretValueResults{
+		Results: []params.SecretValueResult{
+			{Revision: 1, Data: map[string]string{"password": "sekrit"}},
+			{Error: apiservertesting.NotFoundError(`secret "666"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	updateResult, err := s.uniter.UpdateSecrets(params.UpdateSecretArgs{
+		Args: []params.UpdateSecretArg{
+			{UnitTag: "unit-wordpress-0", ID: id, Data: map[string]string{"password": "geheim"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(updateResult.OneError(), jc.ErrorIsNil)
+
+	result, err = s.uniter.SecretValues(params.SecretArgs{Args: args.Args[:1]})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SecretValueResults{
+		Results: []params.SecretValueResult{
+			{Revision: 2, Data: map[string]string{"password": "geheim"}},
+		},
+	})
+
+	result, err = s.uniter.SecretValues(params.SecretArgs{Args: []params.SecretArg{
+		{UnitTag: "unit-wordpress-0", ID: id, Revision: 1},
+		{UnitTag: "unit-wordpress-0", ID: id, Revision: 3},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SecretValueResults{
+		Results: []params.SecretValueResult{
+			{Revision: 1, Data: map[string]string{"password": "sekrit"}},
+			{Error: apiservertesting.NotFoundError(fmt.Sprintf("revision 3 of secret %q", id))},
+		},
+	})
+}
+
+func (s *uniterSuite) TestGrantSecrets(c *gc.C) {
+	id := s.createSecret(c, 0)
+	rel := s.addRelation(c, "wordpress", "mysql")
+	mysqlUniter, err := uniter.NewUniterAPIV4(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		Tag: s.mysqlUnit.Tag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	readArgs := params.SecretArgs{Args: []params.SecretArg{
+		{UnitTag: "unit-mysql-0", ID: id},
+	}}
+	result, err := mysqlUniter.SecretValues(readArgs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	grantResult, err := s.uniter.GrantSecrets(params.GrantSecretArgs{
+		Args: []params.GrantSecretArg{
+			{UnitTag: "unit-wordpress-0", ID: id, GranteeTag: rel.Tag().String()},
+			{UnitTag: "unit-wordpress-0", ID: id, GranteeTag: "relation-foo.db#bar.server"},
+			{UnitTag: "unit-wordpress-0", ID: id, GranteeTag: "service-mysql"},
+			{UnitTag: "unit-mysql-0", ID: id, GranteeTag: "unit-mysql-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(grantResult.Results, gc.HasLen, 4)
+	c.Assert(grantResult.Results[0].Error, gc.IsNil)
+	c.Assert(grantResult.Results[1].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(grantResult.Results[2].Error, gc.ErrorMatches, `grant to "service-mysql" not valid`)
+	c.Assert(grantResult.Results[3].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	result, err = mysqlUniter.SecretValues(readArgs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SecretValueResults{
+		Results: []params.SecretValueResult{
+			{Revision: 1, Data: map[string]string{"password": "sekrit"}},
+		},
+	})
+}
+
+func (s *uniterSuite) TestSecretsToRotate(c *gc.C) {
+	testClock := coretesting.NewClock(time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC))
+	s.PatchValue(&state.GetClock, func() clock.Clock {
+		return testClock
+	})
+	id := s.createSecret(c, time.Hour)
+	s.createSecret(c, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-mysql-0"},
+	}}
+	result, err := s.uniter.SecretsToRotate(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	testClock.Advance(time.Hour)
+	result, err = s.uniter.SecretsToRotate(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0], jc.DeepEquals, params.StringsResult{Result: []string{id}})
+
+	rotatedResult, err := s.uniter.SecretsRotated(params.SecretArgs{Args: []params.SecretArg{
+		{UnitTag: "unit-wordpress-0", ID: id},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotatedResult.OneError(), jc.ErrorIsNil)
+
+	result, err = s.uniter.SecretsToRotate(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0], jc.DeepEquals, params.StringsResult{})
+}
@@ -7,6 +7,7 @@ package uniter
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"path"
 
@@ -30,6 +31,28 @@ var logger = loggo.GetLogger("juju.apiserver.uniter")
 
 func init() {
 	common.RegisterStandardFacade("Uniter", 3, NewUniterAPIV3)
+
+	// Version 4 adds RecordHookExecutions.
+	common.RegisterStandardFacade("Uniter", 4, NewUniterAPIV4)
+
+	// Version 5 adds GoalState.
+	common.RegisterStandardFacade("Uniter", 5, NewUniterAPIV4)
+
+	// Version 6 adds CreateSecrets, SecretValues, UpdateSecrets,
+	// GrantSecrets, SecretsToRotate and SecretsRotated.
+	common.RegisterStandardFacade("Uniter", 6, NewUniterAPIV4)
+
+	// Version 7 adds SetWorkloadVersion.
+	common.RegisterStandardFacade("Uniter", 7, NewUniterAPIV4)
+
+	// Version 8 adds TriggeredHook and ClearTriggeredHook.
+	common.RegisterStandardFacade("Uniter", 8, NewUniterAPIV4)
+
+	// Version 9 adds AgentState and SetAgentState.
+	common.RegisterStandardFacade("Uniter", 9, NewUniterAPIV4)
+
+	// Version 10 adds reading previous revisions with SecretValues.
+	common.RegisterStandardFacade("Uniter", 10, NewUniterAPIV4)
 }
 
 // UniterAPIV3 implements the API version 3, used by the uniter worker.
@@ -141,6 +164,22 @@ func NewUniterAPIV3(st *state.State, resources *common.Resources, authorizer com
 	}, nil
 }
 
+// UniterAPIV4 implements the API version 4 and later, used by the
+// uniter worker; the methods each version adds are listed where the
+// versions are registered.
+type UniterAPIV4 struct {
+	*UniterAPIV3
+}
+
+// NewUniterAPIV4 creates a new instance of the Uniter API, version 4.
+func NewUniterAPIV4(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV4, error) {
+	api, err := NewUniterAPIV3(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV4{api}, nil
+}
+
 // AllMachinePorts returns all opened port ranges for each given
 // machine (on all networks).
 func (u *UniterAPIV3) AllMachinePorts(args params.Entities) (params.MachinePortsResults, error) {
@@ -625,6 +664,210 @@ func (u *UniterAPIV3) SetCharmURL(args params.EntitiesCharmURL) (params.ErrorRes
 	return result, nil
 }
 
+// SetWorkloadVersion sets the workload version reported by each of
+// the given units.
+func (u *UniterAPIV4) SetWorkloadVersion(args params.EntityWorkloadVersions) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.SetWorkloadVersion(entity.WorkloadVersion)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// TriggeredHook returns the hook each given unit has been asked to run
+// for a debug-code session, if any.
+func (u *UniterAPIV4) TriggeredHook(args params.Entities) (params.TriggeredHookResults, error) {
+	result := params.TriggeredHookResults{
+		Results: make([]params.TriggeredHookResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.TriggeredHookResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				if hook, ok := unit.TriggeredHook(); ok {
+					result.Results[i].Hook = &params.TriggeredHook{
+						Name:       hook.Name,
+						RelationId: hook.RelationId,
+						RemoteUnit: hook.RemoteUnit,
+					}
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ClearTriggeredHook removes any triggered hook from each given unit.
+func (u *UniterAPIV4) ClearTriggeredHook(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.ClearTriggeredHook()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// AgentState returns the state last saved by the agent of each of the
+// given units.
+func (u *UniterAPIV4) AgentState(args params.Entities) (params.UnitAgentStateResults, error) {
+	result := params.UnitAgentStateResults{
+		Results: make([]params.UnitAgentStateResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.UnitAgentStateResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				result.Results[i].Files, err = unit.AgentState()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetAgentState replaces the state saved by the agent of each of the
+// given units.
+func (u *UniterAPIV4) SetAgentState(args params.UnitAgentStates) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.States)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.States {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.SetAgentState(arg.Files)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RecordHookExecutions adds the given executions to the hook histories
+// of their units.
+func (u *UniterAPIV4) RecordHookExecutions(args params.UnitHookExecutions) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Executions)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Executions {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = u.recordOneHookExecution(unit, arg.Execution)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV4) recordOneHookExecution(unit *state.Unit, arg params.HookExecution) error {
+	var relationKey string
+	if arg.RelationId >= 0 {
+		// The relation may already have been removed, in which
+		// case the execution is recorded without it.
+		rel, err := u.st.Relation(arg.RelationId)
+		if err == nil {
+			relationKey = rel.String()
+		} else if !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	return unit.RecordHookExecution(state.HookExecution{
+		Kind:       arg.Kind,
+		Name:       arg.Name,
+		Relation:   relationKey,
+		RemoteUnit: arg.RemoteUnit,
+		Started:    arg.Started,
+		Finished:   arg.Finished,
+		Result:     arg.Result,
+		ExitCode:   arg.ExitCode,
+		StderrTail: arg.StderrTail,
+	})
+}
+
 // OpenPorts sets the policy of the port range with protocol to be
 // opened, for all given units.
 func (u *UniterAPIV3) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResults, error) {
@@ -1653,8 +1896,12 @@ func (u *UniterAPIV3) AddMetricBatches(args params.MetricBatchParams) (params.Er
 	return result, nil
 }
 
-// NetworkConfig returns information about all given relation/unit pairs,
-// including their id, key and the local endpoint.
+// NetworkConfig returns the network configuration of each given unit
+// for the given relation. For an endpoint bound to a space, every
+// address of the unit's machine in that space is returned, along with
+// the CIDR of its subnet and the interface it is on, where known. For
+// an unbound endpoint, the machine's preferred private address is
+// returned.
 func (u *UniterAPIV3) NetworkConfig(args params.RelationUnits) (params.UnitNetworkConfigResults, error) {
 	result := params.UnitNetworkConfigResults{
 		Results: make([]params.UnitNetworkConfigResult, len(args.RelationUnits)),
@@ -1746,30 +1993,85 @@ func (u *UniterAPIV3) getOneNetworkConfig(canAccess common.AuthFunc, tagRel, tag
 	}
 	logger.Debugf("endpoint %q is explicitly bound to space %q", endpoint.Name, boundSpace)
 
-	// TODO(dimitern): Use NetworkInterfaces() instead later, this is just for
-	// the PoC to enable minimal network-get implementation returning just the
-	// primary address.
-	//
-	// LKK Card: https://canonical.leankit.com/Boards/View/101652562/119258804
 	addresses := machine.ProviderAddresses()
 	logger.Infof(
-		"geting network config for machine %q with addresses %+v, hosting unit %q of service %q, with bindings %+v",
+		"getting network config for machine %q with addresses %+v, hosting unit %q of service %q, with bindings %+v",
 		machineID, addresses, unit.Name(), service.Name(), bindings,
 	)
+	subnets, err := spaceSubnets(u.st, boundSpace)
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting subnets of space %q", boundSpace)
+	}
+	allocated, err := u.st.AllocatedIPAddresses(machineID)
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting allocated addresses of machine %q", machineID)
+	}
+	interfaceNames := make(map[string]string)
+	for _, addr := range allocated {
+		interfaceNames[addr.Value()] = addr.InterfaceId()
+		addresses = append(addresses, addr.Address())
+	}
 
+	seen := make(map[string]bool)
 	for _, addr := range addresses {
+		if seen[addr.Value] {
+			continue
+		}
+		cidr := subnets.cidrOf(addr.Value)
 		space := string(addr.SpaceName)
-		if space != boundSpace {
+		if space != boundSpace && cidr == "" {
 			logger.Debugf("skipping address %q: want bound to space %q, got space %q", addr.Value, boundSpace, space)
 			continue
 		}
+		seen[addr.Value] = true
 		logger.Debugf("endpoint %q bound to space %q has address %q", endpoint.Name, boundSpace, addr.Value)
 
-		// TODO(dimitern): Fill in the rest later (see linked LKK card above).
 		results = append(results, params.NetworkConfig{
-			Address: addr.Value,
+			Address:       addr.Value,
+			CIDR:          cidr,
+			InterfaceName: interfaceNames[addr.Value],
 		})
 	}
 
 	return results, nil
 }
+
+// subnetCIDRs holds the parsed CIDRs of a set of subnets.
+type subnetCIDRs []*net.IPNet
+
+// spaceSubnets returns the CIDRs of the subnets in the named space.
+func spaceSubnets(st *state.State, spaceName string) (subnetCIDRs, error) {
+	space, err := st.Space(spaceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result subnetCIDRs
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet.CIDR())
+		if err != nil {
+			logger.Warningf("ignoring subnet with invalid CIDR %q", subnet.CIDR())
+			continue
+		}
+		result = append(result, ipNet)
+	}
+	return result, nil
+}
+
+// cidrOf returns the CIDR of the subnet containing the given address,
+// or "" if there is none.
+func (s subnetCIDRs) cidrOf(value string) string {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return ""
+	}
+	for _, ipNet := range s {
+		if ipNet.Contains(ip) {
+			return ipNet.String()
+		}
+	}
+	return ""
+}
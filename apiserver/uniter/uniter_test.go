@@ -37,7 +37,7 @@ type uniterSuite struct {
 
 	authorizer apiservertesting.FakeAuthorizer
 	resources  *common.Resources
-	uniter     *uniter.UniterAPIV3
+	uniter     *uniter.UniterAPIV4
 
 	machine0      *state.Machine
 	machine1      *state.Machine
@@ -116,13 +116,13 @@ func (s *uniterSuite) SetUpTest(c *gc.C) {
 	s.resources = common.NewResources()
 	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
 
-	uniterAPIV3, err := uniter.NewUniterAPIV3(
+	uniterAPIV4, err := uniter.NewUniterAPIV4(
 		s.State,
 		s.resources,
 		s.authorizer,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.uniter = uniterAPIV3
+	s.uniter = uniterAPIV4
 }
 
 func (s *uniterSuite) TestUniterFailsWithNonUnitAgentUser(c *gc.C) {
@@ -747,6 +747,150 @@ func (s *uniterSuite) TestSetCharmURL(c *gc.C) {
 	c.Assert(needsUpgrade, jc.IsTrue)
 }
 
+func (s *uniterSuite) TestSetWorkloadVersion(c *gc.C) {
+	args := params.EntityWorkloadVersions{Entities: []params.EntityWorkloadVersion{
+		{Tag: "unit-mysql-0", WorkloadVersion: "5.7"},
+		{Tag: "unit-wordpress-0", WorkloadVersion: "4.5.1"},
+		{Tag: "unit-foo-42", WorkloadVersion: "1.0"},
+	}}
+	result, err := s.uniter.SetWorkloadVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.wordpressUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpressUnit.WorkloadVersion(), gc.Equals, "4.5.1")
+}
+
+func (s *uniterSuite) TestTriggeredHook(c *gc.C) {
+	err := s.wordpressUnit.TriggerHook(state.TriggeredHook{Name: "config-changed", RelationId: -1})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.TriggeredHook(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.TriggeredHookResults{
+		Results: []params.TriggeredHookResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Hook: &params.TriggeredHook{Name: "config-changed", RelationId: -1}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	clearResult, err := s.uniter.ClearTriggeredHook(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(clearResult, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	result, err = s.uniter.TriggeredHook(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[1], jc.DeepEquals, params.TriggeredHookResult{})
+}
+
+func (s *uniterSuite) TestAgentState(c *gc.C) {
+	err := s.wordpressUnit.SetAgentState(map[string]string{"uniter": "op: continue\n"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.AgentState(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.UnitAgentStateResults{
+		Results: []params.UnitAgentStateResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Files: map[string]string{"uniter": "op: continue\n"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestSetAgentState(c *gc.C) {
+	files := map[string]string{
+		"uniter":              "op: continue\n",
+		"relations/0/mysql-0": "change-version: 1\n",
+	}
+	args := params.UnitAgentStates{States: []params.UnitAgentState{
+		{Tag: "unit-mysql-0", Files: files},
+		{Tag: "unit-wordpress-0", Files: files},
+		{Tag: "unit-foo-42", Files: files},
+	}}
+	result, err := s.uniter.SetAgentState(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	saved, err := s.wordpressUnit.AgentState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(saved, jc.DeepEquals, files)
+}
+
+func (s *uniterSuite) TestRecordHookExecutions(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	started := time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC)
+	execution := params.HookExecution{
+		Kind:       "hook",
+		Name:       "db-relation-changed",
+		RelationId: rel.Id(),
+		RemoteUnit: "mysql/0",
+		Started:    started,
+		Finished:   started.Add(time.Second),
+		Result:     "failed",
+		ExitCode:   1,
+		StderrTail: "boom",
+	}
+	args := params.UnitHookExecutions{Executions: []params.UnitHookExecution{
+		{Tag: "unit-mysql-0", Execution: execution},
+		{Tag: "unit-wordpress-0", Execution: execution},
+		{Tag: "unit-foo-42", Execution: execution},
+	}}
+	result, err := s.uniter.RecordHookExecutions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	history, err := s.wordpressUnit.HookHistory(state.HookHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, jc.DeepEquals, []state.HookExecution{{
+		Kind:       "hook",
+		Name:       "db-relation-changed",
+		Relation:   rel.String(),
+		RemoteUnit: "mysql/0",
+		Started:    started,
+		Finished:   started.Add(time.Second),
+		Result:     "failed",
+		ExitCode:   1,
+		StderrTail: "boom",
+	}})
+}
+
 func (s *uniterSuite) TestOpenPorts(c *gc.C) {
 	openedPorts, err := s.wordpressUnit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
@@ -2437,7 +2581,7 @@ func (s *uniterNetworkConfigSuite) setupUniterAPIForUnit(c *gc.C, givenUnit *sta
 	}
 
 	var err error
-	s.base.uniter, err = uniter.NewUniterAPIV3(
+	s.base.uniter, err = uniter.NewUniterAPIV4(
 		s.base.State,
 		s.base.resources,
 		s.base.authorizer,
@@ -2504,6 +2648,51 @@ func (s *uniterNetworkConfigSuite) TestNetworkConfigForExplicitlyBoundEndpoint(c
 	})
 }
 
+func (s *uniterNetworkConfigSuite) TestNetworkConfigForBoundEndpointWithSubnets(c *gc.C) {
+	_, err := s.base.State.AddSubnet(state.SubnetInfo{
+		CIDR:      "10.0.0.0/24",
+		SpaceName: "internal",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.base.State.AddSubnet(state.SubnetInfo{
+		CIDR:      "10.0.1.0/24",
+		SpaceName: "internal",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	ipAddr, err := s.base.State.AddIPAddress(network.NewAddress("10.0.1.5"), "10.0.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ipAddr.AllocateTo(s.base.machine0.Id(), "eth1", "aa:bb:cc:dd:ee:f0")
+	c.Assert(err, jc.ErrorIsNil)
+	rel := s.addRelationAndAssertInScope(c)
+
+	args := params.RelationUnits{RelationUnits: []params.RelationUnit{
+		{Relation: rel.Tag().String(), Unit: s.base.wordpressUnit.Tag().String()},
+	}}
+
+	// All addresses of the machine in the "internal" space are
+	// returned, along with the subnets they are on and, for addresses
+	// allocated by Juju, the interfaces they are assigned to.
+	expectedConfig := []params.NetworkConfig{{
+		Address: "10.0.0.1",
+		CIDR:    "10.0.0.0/24",
+	}, {
+		Address: "10.0.0.2",
+		CIDR:    "10.0.0.0/24",
+	}, {
+		Address:       "10.0.1.5",
+		CIDR:          "10.0.1.0/24",
+		InterfaceName: "eth1",
+	}}
+
+	result, err := s.base.uniter.NetworkConfig(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.UnitNetworkConfigResults{
+		Results: []params.UnitNetworkConfigResult{
+			{Config: expectedConfig},
+		},
+	})
+}
+
 func (s *uniterNetworkConfigSuite) TestNetworkConfigForImplicitlyBoundEndpoint(c *gc.C) {
 	// Since wordpressUnit as explicit binding for "db", switch the API to
 	// mysqlUnit and check "mysql:server" uses the machine preferred private
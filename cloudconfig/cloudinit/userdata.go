@@ -0,0 +1,176 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudinit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// preRunCmdKey is the user data key listing commands to be run before
+// the Juju agent is installed. It is a Juju extension with no cloud-init
+// equivalent, and is prefixed to distinguish it from cloud-init's keys.
+const preRunCmdKey = "juju-preruncmd"
+
+// defaultUserDataFilePerm is the permission given to files written by
+// operator-supplied user data that do not specify any.
+const defaultUserDataFilePerm = 0644
+
+// UserData holds cloud-init directives supplied by an operator, to be
+// merged with those Juju generates when it starts an instance.
+type UserData struct {
+	// Packages holds the names of additional packages to install.
+	Packages []string
+
+	// WriteFiles holds files to be written before the Juju agent is
+	// installed.
+	WriteFiles []UserDataFile
+
+	// PreRunCmds holds commands to be run before the Juju agent is
+	// installed.
+	PreRunCmds []string
+
+	// RunCmds holds commands to be run once the Juju agent has been
+	// installed.
+	RunCmds []string
+
+	// PackageMirror, if not empty, overrides the mirror used by the
+	// instance's package manager.
+	PackageMirror string
+}
+
+// Merge appends the directives of other to those of u. Any package
+// mirror specified by other takes precedence.
+func (u *UserData) Merge(other *UserData) {
+	u.Packages = append(u.Packages, other.Packages...)
+	u.WriteFiles = append(u.WriteFiles, other.WriteFiles...)
+	u.PreRunCmds = append(u.PreRunCmds, other.PreRunCmds...)
+	u.RunCmds = append(u.RunCmds, other.RunCmds...)
+	if other.PackageMirror != "" {
+		u.PackageMirror = other.PackageMirror
+	}
+}
+
+// UserDataFile describes a file written by operator-supplied user data.
+type UserDataFile struct {
+	Path        string
+	Content     string
+	Permissions uint
+}
+
+func (u *UserData) isEmpty() bool {
+	return len(u.Packages) == 0 && len(u.WriteFiles) == 0 &&
+		len(u.PreRunCmds) == 0 && len(u.RunCmds) == 0 &&
+		u.PackageMirror == ""
+}
+
+// userDataDoc is the YAML serialisation of UserData, using the same
+// keys as cloud-init's own configuration, other than preRunCmdKey.
+type userDataDoc struct {
+	Packages   []string          `yaml:"packages,omitempty"`
+	WriteFiles []userDataFileDoc `yaml:"write_files,omitempty"`
+	PreRunCmd  []string          `yaml:"juju-preruncmd,omitempty"`
+	RunCmd     []string          `yaml:"runcmd,omitempty"`
+	AptMirror  string            `yaml:"apt_mirror,omitempty"`
+}
+
+type userDataFileDoc struct {
+	Path        string `yaml:"path"`
+	Content     string `yaml:"content,omitempty"`
+	Permissions string `yaml:"permissions,omitempty"`
+}
+
+// ParseUserData parses operator-supplied cloud-init user data, which
+// must be a YAML mapping using a subset of cloud-init's keys: packages,
+// write_files, runcmd and apt_mirror, along with the Juju extension
+// juju-preruncmd for commands to be run before the Juju agent is
+// installed. The apt_mirror key sets
+// the mirror of the instance's package manager, whatever its series.
+// Empty user data is valid and results in an empty UserData.
+func ParseUserData(data string) (*UserData, error) {
+	var keys map[string]interface{}
+	if err := yaml.Unmarshal([]byte(data), &keys); err != nil {
+		return nil, errors.Annotate(err, "cannot parse cloud-init user data")
+	}
+	var unknown []string
+	for key := range keys {
+		switch key {
+		case "packages", "write_files", preRunCmdKey, "runcmd", "apt_mirror":
+		default:
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, errors.NotSupportedf("cloud-init user data key %q", unknown[0])
+	}
+	var doc userDataDoc
+	if err := yaml.Unmarshal([]byte(data), &doc); err != nil {
+		return nil, errors.Annotate(err, "cannot parse cloud-init user data")
+	}
+	userData := &UserData{
+		Packages:      doc.Packages,
+		PreRunCmds:    doc.PreRunCmd,
+		RunCmds:       doc.RunCmd,
+		PackageMirror: doc.AptMirror,
+	}
+	for _, f := range doc.WriteFiles {
+		if f.Path == "" {
+			return nil, errors.NotValidf("cloud-init user data file with no path")
+		}
+		perm := uint64(defaultUserDataFilePerm)
+		if f.Permissions != "" {
+			var err error
+			perm, err = strconv.ParseUint(f.Permissions, 8, 32)
+			if err != nil {
+				return nil, errors.NotValidf("permissions %q for file %q", f.Permissions, f.Path)
+			}
+		}
+		userData.WriteFiles = append(userData.WriteFiles, UserDataFile{
+			Path:        f.Path,
+			Content:     f.Content,
+			Permissions: uint(perm),
+		})
+	}
+	return userData, nil
+}
+
+// MergeUserData validates and merges the supplied cloud-init user data
+// documents, in order, and returns the result serialised as YAML. Empty
+// documents are ignored; if all are empty, the result is empty.
+func MergeUserData(docs ...string) (string, error) {
+	var merged UserData
+	for _, data := range docs {
+		userData, err := ParseUserData(data)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		merged.Merge(userData)
+	}
+	if merged.isEmpty() {
+		return "", nil
+	}
+	doc := userDataDoc{
+		Packages:  merged.Packages,
+		PreRunCmd: merged.PreRunCmds,
+		RunCmd:    merged.RunCmds,
+		AptMirror: merged.PackageMirror,
+	}
+	for _, f := range merged.WriteFiles {
+		doc.WriteFiles = append(doc.WriteFiles, userDataFileDoc{
+			Path:        f.Path,
+			Content:     f.Content,
+			Permissions: fmt.Sprintf("%#o", f.Permissions),
+		})
+	}
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(out), nil
+}
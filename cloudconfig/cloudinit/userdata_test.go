@@ -0,0 +1,109 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudinit_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+)
+
+type UserDataSuite struct{}
+
+var _ = gc.Suite(&UserDataSuite{})
+
+func (s *UserDataSuite) TestParseUserData(c *gc.C) {
+	userData, err := cloudinit.ParseUserData(`
+packages: [ca-certificates, monitoring-agent]
+write_files:
+  - path: /usr/local/share/ca-certificates/corp.crt
+    content: |
+      -----BEGIN CERTIFICATE-----
+  - path: /etc/agent.conf
+    content: enabled
+    permissions: "0600"
+juju-preruncmd:
+  - mkdir -p /etc/agent
+runcmd:
+  - update-ca-certificates
+apt_mirror: http://mirror.example.com/ubuntu
+`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(userData, jc.DeepEquals, &cloudinit.UserData{
+		Packages: []string{"ca-certificates", "monitoring-agent"},
+		WriteFiles: []cloudinit.UserDataFile{{
+			Path:        "/usr/local/share/ca-certificates/corp.crt",
+			Content:     "-----BEGIN CERTIFICATE-----\n",
+			Permissions: 0644,
+		}, {
+			Path:        "/etc/agent.conf",
+			Content:     "enabled",
+			Permissions: 0600,
+		}},
+		PreRunCmds:    []string{"mkdir -p /etc/agent"},
+		RunCmds:       []string{"update-ca-certificates"},
+		PackageMirror: "http://mirror.example.com/ubuntu",
+	})
+}
+
+func (s *UserDataSuite) TestParseUserDataEmpty(c *gc.C) {
+	userData, err := cloudinit.ParseUserData("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(userData, jc.DeepEquals, &cloudinit.UserData{})
+}
+
+func (s *UserDataSuite) TestParseUserDataErrors(c *gc.C) {
+	_, err := cloudinit.ParseUserData("users: [bob]\nbootcmd: [reboot]")
+	c.Assert(err, gc.ErrorMatches, `cloud-init user data key "bootcmd" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	_, err = cloudinit.ParseUserData("packages: curl")
+	c.Assert(err, gc.ErrorMatches, "cannot parse cloud-init user data: .*")
+
+	_, err = cloudinit.ParseUserData("write_files: [{content: x}]")
+	c.Assert(err, gc.ErrorMatches, "cloud-init user data file with no path not valid")
+
+	_, err = cloudinit.ParseUserData(`write_files: [{path: /x, permissions: "rw"}]`)
+	c.Assert(err, gc.ErrorMatches, `permissions "rw" for file "/x" not valid`)
+}
+
+func (s *UserDataSuite) TestMergeUserData(c *gc.C) {
+	merged, err := cloudinit.MergeUserData(`
+packages: [ca-certificates]
+runcmd: [update-ca-certificates]
+apt_mirror: http://model.example.com/ubuntu
+`, "", `
+packages: [monitoring-agent]
+write_files: [{path: /etc/agent.conf, content: enabled, permissions: "0600"}]
+juju-preruncmd: [mkdir -p /etc/agent]
+apt_mirror: http://machine.example.com/ubuntu
+`)
+	c.Assert(err, jc.ErrorIsNil)
+	userData, err := cloudinit.ParseUserData(merged)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(userData, jc.DeepEquals, &cloudinit.UserData{
+		Packages: []string{"ca-certificates", "monitoring-agent"},
+		WriteFiles: []cloudinit.UserDataFile{{
+			Path:        "/etc/agent.conf",
+			Content:     "enabled",
+			Permissions: 0600,
+		}},
+		PreRunCmds:    []string{"mkdir -p /etc/agent"},
+		RunCmds:       []string{"update-ca-certificates"},
+		PackageMirror: "http://machine.example.com/ubuntu",
+	})
+}
+
+func (s *UserDataSuite) TestMergeUserDataEmpty(c *gc.C) {
+	merged, err := cloudinit.MergeUserData("", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(merged, gc.Equals, "")
+}
+
+func (s *UserDataSuite) TestMergeUserDataInvalid(c *gc.C) {
+	_, err := cloudinit.MergeUserData("packages: [curl]", "bootcmd: [reboot]")
+	c.Assert(err, gc.ErrorMatches, `cloud-init user data key "bootcmd" not supported`)
+}
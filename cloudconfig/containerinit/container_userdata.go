@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 )
@@ -81,8 +83,36 @@ iface {{.InterfaceName}} inet dhcp
 {{end}}{{range $nic := . }}{{if eq $nic.ConfigType "static"}}
 {{template "static" $nic}}{{else}}{{template "dhcp" $nic}}{{end}}{{end}}`
 
+// subnetNetworkConfigTemplate defines how to render the
+// /etc/network/interfaces file for a container whose NICs are attached
+// directly to the host's subnets, rather than routed through the host.
+const subnetNetworkConfigTemplate = `
+# loopback interface
+auto lo
+iface lo inet loopback
+{{range $nic := . }}
+{{.InterfaceName | printf "# interface %q"}}{{if not .NoAutoStart}}
+auto {{.InterfaceName}}{{end}}
+iface {{.InterfaceName}} inet static
+    address {{cidrAddress .}}{{if .GatewayAddress.Value}}
+    gateway {{.GatewayAddress.Value}}{{end}}{{if gt (len .DNSServers) 0}}
+    dns-nameservers{{range $dns := .DNSServers}} {{$dns.Value}}{{end}}{{end}}{{if gt (len .DNSSearch) 0}}
+    dns-search {{.DNSSearch}}{{end}}
+{{end}}`
+
 var networkInterfacesFile = "/etc/network/interfaces"
 
+// cidrAddress returns the interface's address, qualified with the
+// prefix length of its subnet.
+func cidrAddress(nic network.InterfaceInfo) (string, error) {
+	_, ipNet, err := net.ParseCIDR(nic.CIDR)
+	if err != nil {
+		return "", errors.Annotatef(err, "interface %q", nic.InterfaceName)
+	}
+	ones, _ := ipNet.Mask.Size()
+	return fmt.Sprintf("%s/%d", nic.Address.Value, ones), nil
+}
+
 // GenerateNetworkConfig renders a network config for one or more
 // network interfaces, using the given non-nil networkConfig
 // containing a non-empty Interfaces field.
@@ -94,7 +124,13 @@ func GenerateNetworkConfig(networkConfig *container.NetworkConfig) (string, erro
 	}
 
 	// Render the config first.
-	tmpl, err := template.New("config").Parse(networkConfigTemplate)
+	configTemplate := networkConfigTemplate
+	if networkConfig.NetworkType == container.MacvlanNetwork {
+		configTemplate = subnetNetworkConfigTemplate
+	}
+	tmpl, err := template.New("config").Funcs(template.FuncMap{
+		"cidrAddress": cidrAddress,
+	}).Parse(configTemplate)
 	if err != nil {
 		return "", errors.Annotate(err, "cannot parse network config template")
 	}
@@ -95,6 +95,56 @@ func (s *UserDataSuite) TestGenerateNetworkConfig(c *gc.C) {
 	c.Assert(data, gc.Equals, s.expectedNetConfig)
 }
 
+func (s *UserDataSuite) TestGenerateNetworkConfigMacvlan(c *gc.C) {
+	netConfig := container.MacvlanNetworkConfig(0, []network.InterfaceInfo{{
+		InterfaceName:       "eth0",
+		ParentInterfaceName: "eth2",
+		CIDR:                "10.0.0.0/24",
+		ConfigType:          network.ConfigStatic,
+		Address:             network.NewAddress("10.0.0.5"),
+		DNSServers:          network.NewAddresses("ns1.invalid"),
+		DNSSearch:           "foo.bar",
+		GatewayAddress:      network.NewAddress("10.0.0.1"),
+	}, {
+		InterfaceName:       "eth1",
+		ParentInterfaceName: "eth3",
+		CIDR:                "10.1.0.0/16",
+		ConfigType:          network.ConfigStatic,
+		Address:             network.NewAddress("10.1.0.7"),
+	}})
+	data, err := containerinit.GenerateNetworkConfig(netConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, `
+# loopback interface
+auto lo
+iface lo inet loopback
+
+# interface "eth0"
+auto eth0
+iface eth0 inet static
+    address 10.0.0.5/24
+    gateway 10.0.0.1
+    dns-nameservers ns1.invalid
+    dns-search foo.bar
+
+# interface "eth1"
+auto eth1
+iface eth1 inet static
+    address 10.1.0.7/16
+`)
+}
+
+func (s *UserDataSuite) TestGenerateNetworkConfigMacvlanInvalidCIDR(c *gc.C) {
+	netConfig := container.MacvlanNetworkConfig(0, []network.InterfaceInfo{{
+		InterfaceName: "eth0",
+		CIDR:          "invalid",
+		ConfigType:    network.ConfigStatic,
+		Address:       network.NewAddress("10.0.0.5"),
+	}})
+	_, err := containerinit.GenerateNetworkConfig(netConfig)
+	c.Assert(err, gc.ErrorMatches, `cannot render network config: .*interface "eth0": invalid CIDR address: invalid`)
+}
+
 func (s *UserDataSuite) TestNewCloudInitConfigWithNetworks(c *gc.C) {
 	netConfig := container.BridgeNetworkConfig("foo", 0, s.fakeInterfaces)
 	cloudConf, err := containerinit.NewCloudInitConfigWithNetworks("quantal", netConfig)
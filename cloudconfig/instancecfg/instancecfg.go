@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state/multiwatcher"
@@ -189,6 +190,21 @@ type InstanceConfig struct {
 	// instances. If enabled, the OS will perform any upgrades
 	// available as part of its provisioning.
 	EnableOSUpgrade bool
+
+	// CloudInitUserData holds operator-supplied cloud-init user data,
+	// in the form accepted by cloudinit.ParseUserData, to be merged
+	// with the directives Juju generates for the instance.
+	CloudInitUserData string
+
+	// DNSNameservers holds the addresses of the controllers serving
+	// the model's internal DNS names, which the instance is configured
+	// to resolve names with, if internal DNS is enabled.
+	DNSNameservers []string
+
+	// DNSSearchDomain holds the internal DNS domain of the model,
+	// which the instance is configured to search, if internal DNS is
+	// enabled.
+	DNSSearchDomain string
 }
 
 func (cfg *InstanceConfig) agentInfo() service.AgentInfo {
@@ -550,6 +566,10 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 		// Unfortunately, AgentEnvironment can only take strings as values
 		icfg.AgentEnvironment[agent.NumaCtlPreference] = fmt.Sprintf("%v", cfg.NumaCtlPreference())
 	}
+	if cfg.InternalDNS() && !icfg.Bootstrap && icfg.APIInfo != nil {
+		icfg.DNSNameservers = apiHosts(icfg.APIInfo.Addrs)
+		icfg.DNSSearchDomain = network.ModelDNSDomain(cfg.Name(), cfg.UUID())
+	}
 	// The following settings are only appropriate at bootstrap time. At the
 	// moment, the only controller is the bootstrap node, but this
 	// will probably change.
@@ -648,3 +668,22 @@ func isStateInstanceConfig(icfg *InstanceConfig) bool {
 	}
 	return false
 }
+
+// apiHosts returns the distinct hosts of the given API addresses, in
+// order, excluding loopback addresses.
+func apiHosts(addrs []string) []string {
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, addr := range addrs {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			continue
+		}
+		if ip := net.ParseIP(host); ip == nil || ip.IsLoopback() || seen[host] {
+			continue
+		}
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
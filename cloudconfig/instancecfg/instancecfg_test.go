@@ -7,8 +7,10 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/testing"
 )
@@ -55,6 +57,25 @@ func (*instancecfgSuite) TestInstanceTagsUserSpecified(c *gc.C) {
 	})
 }
 
+func (*instancecfgSuite) TestFinishInstanceConfigInternalDNS(c *gc.C) {
+	apiInfo := &api.Info{Addrs: []string{
+		"10.0.0.1:17070", "127.0.0.1:17070", "[fd00::1]:17070", "10.0.0.1:17071",
+	}}
+	icfg, err := instancecfg.NewInstanceConfig("1", "nonce", "released", "trusty", "", true, nil, nil, apiInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg := testing.CustomModelConfig(c, testing.Attrs{})
+	err = instancecfg.FinishInstanceConfig(icfg, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(icfg.DNSNameservers, gc.HasLen, 0)
+	c.Assert(icfg.DNSSearchDomain, gc.Equals, "")
+
+	cfg = testing.CustomModelConfig(c, testing.Attrs{"internal-dns": true})
+	err = instancecfg.FinishInstanceConfig(icfg, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(icfg.DNSNameservers, jc.DeepEquals, []string{"10.0.0.1", "fd00::1"})
+	c.Assert(icfg.DNSSearchDomain, gc.Equals, network.ModelDNSDomain(cfg.Name(), cfg.UUID()))
+}
+
 func testInstanceTags(c *gc.C, cfg *config.Config, jobs []multiwatcher.MachineJob, expectTags map[string]string) {
 	tags := instancecfg.InstanceTags(cfg, jobs)
 	c.Assert(tags, jc.DeepEquals, expectTags)
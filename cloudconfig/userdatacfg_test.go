@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -1124,6 +1125,134 @@ func (s *cloudinitSuite) testAptMirror(c *gc.C, cfg *config.Config, expect strin
 	//c.Assert(ok, gc.Equals, expect != "")
 }
 
+func (s *cloudinitSuite) TestCloudInitUserData(c *gc.C) {
+	instanceCfg := s.createInstanceConfig(c, minimalModelConfig(c))
+	instanceCfg.CloudInitUserData = `
+packages: [ca-certificates]
+write_files: [{path: /etc/corp.crt, content: CERT}]
+juju-preruncmd: [install-corp-agent]
+runcmd: [update-ca-certificates]
+`
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	pkgs := cloudcfg.Packages()
+	c.Assert(pkgs[len(pkgs)-1], gc.Equals, "ca-certificates")
+	cmds := cloudcfg.RunCmds()
+	c.Assert(cmds[len(cmds)-1], gc.Equals, "update-ca-certificates")
+	fileIndex, preIndex, agentIndex := -1, -1, -1
+	for i, cmd := range cmds {
+		switch {
+		case fileIndex == -1 && strings.Contains(cmd, "/etc/corp.crt"):
+			fileIndex = i
+		case cmd == "install-corp-agent":
+			preIndex = i
+		case agentIndex == -1 && strings.Contains(cmd, "jujud-machine-42"):
+			agentIndex = i
+		}
+	}
+	c.Assert(fileIndex, jc.GreaterThan, -1)
+	c.Assert(preIndex, jc.GreaterThan, fileIndex)
+	c.Assert(agentIndex, jc.GreaterThan, preIndex)
+}
+
+func (s *cloudinitSuite) TestCloudInitUserDataCentOS(c *gc.C) {
+	instanceCfg := s.createInstanceConfig(c, minimalModelConfig(c))
+	instanceCfg.Series = "centos7"
+	instanceCfg.Tools = &tools.Tools{
+		Version: version.MustParseBinary("2.3.4-centos7-amd64"),
+		URL:     "http://tools.testing.invalid/2.3.4-centos7-amd64.tgz",
+	}
+	instanceCfg.CloudInitUserData = `
+packages: [ca-certificates]
+apt_mirror: http://mirror.example.com/centos
+`
+	cloudcfg, err := cloudinit.New("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	pkgs := cloudcfg.Packages()
+	c.Assert(pkgs[len(pkgs)-1], gc.Equals, "ca-certificates")
+	c.Assert(cloudcfg.PackageMirror(), gc.Equals, "http://mirror.example.com/centos")
+}
+
+func (s *cloudinitSuite) TestCloudInitUserDataAptMirror(c *gc.C) {
+	environConfig, err := minimalModelConfig(c).Apply(map[string]interface{}{
+		"apt-mirror": "http://my.archive.ubuntu.com/ubuntu",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	instanceCfg.CloudInitUserData = "apt_mirror: http://corp.archive.ubuntu.com/ubuntu"
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cloudcfg.PackageMirror(), gc.Equals, "http://corp.archive.ubuntu.com/ubuntu")
+}
+
+func (s *cloudinitSuite) TestCloudInitUserDataJujuDirectory(c *gc.C) {
+	instanceCfg := s.createInstanceConfig(c, minimalModelConfig(c))
+	instanceCfg.CloudInitUserData = "write_files: [{path: /var/lib/juju/agents/x, content: x}]"
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, gc.ErrorMatches, `cloud-init user data file "/var/lib/juju/agents/x" in Juju directory "/var/lib/juju" not valid`)
+}
+
+func (s *cloudinitSuite) TestCloudInitUserDataInvalid(c *gc.C) {
+	instanceCfg := s.createInstanceConfig(c, minimalModelConfig(c))
+	instanceCfg.CloudInitUserData = "bootcmd: [reboot]"
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, gc.ErrorMatches, `cloud-init user data key "bootcmd" not supported`)
+}
+
+func (s *cloudinitSuite) TestInternalDNS(c *gc.C) {
+	environConfig, err := minimalModelConfig(c).Apply(map[string]interface{}{
+		"internal-dns": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	c.Assert(instanceCfg.DNSNameservers, jc.DeepEquals, []string{"0.1.2.3"})
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	fileIndex, updateIndex, agentIndex := -1, -1, -1
+	for i, cmd := range cloudcfg.RunCmds() {
+		switch {
+		case strings.HasPrefix(cmd, "printf") && strings.Contains(cmd, "/etc/resolvconf/resolv.conf.d/head"):
+			c.Check(cmd, jc.Contains, "nameserver 0.1.2.3")
+			c.Check(cmd, jc.Contains, "search "+instanceCfg.DNSSearchDomain)
+			fileIndex = i
+		case cmd == "resolvconf -u":
+			updateIndex = i
+		case agentIndex == -1 && strings.Contains(cmd, "jujud-machine-42"):
+			agentIndex = i
+		}
+	}
+	c.Assert(fileIndex, jc.GreaterThan, -1)
+	c.Assert(updateIndex, jc.GreaterThan, fileIndex)
+	c.Assert(agentIndex, jc.GreaterThan, updateIndex)
+}
+
 var serverCert = []byte(`
 SERVER CERT
 -----BEGIN CERTIFICATE-----
@@ -1188,6 +1317,18 @@ func (*cloudinitSuite) TestWindowsCloudInit(c *gc.C) {
 	}
 }
 
+func (*cloudinitSuite) TestWindowsCloudInitUserData(c *gc.C) {
+	testConfig := makeNormalConfig("win8").setMachineID("10").render()
+	testConfig.CloudInitUserData = "packages: [curl]"
+	ci, err := cloudinit.New("win8")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(&testConfig, ci)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, gc.ErrorMatches, `cloud-init user data on series "win8" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (*cloudinitSuite) TestToolsDownloadCommand(c *gc.C) {
 	command := cloudconfig.ToolsDownloadCommand("download", []string{"a", "b", "c"})
 
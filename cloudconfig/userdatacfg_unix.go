@@ -146,6 +146,35 @@ func (w *unixConfigure) addCleanShutdownJob(initSystem string) {
 	}
 }
 
+// internalDNSResolvConfHead is the resolvconf file whose contents are
+// put at the head of the generated /etc/resolv.conf on Ubuntu.
+const internalDNSResolvConfHead = "/etc/resolvconf/resolv.conf.d/head"
+
+// addInternalDNS configures the machine to resolve names with the
+// controllers serving the model's internal DNS names, ahead of any
+// other nameservers, and to search the model's internal domain.
+func (w *unixConfigure) addInternalDNS() {
+	var lines []string
+	for _, nameserver := range w.icfg.DNSNameservers {
+		lines = append(lines, "nameserver "+nameserver)
+	}
+	lines = append(lines, "search "+w.icfg.DNSSearchDomain)
+	switch w.os {
+	case os.CentOS:
+		quoted := make([]string, len(lines))
+		for i, line := range lines {
+			quoted[i] = shquote(line)
+		}
+		w.conf.AddScripts(fmt.Sprintf(
+			"printf '%%s\\n' %s | cat - /etc/resolv.conf > /etc/resolv.conf.juju && mv /etc/resolv.conf.juju /etc/resolv.conf",
+			strings.Join(quoted, " "),
+		))
+	default:
+		w.conf.AddRunTextFile(internalDNSResolvConfHead, strings.Join(lines, "\n"), 0644)
+		w.conf.AddScripts("resolvconf -u")
+	}
+}
+
 func (w *unixConfigure) setDataDirPermissions() string {
 	var user string
 	switch w.os {
@@ -180,13 +209,32 @@ func (w *unixConfigure) ConfigureJuju() error {
 		w.conf.AddBootCmd(cloudinit.LogProgressCmd("Logging to %s on remote host", w.icfg.CloudInitOutputLog))
 	}
 
+	userData, err := w.operatorUserData()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	aptMirror := w.icfg.AptMirror
+	if userData.PackageMirror != "" {
+		aptMirror = userData.PackageMirror
+	}
 	w.conf.AddPackageCommands(
 		w.icfg.AptProxySettings,
-		w.icfg.AptMirror,
+		aptMirror,
 		w.icfg.EnableOSRefreshUpdate,
 		w.icfg.EnableOSUpgrade,
 	)
 
+	// Operator-supplied packages, files and pre-run commands are put in
+	// place before the agent is installed; their other commands are run
+	// once it has been.
+	for _, pkg := range userData.Packages {
+		w.conf.AddPackage(pkg)
+	}
+	for _, f := range userData.WriteFiles {
+		w.conf.AddRunTextFile(f.Path, f.Content, f.Permissions)
+	}
+	w.conf.AddRunCmd(userData.PreRunCmds...)
+
 	// Write out the normal proxy settings so that the settings are
 	// sourced by bash, and ssh through that.
 	w.conf.AddScripts(
@@ -204,6 +252,10 @@ func (w *unixConfigure) ConfigureJuju() error {
 				shquote(w.icfg.ProxySettings.AsScriptEnvironment())))
 	}
 
+	if len(w.icfg.DNSNameservers) > 0 {
+		w.addInternalDNS()
+	}
+
 	if w.icfg.PublicImageSigningKey != "" {
 		keyFile := filepath.Join(agent.DefaultPaths.ConfDir, simplestreams.SimplestreamsPublicKeyFile)
 		w.conf.AddRunTextFile(keyFile, w.icfg.PublicImageSigningKey, 0644)
@@ -354,7 +406,30 @@ func (w *unixConfigure) ConfigureJuju() error {
 		)
 	}
 
-	return w.addMachineAgentToBoot()
+	if err := w.addMachineAgentToBoot(); err != nil {
+		return err
+	}
+	w.conf.AddRunCmd(userData.RunCmds...)
+	return nil
+}
+
+// operatorUserData parses the operator-supplied cloud-init user data,
+// and checks that it will not overwrite any of Juju's own files.
+func (w *unixConfigure) operatorUserData() (*cloudinit.UserData, error) {
+	userData, err := cloudinit.ParseUserData(w.icfg.CloudInitUserData)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	reserved := []string{w.icfg.DataDir, w.icfg.LogDir}
+	for _, f := range userData.WriteFiles {
+		filePath := path.Clean(f.Path)
+		for _, dir := range reserved {
+			if dir != "" && (filePath == dir || strings.HasPrefix(filePath, dir+"/")) {
+				return nil, errors.NotValidf("cloud-init user data file %q in Juju directory %q", f.Path, dir)
+			}
+		}
+	}
+	return userData, nil
 }
 
 // toolsDownloadCommand takes a curl command minus the source URL,
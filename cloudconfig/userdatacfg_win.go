@@ -31,6 +31,12 @@ type windowsConfigure struct {
 // Configure updates the provided cloudinit.Config with
 // configuration to initialize a Juju machine agent.
 func (w *windowsConfigure) Configure() error {
+	// Windows instances are configured with scripts rather than
+	// cloud-init directives, so operator-supplied user data cannot
+	// be honoured.
+	if w.icfg.CloudInitUserData != "" {
+		return errors.NotSupportedf("cloud-init user data on series %q", w.icfg.Series)
+	}
 	if err := w.ConfigureBasic(); err != nil {
 		return err
 	}
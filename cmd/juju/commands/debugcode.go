@@ -0,0 +1,243 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"regexp"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	unitdebug "github.com/juju/juju/worker/uniter/runner/debug"
+)
+
+func newDebugCodeCommand() cmd.Command {
+	return modelcmd.Wrap(&debugCodeCommand{})
+}
+
+// debugCodeCommand is responsible for launching a ssh shell on a given
+// unit, in which the charm's own code runs for the debugged hooks.
+type debugCodeCommand struct {
+	debugHooksCommand
+	debugAt    string
+	run        string
+	relationId int
+	remoteUnit string
+	replay     bool
+
+	// debugCodeAPI is used in place of a new API client
+	// connection if set; it is only set in tests.
+	debugCodeAPI debugCodeAPI
+}
+
+// debugCodeAPI defines the API methods used by debug-code to find the
+// most recently failed hook of a unit, and to ask the unit to run a
+// hook.
+type debugCodeAPI interface {
+	Close() error
+	UnitHookHistory(args params.HookHistoryArgs) ([]params.HookExecution, error)
+	TriggerHook(unit, hook string, relationId int, remoteUnit string) error
+	ClearTriggeredHook(unit string) error
+}
+
+// triggeredHook describes a hook that the unit is asked to run once
+// the debug-code session has started.
+type triggeredHook struct {
+	hook       string
+	relationId int
+	remoteUnit string
+}
+
+const debugCodeDoc = `
+Run the charm's own code for hooks of a service unit in a tmux session,
+with the JUJU_DEBUG_AT environment variable set so that the code can
+stop at its breakpoints. How JUJU_DEBUG_AT is interpreted is up to the
+charm; by convention "all" stops at every breakpoint.
+
+As with debug-hooks, each intercepted hook runs in its own tmux window,
+and Juju waits for it to finish before continuing.
+
+A hook can be run as soon as the session starts, rather than waiting
+for Juju to run it, with --run. Relation hooks are run in the context of
+the relation given by --relation and, optionally, the remote unit given
+by --remote-unit; these can be found with "juju show-hook-history". The
+unit agent runs the hook as it would any other and records it in the
+hook history, but the hook does not change the unit's state and its
+failure does not put the unit in an error state.
+
+The --replay option runs the unit's most recently failed hook again,
+in the same relation and remote unit context that it failed in.
+
+Examples:
+
+    juju debug-code mysql/0
+    juju debug-code --at all mysql/0 config-changed
+    juju debug-code --run db-relation-changed -r 3 --remote-unit wordpress/0 mysql/0
+    juju debug-code --replay mysql/0
+`
+
+var validDebugAt = regexp.MustCompile(`^[\w.,:-]+$`)
+
+func (c *debugCodeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "debug-code",
+		Args:    "<unit name> [hook names]",
+		Purpose: "launch a tmux session to debug a charm's code",
+		Doc:     debugCodeDoc,
+	}
+}
+
+func (c *debugCodeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.debugHooksCommand.SetFlags(f)
+	f.StringVar(&c.debugAt, "at", "all", "value of JUJU_DEBUG_AT, naming the breakpoints to stop at")
+	f.StringVar(&c.run, "run", "", "run the named hook as soon as the session starts")
+	f.IntVar(&c.relationId, "r", -1, "id of the relation to run the hook in the context of")
+	f.IntVar(&c.relationId, "relation", -1, "")
+	f.StringVar(&c.remoteUnit, "remote-unit", "", "name of the remote unit to run the hook in the context of")
+	f.BoolVar(&c.replay, "replay", false, "run the unit's most recently failed hook again")
+}
+
+// AllowInterspersedFlags is true for debug-code, since, unlike ssh,
+// it passes no arguments through.
+func (c *debugCodeCommand) AllowInterspersedFlags() bool {
+	return true
+}
+
+func (c *debugCodeCommand) Init(args []string) error {
+	if err := c.debugHooksCommand.Init(args); err != nil {
+		return err
+	}
+	if !validDebugAt.MatchString(c.debugAt) {
+		return errors.Errorf("invalid --at value %q", c.debugAt)
+	}
+	if c.replay && c.run != "" {
+		return errors.New("cannot specify both --run and --replay")
+	}
+	if c.run == "" && (c.relationId != -1 || c.remoteUnit != "") {
+		return errors.New("--relation and --remote-unit require --run")
+	}
+	if c.remoteUnit != "" {
+		if c.relationId == -1 {
+			return errors.New("--remote-unit requires --relation")
+		}
+		if !names.IsValidUnit(c.remoteUnit) {
+			return errors.Errorf("%q is not a valid unit name", c.remoteUnit)
+		}
+	}
+	if c.relationId < -1 {
+		return errors.Errorf("invalid relation id %d", c.relationId)
+	}
+	return nil
+}
+
+// Run ensures c.Target is a unit, and resolves its address,
+// and connects to it via SSH to execute the debug-code
+// script.
+func (c *debugCodeCommand) Run(ctx *cmd.Context) error {
+	var err error
+	c.apiClient, err = c.initAPIClient()
+	if err != nil {
+		return err
+	}
+	defer c.apiClient.Close()
+
+	if !c.replay && c.run == "" {
+		if err := c.validateHookNames(c.hooks); err != nil {
+			return err
+		}
+		return c.runDebugCode(ctx, c.hooks)
+	}
+
+	api, err := c.getDebugCodeAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+	trigger := &triggeredHook{
+		hook:       c.run,
+		relationId: c.relationId,
+		remoteUnit: c.remoteUnit,
+	}
+	if c.replay {
+		trigger, err = c.replayTrigger(ctx, api)
+		if err != nil {
+			return err
+		}
+	}
+	hookNames := append(c.hooks[:len(c.hooks):len(c.hooks)], trigger.hook)
+	if err := c.validateHookNames(hookNames); err != nil {
+		return err
+	}
+	if len(c.hooks) == 0 {
+		// The session intercepts all hooks, the triggered one
+		// included.
+		hookNames = nil
+	}
+
+	// The unit agent runs the triggered hook once it finds the
+	// session intercepting it; the trigger is cleared when the
+	// session ends, in case the hook never ran.
+	err = api.TriggerHook(c.Target, trigger.hook, trigger.relationId, trigger.remoteUnit)
+	if errors.IsNotSupported(err) {
+		return errors.New("--run and --replay are not supported by this juju controller")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err := api.ClearTriggeredHook(c.Target); err != nil {
+			ctx.Infof("cannot clear triggered hook: %v", err)
+		}
+	}()
+	return c.runDebugCode(ctx, hookNames)
+}
+
+func (c *debugCodeCommand) runDebugCode(ctx *cmd.Context, hookNames []string) error {
+	debugctx := unitdebug.NewHooksContext(c.Target)
+	return c.runClientScript(ctx, unitdebug.DebugCodeClientScript(debugctx, hookNames, c.debugAt))
+}
+
+func (c *debugCodeCommand) getDebugCodeAPI() (debugCodeAPI, error) {
+	if c.debugCodeAPI != nil {
+		return c.debugCodeAPI, nil
+	}
+	return c.NewAPIClient()
+}
+
+// replayTrigger returns a trigger that runs the unit's most recently
+// failed hook again, in the context it failed in.
+func (c *debugCodeCommand) replayTrigger(ctx *cmd.Context, api debugCodeAPI) (*triggeredHook, error) {
+	executions, err := api.UnitHookHistory(params.HookHistoryArgs{
+		Unit: c.Target,
+		Kind: "hook",
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, execution := range executions {
+		if execution.Result == "completed" {
+			continue
+		}
+		if execution.Relation != "" && execution.RelationId == -1 {
+			return nil, errors.Errorf(
+				"cannot replay %s: relation %q no longer exists",
+				execution.Name, execution.Relation,
+			)
+		}
+		ctx.Infof(
+			"replaying %s, which %s at %s",
+			execution.Name, execution.Result, common.FormatTime(&execution.Started, false),
+		)
+		return &triggeredHook{
+			hook:       execution.Name,
+			relationId: execution.RelationId,
+			remoteUnit: execution.RemoteUnit,
+		}, nil
+	}
+	return nil, errors.Errorf("unit %q has no failed hook to replay", c.Target)
+}
@@ -0,0 +1,245 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"encoding/base64"
+	"regexp"
+	"runtime"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&DebugCodeSuite{})
+
+type DebugCodeSuite struct {
+	SSHCommonSuite
+}
+
+func (s *DebugCodeSuite) SetUpTest(c *gc.C) {
+	//TODO(bogdanteleaga): Fix once debughooks are supported on windows
+	if runtime.GOOS == "windows" {
+		c.Skip("bug 1403084: Skipping on windows for now")
+	}
+	s.SSHCommonSuite.SetUpTest(c)
+	machines := s.makeMachines(1, c, true)
+	dummy := s.AddTestingCharm(c, "mysql")
+	srv := s.AddTestingService(c, "mysql", dummy)
+	s.addUnit(srv, machines[0], c)
+}
+
+var debugCodeInitTests = []struct {
+	args  []string
+	error string
+}{{
+	args: []string{"mysql/0"},
+}, {
+	args: []string{"mysql/0", "start", "--at", "all,install"},
+}, {
+	args: []string{"--run", "server-relation-changed", "-r", "3", "--remote-unit", "wordpress/0", "mysql/0"},
+}, {
+	args: []string{"--replay", "mysql/0"},
+}, {
+	args:  []string{"mysql"},
+	error: `"mysql" is not a valid unit name`,
+}, {
+	args:  []string{"mysql/0", "--at", "all; rm -rf /"},
+	error: `invalid --at value "all; rm -rf /"`,
+}, {
+	args:  []string{"mysql/0", "--replay", "--run", "start"},
+	error: "cannot specify both --run and --replay",
+}, {
+	args:  []string{"mysql/0", "-r", "3"},
+	error: "--relation and --remote-unit require --run",
+}, {
+	args:  []string{"mysql/0", "--run", "start", "--remote-unit", "wordpress/0"},
+	error: "--remote-unit requires --relation",
+}, {
+	args:  []string{"mysql/0", "--run", "start", "-r", "3", "--remote-unit", "wordpress"},
+	error: `"wordpress" is not a valid unit name`,
+}}
+
+func (s *DebugCodeSuite) TestInit(c *gc.C) {
+	for i, t := range debugCodeInitTests {
+		c.Logf("test %d: %v", i, t.args)
+		err := coretesting.InitCommand(newDebugCodeCommand(), t.args)
+		if t.error == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, regexp.QuoteMeta(t.error))
+		}
+	}
+}
+
+func (s *DebugCodeSuite) runDebugCode(c *gc.C, api debugCodeAPI, args ...string) (string, error) {
+	ctx := coretesting.Context(c)
+	debugCodeCmd := modelcmd.Wrap(&debugCodeCommand{debugCodeAPI: api})
+	err := coretesting.InitCommand(debugCodeCmd, args)
+	if err == nil {
+		err = debugCodeCmd.Run(ctx)
+	}
+	if err != nil {
+		return "", err
+	}
+	// Decode the client script passed to ssh.
+	m := regexp.MustCompile(`echo (\S+) \| base64 -d > \$F`).FindStringSubmatch(coretesting.Stdout(ctx))
+	c.Assert(m, gc.HasLen, 2)
+	script, err := base64.StdEncoding.DecodeString(m[1])
+	c.Assert(err, jc.ErrorIsNil)
+	return string(script), nil
+}
+
+// hookArgs returns the hook arguments the client script passes to the
+// debug session.
+func hookArgs(c *gc.C, script string) string {
+	m := regexp.MustCompile(`echo "(\S*)" \| base64 -d > `).FindStringSubmatch(script)
+	c.Assert(m, gc.HasLen, 2)
+	args, err := base64.StdEncoding.DecodeString(m[1])
+	c.Assert(err, jc.ErrorIsNil)
+	return string(args)
+}
+
+func (s *DebugCodeSuite) TestRun(c *gc.C) {
+	api := &fakeDebugCodeAPI{}
+	script, err := s.runDebugCode(c, api, "mysql/0", "start")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hookArgs(c, script), gc.Equals, "hooks:\n- start\ndebug-at: all\n")
+	api.CheckNoCalls(c)
+}
+
+func (s *DebugCodeSuite) TestRunTrigger(c *gc.C) {
+	api := &fakeDebugCodeAPI{}
+	script, err := s.runDebugCode(c, api,
+		"--run", "server-relation-changed", "-r", "3", "--remote-unit", "wordpress/0", "mysql/0",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hookArgs(c, script), gc.Equals, "debug-at: all\n")
+	c.Assert(script, gc.Not(jc.Contains), "juju-run")
+	api.CheckCalls(c, []testing.StubCall{
+		{"TriggerHook", []interface{}{"mysql/0", "server-relation-changed", 3, "wordpress/0"}},
+		{"ClearTriggeredHook", []interface{}{"mysql/0"}},
+		{"Close", nil},
+	})
+}
+
+func (s *DebugCodeSuite) TestRunTriggerInterceptsTriggeredHook(c *gc.C) {
+	api := &fakeDebugCodeAPI{}
+	script, err := s.runDebugCode(c, api, "--run", "start", "mysql/0", "install")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hookArgs(c, script), gc.Equals, "hooks:\n- install\n- start\ndebug-at: all\n")
+	api.CheckCallNames(c, "TriggerHook", "ClearTriggeredHook", "Close")
+}
+
+func (s *DebugCodeSuite) TestRunTriggerInvalidHook(c *gc.C) {
+	api := &fakeDebugCodeAPI{}
+	_, err := s.runDebugCode(c, api, "--run", "invalid-hook", "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `unit "mysql/0" does not contain hook "invalid-hook"`)
+	api.CheckCallNames(c, "Close")
+}
+
+func (s *DebugCodeSuite) TestRunTriggerNotSupported(c *gc.C) {
+	api := &fakeDebugCodeAPI{}
+	api.SetErrors(errors.NotSupportedf("triggering hooks"))
+	_, err := s.runDebugCode(c, api, "--run", "start", "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "--run and --replay are not supported by this juju controller")
+	api.CheckCallNames(c, "TriggerHook", "Close")
+}
+
+func (s *DebugCodeSuite) TestRunTriggerAgainstController(c *gc.C) {
+	_, err := s.runDebugCode(c, nil, "--run", "start", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	// The trigger is cleared when the session ends.
+	unit, err := s.State.Unit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := unit.TriggeredHook()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *DebugCodeSuite) TestReplay(c *gc.C) {
+	api := &fakeDebugCodeAPI{executions: []params.HookExecution{{
+		Kind:       "hook",
+		Name:       "config-changed",
+		RelationId: -1,
+		Result:     "completed",
+	}, {
+		Kind:       "hook",
+		Name:       "server-relation-changed",
+		RelationId: 3,
+		Relation:   "wordpress:db mysql:server",
+		RemoteUnit: "wordpress/0",
+		Started:    time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC),
+		Result:     "failed",
+	}}}
+	_, err := s.runDebugCode(c, api, "--replay", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	api.CheckCalls(c, []testing.StubCall{
+		{"UnitHookHistory", []interface{}{params.HookHistoryArgs{Unit: "mysql/0", Kind: "hook"}}},
+		{"TriggerHook", []interface{}{"mysql/0", "server-relation-changed", 3, "wordpress/0"}},
+		{"ClearTriggeredHook", []interface{}{"mysql/0"}},
+		{"Close", nil},
+	})
+}
+
+func (s *DebugCodeSuite) TestReplayNoFailedHook(c *gc.C) {
+	api := &fakeDebugCodeAPI{executions: []params.HookExecution{{
+		Kind:       "hook",
+		Name:       "config-changed",
+		RelationId: -1,
+		Result:     "completed",
+	}}}
+	_, err := s.runDebugCode(c, api, "--replay", "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `unit "mysql/0" has no failed hook to replay`)
+}
+
+func (s *DebugCodeSuite) TestReplayRelationRemoved(c *gc.C) {
+	api := &fakeDebugCodeAPI{executions: []params.HookExecution{{
+		Kind:       "hook",
+		Name:       "server-relation-changed",
+		RelationId: -1,
+		Relation:   "wordpress:db mysql:server",
+		Result:     "failed",
+	}}}
+	_, err := s.runDebugCode(c, api, "--replay", "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `cannot replay server-relation-changed: relation "wordpress:db mysql:server" no longer exists`)
+}
+
+func (s *DebugCodeSuite) TestReplayError(c *gc.C) {
+	api := &fakeDebugCodeAPI{}
+	api.SetErrors(errors.New("boom"))
+	_, err := s.runDebugCode(c, api, "--replay", "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeDebugCodeAPI struct {
+	testing.Stub
+	executions []params.HookExecution
+}
+
+func (f *fakeDebugCodeAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeDebugCodeAPI) UnitHookHistory(args params.HookHistoryArgs) ([]params.HookExecution, error) {
+	f.MethodCall(f, "UnitHookHistory", args)
+	return f.executions, f.NextErr()
+}
+
+func (f *fakeDebugCodeAPI) TriggerHook(unit, hook string, relationId int, remoteUnit string) error {
+	f.MethodCall(f, "TriggerHook", unit, hook, relationId, remoteUnit)
+	return f.NextErr()
+}
+
+func (f *fakeDebugCodeAPI) ClearTriggeredHook(unit string) error {
+	f.MethodCall(f, "ClearTriggeredHook", unit)
+	return f.NextErr()
+}
@@ -74,7 +74,13 @@ func (c *debugHooksCommand) getServiceAPI() (charmRelationsApi, error) {
 }
 
 func (c *debugHooksCommand) validateHooks() error {
-	if len(c.hooks) == 0 {
+	return c.validateHookNames(c.hooks)
+}
+
+// validateHookNames checks that the target unit's charm has each
+// of the named hooks.
+func (c *debugHooksCommand) validateHookNames(hookNames []string) error {
+	if len(hookNames) == 0 {
 		return nil
 	}
 	service, err := names.UnitService(c.Target)
@@ -100,7 +106,7 @@ func (c *debugHooksCommand) validateHooks() error {
 			validHooks[hook] = true
 		}
 	}
-	for _, hook := range c.hooks {
+	for _, hook := range hookNames {
 		if !validHooks[hook] {
 			names := make([]string, 0, len(validHooks))
 			for hookName := range validHooks {
@@ -129,7 +135,13 @@ func (c *debugHooksCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	debugctx := unitdebug.NewHooksContext(c.Target)
-	script := base64.StdEncoding.EncodeToString([]byte(unitdebug.ClientScript(debugctx, c.hooks)))
+	return c.runClientScript(ctx, unitdebug.ClientScript(debugctx, c.hooks))
+}
+
+// runClientScript connects to the target unit via SSH and runs
+// the given debug client script there as root.
+func (c *debugHooksCommand) runClientScript(ctx *cmd.Context, clientScript string) error {
+	script := base64.StdEncoding.EncodeToString([]byte(clientScript))
 	innercmd := fmt.Sprintf(`F=$(mktemp); echo %s | base64 -d > $F; . $F`, script)
 	args := []string{fmt.Sprintf("sudo /bin/bash -c '%s'", innercmd)}
 	c.Args = args
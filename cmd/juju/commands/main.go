@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/cmd/juju/metricsdebug"
 	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/cmd/juju/secrets"
 	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/cmd/juju/setmeterstatus"
 	"github.com/juju/juju/cmd/juju/space"
@@ -139,6 +140,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(status.NewStatusCommand())
 	r.Register(newSwitchCommand())
 	r.Register(status.NewStatusHistoryCommand())
+	r.Register(status.NewHookHistoryCommand())
 
 	// Error resolution and debugging commands.
 	r.Register(newRunCommand())
@@ -147,6 +149,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(newResolvedCommand())
 	r.Register(newDebugLogCommand())
 	r.Register(newDebugHooksCommand())
+	r.Register(newDebugCodeCommand())
 
 	// Configuration commands.
 	r.Register(model.NewModelGetConstraintsCommand())
@@ -190,9 +193,12 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(machine.NewRemoveCommand())
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
+	r.Register(machine.NewAddProfileCommand())
+	r.Register(machine.NewListProfilesCommand())
 
 	// Manage model
 	r.Register(model.NewGetCommand())
+	r.Register(model.NewShowFirewallCommand())
 	r.Register(model.NewSetCommand())
 	r.Register(model.NewUnsetCommand())
 	r.Register(model.NewRetryProvisioningCommand())
@@ -218,6 +224,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(service.NewSetCommand())
 	r.Register(service.NewDeployCommand())
 	r.Register(service.NewExposeCommand())
+	r.Register(service.NewOfferCommand())
+	r.Register(service.NewSetZonePolicyCommand())
 	r.Register(service.NewUnexposeCommand())
 	r.Register(service.NewServiceGetConstraintsCommand())
 	r.Register(service.NewServiceSetConstraintsCommand())
@@ -232,6 +240,11 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterSuperAlias("show-storage", "storage", "show", nil)
 	r.RegisterSuperAlias("add-storage", "storage", "add", nil)
 
+	// Inspect secrets
+	r.Register(secrets.NewSuperCommand())
+	r.RegisterSuperAlias("list-secrets", "secrets", "list", nil)
+	r.RegisterSuperAlias("show-secret", "secrets", "show", nil)
+
 	// Manage spaces
 	r.Register(space.NewSuperCommand())
 	r.RegisterSuperAlias("add-space", "space", "create", nil)
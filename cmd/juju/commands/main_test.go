@@ -195,6 +195,7 @@ var commandNames = []string{
 	"action",
 	"add-cloud",
 	"add-machine",
+	"add-machine-profile",
 	"add-machines",
 	"add-relation",
 	"add-ssh-key",
@@ -218,6 +219,7 @@ var commandNames = []string{
 	"create-backup",
 	"create-budget",
 	"create-model",
+	"debug-code",
 	"debug-hooks",
 	"debug-log",
 	"debug-metrics",
@@ -248,9 +250,11 @@ var commandNames = []string{
 	"list-controllers",
 	"list-credentials",
 	"list-machine",
+	"list-machine-profiles",
 	"list-machines",
 	"list-models",
 	"list-plans",
+	"list-secrets",
 	"list-shares",
 	"list-ssh-key",
 	"list-ssh-keys",
@@ -259,6 +263,7 @@ var commandNames = []string{
 	"list-users",
 	"machine",
 	"machines",
+	"offer",
 	"publish",
 	"register",
 	"remove-all-blocks",
@@ -275,6 +280,7 @@ var commandNames = []string{
 	"run",
 	"run-action",
 	"scp",
+	"secrets",
 	"set-budget",
 	"set-config",
 	"set-configs",
@@ -283,6 +289,7 @@ var commandNames = []string{
 	"set-model-config",
 	"set-model-constraints",
 	"set-plan",
+	"set-zone-policy",
 	"share-model",
 	"ssh-key",
 	"ssh-keys",
@@ -292,8 +299,11 @@ var commandNames = []string{
 	"show-cloud",
 	"show-controller",
 	"show-controllers",
+	"show-firewall",
+	"show-hook-history",
 	"show-machine",
 	"show-machines",
+	"show-secret",
 	"show-status",
 	"show-storage",
 	"show-user",
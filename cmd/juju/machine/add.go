@@ -5,6 +5,7 @@ package machine
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -60,6 +61,16 @@ information about how to allocate the machine. For example, one can direct the
 MAAS provider to acquire a particular node by specifying its hostname.
 For more information on placement directives, see "juju help placement".
 
+A machine profile, added with "juju add-machine-profile", may be named with
+--profile to give the new machines the profile's series, constraints and
+cloud-init user data. Any series or constraints given on the command line
+take precedence over the profile's.
+
+Cloud-init user data for the new machines may be given in a file with
+--cloudinit-userdata. It is merged with the model's cloudinit-userdata
+setting and that of any profile; see "juju add-machine-profile" for the
+supported keys.
+
 Examples:
    juju add-machine                      (starts a new machine)
    juju add-machine -n 2                 (starts 2 new machines)
@@ -67,6 +78,7 @@ Examples:
    juju add-machine lxc -n 2             (starts 2 new machines with an lxc container)
    juju add-machine lxc:4                (starts a new lxc container on machine 4)
    juju add-machine --constraints mem=8G (starts a machine with at least 8GB RAM)
+   juju add-machine --profile database   (starts a machine using the database profile)
    juju add-machine ssh:user@10.10.0.3   (manually provisions a machine with ssh)
    juju add-machine zone=us-east-1a      (start a machine in zone us-east-1a on AWS)
    juju add-machine maas2.name           (acquire machine maas2.name on MAAS)
@@ -75,6 +87,7 @@ See Also:
    juju help constraints
    juju help placement
    juju help remove-machine
+   juju help add-machine-profile
 `
 
 func init() {
@@ -110,6 +123,11 @@ type addCommand struct {
 	NumMachines int
 	// Disks describes disks that are to be attached to the machine.
 	Disks []storage.Constraints
+	// Profile names the machine profile to apply to the machine.
+	Profile string
+	// UserDataFile is the path of a file holding cloud-init user data
+	// for the machine.
+	UserDataFile string
 }
 
 func (c *addCommand) Info() *cmd.Info {
@@ -127,6 +145,8 @@ func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumMachines, "n", 1, "The number of machines to add")
 	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "additional machine constraints")
 	f.Var(disksFlag{&c.Disks}, "disks", "constraints for disks to attach to the machine")
+	f.StringVar(&c.Profile, "profile", "", "the machine profile to apply to the machine")
+	f.StringVar(&c.UserDataFile, "cloudinit-userdata", "", "path to a file of cloud-init user data for the machine")
 }
 
 func (c *addCommand) Init(args []string) error {
@@ -148,6 +168,12 @@ func (c *addCommand) Init(args []string) error {
 	if c.NumMachines > 1 && c.Placement != nil && c.Placement.Directive != "" {
 		return fmt.Errorf("cannot use -n when specifying a placement directive")
 	}
+	if c.Profile != "" && c.Placement != nil && c.Placement.Scope == "ssh" {
+		return fmt.Errorf("cannot use --profile when manually provisioning a machine")
+	}
+	if c.UserDataFile != "" && c.Placement != nil && c.Placement.Scope == "ssh" {
+		return fmt.Errorf("cannot use --cloudinit-userdata when manually provisioning a machine")
+	}
 	return nil
 }
 
@@ -191,6 +217,15 @@ func (c *addCommand) getMachineManagerAPI() (MachineManagerAPI, error) {
 }
 
 func (c *addCommand) Run(ctx *cmd.Context) error {
+	var userData string
+	if c.UserDataFile != "" {
+		data, err := ioutil.ReadFile(ctx.AbsPath(c.UserDataFile))
+		if err != nil {
+			return errors.Annotate(err, "cannot read cloud-init user data")
+		}
+		userData = string(data)
+	}
+
 	client, err := c.getClientAPI()
 	if err != nil {
 		return errors.Trace(err)
@@ -198,15 +233,21 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 	defer client.Close()
 
 	var machineManager MachineManagerAPI
-	if len(c.Disks) > 0 {
+	if len(c.Disks) > 0 || c.Profile != "" || userData != "" {
 		machineManager, err = c.getMachineManagerAPI()
 		if err != nil {
 			return errors.Trace(err)
 		}
 		defer machineManager.Close()
-		if machineManager.BestAPIVersion() < 1 {
+		if len(c.Disks) > 0 && machineManager.BestAPIVersion() < 1 {
 			return errors.New("cannot add machines with disks: not supported by the API server")
 		}
+		if c.Profile != "" && machineManager.BestAPIVersion() < 3 {
+			return errors.New("cannot add machines with profiles: not supported by the API server")
+		}
+		if userData != "" && machineManager.BestAPIVersion() < 4 {
+			return errors.New("cannot add machines with cloud-init user data: not supported by the API server")
+		}
 	}
 
 	logger.Infof("load config")
@@ -265,6 +306,9 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 		Constraints: c.Constraints,
 		Jobs:        jobs,
 		Disks:       c.Disks,
+		Profile:     c.Profile,
+
+		CloudInitUserData: userData,
 	}
 	machines := make([]params.AddMachineParams, c.NumMachines)
 	for i := 0; i < c.NumMachines; i++ {
@@ -272,8 +316,9 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 	}
 
 	var results []params.AddMachinesResult
-	// If storage is specified, we attempt to use a new API on the service facade.
-	if len(c.Disks) > 0 {
+	// If storage, a profile or cloud-init user data is specified, we use
+	// the newer API on the machine manager facade.
+	if machineManager != nil {
 		results, err = machineManager.AddMachines(machines)
 	} else {
 		results, err = client.AddMachines(machines)
@@ -4,6 +4,8 @@
 package machine_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -77,6 +79,12 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"ssh:user@10.10.0.3"},
 			count:     1,
 			placement: "ssh:user@10.10.0.3",
+		}, {
+			args:        []string{"ssh:user@10.10.0.3", "--profile", "database"},
+			errorString: "cannot use --profile when manually provisioning a machine",
+		}, {
+			args:        []string{"ssh:user@10.10.0.3", "--cloudinit-userdata", "userdata.yaml"},
+			errorString: "cannot use --cloudinit-userdata when manually provisioning a machine",
 		}, {
 			args:      []string{"zone=us-east-1a"},
 			count:     1,
@@ -219,6 +227,49 @@ func (s *AddMachineSuite) TestAddMachineWithDisksUnsupported(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cannot add machines with disks: not supported by the API server")
 }
 
+func (s *AddMachineSuite) TestAddMachineWithProfile(c *gc.C) {
+	s.fakeMachineManager.apiVersion = 3
+	_, err := s.run(c, "--profile", "database")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeAddMachine.args, gc.HasLen, 0)
+	c.Assert(s.fakeMachineManager.args, gc.HasLen, 1)
+	c.Assert(s.fakeMachineManager.args[0].Profile, gc.Equals, "database")
+}
+
+func (s *AddMachineSuite) TestAddMachineWithProfileUnsupported(c *gc.C) {
+	s.fakeMachineManager.apiVersion = 2
+	_, err := s.run(c, "--profile", "database")
+	c.Assert(err, gc.ErrorMatches, "cannot add machines with profiles: not supported by the API server")
+}
+
+func (s *AddMachineSuite) TestAddMachineWithCloudInitUserData(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "userdata.yaml")
+	err := ioutil.WriteFile(path, []byte("packages: [curl]\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.fakeMachineManager.apiVersion = 4
+	_, err = s.run(c, "--cloudinit-userdata", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeAddMachine.args, gc.HasLen, 0)
+	c.Assert(s.fakeMachineManager.args, gc.HasLen, 1)
+	c.Assert(s.fakeMachineManager.args[0].CloudInitUserData, gc.Equals, "packages: [curl]\n")
+}
+
+func (s *AddMachineSuite) TestAddMachineWithCloudInitUserDataUnsupported(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "userdata.yaml")
+	err := ioutil.WriteFile(path, []byte("packages: [curl]\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.fakeMachineManager.apiVersion = 3
+	_, err = s.run(c, "--cloudinit-userdata", path)
+	c.Assert(err, gc.ErrorMatches, "cannot add machines with cloud-init user data: not supported by the API server")
+}
+
+func (s *AddMachineSuite) TestAddMachineWithMissingCloudInitUserData(c *gc.C) {
+	_, err := s.run(c, "--cloudinit-userdata", filepath.Join(c.MkDir(), "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, "cannot read cloud-init user data: .*")
+}
+
 type fakeAddMachineAPI struct {
 	successOrder []bool
 	currentOp    int
@@ -0,0 +1,158 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+)
+
+const addMachineProfileDoc = `
+Machine profiles are named sets of machine attributes which may be
+referenced when adding machines, or deploying services, so that the
+machines created are configured consistently.
+
+Profile names consist of lower-case letters and digits, optionally
+separated by single hyphens; for example "web" or "m4-2xlarge".
+
+A profile may specify the series and constraints of its machines, the
+source of their root disks, and cloud-init user data to be applied when
+they are provisioned. Network
+spaces and instance tags are specified with the "spaces" and "tags"
+constraints. Series and constraints given explicitly to add-machine or
+deploy take precedence over those of the profile.
+
+The cloud-init user data file is YAML, and may contain the keys
+"packages", "write_files", "runcmd" and "apt_mirror", which are
+interpreted as they are by cloud-init, and the Juju extension
+"juju-preruncmd", which lists commands to be run before the Juju agent
+is installed. Machines of Windows series cannot be given user data.
+
+The root-disk source names the kind of storage backing the root disks of
+the profile's machines, and is validated by the cloud provider. On EC2
+it may be "magnetic" or "ssd"; providers which cannot choose the source
+of root disks reject profiles specifying one.
+
+Services and machines in a bundle refer to a profile with a
+"machine-profile" annotation.
+
+Examples:
+   juju add-machine-profile web --series xenial --constraints "mem=4G spaces=dmz"
+   juju add-machine-profile corp --cloudinit-userdata corp-ca.yaml
+   juju add-machine-profile fast --root-disk-source ssd
+   juju add-machine --profile web
+
+See Also:
+   juju help list-machine-profiles
+   juju help add-machine
+   juju help deploy
+`
+
+// NewAddProfileCommand returns a command that adds a machine profile
+// to a model.
+func NewAddProfileCommand() cmd.Command {
+	return modelcmd.Wrap(&addProfileCommand{})
+}
+
+// addProfileCommand adds a named machine profile to a model.
+type addProfileCommand struct {
+	modelcmd.ModelCommandBase
+	api         AddMachineProfileAPI
+	Name        string
+	Series      string
+	Constraints constraints.Value
+	// RootDiskSource is the provider-specific source of the root
+	// disks of machines created with the profile.
+	RootDiskSource string
+	// UserDataFile is the path of a file holding cloud-init user data.
+	UserDataFile string
+}
+
+// AddMachineProfileAPI defines the API methods used by the
+// add-machine-profile command.
+type AddMachineProfileAPI interface {
+	AddMachineProfile(params.MachineProfile) error
+	Close() error
+}
+
+// Info implements Command.Info.
+func (c *addProfileCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-machine-profile",
+		Args:    "<name>",
+		Purpose: "add a named machine profile to the model",
+		Doc:     addMachineProfileDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addProfileCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Series, "series", "", "the series of machines created with the profile")
+	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "the constraints of machines created with the profile")
+	f.StringVar(&c.RootDiskSource, "root-disk-source", "", "the source of the root disks of machines created with the profile")
+	f.StringVar(&c.UserDataFile, "cloudinit-userdata", "", "path to a file of cloud-init user data")
+}
+
+// Init implements Command.Init.
+func (c *addProfileCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no profile name specified")
+	}
+	c.Name, args = args[0], args[1:]
+	if !instance.IsValidMachineProfileName(c.Name) {
+		return errors.Errorf("invalid profile name %q", c.Name)
+	}
+	if c.Constraints.Container != nil {
+		return errors.Errorf("container constraint %q not allowed in a machine profile", *c.Constraints.Container)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *addProfileCommand) getAPI() (AddMachineProfileAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *addProfileCommand) Run(ctx *cmd.Context) error {
+	profile := params.MachineProfile{
+		Name:           c.Name,
+		Series:         c.Series,
+		Constraints:    c.Constraints,
+		RootDiskSource: c.RootDiskSource,
+	}
+	if c.UserDataFile != "" {
+		data, err := ioutil.ReadFile(ctx.AbsPath(c.UserDataFile))
+		if err != nil {
+			return errors.Annotate(err, "cannot read cloud-init user data")
+		}
+		profile.CloudInitUserData = string(data)
+	}
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	err = client.AddMachineProfile(profile)
+	if errors.IsNotSupported(err) {
+		return errors.New("cannot add machine profiles: not supported by the API server")
+	}
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,144 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testing"
+)
+
+type AddMachineProfileSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake *fakeMachineProfileAPI
+}
+
+var _ = gc.Suite(&AddMachineProfileSuite{})
+
+func (s *AddMachineProfileSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeMachineProfileAPI{}
+}
+
+func (s *AddMachineProfileSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	add, _ := machine.NewAddProfileCommandForTest(s.fake)
+	return testing.RunCommand(c, add, args...)
+}
+
+func (s *AddMachineProfileSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		name        string
+		series      string
+		constraints string
+		errorString string
+	}{{
+		errorString: "no profile name specified",
+	}, {
+		args: []string{"web"},
+		name: "web",
+	}, {
+		args:        []string{"web", "--series", "xenial", "--constraints", "mem=4G spaces=dmz"},
+		name:        "web",
+		series:      "xenial",
+		constraints: "mem=4096M spaces=dmz",
+	}, {
+		args:        []string{"web!"},
+		errorString: `invalid profile name "web!"`,
+	}, {
+		args:        []string{"Web_Servers"},
+		errorString: `invalid profile name "Web_Servers"`,
+	}, {
+		args: []string{"m4-2xlarge"},
+		name: "m4-2xlarge",
+	}, {
+		args:        []string{"web", "--constraints", "container=lxc"},
+		errorString: `container constraint "lxc" not allowed in a machine profile`,
+	}, {
+		args:        []string{"web", "db"},
+		errorString: `unrecognized args: \["db"\]`,
+	}} {
+		c.Logf("test %d", i)
+		wrappedCommand, addCmd := machine.NewAddProfileCommandForTest(s.fake)
+		err := testing.InitCommand(wrappedCommand, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(addCmd.Name, gc.Equals, test.name)
+			c.Check(addCmd.Series, gc.Equals, test.series)
+			c.Check(addCmd.Constraints.String(), gc.Equals, test.constraints)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *AddMachineProfileSuite) TestAddProfile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "userdata.yaml")
+	err := ioutil.WriteFile(path, []byte("packages: [curl]\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.run(c, "web", "--series", "xenial", "--constraints", "mem=4G", "--root-disk-source", "ssd", "--cloudinit-userdata", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.added, jc.DeepEquals, []params.MachineProfile{{
+		Name:              "web",
+		Series:            "xenial",
+		Constraints:       constraints.MustParse("mem=4G"),
+		RootDiskSource:    "ssd",
+		CloudInitUserData: "packages: [curl]\n",
+	}})
+}
+
+func (s *AddMachineProfileSuite) TestAddProfileMissingUserData(c *gc.C) {
+	_, err := s.run(c, "web", "--cloudinit-userdata", filepath.Join(c.MkDir(), "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, "cannot read cloud-init user data: .*")
+	c.Assert(s.fake.added, gc.HasLen, 0)
+}
+
+func (s *AddMachineProfileSuite) TestAddProfileUnsupported(c *gc.C) {
+	s.fake.err = errors.NotSupportedf("machine profiles on this juju controller")
+	_, err := s.run(c, "web")
+	c.Assert(err, gc.ErrorMatches, "cannot add machine profiles: not supported by the API server")
+}
+
+func (s *AddMachineProfileSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = common.OperationBlockedError("TestBlockedError")
+	_, err := s.run(c, "web")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestBlockedError.*")
+}
+
+type fakeMachineProfileAPI struct {
+	added    []params.MachineProfile
+	profiles []params.MachineProfile
+	err      error
+}
+
+func (f *fakeMachineProfileAPI) Close() error {
+	return nil
+}
+
+func (f *fakeMachineProfileAPI) AddMachineProfile(profile params.MachineProfile) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.added = append(f.added, profile)
+	return nil
+}
+
+func (f *fakeMachineProfileAPI) ListMachineProfiles() ([]params.MachineProfile, error) {
+	return f.profiles, f.err
+}
@@ -51,6 +51,28 @@ func NewRemoveCommandForTest(api RemoveMachineAPI) (cmd.Command, *RemoveCommand)
 	return modelcmd.Wrap(cmd), &RemoveCommand{cmd}
 }
 
+type AddProfileCommand struct {
+	*addProfileCommand
+}
+
+// NewAddProfileCommandForTest returns an AddProfileCommand with the api
+// provided as specified.
+func NewAddProfileCommandForTest(api AddMachineProfileAPI) (cmd.Command, *AddProfileCommand) {
+	cmd := &addProfileCommand{
+		api: api,
+	}
+	return modelcmd.Wrap(cmd), &AddProfileCommand{cmd}
+}
+
+// NewListProfilesCommandForTest returns a listProfilesCommand with the
+// api provided as specified.
+func NewListProfilesCommandForTest(api ListMachineProfilesAPI) cmd.Command {
+	cmd := &listProfilesCommand{
+		api: api,
+	}
+	return modelcmd.Wrap(cmd)
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
@@ -0,0 +1,149 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const listMachineProfilesDoc = `
+List the machine profiles defined in the model. The full cloud-init user
+data of each profile is shown in the yaml and json formats; the tabular
+format only shows whether a profile has any.
+
+See Also:
+   juju help add-machine-profile
+`
+
+// NewListProfilesCommand returns a command that lists the machine
+// profiles in a model.
+func NewListProfilesCommand() cmd.Command {
+	return modelcmd.Wrap(&listProfilesCommand{})
+}
+
+// listProfilesCommand lists the machine profiles in a model.
+type listProfilesCommand struct {
+	modelcmd.ModelCommandBase
+	api ListMachineProfilesAPI
+	out cmd.Output
+}
+
+// ListMachineProfilesAPI defines the API methods used by the
+// list-machine-profiles command.
+type ListMachineProfilesAPI interface {
+	ListMachineProfiles() ([]params.MachineProfile, error)
+	Close() error
+}
+
+// MachineProfileInfo holds the formatted details of a machine profile.
+type MachineProfileInfo struct {
+	Series            string `yaml:"series,omitempty" json:"series,omitempty"`
+	Constraints       string `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	RootDiskSource    string `yaml:"root-disk-source,omitempty" json:"root-disk-source,omitempty"`
+	CloudInitUserData string `yaml:"cloudinit-userdata,omitempty" json:"cloudinit-userdata,omitempty"`
+}
+
+// Info implements Command.Info.
+func (c *listProfilesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-machine-profiles",
+		Purpose: "list the machine profiles in the model",
+		Doc:     listMachineProfilesDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listProfilesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatMachineProfilesTabular,
+	})
+}
+
+func (c *listProfilesCommand) getAPI() (ListMachineProfilesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *listProfilesCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	profiles, err := client.ListMachineProfiles()
+	if errors.IsNotSupported(err) {
+		return errors.New("cannot list machine profiles: not supported by the API server")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if len(profiles) == 0 {
+		ctx.Infof("No machine profiles to display.")
+		return nil
+	}
+	output := make(map[string]MachineProfileInfo)
+	for _, p := range profiles {
+		output[p.Name] = MachineProfileInfo{
+			Series:            p.Series,
+			Constraints:       p.Constraints.String(),
+			RootDiskSource:    p.RootDiskSource,
+			CloudInitUserData: p.CloudInitUserData,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatMachineProfilesTabular returns a tabular summary of machine
+// profiles.
+func formatMachineProfilesTabular(value interface{}) ([]byte, error) {
+	profiles, ok := value.(map[string]MachineProfileInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", profiles, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintln(tw, "NAME\tSERIES\tCONSTRAINTS\tROOT-DISK\tUSER-DATA")
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := profiles[name]
+		userData := "no"
+		if p.CloudInitUserData != "" {
+			userData = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", name, p.Series, p.Constraints, p.RootDiskSource, userData)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testing"
+)
+
+type ListMachineProfilesSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake *fakeMachineProfileAPI
+}
+
+var _ = gc.Suite(&ListMachineProfilesSuite{})
+
+func (s *ListMachineProfilesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeMachineProfileAPI{
+		profiles: []params.MachineProfile{{
+			Name:              "web",
+			Series:            "xenial",
+			Constraints:       constraints.MustParse("mem=4G"),
+			CloudInitUserData: "packages: [curl]\n",
+		}, {
+			Name:           "db",
+			Constraints:    constraints.MustParse("root-disk=100G"),
+			RootDiskSource: "ssd",
+		}},
+	}
+}
+
+func (s *ListMachineProfilesSuite) TestListTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, machine.NewListProfilesCommandForTest(s.fake))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"NAME  SERIES  CONSTRAINTS        ROOT-DISK  USER-DATA\n"+
+		"db            root-disk=102400M  ssd        no\n"+
+		"web   xenial  mem=4096M                     yes\n",
+	)
+}
+
+func (s *ListMachineProfilesSuite) TestListYaml(c *gc.C) {
+	ctx, err := testing.RunCommand(c, machine.NewListProfilesCommandForTest(s.fake), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"db:\n"+
+		"  constraints: root-disk=102400M\n"+
+		"  root-disk-source: ssd\n"+
+		"web:\n"+
+		"  series: xenial\n"+
+		"  constraints: mem=4096M\n"+
+		"  cloudinit-userdata: |\n"+
+		"    packages: [curl]\n",
+	)
+}
+
+func (s *ListMachineProfilesSuite) TestListEmpty(c *gc.C) {
+	s.fake.profiles = nil
+	ctx, err := testing.RunCommand(c, machine.NewListProfilesCommandForTest(s.fake))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "No machine profiles to display.\n")
+}
+
+func (s *ListMachineProfilesSuite) TestListUnsupported(c *gc.C) {
+	s.fake.err = errors.NotSupportedf("machine profiles on this juju controller")
+	_, err := testing.RunCommand(c, machine.NewListProfilesCommandForTest(s.fake))
+	c.Assert(err, gc.ErrorMatches, "cannot list machine profiles: not supported by the API server")
+}
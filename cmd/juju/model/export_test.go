@@ -85,3 +85,11 @@ func NewDestroyCommandForTest(api DestroyEnvironmentAPI, store jujuclient.Client
 		modelcmd.ModelSkipFlags,
 	)
 }
+
+// NewShowFirewallCommandForTest returns a ShowFirewallCommand with the api provided as specified.
+func NewShowFirewallCommandForTest(api ShowFirewallAPI) cmd.Command {
+	cmd := &showFirewallCommand{
+		api: api,
+	}
+	return modelcmd.Wrap(cmd)
+}
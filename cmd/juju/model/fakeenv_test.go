@@ -7,6 +7,7 @@ import (
 	"github.com/juju/names"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/testing"
 )
 
@@ -28,6 +29,7 @@ func (s *fakeEnvSuite) SetUpTest(c *gc.C) {
 
 type fakeEnvAPI struct {
 	values      map[string]interface{}
+	info        params.ModelInfo
 	err         error
 	keys        []string
 	addUsers    []names.UserTag
@@ -42,6 +44,10 @@ func (f *fakeEnvAPI) ModelGet() (map[string]interface{}, error) {
 	return f.values, nil
 }
 
+func (f *fakeEnvAPI) ModelInfo() (params.ModelInfo, error) {
+	return f.info, nil
+}
+
 func (f *fakeEnvAPI) ModelSet(config map[string]interface{}) error {
 	f.values = config
 	return f.err
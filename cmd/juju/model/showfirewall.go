@@ -0,0 +1,125 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
+)
+
+// NewShowFirewallCommand returns a command to show the model's
+// firewall policy.
+func NewShowFirewallCommand() cmd.Command {
+	return modelcmd.Wrap(&showFirewallCommand{})
+}
+
+// showFirewallCommand shows the firewall policy of a model.
+type showFirewallCommand struct {
+	modelcmd.ModelCommandBase
+	api ShowFirewallAPI
+	out cmd.Output
+}
+
+// ShowFirewallAPI defines the API methods used by the show-firewall
+// command.
+type ShowFirewallAPI interface {
+	Close() error
+	ModelGet() (map[string]interface{}, error)
+	ModelInfo() (params.ModelInfo, error)
+}
+
+const showFirewallHelpDoc = `
+Shows the firewall mode of the model, and the policy for outbound traffic
+from the model's machines.
+
+The egress policy is set with the egress-policy and egress-allowed model
+config keys. A "deny" policy can only be set on models whose provider
+supports egress firewalling, and whose firewall-mode is not "none"; the
+"enforced" field reports whether the policy is applied.
+
+Example:
+
+  juju set-model-config egress-policy=deny egress-allowed=10.0.0.0/8,0.0.0.0/0:443/tcp
+  juju show-firewall
+`
+
+func (c *showFirewallCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-firewall",
+		Purpose: "show the model's firewall policy",
+		Doc:     strings.TrimSpace(showFirewallHelpDoc),
+	}
+}
+
+func (c *showFirewallCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *showFirewallCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *showFirewallCommand) getAPI() (ShowFirewallAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// firewallPolicy holds the firewall policy of a model, as shown
+// by show-firewall.
+type firewallPolicy struct {
+	FirewallMode string       `yaml:"firewall-mode" json:"firewall-mode"`
+	Egress       egressPolicy `yaml:"egress" json:"egress"`
+}
+
+type egressPolicy struct {
+	Policy   string   `yaml:"policy" json:"policy"`
+	Allowed  []string `yaml:"allowed,omitempty" json:"allowed,omitempty"`
+	Enforced bool     `yaml:"enforced" json:"enforced"`
+}
+
+func (c *showFirewallCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	attrs, err := client.ModelGet()
+	if err != nil {
+		return err
+	}
+	policy := firewallPolicy{
+		Egress: egressPolicy{Policy: config.EgressAllow},
+	}
+	policy.FirewallMode, _ = attrs["firewall-mode"].(string)
+	if mode, _ := attrs[config.EgressPolicyKey].(string); mode != "" {
+		policy.Egress.Policy = mode
+	}
+	allowed, _ := attrs[config.EgressAllowedKey].(string)
+	rules, err := network.ParseEgressRules(allowed)
+	if err != nil {
+		return errors.Annotatef(err, "invalid %s", config.EgressAllowedKey)
+	}
+	for _, rule := range rules {
+		policy.Egress.Allowed = append(policy.Egress.Allowed, rule.String())
+	}
+	info, err := client.ModelInfo()
+	if err != nil {
+		return err
+	}
+	// Allowing all outbound traffic needs no enforcement, and older
+	// controllers do not report it.
+	policy.Egress.Enforced = info.EgressEnforced || policy.Egress.Policy == config.EgressAllow
+	return c.out.Write(ctx, policy)
+}
@@ -0,0 +1,62 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/testing"
+)
+
+type ShowFirewallSuite struct {
+	fakeEnvSuite
+}
+
+var _ = gc.Suite(&ShowFirewallSuite{})
+
+func (s *ShowFirewallSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := model.NewShowFirewallCommandForTest(s.fake)
+	return testing.RunCommand(c, command, args...)
+}
+
+func (s *ShowFirewallSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(model.NewShowFirewallCommandForTest(s.fake), []string{"one"})
+	c.Check(err, gc.ErrorMatches, `unrecognized args: \["one"\]`)
+}
+
+func (s *ShowFirewallSuite) TestDefaultPolicy(c *gc.C) {
+	s.fake.values["firewall-mode"] = "instance"
+	context, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"firewall-mode: instance\n"+
+		"egress:\n"+
+		"  policy: allow\n"+
+		"  enforced: true\n")
+}
+
+func (s *ShowFirewallSuite) TestEgressPolicy(c *gc.C) {
+	s.fake.values["firewall-mode"] = "global"
+	s.fake.values["egress-policy"] = "deny"
+	s.fake.values["egress-allowed"] = "10.0.0.0/8, 0.0.0.0/0:443/tcp"
+	s.fake.info.EgressEnforced = true
+	context, err := s.run(c, "--format=json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `{"firewall-mode":"global","egress":{"policy":"deny","allowed":["10.0.0.0/8","0.0.0.0/0:443/tcp"],"enforced":true}}`+"\n")
+}
+
+func (s *ShowFirewallSuite) TestEgressPolicyNotEnforced(c *gc.C) {
+	s.fake.values["firewall-mode"] = "instance"
+	s.fake.values["egress-policy"] = "deny"
+	context, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"firewall-mode: instance\n"+
+		"egress:\n"+
+		"  policy: deny\n"+
+		"  enforced: false\n")
+}
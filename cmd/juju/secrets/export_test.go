@@ -0,0 +1,27 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+func NewListCommandForTest(api SecretsAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &listCommand{newAPIFunc: func() (SecretsAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+func NewShowCommandForTest(api SecretsAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &showCommand{newAPIFunc: func() (SecretsAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
@@ -0,0 +1,117 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const listCommandDoc = `
+List the secrets in the model. The content of the secrets is only
+included in the yaml and json formats, and only when --reveal is given;
+revealing secrets requires model or controller admin access.
+
+Examples:
+    juju secrets list
+    juju secrets list --format yaml --reveal
+`
+
+func newListCommand() cmd.Command {
+	cmd := &listCommand{}
+	cmd.newAPIFunc = func() (SecretsAPI, error) {
+		return cmd.NewSecretsAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// listCommand lists the secrets in a model.
+type listCommand struct {
+	SecretsCommandBase
+	out        cmd.Output
+	reveal     bool
+	newAPIFunc func() (SecretsAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *listCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "lists secrets",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SecretsCommandBase.SetFlags(f)
+	f.BoolVar(&c.reveal, "reveal", false, "include the content of the secrets")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSecretsTabular,
+	})
+}
+
+// Run implements Command.Run.
+func (c *listCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.ListSecrets(nil, c.reveal)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results) == 0 {
+		ctx.Infof("No secrets to display.")
+		return nil
+	}
+	output, err := formatSecretDetails(results)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatSecretsTabular returns a tabular summary of secrets. The
+// content of the secrets is never shown.
+func formatSecretsTabular(value interface{}) ([]byte, error) {
+	secrets, ok := value.(map[string]SecretInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", secrets, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintln(tw, "ID\tOWNER\tREVISION\tROTATE\tDESCRIPTION")
+	ids := make([]string, 0, len(secrets))
+	for id := range secrets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		s := secrets[id]
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", id, s.Owner, s.Revision, s.RotateInterval, s.Description)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	jujutesting "github.com/juju/juju/testing"
+)
+
+func TestAll(t *testing.T) {
+	gc.TestingT(t)
+}
+
+type baseSecretsSuite struct {
+	jujutesting.FakeJujuXDGDataHomeSuite
+	store   *jujuclienttesting.MemStore
+	mockAPI *mockSecretsAPI
+}
+
+func (s *baseSecretsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+
+	err := modelcmd.WriteCurrentController("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	s.store = jujuclienttesting.NewMemStore()
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = &jujuclient.ControllerAccounts{
+		CurrentAccount: "admin@local",
+	}
+	s.mockAPI = &mockSecretsAPI{}
+}
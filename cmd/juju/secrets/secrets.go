@@ -0,0 +1,107 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets provides the commands for inspecting the secrets
+// created by the units of a model.
+package secrets
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/api/secrets"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const secretsCmdDoc = `
+"juju secrets" is used to inspect the secrets created by the units
+of the Juju model. The content of secrets is only shown to model
+and controller administrators, when requested with --reveal.
+`
+
+const secretsCmdPurpose = "inspect secrets"
+
+// NewSuperCommand creates the secrets supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	secretscmd := cmd.NewSuperCommand(
+		cmd.SuperCommandParams{
+			Name:        "secrets",
+			Doc:         secretsCmdDoc,
+			UsagePrefix: "juju",
+			Purpose:     secretsCmdPurpose,
+		})
+	secretscmd.Register(newListCommand())
+	secretscmd.Register(newShowCommand())
+	return secretscmd
+}
+
+// SecretsAPI defines the API methods that the secrets commands use.
+type SecretsAPI interface {
+	Close() error
+	ListSecrets(ids []string, reveal bool) ([]params.ListSecretResult, error)
+}
+
+// SecretsCommandBase is a helper base structure that has a method to
+// get the secrets client.
+type SecretsCommandBase struct {
+	modelcmd.ModelCommandBase
+}
+
+// NewSecretsAPI returns a secrets api for the root api endpoint that
+// the model command returns.
+func (c *SecretsCommandBase) NewSecretsAPI() (SecretsAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return secrets.NewClient(root), nil
+}
+
+// SecretInfo defines the serialization behaviour of a secret.
+type SecretInfo struct {
+	Owner          string            `yaml:"owner" json:"owner"`
+	Description    string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Revision       int               `yaml:"revision" json:"revision"`
+	Grants         []string          `yaml:"grants,omitempty" json:"grants,omitempty"`
+	RotateInterval string            `yaml:"rotate-interval,omitempty" json:"rotate-interval,omitempty"`
+	NextRotateTime string            `yaml:"next-rotate-time,omitempty" json:"next-rotate-time,omitempty"`
+	Created        string            `yaml:"created" json:"created"`
+	Updated        string            `yaml:"updated" json:"updated"`
+	Data           map[string]string `yaml:"data,omitempty" json:"data,omitempty"`
+}
+
+// formatSecretDetails returns the secret details keyed on their IDs,
+// or the errors encountered retrieving them.
+func formatSecretDetails(results []params.ListSecretResult) (map[string]SecretInfo, error) {
+	var errs params.ErrorResults
+	output := make(map[string]SecretInfo)
+	for _, result := range results {
+		if result.Error != nil {
+			errs.Results = append(errs.Results, params.ErrorResult{Error: result.Error})
+			continue
+		}
+		details := result.Result
+		info := SecretInfo{
+			Owner:       details.Owner,
+			Description: details.Description,
+			Revision:    details.Revision,
+			Grants:      details.Grants,
+			Created:     common.FormatTime(&details.Created, true),
+			Updated:     common.FormatTime(&details.Updated, true),
+			Data:        details.Data,
+		}
+		if details.RotateInterval > 0 {
+			info.RotateInterval = details.RotateInterval.String()
+		}
+		if details.NextRotateTime != nil {
+			info.NextRotateTime = common.FormatTime(details.NextRotateTime, true)
+		}
+		output[details.ID] = info
+	}
+	if len(errs.Results) > 0 {
+		return nil, errs.Combine()
+	}
+	return output, nil
+}
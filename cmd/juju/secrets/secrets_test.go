@@ -0,0 +1,147 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"time"
+
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/secrets"
+	"github.com/juju/juju/testing"
+)
+
+type mockSecretsAPI struct {
+	gitjujutesting.Stub
+	results []params.ListSecretResult
+}
+
+func (m *mockSecretsAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockSecretsAPI) ListSecrets(ids []string, reveal bool) ([]params.ListSecretResult, error) {
+	m.MethodCall(m, "ListSecrets", ids, reveal)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return m.results, nil
+	}
+	var results []params.ListSecretResult
+	for _, id := range ids {
+		result := params.ListSecretResult{
+			Error: &params.Error{Code: params.CodeNotFound, Message: `secret "` + id + `" not found`},
+		}
+		for _, r := range m.results {
+			if r.Result.ID == id {
+				result = r
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+type secretsSuite struct {
+	baseSecretsSuite
+}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func (s *secretsSuite) SetUpTest(c *gc.C) {
+	s.baseSecretsSuite.SetUpTest(c)
+	created := time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC)
+	next := created.Add(time.Hour)
+	s.mockAPI.results = []params.ListSecretResult{{
+		Result: &params.SecretDetails{
+			ID:             "2",
+			Owner:          "service-mysql",
+			Description:    "root password",
+			Revision:       3,
+			Grants:         []string{"unit-wordpress-0"},
+			RotateInterval: time.Hour,
+			NextRotateTime: &next,
+			Created:        created,
+			Updated:        created,
+			Data:           map[string]string{"password": "sekrit"},
+		},
+	}, {
+		Result: &params.SecretDetails{
+			ID:       "1",
+			Owner:    "service-wordpress",
+			Revision: 1,
+			Created:  created,
+			Updated:  created,
+		},
+	}}
+}
+
+func (s *secretsSuite) TestListTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, secrets.NewListCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"ID  OWNER              REVISION  ROTATE  DESCRIPTION\n"+
+		"1   service-wordpress  1                 \n"+
+		"2   service-mysql      3         1h0m0s  root password\n",
+	)
+	s.mockAPI.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ListSecrets", []interface{}{[]string(nil), false}},
+		{"Close", nil},
+	})
+}
+
+func (s *secretsSuite) TestListReveal(c *gc.C) {
+	_, err := testing.RunCommand(c, secrets.NewListCommandForTest(s.mockAPI, s.store), "--reveal")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCall(c, 0, "ListSecrets", []string(nil), true)
+}
+
+func (s *secretsSuite) TestListEmpty(c *gc.C) {
+	s.mockAPI.results = nil
+	ctx, err := testing.RunCommand(c, secrets.NewListCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "No secrets to display.\n")
+}
+
+func (s *secretsSuite) TestListPermissionDenied(c *gc.C) {
+	s.mockAPI.SetErrors(&params.Error{Code: params.CodeUnauthorized, Message: "permission denied"})
+	_, err := testing.RunCommand(c, secrets.NewListCommandForTest(s.mockAPI, s.store), "--reveal")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *secretsSuite) TestShow(c *gc.C) {
+	ctx, err := testing.RunCommand(c, secrets.NewShowCommandForTest(s.mockAPI, s.store), "2", "--reveal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"\"2\":\n"+
+		"  owner: service-mysql\n"+
+		"  description: root password\n"+
+		"  revision: 3\n"+
+		"  grants:\n"+
+		"  - unit-wordpress-0\n"+
+		"  rotate-interval: 1h0m0s\n"+
+		"  next-rotate-time: 2016-04-01 13:00:00Z\n"+
+		"  created: 2016-04-01 12:00:00Z\n"+
+		"  updated: 2016-04-01 12:00:00Z\n"+
+		"  data:\n"+
+		"    password: sekrit\n",
+	)
+	s.mockAPI.CheckCall(c, 0, "ListSecrets", []string{"2"}, true)
+}
+
+func (s *secretsSuite) TestShowNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, secrets.NewShowCommandForTest(s.mockAPI, s.store), "1", "666")
+	c.Assert(err, gc.ErrorMatches, `secret "666" not found`)
+}
+
+func (s *secretsSuite) TestShowNoArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, secrets.NewShowCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, gc.ErrorMatches, `must specify secret id\(s\)`)
+}
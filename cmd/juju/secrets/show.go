@@ -0,0 +1,84 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const showCommandDoc = `
+Show the details of one or more secrets, specified by ID. The content of
+the secrets is only included when --reveal is given; revealing secrets
+requires model or controller admin access.
+
+Examples:
+    juju secrets show 1
+    juju secrets show 1 2 --reveal
+`
+
+func newShowCommand() cmd.Command {
+	cmd := &showCommand{}
+	cmd.newAPIFunc = func() (SecretsAPI, error) {
+		return cmd.NewSecretsAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// showCommand shows the details of secrets.
+type showCommand struct {
+	SecretsCommandBase
+	out        cmd.Output
+	ids        []string
+	reveal     bool
+	newAPIFunc func() (SecretsAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *showCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show",
+		Args:    "<secret id> ...",
+		Purpose: "shows the details of secrets",
+		Doc:     showCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SecretsCommandBase.SetFlags(f)
+	f.BoolVar(&c.reveal, "reveal", false, "include the content of the secrets")
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *showCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("must specify secret id(s)")
+	}
+	c.ids = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *showCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.ListSecrets(c.ids, c.reveal)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	output, err := formatSecretDetails(results)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, output)
+}
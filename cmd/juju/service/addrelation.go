@@ -26,11 +26,25 @@ type addRelationCommand struct {
 	Endpoints []string
 }
 
+var addRelationHelp = `
+Adds a relation between endpoints of two services. Either service may be
+given as the URL of a service offered from another model on the controller
+(see "juju help offer"), in which case the offer is consumed into this
+model as a remote service named after the offer and the offering model
+(e.g. "db-prod"). You must have access to the offering model.
+
+Examples:
+    juju add-relation wordpress mysql
+    juju add-relation wordpress admin@local/prod.db
+
+`
+
 func (c *addRelationCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "add-relation",
 		Args:    "<service1>[:<relation name1>] <service2>[:<relation name2>]",
 		Purpose: "add a relation between two services",
+		Doc:     addRelationHelp,
 	}
 }
 
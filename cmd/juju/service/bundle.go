@@ -17,6 +17,7 @@ import (
 
 	"github.com/juju/juju/api"
 	apiannotations "github.com/juju/juju/api/annotations"
+	apimachinemanager "github.com/juju/juju/api/machinemanager"
 	apiservice "github.com/juju/juju/api/service"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
@@ -27,6 +28,10 @@ import (
 	"github.com/juju/juju/storage"
 )
 
+// machineProfileAnnotation is the annotation with which services and
+// machines in a bundle name the machine profile applied to them.
+const machineProfileAnnotation = "machine-profile"
+
 var watchAll = func(c *api.Client) (allWatcher, error) {
 	return c.WatchAll()
 }
@@ -96,6 +101,11 @@ func deployBundle(
 		return errors.Annotate(err, "cannot get annotations client")
 	}
 
+	machineManagerClient, err := serviceDeployer.newMachineManagerAPIClient()
+	if err != nil {
+		return errors.Annotate(err, "cannot get machine manager client")
+	}
+
 	// Instantiate the bundle handler.
 	h := &bundleHandler{
 		changes:           changes,
@@ -103,6 +113,7 @@ func deployBundle(
 		client:            client,
 		serviceClient:     serviceClient,
 		annotationsClient: annotationsClient,
+		machineManager:    machineManagerClient,
 		serviceDeployer:   serviceDeployer,
 		bundleStorage:     bundleStorage,
 		csclient:          csclient,
@@ -165,6 +176,8 @@ type bundleHandler struct {
 	serviceClient *apiservice.Client
 	// annotationsClient is used to interact with annotations.
 	annotationsClient *apiannotations.Client
+	// machineManager is used to add machines with machine profiles.
+	machineManager *apimachinemanager.Client
 	// serviceDeployer is used to deploy services.
 	serviceDeployer *serviceDeployer
 	// bundleStorage contains a mapping of service-specific storage
@@ -266,6 +279,7 @@ func (h *bundleHandler) addService(id string, p bundlechanges.AddServiceParams)
 		constraints:   cons,
 		storage:       storageConstraints,
 		spaceBindings: p.EndpointBindings,
+		profile:       h.machineProfile(id, bundlechanges.ServiceType),
 	}); err == nil {
 		h.log.Infof("service %s deployed (charm: %s)", p.Service, ch)
 		return nil
@@ -346,6 +360,7 @@ func (h *bundleHandler) addMachine(id string, p bundlechanges.AddMachineParams)
 		Constraints: cons,
 		Series:      p.Series,
 		Jobs:        []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Profile:     h.machineProfile(id, bundlechanges.MachineType),
 	}
 	if p.ContainerType != "" {
 		containerType, err := instance.ParseContainerType(p.ContainerType)
@@ -360,7 +375,13 @@ func (h *bundleHandler) addMachine(id string, p bundlechanges.AddMachineParams)
 			}
 		}
 	}
-	r, err := h.client.AddMachines([]params.AddMachineParams{machineParams})
+	var r []params.AddMachinesResult
+	if machineParams.Profile != "" {
+		// Only the MachineManager facade applies machine profiles.
+		r, err = h.machineManager.AddMachines([]params.AddMachineParams{machineParams})
+	} else {
+		r, err = h.client.AddMachines([]params.AddMachineParams{machineParams})
+	}
 	if err != nil {
 		return errors.Annotatef(err, "cannot create machine for holding %s", msg)
 	}
@@ -487,6 +508,20 @@ func (h *bundleHandler) setAnnotations(id string, p bundlechanges.SetAnnotations
 	return nil
 }
 
+// machineProfile returns the machine profile named by the
+// "machine-profile" annotation of the service or machine created by the
+// change with the given id, or "" if there is none.
+func (h *bundleHandler) machineProfile(changeId string, entityType bundlechanges.EntityType) string {
+	for _, change := range h.changes {
+		change, ok := change.(*bundlechanges.SetAnnotationsChange)
+		if !ok || change.Params.EntityType != entityType || change.Params.Id != "$"+changeId {
+			continue
+		}
+		return change.Params.Annotations[machineProfileAnnotation]
+	}
+	return ""
+}
+
 // servicesForMachineChange returns the names of the services for which an
 // "addMachine" change is required, as adding machines is required to place
 // units, and units belong to services.
@@ -1439,6 +1439,55 @@ deployment of bundle "local:bundle/example-0" completed`
 	})
 }
 
+func (s *deployRepoCharmStoreSuite) TestDeployBundleMachineProfiles(c *gc.C) {
+	_, err := s.State.AddMachineProfile(state.AddMachineProfileArgs{
+		Name:              "web",
+		CloudInitUserData: "packages: [curl]",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachineProfile(state.AddMachineProfileArgs{
+		Name:        "cache",
+		Constraints: constraints.MustParse("mem=8G"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.UploadCharm(c, s.client, "trusty/django-42", "dummy")
+	testcharms.UploadCharm(c, s.client, "trusty/mem-47", "dummy")
+	_, err = s.deployBundleYAML(c, `
+        services:
+            django:
+                charm: cs:django
+                num_units: 1
+                annotations:
+                    machine-profile: web
+            memcached:
+                charm: trusty/mem-47
+                num_units: 1
+                to: [1]
+        machines:
+            1:
+                annotations:
+                    machine-profile: cache
+    `)
+	c.Assert(err, jc.ErrorIsNil)
+	svc, err := s.State.Service("django")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.CloudInitUserData(), gc.Equals, "packages: [curl]")
+	svc, err = s.State.Service("memcached")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := svc.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+	for _, u := range units {
+		id, err := u.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		cons, err := m.Constraints()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=8G"))
+	}
+}
+
 type mockAllWatcher struct {
 	next func() []multiwatcher.Delta
 }
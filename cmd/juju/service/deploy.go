@@ -19,6 +19,7 @@ import (
 
 	"github.com/juju/juju/api"
 	apiannotations "github.com/juju/juju/api/annotations"
+	apimachinemanager "github.com/juju/juju/api/machinemanager"
 	apiservice "github.com/juju/juju/api/service"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -63,6 +64,10 @@ type DeployCommand struct {
 	RepoPath     string // defaults to JUJU_REPOSITORY
 	BindToSpaces string
 
+	// Profile names the machine profile applied to the machines
+	// created for the service's units.
+	Profile string
+
 	// TODO(axw) move this to UnitCommandBase once we support --storage
 	// on add-unit too.
 	//
@@ -139,6 +144,12 @@ flag.  When used with deploy, service-specific constraints are set so that later
 machines provisioned with add-unit will use the same constraints (unless changed
 by set-constraints).
 
+A machine profile, added with add-machine-profile, may be applied to the
+machines created for the service's units by specifying the --profile flag.
+Constraints given with --constraints take precedence over those of the
+profile, as does a series given with --series. Services and machines in a
+bundle name their profile with a "machine-profile" annotation.
+
 Resources may be uploaded at deploy time by specifying the --resource flag.
 Following the resource flag should be name=filepath pair.  This flag may be
 repeated more than once to upload more than one resource.
@@ -230,7 +241,7 @@ func (c *DeployCommand) Info() *cmd.Info {
 var (
 	// charmOnlyFlags and bundleOnlyFlags are used to validate flags based on
 	// whether we are deploying a charm or a bundle.
-	charmOnlyFlags  = []string{"bind", "config", "constraints", "force", "n", "networks", "num-units", "series", "to", "u", "upgrade", "resource"}
+	charmOnlyFlags  = []string{"bind", "config", "constraints", "force", "n", "networks", "num-units", "profile", "series", "to", "u", "upgrade", "resource"}
 	bundleOnlyFlags = []string{}
 )
 
@@ -250,6 +261,7 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(storageFlag{&c.Storage, &c.BundleStorage}, "storage", "charm storage constraints")
 	f.Var(stringMap{&c.Resources}, "resource", "resource to be uploaded to the controller")
 	f.StringVar(&c.BindToSpaces, "bind", "", "Configure service endpoint bindings to spaces")
+	f.StringVar(&c.Profile, "profile", "", "the machine profile applied to the service's machines")
 
 	for _, step := range c.Steps {
 		step.SetFlags(f)
@@ -298,6 +310,38 @@ func (c *DeployCommand) newAnnotationsAPIClient() (*apiannotations.Client, error
 	return apiannotations.NewClient(root), nil
 }
 
+func (c *DeployCommand) newMachineManagerAPIClient() (*apimachinemanager.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apimachinemanager.NewClient(root), nil
+}
+
+// requestedSeries returns the series requested for the deployment: the
+// one given with --series or, failing that, the series of the machine
+// profile given with --profile.
+func (c *DeployCommand) requestedSeries() (string, error) {
+	if c.Series != "" || c.Profile == "" {
+		return c.Series, nil
+	}
+	client, err := c.newMachineManagerAPIClient()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer client.Close()
+	profiles, err := client.ListMachineProfiles()
+	if err != nil {
+		return "", errors.Annotate(err, "cannot get machine profiles")
+	}
+	for _, profile := range profiles {
+		if profile.Name == c.Profile {
+			return profile.Series, nil
+		}
+	}
+	return "", errors.NotFoundf("machine profile %q", c.Profile)
+}
+
 type ModelConfigGetter interface {
 	ModelGet() (map[string]interface{}, error)
 }
@@ -313,7 +357,11 @@ var getClientConfig = func(client ModelConfigGetter) (*config.Config, error) {
 }
 
 func (c *DeployCommand) deployCharmOrBundle(ctx *cmd.Context, client *api.Client) error {
-	deployer := serviceDeployer{ctx, c.newServiceAPIClient, c.newAnnotationsAPIClient}
+	deployer := serviceDeployer{ctx, c.newServiceAPIClient, c.newAnnotationsAPIClient, c.newMachineManagerAPIClient}
+	requestedSeries, err := c.requestedSeries()
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	// We may have been given a local bundle file.
 	bundlePath := c.CharmOrBundle
@@ -329,7 +377,7 @@ func (c *DeployCommand) deployCharmOrBundle(ctx *cmd.Context, client *api.Client
 	// If not a bundle then maybe a local charm.
 	if err != nil {
 		// Charm may have been supplied via a path reference.
-		ch, curl, charmErr := charmrepo.NewCharmAtPathForceSeries(c.CharmOrBundle, c.Series, c.Force)
+		ch, curl, charmErr := charmrepo.NewCharmAtPathForceSeries(c.CharmOrBundle, requestedSeries, c.Force)
 		if charmErr == nil {
 			if curl, charmErr = client.AddLocalCharm(curl, ch); charmErr != nil {
 				return charmErr
@@ -376,7 +424,7 @@ func (c *DeployCommand) deployCharmOrBundle(ctx *cmd.Context, client *api.Client
 		// Charm or bundle has been supplied as a URL so we resolve and deploy using the store.
 		charmOrBundleURL, supportedSeries, repo, err = resolveCharmStoreEntityURL(resolveCharmStoreEntityParams{
 			urlStr:          c.CharmOrBundle,
-			requestedSeries: c.Series,
+			requestedSeries: requestedSeries,
 			forceSeries:     c.Force,
 			csParams:        csClient.params,
 			repoPath:        repoPath,
@@ -417,7 +465,7 @@ func (c *DeployCommand) deployCharmOrBundle(ctx *cmd.Context, client *api.Client
 		return errors.Errorf("Flags provided but not supported when deploying a charm: %s.", strings.Join(flags, ", "))
 	}
 	// Get the series to use.
-	series, message, err := charmSeries(c.Series, charmOrBundleURL.Series, supportedSeries, c.Force, conf)
+	series, message, err := charmSeries(requestedSeries, charmOrBundleURL.Series, supportedSeries, c.Force, conf)
 	if charm.IsUnsupportedSeriesError(err) {
 		return errors.Errorf("%v. Use --force to deploy the charm anyway.", err)
 	}
@@ -513,6 +561,9 @@ func (c *DeployCommand) deployCharm(
 		if !constraints.IsEmpty(&c.Constraints) {
 			return errors.New("cannot use --constraints with subordinate service")
 		}
+		if c.Profile != "" {
+			return errors.New("cannot use --profile with subordinate service")
+		}
 		if numUnits == 1 && c.PlacementSpec == "" {
 			numUnits = 0
 		} else {
@@ -585,6 +636,7 @@ func (c *DeployCommand) deployCharm(
 		storage:       c.Storage,
 		spaceBindings: c.Bindings,
 		resources:     ids,
+		profile:       c.Profile,
 	}
 	if err := deployer.serviceDeploy(params); err != nil {
 		return err
@@ -623,10 +675,10 @@ func (c *DeployCommand) handleResources(serviceName string, metaResources map[st
 const parseBindErrorPrefix = "--bind must be in the form '[<default-space>] [<relation-name>=<space>] [<relation2-name>=<space2>] ...]'. "
 
 // parseBind parses the --bind option. Valid forms are:
-// * relation-name=space-name
-// * space-name
-// * The above in a space separated list to specify multiple bindings,
-//   e.g. "rel1=space1 rel2=space2 space3"
+//   - relation-name=space-name
+//   - space-name
+//   - The above in a space separated list to specify multiple bindings,
+//     e.g. "rel1=space1 rel2=space2 space3"
 func (c *DeployCommand) parseBind() error {
 	bindings := make(map[string]string)
 	if c.BindToSpaces == "" {
@@ -676,12 +728,16 @@ type serviceDeployParams struct {
 	storage       map[string]storage.Constraints
 	spaceBindings map[string]string
 	resources     map[string]string
+	profile       string
 }
 
 type serviceDeployer struct {
 	ctx                     *cmd.Context
 	newServiceAPIClient     func() (*apiservice.Client, error)
 	newAnnotationsAPIClient func() (*apiannotations.Client, error)
+	// newMachineManagerAPIClient is used to add bundle machines
+	// with machine profiles.
+	newMachineManagerAPIClient func() (*apimachinemanager.Client, error)
 }
 
 func (c *serviceDeployer) serviceDeploy(args serviceDeployParams) error {
@@ -715,6 +771,7 @@ func (c *serviceDeployer) serviceDeploy(args serviceDeployParams) error {
 		args.storage,
 		args.spaceBindings,
 		args.resources,
+		args.profile,
 	}
 
 	return serviceClient.Deploy(clientArgs)
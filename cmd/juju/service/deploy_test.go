@@ -280,6 +280,43 @@ func (s *DeploySuite) TestConstraints(c *gc.C) {
 	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=2G cpu-cores=2"))
 }
 
+func (s *DeploySuite) TestProfile(c *gc.C) {
+	_, err := s.State.AddMachineProfile(state.AddMachineProfileArgs{
+		Name:              "web",
+		Constraints:       constraints.MustParse("mem=4G root-disk=10G"),
+		CloudInitUserData: "packages: [curl]",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err = runDeploy(c, "local:dummy", "--profile", "web", "--constraints", "mem=2G")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/dummy-1")
+	service, _ := s.AssertService(c, "dummy", curl, 1, 0)
+	cons, err := service.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=2G root-disk=10G"))
+	c.Assert(service.CloudInitUserData(), gc.Equals, "packages: [curl]")
+}
+
+func (s *DeploySuite) TestProfileSeries(c *gc.C) {
+	_, err := s.State.AddMachineProfile(state.AddMachineProfileArgs{
+		Name:   "web",
+		Series: "trusty",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	path := testcharms.Repo.ClonedDirPath(s.SeriesPath, "multi-series")
+	err = runDeploy(c, path, "--profile", "web")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/multi-series-1")
+	s.AssertService(c, "multi-series", curl, 1, 0)
+}
+
+func (s *DeploySuite) TestProfileNotFound(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "--profile", "web")
+	c.Assert(err, gc.ErrorMatches, `.*machine profile "web" not found`)
+}
+
 func (s *DeploySuite) TestResources(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
 	dir := c.MkDir()
@@ -373,6 +410,12 @@ func (s *DeploySuite) TestSubordinateConstraints(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cannot use --constraints with subordinate service")
 }
 
+func (s *DeploySuite) TestSubordinateProfile(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "logging")
+	err := runDeploy(c, "local:logging", "--profile", "web")
+	c.Assert(err, gc.ErrorMatches, "cannot use --profile with subordinate service")
+}
+
 func (s *DeploySuite) TestNumUnits(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
 	err := runDeploy(c, "local:dummy", "-n", "13")
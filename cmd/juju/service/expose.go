@@ -4,8 +4,11 @@
 package service
 
 import (
+	"net"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api/service"
 	"github.com/juju/juju/cmd/juju/block"
@@ -20,13 +23,31 @@ func NewExposeCommand() cmd.Command {
 // exposeCommand is responsible exposing services.
 type exposeCommand struct {
 	modelcmd.ModelCommandBase
-	ServiceName string
+	ServiceName  string
+	ToCIDRs      []string
+	LoadBalancer bool
 }
 
 var jujuExposeHelp = `
 Adjusts firewall rules and similar security mechanisms of the provider, to
 allow the service to be accessed on its public address.
 
+By default the service's open ports may be accessed from any address. The
+--to-cidrs option restricts access to the addresses within the given
+comma-separated CIDRs; exposing the service again without the option
+removes the restriction.
+
+The --load-balancer option asks the provider to create a load balancer in
+front of the service, which forwards the service's open ports to its units
+as they come and go. The load balancer's address is shown in the service's
+status once it has been created. Not all providers support load balancers;
+on those that do not, exposing a service through one fails.
+
+Examples:
+    juju expose wordpress
+    juju expose wordpress --to-cidrs 10.0.0.0/8,192.168.1.0/24
+    juju expose wordpress --load-balancer
+
 `
 
 func (c *exposeCommand) Info() *cmd.Info {
@@ -38,17 +59,28 @@ func (c *exposeCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *exposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewStringsValue(nil, &c.ToCIDRs), "to-cidrs", "only expose the service to addresses within these comma-separated CIDRs")
+	f.BoolVar(&c.LoadBalancer, "load-balancer", false, "expose the service through a provider load balancer")
+}
+
 func (c *exposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no service name specified")
 	}
 	c.ServiceName = args[0]
+	for _, cidr := range c.ToCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("CIDR %q", cidr)
+		}
+	}
 	return cmd.CheckEmpty(args[1:])
 }
 
 type serviceExposeAPI interface {
 	Close() error
-	Expose(serviceName string) error
+	Expose(serviceName string, toCIDRs ...string) error
+	ExposeLoadBalanced(serviceName string, toCIDRs ...string) error
 	Unexpose(serviceName string) error
 }
 
@@ -68,5 +100,10 @@ func (c *exposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.Expose(c.ServiceName), block.BlockChange)
+	if c.LoadBalancer {
+		err = client.ExposeLoadBalanced(c.ServiceName, c.ToCIDRs...)
+	} else {
+		err = client.Expose(c.ServiceName, c.ToCIDRs...)
+	}
+	return block.ProcessBlockedError(err, block.BlockChange)
 }
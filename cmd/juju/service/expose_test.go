@@ -60,6 +60,37 @@ func (s *ExposeSuite) TestExpose(c *gc.C) {
 	})
 }
 
+func (s *ExposeSuite) TestExposeToCIDRs(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-service-name", "--to-cidrs", "192.168.1.0/24,10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-service-name")
+	svc, err := s.State.Service("some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "192.168.1.0/24"})
+}
+
+func (s *ExposeSuite) TestExposeLoadBalancer(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-service-name", "--load-balancer")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-service-name")
+	svc, err := s.State.Service("some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsLoadBalanced(), jc.IsTrue)
+}
+
+func (s *ExposeSuite) TestExposeInvalidCIDR(c *gc.C) {
+	err := runExpose(c, "some-service-name", "--to-cidrs", "10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `CIDR "10.0.0.0" not valid`)
+}
+
 func (s *ExposeSuite) TestBlockExpose(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
 	err := runDeploy(c, "local:dummy", "some-service-name")
@@ -0,0 +1,109 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// NewOfferCommand returns a command to offer a service's endpoints to
+// other models.
+func NewOfferCommand() cmd.Command {
+	return modelcmd.Wrap(&offerCommand{})
+}
+
+// offerCommand makes a service's endpoints available for services in
+// other models to relate to.
+type offerCommand struct {
+	modelcmd.ModelCommandBase
+	ServiceName string
+	Endpoints   []string
+	OfferName   string
+}
+
+var offerHelp = `
+Makes the relation endpoints of a service available for services in other
+models on the same controller to relate to. If no endpoints are named, all
+of the service's endpoints that can be related to across models are
+offered. The offer is named after the service unless an offer name is
+given.
+
+Services in other models relate to the offer using its URL, which has the
+form <model owner>/<model name>.<offer name>:
+
+    juju add-relation wordpress admin@local/prod.db
+
+Examples:
+    juju offer mysql
+    juju offer mysql:server db
+    juju offer haproxy:website,reverseproxy
+
+`
+
+func (c *offerCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "offer",
+		Args:    "<service>[:<endpoint>[,<endpoint>...]] [<offer name>]",
+		Purpose: "offer a service's endpoints to other models",
+		Doc:     offerHelp,
+	}
+}
+
+func (c *offerCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	parts := strings.SplitN(args[0], ":", 2)
+	c.ServiceName = parts[0]
+	if !names.IsValidService(c.ServiceName) {
+		return errors.NotValidf("service name %q", c.ServiceName)
+	}
+	if len(parts) == 2 {
+		for _, ep := range strings.Split(parts[1], ",") {
+			if ep == "" {
+				return errors.Errorf("empty endpoint name in %q", args[0])
+			}
+			c.Endpoints = append(c.Endpoints, ep)
+		}
+	}
+	if len(args) == 1 {
+		return nil
+	}
+	c.OfferName = args[1]
+	if !names.IsValidService(c.OfferName) {
+		return errors.NotValidf("offer name %q", c.OfferName)
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+type serviceOfferAPI interface {
+	Close() error
+	Offer(service string, endpoints []string, offerName string) error
+}
+
+func (c *offerCommand) getAPI() (serviceOfferAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apiservice.NewClient(root), nil
+}
+
+func (c *offerCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.Offer(c.ServiceName, c.Endpoints, c.OfferName)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
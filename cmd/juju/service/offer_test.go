@@ -0,0 +1,100 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/common"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testcharms"
+	"github.com/juju/juju/testing"
+)
+
+type OfferSuite struct {
+	jujutesting.RepoSuite
+	common.CmdBlockHelper
+}
+
+func (s *OfferSuite) SetUpTest(c *gc.C) {
+	s.RepoSuite.SetUpTest(c)
+	s.CmdBlockHelper = common.NewCmdBlockHelper(s.APIState)
+	c.Assert(s.CmdBlockHelper, gc.NotNil)
+	s.AddCleanup(func(*gc.C) { s.CmdBlockHelper.Close() })
+}
+
+var _ = gc.Suite(&OfferSuite{})
+
+func runOffer(c *gc.C, args ...string) error {
+	_, err := testing.RunCommand(c, NewOfferCommand(), args...)
+	return err
+}
+
+func (s *OfferSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		service   string
+		endpoints []string
+		offerName string
+		err       string
+	}{{
+		err: "no service name specified",
+	}, {
+		args:    []string{"mysql"},
+		service: "mysql",
+	}, {
+		args:      []string{"haproxy:website,reverseproxy", "proxy"},
+		service:   "haproxy",
+		endpoints: []string{"website", "reverseproxy"},
+		offerName: "proxy",
+	}, {
+		args: []string{"my_sql"},
+		err:  `service name "my_sql" not valid`,
+	}, {
+		args: []string{"mysql:server,"},
+		err:  `empty endpoint name in "mysql:server,"`,
+	}, {
+		args: []string{"mysql", "db_1"},
+		err:  `offer name "db_1" not valid`,
+	}, {
+		args: []string{"mysql", "db", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		offerCmd := &offerCommand{}
+		err := offerCmd.Init(test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(offerCmd.ServiceName, gc.Equals, test.service)
+		c.Check(offerCmd.Endpoints, jc.DeepEquals, test.endpoints)
+		c.Check(offerCmd.OfferName, gc.Equals, test.offerName)
+	}
+}
+
+func (s *OfferSuite) TestOffer(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	err := runDeploy(c, "local:mysql", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runOffer(c, "mysql:server", "db")
+	c.Assert(err, jc.ErrorIsNil)
+	offer, err := s.State.ServiceOffer("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.ServiceName(), gc.Equals, "mysql")
+	c.Assert(offer.EndpointNames(), jc.DeepEquals, []string{"server"})
+}
+
+func (s *OfferSuite) TestBlockOffer(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	err := runDeploy(c, "local:mysql", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.BlockAllChanges(c, "TestBlockOffer")
+	err = runOffer(c, "mysql")
+	s.AssertBlocked(c, err, ".*TestBlockOffer.*")
+}
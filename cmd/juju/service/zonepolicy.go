@@ -0,0 +1,116 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/instance"
+)
+
+// NewSetZonePolicyCommand returns a command to set the availability
+// zone placement policy of a service.
+func NewSetZonePolicyCommand() cmd.Command {
+	return modelcmd.Wrap(&setZonePolicyCommand{})
+}
+
+// setZonePolicyCommand sets the policy used to place a service's units
+// into availability zones.
+type setZonePolicyCommand struct {
+	modelcmd.ModelCommandBase
+	ServiceName string
+	Policy      string
+	Reset       bool
+}
+
+var setZonePolicyHelp = `
+Sets the policy used to place the machines created for a service's units
+into the provider's availability zones. The policy is one of:
+
+    spread               start each new machine in one of the zones
+                         holding the fewest of the service's units,
+                         avoiding zones in which starting an instance
+                         has recently failed
+    pack                 keep the service's units in as few zones as
+                         possible
+    zones=<zone>[,...]   only place units in the listed zones, spreading
+                         them across those zones
+
+The policy applies to units added after it is set; existing units are not
+moved. Units placed explicitly with --to are not affected. The zone of
+each unit is shown by juju status.
+
+The --reset option removes the service's policy, restoring the provider's
+default placement.
+
+Examples:
+    juju set-zone-policy mysql spread
+    juju set-zone-policy cassandra zones=us-east-1a,us-east-1b
+    juju set-zone-policy mysql --reset
+
+`
+
+func (c *setZonePolicyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-zone-policy",
+		Args:    "<service> [<policy>]",
+		Purpose: "set the availability zone placement policy of a service",
+		Doc:     setZonePolicyHelp,
+	}
+}
+
+func (c *setZonePolicyCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Reset, "reset", false, "remove the service's zone policy")
+}
+
+func (c *setZonePolicyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	c.ServiceName = args[0]
+	if !names.IsValidService(c.ServiceName) {
+		return errors.NotValidf("service name %q", c.ServiceName)
+	}
+	args = args[1:]
+	if c.Reset {
+		return cmd.CheckEmpty(args)
+	}
+	if len(args) == 0 {
+		return errors.New("no zone policy specified")
+	}
+	if _, err := instance.ParseZonePolicy(args[0]); err != nil {
+		return err
+	}
+	c.Policy = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+type serviceZonePolicyAPI interface {
+	Close() error
+	SetZonePolicy(service, policy string) error
+}
+
+func (c *setZonePolicyCommand) getAPI() (serviceZonePolicyAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apiservice.NewClient(root), nil
+}
+
+func (c *setZonePolicyCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.SetZonePolicy(c.ServiceName, c.Policy)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,112 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/instance"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testcharms"
+	"github.com/juju/juju/testing"
+)
+
+type SetZonePolicySuite struct {
+	jujutesting.RepoSuite
+	common.CmdBlockHelper
+}
+
+func (s *SetZonePolicySuite) SetUpTest(c *gc.C) {
+	s.RepoSuite.SetUpTest(c)
+	s.CmdBlockHelper = common.NewCmdBlockHelper(s.APIState)
+	c.Assert(s.CmdBlockHelper, gc.NotNil)
+	s.AddCleanup(func(*gc.C) { s.CmdBlockHelper.Close() })
+}
+
+var _ = gc.Suite(&SetZonePolicySuite{})
+
+func runSetZonePolicy(c *gc.C, args ...string) error {
+	_, err := testing.RunCommand(c, NewSetZonePolicyCommand(), args...)
+	return err
+}
+
+func (s *SetZonePolicySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args    []string
+		service string
+		policy  string
+		err     string
+	}{{
+		err: "no service name specified",
+	}, {
+		args: []string{"mysql"},
+		err:  "no zone policy specified",
+	}, {
+		args:    []string{"mysql", "zones=a,b"},
+		service: "mysql",
+		policy:  "zones=a,b",
+	}, {
+		args:    []string{"--reset", "mysql"},
+		service: "mysql",
+	}, {
+		args: []string{"--reset", "mysql", "pack"},
+		err:  `unrecognized args: \["pack"\]`,
+	}, {
+		args: []string{"my_sql", "pack"},
+		err:  `service name "my_sql" not valid`,
+	}, {
+		args: []string{"mysql", "scatter"},
+		err:  `zone policy "scatter" not valid`,
+	}, {
+		args: []string{"mysql", "pack", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		setCmd := &setZonePolicyCommand{}
+		err := testing.InitCommand(setCmd, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(setCmd.ServiceName, gc.Equals, test.service)
+		c.Check(setCmd.Policy, gc.Equals, test.policy)
+	}
+}
+
+func (s *SetZonePolicySuite) TestSetZonePolicy(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	err := runDeploy(c, "local:mysql", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	mysql, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runSetZonePolicy(c, "mysql", "pack")
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err := mysql.ZonePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, &instance.ZonePolicy{Pack: true})
+
+	err = runSetZonePolicy(c, "mysql", "--reset")
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = mysql.ZonePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.IsNil)
+}
+
+func (s *SetZonePolicySuite) TestBlockSetZonePolicy(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	err := runDeploy(c, "local:mysql", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.BlockAllChanges(c, "TestBlockSetZonePolicy")
+	err = runSetZonePolicy(c, "mysql", "spread")
+	s.AssertBlocked(c, err, ".*TestBlockSetZonePolicy.*")
+}
@@ -12,11 +12,12 @@ import (
 )
 
 type formattedStatus struct {
-	Model       string                   `json:"model"`
-	ModelStatus *modelStatus             `json:"model-status,omitempty" yaml:"model-status,omitempty"`
-	Machines    map[string]machineStatus `json:"machines"`
-	Services    map[string]serviceStatus `json:"services"`
-	Networks    map[string]networkStatus `json:"networks,omitempty" yaml:",omitempty"`
+	Model          string                         `json:"model"`
+	ModelStatus    *modelStatus                   `json:"model-status,omitempty" yaml:"model-status,omitempty"`
+	Machines       map[string]machineStatus       `json:"machines"`
+	Services       map[string]serviceStatus       `json:"services"`
+	RemoteServices map[string]remoteServiceStatus `json:"remote-services,omitempty" yaml:"remote-services,omitempty"`
+	Networks       map[string]networkStatus       `json:"networks,omitempty" yaml:",omitempty"`
 }
 
 type formattedMachineStatus struct {
@@ -38,6 +39,7 @@ type machineStatus struct {
 	AgentStateInfo string                   `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
 	AgentVersion   string                   `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
 	DNSName        string                   `json:"dns-name,omitempty" yaml:"dns-name,omitempty"`
+	IPAddresses    []string                 `json:"ip-addresses,omitempty" yaml:"ip-addresses,omitempty"`
 	InstanceId     instance.Id              `json:"instance-id,omitempty" yaml:"instance-id,omitempty"`
 	InstanceState  string                   `json:"instance-state,omitempty" yaml:"instance-state,omitempty"`
 	Life           string                   `json:"life,omitempty" yaml:"life,omitempty"`
@@ -69,8 +71,11 @@ func (s machineStatus) MarshalYAML() (interface{}, error) {
 type serviceStatus struct {
 	Err           error                 `json:"-" yaml:",omitempty"`
 	Charm         string                `json:"charm" yaml:"charm"`
+	Version       string                `json:"version,omitempty" yaml:"version,omitempty"`
 	CanUpgradeTo  string                `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
 	Exposed       bool                  `json:"exposed" yaml:"exposed"`
+	LoadBalancer  string                `json:"load-balancer,omitempty" yaml:"load-balancer,omitempty"`
+	ZonePolicy    string                `json:"zone-policy,omitempty" yaml:"zone-policy,omitempty"`
 	Life          string                `json:"life,omitempty" yaml:"life,omitempty"`
 	StatusInfo    statusInfoContents    `json:"service-status,omitempty" yaml:"service-status"`
 	Relations     map[string][]string   `json:"relations,omitempty" yaml:"relations,omitempty"`
@@ -95,6 +100,35 @@ func (s serviceStatus) MarshalYAML() (interface{}, error) {
 	return serviceStatusNoMarshal(s), nil
 }
 
+type remoteServiceStatus struct {
+	Err       error                     `json:"-" yaml:",omitempty"`
+	URL       string                    `json:"url" yaml:"url"`
+	Life      string                    `json:"life,omitempty" yaml:"life,omitempty"`
+	Endpoints map[string]remoteEndpoint `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Relations map[string][]string       `json:"relations,omitempty" yaml:"relations,omitempty"`
+}
+
+type remoteServiceStatusNoMarshal remoteServiceStatus
+
+func (s remoteServiceStatus) MarshalJSON() ([]byte, error) {
+	if s.Err != nil {
+		return json.Marshal(errorStatus{s.Err.Error()})
+	}
+	return json.Marshal(remoteServiceStatusNoMarshal(s))
+}
+
+func (s remoteServiceStatus) MarshalYAML() (interface{}, error) {
+	if s.Err != nil {
+		return errorStatus{s.Err.Error()}, nil
+	}
+	return remoteServiceStatusNoMarshal(s), nil
+}
+
+type remoteEndpoint struct {
+	Interface string `json:"interface" yaml:"interface"`
+	Role      string `json:"role" yaml:"role"`
+}
+
 type meterStatus struct {
 	Color   string `json:"color,omitempty" yaml:"color,omitempty"`
 	Message string `json:"message,omitempty" yaml:"message,omitempty"`
@@ -106,11 +140,13 @@ type unitStatus struct {
 	AgentStatusInfo    statusInfoContents `json:"agent-status,omitempty" yaml:"agent-status"`
 	MeterStatus        *meterStatus       `json:"meter-status,omitempty" yaml:"meter-status,omitempty"`
 
-	Charm         string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
-	Machine       string                `json:"machine,omitempty" yaml:"machine,omitempty"`
-	OpenedPorts   []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
-	PublicAddress string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
-	Subordinates  map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+	Charm            string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
+	WorkloadVersion  string                `json:"workload-version,omitempty" yaml:"workload-version,omitempty"`
+	Machine          string                `json:"machine,omitempty" yaml:"machine,omitempty"`
+	AvailabilityZone string                `json:"availability-zone,omitempty" yaml:"availability-zone,omitempty"`
+	OpenedPorts      []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
+	PublicAddress    string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
+	Subordinates     map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
 }
 
 type statusInfoContents struct {
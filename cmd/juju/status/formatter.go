@@ -50,6 +50,12 @@ func (sf *statusFormatter) format() formattedStatus {
 	for sn, s := range sf.status.Services {
 		out.Services[sn] = sf.formatService(sn, s)
 	}
+	for sn, s := range sf.status.RemoteServices {
+		if out.RemoteServices == nil {
+			out.RemoteServices = make(map[string]remoteServiceStatus)
+		}
+		out.RemoteServices[sn] = sf.formatRemoteService(s)
+	}
 	for k, n := range sf.status.Networks {
 		if out.Networks == nil {
 			out.Networks = make(map[string]networkStatus)
@@ -94,6 +100,7 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 		Life:           agent.Life,
 		Err:            agent.Err,
 		DNSName:        machine.DNSName,
+		IPAddresses:    machine.IPAddresses,
 		InstanceId:     machine.InstanceId,
 		InstanceState:  machine.InstanceState,
 		Series:         machine.Series,
@@ -115,11 +122,33 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 	return out
 }
 
+func (sf *statusFormatter) formatRemoteService(service params.RemoteServiceStatus) remoteServiceStatus {
+	out := remoteServiceStatus{
+		Err:       service.Err,
+		URL:       service.URL,
+		Life:      service.Life,
+		Relations: service.Relations,
+	}
+	for _, ep := range service.Endpoints {
+		if out.Endpoints == nil {
+			out.Endpoints = make(map[string]remoteEndpoint)
+		}
+		out.Endpoints[ep.Name] = remoteEndpoint{
+			Interface: ep.Interface,
+			Role:      string(ep.Role),
+		}
+	}
+	return out
+}
+
 func (sf *statusFormatter) formatService(name string, service params.ServiceStatus) serviceStatus {
 	out := serviceStatus{
 		Err:           service.Err,
 		Charm:         service.Charm,
+		Version:       service.WorkloadVersion,
 		Exposed:       service.Exposed,
+		LoadBalancer:  service.LoadBalancer,
+		ZonePolicy:    service.ZonePolicy,
 		Life:          service.Life,
 		Relations:     service.Relations,
 		Networks:      make(map[string][]string),
@@ -173,9 +202,11 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 		WorkloadStatusInfo: sf.getWorkloadStatusInfo(info.unit),
 		AgentStatusInfo:    sf.getAgentStatusInfo(info.unit),
 		Machine:            info.unit.Machine,
+		AvailabilityZone:   info.unit.AvailabilityZone,
 		OpenedPorts:        info.unit.OpenedPorts,
 		PublicAddress:      info.unit.PublicAddress,
 		Charm:              info.unit.Charm,
+		WorkloadVersion:    info.unit.WorkloadVersion,
 		Subordinates:       make(map[string]unitStatus),
 	}
 
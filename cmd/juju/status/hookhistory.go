@@ -0,0 +1,232 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/juju/osenv"
+)
+
+// NewHookHistoryCommand returns a command that reports the hooks,
+// actions and commands recently run by a unit's agent.
+func NewHookHistoryCommand() cmd.Command {
+	return modelcmd.Wrap(&hookHistoryCommand{})
+}
+
+// HookHistoryAPI defines the API methods used by the show-hook-history
+// command.
+type HookHistoryAPI interface {
+	Close() error
+	UnitHookHistory(args params.HookHistoryArgs) ([]params.HookExecution, error)
+}
+
+type hookHistoryCommand struct {
+	modelcmd.ModelCommandBase
+	out      cmd.Output
+	api      HookHistoryAPI
+	unitName string
+	kind     string
+	name     string
+	result   string
+	since    time.Duration
+	size     int
+	isoTime  bool
+}
+
+var hookHistoryDoc = `
+Show the hooks, actions and commands most recently run by a unit's
+agent, most recent first, with the time each started, how long it took,
+its result and exit code, and the end of its standard error output.
+
+The history kept for each unit is limited to its most recent executions.
+
+Examples:
+
+    juju show-hook-history mysql/0
+    juju show-hook-history mysql/0 --result failed --since 2h
+    juju show-hook-history mysql/0 --hook config-changed -n 5
+`
+
+func (c *hookHistoryCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-hook-history",
+		Args:    "<unit>",
+		Purpose: "show the hooks, actions and commands recently run by a unit",
+		Doc:     hookHistoryDoc,
+	}
+}
+
+func (c *hookHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+	})
+	f.StringVar(&c.kind, "kind", "", "only show executions of this kind [hook|action|commands]")
+	f.StringVar(&c.name, "hook", "", "only show executions of the named hook or action")
+	f.StringVar(&c.result, "result", "", "only show executions with this result [completed|failed|timed-out]")
+	f.DurationVar(&c.since, "since", 0, "only show executions started within this duration, e.g. 30m")
+	f.IntVar(&c.size, "n", 20, "maximum number of executions to show")
+	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+}
+
+func (c *hookHistoryCommand) Init(args []string) error {
+	switch {
+	case len(args) == 0:
+		return errors.Errorf("unit name is missing")
+	case len(args) > 1:
+		return errors.Errorf("unexpected arguments after unit name")
+	}
+	if !names.IsValidUnit(args[0]) {
+		return errors.Errorf("invalid unit name %q", args[0])
+	}
+	c.unitName = args[0]
+	switch c.kind {
+	case "", "hook", "action", "commands":
+	default:
+		return errors.Errorf("unexpected kind %q", c.kind)
+	}
+	switch c.result {
+	case "", "completed", "failed", "timed-out":
+	default:
+		return errors.Errorf("unexpected result %q", c.result)
+	}
+	if c.since < 0 {
+		return errors.Errorf("invalid duration %v", c.since)
+	}
+	if c.size < 0 {
+		return errors.Errorf("invalid number of executions %d", c.size)
+	}
+	// If use of ISO time not specified on command line,
+	// check env var.
+	if !c.isoTime {
+		var err error
+		envVarValue := os.Getenv(osenv.JujuStatusIsoTimeEnvKey)
+		if envVarValue != "" {
+			if c.isoTime, err = strconv.ParseBool(envVarValue); err != nil {
+				return errors.Annotatef(err, "invalid %s env var, expected true|false", osenv.JujuStatusIsoTimeEnvKey)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *hookHistoryCommand) getAPI() (HookHistoryAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	apiclient, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	return apiclient, nil
+}
+
+func (c *hookHistoryCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	args := params.HookHistoryArgs{
+		Unit:   c.unitName,
+		Kind:   c.kind,
+		Name:   c.name,
+		Result: c.result,
+		Size:   c.size,
+	}
+	if c.since > 0 {
+		since := time.Now().Add(-c.since)
+		args.Since = &since
+	}
+	executions, err := client.UnitHookHistory(args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(executions) == 0 {
+		ctx.Infof("no hook history available")
+		return nil
+	}
+	return c.out.Write(ctx, c.formatExecutions(executions))
+}
+
+// hookExecution is the output format of a hook execution.
+type hookExecution struct {
+	Kind       string `yaml:"kind" json:"kind"`
+	Name       string `yaml:"name,omitempty" json:"name,omitempty"`
+	Relation   string `yaml:"relation,omitempty" json:"relation,omitempty"`
+	RemoteUnit string `yaml:"remote-unit,omitempty" json:"remote-unit,omitempty"`
+	Started    string `yaml:"started" json:"started"`
+	Duration   string `yaml:"duration" json:"duration"`
+	Result     string `yaml:"result" json:"result"`
+	ExitCode   int    `yaml:"exit-code" json:"exit-code"`
+	StderrTail string `yaml:"stderr-tail,omitempty" json:"stderr-tail,omitempty"`
+}
+
+func (c *hookHistoryCommand) formatExecutions(executions []params.HookExecution) []hookExecution {
+	result := make([]hookExecution, len(executions))
+	for i, execution := range executions {
+		started := execution.Started
+		result[i] = hookExecution{
+			Kind:       execution.Kind,
+			Name:       execution.Name,
+			Relation:   execution.Relation,
+			RemoteUnit: execution.RemoteUnit,
+			Started:    common.FormatTime(&started, c.isoTime),
+			Duration:   execution.Finished.Sub(execution.Started).String(),
+			Result:     execution.Result,
+			ExitCode:   execution.ExitCode,
+			StderrTail: execution.StderrTail,
+		}
+	}
+	return result
+}
+
+// formatTabular returns a tabular summary of hook executions, showing
+// the last line of each one's standard error output.
+func (c *hookHistoryCommand) formatTabular(value interface{}) ([]byte, error) {
+	executions, ok := value.([]hookExecution)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", executions, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("STARTED", "KIND", "NAME", "RELATION", "REMOTE-UNIT", "DURATION", "RESULT", "EXIT", "STDERR")
+	for _, e := range executions {
+		var stderr string
+		if lines := strings.Split(strings.TrimSpace(e.StderrTail), "\n"); len(lines) > 0 {
+			stderr = lines[len(lines)-1]
+		}
+		print(e.Started, e.Kind, e.Name, e.Relation, e.RemoteUnit, e.Duration, e.Result, strconv.Itoa(e.ExitCode), stderr)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
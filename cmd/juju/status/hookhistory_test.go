@@ -0,0 +1,173 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type HookHistorySuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	api   *fakeHookHistoryAPI
+	store *jujuclienttesting.MemStore
+}
+
+var _ = gc.Suite(&HookHistorySuite{})
+
+func (s *HookHistorySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	started := time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC)
+	s.api = &fakeHookHistoryAPI{
+		executions: []params.HookExecution{{
+			Kind:       "hook",
+			Name:       "db-relation-changed",
+			RelationId: -1,
+			Relation:   "wordpress:db mysql:server",
+			RemoteUnit: "mysql/0",
+			Started:    started,
+			Finished:   started.Add(2 * time.Second),
+			Result:     "failed",
+			ExitCode:   1,
+			StderrTail: "connecting\nconnection refused\n",
+		}, {
+			Kind:       "action",
+			Name:       "backup",
+			RelationId: -1,
+			Started:    started.Add(-time.Minute),
+			Finished:   started.Add(-59 * time.Second),
+			Result:     "completed",
+		}},
+	}
+	err := modelcmd.WriteCurrentController("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	s.store = jujuclienttesting.NewMemStore()
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = &jujuclient.ControllerAccounts{
+		CurrentAccount: "admin@local",
+	}
+}
+
+func (s *HookHistorySuite) runHookHistory(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &hookHistoryCommand{api: s.api}
+	command.SetClientStore(s.store)
+	args = append(args, "-m", "dummymodel", "--utc")
+	return coretesting.RunCommand(c, modelcmd.Wrap(command), args...)
+}
+
+func (s *HookHistorySuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "unit name is missing",
+	}, {
+		args: []string{"mysql/0", "mysql/1"},
+		err:  "unexpected arguments after unit name",
+	}, {
+		args: []string{"mysql"},
+		err:  `invalid unit name "mysql"`,
+	}, {
+		args: []string{"mysql/0", "--kind", "relation"},
+		err:  `unexpected kind "relation"`,
+	}, {
+		args: []string{"mysql/0", "--result", "error"},
+		err:  `unexpected result "error"`,
+	}, {
+		args: []string{"mysql/0", "-n", "-1"},
+		err:  "invalid number of executions -1",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.runHookHistory(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *HookHistorySuite) TestFilters(c *gc.C) {
+	before := time.Now()
+	_, err := s.runHookHistory(c, "wordpress/0",
+		"--kind", "hook", "--hook", "install", "--result", "timed-out", "--since", "1h", "-n", "5",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	args := s.api.args
+	c.Assert(args.Since, gc.NotNil)
+	c.Assert(args.Since.Before(before.Add(-time.Hour)), jc.IsFalse)
+	args.Since = nil
+	c.Assert(args, jc.DeepEquals, params.HookHistoryArgs{
+		Unit:   "wordpress/0",
+		Kind:   "hook",
+		Name:   "install",
+		Result: "timed-out",
+		Size:   5,
+	})
+}
+
+func (s *HookHistorySuite) TestTabular(c *gc.C) {
+	ctx, err := s.runHookHistory(c, "wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.args, jc.DeepEquals, params.HookHistoryArgs{Unit: "wordpress/0", Size: 20})
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"STARTED               KIND    NAME                 RELATION                   REMOTE-UNIT  DURATION  RESULT     EXIT  STDERR\n"+
+		"2016-04-01 12:00:00Z  hook    db-relation-changed  wordpress:db mysql:server  mysql/0      2s        failed     1     connection refused\n"+
+		"2016-04-01 11:59:00Z  action  backup                                                       1s        completed  0     \n"+
+		"\n")
+}
+
+func (s *HookHistorySuite) TestYaml(c *gc.C) {
+	ctx, err := s.runHookHistory(c, "wordpress/0", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	var executions []hookExecution
+	err = goyaml.Unmarshal([]byte(coretesting.Stdout(ctx)), &executions)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []hookExecution{{
+		Kind:       "hook",
+		Name:       "db-relation-changed",
+		Relation:   "wordpress:db mysql:server",
+		RemoteUnit: "mysql/0",
+		Started:    "2016-04-01 12:00:00Z",
+		Duration:   "2s",
+		Result:     "failed",
+		ExitCode:   1,
+		StderrTail: "connecting\nconnection refused\n",
+	}, {
+		Kind:     "action",
+		Name:     "backup",
+		Started:  "2016-04-01 11:59:00Z",
+		Duration: "1s",
+		Result:   "completed",
+	}})
+}
+
+func (s *HookHistorySuite) TestNoHistory(c *gc.C) {
+	s.api.executions = nil
+	ctx, err := s.runHookHistory(c, "wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "no hook history available\n")
+}
+
+type fakeHookHistoryAPI struct {
+	executions []params.HookExecution
+	args       params.HookHistoryArgs
+}
+
+func (f *fakeHookHistoryAPI) Close() error {
+	return nil
+}
+
+func (f *fakeHookHistoryAPI) UnitHookHistory(args params.HookHistoryArgs) ([]params.HookExecution, error) {
+	f.args = args
+	return f.executions, nil
+}
@@ -106,7 +106,7 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	units := make(map[string]unitStatus)
 	relations := newRelationFormatter()
 	p("[Services]")
-	p("NAME\tSTATUS\tEXPOSED\tCHARM")
+	p("NAME\tVERSION\tSTATUS\tEXPOSED\tCHARM")
 	for _, svcName := range common.SortStringsNaturally(stringKeysFromMap(fs.Services)) {
 		svc := fs.Services[svcName]
 		for un, u := range svc.Units {
@@ -114,7 +114,7 @@ func FormatTabular(value interface{}) ([]byte, error) {
 		}
 
 		subs := set.NewStrings(svc.SubordinateTo...)
-		p(svcName, svc.StatusInfo.Current, fmt.Sprintf("%t", svc.Exposed), svc.Charm)
+		p(svcName, svc.Version, svc.StatusInfo.Current, fmt.Sprintf("%t", svc.Exposed), svc.Charm)
 		for relType, relatedUnits := range svc.Relations {
 			for _, related := range relatedUnits {
 				relations.add(related, svcName, relType, subs.Contains(related))
@@ -122,6 +122,16 @@ func FormatTabular(value interface{}) ([]byte, error) {
 		}
 
 	}
+	if len(fs.RemoteServices) > 0 {
+		p()
+		p("[Remote Services]")
+		p("NAME\tURL\tENDPOINTS")
+		for _, svcName := range common.SortStringsNaturally(stringKeysFromMap(fs.RemoteServices)) {
+			svc := fs.RemoteServices[svcName]
+			endpoints := common.SortStringsNaturally(stringKeysFromMap(svc.Endpoints))
+			p(svcName, svc.URL, strings.Join(endpoints, ","))
+		}
+	}
 	if relations.len() > 0 {
 		p()
 		p("[Relations]")
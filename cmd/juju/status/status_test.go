@@ -289,6 +289,7 @@ var statusTests = []testCase{
 					"0": M{
 						"agent-state":              "pending",
 						"dns-name":                 "dummymodel-0.dns",
+						"ip-addresses":             L{"10.0.0.1"},
 						"instance-id":              "dummymodel-0",
 						"series":                   "quantal",
 						"hardware":                 "arch=amd64 cpu-cores=1 mem=1024M root-disk=8192M",
@@ -305,7 +306,15 @@ var statusTests = []testCase{
 			M{
 				"model": "dummymodel",
 				"machines": M{
-					"0": machine0,
+					"0": M{
+						"agent-state":              "started",
+						"dns-name":                 "dummymodel-0.dns",
+						"ip-addresses":             L{"10.0.0.1"},
+						"instance-id":              "dummymodel-0",
+						"series":                   "quantal",
+						"hardware":                 "arch=amd64 cpu-cores=1 mem=1024M root-disk=8192M",
+						"controller-member-status": "adding-vote",
+					},
 				},
 				"services": M{},
 			},
@@ -319,6 +328,7 @@ var statusTests = []testCase{
 				"machines": M{
 					"0": M{
 						"dns-name":                 "dummymodel-0.dns",
+						"ip-addresses":             L{"10.0.0.1"},
 						"instance-id":              "dummymodel-0",
 						"agent-version":            "1.2.3",
 						"agent-state":              "started",
@@ -348,6 +358,7 @@ var statusTests = []testCase{
 					"0": M{
 						"agent-state":              "started",
 						"dns-name":                 "dummymodel-0.dns",
+						"ip-addresses":             L{"10.0.0.1"},
 						"instance-id":              "dummymodel-0",
 						"series":                   "quantal",
 						"hardware":                 "arch=amd64 cpu-cores=2 mem=8192M root-disk=8192M",
@@ -2405,6 +2416,18 @@ func (st setUnitTools) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setUnitWorkloadVersion struct {
+	unitName string
+	version  string
+}
+
+func (st setUnitWorkloadVersion) step(c *gc.C, ctx *context) {
+	u, err := ctx.st.Unit(st.unitName)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.SetWorkloadVersion(st.version)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type addCharm struct {
 	name string
 }
@@ -2990,6 +3013,7 @@ func (s *StatusSuite) prepareTabularData(c *gc.C) *context {
 			state.StatusMaintenance,
 			"installing all the things", nil},
 		setUnitTools{"mysql/0", version.MustParseBinary("1.2.3-trusty-ppc")},
+		setUnitWorkloadVersion{"mysql/0", "5.7.12"},
 		addService{name: "logging", charm: "logging"},
 		setServiceExposed{"logging", true},
 		relateServices{"wordpress", "mysql"},
@@ -3024,10 +3048,10 @@ UPGRADE-AVAILABLE
 %s
 
 [Services] 
-NAME       STATUS      EXPOSED CHARM                  
-logging                true    cs:quantal/logging-1   
-mysql      maintenance true    cs:quantal/mysql-1     
-wordpress  active      true    cs:quantal/wordpress-3 
+NAME       VERSION STATUS      EXPOSED CHARM                  
+logging                        true    cs:quantal/logging-1   
+mysql      5.7.12  maintenance true    cs:quantal/mysql-1     
+wordpress          active      true    cs:quantal/wordpress-3 
 
 [Relations] 
 SERVICE1    SERVICE2  RELATION          TYPE        
@@ -3094,8 +3118,8 @@ func (s *StatusSuite) TestFormatTabularHookActionName(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(string(out), gc.Equals, `
 [Services] 
-NAME       STATUS EXPOSED CHARM 
-foo               false         
+NAME       VERSION STATUS EXPOSED CHARM 
+foo                       false         
 
 [Units] 
 ID      WORKLOAD-STATE AGENT-STATE VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE                           
@@ -3107,6 +3131,86 @@ ID         STATE DNS INS-ID SERIES AZ
 `[1:])
 }
 
+func (s *StatusSuite) TestFormatWorkloadVersion(c *gc.C) {
+	status := &params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"mysql": params.ServiceStatus{
+				Charm:           "cs:trusty/mysql-1",
+				WorkloadVersion: "5.7.12",
+				Units: map[string]params.UnitStatus{
+					"mysql/0": params.UnitStatus{WorkloadVersion: "5.7.12"},
+					"mysql/1": params.UnitStatus{WorkloadVersion: "5.6.30"},
+				},
+			},
+		},
+	}
+	formatted := NewStatusFormatter(status, true).format()
+	mysql := formatted.Services["mysql"]
+	c.Check(mysql.Version, gc.Equals, "5.7.12")
+	c.Check(mysql.Units["mysql/0"].WorkloadVersion, gc.Equals, "5.7.12")
+	c.Check(mysql.Units["mysql/1"].WorkloadVersion, gc.Equals, "5.6.30")
+
+	out, err := cmd.FormatYaml(formatted.Services)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(out), jc.Contains, "  version: 5.7.12\n")
+	c.Check(string(out), jc.Contains, "      workload-version: 5.6.30\n")
+}
+
+func (s *StatusSuite) TestFormatRemoteServices(c *gc.C) {
+	status := &params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"wordpress": params.ServiceStatus{
+				Charm:     "cs:trusty/wordpress-3",
+				Relations: map[string][]string{"db": []string{"mysql"}},
+				Status:    params.AgentStatus{Status: params.StatusActive},
+			},
+		},
+		RemoteServices: map[string]params.RemoteServiceStatus{
+			"mysql": params.RemoteServiceStatus{
+				URL: "admin@local/prod.mysql",
+				Endpoints: []params.RemoteEndpoint{{
+					Name:      "server",
+					Role:      charm.RoleProvider,
+					Interface: "mysql",
+				}},
+				Relations: map[string][]string{"server": []string{"wordpress"}},
+			},
+		},
+	}
+	formatted := NewStatusFormatter(status, true).format()
+	c.Assert(formatted.RemoteServices, jc.DeepEquals, map[string]remoteServiceStatus{
+		"mysql": remoteServiceStatus{
+			URL: "admin@local/prod.mysql",
+			Endpoints: map[string]remoteEndpoint{
+				"server": remoteEndpoint{Interface: "mysql", Role: "provider"},
+			},
+			Relations: map[string][]string{"server": []string{"wordpress"}},
+		},
+	})
+
+	out, err := FormatTabular(formatted)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(out), gc.Equals, `
+[Services] 
+NAME       VERSION STATUS EXPOSED CHARM                 
+wordpress          active false   cs:trusty/wordpress-3 
+
+[Remote Services] 
+NAME              URL                    ENDPOINTS 
+mysql             admin@local/prod.mysql server    
+
+[Relations] 
+SERVICE1    SERVICE2  RELATION TYPE    
+mysql       wordpress db       regular 
+
+[Units] 
+ID      WORKLOAD-STATE AGENT-STATE VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE 
+
+[Machines] 
+ID         STATE DNS INS-ID SERIES AZ 
+`[1:])
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusApi(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
@@ -3633,3 +3737,26 @@ func (s *StatusSuite) TestFormatProvisioningError(c *gc.C) {
 		Services: map[string]serviceStatus{},
 	})
 }
+
+func (s *StatusSuite) TestFormatZonePlacement(c *gc.C) {
+	status := &params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"mysql": params.ServiceStatus{
+				Charm:      "local:trusty/mysql-1",
+				ZonePolicy: "zones=a,b",
+				Units: map[string]params.UnitStatus{
+					"mysql/0": params.UnitStatus{
+						Machine:          "1",
+						AvailabilityZone: "a",
+					},
+				},
+			},
+		},
+	}
+	formatter := NewStatusFormatter(status, true)
+	formatted := formatter.format()
+
+	service := formatted.Services["mysql"]
+	c.Check(service.ZonePolicy, gc.Equals, "zones=a,b")
+	c.Check(service.Units["mysql/0"].AvailabilityZone, gc.Equals, "a")
+}
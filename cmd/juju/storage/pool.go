@@ -40,15 +40,35 @@ type PoolCommandBase struct {
 type PoolInfo struct {
 	Provider string                 `yaml:"provider" json:"provider"`
 	Attrs    map[string]interface{} `yaml:"attrs,omitempty" json:"attrs,omitempty"`
+	Usage    *PoolUsage             `yaml:"usage,omitempty" json:"usage,omitempty"`
+}
+
+// PoolUsage defines the serialization behaviour of the storage pool
+// usage information.
+type PoolUsage struct {
+	// Count is the number of volumes and filesystems
+	// created from the pool.
+	Count uint64 `yaml:"count" json:"count"`
+
+	// Size is the total size of the volumes and filesystems
+	// created from the pool, in MiB.
+	Size uint64 `yaml:"size" json:"size"`
 }
 
 func formatPoolInfo(all []params.StoragePool) map[string]PoolInfo {
 	output := make(map[string]PoolInfo)
 	for _, one := range all {
-		output[one.Name] = PoolInfo{
+		info := PoolInfo{
 			Provider: one.Provider,
 			Attrs:    one.Attrs,
 		}
+		if one.Usage != nil {
+			info.Usage = &PoolUsage{
+				Count: one.Usage.Count,
+				Size:  one.Usage.Size,
+			}
+		}
+		output[one.Name] = info
 	}
 	return output
 }
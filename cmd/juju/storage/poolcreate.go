@@ -38,6 +38,12 @@ into named resources that can be used during deployment.
 
 Pools defined at the model level are easily reused across services.
 
+Any pool may limit the storage created from it in the model by
+specifying "max-size" (the total size of all volumes and filesystems,
+e.g. max-size=500G) and "max-count" (the number of volumes and
+filesystems). Requests for storage that would exceed these limits
+are rejected.
+
 options:
     -m, --model (= "")
         juju model to operate in
@@ -24,6 +24,9 @@ If only types are specified, all pools of the specified types will be listed.
 Both pool types and names must be valid.
 Valid pool types are pool types that are registered for Juju model.
 
+For each pool, the number and total size of the volumes and filesystems
+created from it in the model are shown.
+
 options:
 -m, --model (= "")
    juju model to operate in
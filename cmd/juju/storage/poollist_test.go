@@ -83,11 +83,11 @@ func (s *poolListSuite) TestPoolListTabular(c *gc.C) {
 			"--name", "xyz", "--name", "abc",
 			"--format", "tabular"},
 		`
-NAME       PROVIDER  ATTRS
-abc        testType  key=value one=1 two=2
-testName0  a         key=value one=1 two=2
-testName1  b         key=value one=1 two=2
-xyz        testType  key=value one=1 two=2
+NAME       PROVIDER  USED  ATTRS
+abc        testType        key=value one=1 two=2
+testName0  a               key=value one=1 two=2
+testName1  b               key=value one=1 two=2
+xyz        testType        key=value one=1 two=2
 
 `[1:])
 }
@@ -101,14 +101,44 @@ func (s *poolListSuite) TestPoolListTabularSortedWithAttrs(c *gc.C) {
 		[]string{"--name", "myaw", "--name", "xyz", "--name", "abc",
 			"--format", "tabular"},
 		`
-NAME  PROVIDER  ATTRS
-abc   testType  a=true b=maybe c=well
-myaw  testType  a=true b=maybe c=well
-xyz   testType  a=true b=maybe c=well
+NAME  PROVIDER  USED  ATTRS
+abc   testType        a=true b=maybe c=well
+myaw  testType        a=true b=maybe c=well
+xyz   testType        a=true b=maybe c=well
 
 `[1:])
 }
 
+func (s *poolListSuite) TestPoolListTabularUsage(c *gc.C) {
+	s.mockAPI.attrs = map[string]interface{}{"key": "value"}
+	s.mockAPI.usage = &params.StoragePoolUsage{Count: 2, Size: 3072}
+
+	s.assertValidList(
+		c,
+		[]string{"--name", "abc", "--format", "tabular"},
+		`
+NAME  PROVIDER  USED        ATTRS
+abc   testType  2 (3.0GiB)  key=value
+
+`[1:])
+}
+
+func (s *poolListSuite) TestPoolListUsage(c *gc.C) {
+	s.mockAPI.attrs = nil
+	s.mockAPI.usage = &params.StoragePoolUsage{Count: 2, Size: 3072}
+
+	s.assertValidList(
+		c,
+		[]string{"--name", "abc"},
+		`
+abc:
+  provider: testType
+  usage:
+    count: 2
+    size: 3072
+`[1:])
+}
+
 type unmarshaller func(in []byte, out interface{}) (err error)
 
 func (s *poolListSuite) assertUnmarshalledOutput(c *gc.C, unmarshall unmarshaller, args ...string) {
@@ -154,7 +184,10 @@ func (s poolListSuite) expect(c *gc.C, types, names []string) map[string]storage
 	c.Assert(err, jc.ErrorIsNil)
 	result := make(map[string]storage.PoolInfo, len(all))
 	for _, one := range all {
-		result[one.Name] = storage.PoolInfo{one.Provider, one.Attrs}
+		result[one.Name] = storage.PoolInfo{
+			Provider: one.Provider,
+			Attrs:    one.Attrs,
+		}
 	}
 	return result
 }
@@ -169,6 +202,7 @@ func (s *poolListSuite) assertValidList(c *gc.C, args []string, expected string)
 
 type mockPoolListAPI struct {
 	attrs map[string]interface{}
+	usage *params.StoragePoolUsage
 }
 
 func (s mockPoolListAPI) Close() error {
@@ -196,5 +230,6 @@ func (s mockPoolListAPI) createTestPoolInstance(aname, atype string) params.Stor
 		Name:     aname,
 		Provider: atype,
 		Attrs:    s.attrs,
+		Usage:    s.usage,
 	}
 }
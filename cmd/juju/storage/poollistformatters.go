@@ -7,6 +7,7 @@ import (
 	"strings"
 	"text/tabwriter"
 
+	"github.com/dustin/go-humanize"
 	"github.com/juju/errors"
 )
 
@@ -36,7 +37,7 @@ func formatPoolsTabular(pools map[string]PoolInfo) ([]byte, error) {
 		fmt.Fprintln(tw, strings.Join(values, "\t"))
 	}
 
-	print("NAME", "PROVIDER", "ATTRS")
+	print("NAME", "PROVIDER", "USED", "ATTRS")
 
 	poolNames := make([]string, 0, len(pools))
 	for name := range pools {
@@ -55,7 +56,14 @@ func formatPoolsTabular(pools map[string]PoolInfo) ([]byte, error) {
 		for i, key := range keys {
 			attrs[i] = fmt.Sprintf("%v=%v", key, pool.Attrs[key])
 		}
-		print(name, pool.Provider, strings.Join(attrs, " "))
+		var used string
+		if pool.Usage != nil {
+			used = fmt.Sprintf(
+				"%d (%s)", pool.Usage.Count,
+				humanize.IBytes(pool.Usage.Size*humanize.MiByte),
+			)
+		}
+		print(name, pool.Provider, used, strings.Join(attrs, " "))
 	}
 	tw.Flush()
 
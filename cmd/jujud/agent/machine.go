@@ -40,6 +40,7 @@ import (
 	"github.com/juju/juju/api/agenttools"
 	apideployer "github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/api/metricsmanager"
+	apiremoterelations "github.com/juju/juju/api/remoterelations"
 	"github.com/juju/juju/api/statushistory"
 	apistorageprovisioner "github.com/juju/juju/api/storageprovisioner"
 	"github.com/juju/juju/apiserver"
@@ -82,6 +83,7 @@ import (
 	"github.com/juju/juju/worker/gate"
 	"github.com/juju/juju/worker/imagemetadataworker"
 	"github.com/juju/juju/worker/instancepoller"
+	"github.com/juju/juju/worker/internaldns"
 	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/metricworker"
@@ -90,6 +92,7 @@ import (
 	"github.com/juju/juju/worker/mongoupgrader"
 	"github.com/juju/juju/worker/peergrouper"
 	"github.com/juju/juju/worker/provisioner"
+	"github.com/juju/juju/worker/remoterelations"
 	"github.com/juju/juju/worker/singular"
 	"github.com/juju/juju/worker/statushistorypruner"
 	"github.com/juju/juju/worker/storageprovisioner"
@@ -297,6 +300,7 @@ func NewMachineAgent(
 		rootDir:                     rootDir,
 		initialUpgradeCheckComplete: gate.NewLock(),
 		loopDeviceManager:           loopDeviceManager,
+		dnsZones:                    internaldns.NewZones(),
 	}
 }
 
@@ -331,6 +335,10 @@ type MachineAgent struct {
 	discoveringSpacesMutex sync.Mutex
 
 	loopDeviceManager looputil.LoopDeviceManager
+
+	// dnsZones holds the internal DNS records of the models served
+	// by this controller machine.
+	dnsZones *internaldns.Zones
 }
 
 // IsRestorePreparing returns bool representing if we are in restore mode
@@ -656,6 +664,10 @@ func (a *MachineAgent) newStateStarterWorker() (worker.Worker, error) {
 // that we need to start a controller, whether they have been cached
 // or read from the state.
 //
+// The state worker is restarted if the key with which secrets are
+// encrypted changes, as it does when an upgrade first generates it,
+// because the key is only read when state is opened.
+//
 // It will stop working as soon as stopch is closed.
 func (a *MachineAgent) stateStarter(stopch <-chan struct{}) error {
 	confWatch := a.configChangedVal.Watch()
@@ -666,6 +678,8 @@ func (a *MachineAgent) stateStarter(stopch <-chan struct{}) error {
 			watchCh <- struct{}{}
 		}
 	}()
+	var started bool
+	var secretsKey string
 	for {
 		select {
 		case <-watchCh:
@@ -674,10 +688,17 @@ func (a *MachineAgent) stateStarter(stopch <-chan struct{}) error {
 			// N.B. StartWorker and StopWorker are idempotent.
 			_, ok := agentConfig.StateServingInfo()
 			if ok {
+				newSecretsKey := agentConfig.Value(agent.SecretsKey)
+				if started && newSecretsKey != secretsKey {
+					logger.Infof("secrets key changed, restarting state worker")
+					a.runner.StopWorker("state")
+				}
+				started, secretsKey = true, newSecretsKey
 				a.runner.StartWorker("state", func() (worker.Worker, error) {
 					return a.StateWorker()
 				})
 			} else {
+				started = false
 				a.runner.StopWorker("state")
 			}
 		case <-stopch:
@@ -1007,6 +1028,10 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 			a.startWorkerAfterUpgrade(singularRunner, "txnpruner", func() (worker.Worker, error) {
 				return txnpruner.New(st, time.Hour*2), nil
 			})
+
+			a.startWorkerAfterUpgrade(runner, "internaldns", func() (worker.Worker, error) {
+				return internaldns.NewMachineServer(m, a.dnsZones)
+			})
 		default:
 			return nil, errors.Errorf("unknown job type %q", job)
 		}
@@ -1088,6 +1113,9 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
+	runner.StartWorker("internaldns-updater", func() (worker.Worker, error) {
+		return internaldns.NewUpdater(st, a.dnsZones)
+	})
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
@@ -1154,6 +1182,13 @@ func (a *MachineAgent) startEnvWorkers(
 		}
 		return w, nil
 	})
+	singularRunner.StartWorker("remoterelations", func() (worker.Worker, error) {
+		w, err := remoterelations.New(apiremoterelations.NewAPI(apiSt))
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot start remote relations worker")
+		}
+		return w, nil
+	})
 	singularRunner.StartWorker("addresserworker", func() (worker.Worker, error) {
 		w, err := newAddresser(apiSt.Addresser())
 		if err != nil {
@@ -1749,6 +1784,11 @@ func openState(agentConfig agent.Config, dialOpts mongo.DialOpts) (_ *state.Stat
 		logger.Errorf("running machine %v agent on inappropriate instance", m)
 		return nil, nil, worker.ErrTerminateAgent
 	}
+	secretsKey, err := agent.SecretsKeyValue(agentConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	st.SetSecretsKey(secretsKey)
 	return st, m, nil
 }
 
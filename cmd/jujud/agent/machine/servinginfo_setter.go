@@ -63,8 +63,15 @@ func ServingInfoSetterManifold(config ServingInfoSetterConfig) dependency.Manifo
 					if err != nil {
 						return nil, errors.Errorf("cannot get state serving info: %v", err)
 					}
+					secretsKey, err := secretsKey(agent.CurrentConfig(), apiState)
+					if err != nil {
+						return nil, errors.Annotate(err, "cannot get secrets key")
+					}
 					err = agent.ChangeConfig(func(config coreagent.ConfigSetter) error {
 						config.SetStateServingInfo(info)
+						if secretsKey != nil {
+							coreagent.SetSecretsKeyValue(config, secretsKey)
+						}
 						return nil
 					})
 					if err != nil {
@@ -78,3 +85,23 @@ func ServingInfoSetterManifold(config ServingInfoSetterConfig) dependency.Manifo
 		},
 	}
 }
+
+// secretsKey returns the key with which controllers encrypt secrets,
+// as held in the given configuration or, failing that, by the
+// controller; it returns nil if neither holds one.
+func secretsKey(config coreagent.Config, apiState *apiagent.State) ([]byte, error) {
+	key, err := coreagent.SecretsKeyValue(config)
+	if err != nil || key != nil {
+		return key, err
+	}
+	key, err = apiState.SecretsKey()
+	if errors.IsNotSupported(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(key) == 0 {
+		return nil, nil
+	}
+	return key, nil
+}
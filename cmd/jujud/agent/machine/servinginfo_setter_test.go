@@ -135,6 +135,45 @@ func (s *ServingInfoSetterSuite) TestJobManageEnviron(c *gc.C) {
 	// Verify that the state serving info was actually set.
 	c.Assert(a.conf.ssiSet, jc.IsTrue)
 	c.Assert(a.conf.ssi.APIPort, gc.Equals, mockAPIPort)
+	// The controller is too old to provide a secrets key.
+	c.Assert(a.conf.values, gc.HasLen, 0)
+}
+
+func (s *ServingInfoSetterSuite) TestJobManageEnvironSecretsKey(c *gc.C) {
+	// The controller's secrets key is copied to new controllers.
+	a := &mockAgent{}
+	apiCaller := versionedAPICaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, args, response interface{}) error {
+				c.Assert(objType, gc.Equals, "Agent")
+				switch request {
+				case "GetEntities":
+					result := response.(*params.AgentGetEntitiesResults)
+					result.Entities = []params.AgentGetEntitiesResult{{
+						Jobs: []multiwatcher.MachineJob{multiwatcher.JobManageModel},
+					}}
+				case "StateServingInfo":
+				case "SecretsKey":
+					c.Assert(version, gc.Equals, 3)
+					result := response.(*params.BytesResult)
+					result.Result = []byte("sekrit")
+				default:
+					c.Fatalf("not sure how to handle: %q", request)
+				}
+				return nil
+			},
+		),
+		version: 3,
+	}
+	_, err := s.manifold.Start(dt.StubGetResource(dt.StubResources{
+		"agent":      dt.StubResource{Output: a},
+		"api-caller": dt.StubResource{Output: apiCaller},
+	}))
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+
+	key, err := coreagent.SecretsKeyValue(&a.conf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, jc.DeepEquals, []byte("sekrit"))
 }
 
 func (s *ServingInfoSetterSuite) TestJobHostUnits(c *gc.C) {
@@ -176,6 +215,17 @@ func (s *ServingInfoSetterSuite) checkNotController(c *gc.C, job multiwatcher.Ma
 	c.Assert(a.conf.ssiSet, jc.IsFalse)
 }
 
+// versionedAPICaller is an APICallerFunc reporting the given version
+// of all facades.
+type versionedAPICaller struct {
+	basetesting.APICallerFunc
+	version int
+}
+
+func (c versionedAPICaller) BestFacadeVersion(facade string) int {
+	return c.version
+}
+
 type mockAgent struct {
 	coreagent.Agent
 	conf mockConfig
@@ -194,6 +244,18 @@ type mockConfig struct {
 	tag    names.Tag
 	ssiSet bool
 	ssi    params.StateServingInfo
+	values map[string]string
+}
+
+func (mc *mockConfig) Value(key string) string {
+	return mc.values[key]
+}
+
+func (mc *mockConfig) SetValue(key, value string) {
+	if mc.values == nil {
+		mc.values = make(map[string]string)
+	}
+	mc.values[key] = value
 }
 
 func (mc *mockConfig) Tag() names.Tag {
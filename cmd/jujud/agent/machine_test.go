@@ -270,6 +270,7 @@ type MachineSuite struct {
 var perEnvSingularWorkers = []string{
 	"cleaner",
 	"minunitsworker",
+	"remoterelations",
 	"addresserworker",
 	"environ-provisioner",
 	"charm-revision-updater",
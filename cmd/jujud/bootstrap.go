@@ -232,6 +232,20 @@ func (c *BootstrapCommand) Run(_ *cmd.Context) error {
 		return errors.Annotate(err, "cannot set mongo version")
 	}
 
+	// The key with which secrets are encrypted is held by the
+	// controller agents, and not in the database.
+	secretsKey, err := state.NewSecretsKey()
+	if err != nil {
+		return errors.Annotate(err, "cannot generate secrets key")
+	}
+	err = c.ChangeConfig(func(config agent.ConfigSetter) error {
+		agent.SetSecretsKeyValue(config, secretsKey)
+		return nil
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot set secrets key")
+	}
+
 	agentConfig = c.CurrentConfig()
 
 	// Create system-identity file
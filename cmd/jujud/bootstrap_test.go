@@ -578,6 +578,19 @@ func (s *BootstrapSuite) TestSystemIdentityWritten(c *gc.C) {
 	c.Assert(string(data), gc.Equals, "private-key")
 }
 
+func (s *BootstrapSuite) TestSecretsKeyWritten(c *gc.C) {
+	machineConf, cmd, err := s.initBootstrapCommand(c, nil, "--model-config", s.b64yamlEnvcfg, "--instance-id", string(s.instanceId))
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machineConf1, err := agent.ReadConfig(agent.ConfigPath(machineConf.DataDir(), names.NewMachineTag("0")))
+	c.Assert(err, jc.ErrorIsNil)
+	key, err := agent.SecretsKeyValue(machineConf1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, gc.HasLen, state.SecretsKeySize)
+}
+
 func (s *BootstrapSuite) TestDownloadedToolsMetadata(c *gc.C) {
 	// Tools downloaded by cloud-init script.
 	s.testToolsMetadata(c, false)
@@ -181,6 +181,11 @@ func (c *RunCommand) executeNoContext() (*exec.ExecResponse, error) {
 		return nil, errors.Trace(err)
 	}
 	defer lock.Unlock()
+	// Hooks of units on this machine may still be running, if their
+	// model allows them to run concurrently.
+	if err := cmdutil.WaitUnitHookExecutions(cmdutil.DataDir, cmdutil.DefaultUnitHookExecutionsTimeout, nil); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	runCmd := c.appendProxyToCommands()
 
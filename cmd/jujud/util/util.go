@@ -6,11 +6,16 @@ package util
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 	"github.com/juju/utils/fslock"
 	"github.com/juju/utils/series"
 
@@ -235,6 +240,106 @@ func HookExecutionLock(dataDir string) (*fslock.Lock, error) {
 	return fslock.NewLock(lockDir, "uniter-hook-execution", fslock.Defaults())
 }
 
+// unitHookExecutionLockPrefix prefixes the names of the locks returned
+// by UnitHookExecutionLock.
+const unitHookExecutionLockPrefix = "uniter-hook-execution-"
+
+// unitHookExecutionPollDelay is the time WaitUnitHookExecutions waits
+// between checks of the unit hook execution locks.
+var unitHookExecutionPollDelay = 100 * time.Millisecond
+
+// UnitHookExecutionLock returns an *fslock.Lock suitable for use as the
+// hook execution lock of the given unit, when the hooks of different
+// units on a machine may run concurrently.
+//
+// A unit's lock must only be acquired while holding the lock returned
+// by HookExecutionLock, which may be released once the unit's lock is
+// held. Holders of the machine lock that require isolation from all
+// hook execution must call WaitUnitHookExecutions after acquiring it.
+func UnitHookExecutionLock(dataDir string, unitTag names.UnitTag) (*fslock.Lock, error) {
+	lockDir := filepath.Join(dataDir, "locks")
+	return fslock.NewLock(lockDir, unitHookExecutionLockPrefix+unitTag.String(), fslock.Defaults())
+}
+
+// DefaultUnitHookExecutionsTimeout is the time callers of
+// WaitUnitHookExecutions should normally allow running hooks to
+// complete.
+const DefaultUnitHookExecutionsTimeout = 30 * time.Minute
+
+// WaitUnitHookExecutions blocks until none of the locks returned by
+// UnitHookExecutionLock are held, until continueFunc, if not nil,
+// returns an error, or until the given timeout elapses. It must be
+// called while holding the lock returned by HookExecutionLock, so that
+// no unit can start running hooks while it waits.
+//
+// Locks held for units that are no longer deployed on the machine
+// cannot be released by their owners, and are broken.
+func WaitUnitHookExecutions(dataDir string, timeout time.Duration, continueFunc func() error) error {
+	deadline := time.After(timeout)
+	for {
+		held, err := unitHookExecutionHeld(dataDir)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !held {
+			return nil
+		}
+		if continueFunc != nil {
+			if err := continueFunc(); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-deadline:
+			return errors.Errorf("timed out after %v waiting for unit hook executions", timeout)
+		case <-time.After(unitHookExecutionPollDelay):
+		}
+	}
+}
+
+// unitHookExecutionHeld reports whether the hook execution lock of any
+// unit deployed on the machine with the given data directory is held,
+// breaking those of units that are not deployed.
+func unitHookExecutionHeld(dataDir string) (bool, error) {
+	lockDir := filepath.Join(dataDir, "locks")
+	infos, err := ioutil.ReadDir(lockDir)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	held := false
+	for _, info := range infos {
+		if !strings.HasPrefix(info.Name(), unitHookExecutionLockPrefix) {
+			continue
+		}
+		lock, err := fslock.NewLock(lockDir, info.Name(), fslock.Defaults())
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if !lock.IsLocked() {
+			continue
+		}
+		unitTag, err := names.ParseUnitTag(strings.TrimPrefix(info.Name(), unitHookExecutionLockPrefix))
+		if err != nil {
+			// Not a lock we know how to own; assume it is live.
+			held = true
+			continue
+		}
+		if _, err := os.Stat(agent.Dir(dataDir, unitTag)); os.IsNotExist(err) {
+			logger.Warningf("breaking stale hook execution lock of %s", unitTag)
+			if err := lock.BreakLock(); err != nil {
+				return false, errors.Trace(err)
+			}
+			continue
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		held = true
+	}
+	return held, nil
+}
+
 // ParamsStateServingInfoToStateStateServingInfo converts a
 // params.StateServingInfo to a state.StateServingInfo.
 func ParamsStateServingInfoToStateStateServingInfo(i params.StateServingInfo) state.StateServingInfo {
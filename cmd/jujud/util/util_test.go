@@ -5,11 +5,16 @@ package util
 
 import (
 	stderrors "errors"
+	"os"
+	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/fslock"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/agent"
 	"github.com/juju/juju/apiserver/params"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker"
@@ -136,3 +141,130 @@ type testPinger func() error
 func (f testPinger) Ping() error {
 	return f()
 }
+
+type hookExecutionLockSuite struct {
+	coretesting.BaseSuite
+	dataDir string
+}
+
+var _ = gc.Suite(&hookExecutionLockSuite{})
+
+func (s *hookExecutionLockSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+	s.PatchValue(&unitHookExecutionPollDelay, time.Millisecond)
+}
+
+// unitLock returns the hook execution lock of the named unit, which is
+// deployed on the machine.
+func (s *hookExecutionLockSuite) unitLock(c *gc.C, unitName string) *fslock.Lock {
+	unitTag := names.NewUnitTag(unitName)
+	err := os.MkdirAll(agent.Dir(s.dataDir, unitTag), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	lock, err := UnitHookExecutionLock(s.dataDir, unitTag)
+	c.Assert(err, jc.ErrorIsNil)
+	return lock
+}
+
+func (s *hookExecutionLockSuite) TestUnitLocksAreIndependent(c *gc.C) {
+	machineLock, err := HookExecutionLock(s.dataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.unitLock(c, "mysql/0")
+	wordpress := s.unitLock(c, "wordpress/0")
+
+	// Units take the machine lock to acquire their own, and release it
+	// once they hold it, so that other units can do the same.
+	for _, lock := range []*fslock.Lock{mysql, wordpress} {
+		c.Assert(machineLock.Lock("acquiring unit lock"), jc.ErrorIsNil)
+		c.Assert(lock.LockWithTimeout(coretesting.ShortWait, "running hook"), jc.ErrorIsNil)
+		c.Assert(machineLock.Unlock(), jc.ErrorIsNil)
+	}
+	c.Assert(mysql.IsLocked(), jc.IsTrue)
+	c.Assert(wordpress.IsLocked(), jc.IsTrue)
+	c.Assert(machineLock.IsLocked(), jc.IsFalse)
+
+	// A unit's own lock still serialises its hooks.
+	err = s.unitLock(c, "mysql/0").LockWithTimeout(coretesting.ShortWait, "running hook")
+	c.Assert(errors.Cause(err), gc.Equals, fslock.ErrTimeout)
+}
+
+func (s *hookExecutionLockSuite) TestWaitUnitHookExecutionsNoLocks(c *gc.C) {
+	err := WaitUnitHookExecutions(s.dataDir, coretesting.LongWait, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machineLock, err := HookExecutionLock(s.dataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineLock.Lock("juju-run"), jc.ErrorIsNil)
+	defer machineLock.Unlock()
+	lock := s.unitLock(c, "mysql/0")
+	c.Assert(lock.Lock("running hook"), jc.ErrorIsNil)
+	c.Assert(lock.Unlock(), jc.ErrorIsNil)
+
+	// The machine lock itself is not waited for.
+	err = WaitUnitHookExecutions(s.dataDir, coretesting.LongWait, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *hookExecutionLockSuite) TestWaitUnitHookExecutionsBlocks(c *gc.C) {
+	mysql := s.unitLock(c, "mysql/0")
+	wordpress := s.unitLock(c, "wordpress/0")
+	c.Assert(mysql.Lock("running hook"), jc.ErrorIsNil)
+	c.Assert(wordpress.Lock("running hook"), jc.ErrorIsNil)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- WaitUnitHookExecutions(s.dataDir, coretesting.LongWait, nil)
+	}()
+	select {
+	case err := <-done:
+		c.Fatalf("wait returned while hooks were running: %v", err)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	c.Assert(mysql.Unlock(), jc.ErrorIsNil)
+	select {
+	case err := <-done:
+		c.Fatalf("wait returned while a hook was running: %v", err)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	c.Assert(wordpress.Unlock(), jc.ErrorIsNil)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("wait did not return after hooks finished")
+	}
+}
+
+func (s *hookExecutionLockSuite) TestWaitUnitHookExecutionsAborted(c *gc.C) {
+	lock := s.unitLock(c, "mysql/0")
+	c.Assert(lock.Lock("running hook"), jc.ErrorIsNil)
+	defer lock.Unlock()
+
+	err := WaitUnitHookExecutions(s.dataDir, coretesting.LongWait, func() error {
+		return errors.New("dying")
+	})
+	c.Assert(err, gc.ErrorMatches, "dying")
+}
+
+func (s *hookExecutionLockSuite) TestWaitUnitHookExecutionsTimeout(c *gc.C) {
+	lock := s.unitLock(c, "mysql/0")
+	c.Assert(lock.Lock("running hook"), jc.ErrorIsNil)
+	defer lock.Unlock()
+
+	err := WaitUnitHookExecutions(s.dataDir, coretesting.ShortWait, nil)
+	c.Assert(err, gc.ErrorMatches, "timed out after .* waiting for unit hook executions")
+}
+
+func (s *hookExecutionLockSuite) TestWaitUnitHookExecutionsBreaksStaleLocks(c *gc.C) {
+	lock := s.unitLock(c, "mysql/0")
+	c.Assert(lock.Lock("running hook"), jc.ErrorIsNil)
+	// The unit is removed from the machine without releasing its lock.
+	err := os.RemoveAll(agent.Dir(s.dataDir, names.NewUnitTag("mysql/0")))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = WaitUnitHookExecutions(s.dataDir, coretesting.LongWait, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lock.IsLocked(), jc.IsFalse)
+}
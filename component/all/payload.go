@@ -28,7 +28,7 @@ import (
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
-const payloadsHookContextFacade = payload.ComponentName + "-hook-context"
+const payloadsHookContextFacade = context.HookContextFacade
 
 type payloads struct{}
 
@@ -59,13 +59,17 @@ func (c payloads) registerPublicFacade() {
 		return
 	}
 
-	const version = 1
 	common.RegisterStandardFacade(
 		payload.ComponentName,
-		version,
+		1,
 		c.newPublicFacade,
 	)
-	api.RegisterFacadeVersion(payload.ComponentName, version)
+	common.RegisterStandardFacade(
+		payload.ComponentName,
+		server.Version,
+		c.newPublicFacade,
+	)
+	api.RegisterFacadeVersion(payload.ComponentName, server.Version)
 }
 
 type facadeCaller struct {
@@ -82,7 +86,7 @@ func (payloads) newListAPIClient(cmd *status.ListCommand) (status.ListAPI, error
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	caller := base.NewFacadeCallerForVersion(apiCaller, payload.ComponentName, 0)
+	caller := base.NewFacadeCaller(apiCaller, payload.ComponentName)
 
 	listAPI := client.NewPublicClient(&facadeCaller{
 		FacadeCaller: caller,
@@ -123,7 +127,7 @@ func (c payloads) registerHookContext() {
 }
 
 func (payloads) newUnitFacadeClient(caller base.APICaller) context.APIClient {
-	facadeCaller := base.NewFacadeCallerForVersion(caller, payloadsHookContextFacade, 0)
+	facadeCaller := base.NewFacadeCaller(caller, payloadsHookContextFacade)
 	return internalclient.NewUnitFacadeClient(facadeCaller)
 }
 
@@ -136,14 +140,19 @@ func (payloads) newHookContextFacade(st *state.State, unit *state.Unit) (interfa
 }
 
 func (c payloads) registerHookContextFacade() {
-	const version = 0
 	common.RegisterHookContextFacade(
 		payloadsHookContextFacade,
-		version,
+		0,
+		c.newHookContextFacade,
+		reflect.TypeOf(&internalserver.UnitFacade{}),
+	)
+	common.RegisterHookContextFacade(
+		payloadsHookContextFacade,
+		internalserver.FacadeVersion,
 		c.newHookContextFacade,
 		reflect.TypeOf(&internalserver.UnitFacade{}),
 	)
-	api.RegisterFacadeVersion(payloadsHookContextFacade, version)
+	api.RegisterFacadeVersion(payloadsHookContextFacade, internalserver.FacadeVersion)
 }
 
 type payloadsHookContext struct {
@@ -63,6 +63,16 @@ func (r resources) registerPublicFacade() {
 		return
 	}
 
+	common.RegisterStandardFacade(
+		resource.ComponentName,
+		1,
+		r.newPublicFacade,
+	)
+	common.RegisterStandardFacade(
+		resource.ComponentName,
+		2,
+		r.newPublicFacade,
+	)
 	common.RegisterStandardFacade(
 		resource.ComponentName,
 		server.Version,
@@ -183,6 +193,16 @@ func (r resources) registerPublicCommands() {
 			},
 		})
 	})
+
+	commands.RegisterCommand(func() jujucmd.Command {
+		return cmd.NewSuperCommand(cmd.ResourcesDeps{
+			Rollback: cmd.RollbackDeps{
+				NewClient: func(c *cmd.RollbackCommand) (cmd.RollbackClient, error) {
+					return resourceadapters.NewAPIClient(c.NewAPIRoot)
+				},
+			},
+		})
+	})
 }
 
 // TODO(ericsnow) Get rid of charmstoreClient once csclient.Client grows the methods.
@@ -216,7 +236,7 @@ func (r resources) registerHookContext() {
 				return nil, errors.Trace(err)
 			}
 			// TODO(ericsnow) Pass the unit's tag through to the component?
-			return context.NewContextAPI(hctxClient, config.DataDir), nil
+			return context.NewContextAPI(hctxClient, config.DataDir, config.SharedDataDir), nil
 		},
 	)
 
@@ -250,6 +270,12 @@ func (c resources) registerHookContextCommands() {
 }
 
 func (r resources) registerHookContextFacade() {
+	common.RegisterHookContextFacade(
+		context.HookContextFacade,
+		1,
+		r.newHookContextFacade,
+		reflect.TypeOf(&internalserver.UnitFacade{}),
+	)
 	common.RegisterHookContextFacade(
 		context.HookContextFacade,
 		internalserver.FacadeVersion,
@@ -276,6 +302,11 @@ func (ds *resourcesUnitDataStore) OpenResource(name string) (resource.Resource,
 	return ds.resources.OpenResource(ds.unit, name)
 }
 
+// SetUnitResource implements resource/api/private/server.UnitDataStore.
+func (ds *resourcesUnitDataStore) SetUnitResource(name string, fingerprint charmresource.Fingerprint) error {
+	return ds.resources.SetUnitResource(ds.unit, name, fingerprint)
+}
+
 func (r resources) newHookContextFacade(st *corestate.State, unit *corestate.Unit) (interface{}, error) {
 	res, err := st.Resources()
 	if err != nil {
@@ -286,7 +317,9 @@ func (r resources) newHookContextFacade(st *corestate.State, unit *corestate.Uni
 
 func (r resources) newUnitFacadeClient(unitName string, caller base.APICaller) (context.APIClient, error) {
 
-	facadeCaller := base.NewFacadeCallerForVersion(caller, context.HookContextFacade, internalserver.FacadeVersion)
+	// The unit must also work with controllers that only have
+	// earlier versions of the facade.
+	facadeCaller := base.NewFacadeCaller(caller, context.HookContextFacade)
 	httpClient, err := caller.HTTPClient()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -107,10 +107,16 @@ func (manager *containerManager) CreateContainer(
 		"boot.autostart": "true",
 	}
 
+	devices, err := networkDevices(networkConfig)
+	if err != nil {
+		return
+	}
+
 	spec := lxdclient.InstanceSpec{
 		Name:     name,
 		Image:    manager.client.ImageNameForSeries(series),
 		Metadata: metadata,
+		Devices:  devices,
 		Profiles: []string{
 			"default",
 		},
@@ -126,6 +132,40 @@ func (manager *containerManager) CreateContainer(
 	return
 }
 
+// networkDevices returns the LXD devices needed to attach the
+// container directly to the host's network devices, or nil if the
+// container uses the bridge configured in its profiles. The devices
+// override the bridged "eth0" of the default profile.
+func networkDevices(networkConfig *container.NetworkConfig) (map[string]map[string]string, error) {
+	if networkConfig == nil || networkConfig.NetworkType != container.MacvlanNetwork {
+		return nil, nil
+	}
+	devices := make(map[string]map[string]string)
+	for _, iface := range networkConfig.Interfaces {
+		if iface.InterfaceName == "" || iface.ParentInterfaceName == "" {
+			return nil, errors.Errorf("interface %q has no parent device", iface.InterfaceName)
+		}
+		device := map[string]string{
+			"type":    "nic",
+			"nictype": "macvlan",
+			"parent":  iface.ParentInterfaceName,
+			"name":    iface.InterfaceName,
+		}
+		if iface.MACAddress != "" {
+			device["hwaddr"] = iface.MACAddress
+		}
+		mtu := iface.MTU
+		if mtu == 0 {
+			mtu = networkConfig.MTU
+		}
+		if mtu > 0 {
+			device["mtu"] = fmt.Sprint(mtu)
+		}
+		devices[iface.InterfaceName] = device
+	}
+	return devices, nil
+}
+
 func (manager *containerManager) DestroyContainer(id instance.Id) error {
 	if manager.client == nil {
 		var err error
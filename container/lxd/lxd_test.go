@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/container/lxd"
 	containertesting "github.com/juju/juju/container/testing"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/tools/lxdclient"
 	jc "github.com/juju/testing/checkers"
@@ -90,3 +91,49 @@ func (t *LxdSuite) TestNotAllContainersAreDeleted(c *gc.C) {
 		c.Assert(err, jc.ErrorIsNil)
 	}
 }
+
+func (t *LxdSuite) TestNetworkDevicesBridge(c *gc.C) {
+	networkConfig := container.BridgeNetworkConfig("lxcbr0", 0, nil)
+	devices, err := lxd.NetworkDevices(networkConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, gc.IsNil)
+}
+
+func (t *LxdSuite) TestNetworkDevicesMacvlan(c *gc.C) {
+	networkConfig := container.MacvlanNetworkConfig(1500, []network.InterfaceInfo{{
+		InterfaceName:       "eth0",
+		ParentInterfaceName: "eth2",
+		MACAddress:          "00:16:3e:01:02:03",
+	}, {
+		InterfaceName:       "eth1",
+		ParentInterfaceName: "bond0.42",
+		MTU:                 9000,
+	}})
+	devices, err := lxd.NetworkDevices(networkConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, jc.DeepEquals, map[string]map[string]string{
+		"eth0": {
+			"type":    "nic",
+			"nictype": "macvlan",
+			"parent":  "eth2",
+			"name":    "eth0",
+			"hwaddr":  "00:16:3e:01:02:03",
+			"mtu":     "1500",
+		},
+		"eth1": {
+			"type":    "nic",
+			"nictype": "macvlan",
+			"parent":  "bond0.42",
+			"name":    "eth1",
+			"mtu":     "9000",
+		},
+	})
+}
+
+func (t *LxdSuite) TestNetworkDevicesMacvlanNoParent(c *gc.C) {
+	networkConfig := container.MacvlanNetworkConfig(0, []network.InterfaceInfo{{
+		InterfaceName: "eth0",
+	}})
+	_, err := lxd.NetworkDevices(networkConfig)
+	c.Assert(err, gc.ErrorMatches, `interface "eth0" has no parent device`)
+}
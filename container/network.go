@@ -12,6 +12,10 @@ const (
 	BridgeNetwork = "bridge"
 	// PhyscialNetwork will have the container use a specified network device.
 	PhysicalNetwork = "physical"
+	// MacvlanNetwork will have each of the container's interfaces
+	// attached directly to a host network device, with a static
+	// address on that device's subnet.
+	MacvlanNetwork = "macvlan"
 )
 
 // NetworkConfig defines how the container network will be configured.
@@ -40,3 +44,11 @@ func BridgeNetworkConfig(device string, mtu int, interfaces []network.InterfaceI
 func PhysicalNetworkConfig(device string, mtu int, interfaces []network.InterfaceInfo) *NetworkConfig {
 	return &NetworkConfig{PhysicalNetwork, device, mtu, interfaces}
 }
+
+// MacvlanNetworkConfig returns a valid NetworkConfig to attach each of
+// the container's network interfaces to the host device named by the
+// interface's ParentInterfaceName, using macvlan. The interfaces must
+// specify static addresses, as the host does not serve DHCP on them.
+func MacvlanNetworkConfig(mtu int, interfaces []network.InterfaceInfo) *NetworkConfig {
+	return &NetworkConfig{MacvlanNetwork, "", mtu, interfaces}
+}
@@ -4,6 +4,8 @@
 package environs
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
@@ -65,6 +67,11 @@ type StartInstanceParams struct {
 	// ImageMetadata is a collection of image metadata
 	// that may be used to start this instance.
 	ImageMetadata []*imagemetadata.ImageMetadata
+
+	// RootDiskSource, if not empty, names the source from which the
+	// instance's root disk should be created. It is only set if the
+	// broker implements RootDiskSourceValidator, and has accepted it.
+	RootDiskSource string
 }
 
 // StartInstanceResult holds the result of an
@@ -120,3 +127,25 @@ type InstanceBroker interface {
 	// correct network configuration.
 	MaintainInstance(args StartInstanceParams) error
 }
+
+// RootDiskSourceValidator is implemented by instance brokers that can
+// create an instance's root disk from a chosen source, such as a class
+// of volume.
+type RootDiskSourceValidator interface {
+	// ValidateRootDiskSource returns an error satisfying
+	// errors.IsNotValid if the broker cannot create root disks
+	// from the named source.
+	ValidateRootDiskSource(source string) error
+}
+
+// ValidateRootDiskSource returns an error if the supplied broker cannot
+// create root disks from the named source. The error satisfies
+// errors.IsNotSupported if the broker cannot choose the source of root
+// disks at all.
+func ValidateRootDiskSource(broker InstanceBroker, source string) error {
+	validator, ok := broker.(RootDiskSourceValidator)
+	if !ok {
+		return errors.NotSupportedf("root-disk source %q", source)
+	}
+	return validator.ValidateRootDiskSource(source)
+}
@@ -24,8 +24,10 @@ import (
 	"gopkg.in/macaroon-bakery.v1/bakery"
 
 	"github.com/juju/juju/cert"
+	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/version"
 )
 
@@ -46,6 +48,13 @@ const (
 	// instance security groups.
 	FwNone = "none"
 
+	// EgressAllow requests that all outbound traffic is allowed.
+	EgressAllow = "allow"
+
+	// EgressDeny requests that outbound traffic is denied, except
+	// to the destinations listed in egress-allowed.
+	EgressDeny = "deny"
+
 	// DefaultStatePort is the default port the controller is listening on.
 	DefaultStatePort int = 37017
 
@@ -93,6 +102,10 @@ const (
 	// config setting. Only non-zero, positive integer values will
 	// have effect.
 	DefaultLXCDefaultMTU = 0
+
+	// DefaultProvisionerParallelism is the default value for the
+	// "provisioner-parallelism" config setting.
+	DefaultProvisionerParallelism = 10
 )
 
 // TODO(katco-): Please grow this over time.
@@ -184,6 +197,40 @@ const (
 	// automatically retry a hook that has failed
 	AutomaticallyRetryHooks = "automatically-retry-hooks"
 
+	// EgressPolicyKey stores whether outbound traffic from the model's
+	// machines is allowed or denied by default.
+	EgressPolicyKey = "egress-policy"
+
+	// EgressAllowedKey stores a comma-separated list of destinations,
+	// in the form accepted by network.ParseEgressRule, to which
+	// outbound traffic is allowed when the egress policy is "deny".
+	EgressAllowedKey = "egress-allowed"
+
+	// ProvisionerParallelismKey stores the maximum number of instances
+	// the provisioner will start concurrently.
+	ProvisionerParallelismKey = "provisioner-parallelism"
+
+	// CloudInitUserDataKey stores operator-supplied cloud-init user
+	// data, which is merged with that Juju generates for each of the
+	// model's machines.
+	CloudInitUserDataKey = "cloudinit-userdata"
+
+	// InternalDNSKey stores whether the controller serves internal DNS
+	// names for the model's units, services and machines, and the
+	// model's machines are configured to resolve them.
+	InternalDNSKey = "internal-dns"
+
+	// HookTimeoutKey stores the default number of seconds a hook may
+	// run before it is killed, or 0 if hooks may run indefinitely.
+	// Charms may declare their own limits for individual hooks.
+	HookTimeoutKey = "hook-timeout"
+
+	// PerUnitHookLockKey stores whether the hooks of different units
+	// on a machine may run concurrently, each unit's hooks being
+	// serialised by a lock of its own. Operations that need the whole
+	// machine still hold the machine-level lock.
+	PerUnitHookLockKey = "per-unit-hook-lock"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -633,6 +680,14 @@ func Validate(cfg, old *Config) error {
 		return errors.Errorf("uuid: expected uuid, got string(%q)", uuid)
 	}
 
+	if _, err := cfg.egressPolicy(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if _, err := cloudinit.ParseUserData(cfg.CloudInitUserData()); err != nil {
+		return errors.Annotate(err, CloudInitUserDataKey)
+	}
+
 	// Ensure the resource tags have the expected k=v format.
 	if _, err := cfg.resourceTags(); err != nil {
 		return errors.Annotate(err, "validating resource tags")
@@ -672,6 +727,16 @@ func Validate(cfg, old *Config) error {
 		return errors.Errorf("%s: expected positive integer, got %v", LXCDefaultMTU, lxcDefaultMTU)
 	}
 
+	// Check ProvisionerParallelism is a positive integer, when set.
+	if v, ok := cfg.defined[ProvisionerParallelismKey].(int); ok && v < 1 {
+		return errors.Errorf("%s: expected positive integer, got %v", ProvisionerParallelismKey, v)
+	}
+
+	// Check HookTimeout is not negative, when set.
+	if v, ok := cfg.defined[HookTimeoutKey].(int); ok && v < 0 {
+		return errors.Errorf("%s: expected non-negative integer, got %v", HookTimeoutKey, v)
+	}
+
 	cfg.defined = ProcessDeprecatedAttributes(cfg.defined)
 	return nil
 }
@@ -1213,6 +1278,70 @@ func (c *Config) resourceTags() (map[string]string, error) {
 	return v, nil
 }
 
+// EgressPolicy returns the policy for outbound traffic from the
+// model's machines, as defined by egress-policy and egress-allowed.
+func (c *Config) EgressPolicy() network.EgressPolicy {
+	policy, err := c.egressPolicy()
+	if err != nil {
+		panic(err) // should be prevented by Validate
+	}
+	return policy
+}
+
+func (c *Config) egressPolicy() (network.EgressPolicy, error) {
+	var policy network.EgressPolicy
+	switch mode := c.asString(EgressPolicyKey); mode {
+	case "", EgressAllow:
+	case EgressDeny:
+		policy.DefaultDeny = true
+	default:
+		return policy, errors.NotValidf("%s %q", EgressPolicyKey, mode)
+	}
+	rules, err := network.ParseEgressRules(c.asString(EgressAllowedKey))
+	if err != nil {
+		return policy, errors.Annotate(err, EgressAllowedKey)
+	}
+	policy.Rules = rules
+	return policy, nil
+}
+
+// ProvisionerParallelism returns the maximum number of instances the
+// provisioner will start concurrently.
+func (c *Config) ProvisionerParallelism() int {
+	if v, ok := c.defined[ProvisionerParallelismKey].(int); ok && v > 0 {
+		return v
+	}
+	return DefaultProvisionerParallelism
+}
+
+// CloudInitUserData returns the operator-supplied cloud-init user data
+// given to the model's machines, or the empty string if there is none.
+func (c *Config) CloudInitUserData() string {
+	return c.asString(CloudInitUserDataKey)
+}
+
+// InternalDNS reports whether internal DNS names are served for the
+// model's units, services and machines.
+func (c *Config) InternalDNS() bool {
+	v, _ := c.defined[InternalDNSKey].(bool)
+	return v
+}
+
+// HookTimeout returns how long a hook may run before it is killed, in
+// the absence of a limit declared by its charm, or 0 if hooks may run
+// indefinitely.
+func (c *Config) HookTimeout() time.Duration {
+	v, _ := c.defined[HookTimeoutKey].(int)
+	return time.Duration(v) * time.Second
+}
+
+// PerUnitHookLock reports whether the hooks of different units on a
+// machine may run concurrently.
+func (c *Config) PerUnitHookLock() bool {
+	v, _ := c.defined[PerUnitHookLockKey].(bool)
+	return v
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1323,6 +1452,13 @@ var alwaysOptional = schema.Defaults{
 	AllowLXCLoopMounts:           false,
 	ResourceTagsKey:              schema.Omit,
 	CloudImageBaseURL:            schema.Omit,
+	EgressPolicyKey:              schema.Omit,
+	EgressAllowedKey:             schema.Omit,
+	ProvisionerParallelismKey:    schema.Omit,
+	CloudInitUserDataKey:         schema.Omit,
+	InternalDNSKey:               schema.Omit,
+	HookTimeoutKey:               schema.Omit,
+	PerUnitHookLockKey:           schema.Omit,
 
 	// AutomaticallyRetryHooks is assumed to be true if missing
 	AutomaticallyRetryHooks: schema.Omit,
@@ -1807,7 +1943,7 @@ global or per instance security groups.`,
 		Group:       environschema.EnvironGroup,
 	},
 	"prefer-ipv6": {
-		Description: `Whether to prefer IPv6 over IPv4 addresses for API endpoints and machines`,
+		Description: `Whether to prefer IPv6 over IPv4 addresses for API endpoints and machines (not supported on ec2 or gce, whose instances only have IPv4 addresses)`,
 		Type:        environschema.Tbool,
 		Immutable:   true,
 		Group:       environschema.EnvironGroup,
@@ -1914,4 +2050,52 @@ data of the store. (default false)`,
 		Immutable:   true,
 		Group:       environschema.EnvironGroup,
 	},
+	EgressPolicyKey: {
+		Description: `Whether outbound traffic from the model's machines is allowed or denied by default.
+
+'allow' (the default) allows all outbound traffic.
+
+'deny' denies outbound traffic to any destination not listed in
+egress-allowed. It is rejected as not supported unless the provider
+supports egress firewalling and firewall-mode is not 'none'.`,
+		Type:   environschema.Tstring,
+		Values: []interface{}{EgressAllow, EgressDeny},
+		Group:  environschema.EnvironGroup,
+	},
+	EgressAllowedKey: {
+		Description: `A comma-separated list of destinations to which outbound traffic is allowed when egress-policy is 'deny', each of the form <cidr>[:<port-range>], e.g. "10.0.0.0/8,0.0.0.0/0:443/tcp"`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ProvisionerParallelismKey: {
+		Description: "The maximum number of instances the provisioner will start concurrently",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	CloudInitUserDataKey: {
+		Description: `Cloud-init user data, in YAML, merged with that Juju generates for each of the model's machines.
+
+The supported keys are "packages", "write_files", "runcmd" and
+"apt_mirror", which are interpreted as they are by cloud-init, and the
+Juju extension "juju-preruncmd", which lists commands to be run before
+the Juju agent is installed. Commands under "runcmd" are run once it
+has been. User data is not supported on Windows series.`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	InternalDNSKey: {
+		Description: `Whether the controller serves DNS names such as <unit-number>.<service>.<model>.<model-uuid>.juju for the model's units, services and machines, and new machines are configured to resolve them`,
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	HookTimeoutKey: {
+		Description: "The number of seconds a hook may run before it is killed and the unit put in an error state, unless its charm declares a limit for that hook; 0 means no limit",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	PerUnitHookLockKey: {
+		Description: "Whether the hooks of different units on a machine may run at the same time; juju-run commands, and hooks and actions their charms declare need the whole machine, still run one at a time",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 }
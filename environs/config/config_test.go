@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 )
@@ -972,6 +973,57 @@ var configTests = []configTest{
 		},
 		err: `resource-tags: expected "key=value", got "a"`,
 	},
+	{
+		about:       "Invalid egress policy",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"egress-policy": "block",
+		},
+		err: `egress-policy: expected one of \[allow deny], got "block"`,
+	},
+	{
+		about:       "Invalid egress allowed destinations",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"egress-policy":  "deny",
+			"egress-allowed": "10.0.0.0/8,example.com",
+		},
+		err: `egress-allowed: egress rule "example.com" not valid`,
+	},
+	{
+		about:       "Invalid provisioner parallelism",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"provisioner-parallelism": 0,
+		},
+		err: `provisioner-parallelism: expected positive integer, got 0`,
+	},
+	{
+		about:       "Invalid hook timeout",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"hook-timeout": -1,
+		},
+		err: `hook-timeout: expected non-negative integer, got -1`,
+	},
+	{
+		about:       "Invalid cloud-init user data",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"cloudinit-userdata": "bootcmd: [reboot]",
+		},
+		err: `cloudinit-userdata: cloud-init user data key "bootcmd" not supported`,
+	},
 	{
 		about:       "Invalid identity URL value",
 		useDefaults: config.UseDefaults,
@@ -1743,6 +1795,77 @@ func (s *ConfigSuite) TestAutoHookRetryTrueEnv(c *gc.C) {
 	c.Assert(config.AutomaticallyRetryHooks(), gc.Equals, true)
 }
 
+func (s *ConfigSuite) TestEgressPolicyDefault(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.EgressPolicy(), jc.DeepEquals, network.EgressPolicy{})
+}
+
+func (s *ConfigSuite) TestEgressPolicy(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{
+		"egress-policy":  "deny",
+		"egress-allowed": "10.0.0.0/8, 0.0.0.0/0:443/tcp",
+	})
+	c.Assert(config.EgressPolicy(), jc.DeepEquals, network.EgressPolicy{
+		DefaultDeny: true,
+		Rules: []network.EgressRule{{
+			DestinationCIDR: "10.0.0.0/8",
+		}, {
+			DestinationCIDR: "0.0.0.0/0",
+			PortRange:       network.MustParsePortRange("443/tcp"),
+		}},
+	})
+}
+
+func (s *ConfigSuite) TestProvisionerParallelism(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ProvisionerParallelism(), gc.Equals, config.DefaultProvisionerParallelism)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"provisioner-parallelism": 3,
+	})
+	c.Assert(cfg.ProvisionerParallelism(), gc.Equals, 3)
+}
+
+func (s *ConfigSuite) TestCloudInitUserData(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.CloudInitUserData(), gc.Equals, "")
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"cloudinit-userdata": "packages: [curl]",
+	})
+	c.Assert(cfg.CloudInitUserData(), gc.Equals, "packages: [curl]")
+}
+
+func (s *ConfigSuite) TestInternalDNS(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.InternalDNS(), jc.IsFalse)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"internal-dns": true,
+	})
+	c.Assert(cfg.InternalDNS(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestHookTimeout(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.HookTimeout(), gc.Equals, time.Duration(0))
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"hook-timeout": 300,
+	})
+	c.Assert(cfg.HookTimeout(), gc.Equals, 5*time.Minute)
+}
+
+func (s *ConfigSuite) TestPerUnitHookLock(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.PerUnitHookLock(), jc.IsFalse)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"per-unit-hook-lock": true,
+	})
+	c.Assert(cfg.PerUnitHookLock(), jc.IsTrue)
+}
+
 func (s *ConfigSuite) TestCloudImageBaseURL(c *gc.C) {
 	s.addJujuFiles(c)
 	config := newTestConfig(c, testing.Attrs{})
@@ -3,7 +3,18 @@
 
 package environs
 
+import (
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
 var (
 	Providers       = &globalProviders.providers
 	ProviderAliases = &globalProviders.aliases
 )
+
+// NewEgressConfigValidator returns the state.ConfigValidator used by
+// the state policy, wrapping v and opening environs with newEnviron.
+func NewEgressConfigValidator(v state.ConfigValidator, newEnviron func(*config.Config) (Environ, error)) state.ConfigValidator {
+	return egressConfigValidator{v, newEnviron}
+}
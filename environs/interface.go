@@ -235,6 +235,80 @@ type Firewaller interface {
 	Ports() ([]network.PortRange, error)
 }
 
+// IngressRuleFirewaller is an optional interface that may be implemented
+// by an Environ whose firewall can restrict access to ports by source
+// address. Instances of such an Environ must implement the
+// instance.IngressRuleFirewaller interface.
+type IngressRuleFirewaller interface {
+	// OpenIngressRules opens the given ingress rules for the whole
+	// environment. Must only be used if the environment was setup
+	// with the FwGlobal firewall mode.
+	OpenIngressRules(rules []network.IngressRule) error
+
+	// CloseIngressRules closes the given ingress rules for the whole
+	// environment. Must only be used if the environment was setup
+	// with the FwGlobal firewall mode.
+	CloseIngressRules(rules []network.IngressRule) error
+
+	// IngressRules returns the ingress rules opened for the whole
+	// environment, sorted by network.SortIngressRules(). Must only
+	// be used if the environment was setup with the FwGlobal
+	// firewall mode.
+	IngressRules() ([]network.IngressRule, error)
+}
+
+// SubnetIngressRuleFirewaller is an optional interface that may be
+// implemented by an Environ whose instances can restrict ingress rules
+// to their network interfaces on particular subnets. Instances of such
+// an Environ must implement the instance.IngressRuleFirewaller
+// interface, and honour the DestinationCIDRs of the rules they are
+// given.
+type SubnetIngressRuleFirewaller interface {
+	// SupportsSubnetIngressRules reports whether the environ's
+	// instances can restrict ingress rules to particular subnets.
+	SupportsSubnetIngressRules() bool
+}
+
+// EgressFirewaller is an optional interface that may be implemented by
+// an Environ whose firewall can restrict outbound traffic from the
+// model's machines.
+type EgressFirewaller interface {
+	// SetEgressPolicy applies the given policy to outbound traffic
+	// from all of the model's machines, replacing any policy
+	// previously applied.
+	SetEgressPolicy(policy network.EgressPolicy) error
+
+	// EgressPolicy returns the policy currently applied to outbound
+	// traffic from the model's machines.
+	EgressPolicy() (network.EgressPolicy, error)
+}
+
+// LoadBalancerManager is an optional interface that may be implemented
+// by an Environ that can place a load balancer in front of a set of its
+// instances, for services exposed through a load balancer.
+type LoadBalancerManager interface {
+	// EnsureLoadBalancer creates the named load balancer, or updates
+	// it if it already exists, so that it forwards the given ports to
+	// the given instances and accepts traffic only from addresses
+	// within the given source CIDRs. If no source CIDRs are given,
+	// traffic is accepted from anywhere. The load balancer's virtual
+	// address is returned.
+	EnsureLoadBalancer(
+		name string,
+		ports []network.PortRange,
+		sourceCIDRs []string,
+		instanceIds []instance.Id,
+	) (network.Address, error)
+
+	// RemoveLoadBalancer removes the named load balancer. It is not an
+	// error to remove a load balancer that does not exist.
+	RemoveLoadBalancer(name string) error
+
+	// LoadBalancerNames returns the names of the load balancers
+	// created with EnsureLoadBalancer that have not been removed.
+	LoadBalancerNames() ([]string, error)
+}
+
 // InstanceTagger is an interface that can be used for tagging instances.
 type InstanceTagger interface {
 	// TagInstance tags the given instance with the specified tags.
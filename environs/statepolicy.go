@@ -32,7 +32,50 @@ func (environStatePolicy) Prechecker(cfg *config.Config) (state.Prechecker, erro
 
 func (environStatePolicy) ConfigValidator(providerType string) (state.ConfigValidator, error) {
 	// EnvironProvider implements state.ConfigValidator.
-	return Provider(providerType)
+	provider, err := Provider(providerType)
+	if err != nil {
+		return nil, err
+	}
+	return egressConfigValidator{provider, New}, nil
+}
+
+// egressConfigValidator validates model config with the provider, and
+// then rejects an egress policy that the model's environ cannot enforce.
+type egressConfigValidator struct {
+	state.ConfigValidator
+	newEnviron func(*config.Config) (Environ, error)
+}
+
+// Validate is part of the state.ConfigValidator interface.
+func (v egressConfigValidator) Validate(cfg, old *config.Config) (*config.Config, error) {
+	valid, err := v.ConfigValidator.Validate(cfg, old)
+	if err != nil {
+		return nil, err
+	}
+	policy := valid.EgressPolicy()
+	if !policy.DefaultDeny || old != nil && policy.Equal(old.EgressPolicy()) {
+		return valid, nil
+	}
+	env, err := v.newEnviron(valid)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot check egress policy support")
+	}
+	if !EgressPolicyEnforced(env) {
+		return nil, errors.NotSupportedf(
+			"%s %q with %q provider and firewall-mode %q",
+			config.EgressPolicyKey, config.EgressDeny, valid.Type(), valid.FirewallMode(),
+		)
+	}
+	return valid, nil
+}
+
+// EgressPolicyEnforced reports whether the egress policy of the
+// environ's model is enforced by its firewall.
+func EgressPolicyEnforced(env Environ) bool {
+	if _, ok := env.(EgressFirewaller); !ok {
+		return false
+	}
+	return env.Config().FirewallMode() != config.FwNone
 }
 
 func (environStatePolicy) EnvironCapability(cfg *config.Config) (state.EnvironCapability, error) {
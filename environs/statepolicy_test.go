@@ -0,0 +1,84 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type statePolicySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&statePolicySuite{})
+
+type passValidator struct{}
+
+func (passValidator) Validate(cfg, old *config.Config) (*config.Config, error) {
+	return cfg, nil
+}
+
+type mockEnviron struct {
+	environs.Environ
+	cfg *config.Config
+}
+
+func (e *mockEnviron) Config() *config.Config {
+	return e.cfg
+}
+
+type mockEgressEnviron struct {
+	mockEnviron
+}
+
+func (*mockEgressEnviron) SetEgressPolicy(network.EgressPolicy) error {
+	return nil
+}
+
+func (*mockEgressEnviron) EgressPolicy() (network.EgressPolicy, error) {
+	return network.EgressPolicy{}, nil
+}
+
+func (s *statePolicySuite) validate(c *gc.C, supported bool, attrs testing.Attrs) error {
+	cfg, err := testing.ModelConfig(c).Apply(attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	newEnviron := func(cfg *config.Config) (environs.Environ, error) {
+		if supported {
+			return &mockEgressEnviron{mockEnviron{cfg: cfg}}, nil
+		}
+		return &mockEnviron{cfg: cfg}, nil
+	}
+	validator := environs.NewEgressConfigValidator(passValidator{}, newEnviron)
+	_, err = validator.Validate(cfg, nil)
+	return err
+}
+
+func (s *statePolicySuite) TestEgressAllowAlwaysValid(c *gc.C) {
+	err := s.validate(c, false, testing.Attrs{"egress-policy": "allow"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *statePolicySuite) TestEgressDenySupported(c *gc.C) {
+	err := s.validate(c, true, testing.Attrs{"egress-policy": "deny"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *statePolicySuite) TestEgressDenyNotSupported(c *gc.C) {
+	err := s.validate(c, false, testing.Attrs{"egress-policy": "deny"})
+	c.Assert(err, gc.ErrorMatches, `egress-policy "deny" with "someprovider" provider and firewall-mode "instance" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *statePolicySuite) TestEgressDenyFirewallModeNone(c *gc.C) {
+	err := s.validate(c, true, testing.Attrs{"egress-policy": "deny", "firewall-mode": "none"})
+	c.Assert(err, gc.ErrorMatches, `egress-policy "deny" with "someprovider" provider and firewall-mode "none" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -231,6 +231,9 @@ block:
   provider: loop
   attrs:
     it: works
+  usage:
+    count: 0
+    size: 0
 ebs:
   provider: ebs
 loop:
@@ -247,12 +250,12 @@ func (s *cmdStorageSuite) TestListPoolsTabular(c *gc.C) {
 	stdout, _, err := runPoolList(c, "--format", "tabular")
 	c.Assert(err, jc.ErrorIsNil)
 	expected := `
-NAME    PROVIDER  ATTRS
-block   loop      it=works
-ebs     ebs       
-loop    loop      
-rootfs  rootfs    
-tmpfs   tmpfs     
+NAME    PROVIDER  USED    ATTRS
+block   loop      0 (0B)  it=works
+ebs     ebs               
+loop    loop              
+rootfs  rootfs            
+tmpfs   tmpfs             
 
 `[1:]
 	c.Assert(stdout, gc.Equals, expected)
@@ -266,6 +269,9 @@ block:
   provider: loop
   attrs:
     it: works
+  usage:
+    count: 0
+    size: 0
 `[1:]
 	c.Assert(stdout, gc.Equals, expected)
 }
@@ -291,6 +297,9 @@ block:
   provider: loop
   attrs:
     it: works
+  usage:
+    count: 0
+    size: 0
 loop:
   provider: loop
 `[1:]
@@ -328,6 +337,9 @@ block:
   provider: loop
   attrs:
     it: works
+  usage:
+    count: 0
+    size: 0
 `[1:]
 	c.Assert(stdout, gc.Equals, expected)
 }
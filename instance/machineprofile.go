@@ -0,0 +1,17 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instance
+
+import (
+	"regexp"
+)
+
+var validMachineProfileName = regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$")
+
+// IsValidMachineProfileName reports whether name is a valid machine
+// profile name: groups of lower-case letters and digits, separated by
+// single hyphens.
+func IsValidMachineProfileName(name string) bool {
+	return validMachineProfileName.MatchString(name)
+}
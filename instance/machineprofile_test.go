@@ -0,0 +1,24 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instance_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+)
+
+type MachineProfileSuite struct{}
+
+var _ = gc.Suite(&MachineProfileSuite{})
+
+func (s *MachineProfileSuite) TestIsValidMachineProfileName(c *gc.C) {
+	for _, name := range []string{"web", "m4-large", "2016", "db-2", "a1-b2-c3"} {
+		c.Check(instance.IsValidMachineProfileName(name), jc.IsTrue, gc.Commentf("%q", name))
+	}
+	for _, name := range []string{"", "Web", "web-", "-web", "web--db", "web_db", "web db", "web/db"} {
+		c.Check(instance.IsValidMachineProfileName(name), jc.IsFalse, gc.Commentf("%q", name))
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instance
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// ZoneSpread is the zone policy that spreads a service's units
+	// across availability zones, always starting a new machine in
+	// one of the zones holding the fewest of the service's units.
+	ZoneSpread = "spread"
+
+	// ZonePack is the zone policy that keeps a service's units in as
+	// few availability zones as possible.
+	ZonePack = "pack"
+
+	// zonesPrefix introduces the list of zones in a zone policy that
+	// restricts the zones a service's units may be placed in.
+	zonesPrefix = "zones="
+)
+
+// AvailabilityZone describes a provider availability zone.
+type AvailabilityZone interface {
+	// Name returns the name of the availability zone.
+	Name() string
+
+	// Available reports whether the availability zone is currently available.
+	Available() bool
+}
+
+// ZonePolicy describes how the machines created for a service's units
+// are placed into availability zones.
+type ZonePolicy struct {
+	// Pack is true if units should be kept in as few zones as
+	// possible, rather than spread across them.
+	Pack bool
+
+	// Zones, if non-empty, holds the only zones in which units may
+	// be placed. Units are spread across the listed zones.
+	Zones []string
+}
+
+// ParseZonePolicy parses a zone policy of the form "spread", "pack",
+// or "zones=<zone>[,<zone>...]".
+func ParseZonePolicy(s string) (ZonePolicy, error) {
+	switch {
+	case s == ZoneSpread:
+		return ZonePolicy{}, nil
+	case s == ZonePack:
+		return ZonePolicy{Pack: true}, nil
+	case strings.HasPrefix(s, zonesPrefix):
+		var zones []string
+		for _, zone := range strings.Split(s[len(zonesPrefix):], ",") {
+			zone = strings.TrimSpace(zone)
+			if zone == "" {
+				return ZonePolicy{}, errors.NotValidf("zone policy %q", s)
+			}
+			zones = append(zones, zone)
+		}
+		return ZonePolicy{Zones: zones}, nil
+	}
+	return ZonePolicy{}, errors.NotValidf("zone policy %q", s)
+}
+
+// String returns the policy in the form accepted by ParseZonePolicy.
+func (p ZonePolicy) String() string {
+	switch {
+	case len(p.Zones) > 0:
+		return zonesPrefix + strings.Join(p.Zones, ",")
+	case p.Pack:
+		return ZonePack
+	}
+	return ZoneSpread
+}
+
+// ChooseZone returns the zone, out of those available, in which a new
+// machine for one of a service's units should be started, given the
+// number of the service's units already in each zone. Ties are broken
+// by zone name, so the choice is deterministic. A NotFound error is
+// returned if the policy permits none of the available zones.
+func (p ZonePolicy) ChooseZone(available []string, population map[string]int) (string, error) {
+	var candidates []string
+	for _, zone := range available {
+		if !p.Permits(zone) {
+			continue
+		}
+		candidates = append(candidates, zone)
+	}
+	if len(candidates) == 0 {
+		return "", errors.NotFoundf("availability zone permitted by zone policy %q", p)
+	}
+	sort.Strings(candidates)
+	best := candidates[0]
+	for _, zone := range candidates[1:] {
+		n, bestN := population[zone], population[best]
+		if p.Pack && n > bestN || !p.Pack && n < bestN {
+			best = zone
+		}
+	}
+	return best, nil
+}
+
+// Strict reports whether the policy restricts units to particular
+// zones. A strict policy is never relaxed: a unit's machine is not
+// started at all if the policy cannot be applied to it.
+func (p ZonePolicy) Strict() bool {
+	return len(p.Zones) > 0
+}
+
+// Permits reports whether the policy allows units to be placed in the
+// specified zone.
+func (p ZonePolicy) Permits(zone string) bool {
+	if len(p.Zones) == 0 {
+		return true
+	}
+	for _, z := range p.Zones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
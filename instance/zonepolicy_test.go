@@ -0,0 +1,124 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instance_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+)
+
+type ZonePolicySuite struct{}
+
+var _ = gc.Suite(&ZonePolicySuite{})
+
+func (s *ZonePolicySuite) TestParseZonePolicy(c *gc.C) {
+	for i, test := range []struct {
+		arg    string
+		expect instance.ZonePolicy
+		err    string
+	}{{
+		arg: "spread",
+	}, {
+		arg:    "pack",
+		expect: instance.ZonePolicy{Pack: true},
+	}, {
+		arg:    "zones=a,b",
+		expect: instance.ZonePolicy{Zones: []string{"a", "b"}},
+	}, {
+		arg:    "zones= a , b",
+		expect: instance.ZonePolicy{Zones: []string{"a", "b"}},
+	}, {
+		arg: "",
+		err: `zone policy "" not valid`,
+	}, {
+		arg: "zones=",
+		err: `zone policy "zones=" not valid`,
+	}, {
+		arg: "zones=a,,b",
+		err: `zone policy "zones=a,,b" not valid`,
+	}, {
+		arg: "scatter",
+		err: `zone policy "scatter" not valid`,
+	}} {
+		c.Logf("test %d: %q", i, test.arg)
+		policy, err := instance.ParseZonePolicy(test.arg)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(policy, jc.DeepEquals, test.expect)
+	}
+}
+
+func (s *ZonePolicySuite) TestString(c *gc.C) {
+	for _, arg := range []string{"spread", "pack", "zones=a,b"} {
+		policy, err := instance.ParseZonePolicy(arg)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(policy.String(), gc.Equals, arg)
+	}
+}
+
+func (s *ZonePolicySuite) TestChooseZone(c *gc.C) {
+	available := []string{"c", "a", "b"}
+	population := map[string]int{"a": 2, "b": 1, "c": 1}
+	for i, test := range []struct {
+		policy string
+		expect string
+		err    string
+	}{{
+		policy: "spread",
+		expect: "b",
+	}, {
+		policy: "pack",
+		expect: "a",
+	}, {
+		policy: "zones=a,c",
+		expect: "c",
+	}, {
+		policy: "zones=a,d",
+		expect: "a",
+	}, {
+		policy: "zones=d",
+		err:    `availability zone permitted by zone policy "zones=d" not found`,
+	}} {
+		c.Logf("test %d: %s", i, test.policy)
+		policy, err := instance.ParseZonePolicy(test.policy)
+		c.Assert(err, jc.ErrorIsNil)
+		zone, err := policy.ChooseZone(available, population)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			c.Check(err, jc.Satisfies, errors.IsNotFound)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(zone, gc.Equals, test.expect)
+	}
+}
+
+func (s *ZonePolicySuite) TestChooseZoneEmptyPopulation(c *gc.C) {
+	zone, err := instance.ZonePolicy{}.ChooseZone([]string{"b", "a"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zone, gc.Equals, "a")
+	zone, err = instance.ZonePolicy{Pack: true}.ChooseZone([]string{"b", "a"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zone, gc.Equals, "a")
+}
+
+func (s *ZonePolicySuite) TestPermits(c *gc.C) {
+	c.Assert(instance.ZonePolicy{}.Permits("a"), jc.IsTrue)
+	policy := instance.ZonePolicy{Zones: []string{"a", "b"}}
+	c.Assert(policy.Permits("b"), jc.IsTrue)
+	c.Assert(policy.Permits("c"), jc.IsFalse)
+}
+
+func (s *ZonePolicySuite) TestStrict(c *gc.C) {
+	c.Check(instance.ZonePolicy{}.Strict(), jc.IsFalse)
+	c.Check(instance.ZonePolicy{Pack: true}.Strict(), jc.IsFalse)
+	c.Check(instance.ZonePolicy{Zones: []string{"a"}}.Strict(), jc.IsTrue)
+}
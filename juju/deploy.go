@@ -35,6 +35,12 @@ type DeployServiceParams struct {
 	EndpointBindings map[string]string
 	// Resources is a map of resource name to IDs of pending resources.
 	Resources map[string]string
+	// CloudInitUserData holds operator-supplied cloud-init user data
+	// given to the machines created for the service's units.
+	CloudInitUserData string
+	// RootDiskSource names the source from which the root disks of the
+	// machines created for the service's units are created.
+	RootDiskSource string
 }
 
 type ServiceDeployer interface {
@@ -91,6 +97,8 @@ func DeployService(st ServiceDeployer, args DeployServiceParams) (*state.Service
 
 	if !args.Charm.Meta().Subordinate {
 		asa.Constraints = args.Constraints
+		asa.CloudInitUserData = args.CloudInitUserData
+		asa.RootDiskSource = args.RootDiskSource
 	}
 
 	// TODO(dimitern): In a follow-up drop Networks and use spaces
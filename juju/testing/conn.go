@@ -287,6 +287,7 @@ func (s *JujuConnSuite) setUpConn(c *gc.C) {
 
 	s.State, err = newState(environ, s.BackingState.MongoConnectionInfo())
 	c.Assert(err, jc.ErrorIsNil)
+	s.State.SetSecretsKey(s.BackingState.SecretsKey())
 
 	apiInfo, err := environs.APIInfo(environ)
 	c.Assert(err, jc.ErrorIsNil)
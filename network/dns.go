@@ -0,0 +1,79 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"sort"
+	"strings"
+)
+
+// InternalDNSDomain is the top-level domain under which the internal
+// DNS names of a model's units, services and machines are created.
+const InternalDNSDomain = "juju"
+
+// DNSRecord associates a fully qualified internal DNS name with the
+// addresses it resolves to.
+type DNSRecord struct {
+	// Name holds the fully qualified name, without a trailing dot.
+	Name string
+
+	// Addresses holds the IPv4 and IPv6 address values the name
+	// resolves to, sorted.
+	Addresses []string
+}
+
+// ModelDNSDomain returns the internal DNS domain of the model with the
+// given name and UUID, e.g. "admin.<model-uuid>.juju". Model names are
+// only unique per owner, so the UUID keeps the domains of models with
+// the same name, served by the same controller, apart.
+func ModelDNSDomain(modelName, modelUUID string) string {
+	return modelName + "." + modelUUID + "." + InternalDNSDomain
+}
+
+// ModelUUIDFromDNSName returns the UUID of the model whose internal
+// DNS domain the given name is in, or "" if it is not in one.
+func ModelUUIDFromDNSName(name string) string {
+	labels := strings.Split(strings.TrimSuffix(name, "."), ".")
+	if len(labels) < 3 || labels[len(labels)-1] != InternalDNSDomain {
+		return ""
+	}
+	return labels[len(labels)-2]
+}
+
+// ServiceDNSName returns the internal DNS name of the named service in
+// the given model domain, which resolves to the addresses of all its
+// units, e.g. "wordpress.admin.<model-uuid>.juju".
+func ServiceDNSName(serviceName, modelDomain string) string {
+	return serviceName + "." + modelDomain
+}
+
+// UnitDNSName returns the internal DNS name of the named unit in the
+// given model domain, e.g. "0.wordpress.admin.<model-uuid>.juju" for
+// unit "wordpress/0".
+func UnitDNSName(unitName, modelDomain string) string {
+	parts := strings.SplitN(unitName, "/", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	return parts[1] + "." + ServiceDNSName(parts[0], modelDomain)
+}
+
+// MachineDNSName returns the internal DNS name of the machine with the
+// given id in the given model domain, e.g.
+// "machine-0-lxc-1.admin.<model-uuid>.juju" for machine "0/lxc/1".
+// Service names cannot take this form, so the names never clash.
+func MachineDNSName(machineId, modelDomain string) string {
+	return "machine-" + strings.Replace(machineId, "/", "-", -1) + "." + modelDomain
+}
+
+// SortDNSRecords sorts the given records by name.
+func SortDNSRecords(records []DNSRecord) {
+	sort.Sort(dnsRecordsByName(records))
+}
+
+type dnsRecordsByName []DNSRecord
+
+func (r dnsRecordsByName) Len() int           { return len(r) }
+func (r dnsRecordsByName) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r dnsRecordsByName) Less(i, j int) bool { return r[i].Name < r[j].Name }
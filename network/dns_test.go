@@ -0,0 +1,55 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type DNSSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&DNSSuite{})
+
+func (*DNSSuite) TestNames(c *gc.C) {
+	domain := network.ModelDNSDomain("admin", "deadbeef-0bad-400d-8000-4b1d0d06f00d")
+	c.Assert(domain, gc.Equals, "admin.deadbeef-0bad-400d-8000-4b1d0d06f00d.juju")
+	c.Assert(network.ServiceDNSName("wordpress", "admin.uuid.juju"), gc.Equals, "wordpress.admin.uuid.juju")
+	c.Assert(network.UnitDNSName("wordpress/0", "admin.uuid.juju"), gc.Equals, "0.wordpress.admin.uuid.juju")
+	c.Assert(network.UnitDNSName("wordpress", "admin.uuid.juju"), gc.Equals, "")
+	c.Assert(network.MachineDNSName("0", "admin.uuid.juju"), gc.Equals, "machine-0.admin.uuid.juju")
+	c.Assert(network.MachineDNSName("0/lxc/1", "admin.uuid.juju"), gc.Equals, "machine-0-lxc-1.admin.uuid.juju")
+}
+
+func (*DNSSuite) TestModelUUIDFromDNSName(c *gc.C) {
+	for name, uuid := range map[string]string{
+		"0.wordpress.admin.uuid.juju":  "uuid",
+		"machine-0.admin.uuid.juju.":   "uuid",
+		"admin.uuid.juju":              "uuid",
+		"uuid.juju":                    "",
+		"juju":                         "",
+		"wordpress.admin.uuid.example": "",
+	} {
+		c.Check(network.ModelUUIDFromDNSName(name), gc.Equals, uuid, gc.Commentf("%q", name))
+	}
+}
+
+func (*DNSSuite) TestSortDNSRecords(c *gc.C) {
+	records := []network.DNSRecord{
+		{Name: "wordpress.admin.juju"},
+		{Name: "0.wordpress.admin.juju"},
+		{Name: "machine-0.admin.juju"},
+	}
+	network.SortDNSRecords(records)
+	c.Assert(records, jc.DeepEquals, []network.DNSRecord{
+		{Name: "0.wordpress.admin.juju"},
+		{Name: "machine-0.admin.juju"},
+		{Name: "wordpress.admin.juju"},
+	})
+}
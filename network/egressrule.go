@@ -0,0 +1,118 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// EgressRule represents a destination to which outbound traffic is
+// allowed. An EgressRule with a zero PortRange allows traffic to any
+// port, using any protocol.
+type EgressRule struct {
+	// DestinationCIDR holds the CIDR of the destination addresses.
+	DestinationCIDR string
+
+	// PortRange holds the destination ports, if restricted.
+	PortRange PortRange
+}
+
+// ParseEgressRule parses an egress rule of the form
+// "<cidr>[:<port-range>]", where the port range is in the
+// form accepted by ParsePortRange.
+// Example strings: "10.0.0.0/8", "0.0.0.0/0:443/tcp",
+// "192.168.0.0/16:8000-8080/udp".
+func ParseEgressRule(s string) (EgressRule, error) {
+	parts := strings.SplitN(s, ":", 2)
+	_, ipNet, err := net.ParseCIDR(parts[0])
+	if err != nil {
+		return EgressRule{}, errors.NotValidf("egress rule %q", s)
+	}
+	rule := EgressRule{DestinationCIDR: ipNet.String()}
+	if len(parts) == 2 {
+		rule.PortRange, err = ParsePortRange(parts[1])
+		if err != nil {
+			return EgressRule{}, errors.Annotatef(err, "invalid egress rule %q", s)
+		}
+	}
+	return rule, nil
+}
+
+// AllPorts reports whether the rule allows traffic to any port.
+func (r EgressRule) AllPorts() bool {
+	return r.PortRange == PortRange{}
+}
+
+func (r EgressRule) String() string {
+	if r.AllPorts() {
+		return r.DestinationCIDR
+	}
+	return fmt.Sprintf("%s:%s", r.DestinationCIDR, r.PortRange)
+}
+
+func (r EgressRule) GoString() string {
+	return r.String()
+}
+
+// EgressPolicy describes the outbound traffic allowed from the
+// machines in a model.
+type EgressPolicy struct {
+	// DefaultDeny is true if outbound traffic that is not allowed
+	// by one of the rules is denied. Otherwise all outbound traffic
+	// is allowed, and the rules have no effect.
+	DefaultDeny bool
+
+	// Rules holds the destinations to which outbound traffic is
+	// allowed.
+	Rules []EgressRule
+}
+
+// ParseEgressRules parses a comma-separated list of egress rules, in
+// the form accepted by ParseEgressRule. Whitespace is ignored.
+func ParseEgressRules(s string) ([]EgressRule, error) {
+	var rules []EgressRule
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		rule, err := ParseEgressRule(part)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Equal reports whether the two egress policies are the same.
+func (a EgressPolicy) Equal(b EgressPolicy) bool {
+	if a.DefaultDeny != b.DefaultDeny || len(a.Rules) != len(b.Rules) {
+		return false
+	}
+	for i, rule := range a.Rules {
+		if b.Rules[i] != rule {
+			return false
+		}
+	}
+	return true
+}
+
+func (p EgressPolicy) String() string {
+	if !p.DefaultDeny {
+		return "allow all"
+	}
+	if len(p.Rules) == 0 {
+		return "deny all"
+	}
+	rules := make([]string, len(p.Rules))
+	for i, rule := range p.Rules {
+		rules[i] = rule.String()
+	}
+	return fmt.Sprintf("deny all except %s", strings.Join(rules, ","))
+}
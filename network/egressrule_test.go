@@ -0,0 +1,80 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type EgressRuleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&EgressRuleSuite{})
+
+func (*EgressRuleSuite) TestParseEgressRule(c *gc.C) {
+	rule, err := network.ParseEgressRule("10.1.2.3/8")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule, jc.DeepEquals, network.EgressRule{DestinationCIDR: "10.0.0.0/8"})
+	c.Assert(rule.AllPorts(), jc.IsTrue)
+	c.Assert(rule.String(), gc.Equals, "10.0.0.0/8")
+
+	rule, err = network.ParseEgressRule("0.0.0.0/0:8000-8080/udp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule, jc.DeepEquals, network.EgressRule{
+		DestinationCIDR: "0.0.0.0/0",
+		PortRange:       network.PortRange{FromPort: 8000, ToPort: 8080, Protocol: "udp"},
+	})
+	c.Assert(rule.AllPorts(), jc.IsFalse)
+	c.Assert(rule.String(), gc.Equals, "0.0.0.0/0:8000-8080/udp")
+}
+
+func (*EgressRuleSuite) TestParseEgressRuleInvalid(c *gc.C) {
+	_, err := network.ParseEgressRule("10.0.0.0")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	_, err = network.ParseEgressRule("10.0.0.0/8:http")
+	c.Assert(err, gc.ErrorMatches, `invalid egress rule "10.0.0.0/8:http": invalid port "http".*`)
+}
+
+func (*EgressRuleSuite) TestParseEgressRules(c *gc.C) {
+	rules, err := network.ParseEgressRules(" 10.0.0.0/8, 0.0.0.0/0:443/tcp,")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+	}, {
+		DestinationCIDR: "0.0.0.0/0",
+		PortRange:       network.MustParsePortRange("443/tcp"),
+	}})
+
+	rules, err = network.ParseEgressRules("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+}
+
+func (*EgressRuleSuite) TestEgressPolicyString(c *gc.C) {
+	policy := network.EgressPolicy{}
+	c.Assert(policy.String(), gc.Equals, "allow all")
+	policy.DefaultDeny = true
+	c.Assert(policy.String(), gc.Equals, "deny all")
+	policy.Rules = []network.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+	}, {
+		DestinationCIDR: "0.0.0.0/0",
+		PortRange:       network.MustParsePortRange("53/udp"),
+	}}
+	c.Assert(policy.String(), gc.Equals, "deny all except 10.0.0.0/8,0.0.0.0/0:53/udp")
+}
+
+func (*EgressRuleSuite) TestEgressPolicyEqual(c *gc.C) {
+	rule := network.EgressRule{DestinationCIDR: "10.0.0.0/8"}
+	a := network.EgressPolicy{DefaultDeny: true, Rules: []network.EgressRule{rule}}
+	c.Assert(a.Equal(network.EgressPolicy{DefaultDeny: true, Rules: []network.EgressRule{rule}}), jc.IsTrue)
+	c.Assert(a.Equal(network.EgressPolicy{DefaultDeny: true}), jc.IsFalse)
+	c.Assert(a.Equal(network.EgressPolicy{Rules: []network.EgressRule{rule}}), jc.IsFalse)
+}
@@ -0,0 +1,237 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// AnyIPv4CIDR is the CIDR that provider firewalls use to allow
+	// access from any IPv4 address.
+	AnyIPv4CIDR = "0.0.0.0/0"
+
+	// AnyIPv6CIDR is the CIDR that provider firewalls use to allow
+	// access from any IPv6 address.
+	AnyIPv6CIDR = "::/0"
+)
+
+// IngressRule represents a range of ports that may be accessed from
+// a set of source address ranges. An IngressRule with no source CIDRs
+// allows access from anywhere.
+type IngressRule struct {
+	PortRange
+
+	// SourceCIDRs holds the CIDRs of the addresses that are
+	// allowed to access the ports, sorted and free of duplicates.
+	SourceCIDRs []string
+
+	// DestinationCIDRs, if not empty, holds the CIDRs of the subnets
+	// on which the ports are opened, sorted and free of duplicates;
+	// the ports are only accessible through an instance's interfaces
+	// on those subnets. A rule with no destination CIDRs applies to
+	// all of an instance's interfaces. Only instances of an Environ
+	// implementing environs.SubnetIngressRuleFirewaller are given
+	// rules with destination CIDRs.
+	DestinationCIDRs []string
+}
+
+// NewIngressRule returns an IngressRule for the given port range and
+// source CIDRs. The source CIDRs are validated, sorted and deduplicated.
+func NewIngressRule(portRange PortRange, sourceCIDRs ...string) (IngressRule, error) {
+	cidrs, err := NormaliseCIDRs(sourceCIDRs)
+	if err != nil {
+		return IngressRule{}, errors.Trace(err)
+	}
+	return IngressRule{PortRange: portRange, SourceCIDRs: cidrs}, nil
+}
+
+// MustNewIngressRule is like NewIngressRule, but panics on error.
+func MustNewIngressRule(portRange PortRange, sourceCIDRs ...string) IngressRule {
+	rule, err := NewIngressRule(portRange, sourceCIDRs...)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// NormaliseCIDRs validates the given CIDRs, and returns them sorted
+// and free of duplicates. A nil slice is returned if there are no
+// CIDRs.
+func NormaliseCIDRs(cidrs []string) ([]string, error) {
+	if len(cidrs) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool)
+	var result []string
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.NotValidf("CIDR %q", cidr)
+		}
+		cidr = ipNet.String()
+		if seen[cidr] {
+			continue
+		}
+		seen[cidr] = true
+		result = append(result, cidr)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// Validate determines if the ingress rule is valid.
+func (r IngressRule) Validate() error {
+	if err := r.PortRange.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	for _, cidr := range r.SourceCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("CIDR %q", cidr)
+		}
+	}
+	for _, cidr := range r.DestinationCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("destination CIDR %q", cidr)
+		}
+	}
+	return nil
+}
+
+// Equal reports whether the two ingress rules are the same.
+func (a IngressRule) Equal(b IngressRule) bool {
+	return a.PortRange == b.PortRange &&
+		cidrsEqual(a.SourceCIDRs, b.SourceCIDRs) &&
+		cidrsEqual(a.DestinationCIDRs, b.DestinationCIDRs)
+}
+
+func cidrsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, cidr := range a {
+		if b[i] != cidr {
+			return false
+		}
+	}
+	return true
+}
+
+func (r IngressRule) String() string {
+	s := r.PortRange.String()
+	if len(r.SourceCIDRs) > 0 {
+		s = fmt.Sprintf("%s from %s", s, strings.Join(r.SourceCIDRs, ","))
+	}
+	if len(r.DestinationCIDRs) > 0 {
+		s = fmt.Sprintf("%s to %s", s, strings.Join(r.DestinationCIDRs, ","))
+	}
+	return s
+}
+
+func (r IngressRule) GoString() string {
+	return r.String()
+}
+
+type ingressRuleSlice []IngressRule
+
+func (r ingressRuleSlice) Len() int      { return len(r) }
+func (r ingressRuleSlice) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r ingressRuleSlice) Less(i, j int) bool {
+	if r[i].PortRange != r[j].PortRange {
+		return portRangeSlice{r[i].PortRange, r[j].PortRange}.Less(0, 1)
+	}
+	iSource, jSource := strings.Join(r[i].SourceCIDRs, ","), strings.Join(r[j].SourceCIDRs, ",")
+	if iSource != jSource {
+		return iSource < jSource
+	}
+	return strings.Join(r[i].DestinationCIDRs, ",") < strings.Join(r[j].DestinationCIDRs, ",")
+}
+
+// SortIngressRules sorts the given rules, first by port range,
+// then by source CIDRs, then by destination CIDRs.
+func SortIngressRules(rules []IngressRule) {
+	sort.Sort(ingressRuleSlice(rules))
+}
+
+// IngressRulesFromPortRanges returns ingress rules allowing access
+// to each of the given port ranges from anywhere.
+func IngressRulesFromPortRanges(portRanges []PortRange) []IngressRule {
+	if len(portRanges) == 0 {
+		return nil
+	}
+	rules := make([]IngressRule, len(portRanges))
+	for i, portRange := range portRanges {
+		rules[i] = IngressRule{PortRange: portRange}
+	}
+	return rules
+}
+
+// IngressRulePortRanges returns the distinct port ranges of the
+// given ingress rules.
+func IngressRulePortRanges(rules []IngressRule) []PortRange {
+	seen := make(map[PortRange]bool)
+	var portRanges []PortRange
+	for _, rule := range rules {
+		if seen[rule.PortRange] {
+			continue
+		}
+		seen[rule.PortRange] = true
+		portRanges = append(portRanges, rule.PortRange)
+	}
+	return portRanges
+}
+
+// IngressRulesForSourceCIDRs returns the ingress rules allowing access
+// to the port range from the given CIDRs, as reported by a provider
+// firewall. Access from AnyIPv4CIDR or AnyIPv6CIDR is returned as a
+// separate rule with no source CIDRs; access from the remaining CIDRs
+// is returned as a single rule.
+func IngressRulesForSourceCIDRs(portRange PortRange, cidrs []string) ([]IngressRule, error) {
+	var rules []IngressRule
+	var sourceCIDRs []string
+	var anySource bool
+	for _, cidr := range cidrs {
+		if cidr == AnyIPv4CIDR || cidr == AnyIPv6CIDR {
+			if !anySource {
+				rules = append(rules, IngressRule{PortRange: portRange})
+				anySource = true
+			}
+			continue
+		}
+		sourceCIDRs = append(sourceCIDRs, cidr)
+	}
+	if len(sourceCIDRs) > 0 {
+		rule, err := NewIngressRule(portRange, sourceCIDRs...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// IngressRuleSourceCIDRs returns the source CIDRs of the rule, as
+// used by provider firewalls that only support IPv4: a rule with no
+// source CIDRs allows access from AnyIPv4CIDR.
+func IngressRuleSourceCIDRs(rule IngressRule) []string {
+	if len(rule.SourceCIDRs) == 0 {
+		return []string{AnyIPv4CIDR}
+	}
+	return rule.SourceCIDRs
+}
+
+// DualStackIngressRuleSourceCIDRs returns the source CIDRs of the rule,
+// as used by provider firewalls that support both IPv4 and IPv6: a rule
+// with no source CIDRs allows access from AnyIPv4CIDR and AnyIPv6CIDR.
+func DualStackIngressRuleSourceCIDRs(rule IngressRule) []string {
+	if len(rule.SourceCIDRs) == 0 {
+		return []string{AnyIPv4CIDR, AnyIPv6CIDR}
+	}
+	return rule.SourceCIDRs
+}
@@ -0,0 +1,131 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type IngressRuleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&IngressRuleSuite{})
+
+func (*IngressRuleSuite) TestNewIngressRule(c *gc.C) {
+	rule, err := network.NewIngressRule(
+		network.PortRange{80, 80, "tcp"},
+		"192.168.1.0/24", "10.1.2.3/8", "10.0.0.0/8",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule.PortRange, gc.Equals, network.PortRange{80, 80, "tcp"})
+	c.Assert(rule.SourceCIDRs, jc.DeepEquals, []string{"10.0.0.0/8", "192.168.1.0/24"})
+	c.Assert(rule.String(), gc.Equals, "80/tcp from 10.0.0.0/8,192.168.1.0/24")
+
+	rule, err = network.NewIngressRule(network.PortRange{80, 90, "udp"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule.SourceCIDRs, gc.IsNil)
+	c.Assert(rule.String(), gc.Equals, "80-90/udp")
+}
+
+func (*IngressRuleSuite) TestNewIngressRuleInvalidCIDR(c *gc.C) {
+	_, err := network.NewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `CIDR "10.0.0.0" not valid`)
+}
+
+func (*IngressRuleSuite) TestValidate(c *gc.C) {
+	rule := network.IngressRule{
+		PortRange:        network.PortRange{80, 80, "tcp"},
+		SourceCIDRs:      []string{"10.0.0.0/8"},
+		DestinationCIDRs: []string{"192.168.1.0/24"},
+	}
+	c.Assert(rule.Validate(), jc.ErrorIsNil)
+	rule.DestinationCIDRs = []string{"bar"}
+	c.Assert(rule.Validate(), gc.ErrorMatches, `destination CIDR "bar" not valid`)
+	rule.SourceCIDRs = []string{"foo"}
+	c.Assert(rule.Validate(), gc.ErrorMatches, `CIDR "foo" not valid`)
+	rule.PortRange.Protocol = "icmp"
+	c.Assert(rule.Validate(), gc.ErrorMatches, `invalid protocol "icmp", expected "tcp" or "udp"`)
+}
+
+func (*IngressRuleSuite) TestEqual(c *gc.C) {
+	a := network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8")
+	c.Assert(a.Equal(network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8")), jc.IsTrue)
+	c.Assert(a.Equal(network.MustNewIngressRule(network.PortRange{80, 80, "tcp"})), jc.IsFalse)
+	c.Assert(a.Equal(network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/16")), jc.IsFalse)
+	c.Assert(a.Equal(network.MustNewIngressRule(network.PortRange{81, 81, "tcp"}, "10.0.0.0/8")), jc.IsFalse)
+}
+
+func (*IngressRuleSuite) TestDestinationCIDRs(c *gc.C) {
+	a := network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8")
+	b := a
+	b.DestinationCIDRs = []string{"192.168.1.0/24", "192.168.2.0/24"}
+	c.Assert(a.Equal(b), jc.IsFalse)
+	c.Assert(b.String(), gc.Equals, "80/tcp from 10.0.0.0/8 to 192.168.1.0/24,192.168.2.0/24")
+
+	b.SourceCIDRs = nil
+	c.Assert(b.String(), gc.Equals, "80/tcp to 192.168.1.0/24,192.168.2.0/24")
+
+	rules := []network.IngressRule{b, a}
+	network.SortIngressRules(rules)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{b, a})
+}
+
+func (*IngressRuleSuite) TestSortIngressRules(c *gc.C) {
+	rules := []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "udp"}),
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "192.168.0.0/16"),
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"),
+		network.MustNewIngressRule(network.PortRange{22, 22, "tcp"}),
+	}
+	network.SortIngressRules(rules)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{22, 22, "tcp"}),
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"),
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "192.168.0.0/16"),
+		network.MustNewIngressRule(network.PortRange{80, 80, "udp"}),
+	})
+}
+
+func (*IngressRuleSuite) TestPortRangeConversion(c *gc.C) {
+	portRanges := []network.PortRange{{80, 80, "tcp"}, {53, 53, "udp"}}
+	rules := network.IngressRulesFromPortRanges(portRanges)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		{PortRange: network.PortRange{80, 80, "tcp"}},
+		{PortRange: network.PortRange{53, 53, "udp"}},
+	})
+	rules = append(rules, network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"))
+	c.Assert(network.IngressRulePortRanges(rules), jc.DeepEquals, portRanges)
+}
+
+func (*IngressRuleSuite) TestIngressRulesForSourceCIDRs(c *gc.C) {
+	portRange := network.PortRange{80, 80, "tcp"}
+	rules, err := network.IngressRulesForSourceCIDRs(portRange, []string{"10.0.0.0/8", "0.0.0.0/0", "192.168.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		{PortRange: portRange},
+		network.MustNewIngressRule(portRange, "10.0.0.0/8", "192.168.1.0/24"),
+	})
+
+	rules, err = network.IngressRulesForSourceCIDRs(portRange, []string{"0.0.0.0/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{{PortRange: portRange}})
+
+	rules, err = network.IngressRulesForSourceCIDRs(portRange, []string{"0.0.0.0/0", "::/0", "2001:db8::/32"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		{PortRange: portRange},
+		network.MustNewIngressRule(portRange, "2001:db8::/32"),
+	})
+
+	c.Assert(network.IngressRuleSourceCIDRs(rules[0]), jc.DeepEquals, []string{"0.0.0.0/0"})
+	c.Assert(network.DualStackIngressRuleSourceCIDRs(rules[0]), jc.DeepEquals, []string{"0.0.0.0/0", "::/0"})
+	rule := network.MustNewIngressRule(portRange, "10.0.0.0/8")
+	c.Assert(network.IngressRuleSourceCIDRs(rule), jc.DeepEquals, []string{"10.0.0.0/8"})
+	c.Assert(network.DualStackIngressRuleSourceCIDRs(rule), jc.DeepEquals, []string{"10.0.0.0/8"})
+}
@@ -163,6 +163,11 @@ type InterfaceInfo struct {
 	// "eth1", even for a VLAN eth1.42 virtual interface).
 	InterfaceName string
 
+	// ParentInterfaceName is the name of the host's network device
+	// that a container's interface is attached to, when the container
+	// is not connected to the host through a bridge.
+	ParentInterfaceName string
+
 	// Disabled is true when the interface needs to be disabled on the
 	// machine, e.g. not to configure it.
 	Disabled bool
@@ -3,6 +3,10 @@
 
 package api
 
+import (
+	"time"
+)
+
 // TODO(ericsnow) Move this file to the top-level "payload" package?
 
 // EnvListArgs are the arguments for the env-based List endpoint.
@@ -35,4 +39,26 @@ type Payload struct {
 	Unit string
 	// Machine identifies the machine tag associated with the payload.
 	Machine string
+
+	// HealthCheck describes how the payload's health is checked,
+	// if at all.
+	HealthCheck *HealthCheck
+}
+
+// HealthCheck contains the details of a payload's health check.
+type HealthCheck struct {
+	// Kind is the kind of check (exec, tcp, or http).
+	Kind string
+	// Target is the command, address, or URL that gets checked.
+	Target string
+	// Interval is how long to wait between checks.
+	Interval time.Duration
+	// Timeout is how long a single check may take.
+	Timeout time.Duration
+	// UnhealthyThreshold is the number of consecutive failures after
+	// which the payload is unhealthy.
+	UnhealthyThreshold int
+	// HealthyThreshold is the number of consecutive successes after
+	// which an unhealthy payload is healthy again.
+	HealthyThreshold int
 }
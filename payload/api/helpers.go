@@ -26,7 +26,7 @@ func Payload2api(p payload.FullPayloadInfo) Payload {
 		machineTag = names.NewMachineTag(p.Machine).String()
 	}
 
-	return Payload{
+	result := Payload{
 		Class:   p.Name,
 		Type:    p.Type,
 		ID:      p.ID,
@@ -35,6 +35,17 @@ func Payload2api(p payload.FullPayloadInfo) Payload {
 		Unit:    unitTag,
 		Machine: machineTag,
 	}
+	if hc := p.HealthCheck; hc != nil {
+		result.HealthCheck = &HealthCheck{
+			Kind:               hc.Kind,
+			Target:             hc.Target,
+			Interval:           hc.Interval,
+			Timeout:            hc.Timeout,
+			UnhealthyThreshold: hc.UnhealthyThreshold,
+			HealthyThreshold:   hc.HealthyThreshold,
+		}
+	}
+	return result
 }
 
 // API2Payload converts an API Payload info struct into
@@ -60,7 +71,7 @@ func API2Payload(apiInfo Payload) (payload.FullPayloadInfo, error) {
 		machine = tag.Id()
 	}
 
-	return payload.FullPayloadInfo{
+	result := payload.FullPayloadInfo{
 		Payload: payload.Payload{
 			PayloadClass: charm.PayloadClass{
 				Name: apiInfo.Class,
@@ -72,5 +83,16 @@ func API2Payload(apiInfo Payload) (payload.FullPayloadInfo, error) {
 			Unit:   unit,
 		},
 		Machine: machine,
-	}, nil
+	}
+	if hc := apiInfo.HealthCheck; hc != nil {
+		result.HealthCheck = &payload.HealthCheck{
+			Kind:               hc.Kind,
+			Target:             hc.Target,
+			Interval:           hc.Interval,
+			Timeout:            hc.Timeout,
+			UnhealthyThreshold: hc.UnhealthyThreshold,
+			HealthyThreshold:   hc.HealthyThreshold,
+		}
+	}
+	return result, nil
 }
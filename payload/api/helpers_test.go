@@ -4,6 +4,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/juju/names"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -71,3 +73,36 @@ func (helpersSuite) TestAPI2Payload(c *gc.C) {
 		Machine: "1",
 	})
 }
+
+func (helpersSuite) TestHealthCheckRoundTrip(c *gc.C) {
+	hc := payload.NewHealthCheck(payload.HealthCheckHTTP, "http://localhost:8080/")
+	hc.Interval = time.Minute
+	info := payload.FullPayloadInfo{
+		Payload: payload.Payload{
+			PayloadClass: charm.PayloadClass{
+				Name: "spam",
+				Type: "docker",
+			},
+			ID:          "idspam",
+			Status:      payload.StateRunning,
+			Labels:      []string{},
+			Unit:        "a-service/0",
+			HealthCheck: &hc,
+		},
+		Machine: "1",
+	}
+
+	apiPayload := Payload2api(info)
+	c.Check(apiPayload.HealthCheck, jc.DeepEquals, &HealthCheck{
+		Kind:               "http",
+		Target:             "http://localhost:8080/",
+		Interval:           time.Minute,
+		Timeout:            10 * time.Second,
+		UnhealthyThreshold: 3,
+		HealthyThreshold:   1,
+	})
+	pl, err := API2Payload(apiPayload)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(pl, jc.DeepEquals, info)
+}
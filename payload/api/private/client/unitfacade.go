@@ -12,6 +12,7 @@ import (
 
 type facadeCaller interface {
 	FacadeCall(request string, params, response interface{}) error
+	BestAPIVersion() int
 }
 
 // UnitFacadeClient provides methods for interacting with Juju's internal
@@ -27,6 +28,11 @@ func NewUnitFacadeClient(caller facadeCaller) UnitFacadeClient {
 
 // Track calls the Track API server method.
 func (c UnitFacadeClient) Track(payloads ...payload.Payload) ([]payload.Result, error) {
+	for _, pl := range payloads {
+		if pl.HealthCheck != nil && c.BestAPIVersion() < 1 {
+			return nil, errors.NotSupportedf("payload health checks on this juju controller")
+		}
+	}
 	args := internal.Payloads2TrackArgs(payloads)
 
 	var rs internal.PayloadResults
@@ -75,6 +81,9 @@ func (c UnitFacadeClient) LookUp(fullIDs ...string) ([]payload.Result, error) {
 
 // SetStatus calls the SetStatus API server method.
 func (c UnitFacadeClient) SetStatus(status string, fullIDs ...string) ([]payload.Result, error) {
+	if status == payload.StateUnhealthy && c.BestAPIVersion() < 1 {
+		return nil, errors.NotSupportedf("unhealthy payload status on this juju controller")
+	}
 	ids, err := c.lookUp(fullIDs)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -321,6 +321,27 @@ func (s *clientSuite) TestSetStatus(c *gc.C) {
 	}})
 }
 
+func (s *clientSuite) TestTrackHealthCheckNotSupported(c *gc.C) {
+	pl, err := api.API2Payload(s.payload)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := payload.NewHealthCheck(payload.HealthCheckExec, "pgrep foobar")
+	pl.HealthCheck = &hc
+
+	pclient := client.NewUnitFacadeClient(s.facade)
+	_, err = pclient.Track(pl.Payload)
+
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	s.stub.CheckCallNames(c, "BestAPIVersion")
+}
+
+func (s *clientSuite) TestSetStatusUnhealthyNotSupported(c *gc.C) {
+	pclient := client.NewUnitFacadeClient(s.facade)
+	_, err := pclient.SetStatus(payload.StateUnhealthy, "idfoo/bar")
+
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	s.stub.CheckCallNames(c, "BestAPIVersion")
+}
+
 func (s *clientSuite) TestUntrack(c *gc.C) {
 	id := "ce5bc2a7-65d8-4800-8199-a7c3356ab309"
 	responses := []interface{}{
@@ -391,6 +412,7 @@ type stubFacade struct {
 	stub      *testing.Stub
 	responses []interface{}
 	methods   apiMethods
+	version   int
 
 	// TODO(ericsnow) Eliminate this.
 	FacadeCallFn func(name string, params, response interface{}) error
@@ -405,6 +427,13 @@ func (s *stubFacade) nextResponse() interface{} {
 	return resp
 }
 
+func (s *stubFacade) BestAPIVersion() int {
+	s.stub.AddCall("BestAPIVersion")
+	s.stub.NextErr() // Pop one off.
+
+	return s.version
+}
+
 func (s *stubFacade) FacadeCall(request string, params, response interface{}) error {
 	s.stub.AddCall("FacadeCall", request, params, response)
 	resp := s.nextResponse()
@@ -14,6 +14,10 @@ import (
 	internal "github.com/juju/juju/payload/api/private"
 )
 
+// FacadeVersion is the version of the current hook context facade.
+// Version 1 adds payload health checks and the unhealthy status.
+const FacadeVersion = 1
+
 // UnitPayloads exposes the State functionality for a unit's payloads.
 type UnitPayloads interface {
 	// Track tracks a payload for the unit and info.
@@ -10,6 +10,10 @@ import (
 	"github.com/juju/juju/payload/api"
 )
 
+// Version is the version of the current public facade.
+// Version 2 adds payload health checks and the unhealthy status.
+const Version = 2
+
 // EnvPayloads exposes the State functionality for payloads in an env.
 type EnvPayloads interface {
 	// ListAll returns information on the payload with the id on the unit.
@@ -14,6 +14,9 @@ import (
 
 var logger = loggo.GetLogger("juju.payload.context")
 
+// HookContextFacade is the name of the API facade for payloads in the uniter.
+const HookContextFacade = payload.ComponentName + "-hook-context"
+
 // APIClient represents the API needs of a Context.
 type APIClient interface {
 	// List requests the payload info for the given IDs.
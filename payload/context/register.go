@@ -4,9 +4,12 @@
 package context
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v6-unstable"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/payload"
 )
@@ -33,6 +36,15 @@ type RegisterCmd struct {
 	class  string
 	id     string
 	labels []string
+
+	healthExec         string
+	healthTCP          string
+	healthHTTP         string
+	healthInterval     time.Duration
+	healthTimeout      time.Duration
+	unhealthyThreshold int
+	healthyThreshold   int
+	healthCheck        *payload.HealthCheck
 }
 
 // TODO(ericsnow) Change "tags" to "labels" in the help text?
@@ -51,10 +63,28 @@ provided when "register" is run.
 The payload class must correspond to one of the payloads defined in
 the charm's metadata.yaml.
 
+A health check may be declared for the payload using one of --health-exec,
+--health-tcp, or --health-http. The unit agent then runs the check
+periodically. When it fails --unhealthy-threshold times in a row the
+payload's status is set to "unhealthy" and the "payload-unhealthy" hook is
+run (with JUJU_PAYLOAD_ID set). Once the check succeeds
+--healthy-threshold times in a row the status goes back to "running".
+
 		`,
 	}
 }
 
+// SetFlags implements cmd.Command.
+func (c *RegisterCmd) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.healthExec, "health-exec", "", "a command that exits zero while the payload is healthy")
+	f.StringVar(&c.healthTCP, "health-tcp", "", "a host:port address that accepts connections while the payload is healthy")
+	f.StringVar(&c.healthHTTP, "health-http", "", "a URL that responds successfully to GET while the payload is healthy")
+	f.DurationVar(&c.healthInterval, "health-interval", payload.DefaultHealthCheckInterval, "how often to run the health check")
+	f.DurationVar(&c.healthTimeout, "health-timeout", payload.DefaultHealthCheckTimeout, "how long a single health check may take")
+	f.IntVar(&c.unhealthyThreshold, "unhealthy-threshold", payload.DefaultUnhealthyThreshold, "consecutive failed checks before the payload is unhealthy")
+	f.IntVar(&c.healthyThreshold, "healthy-threshold", payload.DefaultHealthyThreshold, "consecutive successful checks before the payload is healthy again")
+}
+
 // Init implements cmd.Command.
 func (c *RegisterCmd) Init(args []string) error {
 	if len(args) < 3 {
@@ -64,9 +94,48 @@ func (c *RegisterCmd) Init(args []string) error {
 	c.class = args[1]
 	c.id = args[2]
 	c.labels = args[3:]
+
+	healthCheck, err := c.newHealthCheck()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.healthCheck = healthCheck
 	return nil
 }
 
+func (c *RegisterCmd) newHealthCheck() (*payload.HealthCheck, error) {
+	var kind, target string
+	for _, check := range []struct{ kind, target string }{
+		{payload.HealthCheckExec, c.healthExec},
+		{payload.HealthCheckTCP, c.healthTCP},
+		{payload.HealthCheckHTTP, c.healthHTTP},
+	} {
+		if check.target == "" {
+			continue
+		}
+		if kind != "" {
+			return nil, errors.New("only one of --health-exec, --health-tcp, and --health-http may be used")
+		}
+		kind, target = check.kind, check.target
+	}
+	if kind == "" {
+		return nil, nil
+	}
+
+	healthCheck := &payload.HealthCheck{
+		Kind:               kind,
+		Target:             target,
+		Interval:           c.healthInterval,
+		Timeout:            c.healthTimeout,
+		UnhealthyThreshold: c.unhealthyThreshold,
+		HealthyThreshold:   c.healthyThreshold,
+	}
+	if err := healthCheck.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return healthCheck, nil
+}
+
 // Run implements cmd.Command.
 func (c *RegisterCmd) Run(ctx *cmd.Context) error {
 	if err := c.validate(ctx); err != nil {
@@ -77,10 +146,11 @@ func (c *RegisterCmd) Run(ctx *cmd.Context) error {
 			Name: c.class,
 			Type: c.typ,
 		},
-		ID:     c.id,
-		Status: payload.StateRunning,
-		Labels: c.labels,
-		Unit:   "a-service/0",
+		ID:          c.id,
+		Status:      payload.StateRunning,
+		Labels:      c.labels,
+		Unit:        "a-service/0",
+		HealthCheck: c.healthCheck,
 	}
 	if err := c.hctx.Track(pl); err != nil {
 		return errors.Trace(err)
@@ -6,6 +6,7 @@ package context
 import (
 	"io/ioutil"
 	"path/filepath"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -15,6 +16,7 @@ import (
 	"gopkg.in/juju/charm.v6-unstable"
 
 	"github.com/juju/juju/payload"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type registerSuite struct {
@@ -55,6 +57,71 @@ func (registerSuite) TestInitWithLabels(c *gc.C) {
 	c.Assert(r.labels, gc.DeepEquals, []string{"tag1", "tag 2"})
 }
 
+func (registerSuite) TestInitHealthCheck(c *gc.C) {
+	r := &RegisterCmd{}
+	err := coretesting.InitCommand(r, []string{
+		"--health-tcp", "localhost:8080",
+		"--health-interval", "1m",
+		"--unhealthy-threshold", "5",
+		"type", "class", "id",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(r.healthCheck, jc.DeepEquals, &payload.HealthCheck{
+		Kind:               payload.HealthCheckTCP,
+		Target:             "localhost:8080",
+		Interval:           time.Minute,
+		Timeout:            payload.DefaultHealthCheckTimeout,
+		UnhealthyThreshold: 5,
+		HealthyThreshold:   payload.DefaultHealthyThreshold,
+	})
+}
+
+func (registerSuite) TestInitNoHealthCheck(c *gc.C) {
+	r := &RegisterCmd{}
+	err := coretesting.InitCommand(r, []string{"type", "class", "id"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(r.healthCheck, gc.IsNil)
+}
+
+func (registerSuite) TestInitTooManyHealthChecks(c *gc.C) {
+	r := &RegisterCmd{}
+	err := coretesting.InitCommand(r, []string{
+		"--health-tcp", "localhost:8080",
+		"--health-exec", "pgrep spam",
+		"type", "class", "id",
+	})
+
+	c.Check(err, gc.ErrorMatches, `only one of --health-exec, --health-tcp, and --health-http may be used`)
+}
+
+func (registerSuite) TestInitBadHealthCheck(c *gc.C) {
+	r := &RegisterCmd{}
+	err := coretesting.InitCommand(r, []string{
+		"--health-http", "ftp://localhost",
+		"type", "class", "id",
+	})
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (registerSuite) TestRunWithHealthCheck(c *gc.C) {
+	f := &stubRegisterContext{}
+	r := &RegisterCmd{hctx: f}
+	err := coretesting.InitCommand(r, []string{
+		"--health-exec", "pgrep spam",
+		"type", "class", "id",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := setupMetadata(c)
+	err = r.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := payload.NewHealthCheck(payload.HealthCheckExec, "pgrep spam")
+	c.Check(f.payload.HealthCheck, jc.DeepEquals, &hc)
+}
+
 func (registerSuite) TestRun(c *gc.C) {
 	f := &stubRegisterContext{}
 	r := RegisterCmd{hctx: f}
@@ -0,0 +1,74 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package health
+
+import (
+	"net"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/payload"
+)
+
+// RunCheck runs the health check once. An error is returned if the
+// payload is not healthy.
+func RunCheck(hc payload.HealthCheck) error {
+	switch hc.Kind {
+	case payload.HealthCheckExec:
+		return runExecCheck(hc.Target, hc.Timeout)
+	case payload.HealthCheckTCP:
+		return runTCPCheck(hc.Target, hc.Timeout)
+	case payload.HealthCheckHTTP:
+		return runHTTPCheck(hc.Target, hc.Timeout)
+	}
+	return errors.NotSupportedf("health check kind %q", hc.Kind)
+}
+
+func runExecCheck(command string, timeout time.Duration) error {
+	cmd := exec.Command("/bin/sh", "-c", command)
+	if err := cmd.Start(); err != nil {
+		return errors.Annotate(err, "could not start health check command")
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return errors.Annotate(err, "health check command failed")
+		}
+		return nil
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		<-done
+		return errors.Errorf("health check command timed out after %v", timeout)
+	}
+}
+
+func runTCPCheck(address string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return errors.Annotate(err, "health check connection failed")
+	}
+	conn.Close()
+	return nil
+}
+
+func runHTTPCheck(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return errors.Annotate(err, "health check request failed")
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("health check request failed: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package health_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/payload"
+	"github.com/juju/juju/payload/health"
+)
+
+var _ = gc.Suite(&checkSuite{})
+
+type checkSuite struct {
+	testing.IsolationSuite
+}
+
+func (s *checkSuite) TestExecOkay(c *gc.C) {
+	err := health.RunCheck(payload.NewHealthCheck(payload.HealthCheckExec, "true"))
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *checkSuite) TestExecFailed(c *gc.C) {
+	err := health.RunCheck(payload.NewHealthCheck(payload.HealthCheckExec, "false"))
+
+	c.Check(err, gc.ErrorMatches, `health check command failed: .*`)
+}
+
+func (s *checkSuite) TestExecTimedOut(c *gc.C) {
+	hc := payload.NewHealthCheck(payload.HealthCheckExec, "sleep 10")
+	hc.Timeout = 10 * time.Millisecond
+
+	err := health.RunCheck(hc)
+
+	c.Check(err, gc.ErrorMatches, `health check command timed out after 10ms`)
+}
+
+func (s *checkSuite) TestTCPOkay(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+
+	err = health.RunCheck(payload.NewHealthCheck(payload.HealthCheckTCP, listener.Addr().String()))
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *checkSuite) TestTCPFailed(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	address := listener.Addr().String()
+	listener.Close()
+
+	err = health.RunCheck(payload.NewHealthCheck(payload.HealthCheckTCP, address))
+
+	c.Check(err, gc.ErrorMatches, `health check connection failed: .*`)
+}
+
+func (s *checkSuite) TestHTTPOkay(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := health.RunCheck(payload.NewHealthCheck(payload.HealthCheckHTTP, server.URL))
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *checkSuite) TestHTTPFailed(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := health.RunCheck(payload.NewHealthCheck(payload.HealthCheckHTTP, server.URL))
+
+	c.Check(err, gc.ErrorMatches, `health check request failed: 500 Internal Server Error`)
+}
+
+func (s *checkSuite) TestUnknownKind(c *gc.C) {
+	err := health.RunCheck(payload.HealthCheck{Kind: "ping"})
+
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,305 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package health runs the health checks declared for a unit's
+// payloads. It is used by the unit agent.
+package health
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/payload"
+	internalclient "github.com/juju/juju/payload/api/private/client"
+	"github.com/juju/juju/payload/context"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.payload.health")
+
+// DefaultRefreshInterval is how often the monitor refreshes the list
+// of the unit's payloads (and their health checks) by default.
+const DefaultRefreshInterval = time.Minute
+
+// Client exposes the payload API functionality needed by the monitor.
+type Client interface {
+	// List returns the payloads of the unit.
+	List(fullIDs ...string) ([]payload.Result, error)
+
+	// SetStatus sets the status of the identified payloads.
+	SetStatus(status string, fullIDs ...string) ([]payload.Result, error)
+}
+
+// NewAPIClient returns a Client that uses the best version of the
+// payloads hook context facade supported by the controller. Payloads
+// only have health checks with controllers that support version 1.
+func NewAPIClient(caller base.APICaller) Client {
+	facadeCaller := base.NewFacadeCaller(caller, context.HookContextFacade)
+	return internalclient.NewUnitFacadeClient(facadeCaller)
+}
+
+// MonitorConfig holds the configuration for a Monitor.
+type MonitorConfig struct {
+	// Client is used to list the unit's payloads and to
+	// update their status.
+	Client Client
+
+	// Clock is used to schedule the checks.
+	Clock clock.Clock
+
+	// RefreshInterval is how often the list of payloads is refreshed.
+	RefreshInterval time.Duration
+
+	// Check runs a single health check (e.g. RunCheck). Checks of
+	// different payloads are run concurrently.
+	Check func(payload.HealthCheck) error
+
+	// Unhealthy receives the full ID of each payload that
+	// becomes unhealthy.
+	Unhealthy chan<- string
+}
+
+// Validate returns an error if the config is not valid.
+func (config MonitorConfig) Validate() error {
+	if config.Client == nil {
+		return errors.NotValidf("nil Client")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.RefreshInterval <= 0 {
+		return errors.NotValidf("non-positive RefreshInterval")
+	}
+	if config.Check == nil {
+		return errors.NotValidf("nil Check")
+	}
+	if config.Unhealthy == nil {
+		return errors.NotValidf("nil Unhealthy")
+	}
+	return nil
+}
+
+// Monitor is a worker that periodically runs the health checks of the
+// unit's running payloads. Each payload's check is run on its own
+// schedule, so a slow check does not delay the others. When a
+// payload's check has failed enough times in a row, the payload's
+// status is set to "unhealthy" and its full ID is sent on the
+// configured channel. Once the check has succeeded enough times in a
+// row the status is set back to "running".
+type Monitor struct {
+	catacomb catacomb.Catacomb
+	config   MonitorConfig
+	checks   map[string]*checkState
+	results  chan checkResult
+}
+
+// checkState tracks the health of a single payload.
+type checkState struct {
+	check     payload.HealthCheck
+	status    string
+	due       time.Time
+	running   bool
+	failures  int
+	successes int
+}
+
+// checkResult holds the outcome of running a payload's check.
+type checkResult struct {
+	fullID string
+	check  payload.HealthCheck
+	err    error
+}
+
+// NewMonitor returns a new Monitor, which has been started.
+func NewMonitor(config MonitorConfig) (*Monitor, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	m := &Monitor{
+		config:  config,
+		checks:  make(map[string]*checkState),
+		results: make(chan checkResult),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &m.catacomb,
+		Work: m.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return m, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (m *Monitor) Kill() {
+	m.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (m *Monitor) Wait() error {
+	return m.catacomb.Wait()
+}
+
+func (m *Monitor) loop() error {
+	refresh := m.config.Clock.After(0)
+	var next <-chan time.Time
+	for {
+		select {
+		case <-m.catacomb.Dying():
+			return m.catacomb.ErrDying()
+		case <-refresh:
+			// Failing to list the payloads is not fatal; we keep
+			// checking the ones we already know about.
+			if err := m.refresh(); err != nil {
+				logger.Errorf("could not refresh payloads: %v", err)
+			}
+			refresh = m.config.Clock.After(m.config.RefreshInterval)
+		case <-next:
+		case result := <-m.results:
+			if err := m.handleResult(result); err != nil {
+				return errors.Trace(err)
+			}
+		}
+
+		m.startDueChecks()
+		next = m.nextCheck()
+	}
+}
+
+// refresh updates the checks from the unit's payloads. Only running
+// (or unhealthy) payloads that declare a health check are checked.
+func (m *Monitor) refresh() error {
+	results, err := m.config.Client.List()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	now := m.config.Clock.Now()
+	checks := make(map[string]*checkState)
+	for _, result := range results {
+		if result.Error != nil || result.Payload == nil {
+			continue
+		}
+		pl := result.Payload.Payload
+		if pl.HealthCheck == nil {
+			continue
+		}
+		if pl.Status != payload.StateRunning && pl.Status != payload.StateUnhealthy {
+			continue
+		}
+
+		fullID := pl.FullID()
+		state, ok := m.checks[fullID]
+		if !ok || state.check != *pl.HealthCheck {
+			state = &checkState{
+				check: *pl.HealthCheck,
+				due:   now,
+			}
+		}
+		state.status = pl.Status
+		checks[fullID] = state
+	}
+	m.checks = checks
+	return nil
+}
+
+// startDueChecks starts each check that is due and not already
+// running. Each check runs in its own goroutine and reports its result
+// to the loop.
+func (m *Monitor) startDueChecks() {
+	now := m.config.Clock.Now()
+	for fullID, state := range m.checks {
+		if state.running || state.due.After(now) {
+			continue
+		}
+		state.running = true
+		go m.runCheck(fullID, state.check)
+	}
+}
+
+// runCheck runs the check and sends its result to the loop, unless
+// the monitor is stopped first.
+func (m *Monitor) runCheck(fullID string, check payload.HealthCheck) {
+	result := checkResult{
+		fullID: fullID,
+		check:  check,
+		err:    m.config.Check(check),
+	}
+	select {
+	case <-m.catacomb.Dying():
+	case m.results <- result:
+	}
+}
+
+// handleResult records the result of a payload's check and handles
+// any resulting change in the payload's health. Results for payloads
+// that are no longer checked, or whose check has changed, are ignored.
+func (m *Monitor) handleResult(result checkResult) error {
+	state, ok := m.checks[result.fullID]
+	if !ok || state.check != result.check {
+		return nil
+	}
+	state.running = false
+	state.due = m.config.Clock.Now().Add(state.check.Interval)
+	if result.err != nil {
+		logger.Debugf("health check for payload %q failed: %v", result.fullID, result.err)
+		state.failures++
+		state.successes = 0
+	} else {
+		state.successes++
+		state.failures = 0
+	}
+
+	switch {
+	case state.status != payload.StateUnhealthy && state.failures >= state.check.UnhealthyThreshold:
+		if !m.setStatus(result.fullID, state, payload.StateUnhealthy) {
+			return nil
+		}
+		select {
+		case <-m.catacomb.Dying():
+			return m.catacomb.ErrDying()
+		case m.config.Unhealthy <- result.fullID:
+		}
+	case state.status == payload.StateUnhealthy && state.successes >= state.check.HealthyThreshold:
+		m.setStatus(result.fullID, state, payload.StateRunning)
+	}
+	return nil
+}
+
+// setStatus records the payload's new status in state. Failures are
+// logged (the next check will try again) and reported as false.
+func (m *Monitor) setStatus(fullID string, state *checkState, status string) bool {
+	logger.Infof("payload %q is now %s", fullID, status)
+	results, err := m.config.Client.SetStatus(status, fullID)
+	if err == nil && len(results) > 0 && results[0].Error != nil {
+		err = results[0].Error
+	}
+	if err != nil {
+		logger.Errorf("could not set status of payload %q to %q: %v", fullID, status, err)
+		return false
+	}
+	state.status = status
+	return true
+}
+
+// nextCheck returns a channel that fires when the next check that is
+// not already running is due, or nil if there is none.
+func (m *Monitor) nextCheck() <-chan time.Time {
+	var next time.Time
+	for _, state := range m.checks {
+		if state.running {
+			continue
+		}
+		if next.IsZero() || state.due.Before(next) {
+			next = state.due
+		}
+	}
+	if next.IsZero() {
+		return nil
+	}
+	return m.config.Clock.After(next.Sub(m.config.Clock.Now()))
+}
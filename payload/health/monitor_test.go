@@ -0,0 +1,254 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package health_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/payload"
+	"github.com/juju/juju/payload/health"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/workertest"
+)
+
+var _ = gc.Suite(&monitorSuite{})
+
+type monitorSuite struct {
+	testing.IsolationSuite
+
+	stub      *testing.Stub
+	client    *stubClient
+	clock     *coretesting.Clock
+	unhealthy chan string
+}
+
+func (s *monitorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.stub = &testing.Stub{}
+	s.client = &stubClient{stub: s.stub}
+	s.clock = coretesting.NewClock(time.Now())
+	s.unhealthy = make(chan string)
+}
+
+func (s *monitorSuite) newPayload(status string, hc *payload.HealthCheck) payload.Result {
+	pl := payload.Payload{
+		PayloadClass: charm.PayloadClass{
+			Name: "spam",
+			Type: "docker",
+		},
+		ID:          "id0",
+		Status:      status,
+		Unit:        "a-service/0",
+		HealthCheck: hc,
+	}
+	return payload.Result{
+		ID:      pl.FullID(),
+		Payload: &payload.FullPayloadInfo{Payload: pl},
+	}
+}
+
+func (s *monitorSuite) newHealthCheck() *payload.HealthCheck {
+	hc := payload.NewHealthCheck(payload.HealthCheckExec, "pgrep spam")
+	hc.UnhealthyThreshold = 2
+	return &hc
+}
+
+func (s *monitorSuite) config() health.MonitorConfig {
+	return health.MonitorConfig{
+		Client:          s.client,
+		Clock:           s.clock,
+		RefreshInterval: time.Hour,
+		Check: func(hc payload.HealthCheck) error {
+			s.stub.AddCall("Check", hc)
+			return s.stub.NextErr()
+		},
+		Unhealthy: s.unhealthy,
+	}
+}
+
+func (s *monitorSuite) newMonitor(c *gc.C) *health.Monitor {
+	monitor, err := health.NewMonitor(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		workertest.CleanKill(c, monitor)
+	})
+	return monitor
+}
+
+func (s *monitorSuite) waitAlarms(c *gc.C, count int) {
+	for i := 0; i < count; i++ {
+		select {
+		case <-s.clock.Alarms():
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for alarm %d", i)
+		}
+	}
+}
+
+func (s *monitorSuite) TestValidateConfig(c *gc.C) {
+	for i, test := range []struct {
+		update func(*health.MonitorConfig)
+		err    string
+	}{{
+		update: func(config *health.MonitorConfig) { config.Client = nil },
+		err:    "nil Client not valid",
+	}, {
+		update: func(config *health.MonitorConfig) { config.Clock = nil },
+		err:    "nil Clock not valid",
+	}, {
+		update: func(config *health.MonitorConfig) { config.RefreshInterval = 0 },
+		err:    "non-positive RefreshInterval not valid",
+	}, {
+		update: func(config *health.MonitorConfig) { config.Check = nil },
+		err:    "nil Check not valid",
+	}, {
+		update: func(config *health.MonitorConfig) { config.Unhealthy = nil },
+		err:    "nil Unhealthy not valid",
+	}} {
+		c.Logf("test %d", i)
+		config := s.config()
+		test.update(&config)
+
+		monitor, err := health.NewMonitor(config)
+
+		c.Check(monitor, gc.IsNil)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *monitorSuite) TestUnhealthy(c *gc.C) {
+	hc := s.newHealthCheck()
+	s.client.payloads = []payload.Result{s.newPayload(payload.StateRunning, hc)}
+	failure := errors.New("<failure>")
+	s.stub.SetErrors(nil, failure, failure)
+	s.newMonitor(c)
+	// The initial refresh, the next refresh, and the next check.
+	s.waitAlarms(c, 3)
+
+	s.clock.Advance(hc.Interval)
+
+	select {
+	case fullID := <-s.unhealthy:
+		c.Check(fullID, gc.Equals, "spam/id0")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for unhealthy payload")
+	}
+	s.stub.CheckCallNames(c, "List", "Check", "Check", "SetStatus")
+	s.stub.CheckCall(c, 1, "Check", *hc)
+	s.stub.CheckCall(c, 3, "SetStatus", "unhealthy", []string{"spam/id0"})
+}
+
+func (s *monitorSuite) TestBelowThreshold(c *gc.C) {
+	hc := s.newHealthCheck()
+	s.client.payloads = []payload.Result{s.newPayload(payload.StateRunning, hc)}
+	s.stub.SetErrors(nil, errors.New("<failure>"))
+	s.newMonitor(c)
+
+	s.waitAlarms(c, 3)
+	s.stub.CheckCallNames(c, "List", "Check")
+}
+
+func (s *monitorSuite) TestRecovered(c *gc.C) {
+	hc := s.newHealthCheck()
+	s.client.payloads = []payload.Result{s.newPayload(payload.StateUnhealthy, hc)}
+	s.newMonitor(c)
+
+	s.waitAlarms(c, 3)
+	s.stub.CheckCallNames(c, "List", "Check", "SetStatus")
+	s.stub.CheckCall(c, 2, "SetStatus", "running", []string{"spam/id0"})
+}
+
+func (s *monitorSuite) TestChecksRunConcurrently(c *gc.C) {
+	slow := s.newHealthCheck()
+	slow.Target = "slow"
+	fast := s.newHealthCheck()
+	fast.Target = "fast"
+	other := s.newPayload(payload.StateRunning, fast)
+	other.Payload.ID = "id1"
+	other.ID = other.Payload.FullID()
+	s.client.payloads = []payload.Result{s.newPayload(payload.StateRunning, slow), other}
+
+	// The slow check only completes once the fast one has run, which
+	// would never happen if the checks were run one at a time.
+	fastDone := make(chan struct{})
+	slowDone := make(chan struct{})
+	config := s.config()
+	config.Check = func(hc payload.HealthCheck) error {
+		switch hc.Target {
+		case "fast":
+			close(fastDone)
+		case "slow":
+			select {
+			case <-fastDone:
+			case <-time.After(coretesting.LongWait):
+				c.Errorf("timed out waiting for fast check")
+			}
+			close(slowDone)
+		}
+		return nil
+	}
+	monitor, err := health.NewMonitor(config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, monitor)
+
+	select {
+	case <-slowDone:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for slow check")
+	}
+}
+
+func (s *monitorSuite) TestNotChecked(c *gc.C) {
+	s.client.payloads = []payload.Result{
+		s.newPayload(payload.StateRunning, nil),
+		s.newPayload(payload.StateStopped, s.newHealthCheck()),
+	}
+	s.newMonitor(c)
+
+	// The initial refresh and the next refresh.
+	s.waitAlarms(c, 2)
+	s.stub.CheckCallNames(c, "List")
+}
+
+func (s *monitorSuite) TestListFailed(c *gc.C) {
+	s.stub.SetErrors(errors.New("<failure>"))
+	monitor := s.newMonitor(c)
+
+	s.waitAlarms(c, 2)
+	workertest.CheckAlive(c, monitor)
+	s.stub.CheckCallNames(c, "List")
+}
+
+type stubClient struct {
+	stub     *testing.Stub
+	payloads []payload.Result
+}
+
+func (s *stubClient) List(fullIDs ...string) ([]payload.Result, error) {
+	s.stub.AddCall("List", fullIDs)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.payloads, nil
+}
+
+func (s *stubClient) SetStatus(status string, fullIDs ...string) ([]payload.Result, error) {
+	s.stub.AddCall("SetStatus", status, fullIDs)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results []payload.Result
+	for _, fullID := range fullIDs {
+		results = append(results, payload.Result{ID: fullID})
+	}
+	return results, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package payload
+
+import (
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// The kinds of health check that the unit agent knows how to run.
+const (
+	// HealthCheckExec checks that a command exits with a zero status.
+	HealthCheckExec = "exec"
+
+	// HealthCheckTCP checks that a TCP connection can be made.
+	HealthCheckTCP = "tcp"
+
+	// HealthCheckHTTP checks that an HTTP GET request succeeds.
+	HealthCheckHTTP = "http"
+)
+
+// The default health check settings.
+const (
+	DefaultHealthCheckInterval = 30 * time.Second
+	DefaultHealthCheckTimeout  = 10 * time.Second
+	DefaultUnhealthyThreshold  = 3
+	DefaultHealthyThreshold    = 1
+)
+
+// HealthCheck describes how the unit agent checks that a payload
+// is healthy.
+type HealthCheck struct {
+	// Kind is the kind of check (exec, tcp, or http).
+	Kind string
+
+	// Target is what gets checked: the command for exec checks, the
+	// host:port address for tcp checks, and the URL for http checks.
+	Target string
+
+	// Interval is how long to wait between checks.
+	Interval time.Duration
+
+	// Timeout is how long a single check may take before it
+	// is considered to have failed.
+	Timeout time.Duration
+
+	// UnhealthyThreshold is the number of consecutive failed checks
+	// after which the payload is considered unhealthy.
+	UnhealthyThreshold int
+
+	// HealthyThreshold is the number of consecutive successful
+	// checks after which an unhealthy payload is considered
+	// healthy again.
+	HealthyThreshold int
+}
+
+// NewHealthCheck returns a health check of the given kind for the
+// target, using the default settings.
+func NewHealthCheck(kind, target string) HealthCheck {
+	return HealthCheck{
+		Kind:               kind,
+		Target:             target,
+		Interval:           DefaultHealthCheckInterval,
+		Timeout:            DefaultHealthCheckTimeout,
+		UnhealthyThreshold: DefaultUnhealthyThreshold,
+		HealthyThreshold:   DefaultHealthyThreshold,
+	}
+}
+
+// Validate checks the health check to ensure it is correct.
+func (hc HealthCheck) Validate() error {
+	if hc.Target == "" {
+		return errors.NotValidf("health check missing target")
+	}
+
+	switch hc.Kind {
+	case HealthCheckExec:
+	case HealthCheckTCP:
+		if _, _, err := net.SplitHostPort(hc.Target); err != nil {
+			return errors.NewNotValid(err, "bad tcp health check address")
+		}
+	case HealthCheckHTTP:
+		u, err := url.Parse(hc.Target)
+		if err != nil {
+			return errors.NewNotValid(err, "bad http health check URL")
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.NotValidf("http health check URL %q", hc.Target)
+		}
+	default:
+		return errors.NotValidf("health check kind %q", hc.Kind)
+	}
+
+	if hc.Interval <= 0 {
+		return errors.NotValidf("health check interval %v", hc.Interval)
+	}
+	if hc.Timeout <= 0 || hc.Timeout > hc.Interval {
+		return errors.NotValidf("health check timeout %v (must be positive and no longer than the interval)", hc.Timeout)
+	}
+	if hc.UnhealthyThreshold < 1 {
+		return errors.NotValidf("unhealthy threshold %d", hc.UnhealthyThreshold)
+	}
+	if hc.HealthyThreshold < 1 {
+		return errors.NotValidf("healthy threshold %d", hc.HealthyThreshold)
+	}
+	return nil
+}
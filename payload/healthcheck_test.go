@@ -0,0 +1,88 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package payload_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/payload"
+)
+
+var _ = gc.Suite(&healthCheckSuite{})
+
+type healthCheckSuite struct {
+	testing.IsolationSuite
+}
+
+func (s *healthCheckSuite) TestNewHealthCheck(c *gc.C) {
+	hc := payload.NewHealthCheck(payload.HealthCheckTCP, "localhost:8080")
+
+	c.Check(hc, jc.DeepEquals, payload.HealthCheck{
+		Kind:               "tcp",
+		Target:             "localhost:8080",
+		Interval:           30 * time.Second,
+		Timeout:            10 * time.Second,
+		UnhealthyThreshold: 3,
+		HealthyThreshold:   1,
+	})
+}
+
+func (s *healthCheckSuite) TestValidateOkay(c *gc.C) {
+	for _, hc := range []payload.HealthCheck{
+		payload.NewHealthCheck(payload.HealthCheckExec, "pgrep spam"),
+		payload.NewHealthCheck(payload.HealthCheckTCP, "localhost:8080"),
+		payload.NewHealthCheck(payload.HealthCheckHTTP, "http://localhost:8080/health"),
+		payload.NewHealthCheck(payload.HealthCheckHTTP, "https://localhost/health"),
+	} {
+		c.Logf("checking %#v", hc)
+		err := hc.Validate()
+
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *healthCheckSuite) TestValidateBad(c *gc.C) {
+	for i, test := range []struct {
+		update func(*payload.HealthCheck)
+		err    string
+	}{{
+		update: func(hc *payload.HealthCheck) { hc.Target = "" },
+		err:    `health check missing target not valid`,
+	}, {
+		update: func(hc *payload.HealthCheck) { hc.Kind = "ping" },
+		err:    `health check kind "ping" not valid`,
+	}, {
+		update: func(hc *payload.HealthCheck) { hc.Kind, hc.Target = "tcp", "localhost" },
+		err:    `bad tcp health check address: .*`,
+	}, {
+		update: func(hc *payload.HealthCheck) { hc.Kind, hc.Target = "http", "ftp://localhost" },
+		err:    `http health check URL "ftp://localhost" not valid`,
+	}, {
+		update: func(hc *payload.HealthCheck) { hc.Interval = 0 },
+		err:    `health check interval 0s not valid`,
+	}, {
+		update: func(hc *payload.HealthCheck) { hc.Timeout = time.Minute },
+		err:    `health check timeout 1m0s \(must be .*\) not valid`,
+	}, {
+		update: func(hc *payload.HealthCheck) { hc.UnhealthyThreshold = 0 },
+		err:    `unhealthy threshold 0 not valid`,
+	}, {
+		update: func(hc *payload.HealthCheck) { hc.HealthyThreshold = 0 },
+		err:    `healthy threshold 0 not valid`,
+	}} {
+		c.Logf("test %d", i)
+		hc := payload.NewHealthCheck(payload.HealthCheckExec, "pgrep spam")
+		test.update(&hc)
+
+		err := hc.Validate()
+
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -26,6 +26,10 @@ type Payload struct {
 
 	// Unit identifies the Juju unit associated with the payload.
 	Unit string
+
+	// HealthCheck describes how the unit agent checks the payload's
+	// health. If nil then the payload is not checked.
+	HealthCheck *HealthCheck
 }
 
 // FullID composes a unique ID for the payload (relative to the unit/charm).
@@ -52,6 +56,12 @@ func (p Payload) Validate() error {
 		return errors.NotValidf("missing Unit")
 	}
 
+	if p.HealthCheck != nil {
+		if err := p.HealthCheck.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	return nil
 }
 
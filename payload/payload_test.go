@@ -100,3 +100,11 @@ func (s *payloadSuite) TestValidateMissingUnit(c *gc.C) {
 
 	c.Check(err, gc.ErrorMatches, `missing Unit .*`)
 }
+
+func (s *payloadSuite) TestValidateBadHealthCheck(c *gc.C) {
+	pl := s.newPayload("spam", "docker")
+	pl.HealthCheck = &payload.HealthCheck{}
+	err := pl.Validate()
+
+	c.Check(err, gc.ErrorMatches, `health check missing target not valid`)
+}
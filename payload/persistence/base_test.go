@@ -38,7 +38,7 @@ func (doc PayloadDoc) convert() *payloadDoc {
 }
 
 func (s *BaseSuite) NewDoc(id string, pl payload.Payload) *payloadDoc {
-	return &payloadDoc{
+	doc := &payloadDoc{
 		DocID:  "payload#" + s.Unit + "#" + id,
 		UnitID: s.Unit,
 
@@ -47,6 +47,17 @@ func (s *BaseSuite) NewDoc(id string, pl payload.Payload) *payloadDoc {
 		RawID: pl.ID,
 		State: pl.Status,
 	}
+	if hc := pl.HealthCheck; hc != nil {
+		doc.HealthCheck = &healthCheckDoc{
+			Kind:               hc.Kind,
+			Target:             hc.Target,
+			Interval:           hc.Interval,
+			Timeout:            hc.Timeout,
+			UnhealthyThreshold: hc.UnhealthyThreshold,
+			HealthyThreshold:   hc.HealthyThreshold,
+		}
+	}
+	return doc
 }
 
 func (s *BaseSuite) SetDoc(id string, pl payload.Payload) *payloadDoc {
@@ -6,6 +6,7 @@ package persistence
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -119,6 +120,18 @@ type payloadDoc struct {
 	Labels []string `bson:"labels"`
 
 	RawID string `bson:"rawid"`
+
+	HealthCheck *healthCheckDoc `bson:"healthcheck,omitempty"`
+}
+
+// healthCheckDoc holds the health check declared for a payload.
+type healthCheckDoc struct {
+	Kind               string        `bson:"kind"`
+	Target             string        `bson:"target"`
+	Interval           time.Duration `bson:"interval"`
+	Timeout            time.Duration `bson:"timeout"`
+	UnhealthyThreshold int           `bson:"unhealthy-threshold"`
+	HealthyThreshold   int           `bson:"healthy-threshold"`
 }
 
 func (d payloadDoc) payload(unit string) payload.Payload {
@@ -131,6 +144,16 @@ func (d payloadDoc) payload(unit string) payload.Payload {
 		Labels:       labels,
 		Unit:         unit,
 	}
+	if d.HealthCheck != nil {
+		p.HealthCheck = &payload.HealthCheck{
+			Kind:               d.HealthCheck.Kind,
+			Target:             d.HealthCheck.Target,
+			Interval:           d.HealthCheck.Interval,
+			Timeout:            d.HealthCheck.Timeout,
+			UnhealthyThreshold: d.HealthCheck.UnhealthyThreshold,
+			HealthyThreshold:   d.HealthCheck.HealthyThreshold,
+		}
+	}
 	return p
 }
 
@@ -160,7 +183,7 @@ func (pp Persistence) newPayloadDoc(id string, p payload.Payload) *payloadDoc {
 	labels := make([]string, len(p.Labels))
 	copy(labels, p.Labels)
 
-	return &payloadDoc{
+	doc := &payloadDoc{
 		DocID:  id,
 		UnitID: pp.unit,
 
@@ -173,6 +196,17 @@ func (pp Persistence) newPayloadDoc(id string, p payload.Payload) *payloadDoc {
 
 		RawID: p.ID,
 	}
+	if hc := p.HealthCheck; hc != nil {
+		doc.HealthCheck = &healthCheckDoc{
+			Kind:               hc.Kind,
+			Target:             hc.Target,
+			Interval:           hc.Interval,
+			Timeout:            hc.Timeout,
+			UnhealthyThreshold: hc.UnhealthyThreshold,
+			HealthyThreshold:   hc.HealthyThreshold,
+		}
+	}
+	return doc
 }
 
 func (pp Persistence) allPayloads() (map[string]payloadDoc, error) {
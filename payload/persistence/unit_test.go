@@ -51,6 +51,28 @@ func (s *payloadsPersistenceSuite) TestTrackOkay(c *gc.C) {
 	}})
 }
 
+func (s *payloadsPersistenceSuite) TestTrackWithHealthCheck(c *gc.C) {
+	pl := s.NewPayload("docker", "payloadA/payloadA-xyz")
+	hc := payload.NewHealthCheck(payload.HealthCheckTCP, "localhost:8080")
+	pl.HealthCheck = &hc
+
+	wp := s.NewPersistence()
+	id := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	okay, err := wp.Track(id, pl)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(okay, jc.IsTrue)
+	s.Stub.CheckCallNames(c, "All", "Run")
+	s.State.CheckOps(c, [][]txn.Op{{
+		{
+			C:      "payloads",
+			Id:     "payload#a-unit/0#f47ac10b-58cc-4372-a567-0e02b2c3d479",
+			Assert: txn.DocMissing,
+			Insert: s.NewDoc(id, pl),
+		},
+	}})
+}
+
 func (s *payloadsPersistenceSuite) TestTrackAlreadyExists(c *gc.C) {
 	id := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
 
@@ -216,6 +238,19 @@ func (s *payloadsPersistenceSuite) TestListAllOkay(c *gc.C) {
 	c.Check(payloads, jc.DeepEquals, existing)
 }
 
+func (s *payloadsPersistenceSuite) TestListAllWithHealthCheck(c *gc.C) {
+	pl := s.NewPayload("docker", "payloadA/xyz")
+	hc := payload.NewHealthCheck(payload.HealthCheckExec, "pgrep spam")
+	pl.HealthCheck = &hc
+	s.SetDoc("0", pl)
+
+	pp := s.NewPersistence()
+	payloads, err := pp.ListAll()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(payloads, jc.DeepEquals, []payload.Payload{pl})
+}
+
 func (s *payloadsPersistenceSuite) TestListAllEmpty(c *gc.C) {
 	pp := s.NewPersistence()
 	payloads, err := pp.ListAll()
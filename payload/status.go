@@ -21,6 +21,10 @@ const (
 	StateRunning  = "running"
 	StateStopping = "stopping"
 	StateStopped  = "stopped"
+
+	// StateUnhealthy is set by the unit agent (in place of
+	// StateRunning) when a payload's health check keeps failing.
+	StateUnhealthy = "unhealthy"
 )
 
 var okayStates = set.NewStrings(
@@ -28,6 +32,7 @@ var okayStates = set.NewStrings(
 	StateRunning,
 	StateStopping,
 	StateStopped,
+	StateUnhealthy,
 )
 
 // ValidateState verifies the state passed in is a valid okayState.
@@ -10,22 +10,13 @@ import (
 	"github.com/juju/juju/instance"
 )
 
-// AvailabilityZone describes a provider availability zone.
-type AvailabilityZone interface {
-	// Name returns the name of the availability zone.
-	Name() string
-
-	// Available reports whether the availability zone is currently available.
-	Available() bool
-}
-
 // ZonedEnviron is an environs.Environ that has support for
 // availability zones.
 type ZonedEnviron interface {
 	environs.Environ
 
 	// AvailabilityZones returns all availability zones in the environment.
-	AvailabilityZones() ([]AvailabilityZone, error)
+	AvailabilityZones() ([]instance.AvailabilityZone, error)
 
 	// InstanceAvailabilityZoneNames returns the names of the availability
 	// zones for the specified instances. The error returned follows the same
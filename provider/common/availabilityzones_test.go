@@ -33,14 +33,14 @@ func (s *AvailabilityZoneSuite) SetUpSuite(c *gc.C) {
 		return allInstances, nil
 	}
 
-	availabilityZones := make([]common.AvailabilityZone, 3)
+	availabilityZones := make([]instance.AvailabilityZone, 3)
 	for i := range availabilityZones {
 		availabilityZones[i] = &mockAvailabilityZone{
 			name:      fmt.Sprintf("az%d", i),
 			available: i > 0,
 		}
 	}
-	s.env.availabilityZones = func() ([]common.AvailabilityZone, error) {
+	s.env.availabilityZones = func() ([]instance.AvailabilityZone, error) {
 		return availabilityZones, nil
 	}
 }
@@ -127,9 +127,9 @@ func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsNoZones(c *gc.C)
 		calls = append(calls, "InstanceAvailabilityZoneNames")
 		return []string{"", "", ""}, nil
 	})
-	s.PatchValue(&s.env.availabilityZones, func() ([]common.AvailabilityZone, error) {
+	s.PatchValue(&s.env.availabilityZones, func() ([]instance.AvailabilityZone, error) {
 		calls = append(calls, "AvailabilityZones")
-		return []common.AvailabilityZone{}, nil
+		return []instance.AvailabilityZone{}, nil
 	})
 	zoneInstances, err := common.AvailabilityZoneAllocations(&s.env, nil)
 	c.Assert(calls, gc.DeepEquals, []string{"InstanceAvailabilityZoneNames", "AvailabilityZones"})
@@ -145,7 +145,7 @@ func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsErrors(c *gc.C) {
 		return []string{"", "", ""}, nil
 	})
 	resultErr := fmt.Errorf("u can haz no az")
-	s.PatchValue(&s.env.availabilityZones, func() ([]common.AvailabilityZone, error) {
+	s.PatchValue(&s.env.availabilityZones, func() ([]instance.AvailabilityZone, error) {
 		calls = append(calls, "AvailabilityZones")
 		return nil, resultErr
 	})
@@ -14,7 +14,6 @@ import (
 	"github.com/juju/juju/environs/storage"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
-	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/tools"
 )
 
@@ -88,7 +87,7 @@ func (env *mockEnviron) GetToolsSources() ([]simplestreams.DataSource, error) {
 	return []simplestreams.DataSource{datasource}, nil
 }
 
-type availabilityZonesFunc func() ([]common.AvailabilityZone, error)
+type availabilityZonesFunc func() ([]instance.AvailabilityZone, error)
 type instanceAvailabilityZoneNamesFunc func([]instance.Id) ([]string, error)
 
 type mockZonedEnviron struct {
@@ -97,7 +96,7 @@ type mockZonedEnviron struct {
 	instanceAvailabilityZoneNames instanceAvailabilityZoneNamesFunc
 }
 
-func (env *mockZonedEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (env *mockZonedEnviron) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	return env.availabilityZones()
 }
 
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -197,6 +198,7 @@ type OpStartInstance struct {
 	APIInfo          *api.Info
 	Secret           string
 	AgentEnvironment map[string]string
+	RootDiskSource   string
 }
 
 type OpStopInstances struct {
@@ -209,6 +211,7 @@ type OpOpenPorts struct {
 	MachineId  string
 	InstanceId instance.Id
 	Ports      []network.PortRange
+	Rules      []network.IngressRule
 }
 
 type OpClosePorts struct {
@@ -216,6 +219,7 @@ type OpClosePorts struct {
 	MachineId  string
 	InstanceId instance.Id
 	Ports      []network.PortRange
+	Rules      []network.IngressRule
 }
 
 type OpPutFile struct {
@@ -252,7 +256,10 @@ type environState struct {
 	maxId        int // maximum instance id allocated so far.
 	maxAddr      int // maximum allocated address last byte
 	insts        map[instance.Id]*dummyInstance
-	globalPorts  map[network.PortRange]bool
+	globalRules  map[string]network.IngressRule
+	egressPolicy network.EgressPolicy
+	lbs          map[string]*LoadBalancer
+	maxLB        int // maximum load balancer address last byte
 	bootstrapped bool
 	apiListener  net.Listener
 	apiServer    *apiserver.Server
@@ -271,7 +278,15 @@ type environ struct {
 	spacesMutex  sync.RWMutex
 }
 
-var _ environs.Environ = (*environ)(nil)
+var (
+	_ environs.Environ               = (*environ)(nil)
+	_ environs.IngressRuleFirewaller = (*environ)(nil)
+	_ environs.EgressFirewaller      = (*environ)(nil)
+	_ instance.IngressRuleFirewaller = (*dummyInstance)(nil)
+
+	_ environs.SubnetIngressRuleFirewaller = (*environ)(nil)
+	_ environs.LoadBalancerManager         = (*environ)(nil)
+)
 
 // discardOperations discards all Operations written to it.
 var discardOperations chan<- Operation
@@ -363,7 +378,8 @@ func newState(name string, ops chan<- Operation, policy state.Policy) *environSt
 		ops:         ops,
 		statePolicy: policy,
 		insts:       make(map[instance.Id]*dummyInstance),
-		globalPorts: make(map[network.PortRange]bool),
+		globalRules: make(map[string]network.IngressRule),
+		lbs:         make(map[string]*LoadBalancer),
 	}
 	return s
 }
@@ -711,7 +727,7 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 	i := &dummyInstance{
 		id:           BootstrapInstanceId,
 		addresses:    network.NewAddresses("localhost"),
-		ports:        make(map[network.PortRange]bool),
+		rules:        make(map[string]network.IngressRule),
 		machineId:    agent.BootstrapMachineId,
 		series:       series,
 		firewallMode: e.Config().FirewallMode(),
@@ -735,6 +751,7 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 		if err != nil {
 			panic(err)
 		}
+		st.SetSecretsKey(testing.SecretsKey)
 		if err := st.SetModelConstraints(args.EnvironConstraints); err != nil {
 			panic(err)
 		}
@@ -909,7 +926,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	i := &dummyInstance{
 		id:           instance.Id(idString),
 		addresses:    addrs,
-		ports:        make(map[network.PortRange]bool),
+		rules:        make(map[string]network.IngressRule),
 		machineId:    machineId,
 		series:       series,
 		firewallMode: e.Config().FirewallMode(),
@@ -947,6 +964,12 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 			cores := uint64(1)
 			hc.CpuCores = &cores
 		}
+		// Honour zone placement directives, so the chosen zone is
+		// recorded against the machine.
+		if strings.HasPrefix(args.Placement, "zone=") {
+			zone := strings.TrimPrefix(args.Placement, "zone=")
+			hc.AvailabilityZone = &zone
+		}
 	}
 	// Simulate subnetsToZones gets populated when spaces given in constraints.
 	spaces := args.Constraints.IncludeSpaces()
@@ -990,6 +1013,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		APIInfo:          args.InstanceConfig.APIInfo,
 		AgentEnvironment: args.InstanceConfig.AgentEnvironment,
 		Secret:           e.ecfg().secret(),
+		RootDiskSource:   args.RootDiskSource,
 	}
 	return &environs.StartInstanceResult{
 		Instance: i,
@@ -997,6 +1021,16 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	}, nil
 }
 
+// ValidateRootDiskSource is specified on the
+// environs.RootDiskSourceValidator interface. The dummy provider
+// accepts any root-disk source other than "invalid".
+func (e *environ) ValidateRootDiskSource(source string) error {
+	if source == "invalid" {
+		return errors.NotValidf("root-disk source %q", source)
+	}
+	return nil
+}
+
 func (e *environ) StopInstances(ids ...instance.Id) error {
 	defer delay()
 	if err := e.checkBroken("StopInstance"); err != nil {
@@ -1276,9 +1310,9 @@ func (az azShim) Available() bool {
 }
 
 // AvailabilityZones implements environs.ZonedEnviron.
-func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (env *environ) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	// TODO(dimitern): Fix this properly.
-	return []common.AvailabilityZone{
+	return []instance.AvailabilityZone{
 		azShim{"zone1", true},
 		azShim{"zone2", false},
 	}, nil
@@ -1324,15 +1358,23 @@ func (env *environ) Subnets(instId instance.Id, subnetIds []network.Id) ([]netwo
 		AllocatableIPLow:  net.ParseIP("0.20.0.0"),
 		AllocatableIPHigh: net.ParseIP("0.20.0.255"),
 	}}
+	if estate.preferIPv6 {
+		// Instances are given an address in this subnet when
+		// IPv6 is preferred.
+		allSubnets = append(allSubnets, network.SubnetInfo{
+			CIDR:              "fc00::/64",
+			ProviderId:        "dummy-private-ipv6",
+			AvailabilityZones: []string{"zone1", "zone2"},
+		})
+	}
 
 	// Filter result by ids, if given.
 	var result []network.SubnetInfo
 	for _, subId := range subnetIds {
-		switch subId {
-		case "dummy-private":
-			result = append(result, allSubnets[0])
-		case "dummy-public":
-			result = append(result, allSubnets[1])
+		for _, subnet := range allSubnets {
+			if subnet.ProviderId == subId {
+				result = append(result, subnet)
+			}
 		}
 	}
 	if len(subnetIds) == 0 {
@@ -1449,6 +1491,30 @@ func (e *environ) AllInstances() ([]instance.Instance, error) {
 }
 
 func (e *environ) OpenPorts(ports []network.PortRange) error {
+	return e.OpenIngressRules(network.IngressRulesFromPortRanges(ports))
+}
+
+func (e *environ) ClosePorts(ports []network.PortRange) error {
+	return e.CloseIngressRules(network.IngressRulesFromPortRanges(ports))
+}
+
+func (e *environ) Ports() ([]network.PortRange, error) {
+	rules, err := e.IngressRules()
+	if err != nil {
+		return nil, err
+	}
+	ports := network.IngressRulePortRanges(rules)
+	network.SortPortRanges(ports)
+	return ports, nil
+}
+
+// SupportsSubnetIngressRules is specified on
+// environs.SubnetIngressRuleFirewaller.
+func (e *environ) SupportsSubnetIngressRules() bool {
+	return true
+}
+
+func (e *environ) OpenIngressRules(rules []network.IngressRule) error {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on model", mode)
 	}
@@ -1458,13 +1524,13 @@ func (e *environ) OpenPorts(ports []network.PortRange) error {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
-	for _, p := range ports {
-		estate.globalPorts[p] = true
+	for _, r := range rules {
+		estate.globalRules[r.String()] = r
 	}
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+func (e *environ) CloseIngressRules(rules []network.IngressRule) error {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on model", mode)
 	}
@@ -1474,13 +1540,13 @@ func (e *environ) ClosePorts(ports []network.PortRange) error {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
-	for _, p := range ports {
-		delete(estate.globalPorts, p)
+	for _, r := range rules {
+		delete(estate.globalRules, r.String())
 	}
 	return nil
 }
 
-func (e *environ) Ports() (ports []network.PortRange, err error) {
+func (e *environ) IngressRules() (rules []network.IngressRule, err error) {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from model", mode)
 	}
@@ -1490,20 +1556,140 @@ func (e *environ) Ports() (ports []network.PortRange, err error) {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
-	for p := range estate.globalPorts {
-		ports = append(ports, p)
+	for _, r := range estate.globalRules {
+		rules = append(rules, r)
 	}
-	network.SortPortRanges(ports)
+	network.SortIngressRules(rules)
 	return
 }
 
+// SetEgressPolicy is specified in the environs.EgressFirewaller interface.
+func (e *environ) SetEgressPolicy(policy network.EgressPolicy) error {
+	if err := e.checkBroken("SetEgressPolicy"); err != nil {
+		return err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	estate.egressPolicy = policy
+	return nil
+}
+
+// EgressPolicy is specified in the environs.EgressFirewaller interface.
+func (e *environ) EgressPolicy() (network.EgressPolicy, error) {
+	estate, err := e.state()
+	if err != nil {
+		return network.EgressPolicy{}, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	return estate.egressPolicy, nil
+}
+
+// LoadBalancer describes a load balancer created in a dummy
+// environment.
+type LoadBalancer struct {
+	Name        string
+	Address     network.Address
+	Ports       []network.PortRange
+	SourceCIDRs []string
+	InstanceIds []instance.Id
+}
+
+// EnsureLoadBalancer is specified in the environs.LoadBalancerManager
+// interface.
+func (e *environ) EnsureLoadBalancer(
+	name string,
+	ports []network.PortRange,
+	sourceCIDRs []string,
+	instanceIds []instance.Id,
+) (network.Address, error) {
+	if err := e.checkBroken("EnsureLoadBalancer"); err != nil {
+		return network.Address{}, err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return network.Address{}, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	lb, ok := estate.lbs[name]
+	if !ok {
+		estate.maxLB++
+		lb = &LoadBalancer{
+			Name:    name,
+			Address: network.NewScopedAddress(fmt.Sprintf("0.30.0.%d", estate.maxLB), network.ScopePublic),
+		}
+		estate.lbs[name] = lb
+	}
+	lb.Ports = append([]network.PortRange(nil), ports...)
+	lb.SourceCIDRs = append([]string(nil), sourceCIDRs...)
+	lb.InstanceIds = append([]instance.Id(nil), instanceIds...)
+	return lb.Address, nil
+}
+
+// RemoveLoadBalancer is specified in the environs.LoadBalancerManager
+// interface.
+func (e *environ) RemoveLoadBalancer(name string) error {
+	if err := e.checkBroken("RemoveLoadBalancer"); err != nil {
+		return err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	delete(estate.lbs, name)
+	return nil
+}
+
+// LoadBalancerNames is specified in the environs.LoadBalancerManager
+// interface.
+func (e *environ) LoadBalancerNames() ([]string, error) {
+	if err := e.checkBroken("LoadBalancerNames"); err != nil {
+		return nil, err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	var names []string
+	for name := range estate.lbs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadBalancers returns the load balancers created in the given dummy
+// environment, keyed by name.
+func LoadBalancers(env environs.Environ) (map[string]LoadBalancer, error) {
+	estate, err := env.(*environ).state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	lbs := make(map[string]LoadBalancer)
+	for name, lb := range estate.lbs {
+		lbs[name] = *lb
+	}
+	return lbs, nil
+}
+
 func (*environ) Provider() environs.EnvironProvider {
 	return &providerInstance
 }
 
 type dummyInstance struct {
 	state        *environState
-	ports        map[network.PortRange]bool
+	rules        map[string]network.IngressRule
 	id           instance.Id
 	status       string
 	machineId    string
@@ -1573,76 +1759,108 @@ func (inst *dummyInstance) Addresses() ([]network.Address, error) {
 }
 
 func (inst *dummyInstance) OpenPorts(machineId string, ports []network.PortRange) error {
+	return inst.openIngressRules("OpenPorts", machineId, network.IngressRulesFromPortRanges(ports))
+}
+
+func (inst *dummyInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+	return inst.closeIngressRules("ClosePorts", machineId, network.IngressRulesFromPortRanges(ports))
+}
+
+func (inst *dummyInstance) Ports(machineId string) ([]network.PortRange, error) {
+	rules, err := inst.ingressRules("Ports", machineId)
+	if err != nil {
+		return nil, err
+	}
+	ports := network.IngressRulePortRanges(rules)
+	network.SortPortRanges(ports)
+	return ports, nil
+}
+
+func (inst *dummyInstance) OpenIngressRules(machineId string, rules []network.IngressRule) error {
+	return inst.openIngressRules("OpenIngressRules", machineId, rules)
+}
+
+func (inst *dummyInstance) CloseIngressRules(machineId string, rules []network.IngressRule) error {
+	return inst.closeIngressRules("CloseIngressRules", machineId, rules)
+}
+
+func (inst *dummyInstance) IngressRules(machineId string) ([]network.IngressRule, error) {
+	return inst.ingressRules("IngressRules", machineId)
+}
+
+func (inst *dummyInstance) openIngressRules(method, machineId string, rules []network.IngressRule) error {
 	defer delay()
-	logger.Infof("openPorts %s, %#v", machineId, ports)
+	logger.Infof("openPorts %s, %#v", machineId, rules)
 	if inst.firewallMode != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			inst.firewallMode)
 	}
 	if inst.machineId != machineId {
-		panic(fmt.Errorf("OpenPorts with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+		panic(fmt.Errorf("%s with mismatched machine id, expected %q got %q", method, inst.machineId, machineId))
 	}
 	inst.state.mu.Lock()
 	defer inst.state.mu.Unlock()
-	if err := inst.checkBroken("OpenPorts"); err != nil {
+	if err := inst.checkBroken(method); err != nil {
 		return err
 	}
 	inst.state.ops <- OpOpenPorts{
 		Env:        inst.state.name,
 		MachineId:  machineId,
 		InstanceId: inst.Id(),
-		Ports:      ports,
+		Ports:      network.IngressRulePortRanges(rules),
+		Rules:      rules,
 	}
-	for _, p := range ports {
-		inst.ports[p] = true
+	for _, r := range rules {
+		inst.rules[r.String()] = r
 	}
 	return nil
 }
 
-func (inst *dummyInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+func (inst *dummyInstance) closeIngressRules(method, machineId string, rules []network.IngressRule) error {
 	defer delay()
 	if inst.firewallMode != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
 			inst.firewallMode)
 	}
 	if inst.machineId != machineId {
-		panic(fmt.Errorf("ClosePorts with mismatched machine id, expected %s got %s", inst.machineId, machineId))
+		panic(fmt.Errorf("%s with mismatched machine id, expected %s got %s", method, inst.machineId, machineId))
 	}
 	inst.state.mu.Lock()
 	defer inst.state.mu.Unlock()
-	if err := inst.checkBroken("ClosePorts"); err != nil {
+	if err := inst.checkBroken(method); err != nil {
 		return err
 	}
 	inst.state.ops <- OpClosePorts{
 		Env:        inst.state.name,
 		MachineId:  machineId,
 		InstanceId: inst.Id(),
-		Ports:      ports,
+		Ports:      network.IngressRulePortRanges(rules),
+		Rules:      rules,
 	}
-	for _, p := range ports {
-		delete(inst.ports, p)
+	for _, r := range rules {
+		delete(inst.rules, r.String())
 	}
 	return nil
 }
 
-func (inst *dummyInstance) Ports(machineId string) (ports []network.PortRange, err error) {
+func (inst *dummyInstance) ingressRules(method, machineId string) (rules []network.IngressRule, err error) {
 	defer delay()
 	if inst.firewallMode != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
 			inst.firewallMode)
 	}
 	if inst.machineId != machineId {
-		panic(fmt.Errorf("Ports with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+		panic(fmt.Errorf("%s with mismatched machine id, expected %q got %q", method, inst.machineId, machineId))
 	}
 	inst.state.mu.Lock()
 	defer inst.state.mu.Unlock()
-	if err := inst.checkBroken("Ports"); err != nil {
+	if err := inst.checkBroken(method); err != nil {
 		return nil, err
 	}
-	for p := range inst.ports {
-		ports = append(ports, p)
+	for _, r := range inst.rules {
+		rules = append(rules, r)
 	}
-	network.SortPortRanges(ports)
+	network.SortIngressRules(rules)
 	return
 }
 
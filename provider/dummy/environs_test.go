@@ -565,6 +565,15 @@ func (s *suite) TestPreferIPv6On(c *gc.C) {
 	addrs, err := inst.Addresses()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(addrs, jc.DeepEquals, network.NewAddresses("only-0.dns", "127.0.0.1", "fc00::1"))
+
+	// The instance's IPv6 address is in one of the subnets.
+	netInfo, err := e.Subnets(inst.Id(), []network.Id{"dummy-private-ipv6"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(netInfo, jc.DeepEquals, []network.SubnetInfo{{
+		CIDR:              "fc00::/64",
+		ProviderId:        "dummy-private-ipv6",
+		AvailabilityZones: []string{"zone1", "zone2"},
+	}})
 }
 
 func (s *suite) TestPreferIPv6Off(c *gc.C) {
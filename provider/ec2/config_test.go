@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"gopkg.in/amz.v3/aws"
@@ -338,6 +339,19 @@ func (s *ConfigSuite) TestPrepareForCreateInsertsUniqueControlBucket(c *gc.C) {
 	c.Assert(bucket1, gc.Not(gc.Equals), bucket2)
 }
 
+func (s *ConfigSuite) TestPrepareForCreatePreferIPv6NotSupported(c *gc.C) {
+	attrs := testing.FakeConfig().Merge(testing.Attrs{
+		"type":        "ec2",
+		"prefer-ipv6": true,
+	})
+	cfg, err := config.New(config.NoDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = providerInstance.PrepareForCreateEnvironment(cfg)
+	c.Assert(err, gc.ErrorMatches, "prefer-ipv6 on ec2 not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *ConfigSuite) TestPrepareInsertsUniqueControlBucket(c *gc.C) {
 	s.PatchValue(&verifyCredentials, func(*environ) error { return nil })
 	attrs := testing.FakeConfig().Merge(testing.Attrs{
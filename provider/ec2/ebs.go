@@ -4,6 +4,7 @@
 package ec2
 
 import (
+	"fmt"
 	"regexp"
 	"sync"
 	"time"
@@ -836,6 +837,24 @@ func minRootDiskSizeMiB(ser string) uint64 {
 	return gibToMib(common.MinRootDiskSizeGiB(ser))
 }
 
+// rootDiskVolumeType returns the EBS volume type of root disks created
+// from the named root-disk source, which is a volume type or one of its
+// aliases. Provisioned IOPS volumes cannot be used, as there is no way
+// to specify their IOPS rate.
+func rootDiskVolumeType(source string) (string, error) {
+	switch source {
+	case volumeTypeMagnetic, volumeTypeStandard:
+		return volumeTypeStandard, nil
+	case volumeTypeSsd, volumeTypeGp2:
+		return volumeTypeGp2, nil
+	case volumeTypeProvisionedIops, volumeTypeIo1:
+		return "", errors.NewNotValid(nil, fmt.Sprintf(
+			"root-disk source %q needs an IOPS rate, which cannot be specified", source,
+		))
+	}
+	return "", errors.NotValidf("root-disk source %q", source)
+}
+
 // getBlockDeviceMappings translates constraints into BlockDeviceMappings.
 //
 // The first entry is always the root disk mapping, followed by instance
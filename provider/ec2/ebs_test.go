@@ -703,3 +703,22 @@ func (*blockDeviceMappingSuite) TestGetBlockDeviceMappings(c *gc.C) {
 		DeviceName:  "/dev/sde",
 	}})
 }
+
+func (*blockDeviceMappingSuite) TestRootDiskVolumeType(c *gc.C) {
+	for source, expect := range map[string]string{
+		"magnetic": "standard",
+		"standard": "standard",
+		"ssd":      "gp2",
+		"gp2":      "gp2",
+	} {
+		volumeType, err := ec2.RootDiskVolumeType(source)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(volumeType, gc.Equals, expect)
+	}
+	_, err := ec2.RootDiskVolumeType("io1")
+	c.Check(err, gc.ErrorMatches, `root-disk source "io1" needs an IOPS rate, which cannot be specified`)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	_, err = ec2.RootDiskVolumeType("floppy")
+	c.Check(err, gc.ErrorMatches, `root-disk source "floppy" not valid`)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -77,7 +77,7 @@ type environ struct {
 	storageUnlocked envstorage.Storage
 
 	availabilityZonesMutex sync.Mutex
-	availabilityZones      []common.AvailabilityZone
+	availabilityZones      []instance.AvailabilityZone
 
 	// cachedDefaultVpc caches the id of the ec2 default vpc
 	cachedDefaultVpc *defaultVpc
@@ -85,6 +85,7 @@ type environ struct {
 
 // Ensure EC2 provider supports environs.NetworkingEnviron.
 var _ environs.NetworkingEnviron = (*environ)(nil)
+var _ environs.IngressRuleFirewaller = (*environ)(nil)
 var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
@@ -306,9 +307,17 @@ func (z *ec2AvailabilityZone) Available() bool {
 	return z.AvailabilityZoneInfo.State == "available"
 }
 
+// ValidateRootDiskSource is specified on the
+// environs.RootDiskSourceValidator interface. Root disks may be
+// created from any EBS volume type other than provisioned IOPS.
+func (e *environ) ValidateRootDiskSource(source string) error {
+	_, err := rootDiskVolumeType(source)
+	return err
+}
+
 // AvailabilityZones returns a slice of availability zones
 // for the configured region.
-func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *environ) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	e.availabilityZonesMutex.Lock()
 	defer e.availabilityZonesMutex.Unlock()
 	if e.availabilityZones == nil {
@@ -319,7 +328,7 @@ func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
 			return nil, err
 		}
 		logger.Debugf("availability zones: %+v", resp)
-		e.availabilityZones = make([]common.AvailabilityZone, len(resp.Zones))
+		e.availabilityZones = make([]instance.AvailabilityZone, len(resp.Zones))
 		for i, z := range resp.Zones {
 			e.availabilityZones[i] = &ec2AvailabilityZone{z}
 		}
@@ -543,6 +552,13 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 	}
 
 	blockDeviceMappings := getBlockDeviceMappings(args.Constraints, args.InstanceConfig.Series)
+	if args.RootDiskSource != "" {
+		volumeType, err := rootDiskVolumeType(args.RootDiskSource)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		blockDeviceMappings[0].VolumeType = volumeType
+	}
 	rootDiskSize := uint64(blockDeviceMappings[0].VolumeSize) * 1024
 
 	// If --constraints spaces=foo was passed, the provisioner will populate
@@ -1220,23 +1236,28 @@ func (e *environ) Destroy() error {
 }
 
 func portsToIPPerms(ports []network.PortRange) []ec2.IPPerm {
-	ipPerms := make([]ec2.IPPerm, len(ports))
-	for i, p := range ports {
+	return rulesToIPPerms(network.IngressRulesFromPortRanges(ports))
+}
+
+func rulesToIPPerms(rules []network.IngressRule) []ec2.IPPerm {
+	ipPerms := make([]ec2.IPPerm, len(rules))
+	for i, r := range rules {
 		ipPerms[i] = ec2.IPPerm{
-			Protocol:  p.Protocol,
-			FromPort:  p.FromPort,
-			ToPort:    p.ToPort,
-			SourceIPs: []string{"0.0.0.0/0"},
+			Protocol:  r.Protocol,
+			FromPort:  r.FromPort,
+			ToPort:    r.ToPort,
+			SourceIPs: network.IngressRuleSourceCIDRs(r),
 		}
 	}
 	return ipPerms
 }
 
-func (e *environ) openPortsInGroup(name, legacyName string, ports []network.PortRange) error {
-	if len(ports) == 0 {
+func (e *environ) openPortsInGroup(name, legacyName string, rules []network.IngressRule) error {
+	if len(rules) == 0 {
 		return nil
 	}
-	// Give permissions for anyone to access the given ports.
+	// Give permissions for the given source addresses to access
+	// the given ports.
 	g, err := e.groupByName(name)
 	if ec2ErrCode(err) != "InvalidGroup.NotFound" {
 		// We might be trying to destroy a legacy system
@@ -1246,10 +1267,10 @@ func (e *environ) openPortsInGroup(name, legacyName string, ports []network.Port
 	if err != nil {
 		return err
 	}
-	ipPerms := portsToIPPerms(ports)
+	ipPerms := rulesToIPPerms(rules)
 	_, err = e.ec2().AuthorizeSecurityGroup(g, ipPerms)
 	if err != nil && ec2ErrCode(err) == "InvalidPermission.Duplicate" {
-		if len(ports) == 1 {
+		if len(rules) == 1 {
 			return nil
 		}
 		// If there's more than one port and we get a duplicate error,
@@ -1270,13 +1291,13 @@ func (e *environ) openPortsInGroup(name, legacyName string, ports []network.Port
 	return nil
 }
 
-func (e *environ) closePortsInGroup(name, legacyName string, ports []network.PortRange) error {
-	if len(ports) == 0 {
+func (e *environ) closePortsInGroup(name, legacyName string, rules []network.IngressRule) error {
+	if len(rules) == 0 {
 		return nil
 	}
-	// Revoke permissions for anyone to access the given ports.
-	// Note that ec2 allows the revocation of permissions that aren't
-	// granted, so this is naturally idempotent.
+	// Revoke permissions for the given source addresses to access
+	// the given ports. Note that ec2 allows the revocation of
+	// permissions that aren't granted, so this is naturally idempotent.
 	g, err := e.groupByName(name)
 	if ec2ErrCode(err) != "InvalidGroup.NotFound" {
 		// We might be trying to destroy a legacy system
@@ -1285,63 +1306,99 @@ func (e *environ) closePortsInGroup(name, legacyName string, ports []network.Por
 	if err != nil {
 		return err
 	}
-	_, err = e.ec2().RevokeSecurityGroup(g, portsToIPPerms(ports))
+	_, err = e.ec2().RevokeSecurityGroup(g, rulesToIPPerms(rules))
 	if err != nil {
 		return fmt.Errorf("cannot close ports: %v", err)
 	}
 	return nil
 }
 
-func (e *environ) portsInGroup(name string) (ports []network.PortRange, err error) {
+func (e *environ) rulesInGroup(name string) (rules []network.IngressRule, err error) {
 	group, err := e.groupInfoByName(name)
 	if err != nil {
 		return nil, err
 	}
 	for _, p := range group.IPPerms {
-		if len(p.SourceIPs) != 1 {
+		if len(p.SourceIPs) == 0 {
 			logger.Warningf("unexpected IP permission found: %v", p)
 			continue
 		}
-		ports = append(ports, network.PortRange{
+		portRange := network.PortRange{
 			Protocol: p.Protocol,
 			FromPort: p.FromPort,
 			ToPort:   p.ToPort,
-		})
+		}
+		// EC2 reports all the addresses allowed to access a port
+		// range together.
+		portRules, err := network.IngressRulesForSourceCIDRs(portRange, p.SourceIPs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, portRules...)
+	}
+	network.SortIngressRules(rules)
+	return rules, nil
+}
+
+func (e *environ) portsInGroup(name string) ([]network.PortRange, error) {
+	rules, err := e.rulesInGroup(name)
+	if err != nil {
+		return nil, err
 	}
+	ports := network.IngressRulePortRanges(rules)
 	network.SortPortRanges(ports)
 	return ports, nil
 }
 
 func (e *environ) OpenPorts(ports []network.PortRange) error {
+	return e.OpenIngressRules(network.IngressRulesFromPortRanges(ports))
+}
+
+func (e *environ) ClosePorts(ports []network.PortRange) error {
+	return e.CloseIngressRules(network.IngressRulesFromPortRanges(ports))
+}
+
+func (e *environ) Ports() ([]network.PortRange, error) {
+	if e.Config().FirewallMode() != config.FwGlobal {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from model",
+			e.Config().FirewallMode())
+	}
+	return e.portsInGroup(e.globalGroupName())
+}
+
+// OpenIngressRules is specified in the IngressRuleFirewaller interface.
+func (e *environ) OpenIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on model",
 			e.Config().FirewallMode())
 	}
-	if err := e.openPortsInGroup(e.globalGroupName(), e.legacyGlobalGroupName(), ports); err != nil {
+	if err := e.openPortsInGroup(e.globalGroupName(), e.legacyGlobalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in global group: %v", ports)
+	logger.Infof("opened ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+// CloseIngressRules is specified in the IngressRuleFirewaller interface.
+func (e *environ) CloseIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on model",
 			e.Config().FirewallMode())
 	}
-	if err := e.closePortsInGroup(e.globalGroupName(), e.legacyGlobalGroupName(), ports); err != nil {
+	if err := e.closePortsInGroup(e.globalGroupName(), e.legacyGlobalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in global group: %v", ports)
+	logger.Infof("closed ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) Ports() ([]network.PortRange, error) {
+// IngressRules is specified in the IngressRuleFirewaller interface.
+func (e *environ) IngressRules() ([]network.IngressRule, error) {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from model",
 			e.Config().FirewallMode())
 	}
-	return e.portsInGroup(e.globalGroupName())
+	return e.rulesInGroup(e.globalGroupName())
 }
 
 func (*environ) Provider() environs.EnvironProvider {
@@ -153,3 +153,21 @@ func (*Suite) TestPortsToIPPerms(c *gc.C) {
 		c.Assert(ipperms, gc.DeepEquals, t.expected)
 	}
 }
+
+func (*Suite) TestRulesToIPPerms(c *gc.C) {
+	ipperms := rulesToIPPerms([]network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}),
+		network.MustNewIngressRule(network.PortRange{443, 443, "tcp"}, "10.0.0.0/8", "192.168.1.0/24"),
+	})
+	c.Assert(ipperms, gc.DeepEquals, []amzec2.IPPerm{{
+		Protocol:  "tcp",
+		FromPort:  80,
+		ToPort:    80,
+		SourceIPs: []string{"0.0.0.0/0"},
+	}, {
+		Protocol:  "tcp",
+		FromPort:  443,
+		ToPort:    443,
+		SourceIPs: []string{"10.0.0.0/8", "192.168.1.0/24"},
+	}})
+}
@@ -57,6 +57,7 @@ var (
 	RunInstances                = &runInstances
 	BlockDeviceNamer            = blockDeviceNamer
 	GetBlockDeviceMappings      = getBlockDeviceMappings
+	RootDiskVolumeType          = rootDiskVolumeType
 )
 
 // BucketStorage returns a storage instance addressing
@@ -13,6 +13,8 @@ import (
 	"github.com/juju/juju/network"
 )
 
+var _ instance.IngressRuleFirewaller = (*ec2Instance)(nil)
+
 type ec2Instance struct {
 	e *environ
 
@@ -58,42 +60,65 @@ func (inst *ec2Instance) Addresses() ([]network.Address, error) {
 }
 
 func (inst *ec2Instance) OpenPorts(machineId string, ports []network.PortRange) error {
+	return inst.OpenIngressRules(machineId, network.IngressRulesFromPortRanges(ports))
+}
+
+func (inst *ec2Instance) ClosePorts(machineId string, ports []network.PortRange) error {
+	return inst.CloseIngressRules(machineId, network.IngressRulesFromPortRanges(ports))
+}
+
+func (inst *ec2Instance) Ports(machineId string) ([]network.PortRange, error) {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	ranges, err := inst.e.portsInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// OpenIngressRules is specified in the instance.IngressRuleFirewaller
+// interface.
+func (inst *ec2Instance) OpenIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
 	legacyName := inst.e.legacyMachineGroupName(machineId)
-	if err := inst.e.openPortsInGroup(name, legacyName, ports); err != nil {
+	if err := inst.e.openPortsInGroup(name, legacyName, rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in security group %s: %v", name, ports)
+	logger.Infof("opened ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *ec2Instance) ClosePorts(machineId string, ports []network.PortRange) error {
+// CloseIngressRules is specified in the instance.IngressRuleFirewaller
+// interface.
+func (inst *ec2Instance) CloseIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
 	legacyName := inst.e.legacyMachineGroupName(machineId)
-	if err := inst.e.closePortsInGroup(name, legacyName, ports); err != nil {
+	if err := inst.e.closePortsInGroup(name, legacyName, rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in security group %s: %v", name, ports)
+	logger.Infof("closed ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *ec2Instance) Ports(machineId string) ([]network.PortRange, error) {
+// IngressRules is specified in the instance.IngressRuleFirewaller
+// interface.
+func (inst *ec2Instance) IngressRules(machineId string) ([]network.IngressRule, error) {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	ranges, err := inst.e.portsInGroup(name)
-	if err != nil {
-		return nil, err
-	}
-	return ranges, nil
+	return inst.e.rulesInGroup(name)
 }
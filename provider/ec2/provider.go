@@ -33,6 +33,12 @@ func (p environProvider) RestrictedConfigAttributes() []string {
 
 // PrepareForCreateEnvironment is specified in the EnvironProvider interface.
 func (p environProvider) PrepareForCreateEnvironment(cfg *config.Config) (*config.Config, error) {
+	if cfg.PreferIPv6() {
+		// The EC2 API version used here predates IPv6 support, so
+		// instances only ever have IPv4 addresses. The setting is
+		// immutable, so existing models that have it are unaffected.
+		return nil, errors.NotSupportedf("prefer-ipv6 on ec2")
+	}
 	attrs := cfg.UnknownAttrs()
 	if _, ok := attrs["control-bucket"]; !ok {
 		uuid, err := utils.NewUUID()
@@ -30,6 +30,10 @@ type gceConnection interface {
 	OpenPorts(fwname string, ports ...network.PortRange) error
 	ClosePorts(fwname string, ports ...network.PortRange) error
 
+	IngressRules(fwname string) ([]network.IngressRule, error)
+	OpenIngressRules(fwname string, rules ...network.IngressRule) error
+	CloseIngressRules(fwname string, rules ...network.IngressRule) error
+
 	AvailabilityZones(region string) ([]google.AvailabilityZone, error)
 
 	// Storage related methods.
@@ -52,6 +56,17 @@ type gceConnection interface {
 	DetachDisk(zone, instanceId, volumeName string) error
 	// InstanceDisks returns a list of the disks attached to the passed instance.
 	InstanceDisks(zone, instanceId string) ([]*google.AttachedDisk, error)
+
+	// Load balancer related methods.
+
+	// EnsureLoadBalancer creates or updates the named load balancer so
+	// that it forwards <ports> to <instances>, and returns its address.
+	EnsureLoadBalancer(name string, ports []network.PortRange, instances []google.InstanceSummary) (string, error)
+	// RemoveLoadBalancer removes the named load balancer, if it exists.
+	RemoveLoadBalancer(name string) error
+	// LoadBalancerNames returns the names of the load balancers in
+	// the connection's region.
+	LoadBalancerNames() ([]string, error)
 }
 
 type environ struct {
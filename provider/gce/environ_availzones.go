@@ -13,13 +13,13 @@ import (
 )
 
 // AvailabilityZones returns all availability zones in the environment.
-func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (env *environ) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	zones, err := env.gce.AvailabilityZones(env.ecfg.region())
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	var result []common.AvailabilityZone
+	var result []instance.AvailabilityZone
 	for _, zone := range zones {
 		if zone.Deprecated() {
 			continue
@@ -0,0 +1,57 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/gce/google"
+)
+
+var _ environs.LoadBalancerManager = (*environ)(nil)
+
+// EnsureLoadBalancer is specified in the environs.LoadBalancerManager
+// interface. GCE network load balancers pass the client's address
+// through to the instances, so the source CIDRs are enforced by the
+// instances' firewall rules rather than by the load balancer.
+func (env *environ) EnsureLoadBalancer(
+	name string,
+	ports []network.PortRange,
+	sourceCIDRs []string,
+	instanceIds []instance.Id,
+) (network.Address, error) {
+	instances, err := env.Instances(instanceIds)
+	if err != nil && err != environs.ErrPartialInstances && err != environs.ErrNoInstances {
+		return network.Address{}, errors.Trace(err)
+	}
+	var members []google.InstanceSummary
+	for _, inst := range instances {
+		if inst == nil {
+			continue
+		}
+		members = append(members, inst.(*environInstance).base.InstanceSummary)
+	}
+	address, err := env.gce.EnsureLoadBalancer(name, ports, members)
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	return network.NewScopedAddress(address, network.ScopePublic), nil
+}
+
+// RemoveLoadBalancer is specified in the environs.LoadBalancerManager
+// interface.
+func (env *environ) RemoveLoadBalancer(name string) error {
+	return errors.Trace(env.gce.RemoveLoadBalancer(name))
+}
+
+// LoadBalancerNames is specified in the environs.LoadBalancerManager
+// interface. The names of the load balancers of every model in the
+// environment's region are returned.
+func (env *environ) LoadBalancerNames() ([]string, error) {
+	names, err := env.gce.LoadBalancerNames()
+	return names, errors.Trace(err)
+}
@@ -0,0 +1,55 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
+)
+
+type environLoadBalancerSuite struct {
+	gce.BaseSuite
+}
+
+var _ = gc.Suite(&environLoadBalancerSuite{})
+
+func (s *environLoadBalancerSuite) TestEnsureLoadBalancer(c *gc.C) {
+	s.FakeEnviron.Insts = []instance.Instance{s.Instance}
+	s.FakeConn.LoadBalancerAddress = "1.2.3.4"
+
+	ids := []instance.Id{s.Instance.Id(), "eggs"}
+	address, err := s.Env.EnsureLoadBalancer("lb", s.Ports, []string{"10.0.0.0/8"}, ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(address, jc.DeepEquals, network.NewScopedAddress("1.2.3.4", network.ScopePublic))
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "EnsureLoadBalancer")
+	c.Check(s.FakeConn.Calls[0].LoadBalancerName, gc.Equals, "lb")
+	c.Check(s.FakeConn.Calls[0].PortRanges, jc.DeepEquals, s.Ports)
+	c.Check(s.FakeConn.Calls[0].Members, jc.DeepEquals, []google.InstanceSummary{
+		s.BaseInstance.InstanceSummary,
+	})
+}
+
+func (s *environLoadBalancerSuite) TestRemoveLoadBalancer(c *gc.C) {
+	err := s.Env.RemoveLoadBalancer("lb")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RemoveLoadBalancer")
+	c.Check(s.FakeConn.Calls[0].LoadBalancerName, gc.Equals, "lb")
+}
+
+func (s *environLoadBalancerSuite) TestLoadBalancerNames(c *gc.C) {
+	s.FakeConn.LoadBalancers = []string{"lb-a", "lb-b"}
+
+	names, err := s.Env.LoadBalancerNames()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(names, jc.DeepEquals, []string{"lb-a", "lb-b"})
+}
@@ -35,6 +35,10 @@ type rawConnectionWrapper interface {
 	// the named firewall and returns it. If the firewall is not found,
 	// errors.NotFound is returned.
 	GetFirewall(projectID, name string) (*compute.Firewall, error)
+	// ListFirewalls sends a request to the GCE API for a list of all
+	// firewalls in the project for which the name starts with the
+	// provided prefix.
+	ListFirewalls(projectID, prefix string) ([]*compute.Firewall, error)
 	// AddFirewall requests GCE to add a firewall with the provided info.
 	// If the firewall already exists then an error will be returned.
 	// The call blocks until the firewall is added or the request fails.
@@ -70,6 +74,55 @@ type rawConnectionWrapper interface {
 	// InstanceDisks returns the disks attached to the instance identified
 	// by instanceId
 	InstanceDisks(project, zone, instanceId string) ([]*compute.AttachedDisk, error)
+	// GetTargetPool sends a request to the GCE API for info about the
+	// named target pool in the region. If the target pool does not
+	// exist, errors.NotFound is returned.
+	GetTargetPool(projectID, region, name string) (*compute.TargetPool, error)
+	// ListTargetPools sends a request to the GCE API for a list of all
+	// target pools in the region for which the name starts with the
+	// provided prefix.
+	ListTargetPools(projectID, region, prefix string) ([]*compute.TargetPool, error)
+	// AddTargetPool requests GCE to add a target pool with the provided
+	// info. The call blocks until the target pool is added or the
+	// request fails.
+	AddTargetPool(projectID, region string, pool *compute.TargetPool) error
+	// RemoveTargetPool removes the named target pool from the region.
+	// If it does not exist, errors.NotFound is returned. The call
+	// blocks until the target pool is removed or the request fails.
+	RemoveTargetPool(projectID, region, name string) error
+	// AddTargetPoolInstances adds the instances with the given URLs
+	// to the named target pool. The call blocks until the instances
+	// are added or the request fails.
+	AddTargetPoolInstances(projectID, region, name string, instanceURLs []string) error
+	// RemoveTargetPoolInstances removes the instances with the given
+	// URLs from the named target pool. The call blocks until the
+	// instances are removed or the request fails.
+	RemoveTargetPoolInstances(projectID, region, name string, instanceURLs []string) error
+	// GetAddress sends a request to the GCE API for info about the
+	// named static address in the region. If the address does not
+	// exist, errors.NotFound is returned.
+	GetAddress(projectID, region, name string) (*compute.Address, error)
+	// AddAddress requests GCE to reserve a static address with the
+	// provided info. The call blocks until the address is reserved or
+	// the request fails.
+	AddAddress(projectID, region string, address *compute.Address) error
+	// RemoveAddress releases the named static address. If it does not
+	// exist, errors.NotFound is returned. The call blocks until the
+	// address is released or the request fails.
+	RemoveAddress(projectID, region, name string) error
+	// ListForwardingRules sends a request to the GCE API for a list of
+	// all forwarding rules in the region for which the name starts
+	// with the provided prefix.
+	ListForwardingRules(projectID, region, prefix string) ([]*compute.ForwardingRule, error)
+	// AddForwardingRule requests GCE to add a forwarding rule with the
+	// provided info. The call blocks until the forwarding rule is added
+	// or the request fails.
+	AddForwardingRule(projectID, region string, rule *compute.ForwardingRule) error
+	// RemoveForwardingRule removes the named forwarding rule from the
+	// region. If it does not exist, errors.NotFound is returned. The
+	// call blocks until the forwarding rule is removed or the request
+	// fails.
+	RemoveForwardingRule(projectID, region, name string) error
 }
 
 // TODO(ericsnow) Add specific error types for common failures
@@ -0,0 +1,227 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"google.golang.org/api/compute/v1"
+
+	"github.com/juju/juju/network"
+)
+
+const (
+	instanceURLBase   = "https://www.googleapis.com/compute/v1/projects/%s/zones/%s/instances/%s"
+	targetPoolURLBase = "https://www.googleapis.com/compute/v1/projects/%s/regions/%s/targetPools/%s"
+
+	// loadBalancerPrefix is the prefix of the names of the GCE
+	// resources that make up a load balancer.
+	loadBalancerPrefix = "juju-lb-"
+)
+
+// loadBalancerResourceName returns the name shared by the target pool
+// and static address of the named load balancer. Load balancer names
+// may be longer than GCE allows, so the resources are named after a
+// hash of the name, which is kept in their descriptions instead.
+func loadBalancerResourceName(name string) string {
+	hash := sha1.Sum([]byte(name))
+	return fmt.Sprintf("%s%x", loadBalancerPrefix, hash[:8])
+}
+
+// forwardingRuleName returns the name of the forwarding rule that
+// forwards the given port range for the load balancer with the given
+// resource name.
+func forwardingRuleName(resourceName string, portRange network.PortRange) string {
+	return fmt.Sprintf("%s-%s-%d-%d", resourceName, strings.ToLower(portRange.Protocol), portRange.FromPort, portRange.ToPort)
+}
+
+// EnsureLoadBalancer creates the named network load balancer, or
+// updates it if it already exists, so that it forwards the provided
+// port ranges to the given instances, and returns its address. The
+// load balancer is made up of a target pool holding the instances, a
+// static address, and a forwarding rule per port range from the
+// address to the target pool. Port ranges for protocols that GCE
+// cannot forward (such as ICMP) are ignored.
+//
+// GCE network load balancers pass the client's address through to the
+// instances, so the instances' firewall rules still decide which
+// clients may connect.
+func (gce Connection) EnsureLoadBalancer(name string, ports []network.PortRange, instances []InstanceSummary) (string, error) {
+	resourceName := loadBalancerResourceName(name)
+	var instanceURLs []string
+	for _, inst := range instances {
+		url := fmt.Sprintf(instanceURLBase, gce.projectID, inst.ZoneName, inst.ID)
+		instanceURLs = append(instanceURLs, url)
+	}
+	if err := gce.ensureTargetPool(name, resourceName, instanceURLs); err != nil {
+		return "", errors.Annotatef(err, "updating load balancer %q", name)
+	}
+	address, err := gce.ensureLoadBalancerAddress(name, resourceName)
+	if err != nil {
+		return "", errors.Annotatef(err, "updating load balancer %q", name)
+	}
+	if err := gce.ensureForwardingRules(name, resourceName, address, ports); err != nil {
+		return "", errors.Annotatef(err, "updating load balancer %q", name)
+	}
+	return address, nil
+}
+
+// ensureTargetPool creates the target pool with the given resource
+// name, or updates its instances if it already exists.
+func (gce Connection) ensureTargetPool(name, resourceName string, instanceURLs []string) error {
+	pool, err := gce.raw.GetTargetPool(gce.projectID, gce.region, resourceName)
+	if errors.IsNotFound(err) {
+		pool = &compute.TargetPool{
+			Name:        resourceName,
+			Description: name,
+			Instances:   instanceURLs,
+		}
+		return errors.Trace(gce.raw.AddTargetPool(gce.projectID, gce.region, pool))
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	current := make(map[string]bool)
+	for _, url := range pool.Instances {
+		current[url] = true
+	}
+	var add []string
+	for _, url := range instanceURLs {
+		if !current[url] {
+			add = append(add, url)
+		}
+		delete(current, url)
+	}
+	var remove []string
+	for url := range current {
+		remove = append(remove, url)
+	}
+	sort.Strings(remove)
+	if len(add) > 0 {
+		if err := gce.raw.AddTargetPoolInstances(gce.projectID, gce.region, resourceName, add); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(remove) > 0 {
+		if err := gce.raw.RemoveTargetPoolInstances(gce.projectID, gce.region, resourceName, remove); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// ensureLoadBalancerAddress reserves the static address with the given
+// resource name, if it is not already reserved, and returns it.
+func (gce Connection) ensureLoadBalancerAddress(name, resourceName string) (string, error) {
+	address, err := gce.raw.GetAddress(gce.projectID, gce.region, resourceName)
+	if errors.IsNotFound(err) {
+		address = &compute.Address{
+			Name:        resourceName,
+			Description: name,
+		}
+		if err := gce.raw.AddAddress(gce.projectID, gce.region, address); err != nil {
+			return "", errors.Trace(err)
+		}
+		address, err = gce.raw.GetAddress(gce.projectID, gce.region, resourceName)
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return address.Address, nil
+}
+
+// ensureForwardingRules adds a forwarding rule from the given address
+// to the target pool with the given resource name for each of the
+// provided port ranges that does not have one, and removes the
+// forwarding rules of port ranges that are no longer wanted.
+func (gce Connection) ensureForwardingRules(name, resourceName, address string, ports []network.PortRange) error {
+	want := make(map[string]network.PortRange)
+	for _, portRange := range ports {
+		switch strings.ToLower(portRange.Protocol) {
+		case "tcp", "udp":
+			want[forwardingRuleName(resourceName, portRange)] = portRange
+		}
+	}
+	rules, err := gce.raw.ListForwardingRules(gce.projectID, gce.region, resourceName+"-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, rule := range rules {
+		if _, ok := want[rule.Name]; ok {
+			delete(want, rule.Name)
+			continue
+		}
+		if err := gce.raw.RemoveForwardingRule(gce.projectID, gce.region, rule.Name); err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	var ruleNames []string
+	for ruleName := range want {
+		ruleNames = append(ruleNames, ruleName)
+	}
+	sort.Strings(ruleNames)
+	target := fmt.Sprintf(targetPoolURLBase, gce.projectID, gce.region, resourceName)
+	for _, ruleName := range ruleNames {
+		portRange := want[ruleName]
+		rule := &compute.ForwardingRule{
+			Name:        ruleName,
+			Description: name,
+			IPAddress:   address,
+			IPProtocol:  strings.ToUpper(portRange.Protocol),
+			PortRange:   fmt.Sprintf("%d-%d", portRange.FromPort, portRange.ToPort),
+			Target:      target,
+		}
+		if err := gce.raw.AddForwardingRule(gce.projectID, gce.region, rule); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// RemoveLoadBalancer removes the forwarding rules, static address and
+// target pool of the named load balancer. It is not an error to remove
+// a load balancer that does not exist.
+func (gce Connection) RemoveLoadBalancer(name string) error {
+	resourceName := loadBalancerResourceName(name)
+	rules, err := gce.raw.ListForwardingRules(gce.projectID, gce.region, resourceName+"-")
+	if err != nil {
+		return errors.Annotatef(err, "removing load balancer %q", name)
+	}
+	for _, rule := range rules {
+		err := gce.raw.RemoveForwardingRule(gce.projectID, gce.region, rule.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Annotatef(err, "removing load balancer %q", name)
+		}
+	}
+	err = gce.raw.RemoveAddress(gce.projectID, gce.region, resourceName)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Annotatef(err, "removing load balancer %q", name)
+	}
+	err = gce.raw.RemoveTargetPool(gce.projectID, gce.region, resourceName)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Annotatef(err, "removing load balancer %q", name)
+	}
+	return nil
+}
+
+// LoadBalancerNames returns the names of the load balancers in the
+// Connection's region, whatever model created them.
+func (gce Connection) LoadBalancerNames() ([]string, error) {
+	pools, err := gce.raw.ListTargetPools(gce.projectID, gce.region, loadBalancerPrefix)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing load balancers")
+	}
+	var names []string
+	for _, pool := range pools {
+		if pool.Description != "" {
+			names = append(names, pool.Description)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
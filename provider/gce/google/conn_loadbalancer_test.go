@@ -0,0 +1,174 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"google.golang.org/api/compute/v1"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/gce/google"
+)
+
+const (
+	instanceURL0 = "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone/instances/inst-0"
+	instanceURL1 = "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone/instances/inst-1"
+	instanceURL2 = "https://www.googleapis.com/compute/v1/projects/spam/zones/b-zone/instances/inst-2"
+)
+
+var lbInstances = []google.InstanceSummary{
+	{ID: "inst-1", ZoneName: "a-zone"},
+	{ID: "inst-2", ZoneName: "b-zone"},
+}
+
+func (s *connSuite) TestConnectionEnsureLoadBalancerCreate(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam")
+	s.FakeConn.Address = &compute.Address{Address: "1.2.3.4"}
+	ports := []network.PortRange{
+		{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		{FromPort: 53, ToPort: 54, Protocol: "udp"},
+		{FromPort: -1, ToPort: -1, Protocol: "icmp"},
+	}
+
+	address, err := s.Conn.EnsureLoadBalancer("lb", ports, lbInstances)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(address, gc.Equals, "1.2.3.4")
+
+	resourceName := google.LoadBalancerResourceName("lb")
+	target := "https://www.googleapis.com/compute/v1/projects/spam/regions/a/targetPools/" + resourceName
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 6)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetTargetPool")
+	c.Check(s.FakeConn.Calls[0].Region, gc.Equals, "a")
+	c.Check(s.FakeConn.Calls[0].Name, gc.Equals, resourceName)
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddTargetPool")
+	c.Check(s.FakeConn.Calls[1].TargetPool, jc.DeepEquals, &compute.TargetPool{
+		Name:        resourceName,
+		Description: "lb",
+		Instances:   []string{instanceURL1, instanceURL2},
+	})
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "GetAddress")
+	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "ListForwardingRules")
+	c.Check(s.FakeConn.Calls[3].Prefix, gc.Equals, resourceName+"-")
+	c.Check(s.FakeConn.Calls[4].FuncName, gc.Equals, "AddForwardingRule")
+	c.Check(s.FakeConn.Calls[4].ForwardingRule, jc.DeepEquals, &compute.ForwardingRule{
+		Name:        resourceName + "-tcp-80-80",
+		Description: "lb",
+		IPAddress:   "1.2.3.4",
+		IPProtocol:  "TCP",
+		PortRange:   "80-80",
+		Target:      target,
+	})
+	c.Check(s.FakeConn.Calls[5].FuncName, gc.Equals, "AddForwardingRule")
+	c.Check(s.FakeConn.Calls[5].ForwardingRule, jc.DeepEquals, &compute.ForwardingRule{
+		Name:        resourceName + "-udp-53-54",
+		Description: "lb",
+		IPAddress:   "1.2.3.4",
+		IPProtocol:  "UDP",
+		PortRange:   "53-54",
+		Target:      target,
+	})
+}
+
+func (s *connSuite) TestConnectionEnsureLoadBalancerReservesAddress(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam")
+	s.FakeConn.FailOnCall = 1
+	s.FakeConn.TargetPool = &compute.TargetPool{
+		Instances: []string{instanceURL1, instanceURL2},
+	}
+	s.FakeConn.Address = &compute.Address{Address: "1.2.3.4"}
+
+	address, err := s.Conn.EnsureLoadBalancer("lb", nil, lbInstances)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(address, gc.Equals, "1.2.3.4")
+
+	resourceName := google.LoadBalancerResourceName("lb")
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 5)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetTargetPool")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "GetAddress")
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "AddAddress")
+	c.Check(s.FakeConn.Calls[2].Address, jc.DeepEquals, &compute.Address{
+		Name:        resourceName,
+		Description: "lb",
+	})
+	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "GetAddress")
+	c.Check(s.FakeConn.Calls[4].FuncName, gc.Equals, "ListForwardingRules")
+}
+
+func (s *connSuite) TestConnectionEnsureLoadBalancerUpdate(c *gc.C) {
+	resourceName := google.LoadBalancerResourceName("lb")
+	s.FakeConn.TargetPool = &compute.TargetPool{
+		Name:      resourceName,
+		Instances: []string{instanceURL0, instanceURL1},
+	}
+	s.FakeConn.Address = &compute.Address{Address: "1.2.3.4"}
+	s.FakeConn.ForwardingRules = []*compute.ForwardingRule{
+		{Name: resourceName + "-tcp-80-80"},
+		{Name: resourceName + "-tcp-443-443"},
+	}
+	ports := []network.PortRange{
+		{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		{FromPort: 8080, ToPort: 8080, Protocol: "tcp"},
+	}
+
+	_, err := s.Conn.EnsureLoadBalancer("lb", ports, lbInstances)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 7)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetTargetPool")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddTargetPoolInstances")
+	c.Check(s.FakeConn.Calls[1].InstanceURLs, jc.DeepEquals, []string{instanceURL2})
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "RemoveTargetPoolInstances")
+	c.Check(s.FakeConn.Calls[2].InstanceURLs, jc.DeepEquals, []string{instanceURL0})
+	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "GetAddress")
+	c.Check(s.FakeConn.Calls[4].FuncName, gc.Equals, "ListForwardingRules")
+	c.Check(s.FakeConn.Calls[5].FuncName, gc.Equals, "RemoveForwardingRule")
+	c.Check(s.FakeConn.Calls[5].Name, gc.Equals, resourceName+"-tcp-443-443")
+	c.Check(s.FakeConn.Calls[6].FuncName, gc.Equals, "AddForwardingRule")
+	c.Check(s.FakeConn.Calls[6].ForwardingRule.Name, gc.Equals, resourceName+"-tcp-8080-8080")
+}
+
+func (s *connSuite) TestConnectionRemoveLoadBalancer(c *gc.C) {
+	resourceName := google.LoadBalancerResourceName("lb")
+	s.FakeConn.ForwardingRules = []*compute.ForwardingRule{
+		{Name: resourceName + "-tcp-80-80"},
+	}
+
+	err := s.Conn.RemoveLoadBalancer("lb")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 4)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListForwardingRules")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveForwardingRule")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, resourceName+"-tcp-80-80")
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "RemoveAddress")
+	c.Check(s.FakeConn.Calls[2].Name, gc.Equals, resourceName)
+	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "RemoveTargetPool")
+	c.Check(s.FakeConn.Calls[3].Name, gc.Equals, resourceName)
+}
+
+func (s *connSuite) TestConnectionRemoveLoadBalancerNotFound(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam")
+	s.FakeConn.FailOnCall = 1
+
+	err := s.Conn.RemoveLoadBalancer("lb")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *connSuite) TestConnectionLoadBalancerNames(c *gc.C) {
+	s.FakeConn.TargetPools = []*compute.TargetPool{
+		{Name: "juju-lb-2", Description: "lb-b"},
+		{Name: "juju-lb-1", Description: "lb-a"},
+	}
+
+	names, err := s.Conn.LoadBalancerNames()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(names, jc.DeepEquals, []string{"lb-a", "lb-b"})
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListTargetPools")
+	c.Check(s.FakeConn.Calls[0].Region, gc.Equals, "a")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, "juju-lb-")
+}
@@ -4,7 +4,14 @@
 package google
 
 import (
+	"crypto/sha1"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
 	"github.com/juju/errors"
+	"google.golang.org/api/compute/v1"
 
 	"github.com/juju/juju/network"
 )
@@ -20,7 +27,11 @@ func (gce Connection) Ports(fwname string) ([]network.PortRange, error) {
 	if err != nil {
 		return nil, errors.Annotate(err, "while getting ports from GCE")
 	}
+	return firewallPorts(firewall)
+}
 
+// firewallPorts returns the port ranges opened by the given firewall.
+func firewallPorts(firewall *compute.Firewall) ([]network.PortRange, error) {
 	var ports []network.PortRange
 	for _, allowed := range firewall.Allowed {
 		for _, portRangeStr := range allowed.Ports {
@@ -43,6 +54,13 @@ func (gce Connection) Ports(fwname string) ([]network.PortRange, error) {
 // ports it already has open. The call blocks until the ports are
 // opened or the request fails.
 func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error {
+	return gce.openPorts(fwname, fwname, nil, ports)
+}
+
+// openPorts opens the provided port ranges on the named firewall,
+// which applies to instances tagged with target and admits traffic
+// from the given source ranges (or from anywhere, if there are none).
+func (gce Connection) openPorts(fwname, target string, sourceRanges []string, ports []network.PortRange) error {
 	// TODO(ericsnow) Short-circuit if ports is empty.
 
 	// Compose the full set of open ports.
@@ -59,7 +77,7 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 	// Send the request, depending on the current ports.
 	if currentPortsSet.IsEmpty() {
 		// Create a new firewall.
-		firewall := firewallSpec(fwname, inputPortsSet)
+		firewall := ruleFirewallSpec(fwname, target, sourceRanges, inputPortsSet)
 		if err := gce.raw.AddFirewall(gce.projectID, firewall); err != nil {
 			return errors.Annotatef(err, "opening port(s) %+v", ports)
 		}
@@ -68,7 +86,7 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 
 	// Update an existing firewall.
 	newPortsSet := currentPortsSet.Union(inputPortsSet)
-	firewall := firewallSpec(fwname, newPortsSet)
+	firewall := ruleFirewallSpec(fwname, target, sourceRanges, newPortsSet)
 	if err := gce.raw.UpdateFirewall(gce.projectID, fwname, firewall); err != nil {
 		return errors.Annotatef(err, "opening port(s) %+v", ports)
 	}
@@ -82,6 +100,13 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 // match the provided port ranges. The call blocks until the ports are
 // closed or the request fails.
 func (gce Connection) ClosePorts(fwname string, ports ...network.PortRange) error {
+	return gce.closePorts(fwname, fwname, nil, ports)
+}
+
+// closePorts closes the provided port ranges on the named firewall.
+// The target and source ranges are used when the firewall is updated
+// to leave the remaining ports open; see openPorts.
+func (gce Connection) closePorts(fwname, target string, sourceRanges []string, ports []network.PortRange) error {
 	// Compose the full set of open ports.
 	currentPorts, err := gce.Ports(fwname)
 	if err != nil {
@@ -105,9 +130,128 @@ func (gce Connection) ClosePorts(fwname string, ports ...network.PortRange) erro
 	}
 
 	// Update an existing firewall.
-	firewall := firewallSpec(fwname, newPortsSet)
+	firewall := ruleFirewallSpec(fwname, target, sourceRanges, newPortsSet)
 	if err := gce.raw.UpdateFirewall(gce.projectID, fwname, firewall); err != nil {
 		return errors.Annotatef(err, "closing port(s) %+v", ports)
 	}
 	return nil
 }
+
+// restrictedFirewallPattern matches the names of the firewalls used to
+// hold ingress rules restricted to particular source CIDRs; see
+// restrictedFirewallName.
+var restrictedFirewallPattern = regexp.MustCompile(`^-[0-9a-f]{8}$`)
+
+// restrictedFirewallName returns the name of the firewall that holds
+// the ingress rules for the named firewall that are restricted to the
+// given source CIDRs. GCE firewalls have a single set of source ranges,
+// so each distinct set of CIDRs needs a firewall of its own.
+func restrictedFirewallName(fwname string, cidrs []string) string {
+	hash := sha1.Sum([]byte(strings.Join(cidrs, ",")))
+	return fmt.Sprintf("%s-%x", fwname, hash[:4])
+}
+
+// IngressRules returns the ingress rules opened for the named firewall
+// (within the Connection's project). Rules open to any source address
+// are held by the named firewall itself, while rules restricted to
+// particular source CIDRs are held by additional firewalls that target
+// the same instances.
+func (gce Connection) IngressRules(fwname string) ([]network.IngressRule, error) {
+	ports, err := gce.Ports(fwname)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rules := network.IngressRulesFromPortRanges(ports)
+
+	firewalls, err := gce.raw.ListFirewalls(gce.projectID, fwname+"-")
+	if err != nil {
+		return nil, errors.Annotate(err, "while getting ingress rules from GCE")
+	}
+	for _, firewall := range firewalls {
+		suffix := strings.TrimPrefix(firewall.Name, fwname)
+		if !restrictedFirewallPattern.MatchString(suffix) {
+			continue
+		}
+		ports, err := firewallPorts(firewall)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, portRange := range ports {
+			rule, err := network.NewIngressRule(portRange, firewall.SourceRanges...)
+			if err != nil {
+				return nil, errors.Annotate(err, "bad ingress rule from GCE")
+			}
+			rules = append(rules, rule)
+		}
+	}
+	network.SortIngressRules(rules)
+	return rules, nil
+}
+
+// OpenIngressRules sends requests to the GCE API to open the provided
+// ingress rules for the named firewall. Rules without source CIDRs are
+// opened on the named firewall, as with OpenPorts; the others are
+// opened on a firewall per distinct set of source CIDRs.
+func (gce Connection) OpenIngressRules(fwname string, rules ...network.IngressRule) error {
+	for _, group := range groupIngressRules(rules) {
+		if len(group.cidrs) == 0 {
+			if err := gce.OpenPorts(fwname, group.ports...); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+		name := restrictedFirewallName(fwname, group.cidrs)
+		if err := gce.openPorts(name, fwname, group.cidrs, group.ports); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// CloseIngressRules sends requests to the GCE API to close the provided
+// ingress rules for the named firewall. See OpenIngressRules.
+func (gce Connection) CloseIngressRules(fwname string, rules ...network.IngressRule) error {
+	for _, group := range groupIngressRules(rules) {
+		if len(group.cidrs) == 0 {
+			if err := gce.ClosePorts(fwname, group.ports...); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+		name := restrictedFirewallName(fwname, group.cidrs)
+		if err := gce.closePorts(name, fwname, group.cidrs, group.ports); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// ingressRuleGroup holds the port ranges of ingress rules that share
+// the same source CIDRs.
+type ingressRuleGroup struct {
+	cidrs []string
+	ports []network.PortRange
+}
+
+// groupIngressRules groups the provided ingress rules by source CIDRs,
+// in a stable order.
+func groupIngressRules(rules []network.IngressRule) []ingressRuleGroup {
+	groups := make(map[string]*ingressRuleGroup)
+	var keys []string
+	for _, rule := range rules {
+		key := strings.Join(rule.SourceCIDRs, ",")
+		group, ok := groups[key]
+		if !ok {
+			group = &ingressRuleGroup{cidrs: rule.SourceCIDRs}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.ports = append(group.ports, rule.PortRange)
+	}
+	sort.Strings(keys)
+	result := make([]ingressRuleGroup, len(keys))
+	for i, key := range keys {
+		result[i] = *groups[key]
+	}
+	return result
+}
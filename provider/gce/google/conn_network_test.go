@@ -173,3 +173,60 @@ func (s *connSuite) TestConnectionClosePortsUpdate(c *gc.C) {
 		}},
 	})
 }
+
+func (s *connSuite) TestConnectionIngressRules(c *gc.C) {
+	s.FakeConn.Firewall = &compute.Firewall{
+		Name:         "spam",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80"},
+		}},
+	}
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam-0123abcd",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"443"},
+		}},
+	}, {
+		Name:         "spam-other",
+		TargetTags:   []string{"spam-other"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"22"},
+		}},
+	}}
+
+	rules, err := s.Conn.IngressRules("spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"}),
+		network.MustNewIngressRule(network.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"}, "10.0.0.0/8"),
+	})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "ListFirewalls")
+	c.Check(s.FakeConn.Calls[1].Prefix, gc.Equals, "spam-")
+}
+
+func (s *connSuite) TestConnectionOpenIngressRulesRestricted(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam")
+
+	rule := network.MustNewIngressRule(network.PortRange{FromPort: 80, ToPort: 81, Protocol: "tcp"}, "10.0.0.0/8")
+	err := s.Conn.OpenIngressRules("spam", rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewall")
+	c.Check(s.FakeConn.Calls[0].Name, gc.Matches, "spam-[0-9a-f]{8}")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	firewall := s.FakeConn.Calls[1].Firewall
+	c.Check(firewall.Name, gc.Equals, s.FakeConn.Calls[0].Name)
+	c.Check(firewall.TargetTags, jc.DeepEquals, []string{"spam"})
+	c.Check(firewall.SourceRanges, jc.DeepEquals, []string{"10.0.0.0/8"})
+}
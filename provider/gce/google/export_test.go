@@ -16,6 +16,8 @@ var (
 	FormatMachineType = formatMachineType
 	FirewallSpec      = firewallSpec
 	ExtractAddresses  = extractAddresses
+
+	LoadBalancerResourceName = loadBalancerResourceName
 )
 
 func SetRawConn(conn *Connection, raw rawConnectionWrapper) {
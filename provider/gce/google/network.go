@@ -58,14 +58,25 @@ func (ns *NetworkSpec) newInterface(name string) *compute.NetworkInterface {
 // firewallSpec expands a port range set in to compute.FirewallAllowed
 // and returns a compute.Firewall for the provided name.
 func firewallSpec(name string, ps network.PortSet) *compute.Firewall {
+	return ruleFirewallSpec(name, name, nil, ps)
+}
+
+// ruleFirewallSpec returns a compute.Firewall for the provided name
+// that opens the port range set to traffic from the given source ranges
+// (or from anywhere, if there are none) for instances tagged with
+// target.
+func ruleFirewallSpec(name, target string, sourceRanges []string, ps network.PortSet) *compute.Firewall {
+	if len(sourceRanges) == 0 {
+		sourceRanges = []string{network.AnyIPv4CIDR}
+	}
 	firewall := compute.Firewall{
 		// Allowed is set below.
 		// Description is not set.
 		Name: name,
 		// Network: (defaults to global)
 		// SourceTags is not set.
-		TargetTags:   []string{name},
-		SourceRanges: []string{"0.0.0.0/0"},
+		TargetTags:   []string{target},
+		SourceRanges: sourceRanges,
 	}
 
 	for _, protocol := range ps.Protocols() {
@@ -133,6 +133,16 @@ func (rc *rawConn) GetFirewall(projectID, name string) (*compute.Firewall, error
 	return firewallList.Items[0], nil
 }
 
+func (rc *rawConn) ListFirewalls(projectID, prefix string) ([]*compute.Firewall, error) {
+	call := rc.Firewalls.List(projectID)
+	call = call.Filter("name eq " + prefix + ".*")
+	firewallList, err := call.Do()
+	if err != nil {
+		return nil, errors.Annotate(err, "while listing firewalls from GCE")
+	}
+	return firewallList.Items, nil
+}
+
 func (rc *rawConn) AddFirewall(projectID string, firewall *compute.Firewall) error {
 	call := rc.Firewalls.Insert(projectID, firewall)
 	operation, err := call.Do()
@@ -279,6 +289,158 @@ func (rc *rawConn) InstanceDisks(project, zone, instanceId string) ([]*compute.A
 	return instance.Disks, nil
 }
 
+func (rc *rawConn) GetTargetPool(projectID, region, name string) (*compute.TargetPool, error) {
+	call := rc.TargetPools.Get(projectID, region, name)
+	pool, err := call.Do()
+	return pool, errors.Trace(convertRawAPIError(err))
+}
+
+func (rc *rawConn) ListTargetPools(projectID, region, prefix string) ([]*compute.TargetPool, error) {
+	call := rc.TargetPools.List(projectID, region)
+	call = call.Filter("name eq " + prefix + ".*")
+
+	var results []*compute.TargetPool
+	for {
+		poolList, err := call.Do()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		results = append(results, poolList.Items...)
+		if poolList.NextPageToken == "" {
+			break
+		}
+		call = call.PageToken(poolList.NextPageToken)
+	}
+	return results, nil
+}
+
+func (rc *rawConn) AddTargetPool(projectID, region string, pool *compute.TargetPool) error {
+	call := rc.TargetPools.Insert(projectID, region, pool)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) RemoveTargetPool(projectID, region, name string) error {
+	call := rc.TargetPools.Delete(projectID, region, name)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(convertRawAPIError(err))
+}
+
+func instanceReferences(instanceURLs []string) []*compute.InstanceReference {
+	refs := make([]*compute.InstanceReference, len(instanceURLs))
+	for i, url := range instanceURLs {
+		refs[i] = &compute.InstanceReference{Instance: url}
+	}
+	return refs
+}
+
+func (rc *rawConn) AddTargetPoolInstances(projectID, region, name string, instanceURLs []string) error {
+	req := &compute.TargetPoolsAddInstanceRequest{
+		Instances: instanceReferences(instanceURLs),
+	}
+	call := rc.TargetPools.AddInstance(projectID, region, name, req)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) RemoveTargetPoolInstances(projectID, region, name string, instanceURLs []string) error {
+	req := &compute.TargetPoolsRemoveInstanceRequest{
+		Instances: instanceReferences(instanceURLs),
+	}
+	call := rc.TargetPools.RemoveInstance(projectID, region, name, req)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) GetAddress(projectID, region, name string) (*compute.Address, error) {
+	call := rc.Addresses.Get(projectID, region, name)
+	address, err := call.Do()
+	return address, errors.Trace(convertRawAPIError(err))
+}
+
+func (rc *rawConn) AddAddress(projectID, region string, address *compute.Address) error {
+	call := rc.Addresses.Insert(projectID, region, address)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) RemoveAddress(projectID, region, name string) error {
+	call := rc.Addresses.Delete(projectID, region, name)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(convertRawAPIError(err))
+}
+
+func (rc *rawConn) ListForwardingRules(projectID, region, prefix string) ([]*compute.ForwardingRule, error) {
+	call := rc.ForwardingRules.List(projectID, region)
+	call = call.Filter("name eq " + prefix + ".*")
+
+	var results []*compute.ForwardingRule
+	for {
+		ruleList, err := call.Do()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		results = append(results, ruleList.Items...)
+		if ruleList.NextPageToken == "" {
+			break
+		}
+		call = call.PageToken(ruleList.NextPageToken)
+	}
+	return results, nil
+}
+
+func (rc *rawConn) AddForwardingRule(projectID, region string, rule *compute.ForwardingRule) error {
+	call := rc.ForwardingRules.Insert(projectID, region, rule)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) RemoveForwardingRule(projectID, region, name string) error {
+	call := rc.ForwardingRules.Delete(projectID, region, name)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(convertRawAPIError(err))
+}
+
 type waitError struct {
 	op    *compute.Operation
 	cause error
@@ -158,6 +158,11 @@ type fakeCall struct {
 	AttachedDisk *compute.AttachedDisk
 	DeviceName   string
 	ComputeDisk  *compute.Disk
+
+	TargetPool     *compute.TargetPool
+	InstanceURLs   []string
+	Address        *compute.Address
+	ForwardingRule *compute.ForwardingRule
 }
 
 type fakeConn struct {
@@ -167,12 +172,18 @@ type fakeConn struct {
 	Instance      *compute.Instance
 	Instances     []*compute.Instance
 	Firewall      *compute.Firewall
+	Firewalls     []*compute.Firewall
 	Zones         []*compute.Zone
 	Err           error
 	FailOnCall    int
 	Disks         []*compute.Disk
 	Disk          *compute.Disk
 	AttachedDisks []*compute.AttachedDisk
+
+	TargetPool      *compute.TargetPool
+	TargetPools     []*compute.TargetPool
+	Address         *compute.Address
+	ForwardingRules []*compute.ForwardingRule
 }
 
 func (rc *fakeConn) GetProject(projectID string) (*compute.Project, error) {
@@ -269,6 +280,21 @@ func (rc *fakeConn) GetFirewall(projectID, name string) (*compute.Firewall, erro
 	return rc.Firewall, err
 }
 
+func (rc *fakeConn) ListFirewalls(projectID, prefix string) ([]*compute.Firewall, error) {
+	call := fakeCall{
+		FuncName:  "ListFirewalls",
+		ProjectID: projectID,
+		Prefix:    prefix,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.Firewalls, err
+}
+
 func (rc *fakeConn) AddFirewall(projectID string, firewall *compute.Firewall) error {
 	call := fakeCall{
 		FuncName:  "AddFirewall",
@@ -442,3 +468,197 @@ func (rc *fakeConn) InstanceDisks(project, zone, instanceId string) ([]*compute.
 	}
 	return rc.AttachedDisks, err
 }
+
+func (rc *fakeConn) GetTargetPool(projectID, region, name string) (*compute.TargetPool, error) {
+	call := fakeCall{
+		FuncName:  "GetTargetPool",
+		ProjectID: projectID,
+		Region:    region,
+		Name:      name,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.TargetPool, err
+}
+
+func (rc *fakeConn) ListTargetPools(projectID, region, prefix string) ([]*compute.TargetPool, error) {
+	call := fakeCall{
+		FuncName:  "ListTargetPools",
+		ProjectID: projectID,
+		Region:    region,
+		Prefix:    prefix,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.TargetPools, err
+}
+
+func (rc *fakeConn) AddTargetPool(projectID, region string, pool *compute.TargetPool) error {
+	call := fakeCall{
+		FuncName:   "AddTargetPool",
+		ProjectID:  projectID,
+		Region:     region,
+		TargetPool: pool,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) RemoveTargetPool(projectID, region, name string) error {
+	call := fakeCall{
+		FuncName:  "RemoveTargetPool",
+		ProjectID: projectID,
+		Region:    region,
+		Name:      name,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) AddTargetPoolInstances(projectID, region, name string, instanceURLs []string) error {
+	call := fakeCall{
+		FuncName:     "AddTargetPoolInstances",
+		ProjectID:    projectID,
+		Region:       region,
+		Name:         name,
+		InstanceURLs: instanceURLs,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) RemoveTargetPoolInstances(projectID, region, name string, instanceURLs []string) error {
+	call := fakeCall{
+		FuncName:     "RemoveTargetPoolInstances",
+		ProjectID:    projectID,
+		Region:       region,
+		Name:         name,
+		InstanceURLs: instanceURLs,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) GetAddress(projectID, region, name string) (*compute.Address, error) {
+	call := fakeCall{
+		FuncName:  "GetAddress",
+		ProjectID: projectID,
+		Region:    region,
+		Name:      name,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.Address, err
+}
+
+func (rc *fakeConn) AddAddress(projectID, region string, address *compute.Address) error {
+	call := fakeCall{
+		FuncName:  "AddAddress",
+		ProjectID: projectID,
+		Region:    region,
+		Address:   address,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) RemoveAddress(projectID, region, name string) error {
+	call := fakeCall{
+		FuncName:  "RemoveAddress",
+		ProjectID: projectID,
+		Region:    region,
+		Name:      name,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) ListForwardingRules(projectID, region, prefix string) ([]*compute.ForwardingRule, error) {
+	call := fakeCall{
+		FuncName:  "ListForwardingRules",
+		ProjectID: projectID,
+		Region:    region,
+		Prefix:    prefix,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.ForwardingRules, err
+}
+
+func (rc *fakeConn) AddForwardingRule(projectID, region string, rule *compute.ForwardingRule) error {
+	call := fakeCall{
+		FuncName:       "AddForwardingRule",
+		ProjectID:      projectID,
+		Region:         region,
+		ForwardingRule: rule,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) RemoveForwardingRule(projectID, region, name string) error {
+	call := fakeCall{
+		FuncName:  "RemoveForwardingRule",
+		ProjectID: projectID,
+		Region:    region,
+		Name:      name,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
@@ -71,6 +71,11 @@ func (p environProvider) PrepareForBootstrap(ctx environs.BootstrapContext, args
 
 // PrepareForCreateEnvironment is specified in the EnvironProvider interface.
 func (p environProvider) PrepareForCreateEnvironment(cfg *config.Config) (*config.Config, error) {
+	if cfg.PreferIPv6() {
+		// GCE instances only have IPv4 addresses. The setting is
+		// immutable, so existing models that have it are unaffected.
+		return nil, errors.NotSupportedf("prefer-ipv6 on gce")
+	}
 	return configWithDefaults(cfg)
 }
 
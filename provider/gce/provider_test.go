@@ -4,6 +4,7 @@
 package gce_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -58,6 +59,15 @@ func (s *providerSuite) TestPrepareForBootstrap(c *gc.C) {
 	c.Check(env, gc.NotNil)
 }
 
+func (s *providerSuite) TestPrepareForCreateEnvironmentPreferIPv6NotSupported(c *gc.C) {
+	cfg, err := s.Config.Apply(map[string]interface{}{"prefer-ipv6": true})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.provider.PrepareForCreateEnvironment(cfg)
+	c.Check(err, gc.ErrorMatches, "prefer-ipv6 on gce not supported")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *providerSuite) TestValidate(c *gc.C) {
 	validCfg, err := s.provider.Validate(s.Config, nil)
 	c.Check(err, jc.ErrorIsNil)
@@ -433,11 +433,15 @@ type fakeConnCall struct {
 	InstanceSpec google.InstanceSpec
 	FirewallName string
 	PortRanges   []network.PortRange
+	Rules        []network.IngressRule
 	Region       string
 	Disks        []google.DiskSpec
 	VolumeName   string
 	InstanceId   string
 	Mode         string
+
+	LoadBalancerName string
+	Members          []google.InstanceSummary
 }
 
 type fakeConn struct {
@@ -446,6 +450,7 @@ type fakeConn struct {
 	Inst       *google.Instance
 	Insts      []google.Instance
 	PortRanges []network.PortRange
+	Rules      []network.IngressRule
 	Zones      []google.AvailabilityZone
 
 	GoogleDisks   []*google.Disk
@@ -453,6 +458,9 @@ type fakeConn struct {
 	AttachedDisk  *google.AttachedDisk
 	AttachedDisks []*google.AttachedDisk
 
+	LoadBalancerAddress string
+	LoadBalancers       []string
+
 	Err        error
 	FailOnCall int
 }
@@ -533,6 +541,32 @@ func (fc *fakeConn) ClosePorts(fwname string, ports ...network.PortRange) error
 	return fc.err()
 }
 
+func (fc *fakeConn) IngressRules(fwname string) ([]network.IngressRule, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "IngressRules",
+		FirewallName: fwname,
+	})
+	return fc.Rules, fc.err()
+}
+
+func (fc *fakeConn) OpenIngressRules(fwname string, rules ...network.IngressRule) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "OpenIngressRules",
+		FirewallName: fwname,
+		Rules:        rules,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) CloseIngressRules(fwname string, rules ...network.IngressRule) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "CloseIngressRules",
+		FirewallName: fwname,
+		Rules:        rules,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) AvailabilityZones(region string) ([]google.AvailabilityZone, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName: "AvailabilityZones",
@@ -606,6 +640,31 @@ func (fc *fakeConn) InstanceDisks(zone, instanceId string) ([]*google.AttachedDi
 	return fc.AttachedDisks, fc.err()
 }
 
+func (fc *fakeConn) EnsureLoadBalancer(name string, ports []network.PortRange, instances []google.InstanceSummary) (string, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:         "EnsureLoadBalancer",
+		LoadBalancerName: name,
+		PortRanges:       ports,
+		Members:          instances,
+	})
+	return fc.LoadBalancerAddress, fc.err()
+}
+
+func (fc *fakeConn) RemoveLoadBalancer(name string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:         "RemoveLoadBalancer",
+		LoadBalancerName: name,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) LoadBalancerNames() ([]string, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "LoadBalancerNames",
+	})
+	return fc.LoadBalancers, fc.err()
+}
+
 func (fc *fakeConn) WasCalled(funcName string) (bool, []fakeConnCall) {
 	var calls []fakeConnCall
 	called := false
@@ -156,7 +156,7 @@ type maasEnviron struct {
 	storageUnlocked    storage.Storage
 
 	availabilityZonesMutex sync.Mutex
-	availabilityZones      []common.AvailabilityZone
+	availabilityZones      []instance.AvailabilityZone
 
 	// The following are initialized from the discovered MAAS API capabilities.
 	supportsDevices                 bool
@@ -484,7 +484,7 @@ func (z maasAvailabilityZone) Available() bool {
 
 // AvailabilityZones returns a slice of availability zones
 // for the configured region.
-func (e *maasEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *maasEnviron) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	e.availabilityZonesMutex.Lock()
 	defer e.availabilityZonesMutex.Unlock()
 	if e.availabilityZones == nil {
@@ -501,7 +501,7 @@ func (e *maasEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
 			return nil, err
 		}
 		logger.Debugf("availability zones: %+v", list)
-		availabilityZones := make([]common.AvailabilityZone, len(list))
+		availabilityZones := make([]instance.AvailabilityZone, len(list))
 		for i, obj := range list {
 			zone, err := obj.GetMap()
 			if err != nil {
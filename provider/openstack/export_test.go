@@ -315,6 +315,10 @@ func SetUpGlobalGroup(e environs.Environ, name string, apiPort int) (nova.Securi
 	return e.(*Environ).firewaller.(*defaultFirewaller).setUpGlobalGroup(name, apiPort)
 }
 
+func SupportsIPv6Rules(e environs.Environ) (bool, error) {
+	return e.(*Environ).supportsIPv6Rules()
+}
+
 func EnsureGroup(e environs.Environ, name string, rules []nova.RuleInfo) (nova.SecurityGroup, error) {
 	return e.(*Environ).firewaller.(*defaultFirewaller).ensureGroup(name, rules)
 }
@@ -358,6 +362,7 @@ func ResolveNetwork(e environs.Environ, networkName string) (string, error) {
 }
 
 var PortsToRuleInfo = portsToRuleInfo
+var RulesToRuleInfo = rulesToRuleInfo
 var RuleMatchesPortRange = ruleMatchesPortRange
 
 var MakeServiceURL = &makeServiceURL
@@ -60,6 +60,29 @@ type Firewaller interface {
 	InstancePorts(inst instance.Instance, machineId string) ([]network.PortRange, error)
 }
 
+// IngressRuleFirewaller is an optional interface that may be
+// implemented by a Firewaller which can restrict access to ports
+// by source address.
+type IngressRuleFirewaller interface {
+	// OpenIngressRules opens the given ingress rules for the whole environment.
+	OpenIngressRules(rules []network.IngressRule) error
+
+	// CloseIngressRules closes the given ingress rules for the whole environment.
+	CloseIngressRules(rules []network.IngressRule) error
+
+	// IngressRules returns the ingress rules opened for the whole environment.
+	IngressRules() ([]network.IngressRule, error)
+
+	// OpenInstanceIngressRules opens the given ingress rules for the specified instance.
+	OpenInstanceIngressRules(inst instance.Instance, machineId string, rules []network.IngressRule) error
+
+	// CloseInstanceIngressRules closes the given ingress rules for the specified instance.
+	CloseInstanceIngressRules(inst instance.Instance, machineId string, rules []network.IngressRule) error
+
+	// InstanceIngressRules returns the ingress rules opened for the specified instance.
+	InstanceIngressRules(inst instance.Instance, machineId string) ([]network.IngressRule, error)
+}
+
 type firewallerFactory struct {
 }
 
@@ -72,6 +95,8 @@ type defaultFirewaller struct {
 	environ *Environ
 }
 
+var _ IngressRuleFirewaller = (*defaultFirewaller)(nil)
+
 // InitialNetworks implements Firewaller interface.
 func (c *defaultFirewaller) InitialNetworks() []nova.ServerNetworks {
 	return []nova.ServerNetworks{}
@@ -115,36 +140,43 @@ func (c *defaultFirewaller) SetUpGroups(machineId string, apiPort int) ([]nova.S
 }
 
 func (c *defaultFirewaller) setUpGlobalGroup(groupName string, apiPort int) (nova.SecurityGroup, error) {
-	return c.ensureGroup(groupName,
-		[]nova.RuleInfo{
-			{
-				IPProtocol: "tcp",
-				FromPort:   22,
-				ToPort:     22,
-				Cidr:       "0.0.0.0/0",
-			},
-			{
-				IPProtocol: "tcp",
-				FromPort:   apiPort,
-				ToPort:     apiPort,
-				Cidr:       "0.0.0.0/0",
-			},
-			{
+	ipv6, err := c.environ.supportsIPv6Rules()
+	if err != nil {
+		return zeroGroup, err
+	}
+	cidrs := []string{network.AnyIPv4CIDR}
+	if ipv6 {
+		cidrs = append(cidrs, network.AnyIPv6CIDR)
+	}
+	var rules []nova.RuleInfo
+	for _, port := range []int{22, apiPort} {
+		for _, cidr := range cidrs {
+			rules = append(rules, nova.RuleInfo{
 				IPProtocol: "tcp",
-				FromPort:   1,
-				ToPort:     65535,
-			},
-			{
-				IPProtocol: "udp",
-				FromPort:   1,
-				ToPort:     65535,
-			},
-			{
-				IPProtocol: "icmp",
-				FromPort:   -1,
-				ToPort:     -1,
-			},
-		})
+				FromPort:   port,
+				ToPort:     port,
+				Cidr:       cidr,
+			})
+		}
+	}
+	rules = append(rules,
+		nova.RuleInfo{
+			IPProtocol: "tcp",
+			FromPort:   1,
+			ToPort:     65535,
+		},
+		nova.RuleInfo{
+			IPProtocol: "udp",
+			FromPort:   1,
+			ToPort:     65535,
+		},
+		nova.RuleInfo{
+			IPProtocol: "icmp",
+			FromPort:   -1,
+			ToPort:     -1,
+		},
+	)
+	return c.ensureGroup(groupName, rules)
 }
 
 // zeroGroup holds the zero security group.
@@ -279,88 +311,130 @@ func deleteSecurityGroup(novaclient *nova.Client, name, id string) {
 
 // OpenPorts implements Firewaller interface.
 func (c *defaultFirewaller) OpenPorts(ports []network.PortRange) error {
+	return c.OpenIngressRules(network.IngressRulesFromPortRanges(ports))
+}
+
+// ClosePorts implements Firewaller interface.
+func (c *defaultFirewaller) ClosePorts(ports []network.PortRange) error {
+	return c.CloseIngressRules(network.IngressRulesFromPortRanges(ports))
+}
+
+// Ports implements Firewaller interface.
+func (c *defaultFirewaller) Ports() ([]network.PortRange, error) {
+	if c.environ.Config().FirewallMode() != config.FwGlobal {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from model",
+			c.environ.Config().FirewallMode())
+	}
+	return c.portsInGroup(c.globalGroupName())
+}
+
+// OpenIngressRules implements IngressRuleFirewaller interface.
+func (c *defaultFirewaller) OpenIngressRules(rules []network.IngressRule) error {
 	if c.environ.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on model",
 			c.environ.Config().FirewallMode())
 	}
-	if err := c.openPortsInGroup(c.globalGroupName(), ports); err != nil {
+	if err := c.openPortsInGroup(c.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in global group: %v", ports)
+	logger.Infof("opened ports in global group: %v", rules)
 	return nil
 }
 
-// ClosePorts implements Firewaller interface.
-func (c *defaultFirewaller) ClosePorts(ports []network.PortRange) error {
+// CloseIngressRules implements IngressRuleFirewaller interface.
+func (c *defaultFirewaller) CloseIngressRules(rules []network.IngressRule) error {
 	if c.environ.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on model",
 			c.environ.Config().FirewallMode())
 	}
-	if err := c.closePortsInGroup(c.globalGroupName(), ports); err != nil {
+	if err := c.closePortsInGroup(c.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in global group: %v", ports)
+	logger.Infof("closed ports in global group: %v", rules)
 	return nil
 }
 
-// Ports implements Firewaller interface.
-func (c *defaultFirewaller) Ports() ([]network.PortRange, error) {
+// IngressRules implements IngressRuleFirewaller interface.
+func (c *defaultFirewaller) IngressRules() ([]network.IngressRule, error) {
 	if c.environ.Config().FirewallMode() != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from model",
 			c.environ.Config().FirewallMode())
 	}
-	return c.portsInGroup(c.globalGroupName())
+	return c.rulesInGroup(c.globalGroupName())
 }
 
 // OpenInstancePorts implements Firewaller interface.
 func (c *defaultFirewaller) OpenInstancePorts(inst instance.Instance, machineId string, ports []network.PortRange) error {
+	return c.OpenInstanceIngressRules(inst, machineId, network.IngressRulesFromPortRanges(ports))
+}
+
+// CloseInstancePorts implements Firewaller interface.
+func (c *defaultFirewaller) CloseInstancePorts(inst instance.Instance, machineId string, ports []network.PortRange) error {
+	return c.CloseInstanceIngressRules(inst, machineId, network.IngressRulesFromPortRanges(ports))
+}
+
+// InstancePorts implements Firewaller interface.
+func (c *defaultFirewaller) InstancePorts(inst instance.Instance, machineId string) ([]network.PortRange, error) {
+	if c.environ.Config().FirewallMode() != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
+			c.environ.Config().FirewallMode())
+	}
+	name := c.machineGroupName(machineId)
+	portRanges, err := c.portsInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	return portRanges, nil
+}
+
+// OpenInstanceIngressRules implements IngressRuleFirewaller interface.
+func (c *defaultFirewaller) OpenInstanceIngressRules(inst instance.Instance, machineId string, rules []network.IngressRule) error {
 	if c.environ.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			c.environ.Config().FirewallMode())
 	}
 	name := c.machineGroupName(machineId)
-	if err := c.openPortsInGroup(name, ports); err != nil {
+	if err := c.openPortsInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in security group %s: %v", name, ports)
+	logger.Infof("opened ports in security group %s: %v", name, rules)
 	return nil
 }
 
-// CloseInstancePorts implements Firewaller interface.
-func (c *defaultFirewaller) CloseInstancePorts(inst instance.Instance, machineId string, ports []network.PortRange) error {
+// CloseInstanceIngressRules implements IngressRuleFirewaller interface.
+func (c *defaultFirewaller) CloseInstanceIngressRules(inst instance.Instance, machineId string, rules []network.IngressRule) error {
 	if c.environ.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
 			c.environ.Config().FirewallMode())
 	}
 	name := c.machineGroupName(machineId)
-	if err := c.closePortsInGroup(name, ports); err != nil {
+	if err := c.closePortsInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in security group %s: %v", name, ports)
+	logger.Infof("closed ports in security group %s: %v", name, rules)
 	return nil
 }
 
-// InstancePorts implements Firewaller interface.
-func (c *defaultFirewaller) InstancePorts(inst instance.Instance, machineId string) ([]network.PortRange, error) {
+// InstanceIngressRules implements IngressRuleFirewaller interface.
+func (c *defaultFirewaller) InstanceIngressRules(inst instance.Instance, machineId string) ([]network.IngressRule, error) {
 	if c.environ.Config().FirewallMode() != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
 			c.environ.Config().FirewallMode())
 	}
-	name := c.machineGroupName(machineId)
-	portRanges, err := c.portsInGroup(name)
-	if err != nil {
-		return nil, err
-	}
-	return portRanges, nil
+	return c.rulesInGroup(c.machineGroupName(machineId))
 }
 
-func (c *defaultFirewaller) openPortsInGroup(name string, portRanges []network.PortRange) error {
+func (c *defaultFirewaller) openPortsInGroup(name string, ingressRules []network.IngressRule) error {
 	novaclient := c.environ.nova()
 	group, err := novaclient.SecurityGroupByName(name)
 	if err != nil {
 		return err
 	}
-	rules := portsToRuleInfo(group.Id, portRanges)
+	ipv6, err := c.environ.supportsIPv6Rules()
+	if err != nil {
+		return err
+	}
+	rules := rulesToRuleInfo(group.Id, ingressRules, ipv6)
 	for _, rule := range rules {
 		_, err := novaclient.CreateSecurityGroupRule(rule)
 		if err != nil {
@@ -381,8 +455,17 @@ func ruleMatchesPortRange(rule nova.SecurityGroupRule, portRange network.PortRan
 		*rule.ToPort == portRange.ToPort
 }
 
-func (c *defaultFirewaller) closePortsInGroup(name string, portRanges []network.PortRange) error {
-	if len(portRanges) == 0 {
+// ruleCIDR returns the source CIDR of the supplied nova security group
+// rule. Rules without a CIDR allow access from anywhere.
+func ruleCIDR(rule nova.SecurityGroupRule) string {
+	if cidr := rule.IPRange["cidr"]; cidr != "" {
+		return cidr
+	}
+	return network.AnyIPv4CIDR
+}
+
+func (c *defaultFirewaller) closePortsInGroup(name string, ingressRules []network.IngressRule) error {
+	if len(ingressRules) == 0 {
 		return nil
 	}
 	novaclient := c.environ.nova()
@@ -391,36 +474,65 @@ func (c *defaultFirewaller) closePortsInGroup(name string, portRanges []network.
 		return err
 	}
 	// TODO: Hey look ma, it's quadratic
-	for _, portRange := range portRanges {
-		for _, p := range (*group).Rules {
-			if !ruleMatchesPortRange(p, portRange) {
-				continue
-			}
-			err := novaclient.DeleteSecurityGroupRule(p.Id)
-			if err != nil {
-				return err
+	for _, ingressRule := range ingressRules {
+		for _, cidr := range network.DualStackIngressRuleSourceCIDRs(ingressRule) {
+			for _, p := range (*group).Rules {
+				if !ruleMatchesPortRange(p, ingressRule.PortRange) || ruleCIDR(p) != cidr {
+					continue
+				}
+				err := novaclient.DeleteSecurityGroupRule(p.Id)
+				if err != nil {
+					return err
+				}
+				break
 			}
-			break
 		}
 	}
 	return nil
 }
 
-func (c *defaultFirewaller) portsInGroup(name string) (portRanges []network.PortRange, err error) {
+func (c *defaultFirewaller) rulesInGroup(name string) ([]network.IngressRule, error) {
 	group, err := c.environ.nova().SecurityGroupByName(name)
 	if err != nil {
 		return nil, err
 	}
+	// Nova reports a rule for each source CIDR, so gather the
+	// CIDRs allowed to access each port range.
+	var portRanges []network.PortRange
+	cidrs := make(map[network.PortRange][]string)
 	for _, p := range (*group).Rules {
-		portRanges = append(portRanges, network.PortRange{
+		portRange := network.PortRange{
 			Protocol: *p.IPProtocol,
 			FromPort: *p.FromPort,
 			ToPort:   *p.ToPort,
-		})
+		}
+		if _, ok := cidrs[portRange]; !ok {
+			portRanges = append(portRanges, portRange)
+		}
+		cidrs[portRange] = append(cidrs[portRange], ruleCIDR(p))
+	}
+	var rules []network.IngressRule
+	for _, portRange := range portRanges {
+		portRules, err := network.IngressRulesForSourceCIDRs(portRange, cidrs[portRange])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, portRules...)
 	}
+	network.SortIngressRules(rules)
+	return rules, nil
+}
+
+func (c *defaultFirewaller) portsInGroup(name string) ([]network.PortRange, error) {
+	rules, err := c.rulesInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	portRanges := network.IngressRulePortRanges(rules)
 	network.SortPortRanges(portRanges)
 	return portRanges, nil
 }
+
 func (c *defaultFirewaller) globalGroupName() string {
 	return fmt.Sprintf("%s-global", c.jujuGroupName())
 }
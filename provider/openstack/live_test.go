@@ -205,6 +205,15 @@ func (t *LiveTests) TestSetupGlobalGroupExposesCorrectPorts(c *gc.C) {
 		fmt.Sprintf(`udp 1 65535 "" "%s"`, groupName),
 		fmt.Sprintf(`icmp -1 -1 "" "%s"`, groupName),
 	}
+	// SSH and the API are only opened over IPv6 with neutron.
+	ipv6, err := openstack.SupportsIPv6Rules(t.Env)
+	c.Assert(err, jc.ErrorIsNil)
+	if ipv6 {
+		expectedRules = append(expectedRules,
+			`tcp 22 22 "::/0" ""`,
+			fmt.Sprintf(`tcp %d %d "::/0" ""`, apiPort, apiPort),
+		)
+	}
 	sort.Strings(stringRules)
 	sort.Strings(expectedRules)
 	c.Check(stringRules, gc.DeepEquals, expectedRules)
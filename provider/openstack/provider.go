@@ -237,7 +237,7 @@ type Environ struct {
 	keystoneToolsDataSource      simplestreams.DataSource
 
 	availabilityZonesMutex sync.Mutex
-	availabilityZones      []common.AvailabilityZone
+	availabilityZones      []instance.AvailabilityZone
 	firewaller             Firewaller
 	configurator           ProviderConfigurator
 }
@@ -247,6 +247,7 @@ var _ simplestreams.HasRegion = (*Environ)(nil)
 var _ state.Prechecker = (*Environ)(nil)
 var _ state.InstanceDistributor = (*Environ)(nil)
 var _ environs.InstanceTagger = (*Environ)(nil)
+var _ environs.IngressRuleFirewaller = (*Environ)(nil)
 
 type openstackInstance struct {
 	e        *Environ
@@ -264,6 +265,7 @@ func (inst *openstackInstance) String() string {
 }
 
 var _ instance.Instance = (*openstackInstance)(nil)
+var _ instance.IngressRuleFirewaller = (*openstackInstance)(nil)
 
 func (inst *openstackInstance) Refresh() error {
 	inst.mu.Lock()
@@ -390,6 +392,43 @@ func (inst *openstackInstance) Ports(machineId string) ([]network.PortRange, err
 	return inst.e.firewaller.InstancePorts(inst, machineId)
 }
 
+// OpenIngressRules is specified in the instance.IngressRuleFirewaller
+// interface.
+func (inst *openstackInstance) OpenIngressRules(machineId string, rules []network.IngressRule) error {
+	if fw, ok := inst.e.firewaller.(IngressRuleFirewaller); ok {
+		return fw.OpenInstanceIngressRules(inst, machineId, rules)
+	}
+	if err := checkUnrestrictedIngressRules(rules); err != nil {
+		return errors.Trace(err)
+	}
+	return inst.OpenPorts(machineId, network.IngressRulePortRanges(rules))
+}
+
+// CloseIngressRules is specified in the instance.IngressRuleFirewaller
+// interface.
+func (inst *openstackInstance) CloseIngressRules(machineId string, rules []network.IngressRule) error {
+	if fw, ok := inst.e.firewaller.(IngressRuleFirewaller); ok {
+		return fw.CloseInstanceIngressRules(inst, machineId, rules)
+	}
+	if err := checkUnrestrictedIngressRules(rules); err != nil {
+		return errors.Trace(err)
+	}
+	return inst.ClosePorts(machineId, network.IngressRulePortRanges(rules))
+}
+
+// IngressRules is specified in the instance.IngressRuleFirewaller
+// interface.
+func (inst *openstackInstance) IngressRules(machineId string) ([]network.IngressRule, error) {
+	if fw, ok := inst.e.firewaller.(IngressRuleFirewaller); ok {
+		return fw.InstanceIngressRules(inst, machineId)
+	}
+	portRanges, err := inst.Ports(machineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return network.IngressRulesFromPortRanges(portRanges), nil
+}
+
 func (e *Environ) ecfg() *environConfig {
 	e.ecfgMutex.Lock()
 	ecfg := e.ecfgUnlocked
@@ -469,7 +508,7 @@ func (z *openstackAvailabilityZone) Available() bool {
 }
 
 // AvailabilityZones returns a slice of availability zones.
-func (e *Environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *Environ) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	e.availabilityZonesMutex.Lock()
 	defer e.availabilityZonesMutex.Unlock()
 	if e.availabilityZones == nil {
@@ -480,7 +519,7 @@ func (e *Environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
 		if err != nil {
 			return nil, err
 		}
-		e.availabilityZones = make([]common.AvailabilityZone, len(zones))
+		e.availabilityZones = make([]instance.AvailabilityZone, len(zones))
 		for i, z := range zones {
 			e.availabilityZones[i] = &openstackAvailabilityZone{z}
 		}
@@ -637,6 +676,21 @@ for tenant-name in your model configuration.`)
 	return nil
 }
 
+// supportsIPv6Rules reports whether the cloud's security groups
+// accept rules for IPv6 addresses. Nova-network only filters IPv4
+// traffic and rejects IPv6 rules, so they are only created where
+// networking is provided by neutron, which the catalog lists as the
+// "network" service.
+func (e *Environ) supportsIPv6Rules() (bool, error) {
+	if !e.client.IsAuthenticated() {
+		if err := authenticateClient(e); err != nil {
+			return false, errors.Trace(err)
+		}
+	}
+	_, ok := e.client.EndpointsForRegion(e.ecfg().region())["network"]
+	return ok, nil
+}
+
 func (e *Environ) SetConfig(cfg *config.Config) error {
 	ecfg, err := providerInstance.newConfig(cfg)
 	if err != nil {
@@ -1161,15 +1215,28 @@ func (e *Environ) machinesFilter() *nova.Filter {
 }
 
 // portsToRuleInfo maps port ranges to nova rules
-func portsToRuleInfo(groupId string, ports []network.PortRange) []nova.RuleInfo {
-	rules := make([]nova.RuleInfo, len(ports))
-	for i, portRange := range ports {
-		rules[i] = nova.RuleInfo{
-			ParentGroupId: groupId,
-			FromPort:      portRange.FromPort,
-			ToPort:        portRange.ToPort,
-			IPProtocol:    portRange.Protocol,
-			Cidr:          "0.0.0.0/0",
+func portsToRuleInfo(groupId string, ports []network.PortRange, ipv6 bool) []nova.RuleInfo {
+	return rulesToRuleInfo(groupId, network.IngressRulesFromPortRanges(ports), ipv6)
+}
+
+// rulesToRuleInfo maps ingress rules to nova rules, one for
+// each source CIDR. Rules allowing access from anywhere do so
+// over IPv6 as well as IPv4 if ipv6 is true.
+func rulesToRuleInfo(groupId string, ingressRules []network.IngressRule, ipv6 bool) []nova.RuleInfo {
+	sourceCIDRs := network.IngressRuleSourceCIDRs
+	if ipv6 {
+		sourceCIDRs = network.DualStackIngressRuleSourceCIDRs
+	}
+	var rules []nova.RuleInfo
+	for _, ingressRule := range ingressRules {
+		for _, cidr := range sourceCIDRs(ingressRule) {
+			rules = append(rules, nova.RuleInfo{
+				ParentGroupId: groupId,
+				FromPort:      ingressRule.FromPort,
+				ToPort:        ingressRule.ToPort,
+				IPProtocol:    ingressRule.Protocol,
+				Cidr:          cidr,
+			})
 		}
 	}
 	return rules
@@ -1187,6 +1254,55 @@ func (e *Environ) Ports() ([]network.PortRange, error) {
 	return e.firewaller.Ports()
 }
 
+// OpenIngressRules is specified in the environs.IngressRuleFirewaller
+// interface.
+func (e *Environ) OpenIngressRules(rules []network.IngressRule) error {
+	if fw, ok := e.firewaller.(IngressRuleFirewaller); ok {
+		return fw.OpenIngressRules(rules)
+	}
+	if err := checkUnrestrictedIngressRules(rules); err != nil {
+		return errors.Trace(err)
+	}
+	return e.firewaller.OpenPorts(network.IngressRulePortRanges(rules))
+}
+
+// CloseIngressRules is specified in the environs.IngressRuleFirewaller
+// interface.
+func (e *Environ) CloseIngressRules(rules []network.IngressRule) error {
+	if fw, ok := e.firewaller.(IngressRuleFirewaller); ok {
+		return fw.CloseIngressRules(rules)
+	}
+	if err := checkUnrestrictedIngressRules(rules); err != nil {
+		return errors.Trace(err)
+	}
+	return e.firewaller.ClosePorts(network.IngressRulePortRanges(rules))
+}
+
+// IngressRules is specified in the environs.IngressRuleFirewaller
+// interface.
+func (e *Environ) IngressRules() ([]network.IngressRule, error) {
+	if fw, ok := e.firewaller.(IngressRuleFirewaller); ok {
+		return fw.IngressRules()
+	}
+	portRanges, err := e.firewaller.Ports()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return network.IngressRulesFromPortRanges(portRanges), nil
+}
+
+// checkUnrestrictedIngressRules returns an error if any of the given
+// rules restricts access by source address, for use with firewallers
+// that do not implement IngressRuleFirewaller.
+func checkUnrestrictedIngressRules(rules []network.IngressRule) error {
+	for _, rule := range rules {
+		if len(rule.SourceCIDRs) > 0 {
+			return errors.NotSupportedf("ingress rule %v", rule)
+		}
+	}
+	return nil
+}
+
 func (e *Environ) Provider() environs.EnvironProvider {
 	return providerInstance
 }
@@ -233,6 +233,12 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			ToPort:        80,
 			Cidr:          "0.0.0.0/0",
 			ParentGroupId: groupId,
+		}, {
+			IPProtocol:    "tcp",
+			FromPort:      80,
+			ToPort:        80,
+			Cidr:          "::/0",
+			ParentGroupId: groupId,
 		}},
 	}, {
 		about: "multiple ports",
@@ -247,6 +253,12 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			ToPort:        82,
 			Cidr:          "0.0.0.0/0",
 			ParentGroupId: groupId,
+		}, {
+			IPProtocol:    "tcp",
+			FromPort:      80,
+			ToPort:        82,
+			Cidr:          "::/0",
+			ParentGroupId: groupId,
 		}},
 	}, {
 		about: "multiple port ranges",
@@ -265,23 +277,91 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			ToPort:        82,
 			Cidr:          "0.0.0.0/0",
 			ParentGroupId: groupId,
+		}, {
+			IPProtocol:    "tcp",
+			FromPort:      80,
+			ToPort:        82,
+			Cidr:          "::/0",
+			ParentGroupId: groupId,
 		}, {
 			IPProtocol:    "tcp",
 			FromPort:      100,
 			ToPort:        120,
 			Cidr:          "0.0.0.0/0",
 			ParentGroupId: groupId,
+		}, {
+			IPProtocol:    "tcp",
+			FromPort:      100,
+			ToPort:        120,
+			Cidr:          "::/0",
+			ParentGroupId: groupId,
 		}},
 	}}
 
 	for i, t := range testCases {
 		c.Logf("test %d: %s", i, t.about)
-		rules := openstack.PortsToRuleInfo(groupId, t.ports)
+		rules := openstack.PortsToRuleInfo(groupId, t.ports, true)
 		c.Check(len(rules), gc.Equals, len(t.expected))
 		c.Check(rules, gc.DeepEquals, t.expected)
 	}
 }
 
+func (*localTests) TestRulesToRuleInfo(c *gc.C) {
+	ingressRules := []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}),
+		network.MustNewIngressRule(network.PortRange{443, 443, "tcp"}, "10.0.0.0/8", "192.168.1.0/24"),
+	}
+	rules := openstack.RulesToRuleInfo("groupid", ingressRules, true)
+	c.Assert(rules, gc.DeepEquals, []nova.RuleInfo{{
+		IPProtocol:    "tcp",
+		FromPort:      80,
+		ToPort:        80,
+		Cidr:          "0.0.0.0/0",
+		ParentGroupId: "groupid",
+	}, {
+		IPProtocol:    "tcp",
+		FromPort:      80,
+		ToPort:        80,
+		Cidr:          "::/0",
+		ParentGroupId: "groupid",
+	}, {
+		IPProtocol:    "tcp",
+		FromPort:      443,
+		ToPort:        443,
+		Cidr:          "10.0.0.0/8",
+		ParentGroupId: "groupid",
+	}, {
+		IPProtocol:    "tcp",
+		FromPort:      443,
+		ToPort:        443,
+		Cidr:          "192.168.1.0/24",
+		ParentGroupId: "groupid",
+	}})
+
+	// Without IPv6 support, as with nova-network, rules allowing
+	// access from anywhere only do so over IPv4.
+	rules = openstack.RulesToRuleInfo("groupid", ingressRules, false)
+	c.Assert(rules, gc.DeepEquals, []nova.RuleInfo{{
+		IPProtocol:    "tcp",
+		FromPort:      80,
+		ToPort:        80,
+		Cidr:          "0.0.0.0/0",
+		ParentGroupId: "groupid",
+	}, {
+		IPProtocol:    "tcp",
+		FromPort:      443,
+		ToPort:        443,
+		Cidr:          "10.0.0.0/8",
+		ParentGroupId: "groupid",
+	}, {
+		IPProtocol:    "tcp",
+		FromPort:      443,
+		ToPort:        443,
+		Cidr:          "192.168.1.0/24",
+		ParentGroupId: "groupid",
+	}})
+}
+
 func (*localTests) TestRuleMatchesPortRange(c *gc.C) {
 	proto_tcp := "tcp"
 	proto_udp := "udp"
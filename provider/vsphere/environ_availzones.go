@@ -18,24 +18,24 @@ type vmwareAvailZone struct {
 	r mo.ComputeResource
 }
 
-// Name implements common.AvailabilityZone
+// Name implements instance.AvailabilityZone
 func (z *vmwareAvailZone) Name() string {
 	return z.r.Name
 }
 
-// Available implements common.AvailabilityZone
+// Available implements instance.AvailabilityZone
 func (z *vmwareAvailZone) Available() bool {
 	return true
 }
 
 // AvailabilityZones returns all availability zones in the environment.
-func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (env *environ) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	zones, err := env.client.AvailabilityZones()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	var result []common.AvailabilityZone
+	var result []instance.AvailabilityZone
 	for _, zone := range zones {
 		result = append(result, &vmwareAvailZone{*zone})
 	}
@@ -100,8 +100,9 @@ func newResource(c *gc.C, name, username, data string) (resource.Resource, api.R
 type stubFacade struct {
 	basetesting.StubFacadeCaller
 
-	apiResults map[string]api.ResourcesResult
-	pendingIDs []string
+	apiResults     map[string]api.ResourcesResult
+	pendingIDs     []string
+	rollbackResult api.RollbackResourceResult
 }
 
 func newStubFacade(c *gc.C, stub *testing.Stub) *stubFacade {
@@ -134,6 +135,8 @@ func newStubFacade(c *gc.C, stub *testing.Stub) *stubFacade {
 			}
 		case *api.AddPendingResourcesResult:
 			typedResponse.PendingIDs = s.pendingIDs
+		case *api.RollbackResourceResult:
+			*typedResponse = s.rollbackResult
 		default:
 			c.Errorf("bad type %T", response)
 		}
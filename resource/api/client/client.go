@@ -24,6 +24,7 @@ var logger = loggo.GetLogger("juju.resource.api.client")
 // FacadeCaller has the api/base.FacadeCaller methods needed for the component.
 type FacadeCaller interface {
 	FacadeCall(request string, params, response interface{}) error
+	BestAPIVersion() int
 }
 
 // Doer
@@ -163,6 +164,38 @@ func (c Client) AddPendingResource(serviceID string, res charmresource.Resource,
 	return pendingID, nil
 }
 
+// RollbackResource makes a previous revision of the identified
+// resource the active one, and returns it. If revision is empty then
+// the most recent previous revision is used.
+func (c Client) RollbackResource(service, name, revision string) (resource.Resource, error) {
+	args, err := api.NewRollbackResourceArgs(service, name, revision)
+	if err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+	version := c.BestAPIVersion()
+	if version < 2 {
+		return resource.Resource{}, errors.NotSupportedf("rolling back resources on this juju controller")
+	}
+	if revision != "" && version < 3 {
+		return resource.Resource{}, errors.NotSupportedf("rolling back resources to a given revision on this juju controller")
+	}
+
+	var result api.RollbackResourceResult
+	if err := c.FacadeCall("RollbackResource", &args, &result); err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+	if result.Error != nil {
+		err := common.RestoreError(result.Error)
+		return resource.Resource{}, errors.Trace(err)
+	}
+
+	res, err := api.API2Resource(result.Resource)
+	if err != nil {
+		return resource.Resource{}, errors.Annotate(err, "got bad data from server")
+	}
+	return res, nil
+}
+
 func resolveErrors(errs []error) error {
 	switch len(errs) {
 	case 0:
@@ -0,0 +1,108 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/resource/api"
+	"github.com/juju/juju/resource/api/client"
+)
+
+var _ = gc.Suite(&RollbackResourceSuite{})
+
+type RollbackResourceSuite struct {
+	BaseSuite
+}
+
+func (s *RollbackResourceSuite) TestOkay(c *gc.C) {
+	expected, apiRes := newResource(c, "spam", "a-user", "spamspamspam")
+	s.facade.rollbackResult = api.RollbackResourceResult{Resource: apiRes}
+	s.facade.ReturnBestAPIVersion = 2
+	cl := client.NewClient(s.facade, s, s.facade)
+
+	res, err := cl.RollbackResource("a-service", "spam", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(res, jc.DeepEquals, expected)
+	s.stub.CheckCallNames(c, "BestAPIVersion", "FacadeCall")
+	s.stub.CheckCall(c, 1, "FacadeCall",
+		"RollbackResource",
+		&api.RollbackResourceArgs{
+			Entity: params.Entity{Tag: "service-a-service"},
+			Name:   "spam",
+		},
+		&api.RollbackResourceResult{Resource: apiRes},
+	)
+}
+
+func (s *RollbackResourceSuite) TestServerError(c *gc.C) {
+	s.facade.rollbackResult = api.RollbackResourceResult{
+		ErrorResult: params.ErrorResult{Error: &params.Error{
+			Message: `cannot roll back resource "spam": previous revision of resource "a-service/spam" not found`,
+			Code:    params.CodeNotFound,
+		}},
+	}
+	s.facade.ReturnBestAPIVersion = 2
+	cl := client.NewClient(s.facade, s, s.facade)
+
+	_, err := cl.RollbackResource("a-service", "spam", "")
+
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `cannot roll back resource "spam": previous revision of resource "a-service/spam" not found`)
+}
+
+func (s *RollbackResourceSuite) TestBadService(c *gc.C) {
+	cl := client.NewClient(s.facade, s, s.facade)
+
+	_, err := cl.RollbackResource("???", "spam", "")
+
+	c.Check(err, gc.ErrorMatches, `invalid service "\?\?\?"`)
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *RollbackResourceSuite) TestNotSupported(c *gc.C) {
+	s.facade.ReturnBestAPIVersion = 1
+	cl := client.NewClient(s.facade, s, s.facade)
+
+	_, err := cl.RollbackResource("a-service", "spam", "")
+
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	s.stub.CheckCallNames(c, "BestAPIVersion")
+}
+
+func (s *RollbackResourceSuite) TestRevision(c *gc.C) {
+	expected, apiRes := newResource(c, "spam", "a-user", "spamspamspam")
+	s.facade.rollbackResult = api.RollbackResourceResult{Resource: apiRes}
+	s.facade.ReturnBestAPIVersion = 3
+	cl := client.NewClient(s.facade, s, s.facade)
+
+	res, err := cl.RollbackResource("a-service", "spam", "2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(res, jc.DeepEquals, expected)
+	s.stub.CheckCallNames(c, "BestAPIVersion", "FacadeCall")
+	s.stub.CheckCall(c, 1, "FacadeCall",
+		"RollbackResource",
+		&api.RollbackResourceArgs{
+			Entity:   params.Entity{Tag: "service-a-service"},
+			Name:     "spam",
+			Revision: "2",
+		},
+		&api.RollbackResourceResult{Resource: apiRes},
+	)
+}
+
+func (s *RollbackResourceSuite) TestRevisionNotSupported(c *gc.C) {
+	s.facade.ReturnBestAPIVersion = 2
+	cl := client.NewClient(s.facade, s, s.facade)
+
+	_, err := cl.RollbackResource("a-service", "spam", "2")
+
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	s.stub.CheckCallNames(c, "BestAPIVersion")
+}
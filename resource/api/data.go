@@ -81,6 +81,46 @@ type AddPendingResourcesResult struct {
 	PendingIDs []string
 }
 
+// RollbackResourceArgs holds the arguments to the RollbackResource
+// API endpoint.
+type RollbackResourceArgs struct {
+	params.Entity
+
+	// Name is the name of the resource to roll back.
+	Name string
+
+	// Revision identifies the previous revision to roll back to, as
+	// given by its revision string. If it is empty then the most
+	// recent previous revision is used.
+	Revision string
+}
+
+// NewRollbackResourceArgs returns the arguments for the
+// RollbackResource API endpoint.
+func NewRollbackResourceArgs(service, name, revision string) (RollbackResourceArgs, error) {
+	var args RollbackResourceArgs
+	if !names.IsValidService(service) {
+		return args, errors.Errorf("invalid service %q", service)
+	}
+	if name == "" {
+		return args, errors.New("missing resource name")
+	}
+	args.Tag = names.NewServiceTag(service).String()
+	args.Name = name
+	args.Revision = revision
+	return args, nil
+}
+
+// RollbackResourceResult holds the result of the RollbackResource
+// API endpoint.
+type RollbackResourceResult struct {
+	params.ErrorResult
+
+	// Resource describes the revision of the resource that is now
+	// active.
+	Resource Resource
+}
+
 // ResourcesResults holds the resources that result
 // from a bulk API call.
 type ResourcesResults struct {
@@ -9,8 +9,10 @@ import (
 	"path"
 
 	"github.com/juju/errors"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/api"
 	"github.com/juju/juju/resource/api/private"
@@ -20,6 +22,9 @@ import (
 type FacadeCaller interface {
 	// FacadeCall makes an API request.
 	FacadeCall(request string, params, response interface{}) error
+
+	// BestAPIVersion returns the facade version used for requests.
+	BestAPIVersion() int
 }
 
 // HTTPClient exposes the raw API HTTP caller functionality needed here.
@@ -68,7 +73,7 @@ func (c *UnitFacadeClient) GetResource(resourceName string) (resource.Resource,
 	}
 
 	// HACK(katco): Combine this into one request?
-	resourceInfo, err := c.getResourceInfo(resourceName)
+	resourceInfo, err := c.GetResourceInfo(resourceName)
 	if err != nil {
 		return resource.Resource{}, nil, errors.Trace(err)
 	}
@@ -78,7 +83,9 @@ func (c *UnitFacadeClient) GetResource(resourceName string) (resource.Resource,
 	return resourceInfo, response.Body, nil
 }
 
-func (c *UnitFacadeClient) getResourceInfo(resourceName string) (resource.Resource, error) {
+// GetResourceInfo returns the info for the named resource (of the
+// unit-implied service), without its content.
+func (c *UnitFacadeClient) GetResourceInfo(resourceName string) (resource.Resource, error) {
 	var response private.ResourcesResult
 
 	args := private.ListResourcesArgs{
@@ -106,6 +113,34 @@ func (c *UnitFacadeClient) getResourceInfo(resourceName string) (resource.Resour
 	return res, nil
 }
 
+// SetUnitResource records that the unit is using the current revision
+// of the named resource, which it got without downloading it. The
+// request fails if the current revision does not have the given
+// fingerprint.
+func (c *UnitFacadeClient) SetUnitResource(resourceName string, fingerprint charmresource.Fingerprint) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("recording resource use on this juju controller")
+	}
+	var response params.ErrorResults
+
+	args := private.SetUnitResourcesArgs{
+		Resources: []private.SetUnitResourceArg{{
+			Name:        resourceName,
+			Fingerprint: fingerprint.Bytes(),
+		}},
+	}
+	if err := c.FacadeCall("SetUnitResources", &args, &response); err != nil {
+		return errors.Annotate(err, "could not record resource use")
+	}
+	if len(response.Results) != 1 {
+		return errors.New("got bad response from API server")
+	}
+	if err := response.Results[0].Error; err != nil {
+		return errors.Annotate(common.RestoreError(err), "request failed for resource")
+	}
+	return nil
+}
+
 type unitHTTPClient struct {
 	HTTPClient
 	unitName string
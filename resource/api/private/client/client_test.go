@@ -12,7 +12,9 @@ import (
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/testing/filetesting"
 	gc "gopkg.in/check.v1"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/api"
 	"github.com/juju/juju/resource/api/private"
@@ -60,6 +62,76 @@ func (s *UnitFacadeClientSuite) TestGetResource(c *gc.C) {
 	c.Check(content, jc.DeepEquals, opened)
 }
 
+func (s *UnitFacadeClientSuite) TestGetResourceInfo(c *gc.C) {
+	opened := resourcetesting.NewResource(c, s.stub, "spam", "a-service", "some data")
+	s.api.setResource(opened.Resource, opened)
+	cl := client.NewUnitFacadeClient(s.api, s.api)
+
+	info, err := cl.GetResourceInfo("spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "FacadeCall")
+	s.stub.CheckCall(c, 0, "FacadeCall",
+		&private.ListResourcesArgs{ResourceNames: []string{"spam"}},
+		&private.ResourcesResult{Resources: []private.ResourceResult{{
+			Resource: api.Resource2API(opened.Resource),
+		}}},
+	)
+	c.Check(info, jc.DeepEquals, opened.Resource)
+}
+
+func (s *UnitFacadeClientSuite) TestSetUnitResource(c *gc.C) {
+	s.api.ReturnErrorResults = params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	}
+	s.api.ReturnBestAPIVersion = 2
+	opened := resourcetesting.NewResource(c, nil, "spam", "a-service", "some data")
+	fp := opened.Resource.Fingerprint
+	cl := client.NewUnitFacadeClient(s.api, s.api)
+
+	err := cl.SetUnitResource("spam", fp)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "BestAPIVersion", "FacadeCall")
+	s.stub.CheckCall(c, 1, "FacadeCall",
+		&private.SetUnitResourcesArgs{
+			Resources: []private.SetUnitResourceArg{{
+				Name:        "spam",
+				Fingerprint: fp.Bytes(),
+			}},
+		},
+		&params.ErrorResults{Results: []params.ErrorResult{{}}},
+	)
+}
+
+func (s *UnitFacadeClientSuite) TestSetUnitResourceError(c *gc.C) {
+	s.api.ReturnErrorResults = params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: &params.Error{
+				Message: `resource "spam" not found`,
+				Code:    params.CodeNotFound,
+			},
+		}},
+	}
+	s.api.ReturnBestAPIVersion = 2
+	cl := client.NewUnitFacadeClient(s.api, s.api)
+
+	err := cl.SetUnitResource("spam", charmresource.Fingerprint{})
+
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `request failed for resource: resource "spam" not found`)
+}
+
+func (s *UnitFacadeClientSuite) TestSetUnitResourceNotSupported(c *gc.C) {
+	s.api.ReturnBestAPIVersion = 1
+	cl := client.NewUnitFacadeClient(s.api, s.api)
+
+	err := cl.SetUnitResource("spam", charmresource.Fingerprint{})
+
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	s.stub.CheckCallNames(c, "BestAPIVersion")
+}
+
 func (s *UnitFacadeClientSuite) TestUnitDoer(c *gc.C) {
 	req, err := http.NewRequest("GET", "/resources/eggs", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -78,9 +150,11 @@ func (s *UnitFacadeClientSuite) TestUnitDoer(c *gc.C) {
 type stubAPI struct {
 	*testing.Stub
 
-	ReturnFacadeCall private.ResourcesResult
-	ReturnUnit       string
-	ReturnDo         *http.Response
+	ReturnFacadeCall     private.ResourcesResult
+	ReturnErrorResults   params.ErrorResults
+	ReturnBestAPIVersion int
+	ReturnUnit           string
+	ReturnDo             *http.Response
 }
 
 func (s *stubAPI) setResource(info resource.Resource, reader io.ReadCloser) {
@@ -94,17 +168,28 @@ func (s *stubAPI) setResource(info resource.Resource, reader io.ReadCloser) {
 	}
 }
 
-func (s *stubAPI) FacadeCall(request string, params, response interface{}) error {
-	s.AddCall("FacadeCall", params, response)
+func (s *stubAPI) FacadeCall(request string, args, response interface{}) error {
+	s.AddCall("FacadeCall", args, response)
 	if err := s.NextErr(); err != nil {
 		return errors.Trace(err)
 	}
 
-	resp := response.(*private.ResourcesResult)
-	*resp = s.ReturnFacadeCall
+	switch resp := response.(type) {
+	case *private.ResourcesResult:
+		*resp = s.ReturnFacadeCall
+	case *params.ErrorResults:
+		*resp = s.ReturnErrorResults
+	}
 	return nil
 }
 
+func (s *stubAPI) BestAPIVersion() int {
+	s.AddCall("BestAPIVersion")
+	s.NextErr() // Pop one off.
+
+	return s.ReturnBestAPIVersion
+}
+
 func (s *stubAPI) Unit() string {
 	s.AddCall("Unit")
 	s.NextErr() // Pop one off.
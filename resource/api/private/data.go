@@ -19,6 +19,25 @@ type ListResourcesArgs struct {
 	ResourceNames []string
 }
 
+// SetUnitResourcesArgs holds the arguments for an API request to
+// record that the unit is using resources of its service. The service
+// is implicit to the uniter-specific HTTP connection.
+type SetUnitResourcesArgs struct {
+	// Resources identifies the resources the unit is using.
+	Resources []SetUnitResourceArg
+}
+
+// SetUnitResourceArg identifies a resource the unit is using.
+type SetUnitResourceArg struct {
+	// Name is the name of the resource.
+	Name string
+
+	// Fingerprint is the fingerprint of the content the unit has.
+	// The request fails if it does not match the current revision
+	// of the resource.
+	Fingerprint []byte
+}
+
 // ResourcesResult holds the resource info for a list of requested
 // resources.
 type ResourcesResult struct {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
 
 	"github.com/juju/juju/resource"
 )
@@ -36,3 +37,12 @@ func (s *stubUnitDataStore) ListResources() (resource.ServiceResources, error) {
 
 	return s.ReturnListResources, nil
 }
+
+func (s *stubUnitDataStore) SetUnitResource(name string, fingerprint charmresource.Fingerprint) error {
+	s.AddCall("SetUnitResource", name, fingerprint)
+	if err := s.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
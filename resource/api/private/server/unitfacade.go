@@ -5,8 +5,10 @@ package server
 
 import (
 	"github.com/juju/errors"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/api"
 	"github.com/juju/juju/resource/api/private"
@@ -14,7 +16,8 @@ import (
 
 // FacadeVersion is the version of the current API facade.
 // (We start at 1 to distinguish from the default value.)
-const FacadeVersion = 1
+// Version 2 adds SetUnitResources.
+const FacadeVersion = 2
 
 // UnitDataStore exposes the data storage functionality needed here.
 // All functionality is tied to the unit's service.
@@ -23,6 +26,11 @@ type UnitDataStore interface {
 
 	// ListResources lists all the resources for the service.
 	ListResources() (resource.ServiceResources, error)
+
+	// SetUnitResource records that the unit is using the current
+	// revision of the named resource, which must have the given
+	// fingerprint.
+	SetUnitResource(name string, fingerprint charmresource.Fingerprint) error
 }
 
 // NewUnitFacade returns the resources portion of the uniter's API facade.
@@ -63,6 +71,31 @@ func (uf UnitFacade) GetResourceInfo(args private.ListResourcesArgs) (private.Re
 	return r, nil
 }
 
+// SetUnitResources records that the unit is using the current revision
+// of each of the given resources (of the implicit service). Units call
+// this when they got the content of a resource without downloading
+// it, as when another unit on the same machine already had it. If the
+// current revision of a resource does not have the given fingerprint
+// then the unit does not have it, and the corresponding result is set
+// with an error.
+func (uf UnitFacade) SetUnitResources(args private.SetUnitResourcesArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Resources)),
+	}
+	for i, arg := range args.Resources {
+		fp, err := resource.DeserializeFingerprint(arg.Fingerprint)
+		if err != nil {
+			err = errors.Annotatef(err, "bad fingerprint for resource %q", arg.Name)
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if err := uf.DataStore.SetUnitResource(arg.Name, fp); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return results, nil
+}
+
 func lookUpResource(name string, resources []resource.Resource) (resource.Resource, bool) {
 	for _, res := range resources {
 		if name == res.Name {
@@ -108,3 +108,51 @@ func (s *UnitFacadeSuite) TestGetResourceInfoNotFound(c *gc.C) {
 		}},
 	})
 }
+
+func (s *UnitFacadeSuite) TestSetUnitResources(c *gc.C) {
+	store := &stubUnitDataStore{Stub: s.stub}
+	failure := errors.NotFoundf(`resource "eggs"`)
+	s.stub.SetErrors(nil, failure)
+	uf := server.UnitFacade{DataStore: store}
+
+	spam := resourcetesting.NewResource(c, nil, "spam", "a-service", "spamspamspam").Resource
+	eggs := resourcetesting.NewResource(c, nil, "eggs", "a-service", "eggseggseggs").Resource
+
+	results, err := uf.SetUnitResources(private.SetUnitResourcesArgs{
+		Resources: []private.SetUnitResourceArg{{
+			Name:        "spam",
+			Fingerprint: spam.Fingerprint.Bytes(),
+		}, {
+			Name:        "eggs",
+			Fingerprint: eggs.Fingerprint.Bytes(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "SetUnitResource", "SetUnitResource")
+	s.stub.CheckCall(c, 0, "SetUnitResource", "spam", spam.Fingerprint)
+	s.stub.CheckCall(c, 1, "SetUnitResource", "eggs", eggs.Fingerprint)
+	c.Check(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: common.ServerError(failure)},
+		},
+	})
+}
+
+func (s *UnitFacadeSuite) TestSetUnitResourcesBadFingerprint(c *gc.C) {
+	store := &stubUnitDataStore{Stub: s.stub}
+	uf := server.UnitFacade{DataStore: store}
+
+	results, err := uf.SetUnitResources(private.SetUnitResourcesArgs{
+		Resources: []private.SetUnitResourceArg{{
+			Name:        "spam",
+			Fingerprint: []byte("not a fingerprint"),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckNoCalls(c)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Check(results.Results[0].Error, gc.ErrorMatches, `bad fingerprint for resource "spam": .*`)
+}
@@ -67,6 +67,7 @@ type stubDataStore struct {
 	ReturnGetPendingResource    resource.Resource
 	ReturnSetResource           resource.Resource
 	ReturnUpdatePendingResource resource.Resource
+	ReturnRollbackResource      resource.Resource
 }
 
 func (s *stubDataStore) ListResources(service string) (resource.ServiceResources, error) {
@@ -122,3 +123,12 @@ func (s *stubDataStore) UpdatePendingResource(serviceID, pendingID, userID strin
 
 	return s.ReturnUpdatePendingResource, nil
 }
+
+func (s *stubDataStore) RollbackResource(serviceID, name, revision string) (resource.Resource, error) {
+	s.stub.AddCall("RollbackResource", serviceID, name, revision)
+	if err := s.stub.NextErr(); err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+
+	return s.ReturnRollbackResource, nil
+}
@@ -21,7 +21,9 @@ var logger = loggo.GetLogger("juju.resource.api.server")
 
 const (
 	// Version is the version number of the current Facade.
-	Version = 1
+	// Version 2 adds RollbackResource.
+	// Version 3 adds Revision to RollbackResource.
+	Version = 3
 )
 
 // DataStore is the functionality of Juju's state needed for the resources API.
@@ -54,6 +56,11 @@ type resourceInfoStore interface {
 	// it is resolved. The returned ID is used to identify the pending
 	// resources when resolving it.
 	AddPendingResource(serviceID, userID string, chRes charmresource.Resource, r io.Reader) (string, error)
+
+	// RollbackResource makes a previous revision of the resource the
+	// active one and returns it. If revision is empty then the most
+	// recent previous revision is used.
+	RollbackResource(serviceID, name, revision string) (resource.Resource, error)
 }
 
 // ListResources returns the list of resources for the given service.
@@ -141,6 +148,27 @@ func (f Facade) addPendingResource(serviceID string, apiRes api.CharmResource) (
 	return pendingID, nil
 }
 
+// RollbackResource makes a previous revision of the identified
+// resource the active one. If no revision is given then the most
+// recent previous revision is used.
+func (f Facade) RollbackResource(args api.RollbackResourceArgs) (api.RollbackResourceResult, error) {
+	var result api.RollbackResourceResult
+
+	tag, apiErr := parseServiceTag(args.Tag)
+	if apiErr != nil {
+		result.Error = apiErr
+		return result, nil
+	}
+
+	res, err := f.store.RollbackResource(tag.Id(), args.Name, args.Revision)
+	if err != nil {
+		result.Error = common.ServerError(errors.Annotatef(err, "cannot roll back resource %q", args.Name))
+		return result, nil
+	}
+	result.Resource = api.Resource2API(res)
+	return result, nil
+}
+
 func parseServiceTag(tagStr string) (names.ServiceTag, *params.Error) { // note the concrete error type
 	serviceTag, err := names.ParseServiceTag(tagStr)
 	if err != nil {
@@ -0,0 +1,98 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package server_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/resource/api"
+	"github.com/juju/juju/resource/api/server"
+)
+
+var _ = gc.Suite(&RollbackResourceSuite{})
+
+type RollbackResourceSuite struct {
+	BaseSuite
+}
+
+func (s *RollbackResourceSuite) TestOkay(c *gc.C) {
+	res, apiRes := newResource(c, "spam", "a-user", "spamspamspam")
+	s.data.ReturnRollbackResource = res
+	facade := server.NewFacade(s.data)
+
+	result, err := facade.RollbackResource(api.RollbackResourceArgs{
+		Entity: params.Entity{
+			Tag: "service-a-service",
+		},
+		Name: "spam",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "RollbackResource")
+	s.stub.CheckCall(c, 0, "RollbackResource", "a-service", "spam", "")
+	c.Check(result, jc.DeepEquals, api.RollbackResourceResult{
+		Resource: apiRes,
+	})
+}
+
+func (s *RollbackResourceSuite) TestRevision(c *gc.C) {
+	res, apiRes := newResource(c, "spam", "a-user", "spamspamspam")
+	s.data.ReturnRollbackResource = res
+	facade := server.NewFacade(s.data)
+
+	result, err := facade.RollbackResource(api.RollbackResourceArgs{
+		Entity: params.Entity{
+			Tag: "service-a-service",
+		},
+		Name:     "spam",
+		Revision: "2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "RollbackResource")
+	s.stub.CheckCall(c, 0, "RollbackResource", "a-service", "spam", "2")
+	c.Check(result, jc.DeepEquals, api.RollbackResourceResult{
+		Resource: apiRes,
+	})
+}
+
+func (s *RollbackResourceSuite) TestNotFound(c *gc.C) {
+	s.stub.SetErrors(errors.NotFoundf(`previous revision of resource "a-service/spam"`))
+	facade := server.NewFacade(s.data)
+
+	result, err := facade.RollbackResource(api.RollbackResourceArgs{
+		Entity: params.Entity{
+			Tag: "service-a-service",
+		},
+		Name: "spam",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "RollbackResource")
+	c.Check(result, jc.DeepEquals, api.RollbackResourceResult{
+		ErrorResult: params.ErrorResult{Error: &params.Error{
+			Message: `cannot roll back resource "spam": previous revision of resource "a-service/spam" not found`,
+			Code:    params.CodeNotFound,
+		}},
+	})
+}
+
+func (s *RollbackResourceSuite) TestBadTag(c *gc.C) {
+	facade := server.NewFacade(s.data)
+
+	result, err := facade.RollbackResource(api.RollbackResourceArgs{
+		Entity: params.Entity{
+			Tag: "unit-a-service-0",
+		},
+		Name: "spam",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckNoCalls(c)
+	c.Check(result.Error, gc.NotNil)
+	c.Check(result.Error.Code, gc.Equals, params.CodeBadRequest)
+}
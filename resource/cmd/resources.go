@@ -0,0 +1,36 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cmd
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var resourcesDoc = `
+"juju resources" manages the resources of the services in your model.
+`
+
+const resourcesPurpose = "manage service resources"
+
+// ResourcesDeps is a type that contains external functions that the
+// "resources" super-command's sub-commands depend on to function.
+type ResourcesDeps struct {
+	// Rollback holds the dependencies of the rollback sub-command.
+	Rollback RollbackDeps
+}
+
+// NewSuperCommand returns a new resources super-command.
+func NewSuperCommand(deps ResourcesDeps) cmd.Command {
+	resourcesCmd := cmd.NewSuperCommand(
+		cmd.SuperCommandParams{
+			Name:        "resources",
+			Doc:         resourcesDoc,
+			UsagePrefix: "juju",
+			Purpose:     resourcesPurpose,
+		})
+	resourcesCmd.Register(modelcmd.Wrap(NewRollbackCommand(deps.Rollback)))
+	return resourcesCmd
+}
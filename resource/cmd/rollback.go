@@ -0,0 +1,116 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cmd
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/resource"
+)
+
+// RollbackClient has the API client methods needed by RollbackCommand.
+type RollbackClient interface {
+	// RollbackResource makes a previous revision of the resource the
+	// active one, and returns it. If revision is empty then the most
+	// recent previous revision is used.
+	RollbackResource(service, name, revision string) (resource.Resource, error)
+
+	// Close closes the client.
+	Close() error
+}
+
+// RollbackDeps is a type that contains external functions that Rollback
+// depends on to function.
+type RollbackDeps struct {
+	// NewClient returns the value that wraps the API for rolling back
+	// resources on the server.
+	NewClient func(*RollbackCommand) (RollbackClient, error)
+}
+
+// RollbackCommand implements the "resources rollback" command.
+type RollbackCommand struct {
+	deps RollbackDeps
+	modelcmd.ModelCommandBase
+	service  string
+	resource string
+	revision string
+}
+
+// NewRollbackCommand returns a new command that restores the previous
+// revision of a service's resource.
+func NewRollbackCommand(deps RollbackDeps) *RollbackCommand {
+	return &RollbackCommand{deps: deps}
+}
+
+// Info implements cmd.Command.Info
+func (c *RollbackCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "rollback",
+		Args:    "service resource",
+		Purpose: "restore a previous revision of a service's resource",
+		Doc: `
+This command makes a revision of a resource that was active before the
+current one the active one again. Units get the restored revision the
+next time they run resource-get.
+
+By default the revision that was active before the current one is
+restored. Use --revision to pick an older one instead, as shown in the
+REVISION column of list-resources. The five most recent previous
+revisions are kept.
+
+The replaced revision is kept as a previous one in turn, so running the
+command again without --revision undoes the rollback.
+`,
+	}
+}
+
+// SetFlags implements cmd.Command.SetFlags.
+func (c *RollbackCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.revision, "revision", "", "the previous revision of the resource to restore")
+}
+
+// Init implements cmd.Command.Init. It will return an error satisfying
+// errors.BadRequest if you give it an incorrect number of arguments.
+func (c *RollbackCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.BadRequestf("missing service name")
+	case 1:
+		return errors.BadRequestf("missing resource name")
+	}
+
+	if !names.IsValidService(args[0]) {
+		return errors.NotValidf("service name %q", args[0])
+	}
+	if args[1] == "" {
+		return errors.NewNotValid(nil, "missing resource name")
+	}
+	c.service = args[0]
+	c.resource = args[1]
+
+	if err := cmd.CheckEmpty(args[2:]); err != nil {
+		return errors.NewBadRequest(err, "")
+	}
+	return nil
+}
+
+// Run implements cmd.Command.Run.
+func (c *RollbackCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.deps.NewClient(c)
+	if err != nil {
+		return errors.Annotatef(err, "can't connect to %s", c.ConnectionName())
+	}
+	defer apiclient.Close()
+
+	res, err := apiclient.RollbackResource(c.service, c.resource, c.revision)
+	if err != nil {
+		return errors.Annotatef(err, "failed to roll back resource %q", c.resource)
+	}
+	ctx.Infof("resource %q of service %q rolled back to revision %s", c.resource, c.service, res.RevisionString())
+	return nil
+}
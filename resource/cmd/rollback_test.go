@@ -0,0 +1,154 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cmd
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
+
+	"github.com/juju/juju/resource"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&RollbackSuite{})
+
+type RollbackSuite struct {
+	testing.IsolationSuite
+
+	stub   *testing.Stub
+	client *stubRollbackClient
+}
+
+func (s *RollbackSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.stub = &testing.Stub{}
+	s.client = &stubRollbackClient{stub: s.stub}
+}
+
+func (s *RollbackSuite) newCommand() *RollbackCommand {
+	return NewRollbackCommand(RollbackDeps{
+		NewClient: func(c *RollbackCommand) (RollbackClient, error) {
+			s.stub.AddCall("NewClient", c)
+			if err := s.stub.NextErr(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			return s.client, nil
+		},
+	})
+}
+
+func (s *RollbackSuite) TestInfo(c *gc.C) {
+	info := s.newCommand().Info()
+	c.Check(info.Name, gc.Equals, "rollback")
+	c.Check(info.Args, gc.Equals, "service resource")
+}
+
+func (s *RollbackSuite) TestInitEmpty(c *gc.C) {
+	err := s.newCommand().Init([]string{})
+	c.Check(err, jc.Satisfies, errors.IsBadRequest)
+}
+
+func (s *RollbackSuite) TestInitOneArg(c *gc.C) {
+	err := s.newCommand().Init([]string{"svc"})
+	c.Check(err, jc.Satisfies, errors.IsBadRequest)
+}
+
+func (s *RollbackSuite) TestInitBadService(c *gc.C) {
+	err := s.newCommand().Init([]string{"svc/0", "spam"})
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *RollbackSuite) TestInitTooManyArgs(c *gc.C) {
+	err := s.newCommand().Init([]string{"svc", "spam", "eggs"})
+	c.Check(err, jc.Satisfies, errors.IsBadRequest)
+}
+
+func (s *RollbackSuite) TestInitGood(c *gc.C) {
+	command := s.newCommand()
+	err := command.Init([]string{"svc", "spam"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(command.service, gc.Equals, "svc")
+	c.Check(command.resource, gc.Equals, "spam")
+}
+
+func (s *RollbackSuite) TestRun(c *gc.C) {
+	s.client.ReturnRollbackResource = resource.Resource{
+		Resource: charmresource.Resource{
+			Origin:   charmresource.OriginStore,
+			Revision: 3,
+		},
+	}
+	command := s.newCommand()
+	command.service = "svc"
+	command.resource = "spam"
+	ctx := coretesting.Context(c)
+
+	err := command.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "NewClient", "RollbackResource", "Close")
+	s.stub.CheckCall(c, 1, "RollbackResource", "svc", "spam", "")
+	c.Check(coretesting.Stderr(ctx), gc.Equals, "resource \"spam\" of service \"svc\" rolled back to revision 3\n")
+}
+
+func (s *RollbackSuite) TestRunRevision(c *gc.C) {
+	s.client.ReturnRollbackResource = resource.Resource{
+		Resource: charmresource.Resource{
+			Origin:   charmresource.OriginStore,
+			Revision: 1,
+		},
+	}
+	command := s.newCommand()
+	err := coretesting.InitCommand(command, []string{"--revision", "1", "svc", "spam"})
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := coretesting.Context(c)
+
+	err = command.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "NewClient", "RollbackResource", "Close")
+	s.stub.CheckCall(c, 1, "RollbackResource", "svc", "spam", "1")
+	c.Check(coretesting.Stderr(ctx), gc.Equals, "resource \"spam\" of service \"svc\" rolled back to revision 1\n")
+}
+
+func (s *RollbackSuite) TestRunError(c *gc.C) {
+	failure := errors.New("<failure>")
+	s.stub.SetErrors(nil, failure)
+	command := s.newCommand()
+	command.service = "svc"
+	command.resource = "spam"
+
+	err := command.Run(coretesting.Context(c))
+
+	c.Check(err, gc.ErrorMatches, `failed to roll back resource "spam": <failure>`)
+	s.stub.CheckCallNames(c, "NewClient", "RollbackResource", "Close")
+}
+
+type stubRollbackClient struct {
+	stub *testing.Stub
+
+	ReturnRollbackResource resource.Resource
+}
+
+func (s *stubRollbackClient) RollbackResource(service, name, revision string) (resource.Resource, error) {
+	s.stub.AddCall("RollbackResource", service, name, revision)
+	if err := s.stub.NextErr(); err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+
+	return s.ReturnRollbackResource, nil
+}
+
+func (s *stubRollbackClient) Close() error {
+	s.stub.AddCall("Close")
+	if err := s.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
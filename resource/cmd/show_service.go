@@ -49,7 +49,6 @@ func NewShowServiceCommand(deps ShowServiceDeps) *ShowServiceCommand {
 func (c *ShowServiceCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "list-resources",
-		Aliases: []string{"resources"},
 		Args:    "service-or-unit",
 		Purpose: "show the resources for a service or unit",
 		Doc: `
@@ -62,7 +62,6 @@ func (s *ShowServiceSuite) TestInfo(c *gc.C) {
 
 	c.Check(info, jc.DeepEquals, &jujucmd.Info{
 		Name:    "list-resources",
-		Aliases: []string{"resources"},
 		Args:    "service-or-unit",
 		Purpose: "show the resources for a service or unit",
 		Doc: `
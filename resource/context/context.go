@@ -28,6 +28,15 @@ type APIClient interface {
 	// GetResource returns the resource info and content for the given
 	// name (and unit-implied service).
 	GetResource(resourceName string) (resource.Resource, io.ReadCloser, error)
+
+	// GetResourceInfo returns the resource info for the given name
+	// (and unit-implied service), without its content.
+	GetResourceInfo(resourceName string) (resource.Resource, error)
+
+	// SetUnitResource records that the unit is using the current
+	// revision of the named resource. It fails if that revision does
+	// not have the given fingerprint.
+	SetUnitResource(resourceName string, fingerprint charmresource.Fingerprint) error
 }
 
 // Content is the resources portion of a uniter hook context.
@@ -39,13 +48,20 @@ type Context struct {
 	//
 	//   /var/lib/juju/agents/unit-spam-1/resources
 	dataDir string
+
+	// cacheDir is the path to the machine-level directory where
+	// resource content is cached for all units on the machine. If it
+	// is empty then resources are not cached.
+	cacheDir string
 }
 
-// NewContextAPI returns a new Content for the given API client and data dir.
-func NewContextAPI(apiClient APIClient, dataDir string) *Context {
+// NewContextAPI returns a new Content for the given API client,
+// data dir, and cache dir.
+func NewContextAPI(apiClient APIClient, dataDir, cacheDir string) *Context {
 	return &Context{
 		apiClient: apiClient,
 		dataDir:   dataDir,
+		cacheDir:  cacheDir,
 	}
 }
 
@@ -64,6 +80,7 @@ func (c *Context) Download(name string) (string, error) {
 		APIClient: c.apiClient,
 		name:      name,
 		dataDir:   c.dataDir,
+		cacheDir:  c.cacheDir,
 	}
 	path, err := internal.ContextDownload(deps)
 	if err != nil {
@@ -76,8 +93,9 @@ func (c *Context) Download(name string) (string, error) {
 // of ContextDownload().
 type contextDeps struct {
 	APIClient
-	name    string
-	dataDir string
+	name     string
+	dataDir  string
+	cacheDir string
 }
 
 func (deps *contextDeps) NewContextDirectorySpec() internal.ContextDirectorySpec {
@@ -85,7 +103,11 @@ func (deps *contextDeps) NewContextDirectorySpec() internal.ContextDirectorySpec
 }
 
 func (deps *contextDeps) OpenResource() (internal.ContextOpenedResource, error) {
-	return internal.OpenResource(deps.name, deps)
+	if deps.cacheDir == "" {
+		return internal.OpenResource(deps.name, deps)
+	}
+	cache := internal.NewCache(deps.cacheDir, deps)
+	return internal.OpenCachedResource(deps.name, cache, deps, logger)
 }
 
 func (deps *contextDeps) Download(target internal.DownloadTarget, remote internal.ContextOpenedResource) error {
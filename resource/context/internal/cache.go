@@ -0,0 +1,206 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internal
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
+
+	"github.com/juju/juju/resource"
+)
+
+// Cache is a machine-level store of resource content, shared by all
+// the units on the machine. Entries are keyed by fingerprint, so
+// identical content is only ever downloaded once, regardless of
+// which service or resource it belongs to.
+type Cache struct {
+	dir  string
+	deps CacheDeps
+}
+
+// NewCache returns a new cache rooted at the given directory.
+func NewCache(dir string, deps CacheDeps) *Cache {
+	return &Cache{
+		dir:  dir,
+		deps: deps,
+	}
+}
+
+// CacheDeps exposes the external functionality needed by Cache.
+type CacheDeps interface {
+	// NewChecker provides a content checker for the given content.
+	NewChecker(Content) ContentChecker
+}
+
+func (c Cache) filename(fp charmresource.Fingerprint) string {
+	return filepath.Join(c.dir, fp.String())
+}
+
+// Open returns a reader for the cached data matching the content's
+// size and fingerprint. If there is no such entry then
+// errors.NotFound is returned. An entry that does not match is
+// removed from the cache and reported as not found.
+func (c Cache) Open(content Content) (io.ReadCloser, error) {
+	if content.Fingerprint.IsZero() {
+		return nil, errors.NotFoundf("cached resource")
+	}
+	filename := c.filename(content.Fingerprint)
+
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("cached resource %q", content.Fingerprint)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := c.deps.NewChecker(content)
+	if _, err := io.Copy(ioutil.Discard, checker.WrapReader(file)); err != nil {
+		file.Close()
+		return nil, errors.Annotate(err, "could not read cached resource")
+	}
+	if err := checker.Verify(); err != nil {
+		file.Close()
+		if err := os.Remove(filename); err != nil {
+			return nil, errors.Annotate(err, "could not remove corrupt cached resource")
+		}
+		return nil, errors.NotFoundf("cached resource %q (removed corrupt entry)", content.Fingerprint)
+	}
+
+	if _, err := file.Seek(0, os.SEEK_SET); err != nil {
+		file.Close()
+		return nil, errors.Trace(err)
+	}
+	return file, nil
+}
+
+// Add wraps the provided reader so that the data read through it is
+// also written to the cache. The entry is only added when the reader
+// is closed, and then only if all the data was read and it matches
+// the content's size and fingerprint.
+func (c Cache) Add(content Content, reader io.ReadCloser) (io.ReadCloser, error) {
+	if content.Fingerprint.IsZero() {
+		return reader, nil
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	file, err := ioutil.TempFile(c.dir, ".download-")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := c.deps.NewChecker(content)
+	tee := io.TeeReader(reader, file)
+	cw := &cacheWriter{
+		Reader:   checker.WrapReader(tee),
+		source:   reader,
+		file:     file,
+		checker:  checker,
+		filename: c.filename(content.Fingerprint),
+	}
+	return cw, nil
+}
+
+type cacheWriter struct {
+	io.Reader
+	source   io.Closer
+	file     *os.File
+	checker  ContentChecker
+	filename string
+}
+
+// Close implements io.Closer. It moves the downloaded data into
+// place in the cache if (and only if) it is complete and correct.
+func (cw *cacheWriter) Close() error {
+	sourceErr := cw.source.Close()
+
+	tempname := cw.file.Name()
+	err := cw.file.Close()
+	if err == nil {
+		err = cw.checker.Verify()
+	}
+	if err == nil {
+		err = os.Rename(tempname, cw.filename)
+	}
+	if err != nil {
+		os.Remove(tempname)
+	}
+
+	if sourceErr != nil {
+		return errors.Trace(sourceErr)
+	}
+	return nil
+}
+
+// CachedResourceClient exposes the API functionality needed
+// by OpenCachedResource.
+type CachedResourceClient interface {
+	OpenedResourceClient
+
+	// GetResourceInfo returns the info for the given resource name
+	// (and unit-implied service), without its content.
+	GetResourceInfo(resourceName string) (resource.Resource, error)
+
+	// SetUnitResource records that the unit is using the current
+	// revision of the named resource. It fails if that revision does
+	// not have the given fingerprint.
+	SetUnitResource(resourceName string, fingerprint charmresource.Fingerprint) error
+}
+
+// OpenCachedResource opens the identified resource, using the
+// machine-level cache if it already holds the resource's content.
+// Otherwise the resource is downloaded using the provided client
+// and added to the cache as it is read.
+func OpenCachedResource(name string, cache *Cache, client CachedResourceClient, logger Logger) (*OpenedResource, error) {
+	info, err := client.GetResourceInfo(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if !info.IsPlaceholder() {
+		content := Content{
+			Size:        info.Size,
+			Fingerprint: info.Fingerprint,
+		}
+		reader, err := cache.Open(content)
+		switch {
+		case err == nil:
+			// The controller only knows that the unit has the
+			// resource once it has been downloaded, so we must
+			// tell it ourselves. The resource may have changed
+			// since we got its info, so the controller checks
+			// that the cached content is still current.
+			if err := client.SetUnitResource(name, info.Fingerprint); err != nil {
+				logger.Errorf("could not record use of cached resource %q (downloading instead): %v", name, err)
+				reader.Close()
+				break
+			}
+			or := &OpenedResource{
+				Resource:   info,
+				ReadCloser: reader,
+			}
+			return or, nil
+		case !errors.IsNotFound(err):
+			logger.Errorf("could not open cached resource %q: %v", name, err)
+		}
+	}
+
+	opened, err := OpenResource(name, client)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	reader, err := cache.Add(opened.Content(), opened.ReadCloser)
+	if err != nil {
+		logger.Errorf("could not cache resource %q: %v", name, err)
+		return opened, nil
+	}
+	opened.ReadCloser = reader
+	return opened, nil
+}
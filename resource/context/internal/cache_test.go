@@ -0,0 +1,197 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internal_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
+
+	"github.com/juju/juju/resource/context/internal"
+)
+
+var _ = gc.Suite(&CacheSuite{})
+
+type CacheSuite struct {
+	testing.IsolationSuite
+
+	stub *internalStub
+	dir  string
+}
+
+func (s *CacheSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.stub = newInternalStub()
+	s.dir = filepath.Join(c.MkDir(), "resources")
+}
+
+func (s *CacheSuite) newContent(c *gc.C, data string) internal.Content {
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	return internal.Content{
+		Data:        strings.NewReader(data),
+		Size:        int64(len(data)),
+		Fingerprint: fp,
+	}
+}
+
+func (s *CacheSuite) addToCache(c *gc.C, cache *internal.Cache, data string) internal.Content {
+	content := s.newContent(c, data)
+	reader, err := cache.Add(content, ioutil.NopCloser(content.Data))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = io.Copy(ioutil.Discard, reader)
+	c.Assert(err, jc.ErrorIsNil)
+	err = reader.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	return content
+}
+
+func (s *CacheSuite) checkCached(c *gc.C, cache *internal.Cache, content internal.Content, expected string) {
+	reader, err := cache.Open(content)
+	c.Assert(err, jc.ErrorIsNil)
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, expected)
+}
+
+func (s *CacheSuite) TestOpenNotFound(c *gc.C) {
+	cache := internal.NewCache(s.dir, &checkerDeps{})
+	content := s.newContent(c, "some data")
+
+	_, err := cache.Open(content)
+
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CacheSuite) TestAddThenOpen(c *gc.C) {
+	cache := internal.NewCache(s.dir, &checkerDeps{})
+	content := s.addToCache(c, cache, "some data")
+
+	s.checkCached(c, cache, content, "some data")
+	entries, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Check(entries[0].Name(), gc.Equals, content.Fingerprint.String())
+}
+
+func (s *CacheSuite) TestAddIncomplete(c *gc.C) {
+	cache := internal.NewCache(s.dir, &checkerDeps{})
+	content := s.newContent(c, "some data")
+
+	reader, err := cache.Add(content, ioutil.NopCloser(content.Data))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = reader.Read(make([]byte, 4))
+	c.Assert(err, jc.ErrorIsNil)
+	err = reader.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = cache.Open(content)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	entries, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(entries, gc.HasLen, 0)
+}
+
+func (s *CacheSuite) TestOpenCorrupt(c *gc.C) {
+	cache := internal.NewCache(s.dir, &checkerDeps{})
+	content := s.addToCache(c, cache, "some data")
+	filename := filepath.Join(s.dir, content.Fingerprint.String())
+	err := ioutil.WriteFile(filename, []byte("more data"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = cache.Open(content)
+
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	_, err = os.Stat(filename)
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
+
+func (s *CacheSuite) TestOpenCachedResourceHit(c *gc.C) {
+	cache := internal.NewCache(s.dir, &checkerDeps{})
+	s.addToCache(c, cache, "some data")
+	info, _ := newResource(c, &testing.Stub{}, "spam", "some data")
+	s.stub.ReturnGetResourceInfo = info
+	logger := &stubLogger{Stub: s.stub.Stub}
+
+	opened, err := internal.OpenCachedResource("spam", cache, s.stub, logger)
+	c.Assert(err, jc.ErrorIsNil)
+	defer opened.Close()
+
+	s.stub.CheckCallNames(c, "GetResourceInfo", "SetUnitResource")
+	s.stub.CheckCall(c, 1, "SetUnitResource", "spam", info.Fingerprint)
+	c.Check(opened.Resource, jc.DeepEquals, info)
+	data, err := ioutil.ReadAll(opened)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "some data")
+}
+
+func (s *CacheSuite) TestOpenCachedResourceMiss(c *gc.C) {
+	cache := internal.NewCache(s.dir, &checkerDeps{})
+	info, reader := newResource(c, &testing.Stub{}, "spam", "some data")
+	s.stub.ReturnGetResourceInfo = info
+	s.stub.ReturnGetResourceData = reader
+	logger := &stubLogger{Stub: s.stub.Stub}
+
+	opened, err := internal.OpenCachedResource("spam", cache, s.stub, logger)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = io.Copy(ioutil.Discard, opened)
+	c.Assert(err, jc.ErrorIsNil)
+	err = opened.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "GetResourceInfo", "GetResource")
+	c.Check(opened.Resource, jc.DeepEquals, info)
+	s.checkCached(c, cache, opened.Content(), "some data")
+}
+
+func (s *CacheSuite) TestOpenCachedResourceSetUnitResourceFailed(c *gc.C) {
+	cache := internal.NewCache(s.dir, &checkerDeps{})
+	s.addToCache(c, cache, "some data")
+	info, reader := newResource(c, &testing.Stub{}, "spam", "some data")
+	s.stub.ReturnGetResourceInfo = info
+	s.stub.ReturnGetResourceData = reader
+	logger := &stubLogger{Stub: s.stub.Stub}
+	failure := errors.New("<failure>")
+	s.stub.SetErrors(nil, failure)
+
+	opened, err := internal.OpenCachedResource("spam", cache, s.stub, logger)
+	c.Assert(err, jc.ErrorIsNil)
+	defer opened.Close()
+
+	s.stub.CheckCallNames(c, "GetResourceInfo", "SetUnitResource", "Errorf", "GetResource")
+	c.Check(logger.logged, gc.Equals, `could not record use of cached resource "spam" (downloading instead): <failure>`)
+}
+
+func (s *CacheSuite) TestOpenCachedResourcePlaceholder(c *gc.C) {
+	cache := internal.NewCache(s.dir, &checkerDeps{})
+	info, _ := newResource(c, &testing.Stub{}, "spam", "")
+	s.stub.ReturnGetResourceInfo = info
+	logger := &stubLogger{Stub: s.stub.Stub}
+	notFound := errors.NotFoundf("resource")
+	s.stub.SetErrors(nil, notFound)
+
+	_, err := internal.OpenCachedResource("spam", cache, s.stub, logger)
+
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	s.stub.CheckCallNames(c, "GetResourceInfo", "GetResource")
+}
+
+// checkerDeps provides real content checkers to the cache.
+type checkerDeps struct{}
+
+func (checkerDeps) NewChecker(content internal.Content) internal.ContentChecker {
+	var sizer utils.SizeTracker
+	return internal.NewContentChecker(content, &sizer, charmresource.NewFingerprintHash())
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
 
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/context/internal"
@@ -43,6 +44,24 @@ func (s *internalStub) GetResource(name string) (resource.Resource, io.ReadClose
 	return s.ReturnGetResourceInfo, s.ReturnGetResourceData, nil
 }
 
+func (s *internalStub) GetResourceInfo(name string) (resource.Resource, error) {
+	s.Stub.AddCall("GetResourceInfo", name)
+	if err := s.Stub.NextErr(); err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+
+	return s.ReturnGetResourceInfo, nil
+}
+
+func (s *internalStub) SetUnitResource(name string, fingerprint charmresource.Fingerprint) error {
+	s.Stub.AddCall("SetUnitResource", name, fingerprint)
+	if err := s.Stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
 func (s *internalStub) NewContextDirectorySpec() internal.ContextDirectorySpec {
 	s.Stub.AddCall("NewContextDirectorySpec")
 	s.Stub.NextErr() // Pop one off.
@@ -5,6 +5,7 @@ package persistence
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/juju/errors"
@@ -19,6 +20,12 @@ const (
 	resourcesC = "resources"
 
 	stagedIDSuffix = "#staged"
+
+	previousIDSuffix = "#previous"
+
+	// previousRevisionsKept is the number of previous revisions of
+	// a resource that are recorded for rolling back.
+	previousRevisionsKept = 5
 )
 
 // resourceID converts an external resource ID into an internal one.
@@ -46,6 +53,12 @@ func stagedID(id string) string {
 	return serviceResourceID(id) + stagedIDSuffix
 }
 
+// previousID converts an external resource ID into an internal one
+// for one of the resource's previous revisions.
+func previousID(id string, seq int) string {
+	return fmt.Sprintf("%s%s-%d", serviceResourceID(id), previousIDSuffix, seq)
+}
+
 // storedResource holds all model-stored information for a resource.
 type storedResource struct {
 	resource.Resource
@@ -158,6 +171,71 @@ func newResolvePendingResourceOps(pending storedResource, exists bool) []txn.Op
 	}
 }
 
+// newAddPreviousResourceOps generates transaction operations that
+// record the provided resource doc as the most recent previous
+// revision of the resource, using the given sequence number. The
+// oldest of the already recorded previous revisions are dropped so
+// that no more than previousRevisionsKept remain.
+func newAddPreviousResourceOps(current resourceDoc, seq int, previous []resourceDoc) []txn.Op {
+	doc := current
+	doc.DocID = previousID(current.ID, seq)
+	doc.PreviousSeq = seq
+
+	ops := []txn.Op{{
+		C:      resourcesC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	for len(previous) >= previousRevisionsKept {
+		ops = append(ops, txn.Op{
+			C:      resourcesC,
+			Id:     previous[0].DocID,
+			Assert: txn.DocExists,
+			Remove: true,
+		})
+		previous = previous[1:]
+	}
+	return ops
+}
+
+// newRollbackResourceOps generates transaction operations that make
+// the target previous revision the active resource doc, and record
+// the replaced doc as the most recent previous revision. The other
+// previous revisions must not include the target.
+func newRollbackResourceOps(current, target resourceDoc, seq int, others []resourceDoc) []txn.Op {
+	active := target
+	active.DocID = serviceResourceID(current.ID)
+	active.PreviousSeq = 0
+
+	ops := []txn.Op{{
+		C:      resourcesC,
+		Id:     active.DocID,
+		Assert: bson.D{{"storage-path", current.StoragePath}},
+		Remove: true,
+	}, {
+		C:      resourcesC,
+		Id:     active.DocID,
+		Assert: txn.DocMissing,
+		Insert: &active,
+	}, {
+		C:      resourcesC,
+		Id:     target.DocID,
+		Assert: bson.D{{"storage-path", target.StoragePath}},
+		Remove: true,
+	}}
+	return append(ops, newAddPreviousResourceOps(current, seq, others)...)
+}
+
+// nextPreviousSeq returns the sequence number to use for the next
+// previous revision recorded after the provided ones.
+func nextPreviousSeq(previous []resourceDoc) int {
+	if len(previous) == 0 {
+		return 1
+	}
+	return previous[len(previous)-1].PreviousSeq + 1
+}
+
 // newUnitResourceDoc generates a doc that represents the given resource.
 func newUnitResourceDoc(unitID string, stored storedResource) *resourceDoc {
 	fullID := unitResourceID(stored.ID, unitID)
@@ -213,6 +291,30 @@ func (p Persistence) getOnePending(resID, pendingID string) (resourceDoc, error)
 	return doc, nil
 }
 
+// getPrevious returns the docs for the previous revisions of the
+// resource that matches the provided model ID, oldest first.
+func (p Persistence) getPrevious(resID string) ([]resourceDoc, error) {
+	logger.Tracef("querying db for previous revisions of resource %q", resID)
+	var docs []resourceDoc
+	query := bson.D{
+		{"resource-id", resID},
+		{"previous-seq", bson.D{{"$gt", 0}}},
+	}
+	if err := p.base.All(resourcesC, query, &docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Sort(byPreviousSeq(docs))
+	return docs, nil
+}
+
+// byPreviousSeq sorts resource docs by their previous revision
+// sequence number.
+type byPreviousSeq []resourceDoc
+
+func (docs byPreviousSeq) Len() int           { return len(docs) }
+func (docs byPreviousSeq) Swap(i, j int)      { docs[i], docs[j] = docs[j], docs[i] }
+func (docs byPreviousSeq) Less(i, j int) bool { return docs[i].PreviousSeq < docs[j].PreviousSeq }
+
 // resourceDoc is the top-level document for resources.
 type resourceDoc struct {
 	DocID     string `bson:"_id"`
@@ -237,6 +339,10 @@ type resourceDoc struct {
 	Timestamp time.Time `bson:"timestamp-when-added"`
 
 	StoragePath string `bson:"storage-path"`
+
+	// PreviousSeq orders the previous revisions of the resource. It
+	// is only set on docs that record a previous revision.
+	PreviousSeq int `bson:"previous-seq,omitempty"`
 }
 
 func unitResource2Doc(id, unitID string, stored storedResource) *resourceDoc {
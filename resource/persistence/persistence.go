@@ -63,6 +63,10 @@ func (p Persistence) ListResources(serviceID string) (resource.ServiceResources,
 		if doc.PendingID != "" {
 			continue
 		}
+		if doc.PreviousSeq > 0 {
+			// Previous revisions are only kept for rolling back.
+			continue
+		}
 
 		res, err := doc2basicResource(doc)
 		if err != nil {
@@ -124,6 +128,74 @@ func (p Persistence) GetResource(id string) (res resource.Resource, storagePath
 	return stored.Resource, stored.storagePath, nil
 }
 
+// GetPreviousResources returns the extended, model-related info for
+// the recorded previous revisions of the resource, oldest first, along
+// with the storage path of each.
+func (p Persistence) GetPreviousResources(id string) ([]resource.Resource, []string, error) {
+	docs, err := p.getPrevious(id)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	var resources []resource.Resource
+	var storagePaths []string
+	for _, doc := range docs {
+		stored, err := doc2resource(doc)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		resources = append(resources, stored.Resource)
+		storagePaths = append(storagePaths, stored.storagePath)
+	}
+	return resources, storagePaths, nil
+}
+
+// RollbackResource makes a previous revision of the identified
+// resource the active one and returns its info. If revision is empty
+// then the most recent previous revision is used, otherwise the most
+// recent one whose revision string matches. The replaced revision is
+// recorded as the most recent previous one in turn, so rolling back
+// again undoes the rollback. If no matching previous revision has been
+// recorded then errors.NotFound is returned.
+func (p Persistence) RollbackResource(id, revision string) (resource.Resource, error) {
+	var res resource.Resource
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		current, err := p.getOne(id)
+		if errors.IsNotFound(err) {
+			return nil, errors.NotFoundf("resource %q", id)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		previous, err := p.getPrevious(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for i := len(previous) - 1; i >= 0; i-- {
+			candidate, err := doc2basicResource(previous[i])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if revision != "" && candidate.RevisionString() != revision {
+				continue
+			}
+			res = candidate
+			seq := nextPreviousSeq(previous)
+			others := make([]resourceDoc, 0, len(previous)-1)
+			others = append(others, previous[:i]...)
+			others = append(others, previous[i+1:]...)
+			return newRollbackResourceOps(current, previous[i], seq, others), nil
+		}
+		if revision != "" {
+			return nil, errors.NotFoundf("revision %s of resource %q", revision, id)
+		}
+		return nil, errors.NotFoundf("previous revision of resource %q", id)
+	}
+	if err := p.base.Run(buildTxn); err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+	return res, nil
+}
+
 // StageResource adds the resource in a separate staging area
 // if the resource isn't already staged. If it is then
 // errors.AlreadyExists is returned. A wrapper around the staged
@@ -246,7 +318,9 @@ func (p Persistence) getStored(res resource.Resource) (storedResource, error) {
 }
 
 // NewResolvePendingResourceOps generates mongo transaction operations
-// to set the identified resource as active.
+// to set the identified resource as active. If the replaced resource
+// had content stored then it is recorded as the most recent previous
+// revision of the resource.
 //
 // Leaking mongo details (transaction ops) is a necessary evil since we
 // do not have any machinery to facilitate transactions between
@@ -269,12 +343,23 @@ func (p Persistence) NewResolvePendingResourceOps(resID, pendingID string) ([]tx
 	}
 
 	exists := true
-	if _, err := p.getOne(resID); errors.IsNotFound(err) {
+	current, err := p.getOne(resID)
+	if errors.IsNotFound(err) {
 		exists = false
 	} else if err != nil {
 		return nil, errors.Trace(err)
 	}
 
 	ops := newResolvePendingResourceOps(pending, exists)
+	if exists && current.StoragePath != "" && current.StoragePath != pending.storagePath {
+		// Note that the content of any previous revision dropped here
+		// is left in storage, since removing it cannot be part of the
+		// transaction.
+		previous, err := p.getPrevious(resID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, newAddPreviousResourceOps(current, nextPreviousSeq(previous), previous)...)
+	}
 	return ops, nil
 }
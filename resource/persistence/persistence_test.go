@@ -4,6 +4,7 @@
 package persistence
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/juju/errors"
@@ -94,6 +95,19 @@ func (s *PersistenceSuite) TestListResourcesIgnorePending(c *gc.C) {
 	checkResources(c, resources, expected)
 }
 
+func (s *PersistenceSuite) TestListResourcesIgnorePrevious(c *gc.C) {
+	expected, docs := newResources(c, "a-service", "spam", "eggs")
+	docs = append(docs, newPreviousDoc(docs[0], 1))
+	s.base.docs = docs
+	p := NewPersistence(s.base)
+
+	resources, err := p.ListResources("a-service")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "All")
+	checkResources(c, resources, expected)
+}
+
 func (s *PersistenceSuite) TestListResourcesBaseError(c *gc.C) {
 	failure := errors.New("<failure>")
 	s.stub.SetErrors(failure)
@@ -178,6 +192,172 @@ func (s *PersistenceSuite) TestGetResourceOkay(c *gc.C) {
 	c.Check(storagePath, gc.Equals, expected.storagePath)
 }
 
+func (s *PersistenceSuite) TestGetPreviousResourcesOkay(c *gc.C) {
+	expected, doc := newResource(c, "a-service", "spam")
+	older := newPreviousDoc(doc, 1)
+	older.Username = "another-user"
+	newer := newPreviousDoc(doc, 2)
+	s.base.docs = []resourceDoc{newer, older}
+	p := NewPersistence(s.base)
+
+	resources, storagePaths, err := p.GetPreviousResources("a-service/spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "All")
+	s.stub.CheckCall(c, 0, "All",
+		"resources",
+		bson.D{
+			{"resource-id", "a-service/spam"},
+			{"previous-seq", bson.D{{"$gt", 0}}},
+		},
+		&[]resourceDoc{older, newer},
+	)
+	olderRes := expected.Resource
+	olderRes.Username = "another-user"
+	c.Check(resources, jc.DeepEquals, []resource.Resource{olderRes, expected.Resource})
+	c.Check(storagePaths, jc.DeepEquals, []string{expected.storagePath, expected.storagePath})
+}
+
+func (s *PersistenceSuite) TestGetPreviousResourcesNone(c *gc.C) {
+	p := NewPersistence(s.base)
+
+	resources, storagePaths, err := p.GetPreviousResources("a-service/spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(resources, gc.HasLen, 0)
+	c.Check(storagePaths, gc.HasLen, 0)
+	s.stub.CheckCallNames(c, "All")
+}
+
+func (s *PersistenceSuite) TestRollbackResourceOkay(c *gc.C) {
+	_, current := newResource(c, "a-service", "spam")
+	current.StoragePath += "-some-unique-ID-003"
+	expected, doc := newResource(c, "a-service", "spam")
+	expected.Username = "another-user"
+	older := newPreviousDoc(doc, 1)
+	older.StoragePath += "-some-unique-ID-001"
+	newer := newPreviousDoc(doc, 2)
+	newer.StoragePath += "-some-unique-ID-002"
+	newer.Username = "another-user"
+	s.base.ReturnOne = current
+	s.base.docs = []resourceDoc{older, newer}
+	ignoredErr := errors.New("<never reached>")
+	s.stub.SetErrors(nil, nil, nil, nil, ignoredErr)
+	p := NewPersistence(s.base)
+
+	res, err := p.RollbackResource("a-service/spam", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "Run", "One", "All", "RunTransaction")
+	active := newer // a copy
+	active.DocID = "resource#a-service/spam"
+	active.PreviousSeq = 0
+	replaced := newPreviousDoc(current, 3)
+	s.stub.CheckCall(c, 3, "RunTransaction", []txn.Op{{
+		C:      "resources",
+		Id:     "resource#a-service/spam",
+		Assert: bson.D{{"storage-path", "service-a-service/resources/spam-some-unique-ID-003"}},
+		Remove: true,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam",
+		Assert: txn.DocMissing,
+		Insert: &active,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#previous-2",
+		Assert: bson.D{{"storage-path", "service-a-service/resources/spam-some-unique-ID-002"}},
+		Remove: true,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#previous-3",
+		Assert: txn.DocMissing,
+		Insert: &replaced,
+	}})
+	c.Check(res, jc.DeepEquals, expected.Resource)
+}
+
+func (s *PersistenceSuite) TestRollbackResourceRevision(c *gc.C) {
+	_, current := newResource(c, "a-service", "spam")
+	current.Origin = "store"
+	current.Revision = 3
+	current.StoragePath += "-some-unique-ID-003"
+	_, doc := newResource(c, "a-service", "spam")
+	doc.Origin = "store"
+	older := newPreviousDoc(doc, 1)
+	older.Revision = 1
+	older.StoragePath += "-some-unique-ID-001"
+	newer := newPreviousDoc(doc, 2)
+	newer.Revision = 2
+	newer.StoragePath += "-some-unique-ID-002"
+	s.base.ReturnOne = current
+	s.base.docs = []resourceDoc{older, newer}
+	ignoredErr := errors.New("<never reached>")
+	s.stub.SetErrors(nil, nil, nil, nil, ignoredErr)
+	p := NewPersistence(s.base)
+
+	res, err := p.RollbackResource("a-service/spam", "1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "Run", "One", "All", "RunTransaction")
+	active := older // a copy
+	active.DocID = "resource#a-service/spam"
+	active.PreviousSeq = 0
+	replaced := newPreviousDoc(current, 3)
+	s.stub.CheckCall(c, 3, "RunTransaction", []txn.Op{{
+		C:      "resources",
+		Id:     "resource#a-service/spam",
+		Assert: bson.D{{"storage-path", "service-a-service/resources/spam-some-unique-ID-003"}},
+		Remove: true,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam",
+		Assert: txn.DocMissing,
+		Insert: &active,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#previous-1",
+		Assert: bson.D{{"storage-path", "service-a-service/resources/spam-some-unique-ID-001"}},
+		Remove: true,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#previous-3",
+		Assert: txn.DocMissing,
+		Insert: &replaced,
+	}})
+	c.Check(res.Revision, gc.Equals, 1)
+	c.Check(res.RevisionString(), gc.Equals, "1")
+}
+
+func (s *PersistenceSuite) TestRollbackResourceUnknownRevision(c *gc.C) {
+	_, current := newResource(c, "a-service", "spam")
+	current.Origin = "store"
+	current.Revision = 3
+	previous := newPreviousDoc(current, 1)
+	previous.Revision = 2
+	s.base.ReturnOne = current
+	s.base.docs = []resourceDoc{previous}
+	p := NewPersistence(s.base)
+
+	_, err := p.RollbackResource("a-service/spam", "1")
+
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `revision 1 of resource "a-service/spam" not found`)
+	s.stub.CheckCallNames(c, "Run", "One", "All")
+}
+
+func (s *PersistenceSuite) TestRollbackResourceNoPrevious(c *gc.C) {
+	_, current := newResource(c, "a-service", "spam")
+	s.base.ReturnOne = current
+	p := NewPersistence(s.base)
+
+	_, err := p.RollbackResource("a-service/spam", "")
+
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `previous revision of resource "a-service/spam" not found`)
+	s.stub.CheckCallNames(c, "Run", "One", "All")
+}
+
 func (s *PersistenceSuite) TestStageResourceOkay(c *gc.C) {
 	res, doc := newResource(c, "a-service", "spam")
 	doc.DocID += "#staged"
@@ -389,6 +569,87 @@ func (s *PersistenceSuite) TestNewResourcePendingResourceOpsExists(c *gc.C) {
 	}})
 }
 
+func (s *PersistenceSuite) TestNewResourcePendingResourceOpsSetsPrevious(c *gc.C) {
+	pendingID := "some-unique-ID-001"
+	stored, expected := newResource(c, "a-service", "spam")
+	stored.PendingID = pendingID
+	doc := expected // a copy
+	doc.DocID = pendingResourceID(stored.ID, pendingID)
+	doc.PendingID = pendingID
+	doc.StoragePath += "-" + pendingID
+	expected.StoragePath = doc.StoragePath
+	_, current := newResource(c, "a-service", "spam")
+	current.Username = "another-user"
+	s.base.ReturnOneByID = map[string]resourceDoc{
+		"resource#a-service/spam#pending-some-unique-ID-001": doc,
+		"resource#a-service/spam":                            current,
+	}
+	p := NewPersistence(s.base)
+
+	ops, err := p.NewResolvePendingResourceOps(stored.ID, stored.PendingID)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "One", "One", "All")
+	previous := newPreviousDoc(current, 1)
+	c.Check(ops, jc.DeepEquals, []txn.Op{{
+		C:      "resources",
+		Id:     doc.DocID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}, {
+		C:      "resources",
+		Id:     expected.DocID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}, {
+		C:      "resources",
+		Id:     expected.DocID,
+		Assert: txn.DocMissing,
+		Insert: &expected,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#previous-1",
+		Assert: txn.DocMissing,
+		Insert: &previous,
+	}})
+}
+
+func (s *PersistenceSuite) TestNewResourcePendingResourceOpsDropsOldestPrevious(c *gc.C) {
+	pendingID := "some-unique-ID-001"
+	stored, expected := newResource(c, "a-service", "spam")
+	stored.PendingID = pendingID
+	doc := expected // a copy
+	doc.DocID = pendingResourceID(stored.ID, pendingID)
+	doc.PendingID = pendingID
+	doc.StoragePath += "-" + pendingID
+	_, current := newResource(c, "a-service", "spam")
+	s.base.ReturnOneByID = map[string]resourceDoc{
+		"resource#a-service/spam#pending-some-unique-ID-001": doc,
+		"resource#a-service/spam":                            current,
+	}
+	for seq := 3; seq < 3+previousRevisionsKept; seq++ {
+		s.base.docs = append(s.base.docs, newPreviousDoc(current, seq))
+	}
+	p := NewPersistence(s.base)
+
+	ops, err := p.NewResolvePendingResourceOps(stored.ID, stored.PendingID)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "One", "One", "All")
+	previous := newPreviousDoc(current, 3+previousRevisionsKept)
+	c.Check(ops[3:], jc.DeepEquals, []txn.Op{{
+		C:      "resources",
+		Id:     "resource#a-service/spam#previous-8",
+		Assert: txn.DocMissing,
+		Insert: &previous,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#previous-3",
+		Assert: txn.DocExists,
+		Remove: true,
+	}})
+}
+
 func (s *PersistenceSuite) TestNewResourcePendingResourceOpsNotFound(c *gc.C) {
 	pendingID := "some-unique-ID-001"
 	stored, expected := newResource(c, "a-service", "spam")
@@ -471,6 +732,14 @@ func newResource(c *gc.C, serviceID, name string) (storedResource, resourceDoc)
 	return stored, doc
 }
 
+// newPreviousDoc returns a copy of the doc recorded as the previous
+// revision with the given sequence number.
+func newPreviousDoc(doc resourceDoc, seq int) resourceDoc {
+	doc.DocID = fmt.Sprintf("resource#%s#previous-%d", doc.ID, seq)
+	doc.PreviousSeq = seq
+	return doc
+}
+
 func checkResources(c *gc.C, resources, expected resource.ServiceResources) {
 	resMap := make(map[string]resource.Resource)
 	for _, res := range resources.Resources {
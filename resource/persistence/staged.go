@@ -59,7 +59,9 @@ func (staged StagedResource) Unstage() error {
 	return nil
 }
 
-// Activate makes the staged resource the active resource.
+// Activate makes the staged resource the active resource. If the
+// replaced resource had content stored then it is recorded as the
+// most recent previous revision of the resource.
 func (staged StagedResource) Activate() error {
 	// TODO(ericsnow) Ensure that the service is still there?
 
@@ -71,6 +73,11 @@ func (staged StagedResource) Activate() error {
 			ops = newInsertResourceOps(staged.stored)
 		case 1:
 			ops = newUpdateResourceOps(staged.stored)
+			previousOps, err := staged.newAddPreviousOps()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, previousOps...)
 		default:
 			return nil, errors.New("setting the resource failed")
 		}
@@ -83,3 +90,30 @@ func (staged StagedResource) Activate() error {
 	}
 	return nil
 }
+
+// newAddPreviousOps returns the transaction operations needed to
+// record the active resource as the most recent previous revision, if
+// it has content stored that the staged resource does not overwrite.
+// Pending resources do not replace the active one when activated, so
+// nothing is recorded for them until they are resolved.
+func (staged StagedResource) newAddPreviousOps() ([]txn.Op, error) {
+	if staged.stored.PendingID != "" {
+		return nil, nil
+	}
+	p := NewPersistence(staged.base)
+	current, err := p.getOne(staged.id)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if current.StoragePath == "" || current.StoragePath == staged.stored.storagePath {
+		// There is no content to roll back to.
+		return nil, nil
+	}
+	previous, err := p.getPrevious(staged.id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newAddPreviousResourceOps(current, nextPreviousSeq(previous), previous), nil
+}
@@ -122,12 +122,12 @@ func (s *StagedResourceSuite) TestActivateOkay(c *gc.C) {
 func (s *StagedResourceSuite) TestActivateExists(c *gc.C) {
 	staged, doc := s.newStagedResource(c, "a-service", "spam")
 	ignoredErr := errors.New("<never reached>")
-	s.stub.SetErrors(nil, txn.ErrAborted, nil, ignoredErr)
+	s.stub.SetErrors(nil, txn.ErrAborted, nil, nil, ignoredErr)
 
 	err := staged.Activate()
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.stub.CheckCallNames(c, "Run", "RunTransaction", "RunTransaction")
+	s.stub.CheckCallNames(c, "Run", "RunTransaction", "One", "RunTransaction")
 	s.stub.CheckCall(c, 1, "RunTransaction", []txn.Op{{
 		C:      "resources",
 		Id:     "resource#a-service/spam",
@@ -138,7 +138,40 @@ func (s *StagedResourceSuite) TestActivateExists(c *gc.C) {
 		Id:     "resource#a-service/spam#staged",
 		Remove: true,
 	}})
-	s.stub.CheckCall(c, 2, "RunTransaction", []txn.Op{{
+	s.stub.CheckCall(c, 2, "One", "resources", "resource#a-service/spam", &resourceDoc{})
+	s.stub.CheckCall(c, 3, "RunTransaction", []txn.Op{{
+		C:      "resources",
+		Id:     "resource#a-service/spam",
+		Assert: txn.DocExists,
+		Remove: true,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam",
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#staged",
+		Remove: true,
+	}})
+}
+
+func (s *StagedResourceSuite) TestActivateRecordsPrevious(c *gc.C) {
+	staged, doc := s.newStagedResource(c, "a-service", "spam")
+	staged.stored.storagePath = "service-a-service/resources/spam-some-unique-ID"
+	doc.StoragePath = staged.stored.storagePath
+	_, current := newResource(c, "a-service", "spam")
+	s.base.ReturnOne = current
+	s.base.docs = []resourceDoc{newPreviousDoc(current, 1)}
+	previous := newPreviousDoc(current, 2)
+	ignoredErr := errors.New("<never reached>")
+	s.stub.SetErrors(nil, txn.ErrAborted, nil, nil, nil, ignoredErr)
+
+	err := staged.Activate()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "Run", "RunTransaction", "One", "All", "RunTransaction")
+	s.stub.CheckCall(c, 4, "RunTransaction", []txn.Op{{
 		C:      "resources",
 		Id:     "resource#a-service/spam",
 		Assert: txn.DocExists,
@@ -148,6 +181,40 @@ func (s *StagedResourceSuite) TestActivateExists(c *gc.C) {
 		Id:     "resource#a-service/spam",
 		Assert: txn.DocMissing,
 		Insert: &doc,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#previous-2",
+		Assert: txn.DocMissing,
+		Insert: &previous,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#staged",
+		Remove: true,
+	}})
+}
+
+func (s *StagedResourceSuite) TestActivatePendingRecordsNothing(c *gc.C) {
+	staged, doc := s.newStagedResource(c, "a-service", "spam")
+	staged.stored.PendingID = "some-unique-ID"
+	doc.DocID = "resource#a-service/spam#pending-some-unique-ID"
+	doc.PendingID = "some-unique-ID"
+	ignoredErr := errors.New("<never reached>")
+	s.stub.SetErrors(nil, txn.ErrAborted, nil, ignoredErr)
+
+	err := staged.Activate()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "Run", "RunTransaction", "RunTransaction")
+	s.stub.CheckCall(c, 2, "RunTransaction", []txn.Op{{
+		C:      "resources",
+		Id:     "resource#a-service/spam#pending-some-unique-ID",
+		Assert: txn.DocExists,
+		Remove: true,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-service/spam#pending-some-unique-ID",
+		Assert: txn.DocMissing,
+		Insert: &doc,
 	}, {
 		C:      "resources",
 		Id:     "resource#a-service/spam#staged",
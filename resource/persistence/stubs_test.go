@@ -13,8 +13,9 @@ import (
 type stubStatePersistence struct {
 	stub *testing.Stub
 
-	docs      []resourceDoc
-	ReturnOne resourceDoc
+	docs          []resourceDoc
+	ReturnOne     resourceDoc
+	ReturnOneByID map[string]resourceDoc
 }
 
 func (s stubStatePersistence) One(collName, id string, doc interface{}) error {
@@ -24,6 +25,10 @@ func (s stubStatePersistence) One(collName, id string, doc interface{}) error {
 	}
 
 	actual := doc.(*resourceDoc)
+	if found, ok := s.ReturnOneByID[id]; ok {
+		*actual = found
+		return nil
+	}
 	*actual = s.ReturnOne
 	return nil
 }
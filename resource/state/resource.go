@@ -6,6 +6,7 @@ package state
 // TODO(ericsnow) Figure out a way to drop the txn dependency here?
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"path"
@@ -32,6 +33,16 @@ type resourcePersistence interface {
 	// non-pending resource.
 	GetResource(id string) (res resource.Resource, storagePath string, _ error)
 
+	// GetPreviousResources returns the extended, model-related info
+	// for the recorded previous revisions of the resource, oldest
+	// first, along with the storage path of each.
+	GetPreviousResources(id string) ([]resource.Resource, []string, error)
+
+	// RollbackResource makes a previous revision of the resource the
+	// active one and returns its info. If revision is empty then the
+	// most recent previous revision is used.
+	RollbackResource(id, revision string) (resource.Resource, error)
+
 	// StageResource adds the resource in a separate staging area
 	// if the resource isn't already staged. If the resource already
 	// exists then it is treated as unavailable as long as the new one
@@ -201,7 +212,24 @@ func (st resourceState) storeResource(res resource.Resource, r io.Reader) error
 	// is stored separately and adding to both should be an atomic
 	// operation.
 
-	storagePath := storagePath(res.Name, res.ServiceID, res.PendingID)
+	// Each revision of an active resource is stored separately, so
+	// that the ones it replaces remain available for rolling back.
+	var oldPaths map[string]bool
+	storageID := res.PendingID
+	if storageID == "" {
+		paths, err := st.revisionPaths(res.ID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		oldPaths = paths
+
+		id, err := st.newPendingID()
+		if err != nil {
+			return errors.Annotate(err, "could not generate storage ID")
+		}
+		storageID = id
+	}
+	storagePath := storagePath(res.Name, res.ServiceID, storageID)
 	staged, err := st.persist.StageResource(res, storagePath)
 	if err != nil {
 		return errors.Trace(err)
@@ -225,6 +253,98 @@ func (st resourceState) storeResource(res resource.Resource, r io.Reader) error
 		return errors.Trace(err)
 	}
 
+	if res.PendingID == "" {
+		st.removeDroppedRevisions(res, oldPaths)
+	}
+	return nil
+}
+
+// revisionPaths returns the storage paths of the current and
+// previous revisions of the identified resource.
+func (st resourceState) revisionPaths(id string) (map[string]bool, error) {
+	paths := make(map[string]bool)
+	_, current, err := st.persist.GetResource(id)
+	if errors.IsNotFound(err) {
+		return paths, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if current != "" {
+		paths[current] = true
+	}
+	_, previous, err := st.persist.GetPreviousResources(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, path := range previous {
+		if path != "" {
+			paths[path] = true
+		}
+	}
+	return paths, nil
+}
+
+// removeDroppedRevisions removes from storage the content of the
+// revisions that were recorded before the newly stored resource was
+// activated but are not any more. Only a limited number of previous
+// revisions is kept, so the oldest is dropped once the limit is hit.
+func (st resourceState) removeDroppedRevisions(res resource.Resource, old map[string]bool) {
+	if len(old) == 0 {
+		return
+	}
+	current, err := st.revisionPaths(res.ID)
+	if err != nil {
+		logger.Errorf("could not get revisions of resource %q (service %q): %v", res.Name, res.ServiceID, err)
+		return
+	}
+	for path := range old {
+		if current[path] {
+			continue
+		}
+		if err := st.storage.Remove(path); err != nil {
+			logger.Errorf("could not remove dropped revision of resource %q (service %q) from storage: %v", res.Name, res.ServiceID, err)
+		}
+	}
+}
+
+// RollbackResource makes a previous revision of the identified
+// resource the active one, and returns it. If revision is empty then
+// the most recent previous revision is used, otherwise the most recent
+// one with a matching revision string. The replaced revision becomes
+// the most recent previous one, so rolling back again undoes the
+// rollback. If there is no such previous revision then errors.NotFound
+// is returned.
+func (st resourceState) RollbackResource(serviceID, name, revision string) (resource.Resource, error) {
+	logger.Tracef("rolling back resource %q for service %q (revision %q)", name, serviceID, revision)
+	id := newResourceID(serviceID, name)
+	res, err := st.persist.RollbackResource(id, revision)
+	if err != nil {
+		return res, errors.Trace(err)
+	}
+	return res, nil
+}
+
+// SetUnitResource records that the unit is using the current revision
+// of the named resource. This is needed when the unit gets the
+// resource's content from somewhere other than OpenResource, which
+// records it automatically. The fingerprint is that of the content
+// the unit got, so if it does not match the current revision then the
+// unit does not have that revision and an error is returned.
+func (st resourceState) SetUnitResource(unit resource.Unit, name string, fingerprint charmresource.Fingerprint) error {
+	id := newResourceID(unit.ServiceName(), name)
+	res, _, err := st.persist.GetResource(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if res.IsPlaceholder() {
+		return errors.NotFoundf("resource %q", name)
+	}
+	if !bytes.Equal(res.Fingerprint.Bytes(), fingerprint.Bytes()) {
+		return errors.Errorf("resource %q has changed (revision %s is current)", name, res.RevisionString())
+	}
+	if err := st.persist.SetUnitResource(unit.Name(), res); err != nil {
+		return errors.Trace(err)
+	}
 	return nil
 }
 
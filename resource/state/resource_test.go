@@ -123,10 +123,12 @@ func (s *ResourceSuite) TestSetResourceOkay(c *gc.C) {
 	expected.Timestamp = s.timestamp
 	chRes := expected.Resource
 	hash := chRes.Fingerprint.String()
-	path := "service-a-service/resources/spam"
+	path := "service-a-service/resources/spam-some-unique-ID"
 	file := &stubReader{stub: s.stub}
 	st := NewState(s.raw)
 	st.currentTimestamp = s.now
+	st.newPendingID = s.newPendingID
+	s.pendingID = "some-unique-ID"
 	s.stub.ResetCalls()
 
 	res, err := st.SetResource("a-service", "a-user", chRes, file)
@@ -134,12 +136,15 @@ func (s *ResourceSuite) TestSetResourceOkay(c *gc.C) {
 
 	s.stub.CheckCallNames(c,
 		"currentTimestamp",
+		"GetResource",
+		"GetPreviousResources",
+		"newPendingID",
 		"StageResource",
 		"PutAndCheckHash",
 		"Activate",
 	)
-	s.stub.CheckCall(c, 1, "StageResource", expected, path)
-	s.stub.CheckCall(c, 2, "PutAndCheckHash", path, file, res.Size, hash)
+	s.stub.CheckCall(c, 4, "StageResource", expected, path)
+	s.stub.CheckCall(c, 5, "PutAndCheckHash", path, file, res.Size, hash)
 	c.Check(res, jc.DeepEquals, resource.Resource{
 		Resource:  chRes,
 		ID:        "a-service/" + res.Name,
@@ -190,133 +195,302 @@ func (s *ResourceSuite) TestSetResourceBadResource(c *gc.C) {
 func (s *ResourceSuite) TestSetResourceStagingFailure(c *gc.C) {
 	expected := newUploadResource(c, "spam", "spamspamspam")
 	expected.Timestamp = s.timestamp
-	path := "service-a-service/resources/spam"
+	path := "service-a-service/resources/spam-some-unique-ID"
 	file := &stubReader{stub: s.stub}
 	st := NewState(s.raw)
 	st.currentTimestamp = s.now
+	st.newPendingID = s.newPendingID
+	s.pendingID = "some-unique-ID"
 	s.stub.ResetCalls()
 	failure := errors.New("<failure>")
 	ignoredErr := errors.New("<never reached>")
-	s.stub.SetErrors(nil, failure, nil, nil, ignoredErr)
+	s.stub.SetErrors(nil, nil, nil, nil, failure, nil, nil, ignoredErr)
 
 	_, err := st.SetResource("a-service", "a-user", expected.Resource, file)
 
 	c.Check(errors.Cause(err), gc.Equals, failure)
-	s.stub.CheckCallNames(c, "currentTimestamp", "StageResource")
-	s.stub.CheckCall(c, 1, "StageResource", expected, path)
+	s.stub.CheckCallNames(c, "currentTimestamp", "GetResource", "GetPreviousResources", "newPendingID", "StageResource")
+	s.stub.CheckCall(c, 4, "StageResource", expected, path)
 }
 
 func (s *ResourceSuite) TestSetResourcePutFailureBasic(c *gc.C) {
 	expected := newUploadResource(c, "spam", "spamspamspam")
 	expected.Timestamp = s.timestamp
 	hash := expected.Fingerprint.String()
-	path := "service-a-service/resources/spam"
+	path := "service-a-service/resources/spam-some-unique-ID"
 	file := &stubReader{stub: s.stub}
 	st := NewState(s.raw)
 	st.currentTimestamp = s.now
+	st.newPendingID = s.newPendingID
+	s.pendingID = "some-unique-ID"
 	s.stub.ResetCalls()
 	failure := errors.New("<failure>")
 	ignoredErr := errors.New("<never reached>")
-	s.stub.SetErrors(nil, nil, failure, nil, ignoredErr)
+	s.stub.SetErrors(nil, nil, nil, nil, nil, failure, nil, ignoredErr)
 
 	_, err := st.SetResource("a-service", "a-user", expected.Resource, file)
 
 	c.Check(errors.Cause(err), gc.Equals, failure)
 	s.stub.CheckCallNames(c,
 		"currentTimestamp",
+		"GetResource",
+		"GetPreviousResources",
+		"newPendingID",
 		"StageResource",
 		"PutAndCheckHash",
 		"Unstage",
 	)
-	s.stub.CheckCall(c, 1, "StageResource", expected, path)
-	s.stub.CheckCall(c, 2, "PutAndCheckHash", path, file, expected.Size, hash)
+	s.stub.CheckCall(c, 4, "StageResource", expected, path)
+	s.stub.CheckCall(c, 5, "PutAndCheckHash", path, file, expected.Size, hash)
 }
 
 func (s *ResourceSuite) TestSetResourcePutFailureExtra(c *gc.C) {
 	expected := newUploadResource(c, "spam", "spamspamspam")
 	expected.Timestamp = s.timestamp
 	hash := expected.Fingerprint.String()
-	path := "service-a-service/resources/spam"
+	path := "service-a-service/resources/spam-some-unique-ID"
 	file := &stubReader{stub: s.stub}
 	st := NewState(s.raw)
 	st.currentTimestamp = s.now
+	st.newPendingID = s.newPendingID
+	s.pendingID = "some-unique-ID"
 	s.stub.ResetCalls()
 	failure := errors.New("<failure>")
 	extraErr := errors.New("<just not your day>")
 	ignoredErr := errors.New("<never reached>")
-	s.stub.SetErrors(nil, nil, failure, extraErr, ignoredErr)
+	s.stub.SetErrors(nil, nil, nil, nil, nil, failure, extraErr, ignoredErr)
 
 	_, err := st.SetResource("a-service", "a-user", expected.Resource, file)
 
 	c.Check(errors.Cause(err), gc.Equals, failure)
 	s.stub.CheckCallNames(c,
 		"currentTimestamp",
+		"GetResource",
+		"GetPreviousResources",
+		"newPendingID",
 		"StageResource",
 		"PutAndCheckHash",
 		"Unstage",
 	)
-	s.stub.CheckCall(c, 1, "StageResource", expected, path)
-	s.stub.CheckCall(c, 2, "PutAndCheckHash", path, file, expected.Size, hash)
+	s.stub.CheckCall(c, 4, "StageResource", expected, path)
+	s.stub.CheckCall(c, 5, "PutAndCheckHash", path, file, expected.Size, hash)
 }
 
 func (s *ResourceSuite) TestSetResourceSetFailureBasic(c *gc.C) {
 	expected := newUploadResource(c, "spam", "spamspamspam")
 	expected.Timestamp = s.timestamp
 	hash := expected.Fingerprint.String()
-	path := "service-a-service/resources/spam"
+	path := "service-a-service/resources/spam-some-unique-ID"
 	file := &stubReader{stub: s.stub}
 	st := NewState(s.raw)
 	st.currentTimestamp = s.now
+	st.newPendingID = s.newPendingID
+	s.pendingID = "some-unique-ID"
 	s.stub.ResetCalls()
 	failure := errors.New("<failure>")
 	ignoredErr := errors.New("<never reached>")
-	s.stub.SetErrors(nil, nil, nil, failure, nil, nil, ignoredErr)
+	s.stub.SetErrors(nil, nil, nil, nil, nil, nil, failure, nil, nil, ignoredErr)
 
 	_, err := st.SetResource("a-service", "a-user", expected.Resource, file)
 
 	c.Check(errors.Cause(err), gc.Equals, failure)
 	s.stub.CheckCallNames(c,
 		"currentTimestamp",
+		"GetResource",
+		"GetPreviousResources",
+		"newPendingID",
 		"StageResource",
 		"PutAndCheckHash",
 		"Activate",
 		"Remove",
 		"Unstage",
 	)
-	s.stub.CheckCall(c, 1, "StageResource", expected, path)
-	s.stub.CheckCall(c, 2, "PutAndCheckHash", path, file, expected.Size, hash)
-	s.stub.CheckCall(c, 4, "Remove", path)
+	s.stub.CheckCall(c, 4, "StageResource", expected, path)
+	s.stub.CheckCall(c, 5, "PutAndCheckHash", path, file, expected.Size, hash)
+	s.stub.CheckCall(c, 7, "Remove", path)
 }
 
 func (s *ResourceSuite) TestSetResourceSetFailureExtra(c *gc.C) {
 	expected := newUploadResource(c, "spam", "spamspamspam")
 	expected.Timestamp = s.timestamp
 	hash := expected.Fingerprint.String()
-	path := "service-a-service/resources/spam"
+	path := "service-a-service/resources/spam-some-unique-ID"
 	file := &stubReader{stub: s.stub}
 	st := NewState(s.raw)
 	st.currentTimestamp = s.now
+	st.newPendingID = s.newPendingID
+	s.pendingID = "some-unique-ID"
 	s.stub.ResetCalls()
 	failure := errors.New("<failure>")
 	extraErr1 := errors.New("<just not your day>")
 	extraErr2 := errors.New("<wow...just wow>")
 	ignoredErr := errors.New("<never reached>")
-	s.stub.SetErrors(nil, nil, nil, failure, extraErr1, extraErr2, ignoredErr)
+	s.stub.SetErrors(nil, nil, nil, nil, nil, nil, failure, extraErr1, extraErr2, ignoredErr)
 
 	_, err := st.SetResource("a-service", "a-user", expected.Resource, file)
 
 	c.Check(errors.Cause(err), gc.Equals, failure)
 	s.stub.CheckCallNames(c,
 		"currentTimestamp",
+		"GetResource",
+		"GetPreviousResources",
+		"newPendingID",
 		"StageResource",
 		"PutAndCheckHash",
 		"Activate",
 		"Remove",
 		"Unstage",
 	)
-	s.stub.CheckCall(c, 1, "StageResource", expected, path)
-	s.stub.CheckCall(c, 2, "PutAndCheckHash", path, file, expected.Size, hash)
-	s.stub.CheckCall(c, 4, "Remove", path)
+	s.stub.CheckCall(c, 4, "StageResource", expected, path)
+	s.stub.CheckCall(c, 5, "PutAndCheckHash", path, file, expected.Size, hash)
+	s.stub.CheckCall(c, 7, "Remove", path)
+}
+
+func (s *ResourceSuite) TestSetResourceRemovesDroppedRevision(c *gc.C) {
+	expected := newUploadResource(c, "spam", "spamspamspam")
+	expected.Timestamp = s.timestamp
+	s.persist.ReturnGetResourcePath = "service-a-service/resources/spam-current-ID"
+	s.persist.ReturnGetPreviousResourcePaths = [][]string{{
+		"service-a-service/resources/spam-oldest-ID",
+		"service-a-service/resources/spam-previous-ID",
+	}, {
+		"service-a-service/resources/spam-previous-ID",
+		"service-a-service/resources/spam-current-ID",
+	}}
+	file := &stubReader{stub: s.stub}
+	st := NewState(s.raw)
+	st.currentTimestamp = s.now
+	st.newPendingID = s.newPendingID
+	s.pendingID = "some-unique-ID"
+	s.stub.ResetCalls()
+
+	_, err := st.SetResource("a-service", "a-user", expected.Resource, file)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c,
+		"currentTimestamp",
+		"GetResource",
+		"GetPreviousResources",
+		"newPendingID",
+		"StageResource",
+		"PutAndCheckHash",
+		"Activate",
+		"GetResource",
+		"GetPreviousResources",
+		"Remove",
+	)
+	s.stub.CheckCall(c, 1, "GetResource", "a-service/spam")
+	s.stub.CheckCall(c, 2, "GetPreviousResources", "a-service/spam")
+	s.stub.CheckCall(c, 9, "Remove", "service-a-service/resources/spam-oldest-ID")
+}
+
+func (s *ResourceSuite) TestSetResourceKeepsRecordedRevisions(c *gc.C) {
+	expected := newUploadResource(c, "spam", "spamspamspam")
+	expected.Timestamp = s.timestamp
+	s.persist.ReturnGetPreviousResourcePaths = [][]string{{
+		"service-a-service/resources/spam-previous-ID",
+	}}
+	file := &stubReader{stub: s.stub}
+	st := NewState(s.raw)
+	st.currentTimestamp = s.now
+	st.newPendingID = s.newPendingID
+	s.pendingID = "some-unique-ID"
+	s.stub.ResetCalls()
+
+	_, err := st.SetResource("a-service", "a-user", expected.Resource, file)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The previous revision is still recorded, so its content is kept.
+	s.stub.CheckCallNames(c,
+		"currentTimestamp",
+		"GetResource",
+		"GetPreviousResources",
+		"newPendingID",
+		"StageResource",
+		"PutAndCheckHash",
+		"Activate",
+		"GetResource",
+		"GetPreviousResources",
+	)
+}
+
+func (s *ResourceSuite) TestRollbackResource(c *gc.C) {
+	expected := newUploadResource(c, "spam", "spamspamspam")
+	s.persist.ReturnRollbackResource = expected
+	st := NewState(s.raw)
+	s.stub.ResetCalls()
+
+	res, err := st.RollbackResource("a-service", "spam", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "RollbackResource")
+	s.stub.CheckCall(c, 0, "RollbackResource", "a-service/spam", "")
+	c.Check(res, jc.DeepEquals, expected)
+}
+
+func (s *ResourceSuite) TestRollbackResourceRevision(c *gc.C) {
+	expected := newUploadResource(c, "spam", "spamspamspam")
+	s.persist.ReturnRollbackResource = expected
+	st := NewState(s.raw)
+	s.stub.ResetCalls()
+
+	_, err := st.RollbackResource("a-service", "spam", "2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "RollbackResource")
+	s.stub.CheckCall(c, 0, "RollbackResource", "a-service/spam", "2")
+}
+
+func (s *ResourceSuite) TestRollbackResourceError(c *gc.C) {
+	st := NewState(s.raw)
+	s.stub.ResetCalls()
+	failure := errors.New("<failure>")
+	s.stub.SetErrors(failure)
+
+	_, err := st.RollbackResource("a-service", "spam", "")
+
+	c.Check(errors.Cause(err), gc.Equals, failure)
+	s.stub.CheckCallNames(c, "RollbackResource")
+}
+
+func (s *ResourceSuite) TestSetUnitResource(c *gc.C) {
+	expected := newUploadResource(c, "spam", "spamspamspam")
+	s.persist.ReturnGetResource = expected
+	st := NewState(s.raw)
+	s.stub.ResetCalls()
+
+	err := st.SetUnitResource(fakeUnit{"a-service/0", "a-service"}, "spam", expected.Fingerprint)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "GetResource", "SetUnitResource")
+	s.stub.CheckCall(c, 0, "GetResource", "a-service/spam")
+	s.stub.CheckCall(c, 1, "SetUnitResource", "a-service/0", expected)
+}
+
+func (s *ResourceSuite) TestSetUnitResourceChanged(c *gc.C) {
+	current := newUploadResource(c, "spam", "spamspamspam")
+	current.Origin = charmresource.OriginStore
+	current.Revision = 3
+	s.persist.ReturnGetResource = current
+	cached := newUploadResource(c, "spam", "eggs")
+	st := NewState(s.raw)
+	s.stub.ResetCalls()
+
+	err := st.SetUnitResource(fakeUnit{"a-service/0", "a-service"}, "spam", cached.Fingerprint)
+
+	c.Check(err, gc.ErrorMatches, `resource "spam" has changed \(revision 3 is current\)`)
+	s.stub.CheckCallNames(c, "GetResource")
+}
+
+func (s *ResourceSuite) TestSetUnitResourcePlaceholder(c *gc.C) {
+	s.persist.ReturnGetResource = resourcetesting.NewPlaceholderResource(c, "spam", "a-service")
+	st := NewState(s.raw)
+	s.stub.ResetCalls()
+
+	err := st.SetUnitResource(fakeUnit{"a-service/0", "a-service"}, "spam", charmresource.Fingerprint{})
+
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	s.stub.CheckCallNames(c, "GetResource")
 }
 
 func (s *ResourceSuite) TestUpdatePendingResourceOkay(c *gc.C) {
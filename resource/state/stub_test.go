@@ -42,6 +42,9 @@ type stubPersistence struct {
 	ReturnListPendingResources         []resource.Resource
 	ReturnGetResource                  resource.Resource
 	ReturnGetResourcePath              string
+	ReturnGetPreviousResources         []resource.Resource
+	ReturnGetPreviousResourcePaths     [][]string
+	ReturnRollbackResource             resource.Resource
 	ReturnStageResource                *stubStagedResource
 	ReturnNewResolvePendingResourceOps [][]txn.Op
 
@@ -75,6 +78,33 @@ func (s *stubPersistence) GetResource(serviceID string) (resource.Resource, stri
 	return s.ReturnGetResource, s.ReturnGetResourcePath, nil
 }
 
+func (s *stubPersistence) GetPreviousResources(id string) ([]resource.Resource, []string, error) {
+	s.stub.AddCall("GetPreviousResources", id)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	// Each call gets the next set of paths, and the last set is
+	// repeated after that.
+	var paths []string
+	if len(s.ReturnGetPreviousResourcePaths) > 0 {
+		paths = s.ReturnGetPreviousResourcePaths[0]
+		if len(s.ReturnGetPreviousResourcePaths) > 1 {
+			s.ReturnGetPreviousResourcePaths = s.ReturnGetPreviousResourcePaths[1:]
+		}
+	}
+	return s.ReturnGetPreviousResources, paths, nil
+}
+
+func (s *stubPersistence) RollbackResource(id, revision string) (resource.Resource, error) {
+	s.stub.AddCall("RollbackResource", id, revision)
+	if err := s.stub.NextErr(); err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+
+	return s.ReturnRollbackResource, nil
+}
+
 func (s *stubPersistence) StageResource(res resource.Resource, storagePath string) (StagedResource, error) {
 	s.stub.AddCall("StageResource", res, storagePath)
 	if err := s.stub.NextErr(); err != nil {
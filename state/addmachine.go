@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/juju/names"
 	"github.com/juju/replicaset"
 	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
@@ -90,6 +92,14 @@ type MachineTemplate struct {
 	// with the machine.
 	Placement string
 
+	// CloudInitUserData holds operator-supplied cloud-init user data
+	// to be merged with Juju's own when provisioning the machine.
+	CloudInitUserData string
+
+	// RootDiskSource, if not empty, names the source from which the
+	// machine's root disk is created.
+	RootDiskSource string
+
 	// principals holds the principal units that will
 	// associated with the machine.
 	principals []string
@@ -471,6 +481,8 @@ func (st *State) machineDocForTemplate(template MachineTemplate, id string) *mac
 		PreferredPublicAddress:  fromNetworkAddress(publicAddr, OriginMachine),
 		NoVote:                  template.NoVote,
 		Placement:               template.Placement,
+		CloudInitUserData:       template.CloudInitUserData,
+		RootDiskSource:          template.RootDiskSource,
 	}
 }
 
@@ -581,8 +593,27 @@ func (st *State) machineStorageOps(
 		})
 	}
 
-	// TODO(axw) handle args.filesystemAttachments, args.volumeAttachments
-	// when we handle attaching to existing (e.g. shared) storage.
+	// Attach existing (shared) filesystems. The filesystem may already
+	// be attached to the machine if another unit of the same service is
+	// assigned to it, in which case there is nothing more to do.
+	//
+	// TODO(axw) handle args.volumeAttachments when we handle attaching
+	// to existing volumes.
+	existingFilesystems := set.NewStrings(mdoc.Filesystems...)
+	for _, tag := range sortedFilesystemTags(args.filesystemAttachments) {
+		if existingFilesystems.Contains(tag.Id()) {
+			continue
+		}
+		filesystemOps = append(filesystemOps, txn.Op{
+			C:      filesystemsC,
+			Id:     tag.Id(),
+			Assert: isAliveDoc,
+			Update: bson.D{{"$inc", bson.D{{"attachmentcount", 1}}}},
+		})
+		fsAttachments = append(fsAttachments, filesystemAttachmentTemplate{
+			tag: tag, params: args.filesystemAttachments[tag],
+		})
+	}
 
 	ops := make([]txn.Op, 0, len(filesystemOps)+len(volumeOps)+len(fsAttachments)+len(volumeAttachments))
 	if len(fsAttachments) > 0 {
@@ -598,6 +629,21 @@ func (st *State) machineStorageOps(
 	return ops, volumeAttachments, fsAttachments, nil
 }
 
+// sortedFilesystemTags returns the tags of the specified filesystem
+// attachment parameters, sorted by ID to simplify testing.
+func sortedFilesystemTags(attachments map[names.FilesystemTag]FilesystemAttachmentParams) []names.FilesystemTag {
+	ids := make([]string, 0, len(attachments))
+	for tag := range attachments {
+		ids = append(ids, tag.Id())
+	}
+	sort.Strings(ids)
+	tags := make([]names.FilesystemTag, len(ids))
+	for i, id := range ids {
+		tags[i] = names.NewFilesystemTag(id)
+	}
+	return tags
+}
+
 // addMachineStorageAttachmentsOps returns txn.Ops for adding the IDs of
 // attached volumes and filesystems to an existing machine. Filesystem
 // mount points are checked against existing filesystem attachments for
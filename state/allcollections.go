@@ -202,6 +202,21 @@ func allCollections() collectionSchema {
 		},
 		minUnitsC: {},
 
+		// These collections hold information about cross-model relations:
+		// the offers that make a service's endpoints available to other
+		// models on the controller, and the remote services that stand in
+		// for services in other models.
+		serviceOffersC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "service-name"},
+			}},
+		},
+		remoteServicesC: {},
+
+		// This collection holds the named machine profiles that may be
+		// applied to the machines added to a model.
+		machineProfilesC: {},
+
 		// This collection holds documents that indicate units which are queued
 		// to be assigned to machines. It is used exclusively by the
 		// AssignUnitWorker.
@@ -246,6 +261,9 @@ func allCollections() collectionSchema {
 				Key: []string{"model-uuid", "owner"},
 			}},
 		},
+		// This collection holds a revision per capacity-limited storage
+		// pool, used to serialise the creation of storage in the pool.
+		storagePoolUsageC: {},
 		storageAttachmentsC: {
 			indexes: []mgo.Index{{
 				Key: []string{"model-uuid", "storageid"},
@@ -361,9 +379,34 @@ func allCollections() collectionSchema {
 			}},
 		},
 
+		// This collection holds the hooks, actions and commands run by
+		// each unit's agent. It is not a capped collection, whose size
+		// limit would be shared by all units: instead, a unit's oldest
+		// entries are pruned whenever one is added for it, keeping at
+		// most maxHookHistoryPerUnit per unit.
+		hookHistoryC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "unit", "-started"},
+			}},
+		},
+
+		// This collection holds the state each unit's agent keeps
+		// about the hooks and operations it has run, so that units
+		// can be recovered on fresh machines.
+		unitStatesC: {},
+
 		// This collection holds information about cloud image metadata.
 		cloudimagemetadataC: {},
 
+		// This collection holds the secrets owned by services, with
+		// their content encrypted by a key that is not held in the
+		// database; see State.SetSecretsKey.
+		secretsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "owner"},
+			}},
+		},
+
 		// ----------------------
 
 		// Raw-access collections
@@ -393,10 +436,12 @@ const (
 	controllersC             = "controllers"
 	filesystemAttachmentsC   = "filesystemAttachments"
 	filesystemsC             = "filesystems"
+	hookHistoryC             = "hookhistory"
 	instanceDataC            = "instanceData"
 	ipaddressesC             = "ipaddresses"
 	leaseC                   = "lease"
 	leasesC                  = "leases"
+	machineProfilesC         = "machineprofiles"
 	machinesC                = "machines"
 	meterStatusC             = "meterStatus"
 	metricsC                 = "metrics"
@@ -414,9 +459,12 @@ const (
 	rebootC                  = "reboot"
 	relationScopesC          = "relationscopes"
 	relationsC               = "relations"
+	remoteServicesC          = "remoteservices"
 	requestedNetworksC       = "requestednetworks"
 	restoreInfoC             = "restoreInfo"
+	secretsC                 = "secrets"
 	sequenceC                = "sequence"
+	serviceOffersC           = "serviceoffers"
 	servicesC                = "services"
 	endpointBindingsC        = "endpointbindings"
 	settingsC                = "settings"
@@ -427,10 +475,12 @@ const (
 	storageAttachmentsC      = "storageattachments"
 	storageConstraintsC      = "storageconstraints"
 	storageInstancesC        = "storageinstances"
+	storagePoolUsageC        = "storagepoolusage"
 	subnetsC                 = "subnets"
 	toolsmetadataC           = "toolsmetadata"
 	txnLogC                  = "txns.log"
 	txnsC                    = "txns"
+	unitStatesC              = "unitstates"
 	unitsC                   = "units"
 	upgradeInfoC             = "upgradeInfo"
 	userLastLoginC           = "userLastLogin"
@@ -254,13 +254,14 @@ func unitAndAgentStatus(st *State, name string) (unitStatus, agentStatus *Status
 
 func (u *backingUnit) updated(st *State, store *multiwatcherStore, id string) error {
 	info := &multiwatcher.UnitInfo{
-		ModelUUID:   st.ModelUUID(),
-		Name:        u.Name,
-		Service:     u.Service,
-		Series:      u.Series,
-		MachineId:   u.MachineId,
-		Subordinate: u.Principal != "",
-		StatusData:  make(map[string]interface{}),
+		ModelUUID:       st.ModelUUID(),
+		Name:            u.Name,
+		Service:         u.Service,
+		Series:          u.Series,
+		WorkloadVersion: u.WorkloadVersion,
+		MachineId:       u.MachineId,
+		Subordinate:     u.Principal != "",
+		StatusData:      make(map[string]interface{}),
 	}
 	if u.CharmURL != nil {
 		info.CharmURL = u.CharmURL.String()
@@ -2185,6 +2185,8 @@ func testChangeUnits(c *gc.C, owner names.UserTag, runChangeTests func(*gc.C, []
 			c.Assert(err, jc.ErrorIsNil)
 			err = u.OpenPort("udp", 17070)
 			c.Assert(err, jc.ErrorIsNil)
+			err = u.SetWorkloadVersion("4.2")
+			c.Assert(err, jc.ErrorIsNil)
 
 			return changeTestCase{
 				about: "unit is updated if it's in backing and in multiwatcher.Store",
@@ -2215,16 +2217,17 @@ func testChangeUnits(c *gc.C, owner names.UserTag, runChangeTests func(*gc.C, []
 				},
 				expectContents: []multiwatcher.EntityInfo{
 					&multiwatcher.UnitInfo{
-						ModelUUID:  st.ModelUUID(),
-						Name:       "wordpress/0",
-						Service:    "wordpress",
-						Series:     "quantal",
-						MachineId:  "0",
-						Ports:      []network.Port{{"udp", 17070}},
-						PortRanges: []network.PortRange{{17070, 17070, "udp"}},
-						Status:     multiwatcher.Status("error"),
-						StatusInfo: "another failure",
-						StatusData: map[string]interface{}{},
+						ModelUUID:       st.ModelUUID(),
+						Name:            "wordpress/0",
+						Service:         "wordpress",
+						Series:          "quantal",
+						WorkloadVersion: "4.2",
+						MachineId:       "0",
+						Ports:           []network.Port{{"udp", 17070}},
+						PortRanges:      []network.PortRange{{17070, 17070, "udp"}},
+						Status:          multiwatcher.Status("error"),
+						StatusInfo:      "another failure",
+						StatusData:      map[string]interface{}{},
 						AgentStatus: multiwatcher.StatusInfo{
 							Current: "idle",
 							Message: "",
@@ -379,6 +379,28 @@ func (s *AssignSuite) TestAssignUnitToNewMachine(c *gc.C) {
 	s.assertAssignedUnit(c, unit)
 }
 
+func (s *AssignSuite) TestAssignUnitToNewMachineWithServiceUserData(c *gc.C) {
+	svc, err := s.State.AddService(state.AddServiceArgs{
+		Name:              "mysql",
+		Owner:             s.Owner.String(),
+		Charm:             s.AddTestingCharm(c, "mysql"),
+		CloudInitUserData: "packages: [ca-certificates]",
+		RootDiskSource:    "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.CloudInitUserData(), gc.Equals, "packages: [ca-certificates]")
+	c.Assert(svc.RootDiskSource(), gc.Equals, "ssd")
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(s.assertAssignedUnit(c, unit))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.CloudInitUserData(), gc.Equals, "packages: [ca-certificates]")
+	c.Assert(machine.RootDiskSource(), gc.Equals, "ssd")
+}
+
 func (s *AssignSuite) assertAssignUnitToNewMachineContainerConstraint(c *gc.C) {
 	unit, err := s.wordpress.AddUnit()
 	c.Assert(err, jc.ErrorIsNil)
@@ -30,6 +30,9 @@ const (
 	cleanupAttachmentsForDyingFilesystem cleanupKind = "filesystemAttachments"
 	cleanupModelsForDyingController      cleanupKind = "models"
 	cleanupMachinesForDyingModel         cleanupKind = "modelMachines"
+	cleanupServiceOffers                 cleanupKind = "serviceOffers"
+	cleanupServiceSecrets                cleanupKind = "serviceSecrets"
+	cleanupServiceStorage                cleanupKind = "serviceStorage"
 )
 
 // cleanupDoc represents a potentially large set of documents that should be
@@ -105,6 +108,12 @@ func (st *State) Cleanup() (err error) {
 			err = st.cleanupModelsForDyingController()
 		case cleanupMachinesForDyingModel:
 			err = st.cleanupMachinesForDyingModel()
+		case cleanupServiceOffers:
+			err = st.cleanupServiceOffers(doc.Prefix)
+		case cleanupServiceSecrets:
+			err = st.cleanupServiceSecrets(doc.Prefix)
+		case cleanupServiceStorage:
+			err = st.cleanupServiceStorage(doc.Prefix)
 		default:
 			err = fmt.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -212,6 +221,18 @@ func (st *State) cleanupServicesForDyingModel() (err error) {
 			return err
 		}
 	}
+	remoteServices, err := st.AllRemoteServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, remoteService := range remoteServices {
+		if remoteService.Life() != Alive {
+			continue
+		}
+		if err := remoteService.Destroy(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -485,6 +506,22 @@ func (st *State) cleanupAttachmentsForDyingStorage(storageId string) (err error)
 	return nil
 }
 
+// cleanupServiceStorage destroys the shared storage instances owned by
+// the removed service with the specified name. Destroying the storage
+// instances destroys the filesystems assigned to them.
+func (st *State) cleanupServiceStorage(serviceName string) error {
+	shared, err := serviceSharedStorage(st, names.NewServiceTag(serviceName))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, tag := range shared.tags {
+		if err := st.DestroyStorageInstance(tag); err != nil {
+			return errors.Annotate(err, "destroying shared storage")
+		}
+	}
+	return nil
+}
+
 // cleanupAttachmentsForDyingVolume sets all volume attachments related
 // to the specified volume to Dying, if they are not already Dying or
 // Dead. It's expected to be used when a volume is destroyed.
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/instance"
 )
@@ -15,10 +16,24 @@ import (
 // and asks the InstanceDistributor policy (if any) which ones are suitable
 // for assigning the unit to. If there is no InstanceDistributor, or the
 // distribution group is empty, then all of the candidates will be returned.
+//
+// If the unit's service has a zone policy, it takes the place of the
+// InstanceDistributor; see distributeUnitByZone.
 func distributeUnit(u *Unit, candidates []instance.Id) ([]instance.Id, error) {
 	if len(candidates) == 0 {
 		return nil, nil
 	}
+	svc, err := u.Service()
+	if err != nil {
+		return nil, err
+	}
+	zonePolicy, err := svc.ZonePolicy()
+	if err != nil {
+		return nil, err
+	}
+	if zonePolicy != nil {
+		return distributeUnitByZone(u.st, u.doc.Service, *zonePolicy, candidates)
+	}
 	if u.st.policy == nil {
 		return candidates, nil
 	}
@@ -45,6 +60,148 @@ func distributeUnit(u *Unit, candidates []instance.Id) ([]instance.Id, error) {
 	return distributor.DistributeInstances(candidates, distributionGroup)
 }
 
+// distributeUnitByZone returns those of the candidate instances that
+// are in the zone the zone policy would choose for a new unit of the
+// service, or in zones that are as good a choice. If none of the
+// candidates' zones are known, all of the candidates are returned,
+// unless the policy is strict; then none are, so that the unit gets a
+// new machine that the provisioner places according to the policy.
+func distributeUnitByZone(st *State, service string, policy instance.ZonePolicy, candidates []instance.Id) ([]instance.Id, error) {
+	candidateZones, err := instanceZones(st, candidates)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(candidateZones) == 0 {
+		if policy.Strict() {
+			return nil, nil
+		}
+		return candidates, nil
+	}
+	population, err := ServiceZonePopulation(st, service)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var zones []string
+	for zone := range population {
+		zones = append(zones, zone)
+	}
+	for _, zone := range candidateZones {
+		if _, ok := population[zone]; !ok {
+			zones = append(zones, zone)
+		}
+	}
+	best, err := policy.ChooseZone(zones, population)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var eligible []instance.Id
+	for _, id := range candidates {
+		zone, ok := candidateZones[id]
+		if !ok || population[zone] != population[best] {
+			continue
+		}
+		if !policy.Permits(zone) {
+			continue
+		}
+		eligible = append(eligible, id)
+	}
+	return eligible, nil
+}
+
+// instanceZones returns the availability zones of those of the
+// supplied instances whose zones are known.
+func instanceZones(st *State, ids []instance.Id) (map[instance.Id]string, error) {
+	instanceDataCollection, closer := st.getCollection(instanceDataC)
+	defer closer()
+
+	var docs []instanceData
+	query := bson.D{{"instanceid", bson.D{{"$in", ids}}}}
+	if err := instanceDataCollection.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get instance zones")
+	}
+	zones := make(map[instance.Id]string)
+	for _, doc := range docs {
+		if doc.AvailZone != nil && *doc.AvailZone != "" {
+			zones[doc.InstanceId] = *doc.AvailZone
+		}
+	}
+	return zones, nil
+}
+
+// ServiceZonePopulation returns the number of units of the specified
+// service on provisioned machines in each availability zone. Units on
+// machines whose zones are not known are not counted.
+func ServiceZonePopulation(st *State, service string) (map[string]int, error) {
+	machineUnits, err := serviceMachineUnits(st, service)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	population := make(map[string]int)
+	if len(machineUnits) == 0 {
+		return population, nil
+	}
+	machineIds := make([]string, 0, len(machineUnits))
+	for id := range machineUnits {
+		machineIds = append(machineIds, id)
+	}
+	instanceDataCollection, closer := st.getCollection(instanceDataC)
+	defer closer()
+	var docs []instanceData
+	query := bson.D{{"machineid", bson.D{{"$in", machineIds}}}}
+	if err := instanceDataCollection.Find(query).Select(bson.D{{"machineid", 1}, {"availzone", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get machine zones")
+	}
+	for _, doc := range docs {
+		if doc.AvailZone != nil && *doc.AvailZone != "" {
+			population[*doc.AvailZone] += machineUnits[doc.MachineId]
+		}
+	}
+	return population, nil
+}
+
+// serviceMachineUnits returns the number of units of the specified
+// service assigned to each machine. Subordinate units are counted
+// against their principals' machines.
+func serviceMachineUnits(st *State, service string) (map[string]int, error) {
+	units, err := allUnits(st, service)
+	if err != nil {
+		return nil, err
+	}
+	machineUnits := make(map[string]int)
+	principals := make(map[string]int)
+	for _, unit := range units {
+		if unit.IsPrincipal() {
+			if unit.doc.MachineId != "" {
+				machineUnits[unit.doc.MachineId]++
+			}
+			continue
+		}
+		principals[unit.doc.Principal]++
+	}
+	if len(principals) == 0 {
+		return machineUnits, nil
+	}
+	names := make([]string, 0, len(principals))
+	for name := range principals {
+		names = append(names, name)
+	}
+	unitsCollection, closer := st.getCollection(unitsC)
+	defer closer()
+	var docs []unitDoc
+	query := bson.D{{"name", bson.D{{"$in", names}}}}
+	if err := unitsCollection.Find(query).Select(bson.D{{"name", 1}, {"machineid", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get principal units")
+	}
+	for _, doc := range docs {
+		if doc.MachineId != "" {
+			machineUnits[doc.MachineId] += principals[doc.Name]
+		}
+	}
+	return machineUnits, nil
+}
+
 // ServiceInstances returns the instance IDs of provisioned
 // machines that are assigned units of the specified service.
 func ServiceInstances(st *State, service string) ([]instance.Id, error) {
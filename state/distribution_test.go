@@ -184,3 +184,74 @@ func (s *InstanceDistributorSuite) TestDistributeInstancesNoPolicy(c *gc.C) {
 	_, err = unit.AssignToCleanMachine()
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *InstanceDistributorSuite) setupZonedScenario(c *gc.C, policy string) {
+	// Assign a unit to the first machine, and provision the machines
+	// so that two are in zone "a" and one is in zone "b".
+	zonePolicy, err := instance.ParseZonePolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetZonePolicy(&zonePolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machines[0])
+	c.Assert(err, jc.ErrorIsNil)
+	for i, m := range s.machines {
+		zone := "a"
+		if i == 2 {
+			zone = "b"
+		}
+		instId := instance.Id(fmt.Sprintf("i-blah-%d", i))
+		err = m.SetProvisioned(instId, "fake-nonce", &instance.HardwareCharacteristics{
+			AvailabilityZone: &zone,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.distributor.err = fmt.Errorf("should not be consulted")
+}
+
+func (s *InstanceDistributorSuite) TestDistributeByZoneSpread(c *gc.C) {
+	s.setupZonedScenario(c, "spread")
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := unit.AssignToCleanMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, s.machines[2].Id())
+
+	population, err := state.ServiceZonePopulation(s.State, "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(population, jc.DeepEquals, map[string]int{"a": 1, "b": 1})
+}
+
+func (s *InstanceDistributorSuite) TestDistributeByZonePack(c *gc.C) {
+	s.setupZonedScenario(c, "pack")
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := unit.AssignToCleanMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, s.machines[1].Id())
+}
+
+func (s *InstanceDistributorSuite) TestDistributeByZoneRestricted(c *gc.C) {
+	s.setupZonedScenario(c, "zones=c")
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit.AssignToCleanMachine()
+	c.Assert(err, gc.ErrorMatches, eligibleMachinesInUse)
+}
+
+func (s *InstanceDistributorSuite) TestDistributeByZoneRestrictedUnknownZones(c *gc.C) {
+	// None of the machines' zones are known, so none can be shown
+	// to satisfy the strict policy.
+	err := s.wordpress.SetZonePolicy(&instance.ZonePolicy{Zones: []string{"a"}})
+	c.Assert(err, jc.ErrorIsNil)
+	for i, m := range s.machines {
+		instId := instance.Id(fmt.Sprintf("i-blah-%d", i))
+		err = m.SetProvisioned(instId, "fake-nonce", nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit.AssignToCleanMachine()
+	c.Assert(err, gc.ErrorMatches, eligibleMachinesInUse)
+}
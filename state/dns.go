@@ -0,0 +1,80 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/network"
+)
+
+// DNSRecords returns the internal DNS records of the model, sorted by
+// name: one for each machine with a private IP address, one for each
+// unit assigned to such a machine, and one for each service with such
+// units, resolving to the addresses of all of them.
+func (st *State) DNSRecords() ([]network.DNSRecord, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	domain := network.ModelDNSDomain(model.Name(), model.UUID())
+
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var records []network.DNSRecord
+	machineAddresses := make(map[string]string)
+	for _, m := range machines {
+		addr, err := m.PrivateAddress()
+		if network.IsNoAddress(err) || addr.Type == network.HostName {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machineAddresses[m.Id()] = addr.Value
+		records = append(records, network.DNSRecord{
+			Name:      network.MachineDNSName(m.Id(), domain),
+			Addresses: []string{addr.Value},
+		})
+	}
+
+	services, err := st.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, svc := range services {
+		units, err := svc.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		serviceAddresses := set.NewStrings()
+		for _, u := range units {
+			machineId, err := u.AssignedMachineId()
+			if errors.IsNotAssigned(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			addr, ok := machineAddresses[machineId]
+			if !ok {
+				continue
+			}
+			serviceAddresses.Add(addr)
+			records = append(records, network.DNSRecord{
+				Name:      network.UnitDNSName(u.Name(), domain),
+				Addresses: []string{addr},
+			})
+		}
+		if !serviceAddresses.IsEmpty() {
+			records = append(records, network.DNSRecord{
+				Name:      network.ServiceDNSName(svc.Name(), domain),
+				Addresses: serviceAddresses.SortedValues(),
+			})
+		}
+	}
+	network.SortDNSRecords(records)
+	return records, nil
+}
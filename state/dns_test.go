@@ -0,0 +1,91 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type DNSSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&DNSSuite{})
+
+func (s *DNSSuite) addUnit(c *gc.C, svc *state.Service, address string) *state.Unit {
+	u, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	if address != "" {
+		err = m.SetProviderAddresses(network.NewAddress(address))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return u
+}
+
+func (s *DNSSuite) TestDNSRecords(c *gc.C) {
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	domain := network.ModelDNSDomain(model.Name(), model.UUID())
+
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.addUnit(c, wordpress, "10.0.0.1")
+	s.addUnit(c, wordpress, "10.0.0.2")
+	// A unit whose machine has no address yet gets no record.
+	s.addUnit(c, wordpress, "")
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.addUnit(c, mysql, "fd00::1")
+	// A service with no unit on an addressed machine gets no record.
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+
+	records, err := s.State.DNSRecords()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, jc.DeepEquals, []network.DNSRecord{
+		{Name: "0.mysql." + domain, Addresses: []string{"fd00::1"}},
+		{Name: "0.wordpress." + domain, Addresses: []string{"10.0.0.1"}},
+		{Name: "1.wordpress." + domain, Addresses: []string{"10.0.0.2"}},
+		{Name: "machine-0." + domain, Addresses: []string{"10.0.0.1"}},
+		{Name: "machine-1." + domain, Addresses: []string{"10.0.0.2"}},
+		{Name: "machine-3." + domain, Addresses: []string{"fd00::1"}},
+		{Name: "mysql." + domain, Addresses: []string{"fd00::1"}},
+		{Name: "wordpress." + domain, Addresses: []string{"10.0.0.1", "10.0.0.2"}},
+	})
+}
+
+func (s *DNSSuite) TestWatchDNSRecords(c *gc.C) {
+	w := s.State.WatchDNSRecords()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wc.AssertOneChange()
+
+	u, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = u.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = m.SetProviderAddresses(network.NewAddress("10.0.0.1"))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
@@ -40,6 +40,11 @@ const (
 	StatusesHistoryC   = statusesHistoryC
 )
 
+const (
+	MaxHookHistoryPerUnit = maxHookHistoryPerUnit
+	MaxStderrTail         = maxStderrTail
+)
+
 var (
 	ToolstorageNewStorage  = &toolstorageNewStorage
 	ImageStorageNewStorage = &imageStorageNewStorage
@@ -260,6 +260,18 @@ func (f *filesystem) Info() (FilesystemInfo, error) {
 	return *f.doc.Info, nil
 }
 
+// pool returns the name of the storage pool the filesystem was
+// or will be created in.
+func (f *filesystem) pool() string {
+	if f.doc.Info != nil {
+		return f.doc.Info.Pool
+	}
+	if f.doc.Params != nil {
+		return f.doc.Params.Pool
+	}
+	return ""
+}
+
 // Params is required to implement Filesystem.
 func (f *filesystem) Params() (FilesystemParams, bool) {
 	if f.doc.Params == nil {
@@ -480,10 +492,22 @@ func (st *State) removeMachineFilesystemsOps(machine names.MachineTag) ([]txn.Op
 // with the specified tag is inherently bound to the lifetime of the machine,
 // and will be removed along with it, leaving no resources dangling.
 func isFilesystemInherentlyMachineBound(st *State, tag names.FilesystemTag) (bool, error) {
-	// TODO(axw) when we have support for persistent filesystems,
-	// e.g. NFS shares, then we need to check the filesystem info
-	// to decide whether or not to remove.
-	return true, nil
+	if _, ok := names.FilesystemMachine(tag); ok {
+		return true, nil
+	}
+	// Shared filesystems, e.g. NFS shares, are attached to
+	// multiple machines and outlive any one of them.
+	f, err := st.filesystemByTag(tag)
+	if errors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	_, provider, err := poolStorageProvider(st, f.pool())
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return !storage.SupportsSharedFilesystems(provider), nil
 }
 
 // DetachFilesystem marks the filesystem attachment identified by the specified machine
@@ -710,6 +734,13 @@ func newFilesystemId(st *State, machineId string) (string, error) {
 // directly, a volume will be created and Juju will manage a filesystem
 // on it.
 func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]txn.Op, names.FilesystemTag, names.VolumeTag, error) {
+	// A filesystem created for a machine is created with an attachment
+	// to that machine. A filesystem created for shared storage is not
+	// created for any one machine, and is created with no attachments.
+	attachmentCount := 1
+	if machineId == "" {
+		attachmentCount = 0
+	}
 	if params.binding == nil {
 		params.binding = names.NewMachineTag(machineId)
 	}
@@ -762,13 +793,12 @@ func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]
 			Id:     filesystemId,
 			Assert: txn.DocMissing,
 			Insert: &filesystemDoc{
-				FilesystemId: filesystemId,
-				VolumeId:     volumeId,
-				StorageId:    params.storage.Id(),
-				Binding:      params.binding.String(),
-				Params:       &params,
-				// Every filesystem is created with one attachment.
-				AttachmentCount: 1,
+				FilesystemId:    filesystemId,
+				VolumeId:        volumeId,
+				StorageId:       params.storage.Id(),
+				Binding:         params.binding.String(),
+				Params:          &params,
+				AttachmentCount: attachmentCount,
 			},
 		},
 	}
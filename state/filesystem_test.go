@@ -405,17 +405,18 @@ func (s *FilesystemStateSuite) TestWatchMachineFilesystemAttachments(c *gc.C) {
 	w := s.State.WatchMachineFilesystemAttachments(names.NewMachineTag("0"))
 	defer testing.AssertStop(c, w)
 	wc := testing.NewStringsWatcherC(c, s.State, w)
-	wc.AssertChangeInSingleEvent("0:0/1", "0:0/2") // initial
+	wc.AssertChangeInSingleEvent("0:0", "0:0/1", "0:0/2") // initial
 	wc.AssertNoChange()
 
 	addUnit(nil)
 	// no change, since we're only interested in the one machine.
 	wc.AssertNoChange()
 
+	// Attachments of model-scoped filesystems to the machine are
+	// reported, since the machine attaches shared filesystems.
 	err := s.State.DetachFilesystem(names.NewMachineTag("0"), names.NewFilesystemTag("0"))
 	c.Assert(err, jc.ErrorIsNil)
-	// no change, since we're only interested in attachments of
-	// machine-scoped volumes.
+	wc.AssertChangeInSingleEvent("0:0") // dying
 	wc.AssertNoChange()
 
 	err = s.State.DetachFilesystem(names.NewMachineTag("0"), names.NewFilesystemTag("0/1"))
@@ -429,7 +430,7 @@ func (s *FilesystemStateSuite) TestWatchMachineFilesystemAttachments(c *gc.C) {
 	wc.AssertNoChange()
 
 	addUnit(m0)
-	wc.AssertChangeInSingleEvent("0:0/7", "0:0/8")
+	wc.AssertChangeInSingleEvent("0:6", "0:0/7", "0:0/8")
 	wc.AssertNoChange()
 }
 
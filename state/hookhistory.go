@@ -0,0 +1,193 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// maxHookHistoryPerUnit is the number of hook executions kept for
+	// each unit; older ones are removed as new ones are recorded.
+	maxHookHistoryPerUnit = 100
+
+	// maxStderrTail is the number of bytes of a hook's standard error
+	// output that are kept, from the end of the output.
+	maxStderrTail = 4096
+)
+
+// HookExecution describes a hook, action or set of commands run by a
+// unit's agent.
+type HookExecution struct {
+	// Kind is "hook", "action" or "commands".
+	Kind string
+
+	// Name holds the name of the hook or action run, and is empty
+	// for commands.
+	Name string
+
+	// Relation holds the key of the relation the hook or commands
+	// ran in the context of, if any.
+	Relation string
+
+	// RemoteUnit holds the name of the remote unit the hook or
+	// commands ran in the context of, if any.
+	RemoteUnit string
+
+	// Started and Finished hold the times the execution began and
+	// ended.
+	Started  time.Time
+	Finished time.Time
+
+	// Result is "completed", "failed" or "timed-out".
+	Result string
+
+	// ExitCode holds the exit code of the process run, or -1 if it
+	// did not exit normally.
+	ExitCode int
+
+	// StderrTail holds the end of the standard error output of the
+	// process run.
+	StderrTail string
+}
+
+// HookHistoryFilter restricts the hook executions returned by
+// Unit.HookHistory. Zero-valued fields match any execution.
+type HookHistoryFilter struct {
+	// Kind, Name and Result match the corresponding fields of
+	// HookExecution.
+	Kind   string
+	Name   string
+	Result string
+
+	// Since matches executions started at or after that time.
+	Since time.Time
+
+	// Limit restricts the number of executions returned to the most
+	// recent ones.
+	Limit int
+}
+
+type hookExecutionDoc struct {
+	ModelUUID  string `bson:"model-uuid"`
+	Unit       string `bson:"unit"`
+	Kind       string `bson:"kind"`
+	Name       string `bson:"name,omitempty"`
+	Relation   string `bson:"relation,omitempty"`
+	RemoteUnit string `bson:"remote-unit,omitempty"`
+	Started    int64  `bson:"started"`
+	Finished   int64  `bson:"finished"`
+	Result     string `bson:"result"`
+	ExitCode   int    `bson:"exit-code"`
+	StderrTail string `bson:"stderr-tail,omitempty"`
+}
+
+func (doc hookExecutionDoc) execution() HookExecution {
+	return HookExecution{
+		Kind:       doc.Kind,
+		Name:       doc.Name,
+		Relation:   doc.Relation,
+		RemoteUnit: doc.RemoteUnit,
+		Started:    time.Unix(0, doc.Started).UTC(),
+		Finished:   time.Unix(0, doc.Finished).UTC(),
+		Result:     doc.Result,
+		ExitCode:   doc.ExitCode,
+		StderrTail: doc.StderrTail,
+	}
+}
+
+// RecordHookExecution adds the given execution to the unit's hook
+// history, removing the oldest executions recorded for the unit if
+// it has more than it can keep.
+func (u *Unit) RecordHookExecution(execution HookExecution) error {
+	stderrTail := execution.StderrTail
+	if len(stderrTail) > maxStderrTail {
+		stderrTail = stderrTail[len(stderrTail)-maxStderrTail:]
+	}
+	doc := &hookExecutionDoc{
+		Unit:       u.Name(),
+		Kind:       execution.Kind,
+		Name:       execution.Name,
+		Relation:   execution.Relation,
+		RemoteUnit: execution.RemoteUnit,
+		Started:    execution.Started.UnixNano(),
+		Finished:   execution.Finished.UnixNano(),
+		Result:     execution.Result,
+		ExitCode:   execution.ExitCode,
+		StderrTail: stderrTail,
+	}
+	history, closer := u.st.getCollection(hookHistoryC)
+	defer closer()
+	// Hook history, like status history, is written without
+	// transactions: it is never updated, only added to and pruned.
+	historyW := history.Writeable()
+	if err := historyW.Insert(doc); err != nil {
+		return errors.Annotatef(err, "cannot record hook execution for unit %q", u.Name())
+	}
+
+	var oldest hookExecutionDoc
+	err := history.Find(bson.D{{"unit", u.Name()}}).Sort("-started").Skip(maxHookHistoryPerUnit - 1).One(&oldest)
+	if err == mgo.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot prune hook history for unit %q", u.Name())
+	}
+	_, err = historyW.RemoveAll(bson.D{
+		{"unit", u.Name()},
+		{"started", bson.D{{"$lt", oldest.Started}}},
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot prune hook history for unit %q", u.Name())
+	}
+	return nil
+}
+
+// HookHistory returns the executions in the unit's hook history that
+// match the given filter, most recent first.
+func (u *Unit) HookHistory(filter HookHistoryFilter) ([]HookExecution, error) {
+	history, closer := u.st.getCollection(hookHistoryC)
+	defer closer()
+
+	query := bson.D{{"unit", u.Name()}}
+	if filter.Kind != "" {
+		query = append(query, bson.DocElem{"kind", filter.Kind})
+	}
+	if filter.Name != "" {
+		query = append(query, bson.DocElem{"name", filter.Name})
+	}
+	if filter.Result != "" {
+		query = append(query, bson.DocElem{"result", filter.Result})
+	}
+	if !filter.Since.IsZero() {
+		query = append(query, bson.DocElem{"started", bson.D{{"$gte", filter.Since.UnixNano()}}})
+	}
+	q := history.Find(query).Sort("-started")
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var docs []hookExecutionDoc
+	if err := q.All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get hook history for unit %q", u.Name())
+	}
+	executions := make([]HookExecution, len(docs))
+	for i, doc := range docs {
+		executions[i] = doc.execution()
+	}
+	return executions, nil
+}
+
+// eraseHookHistory removes the unit's hook history.
+func (u *Unit) eraseHookHistory() error {
+	history, closer := u.st.getCollection(hookHistoryC)
+	defer closer()
+	historyW := history.Writeable()
+	if _, err := historyW.RemoveAll(bson.D{{"unit", u.Name()}}); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type HookHistorySuite struct {
+	statetesting.StateSuite
+	unit *state.Unit
+	t0   time.Time
+}
+
+var _ = gc.Suite(&HookHistorySuite{})
+
+func (s *HookHistorySuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, nil)
+	s.t0 = time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *HookHistorySuite) record(c *gc.C, unit *state.Unit, i int, name, result string) state.HookExecution {
+	execution := state.HookExecution{
+		Kind:     "hook",
+		Name:     name,
+		Started:  s.t0.Add(time.Duration(i) * time.Minute),
+		Finished: s.t0.Add(time.Duration(i)*time.Minute + time.Second),
+		Result:   result,
+	}
+	if result != "completed" {
+		execution.ExitCode = 1
+		execution.StderrTail = "boom"
+	}
+	err := unit.RecordHookExecution(execution)
+	c.Assert(err, jc.ErrorIsNil)
+	return execution
+}
+
+func (s *HookHistorySuite) TestRecordHookExecution(c *gc.C) {
+	install := s.record(c, s.unit, 0, "install", "completed")
+	configChanged := s.record(c, s.unit, 1, "config-changed", "failed")
+
+	history, err := s.unit.HookHistory(state.HookHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, jc.DeepEquals, []state.HookExecution{configChanged, install})
+}
+
+func (s *HookHistorySuite) TestHookHistoryFilter(c *gc.C) {
+	s.record(c, s.unit, 0, "install", "completed")
+	s.record(c, s.unit, 1, "config-changed", "failed")
+	s.record(c, s.unit, 2, "config-changed", "completed")
+	s.record(c, s.unit, 3, "start", "timed-out")
+	other := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.unitService(c)})
+	s.record(c, other, 4, "install", "completed")
+
+	assertNames := func(filter state.HookHistoryFilter, expect ...string) {
+		history, err := s.unit.HookHistory(filter)
+		c.Assert(err, jc.ErrorIsNil)
+		var names []string
+		for _, execution := range history {
+			names = append(names, execution.Name)
+		}
+		c.Check(names, jc.DeepEquals, expect)
+	}
+	assertNames(state.HookHistoryFilter{}, "start", "config-changed", "config-changed", "install")
+	assertNames(state.HookHistoryFilter{Name: "config-changed"}, "config-changed", "config-changed")
+	assertNames(state.HookHistoryFilter{Result: "failed"}, "config-changed")
+	assertNames(state.HookHistoryFilter{Since: s.t0.Add(2 * time.Minute)}, "start", "config-changed")
+	assertNames(state.HookHistoryFilter{Limit: 1}, "start")
+	assertNames(state.HookHistoryFilter{Kind: "action"})
+}
+
+func (s *HookHistorySuite) unitService(c *gc.C) *state.Service {
+	service, err := s.unit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	return service
+}
+
+func (s *HookHistorySuite) TestHookHistoryCapped(c *gc.C) {
+	for i := 0; i < state.MaxHookHistoryPerUnit+5; i++ {
+		s.record(c, s.unit, i, "update-status", "completed")
+	}
+	history, err := s.unit.HookHistory(state.HookHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, state.MaxHookHistoryPerUnit)
+	c.Assert(history[len(history)-1].Started, gc.Equals, s.t0.Add(5*time.Minute))
+}
+
+func (s *HookHistorySuite) TestStderrTailTruncated(c *gc.C) {
+	stderr := "lost" + strings.Repeat("x", state.MaxStderrTail)
+	err := s.unit.RecordHookExecution(state.HookExecution{
+		Kind:       "hook",
+		Name:       "install",
+		Started:    s.t0,
+		Finished:   s.t0,
+		Result:     "failed",
+		StderrTail: stderr,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	history, err := s.unit.HookHistory(state.HookHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].StderrTail, gc.Equals, strings.Repeat("x", state.MaxStderrTail))
+}
+
+func (s *HookHistorySuite) TestRemoveUnitErasesHookHistory(c *gc.C) {
+	s.record(c, s.unit, 0, "install", "completed")
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.unit.HookHistory(state.HookHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+}
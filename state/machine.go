@@ -153,6 +153,14 @@ type machineDoc struct {
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
 
+	// CloudInitUserData holds operator-supplied cloud-init user data
+	// to be merged with Juju's own when provisioning the machine.
+	CloudInitUserData string `bson:"cloudinit-userdata,omitempty"`
+
+	// RootDiskSource names the source from which the machine's root
+	// disk is created, if not the provider's default.
+	RootDiskSource string `bson:"root-disk-source,omitempty"`
+
 	// StopMongoUntilVersion holds the version that must be checked to
 	// know if mongo must be stopped.
 	StopMongoUntilVersion string `bson:",omitempty"`
@@ -1625,6 +1633,20 @@ func (m *Machine) Placement() string {
 	return m.doc.Placement
 }
 
+// CloudInitUserData returns the operator-supplied cloud-init user data
+// to be merged with Juju's own when provisioning an instance for the
+// machine.
+func (m *Machine) CloudInitUserData() string {
+	return m.doc.CloudInitUserData
+}
+
+// RootDiskSource returns the source from which the root disk of the
+// machine's instance should be created, or the empty string if the
+// provider's default should be used.
+func (m *Machine) RootDiskSource() string {
+	return m.doc.RootDiskSource
+}
+
 // Constraints returns the exact constraints that should apply when provisioning
 // an instance for the machine.
 func (m *Machine) Constraints() (constraints.Value, error) {
@@ -0,0 +1,210 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+)
+
+// machineProfileDoc represents the internal state of a machine profile
+// in MongoDB.
+type machineProfileDoc struct {
+	DocID             string `bson:"_id"`
+	Name              string `bson:"name"`
+	ModelUUID         string `bson:"model-uuid"`
+	Series            string `bson:"series,omitempty"`
+	Constraints       string `bson:"constraints,omitempty"`
+	CloudInitUserData string `bson:"cloudinit-userdata,omitempty"`
+	RootDiskSource    string `bson:"root-disk-source,omitempty"`
+}
+
+// MachineProfile is a named set of machine attributes, which may be
+// applied to the machines added to a model, or created for the units
+// of a service, so that they are configured consistently.
+type MachineProfile struct {
+	st  *State
+	doc machineProfileDoc
+}
+
+func newMachineProfile(st *State, doc *machineProfileDoc) *MachineProfile {
+	return &MachineProfile{
+		st:  st,
+		doc: *doc,
+	}
+}
+
+// Name returns the name of the profile.
+func (p *MachineProfile) Name() string {
+	return p.doc.Name
+}
+
+// String returns the name of the profile.
+func (p *MachineProfile) String() string {
+	return p.doc.Name
+}
+
+// Series returns the series of the machines created with the profile,
+// or the empty string if the profile does not specify one.
+func (p *MachineProfile) Series() string {
+	return p.doc.Series
+}
+
+// Constraints returns the constraints of the machines created with the
+// profile. Spaces and tags are expressed as constraints.
+func (p *MachineProfile) Constraints() constraints.Value {
+	// The constraints were validated when the profile was added.
+	return constraints.MustParse(p.doc.Constraints)
+}
+
+// CloudInitUserData returns the operator-supplied cloud-init user data
+// given to the machines created with the profile.
+func (p *MachineProfile) CloudInitUserData() string {
+	return p.doc.CloudInitUserData
+}
+
+// RootDiskSource returns the source from which the root disks of the
+// machines created with the profile are created, or the empty string
+// if the provider's default is used.
+func (p *MachineProfile) RootDiskSource() string {
+	return p.doc.RootDiskSource
+}
+
+// ApplyTo updates the supplied machine template with the profile's
+// attributes. Any series set in the template takes precedence over the
+// profile's, as do the template's constraints, user data and root-disk
+// source.
+func (p *MachineProfile) ApplyTo(template *MachineTemplate) error {
+	cons, err := p.MergeConstraints(template.Constraints)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	template.Constraints = cons
+	if template.Series == "" {
+		template.Series = p.doc.Series
+	}
+	if template.CloudInitUserData == "" {
+		template.CloudInitUserData = p.doc.CloudInitUserData
+	}
+	if template.RootDiskSource == "" {
+		template.RootDiskSource = p.doc.RootDiskSource
+	}
+	return nil
+}
+
+// MergeConstraints returns the profile's constraints, overridden by
+// those specified explicitly.
+func (p *MachineProfile) MergeConstraints(explicit constraints.Value) (constraints.Value, error) {
+	cons, err := constraints.NewValidator().Merge(p.Constraints(), explicit)
+	if err != nil {
+		return constraints.Value{}, errors.Annotatef(err, "cannot apply machine profile %q", p.doc.Name)
+	}
+	return cons, nil
+}
+
+// AddMachineProfileArgs contains the parameters for adding a machine
+// profile to a model.
+type AddMachineProfileArgs struct {
+	// Name is the name of the profile.
+	Name string
+
+	// Series is the series of machines created with the profile. If
+	// empty, the model's default series is used.
+	Series string
+
+	// Constraints holds the constraints of machines created with the
+	// profile.
+	Constraints constraints.Value
+
+	// CloudInitUserData holds operator-supplied cloud-init user data
+	// to be given to machines created with the profile. It is not
+	// interpreted by state.
+	CloudInitUserData string
+
+	// RootDiskSource, if not empty, names the source from which the
+	// root disks of machines created with the profile are created. It
+	// is not interpreted by state; the provider validates it.
+	RootDiskSource string
+}
+
+// AddMachineProfile adds a named machine profile to the model.
+func (st *State) AddMachineProfile(args AddMachineProfileArgs) (_ *MachineProfile, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add machine profile %q", args.Name)
+	if !instance.IsValidMachineProfileName(args.Name) {
+		return nil, errors.Errorf("invalid profile name")
+	}
+	docID := st.docID(args.Name)
+	doc := &machineProfileDoc{
+		DocID:             docID,
+		Name:              args.Name,
+		ModelUUID:         st.ModelUUID(),
+		Series:            args.Series,
+		Constraints:       args.Constraints.String(),
+		CloudInitUserData: args.CloudInitUserData,
+		RootDiskSource:    args.RootDiskSource,
+	}
+	ops := []txn.Op{{
+		C:      machineProfilesC,
+		Id:     docID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.AlreadyExistsf("profile")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newMachineProfile(st, doc), nil
+}
+
+// MachineProfile returns the machine profile with the given name.
+func (st *State) MachineProfile(name string) (*MachineProfile, error) {
+	machineProfiles, closer := st.getCollection(machineProfilesC)
+	defer closer()
+
+	doc := &machineProfileDoc{}
+	err := machineProfiles.FindId(name).One(doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("machine profile %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get machine profile %q", name)
+	}
+	return newMachineProfile(st, doc), nil
+}
+
+// AllMachineProfiles returns all the machine profiles in the model.
+func (st *State) AllMachineProfiles() (profiles []*MachineProfile, err error) {
+	machineProfiles, closer := st.getCollection(machineProfilesC)
+	defer closer()
+
+	docs := []machineProfileDoc{}
+	if err := machineProfiles.Find(bson.D{}).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all machine profiles")
+	}
+	for _, doc := range docs {
+		profiles = append(profiles, newMachineProfile(st, &doc))
+	}
+	return profiles, nil
+}
+
+// RemoveMachineProfile removes the named machine profile. Machines and
+// services created with the profile are not affected. It is not an
+// error to remove a profile that does not exist.
+func (st *State) RemoveMachineProfile(name string) error {
+	ops := []txn.Op{{
+		C:      machineProfilesC,
+		Id:     st.docID(name),
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove machine profile %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+type MachineProfileSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&MachineProfileSuite{})
+
+func (s *MachineProfileSuite) addProfile(c *gc.C, name string) *state.MachineProfile {
+	profile, err := s.State.AddMachineProfile(state.AddMachineProfileArgs{
+		Name:              name,
+		Series:            "trusty",
+		Constraints:       constraints.MustParse("mem=4G spaces=db tags=ssd"),
+		CloudInitUserData: "packages: [ca-certificates]",
+		RootDiskSource:    "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return profile
+}
+
+func (s *MachineProfileSuite) TestAddMachineProfile(c *gc.C) {
+	s.addProfile(c, "database")
+	profile, err := s.State.MachineProfile("database")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile.Name(), gc.Equals, "database")
+	c.Assert(profile.Series(), gc.Equals, "trusty")
+	c.Assert(profile.Constraints(), jc.DeepEquals, constraints.MustParse("mem=4G spaces=db tags=ssd"))
+	c.Assert(profile.CloudInitUserData(), gc.Equals, "packages: [ca-certificates]")
+	c.Assert(profile.RootDiskSource(), gc.Equals, "ssd")
+
+	_, err = s.State.AddMachineProfile(state.AddMachineProfileArgs{Name: "database"})
+	c.Assert(err, gc.ErrorMatches, `cannot add machine profile "database": profile already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+
+	_, err = s.State.AddMachineProfile(state.AddMachineProfileArgs{Name: "data_base"})
+	c.Assert(err, gc.ErrorMatches, `cannot add machine profile "data_base": invalid profile name`)
+
+	// Profile names are not service names; they may hold groups of
+	// digits.
+	_, err = s.State.AddMachineProfile(state.AddMachineProfileArgs{Name: "m4-2xlarge"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineProfileSuite) TestAllMachineProfiles(c *gc.C) {
+	s.addProfile(c, "web")
+	s.addProfile(c, "database")
+	profiles, err := s.State.AllMachineProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, gc.HasLen, 2)
+	c.Assert(profiles[0].Name(), gc.Equals, "database")
+	c.Assert(profiles[1].Name(), gc.Equals, "web")
+}
+
+func (s *MachineProfileSuite) TestRemoveMachineProfile(c *gc.C) {
+	s.addProfile(c, "database")
+	err := s.State.RemoveMachineProfile("database")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MachineProfile("database")
+	c.Assert(err, gc.ErrorMatches, `machine profile "database" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveMachineProfile("database")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineProfileSuite) TestApplyTo(c *gc.C) {
+	profile := s.addProfile(c, "database")
+	template := state.MachineTemplate{
+		Constraints: constraints.MustParse("mem=8G"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+	}
+	err := profile.ApplyTo(&template)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(template.Series, gc.Equals, "trusty")
+	c.Assert(template.Constraints, jc.DeepEquals, constraints.MustParse("mem=8G spaces=db tags=ssd"))
+	c.Assert(template.CloudInitUserData, gc.Equals, "packages: [ca-certificates]")
+	c.Assert(template.RootDiskSource, gc.Equals, "ssd")
+
+	m, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Series(), gc.Equals, "trusty")
+	c.Assert(m.CloudInitUserData(), gc.Equals, "packages: [ca-certificates]")
+	c.Assert(m.RootDiskSource(), gc.Equals, "ssd")
+	cons, err := m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=8G spaces=db tags=ssd"))
+}
+
+func (s *MachineProfileSuite) TestApplyToKeepsExplicitValues(c *gc.C) {
+	profile := s.addProfile(c, "database")
+	template := state.MachineTemplate{
+		Series:            "xenial",
+		CloudInitUserData: "runcmd: [true]",
+	}
+	err := profile.ApplyTo(&template)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(template.Series, gc.Equals, "xenial")
+	c.Assert(template.CloudInitUserData, gc.Equals, "runcmd: [true]")
+}
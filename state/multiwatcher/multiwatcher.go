@@ -182,17 +182,18 @@ func (i *ServiceInfo) EntityId() EntityId {
 // UnitInfo holds the information about a unit
 // that is tracked by multiwatcherStore.
 type UnitInfo struct {
-	ModelUUID      string
-	Name           string
-	Service        string
-	Series         string
-	CharmURL       string
-	PublicAddress  string
-	PrivateAddress string
-	MachineId      string
-	Ports          []network.Port
-	PortRanges     []network.PortRange
-	Subordinate    bool
+	ModelUUID       string
+	Name            string
+	Service         string
+	Series          string
+	CharmURL        string
+	WorkloadVersion string
+	PublicAddress   string
+	PrivateAddress  string
+	MachineId       string
+	Ports           []network.Port
+	PortRanges      []network.PortRange
+	Subordinate     bool
 	// The following 3 status values are deprecated.
 	Status     Status
 	StatusInfo string
@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -147,7 +148,7 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 		return nil, false, errAlreadyDying
 	}
 	if r.doc.UnitCount == 0 {
-		removeOps, err := r.removeOps(ignoreService, nil)
+		removeOps, err := r.removeOps(ignoreService, "")
 		if err != nil {
 			return nil, false, err
 		}
@@ -163,16 +164,17 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 
 // removeOps returns the operations necessary to remove the relation. If
 // ignoreService is not empty, no operations affecting that service will be
-// included; if departingUnit is not nil, this implies that the relation's
-// services may be Dying and otherwise unreferenced, and may thus require
-// removal themselves.
-func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.Op, error) {
+// included; if departingService is not empty, this implies that a unit of
+// that service is departing the relation, and that the relation's services
+// may be Dying and otherwise unreferenced, and may thus require removal
+// themselves.
+func (r *Relation) removeOps(ignoreService, departingService string) ([]txn.Op, error) {
 	relOp := txn.Op{
 		C:      relationsC,
 		Id:     r.doc.DocID,
 		Remove: true,
 	}
-	if departingUnit != nil {
+	if departingService != "" {
 		relOp.Assert = bson.D{{"life", Dying}, {"unitcount", 1}}
 	} else {
 		relOp.Assert = bson.D{{"life", Alive}, {"unitcount", 0}}
@@ -182,14 +184,26 @@ func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.O
 		if ep.ServiceName == ignoreService {
 			continue
 		}
+		isRemote, err := isRemoteService(r.st, ep.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+		if isRemote {
+			remoteOps, err := r.removeRemoteServiceOps(ep.ServiceName, departingService)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, remoteOps...)
+			continue
+		}
 		var asserts bson.D
 		hasRelation := bson.D{{"relationcount", bson.D{{"$gt", 0}}}}
-		if departingUnit == nil {
+		if departingService == "" {
 			// We're constructing a destroy operation, either of the relation
 			// or one of its services, and can therefore be assured that both
 			// services are Alive.
 			asserts = append(hasRelation, isAliveDoc...)
-		} else if ep.ServiceName == departingUnit.ServiceName() {
+		} else if ep.ServiceName == departingService {
 			// This service must have at least one unit -- the one that's
 			// departing the relation -- so it cannot be ready for removal.
 			cannotDieYet := bson.D{{"unitcount", bson.D{{"$gt", 0}}}}
@@ -227,6 +241,44 @@ func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.O
 	return append(ops, cleanupOp), nil
 }
 
+// removeRemoteServiceOps returns the operations necessary to release the
+// relation's reference to the named remote service; see removeOps. A
+// remote service has no units of its own, so it may be removed with its
+// last relation whenever it is Dying.
+func (r *Relation) removeRemoteServiceOps(serviceName, departingService string) ([]txn.Op, error) {
+	hasRelation := bson.D{{"relationcount", bson.D{{"$gt", 0}}}}
+	if departingService == "" {
+		asserts := append(hasRelation, isAliveDoc...)
+		return []txn.Op{{
+			C:      remoteServicesC,
+			Id:     r.st.docID(serviceName),
+			Assert: asserts,
+			Update: bson.D{{"$inc", bson.D{{"relationcount", -1}}}},
+		}}, nil
+	}
+	remoteServices, closer := r.st.getCollection(remoteServicesC)
+	defer closer()
+
+	svc := &RemoteService{st: r.st}
+	hasLastRef := bson.D{{"life", Dying}, {"relationcount", 1}}
+	removable := append(bson.D{{"_id", serviceName}}, hasLastRef...)
+	if err := remoteServices.Find(removable).One(&svc.doc); err == nil {
+		return svc.removeOps(hasLastRef), nil
+	} else if err != mgo.ErrNotFound {
+		return nil, err
+	}
+	asserts := bson.D{{"$or", []bson.D{
+		{{"life", Alive}},
+		{{"relationcount", bson.D{{"$gt", 1}}}},
+	}}}
+	return []txn.Op{{
+		C:      remoteServicesC,
+		Id:     r.st.docID(serviceName),
+		Assert: asserts,
+		Update: bson.D{{"$inc", bson.D{{"relationcount", -1}}}},
+	}}, nil
+}
+
 // Id returns the integer internal relation key. This is exposed
 // because the unit agent needs to expose a value derived from this
 // (as JUJU_RELATION_ID) to allow relation hooks to differentiate
@@ -290,7 +342,101 @@ func (r *Relation) Unit(u *Unit) (*RelationUnit, error) {
 		st:       r.st,
 		relation: r,
 		unit:     u,
+		unitName: u.doc.Name,
 		endpoint: ep,
 		scope:    strings.Join(scope, "#"),
 	}, nil
 }
+
+// RemoteUnit returns a RelationUnit for the named unit of a remote
+// service in the relation. Remote units have no presence in the model
+// other than their membership of relation scopes, which is managed on
+// their behalf by the relation proxy.
+func (r *Relation) RemoteUnit(unitName string) (*RelationUnit, error) {
+	serviceName, err := names.UnitService(unitName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ep, err := r.Endpoint(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if isRemote, err := isRemoteService(r.st, serviceName); err != nil {
+		return nil, errors.Trace(err)
+	} else if !isRemote {
+		return nil, errors.Errorf("service %q is not a remote service", serviceName)
+	}
+	return &RelationUnit{
+		st:       r.st,
+		relation: r,
+		unitName: unitName,
+		endpoint: ep,
+		scope:    "r#" + strconv.Itoa(r.doc.Id),
+	}, nil
+}
+
+// UnitsInScope returns the names of the units of the named service that
+// have entered, and are not preparing to leave, the relation's global
+// scope.
+func (r *Relation) UnitsInScope(serviceName string) ([]string, error) {
+	ep, err := r.Endpoint(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if ep.Scope != charm.ScopeGlobal {
+		return nil, errors.Errorf("relation %q does not have global scope", r)
+	}
+	relationScopes, closer := r.st.getCollection(relationScopesC)
+	defer closer()
+
+	prefix := fmt.Sprintf("r#%d#%s#%s/", r.doc.Id, ep.Role, serviceName)
+	sel := bson.D{
+		{"key", bson.D{{"$regex", "^" + regexp.QuoteMeta(prefix)}}},
+		{"departing", bson.D{{"$ne", true}}},
+	}
+	var docs []relationScopeDoc
+	if err := relationScopes.Find(sel).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get units in scope of relation %q", r)
+	}
+	unitNames := make([]string, len(docs))
+	for i, doc := range docs {
+		unitNames[i] = doc.unitName()
+	}
+	sort.Strings(unitNames)
+	return unitNames, nil
+}
+
+// AllUnitsInScope returns the names of the units, of any service, that
+// have entered, and are not preparing to leave, any scope of the
+// relation.
+func (r *Relation) AllUnitsInScope() ([]string, error) {
+	relationScopes, closer := r.st.getCollection(relationScopesC)
+	defer closer()
+
+	prefix := fmt.Sprintf("r#%d#", r.doc.Id)
+	sel := bson.D{
+		{"key", bson.D{{"$regex", "^" + regexp.QuoteMeta(prefix)}}},
+		{"departing", bson.D{{"$ne", true}}},
+	}
+	var docs []relationScopeDoc
+	if err := relationScopes.Find(sel).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get units in scope of relation %q", r)
+	}
+	unitNames := make([]string, len(docs))
+	for i, doc := range docs {
+		unitNames[i] = doc.unitName()
+	}
+	sort.Strings(unitNames)
+	return unitNames, nil
+}
+
+// UnitSettings returns the settings of the named unit within the
+// relation's global scope; see RelationUnit.ReadSettings.
+func (r *Relation) UnitSettings(unitName string) (map[string]interface{}, error) {
+	ru := &RelationUnit{
+		st:       r.st,
+		relation: r,
+		scope:    "r#" + strconv.Itoa(r.doc.Id),
+	}
+	return ru.ReadSettings(unitName)
+}
@@ -260,6 +260,35 @@ func (s *RelationSuite) TestDestroyRelation(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *RelationSuite) TestAllUnitsInScope(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var relUnits []*state.RelationUnit
+	for _, svc := range []*state.Service{wordpress, mysql} {
+		unit, err := svc.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		relUnit, err := rel.Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = relUnit.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+		relUnits = append(relUnits, relUnit)
+	}
+	units, err := rel.AllUnitsInScope()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"mysql/0", "wordpress/0"})
+
+	err = relUnits[0].LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = rel.AllUnitsInScope()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"mysql/0"})
+}
+
 func (s *RelationSuite) TestDestroyPeerRelation(c *gc.C) {
 	// Check that a peer relation cannot be destroyed directly.
 	riakch := s.AddTestingCharm(c, "riak")
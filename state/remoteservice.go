@@ -0,0 +1,437 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// remoteServiceDoc represents the internal state of a remote service in
+// MongoDB. A remote service stands in for a service in another model on
+// the same controller, so that local services may relate to it.
+type remoteServiceDoc struct {
+	DocID           string              `bson:"_id"`
+	Name            string              `bson:"name"`
+	ModelUUID       string              `bson:"model-uuid"`
+	URL             string              `bson:"url,omitempty"`
+	SourceModelUUID string              `bson:"source-model-uuid"`
+	SourceService   string              `bson:"source-service,omitempty"`
+	OfferName       string              `bson:"offer-name,omitempty"`
+	Endpoints       []remoteEndpointDoc `bson:"endpoints"`
+	Life            Life                `bson:"life"`
+	RelationCount   int                 `bson:"relationcount"`
+}
+
+// remoteEndpointDoc represents the internal state of a remote service
+// endpoint in MongoDB.
+type remoteEndpointDoc struct {
+	Name      string              `bson:"name"`
+	Role      charm.RelationRole  `bson:"role"`
+	Interface string              `bson:"interface"`
+	Limit     int                 `bson:"limit"`
+	Scope     charm.RelationScope `bson:"scope"`
+}
+
+// RemoteService represents the state of a service hosted in another
+// model on the same controller.
+type RemoteService struct {
+	st  *State
+	doc remoteServiceDoc
+}
+
+func newRemoteService(st *State, doc *remoteServiceDoc) *RemoteService {
+	return &RemoteService{
+		st:  st,
+		doc: *doc,
+	}
+}
+
+// Name returns the name of the remote service.
+func (s *RemoteService) Name() string {
+	return s.doc.Name
+}
+
+// String returns the name of the remote service.
+func (s *RemoteService) String() string {
+	return s.doc.Name
+}
+
+// URL returns the URL of the offer from which the remote service was
+// created. It is empty for remote services created by the relation
+// proxy to represent the consuming side of a cross-model relation.
+func (s *RemoteService) URL() string {
+	return s.doc.URL
+}
+
+// OfferName returns the name of the offer, in the source model, from
+// which the remote service was created; see URL.
+func (s *RemoteService) OfferName() string {
+	return s.doc.OfferName
+}
+
+// SourceModel returns the tag of the model hosting the service that
+// the remote service represents.
+func (s *RemoteService) SourceModel() names.ModelTag {
+	return names.NewModelTag(s.doc.SourceModelUUID)
+}
+
+// SourceService returns the name, within the source model, of the
+// service that the remote service represents.
+func (s *RemoteService) SourceService() string {
+	return s.doc.SourceService
+}
+
+// IsConsumerProxy returns whether the remote service was created by the
+// relation proxy to represent, within the model hosting an offer, the
+// service consuming that offer.
+func (s *RemoteService) IsConsumerProxy() bool {
+	return s.doc.OfferName == ""
+}
+
+// Life returns whether the remote service is Alive, Dying or Dead.
+func (s *RemoteService) Life() Life {
+	return s.doc.Life
+}
+
+// Endpoints returns the remote service's currently available relation
+// endpoints.
+func (s *RemoteService) Endpoints() ([]Endpoint, error) {
+	eps := make([]Endpoint, len(s.doc.Endpoints))
+	for i, ep := range s.doc.Endpoints {
+		eps[i] = Endpoint{
+			ServiceName: s.doc.Name,
+			Relation: charm.Relation{
+				Name:      ep.Name,
+				Role:      ep.Role,
+				Interface: ep.Interface,
+				Limit:     ep.Limit,
+				Scope:     ep.Scope,
+			},
+		}
+	}
+	return eps, nil
+}
+
+// Endpoint returns the relation endpoint with the supplied name, if it
+// exists.
+func (s *RemoteService) Endpoint(relationName string) (Endpoint, error) {
+	eps, err := s.Endpoints()
+	if err != nil {
+		return Endpoint{}, err
+	}
+	for _, ep := range eps {
+		if ep.Name == relationName {
+			return ep, nil
+		}
+	}
+	return Endpoint{}, fmt.Errorf("remote service %q has no %q relation", s, relationName)
+}
+
+// Relations returns a Relation for every relation the remote service
+// is in.
+func (s *RemoteService) Relations() ([]*Relation, error) {
+	return serviceRelations(s.st, s.doc.Name)
+}
+
+// Refresh refreshes the contents of the RemoteService from the
+// underlying state. It returns an error that satisfies
+// errors.IsNotFound if the remote service has been removed.
+func (s *RemoteService) Refresh() error {
+	remoteServices, closer := s.st.getCollection(remoteServicesC)
+	defer closer()
+
+	err := remoteServices.FindId(s.doc.DocID).One(&s.doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("remote service %q", s)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot refresh remote service %q", s)
+	}
+	return nil
+}
+
+// Destroy ensures that the remote service and all its relations will be
+// removed at some point; if no relation involving the remote service
+// has any units in scope, they are all removed immediately.
+func (s *RemoteService) Destroy() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot destroy remote service %q", s)
+	defer func() {
+		if err == nil {
+			// This is a white lie; the document might actually be removed.
+			s.doc.Life = Dying
+		}
+	}()
+	svc := &RemoteService{st: s.st, doc: s.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := svc.Refresh(); errors.IsNotFound(err) {
+				return nil, jujutxn.ErrNoOperations
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		switch ops, err := svc.destroyOps(); err {
+		case errRefresh:
+		case errAlreadyDying:
+			return nil, jujutxn.ErrNoOperations
+		case nil:
+			return ops, nil
+		default:
+			return nil, err
+		}
+		return nil, jujutxn.ErrTransientFailure
+	}
+	return s.st.run(buildTxn)
+}
+
+// destroyOps returns the operations required to destroy the remote
+// service. If it returns errRefresh, the remote service should be
+// refreshed and the destruction operations recalculated.
+func (s *RemoteService) destroyOps() ([]txn.Op, error) {
+	if s.doc.Life == Dying {
+		return nil, errAlreadyDying
+	}
+	rels, err := s.Relations()
+	if err != nil {
+		return nil, err
+	}
+	if len(rels) != s.doc.RelationCount {
+		// This is just an early bail out; see Service.destroyOps.
+		return nil, errRefresh
+	}
+	var ops []txn.Op
+	removeCount := 0
+	for _, rel := range rels {
+		relOps, isRemove, err := rel.destroyOps(s.doc.Name)
+		if err == errAlreadyDying {
+			relOps = []txn.Op{{
+				C:      relationsC,
+				Id:     rel.doc.DocID,
+				Assert: bson.D{{"life", Dying}},
+			}}
+		} else if err != nil {
+			return nil, err
+		}
+		if isRemove {
+			removeCount++
+		}
+		ops = append(ops, relOps...)
+	}
+	// If all its known relations will be removed, the remote service can
+	// also be removed.
+	if s.doc.RelationCount == removeCount {
+		hasLastRefs := bson.D{{"life", Alive}, {"relationcount", removeCount}}
+		return append(ops, s.removeOps(hasLastRefs)...), nil
+	}
+	// Otherwise, removal will be handled as a consequence of the removal
+	// of the last relation referencing it.
+	notLastRefs := bson.D{
+		{"life", Alive},
+		{"relationcount", s.doc.RelationCount},
+	}
+	update := bson.D{{"$set", bson.D{{"life", Dying}}}}
+	if removeCount != 0 {
+		decref := bson.D{{"$inc", bson.D{{"relationcount", -removeCount}}}}
+		update = append(update, decref...)
+	}
+	return append(ops, txn.Op{
+		C:      remoteServicesC,
+		Id:     s.doc.DocID,
+		Assert: notLastRefs,
+		Update: update,
+	}), nil
+}
+
+// removeOps returns the operations required to remove the remote
+// service. Supplied asserts will be included in the operation on the
+// remote service document.
+func (s *RemoteService) removeOps(asserts bson.D) []txn.Op {
+	return []txn.Op{{
+		C:      remoteServicesC,
+		Id:     s.doc.DocID,
+		Assert: asserts,
+		Remove: true,
+	}}
+}
+
+// AddRemoteServiceArgs contains the parameters for adding a remote
+// service to the model.
+type AddRemoteServiceArgs struct {
+	// Name is the name of the remote service within the model.
+	Name string
+
+	// URL is the URL of the offer from which the remote service is
+	// created, if any.
+	URL string
+
+	// SourceModel is the tag of the model hosting the service that the
+	// remote service represents.
+	SourceModel names.ModelTag
+
+	// SourceService is the name, within the source model, of the
+	// service that the remote service represents.
+	SourceService string
+
+	// OfferName is the name of the offer, in the source model, from
+	// which the remote service is created. It is empty for remote
+	// services created by the relation proxy to represent consumers.
+	OfferName string
+
+	// Endpoints holds the relation endpoints of the remote service.
+	Endpoints []charm.Relation
+}
+
+// AddRemoteService creates a new remote service record, representing
+// a service in another model on the controller.
+func (st *State) AddRemoteService(args AddRemoteServiceArgs) (_ *RemoteService, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add remote service %q", args.Name)
+
+	// Sanity checks.
+	if !names.IsValidService(args.Name) {
+		return nil, errors.Errorf("invalid name")
+	}
+	if args.SourceModel.Id() == st.ModelUUID() {
+		return nil, errors.Errorf("source model must not be the current model")
+	}
+	if len(args.Endpoints) == 0 {
+		return nil, errors.Errorf("no endpoints specified")
+	}
+	docEndpoints := make([]remoteEndpointDoc, len(args.Endpoints))
+	for i, ep := range args.Endpoints {
+		if ep.Role == charm.RolePeer {
+			return nil, errors.Errorf("endpoint %q is a peer relation", ep.Name)
+		}
+		if ep.Scope != charm.ScopeGlobal {
+			return nil, errors.Errorf("endpoint %q does not have global scope", ep.Name)
+		}
+		docEndpoints[i] = remoteEndpointDoc{
+			Name:      ep.Name,
+			Role:      ep.Role,
+			Interface: ep.Interface,
+			Limit:     ep.Limit,
+			Scope:     ep.Scope,
+		}
+	}
+	env, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	} else if env.Life() != Alive {
+		return nil, errors.Errorf("model is no longer alive")
+	}
+
+	docID := st.docID(args.Name)
+	doc := &remoteServiceDoc{
+		DocID:           docID,
+		Name:            args.Name,
+		ModelUUID:       st.ModelUUID(),
+		URL:             args.URL,
+		SourceModelUUID: args.SourceModel.Id(),
+		SourceService:   args.SourceService,
+		OfferName:       args.OfferName,
+		Endpoints:       docEndpoints,
+		Life:            Alive,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		// If we've tried once already and failed, check that the model
+		// may have been destroyed, or a service of the same name added.
+		if attempt > 0 {
+			if err := checkModeLife(st); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if err := checkServiceNameFree(st, args.Name); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{
+			env.assertAliveOp(),
+			{
+				C:      servicesC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+			}, {
+				C:      remoteServicesC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			},
+		}
+		return ops, nil
+	}
+	if err = st.run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newRemoteService(st, doc), nil
+}
+
+// checkServiceNameFree returns an error if a service or remote service
+// with the given name already exists in the model.
+func checkServiceNameFree(st *State, name string) error {
+	if exists, err := isNotDead(st, servicesC, name); err != nil {
+		return errors.Trace(err)
+	} else if exists {
+		return errors.Errorf("service already exists")
+	}
+	if exists, err := isNotDead(st, remoteServicesC, name); err != nil {
+		return errors.Trace(err)
+	} else if exists {
+		return errors.Errorf("remote service already exists")
+	}
+	return nil
+}
+
+// RemoteService returns a remote service state by name.
+func (st *State) RemoteService(name string) (_ *RemoteService, err error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	if !names.IsValidService(name) {
+		return nil, errors.Errorf("%q is not a valid remote service name", name)
+	}
+	doc := &remoteServiceDoc{}
+	err = remoteServices.FindId(name).One(doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("remote service %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get remote service %q", name)
+	}
+	return newRemoteService(st, doc), nil
+}
+
+// AllRemoteServices returns all the remote services in the model.
+func (st *State) AllRemoteServices() (services []*RemoteService, err error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	docs := []remoteServiceDoc{}
+	err = remoteServices.Find(bson.D{}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get all remote services")
+	}
+	for _, doc := range docs {
+		services = append(services, newRemoteService(st, &doc))
+	}
+	return services, nil
+}
+
+// isRemoteService returns whether the named service is a remote service
+// in the model.
+func isRemoteService(st *State, name string) (bool, error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	count, err := remoteServices.FindId(name).Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return count > 0, nil
+}
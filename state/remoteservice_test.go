@@ -0,0 +1,264 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type RemoteServiceSuite struct {
+	ConnSuite
+	sourceModel names.ModelTag
+	mysql       *state.RemoteService
+}
+
+var _ = gc.Suite(&RemoteServiceSuite{})
+
+var mysqlServerRelation = charm.Relation{
+	Name:      "server",
+	Role:      charm.RoleProvider,
+	Interface: "mysql",
+	Scope:     charm.ScopeGlobal,
+}
+
+func (s *RemoteServiceSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.sourceModel = names.NewModelTag(utils.MustNewUUID().String())
+	var err error
+	s.mysql, err = s.State.AddRemoteService(state.AddRemoteServiceArgs{
+		Name:          "mysql",
+		URL:           "admin@local/prod.mysql",
+		SourceModel:   s.sourceModel,
+		SourceService: "mysql",
+		OfferName:     "mysql",
+		Endpoints:     []charm.Relation{mysqlServerRelation},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RemoteServiceSuite) TestAddRemoteService(c *gc.C) {
+	svc, err := s.State.RemoteService("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.Name(), gc.Equals, "mysql")
+	c.Assert(svc.URL(), gc.Equals, "admin@local/prod.mysql")
+	c.Assert(svc.OfferName(), gc.Equals, "mysql")
+	c.Assert(svc.SourceModel(), gc.Equals, s.sourceModel)
+	c.Assert(svc.SourceService(), gc.Equals, "mysql")
+	c.Assert(svc.IsConsumerProxy(), jc.IsFalse)
+	c.Assert(svc.Life(), gc.Equals, state.Alive)
+	eps, err := svc.Endpoints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eps, jc.DeepEquals, []state.Endpoint{{
+		ServiceName: "mysql",
+		Relation:    mysqlServerRelation,
+	}})
+
+	all, err := s.State.AllRemoteServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	c.Assert(all[0].Name(), gc.Equals, "mysql")
+}
+
+func (s *RemoteServiceSuite) TestAddRemoteServiceErrors(c *gc.C) {
+	_, err := s.State.AddRemoteService(state.AddRemoteServiceArgs{
+		Name:        "mysql",
+		SourceModel: s.sourceModel,
+		Endpoints:   []charm.Relation{mysqlServerRelation},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add remote service "mysql": remote service already exists`)
+
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err = s.State.AddRemoteService(state.AddRemoteServiceArgs{
+		Name:        "wordpress",
+		SourceModel: s.sourceModel,
+		Endpoints:   []charm.Relation{mysqlServerRelation},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add remote service "wordpress": service already exists`)
+
+	_, err = s.State.AddRemoteService(state.AddRemoteServiceArgs{
+		Name:        "other",
+		SourceModel: s.State.ModelTag(),
+		Endpoints:   []charm.Relation{mysqlServerRelation},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add remote service "other": source model must not be the current model`)
+
+	containerRelation := mysqlServerRelation
+	containerRelation.Scope = charm.ScopeContainer
+	_, err = s.State.AddRemoteService(state.AddRemoteServiceArgs{
+		Name:        "other",
+		SourceModel: s.sourceModel,
+		Endpoints:   []charm.Relation{containerRelation},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add remote service "other": endpoint "server" does not have global scope`)
+}
+
+func (s *RemoteServiceSuite) TestAddServiceClashesWithRemoteService(c *gc.C) {
+	_, err := s.State.AddService(state.AddServiceArgs{
+		Name:  "mysql",
+		Owner: s.Owner.String(),
+		Charm: s.AddTestingCharm(c, "mysql"),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "mysql": remote service already exists`)
+}
+
+func (s *RemoteServiceSuite) TestInferEndpoints(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eps, jc.DeepEquals, []state.Endpoint{{
+		ServiceName: "wordpress",
+		Relation: charm.Relation{
+			Name:      "db",
+			Role:      charm.RoleRequirer,
+			Interface: "mysql",
+			Limit:     1,
+			Scope:     charm.ScopeGlobal,
+		},
+	}, {
+		ServiceName: "mysql",
+		Relation:    mysqlServerRelation,
+	}})
+}
+
+func (s *RemoteServiceSuite) addRelation(c *gc.C) (*state.Service, *state.Relation) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	return wordpress, rel
+}
+
+func (s *RemoteServiceSuite) TestAddRelation(c *gc.C) {
+	_, rel := s.addRelation(c)
+	c.Assert(rel.String(), gc.Equals, "wordpress:db mysql:server")
+
+	rels, err := s.mysql.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	c.Assert(rels[0].Id(), gc.Equals, rel.Id())
+}
+
+func (s *RemoteServiceSuite) TestRemoteUnitScope(c *gc.C) {
+	wordpress, rel := s.addRelation(c)
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	localRU, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = localRU.EnterScope(map[string]interface{}{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	remoteRU, err := rel.RemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = remoteRU.EnterScope(map[string]interface{}{"user": "admin"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	units, err := rel.UnitsInScope("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"mysql/0"})
+	units, err = rel.UnitsInScope("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"wordpress/0"})
+
+	settings, err := localRU.ReadSettings("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"user": "admin"})
+	settings, err = rel.UnitSettings("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"foo": "bar"})
+
+	_, err = remoteRU.PrivateAddress()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	err = remoteRU.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = rel.UnitsInScope("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *RemoteServiceSuite) TestRemoteUnitNotRemote(c *gc.C) {
+	_, rel := s.addRelation(c)
+	_, err := rel.RemoteUnit("wordpress/0")
+	c.Assert(err, gc.ErrorMatches, `service "wordpress" is not a remote service`)
+}
+
+func (s *RemoteServiceSuite) TestDestroyWithoutRelations(c *gc.C) {
+	err := s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteServiceSuite) TestDestroyWithRemoteUnitInScope(c *gc.C) {
+	_, rel := s.addRelation(c)
+	remoteRU, err := rel.RemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = remoteRU.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.Life(), gc.Equals, state.Dying)
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.Life(), gc.Equals, state.Dying)
+
+	// The last remote unit leaving scope removes both the relation
+	// and the remote service.
+	err = remoteRU.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteServiceSuite) TestDestroyServiceRelatedToRemoteService(c *gc.C) {
+	wordpress, rel := s.addRelation(c)
+	err := wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	rels, err := s.mysql.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 0)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteServiceSuite) TestWatchRemoteRelations(c *gc.C) {
+	w := s.State.WatchRemoteRelations()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Relations in unrelated models are ignored.
+	otherSt := s.NewStateForModelNamed(c, "other")
+	f := factory.NewFactory(otherSt)
+	f.MakeRelation(c, nil)
+	wc.AssertNoChange()
+
+	// Relating to the remote service triggers a change.
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
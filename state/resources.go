@@ -39,6 +39,16 @@ type Resources interface {
 	// OpenResource returns the metadata for a resource and a reader for the resource.
 	OpenResource(unit resource.Unit, name string) (resource.Resource, io.ReadCloser, error)
 
+	// SetUnitResource records that the unit is using the current
+	// revision of the named resource, which must have the given
+	// fingerprint.
+	SetUnitResource(unit resource.Unit, name string, fingerprint charmresource.Fingerprint) error
+
+	// RollbackResource makes a previous revision of the resource the
+	// active one and returns it. If revision is empty then the most
+	// recent previous revision is used.
+	RollbackResource(serviceID, name, revision string) (resource.Resource, error)
+
 	// NewResolvePendingResourcesOps generates mongo transaction operations
 	// to set the identified resources as active.
 	NewResolvePendingResourcesOps(serviceID string, pendingIDs map[string]string) ([]txn.Op, error)
@@ -0,0 +1,597 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// SecretsKeySize is the size, in bytes, of the AES-256 key with which
+// secrets are encrypted.
+const SecretsKeySize = 32
+
+// SecretRevisionsKept is the number of revisions of a secret that are
+// kept after they are replaced by an update, so that consumers that
+// read a secret before it was rotated can still read the content they
+// were given until they catch up.
+const SecretRevisionsKept = 5
+
+// SecretParams holds the parameters for adding a secret.
+type SecretParams struct {
+	// Owner is the service that owns the secret. The units of the
+	// owning service may read and update it, and grant access to it.
+	Owner names.ServiceTag
+
+	// Description describes the secret.
+	Description string
+
+	// Data holds the content of the secret.
+	Data map[string]string
+
+	// RotateInterval is the interval at which the owning service's
+	// leader is asked to rotate the secret by running the
+	// secret-rotate hook. If zero, it is never asked.
+	RotateInterval time.Duration
+}
+
+// Validate returns an error if the parameters are not valid.
+func (p SecretParams) Validate() error {
+	if p.Owner.Id() == "" {
+		return errors.NotValidf("missing owner")
+	}
+	if p.RotateInterval < 0 {
+		return errors.NotValidf("negative rotate interval %v", p.RotateInterval)
+	}
+	return validateSecretData(p.Data)
+}
+
+func validateSecretData(data map[string]string) error {
+	if len(data) == 0 {
+		return errors.NotValidf("empty secret")
+	}
+	for key := range data {
+		if key == "" {
+			return errors.NotValidf("empty secret key")
+		}
+	}
+	return nil
+}
+
+// Secret represents a piece of sensitive data owned by a service, such
+// as a password, which is encrypted at rest with a controller key.
+// Each update of a secret's content increments its revision.
+type Secret struct {
+	st  *State
+	doc secretDoc
+}
+
+type secretDoc struct {
+	DocID          string   `bson:"_id"`
+	ID             string   `bson:"id"`
+	ModelUUID      string   `bson:"model-uuid"`
+	Owner          string   `bson:"owner"`
+	Description    string   `bson:"description,omitempty"`
+	Revision       int      `bson:"revision"`
+	Data           []byte   `bson:"data"`
+	Grants         []string `bson:"grants,omitempty"`
+	RotateInterval int64    `bson:"rotate-interval,omitempty"`
+	NextRotateTime int64    `bson:"next-rotate-time,omitempty"`
+	Created        int64    `bson:"created"`
+	Updated        int64    `bson:"updated"`
+
+	// PreviousRevisions holds up to SecretRevisionsKept of the
+	// revisions replaced by updates, oldest first.
+	PreviousRevisions []secretRevisionDoc `bson:"previous-revisions,omitempty"`
+}
+
+// secretRevisionDoc holds a replaced revision of a secret's content.
+type secretRevisionDoc struct {
+	Revision int    `bson:"revision"`
+	Data     []byte `bson:"data"`
+	Updated  int64  `bson:"updated"`
+}
+
+// ID returns the secret's ID.
+func (s *Secret) ID() string {
+	return s.doc.ID
+}
+
+// Owner returns the tag of the service that owns the secret.
+func (s *Secret) Owner() names.ServiceTag {
+	return names.NewServiceTag(s.doc.Owner)
+}
+
+// Description returns the secret's description.
+func (s *Secret) Description() string {
+	return s.doc.Description
+}
+
+// Revision returns the secret's revision, starting at 1 and
+// incremented each time its content is updated.
+func (s *Secret) Revision() int {
+	return s.doc.Revision
+}
+
+// Grants returns the tags of the units and relations that have been
+// granted access to the secret.
+func (s *Secret) Grants() []names.Tag {
+	tags := make([]names.Tag, 0, len(s.doc.Grants))
+	for _, grant := range s.doc.Grants {
+		tag, err := names.ParseTag(grant)
+		if err != nil {
+			logger.Warningf("ignoring invalid grant %q of secret %q", grant, s.doc.ID)
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// RotateInterval returns the interval at which the secret is rotated,
+// or zero if it is not.
+func (s *Secret) RotateInterval() time.Duration {
+	return time.Duration(s.doc.RotateInterval)
+}
+
+// NextRotateTime returns the time at which the secret is next due to
+// be rotated, or the zero time if it is not rotated.
+func (s *Secret) NextRotateTime() time.Time {
+	if s.doc.NextRotateTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, s.doc.NextRotateTime).UTC()
+}
+
+// Created returns the time at which the secret was added.
+func (s *Secret) Created() time.Time {
+	return time.Unix(0, s.doc.Created).UTC()
+}
+
+// Updated returns the time at which the secret's content was last
+// updated.
+func (s *Secret) Updated() time.Time {
+	return time.Unix(0, s.doc.Updated).UTC()
+}
+
+// Data returns the decrypted content of the secret.
+func (s *Secret) Data() (map[string]string, error) {
+	return s.decryptData(s.doc.Revision, s.doc.Data)
+}
+
+// Revisions returns the revisions of the secret whose content can be
+// read with RevisionData, oldest first: the current revision and those
+// kept after being replaced.
+func (s *Secret) Revisions() []int {
+	revisions := make([]int, 0, len(s.doc.PreviousRevisions)+1)
+	for _, previous := range s.doc.PreviousRevisions {
+		revisions = append(revisions, previous.Revision)
+	}
+	return append(revisions, s.doc.Revision)
+}
+
+// RevisionData returns the decrypted content of the given revision of
+// the secret. It returns an error satisfying errors.IsNotFound if the
+// revision is not one of those returned by Revisions.
+func (s *Secret) RevisionData(revision int) (map[string]string, error) {
+	if revision == s.doc.Revision {
+		return s.Data()
+	}
+	for _, previous := range s.doc.PreviousRevisions {
+		if previous.Revision == revision {
+			return s.decryptData(previous.Revision, previous.Data)
+		}
+	}
+	return nil, errors.NotFoundf("revision %d of secret %q", revision, s.doc.ID)
+}
+
+// decryptData decrypts the content of the given revision of the secret.
+func (s *Secret) decryptData(revision int, encrypted []byte) (map[string]string, error) {
+	key, err := s.st.encryptionKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	plaintext, err := decryptSecret(key, encrypted, secretAAD(s.doc.DocID, revision))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot decrypt secret %q", s.doc.ID)
+	}
+	var data map[string]string
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, errors.Annotatef(err, "cannot decode secret %q", s.doc.ID)
+	}
+	return data, nil
+}
+
+// Refresh refreshes the contents of the secret from the underlying
+// state. It returns an error that satisfies errors.IsNotFound if the
+// secret has been removed.
+func (s *Secret) Refresh() error {
+	secret, err := s.st.Secret(s.doc.ID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc = secret.doc
+	return nil
+}
+
+// Update replaces the content of the secret, incrementing its
+// revision. The replaced revision is kept, along with up to
+// SecretRevisionsKept-1 earlier ones.
+func (s *Secret) Update(data map[string]string) error {
+	if err := validateSecretData(data); err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		encrypted, err := s.st.encryptSecretData(s.doc.DocID, s.doc.Revision+1, data)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		previous := append([]secretRevisionDoc(nil), s.doc.PreviousRevisions...)
+		previous = append(previous, secretRevisionDoc{
+			Revision: s.doc.Revision,
+			Data:     s.doc.Data,
+			Updated:  s.doc.Updated,
+		})
+		if len(previous) > SecretRevisionsKept {
+			previous = previous[len(previous)-SecretRevisionsKept:]
+		}
+		now := GetClock().Now()
+		update := bson.D{
+			{"data", encrypted},
+			{"revision", s.doc.Revision + 1},
+			{"updated", now.UnixNano()},
+			{"previous-revisions", previous},
+		}
+		if s.doc.RotateInterval > 0 {
+			update = append(update, bson.DocElem{
+				"next-rotate-time", now.Add(s.RotateInterval()).UnixNano(),
+			})
+		}
+		return []txn.Op{{
+			C:      secretsC,
+			Id:     s.doc.DocID,
+			Assert: bson.D{{"revision", s.doc.Revision}},
+			Update: bson.D{{"$set", update}},
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot update secret %q", s.doc.ID)
+	}
+	return s.Refresh()
+}
+
+// Grant gives the unit, or the units of the services in the relation,
+// with the given tag access to the secret.
+func (s *Secret) Grant(tag names.Tag) error {
+	return s.updateGrants(tag, "$addToSet")
+}
+
+// Revoke removes the access to the secret given to the unit or
+// relation with the given tag.
+func (s *Secret) Revoke(tag names.Tag) error {
+	return s.updateGrants(tag, "$pull")
+}
+
+func (s *Secret) updateGrants(tag names.Tag, operator string) error {
+	switch tag.(type) {
+	case names.UnitTag, names.RelationTag:
+	default:
+		return errors.NotValidf("grant to %q", tag)
+	}
+	ops := []txn.Op{{
+		C:      secretsC,
+		Id:     s.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{operator, bson.D{{"grants", tag.String()}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("secret %q", s.doc.ID)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot update grants of secret %q", s.doc.ID)
+	}
+	return s.Refresh()
+}
+
+// CanRead returns whether the given unit may read the secret: that is,
+// whether it is a unit of the owning service, has been granted access,
+// or its service is in a relation that has been granted access.
+func (s *Secret) CanRead(unitTag names.UnitTag) (bool, error) {
+	serviceName, err := names.UnitService(unitTag.Id())
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if serviceName == s.doc.Owner {
+		return true, nil
+	}
+	for _, tag := range s.Grants() {
+		switch tag := tag.(type) {
+		case names.UnitTag:
+			if tag == unitTag {
+				return true, nil
+			}
+		case names.RelationTag:
+			rel, err := s.st.KeyRelation(tag.Id())
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return false, errors.Trace(err)
+			}
+			if _, err := rel.Endpoint(serviceName); err == nil {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// Rotated records that the secret has been rotated, so that it is next
+// due to be rotated after its rotate interval.
+func (s *Secret) Rotated() error {
+	if s.doc.RotateInterval == 0 {
+		return errors.Errorf("secret %q is not rotated", s.doc.ID)
+	}
+	next := GetClock().Now().Add(s.RotateInterval())
+	ops := []txn.Op{{
+		C:      secretsC,
+		Id:     s.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"next-rotate-time", next.UnixNano()}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("secret %q", s.doc.ID)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot record rotation of secret %q", s.doc.ID)
+	}
+	s.doc.NextRotateTime = next.UnixNano()
+	return nil
+}
+
+// Remove removes the secret.
+func (s *Secret) Remove() error {
+	ops := []txn.Op{{
+		C:      secretsC,
+		Id:     s.doc.DocID,
+		Remove: true,
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove secret %q", s.doc.ID)
+	}
+	return nil
+}
+
+// AddSecret adds a secret, owned by a service, with the given
+// parameters.
+func (st *State) AddSecret(p SecretParams) (_ *Secret, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add secret")
+	if err := p.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	service, err := st.Service(p.Owner.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if service.Life() != Alive {
+		return nil, errors.Errorf("service %q is not alive", service.Name())
+	}
+	seq, err := st.sequence("secret")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id := strconv.Itoa(seq)
+	docID := st.docID(id)
+	encrypted, err := st.encryptSecretData(docID, 1, p.Data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	now := GetClock().Now()
+	doc := secretDoc{
+		DocID:          docID,
+		ID:             id,
+		ModelUUID:      st.ModelUUID(),
+		Owner:          service.Name(),
+		Description:    p.Description,
+		Revision:       1,
+		Data:           encrypted,
+		RotateInterval: int64(p.RotateInterval),
+		Created:        now.UnixNano(),
+		Updated:        now.UnixNano(),
+	}
+	if p.RotateInterval > 0 {
+		doc.NextRotateTime = now.Add(p.RotateInterval).UnixNano()
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     service.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      secretsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.Errorf("service %q is not alive", service.Name())
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Secret{st: st, doc: doc}, nil
+}
+
+// Secret returns the secret with the given ID.
+func (st *State) Secret(id string) (*Secret, error) {
+	secrets, closer := st.getCollection(secretsC)
+	defer closer()
+
+	var doc secretDoc
+	err := secrets.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("secret %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get secret %q", id)
+	}
+	return &Secret{st: st, doc: doc}, nil
+}
+
+// AllSecrets returns all the secrets in the model.
+func (st *State) AllSecrets() ([]*Secret, error) {
+	return st.secrets(nil)
+}
+
+// SecretsToRotate returns the secrets owned by the given service that
+// are due to be rotated.
+func (st *State) SecretsToRotate(owner names.ServiceTag) ([]*Secret, error) {
+	return st.secrets(bson.D{
+		{"owner", owner.Id()},
+		{"rotate-interval", bson.D{{"$gt", 0}}},
+		{"next-rotate-time", bson.D{{"$lte", GetClock().Now().UnixNano()}}},
+	})
+}
+
+func (st *State) secrets(query bson.D) ([]*Secret, error) {
+	secrets, closer := st.getCollection(secretsC)
+	defer closer()
+
+	var docs []secretDoc
+	if err := secrets.Find(query).Sort("id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get secrets")
+	}
+	result := make([]*Secret, len(docs))
+	for i, doc := range docs {
+		result[i] = &Secret{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// cleanupServiceSecrets removes the secrets owned by the named service.
+func (st *State) cleanupServiceSecrets(serviceName string) error {
+	secrets, err := st.secrets(bson.D{{"owner", serviceName}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, secret := range secrets {
+		if err := secret.Remove(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// encryptSecretData encrypts the content of the given revision of the
+// secret with the given document ID.
+func (st *State) encryptSecretData(docID string, revision int, data map[string]string) ([]byte, error) {
+	key, err := st.encryptionKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return encryptSecret(key, plaintext, secretAAD(docID, revision))
+}
+
+// NewSecretsKey returns a new random key for encrypting secrets.
+func NewSecretsKey() ([]byte, error) {
+	key := make([]byte, SecretsKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return key, nil
+}
+
+// SetSecretsKey sets the key with which the content of secrets is
+// encrypted and decrypted, and that of the States returned by
+// ForModel.
+//
+// The key is deliberately never written to the database, so that
+// access to the database, or to a backup of it, does not reveal the
+// content of secrets: controller agents hold it in their agent
+// configuration, which is created at bootstrap and copied over the API
+// to new controllers. The agent configuration is included in backups,
+// from which the key is restored along with the database; a database
+// restored without it leaves the content of secrets unreadable.
+func (st *State) SetSecretsKey(key []byte) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.secretsKey = key
+}
+
+// SecretsKey returns the key set by SetSecretsKey, if any.
+func (st *State) SecretsKey() []byte {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.secretsKey
+}
+
+// encryptionKey returns the key for encrypting secrets, or an error if
+// it has not been set.
+func (st *State) encryptionKey() ([]byte, error) {
+	key := st.SecretsKey()
+	if len(key) != SecretsKeySize {
+		return nil, errors.New("secrets key not available")
+	}
+	return key, nil
+}
+
+// secretAAD returns the additional authenticated data with which the
+// content of the given revision of the secret with the given document
+// ID is encrypted. It binds the ciphertext to the secret and revision,
+// so that content copied from one secret document, or revision, to
+// another fails to decrypt rather than being served as the other's.
+func secretAAD(docID string, revision int) []byte {
+	return []byte(docID + "#" + strconv.Itoa(revision))
+}
+
+// encryptSecret encrypts the plaintext with AES-GCM using the given
+// key and additional authenticated data, returning the nonce followed
+// by the ciphertext.
+func encryptSecret(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// decryptSecret decrypts data encrypted by encryptSecret with the same
+// additional authenticated data.
+func decryptSecret(key, data, aad []byte) ([]byte, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+func newSecretCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cipher.NewGCM(block)
+}
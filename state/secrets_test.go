@@ -0,0 +1,313 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SecretsSuite struct {
+	ConnSuite
+	clock     *coretesting.Clock
+	wordpress *state.Service
+	mysql     *state.Service
+}
+
+var _ = gc.Suite(&SecretsSuite{})
+
+func (s *SecretsSuite) SetUpTest(c *gc.C) {
+	s.clock = coretesting.NewClock(time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC))
+	s.PatchValue(&state.GetClock, func() clock.Clock {
+		return s.clock
+	})
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.mysql = s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+}
+
+func (s *SecretsSuite) addSecret(c *gc.C, rotateInterval time.Duration) *state.Secret {
+	secret, err := s.State.AddSecret(state.SecretParams{
+		Owner:          s.wordpress.ServiceTag(),
+		Description:    "db password",
+		Data:           map[string]string{"password": "sekrit"},
+		RotateInterval: rotateInterval,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return secret
+}
+
+func (s *SecretsSuite) TestAddSecret(c *gc.C) {
+	secret := s.addSecret(c, 0)
+	c.Assert(secret.ID(), gc.Not(gc.Equals), "")
+	c.Assert(secret.Owner(), gc.Equals, s.wordpress.ServiceTag())
+	c.Assert(secret.Description(), gc.Equals, "db password")
+	c.Assert(secret.Revision(), gc.Equals, 1)
+	c.Assert(secret.Grants(), gc.HasLen, 0)
+	c.Assert(secret.RotateInterval(), gc.Equals, time.Duration(0))
+	c.Assert(secret.NextRotateTime().IsZero(), jc.IsTrue)
+	c.Assert(secret.Created(), gc.Equals, s.clock.Now())
+	c.Assert(secret.Updated(), gc.Equals, s.clock.Now())
+
+	secret, err := s.State.Secret(secret.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := secret.Data()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "sekrit"})
+}
+
+func (s *SecretsSuite) TestSecretEncryptedAtRest(c *gc.C) {
+	secret := s.addSecret(c, 0)
+	coll, closer := state.GetCollection(s.State, "secrets")
+	defer closer()
+	var doc bson.M
+	err := coll.FindId(secret.ID()).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	raw, ok := doc["data"].([]byte)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(bytes.Contains(raw, []byte("sekrit")), jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestSecretDataBoundToSecretAndRevision(c *gc.C) {
+	secret := s.addSecret(c, 0)
+	other := s.addSecret(c, 0)
+	coll, closer := state.GetRawCollection(s.State, "secrets")
+	defer closer()
+
+	// Content copied from another secret is not accepted.
+	var doc bson.M
+	err := coll.FindId(state.DocID(s.State, secret.ID())).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	err = coll.UpdateId(state.DocID(s.State, other.ID()), bson.D{{"$set", bson.D{{"data", doc["data"]}}}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = other.Data()
+	c.Assert(err, gc.ErrorMatches, `cannot decrypt secret ".*": .*`)
+
+	// Nor is content claimed to be of another revision.
+	err = coll.UpdateId(state.DocID(s.State, secret.ID()), bson.D{{"$set", bson.D{{"revision", 2}}}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = secret.Data()
+	c.Assert(err, gc.ErrorMatches, `cannot decrypt secret ".*": .*`)
+}
+
+func (s *SecretsSuite) TestSecretsKeyRequired(c *gc.C) {
+	secret := s.addSecret(c, 0)
+	s.State.SetSecretsKey(nil)
+	defer s.State.SetSecretsKey(coretesting.SecretsKey)
+
+	_, err := secret.Data()
+	c.Assert(err, gc.ErrorMatches, "secrets key not available")
+	_, err = s.State.AddSecret(state.SecretParams{
+		Owner: s.wordpress.ServiceTag(),
+		Data:  map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, gc.ErrorMatches, ".*secrets key not available")
+}
+
+func (s *SecretsSuite) TestSecretsKeyNotInDatabase(c *gc.C) {
+	s.addSecret(c, 0)
+	collNames, err := s.State.MongoSession().DB("juju").CollectionNames()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, name := range collNames {
+		iter := s.State.MongoSession().DB("juju").C(name).Find(nil).Iter()
+		var doc bson.Raw
+		for iter.Next(&doc) {
+			c.Check(bytes.Contains(doc.Data, coretesting.SecretsKey), jc.IsFalse, gc.Commentf("collection %q", name))
+		}
+		c.Assert(iter.Close(), jc.ErrorIsNil)
+	}
+}
+
+func (s *SecretsSuite) TestForModelSharesSecretsKey(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	otherSt, err := s.State.ForModel(st.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	defer otherSt.Close()
+	c.Assert(otherSt.SecretsKey(), jc.DeepEquals, coretesting.SecretsKey)
+}
+
+func (s *SecretsSuite) TestAddSecretInvalid(c *gc.C) {
+	for i, test := range []struct {
+		params state.SecretParams
+		err    string
+	}{{
+		params: state.SecretParams{Owner: s.wordpress.ServiceTag()},
+		err:    "cannot add secret: empty secret not valid",
+	}, {
+		params: state.SecretParams{
+			Owner: s.wordpress.ServiceTag(),
+			Data:  map[string]string{"": "x"},
+		},
+		err: "cannot add secret: empty secret key not valid",
+	}, {
+		params: state.SecretParams{
+			Owner:          s.wordpress.ServiceTag(),
+			Data:           map[string]string{"a": "x"},
+			RotateInterval: -time.Second,
+		},
+		err: "cannot add secret: negative rotate interval -1s not valid",
+	}, {
+		params: state.SecretParams{
+			Owner: names.NewServiceTag("foo"),
+			Data:  map[string]string{"a": "x"},
+		},
+		err: `cannot add secret: service "foo" not found`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.AddSecret(test.params)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *SecretsSuite) TestUpdate(c *gc.C) {
+	secret := s.addSecret(c, 0)
+	s.clock.Advance(time.Minute)
+	err := secret.Update(map[string]string{"password": "hunter2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revision(), gc.Equals, 2)
+	c.Assert(secret.Updated(), gc.Equals, s.clock.Now())
+	data, err := secret.Data()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "hunter2"})
+}
+
+func (s *SecretsSuite) TestUpdateKeepsPreviousRevisions(c *gc.C) {
+	secret := s.addSecret(c, 0)
+	c.Assert(secret.Revisions(), jc.DeepEquals, []int{1})
+	err := secret.Update(map[string]string{"password": "hunter2"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	secret, err = s.State.Secret(secret.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revisions(), jc.DeepEquals, []int{1, 2})
+	data, err := secret.RevisionData(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "sekrit"})
+	data, err = secret.RevisionData(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "hunter2"})
+
+	// Only the most recent revisions are kept.
+	for i := 0; i < state.SecretRevisionsKept; i++ {
+		err := secret.Update(map[string]string{"password": "hunter2"})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(secret.Revisions(), jc.DeepEquals, []int{2, 3, 4, 5, 6, 7})
+	_, err = secret.RevisionData(1)
+	c.Assert(err, gc.ErrorMatches, `revision 1 of secret ".*" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	data, err = secret.RevisionData(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "hunter2"})
+}
+
+func (s *SecretsSuite) TestCanRead(c *gc.C) {
+	secret := s.addSecret(c, 0)
+	wordpress0 := names.NewUnitTag("wordpress/0")
+	mysql0 := names.NewUnitTag("mysql/0")
+	mysql1 := names.NewUnitTag("mysql/1")
+	logging0 := names.NewUnitTag("logging/0")
+
+	check := func(unit names.UnitTag, expect bool) {
+		ok, err := secret.CanRead(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(ok, gc.Equals, expect, gc.Commentf("%s", unit.Id()))
+	}
+	check(wordpress0, true)
+	check(mysql0, false)
+
+	err := secret.Grant(mysql1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Grants(), jc.DeepEquals, []names.Tag{mysql1})
+	check(mysql0, false)
+	check(mysql1, true)
+
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Grant(rel.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	check(mysql0, true)
+	check(logging0, false)
+
+	err = secret.Revoke(rel.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Grants(), jc.DeepEquals, []names.Tag{mysql1})
+	check(mysql0, false)
+}
+
+func (s *SecretsSuite) TestGrantInvalid(c *gc.C) {
+	secret := s.addSecret(c, 0)
+	err := secret.Grant(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, `grant to "machine-0" not valid`)
+}
+
+func (s *SecretsSuite) TestRotation(c *gc.C) {
+	rotated := s.addSecret(c, time.Hour)
+	s.addSecret(c, 0)
+	c.Assert(rotated.NextRotateTime(), gc.Equals, s.clock.Now().Add(time.Hour))
+
+	secrets, err := s.State.SecretsToRotate(s.wordpress.ServiceTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 0)
+
+	s.clock.Advance(time.Hour)
+	secrets, err = s.State.SecretsToRotate(s.wordpress.ServiceTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 1)
+	c.Assert(secrets[0].ID(), gc.Equals, rotated.ID())
+
+	secrets, err = s.State.SecretsToRotate(s.mysql.ServiceTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 0)
+
+	err = rotated.Rotated()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotated.NextRotateTime(), gc.Equals, s.clock.Now().Add(time.Hour))
+	secrets, err = s.State.SecretsToRotate(s.wordpress.ServiceTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 0)
+}
+
+func (s *SecretsSuite) TestAllSecrets(c *gc.C) {
+	secret0 := s.addSecret(c, 0)
+	secret1 := s.addSecret(c, time.Hour)
+	secrets, err := s.State.AllSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 2)
+	c.Assert(secrets[0].ID(), gc.Equals, secret0.ID())
+	c.Assert(secrets[1].ID(), gc.Equals, secret1.ID())
+}
+
+func (s *SecretsSuite) TestSecretNotFound(c *gc.C) {
+	_, err := s.State.Secret("42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `secret "42" not found`)
+}
+
+func (s *SecretsSuite) TestServiceRemovalRemovesSecrets(c *gc.C) {
+	secret := s.addSecret(c, 0)
+	err := s.wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Secret(secret.ID())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -22,6 +22,8 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 )
 
 // Service represents the state of a service.
@@ -44,6 +46,12 @@ type serviceDoc struct {
 	UnitCount         int        `bson:"unitcount"`
 	RelationCount     int        `bson:"relationcount"`
 	Exposed           bool       `bson:"exposed"`
+	ExposedCIDRs      []string   `bson:"exposed-cidrs,omitempty"`
+	LoadBalanced      bool       `bson:"load-balanced,omitempty"`
+	LoadBalancerAddr  string     `bson:"load-balancer-address,omitempty"`
+	ZonePolicy        string     `bson:"zone-policy,omitempty"`
+	CloudInitUserData string     `bson:"cloudinit-userdata,omitempty"`
+	RootDiskSource    string     `bson:"root-disk-source,omitempty"`
 	MinUnits          int        `bson:"minunits"`
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
@@ -244,6 +252,9 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		annotationRemoveOp(s.st, s.globalKey()),
 		removeLeadershipSettingsOp(s.Tag().Id()),
 		removeStatusOp(s.st, s.globalKey()),
+		s.st.newCleanupOp(cleanupServiceOffers, s.doc.Name),
+		s.st.newCleanupOp(cleanupServiceSecrets, s.doc.Name),
+		s.st.newCleanupOp(cleanupServiceStorage, s.doc.Name),
 	}
 	return ops
 }
@@ -255,29 +266,142 @@ func (s *Service) IsExposed() bool {
 	return s.doc.Exposed
 }
 
-// SetExposed marks the service as exposed.
-// See ClearExposed and IsExposed.
-func (s *Service) SetExposed() error {
-	return s.setExposed(true)
+// ExposedCIDRs returns the CIDRs of the addresses that may access the
+// explicitly open ports of an exposed service. If there are none, the
+// ports may be accessed from anywhere. See SetExposed.
+func (s *Service) ExposedCIDRs() []string {
+	return s.doc.ExposedCIDRs
 }
 
-// ClearExposed removes the exposed flag from the service.
+// SetExposed marks the service as exposed. If any CIDRs are specified,
+// only addresses within them may access the service's open ports.
+// See ClearExposed, IsExposed and ExposedCIDRs.
+func (s *Service) SetExposed(cidrs ...string) error {
+	cidrs, err := network.NormaliseCIDRs(cidrs)
+	if err != nil {
+		return errors.Annotatef(err, "cannot set exposed flag for service %q to true", s)
+	}
+	return s.setExposed(true, false, cidrs)
+}
+
+// SetExposedLoadBalanced marks the service as exposed through a
+// provider load balancer, which forwards the service's open ports to
+// its units. If any CIDRs are specified, only addresses within them
+// may access the service's open ports.
+// See SetExposed, IsLoadBalanced and LoadBalancerAddress.
+func (s *Service) SetExposedLoadBalanced(cidrs ...string) error {
+	cidrs, err := network.NormaliseCIDRs(cidrs)
+	if err != nil {
+		return errors.Annotatef(err, "cannot set exposed flag for service %q to true", s)
+	}
+	return s.setExposed(true, true, cidrs)
+}
+
+// ClearExposed removes the exposed flag from the service, along with
+// any request for a load balancer.
 // See SetExposed and IsExposed.
 func (s *Service) ClearExposed() error {
-	return s.setExposed(false)
+	return s.setExposed(false, false, nil)
 }
 
-func (s *Service) setExposed(exposed bool) (err error) {
+func (s *Service) setExposed(exposed, loadBalanced bool, cidrs []string) (err error) {
 	ops := []txn.Op{{
 		C:      servicesC,
 		Id:     s.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: bson.D{{"$set", bson.D{
+			{"exposed", exposed},
+			{"exposed-cidrs", cidrs},
+			{"load-balanced", loadBalanced},
+		}}},
 	}}
 	if err := s.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set exposed flag for service %q to %v: %v", s, exposed, onAbort(err, errNotAlive))
 	}
 	s.doc.Exposed = exposed
+	s.doc.ExposedCIDRs = cidrs
+	s.doc.LoadBalanced = loadBalanced
+	return nil
+}
+
+// IsLoadBalanced returns whether the service is exposed through a
+// provider load balancer. See SetExposedLoadBalanced.
+func (s *Service) IsLoadBalanced() bool {
+	return s.doc.LoadBalanced
+}
+
+// LoadBalancerAddress returns the virtual address of the provider
+// load balancer in front of the service, or an empty string if there
+// is none.
+func (s *Service) LoadBalancerAddress() string {
+	return s.doc.LoadBalancerAddr
+}
+
+// SetLoadBalancerAddress records the virtual address of the provider
+// load balancer in front of the service. It is set by the firewaller
+// once the load balancer has been created, and cleared once it has
+// been removed.
+func (s *Service) SetLoadBalancerAddress(address string) error {
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"load-balancer-address", address}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("service %q", s)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot set load balancer address for service %q", s)
+	}
+	s.doc.LoadBalancerAddr = address
+	return nil
+}
+
+// CloudInitUserData returns the operator-supplied cloud-init user data
+// given to the machines created for the service's units.
+func (s *Service) CloudInitUserData() string {
+	return s.doc.CloudInitUserData
+}
+
+// RootDiskSource returns the source from which the root disks of the
+// machines created for the service's units are created, or the empty
+// string if the provider's default is used.
+func (s *Service) RootDiskSource() string {
+	return s.doc.RootDiskSource
+}
+
+// ZonePolicy returns the policy governing the availability zones in
+// which machines are started for the service's units, or nil if none
+// has been set. See SetZonePolicy.
+func (s *Service) ZonePolicy() (*instance.ZonePolicy, error) {
+	if s.doc.ZonePolicy == "" {
+		return nil, nil
+	}
+	policy, err := instance.ParseZonePolicy(s.doc.ZonePolicy)
+	if err != nil {
+		return nil, errors.Annotatef(err, "service %q", s)
+	}
+	return &policy, nil
+}
+
+// SetZonePolicy sets the policy governing the availability zones in
+// which machines are started for the service's units. A nil policy
+// leaves the choice of zone to the provider.
+func (s *Service) SetZonePolicy(policy *instance.ZonePolicy) error {
+	var value string
+	if policy != nil {
+		value = policy.String()
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"zone-policy", value}}}},
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return errors.Errorf("cannot set zone policy for service %q: %v", s, onAbort(err, errNotAlive))
+	}
+	s.doc.ZonePolicy = value
 	return nil
 }
 
@@ -760,6 +884,11 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 	if err != nil {
 		return names, ops, err
 	}
+	limitOps, err := storagePoolLimitsOps(s.st, storageCons, 1)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	ops = append(ops, limitOps...)
 	// we verify the service is alive
 	asserts = append(isAliveDoc, asserts...)
 	ops = append(ops, s.incUnitCountOp(asserts))
@@ -770,6 +899,12 @@ type serviceAddUnitOpsArgs struct {
 	principalName string
 	cons          constraints.Value
 	storageCons   map[string]StorageConstraints
+
+	// sharedStorage, if non-nil, identifies the service's shared
+	// storage instances, which are being created along with the
+	// unit. If nil, the service's existing shared storage instances
+	// are looked up.
+	sharedStorage *sharedStorageInstances
 }
 
 // addServiceUnitOps is just like addUnitOps but explicitly takes a
@@ -794,8 +929,17 @@ func (s *Service) addUnitOpsWithCons(args serviceAddUnitOpsArgs) (string, []txn.
 		return "", nil, err
 	}
 
-	// Create instances of the charm's declared stores.
-	storageOps, numStorageAttachments, err := s.unitStorageOps(name, args.storageCons)
+	// Create instances of the charm's declared stores, and attach
+	// the unit to the service's shared storage instances.
+	sharedStorage := args.sharedStorage
+	if sharedStorage == nil {
+		shared, err := serviceSharedStorage(s.st, s.ServiceTag())
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		sharedStorage = &shared
+	}
+	storageOps, numStorageAttachments, err := s.unitStorageOps(name, args.storageCons, *sharedStorage)
 	if err != nil {
 		return "", nil, errors.Trace(err)
 	}
@@ -869,10 +1013,16 @@ func (s *Service) incUnitCountOp(asserts bson.D) txn.Op {
 }
 
 // unitStorageOps returns operations for creating storage
-// instances and attachments for a new unit. unitStorageOps
-// returns the number of initial storage attachments, to
-// initialise the unit's storage attachment refcount.
-func (s *Service) unitStorageOps(unitName string, cons map[string]StorageConstraints) (ops []txn.Op, numStorageAttachments int, err error) {
+// instances and attachments for a new unit, including
+// attachments to the specified shared storage instances.
+// unitStorageOps returns the number of initial storage
+// attachments, to initialise the unit's storage attachment
+// refcount.
+func (s *Service) unitStorageOps(
+	unitName string,
+	cons map[string]StorageConstraints,
+	sharedStorage sharedStorageInstances,
+) (ops []txn.Op, numStorageAttachments int, err error) {
 	charm, _, err := s.Charm()
 	if err != nil {
 		return nil, -1, err
@@ -885,6 +1035,7 @@ func (s *Service) unitStorageOps(unitName string, cons map[string]StorageConstra
 		s.st, tag, meta, url, cons,
 		s.doc.Series,
 		false, // unit is not assigned yet; don't create machine storage
+		sharedStorage,
 	)
 	if err != nil {
 		return nil, -1, errors.Trace(err)
@@ -907,19 +1058,25 @@ func (s *Service) GetOwnerTag() string {
 // AddUnit adds a new principal unit to the service.
 func (s *Service) AddUnit() (unit *Unit, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add unit to service %q", s)
-	name, ops, err := s.addUnitOps("", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
-		if alive, err := isAlive(s.st, servicesC, s.doc.DocID); err != nil {
-			return nil, err
-		} else if !alive {
-			return nil, fmt.Errorf("service is not alive")
+	var name string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			// The transaction may have been aborted because
+			// storage was created concurrently in a pool with
+			// capacity limits, so we try again unless the
+			// service has gone away.
+			if alive, err := isAlive(s.st, servicesC, s.doc.DocID); err != nil {
+				return nil, err
+			} else if !alive {
+				return nil, fmt.Errorf("service is not alive")
+			}
 		}
-		return nil, fmt.Errorf("inconsistent state")
-	} else if err != nil {
+		var ops []txn.Op
+		var err error
+		name, ops, err = s.addUnitOps("", nil)
+		return ops, err
+	}
+	if err := s.st.run(buildTxn); err != nil {
 		return nil, err
 	}
 	return s.st.Unit(name)
@@ -955,6 +1112,7 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		removeMeterStatusOp(s.st, u.globalMeterStatusKey()),
 		removeStatusOp(s.st, u.globalAgentKey()),
 		removeStatusOp(s.st, u.globalKey()),
+		removeUnitStateOp(s.st, u.globalKey()),
 		removeConstraintsOp(s.st, u.globalAgentKey()),
 		annotationRemoveOp(s.st, u.globalKey()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
@@ -20,6 +20,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/testing"
 	"github.com/juju/juju/storage/provider"
@@ -1253,6 +1254,104 @@ func (s *ServiceSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ServiceSuite) TestServiceExposedCIDRs(c *gc.C) {
+	c.Assert(s.mysql.ExposedCIDRs(), gc.HasLen, 0)
+
+	err := s.mysql.SetExposed("192.168.1.0/24", "10.1.2.3/8", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	expected := []string{"10.0.0.0/8", "192.168.1.0/24"}
+	c.Assert(s.mysql.ExposedCIDRs(), jc.DeepEquals, expected)
+
+	svc, err := s.State.Service(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedCIDRs(), jc.DeepEquals, expected)
+
+	// Exposing again without CIDRs opens the service to all.
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedCIDRs(), gc.HasLen, 0)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedCIDRs(), gc.HasLen, 0)
+
+	// Clearing the exposed flag clears the CIDRs.
+	err = s.mysql.SetExposed("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedCIDRs(), gc.HasLen, 0)
+}
+
+func (s *ServiceSuite) TestServiceExposedLoadBalanced(c *gc.C) {
+	c.Assert(s.mysql.IsLoadBalanced(), jc.IsFalse)
+	c.Assert(s.mysql.LoadBalancerAddress(), gc.Equals, "")
+
+	err := s.mysql.SetExposedLoadBalanced("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.IsLoadBalanced(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8"})
+
+	err = s.mysql.SetLoadBalancerAddress("203.0.113.10")
+	c.Assert(err, jc.ErrorIsNil)
+	svc, err := s.State.Service(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsLoadBalanced(), jc.IsTrue)
+	c.Assert(svc.LoadBalancerAddress(), gc.Equals, "203.0.113.10")
+
+	// Exposing again without a load balancer drops the request, but
+	// the address remains until the load balancer is removed.
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsLoadBalanced(), jc.IsFalse)
+	err = s.mysql.SetExposedLoadBalanced()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsLoadBalanced(), jc.IsFalse)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsLoadBalanced(), jc.IsFalse)
+	c.Assert(svc.LoadBalancerAddress(), gc.Equals, "203.0.113.10")
+
+	err = s.mysql.SetLoadBalancerAddress("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.LoadBalancerAddress(), gc.Equals, "")
+}
+
+func (s *ServiceSuite) TestServiceZonePolicy(c *gc.C) {
+	policy, err := s.mysql.ZonePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.IsNil)
+
+	err = s.mysql.SetZonePolicy(&instance.ZonePolicy{Zones: []string{"a", "b"}})
+	c.Assert(err, jc.ErrorIsNil)
+	svc, err := s.State.Service(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = svc.ZonePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, &instance.ZonePolicy{Zones: []string{"a", "b"}})
+
+	// A nil policy leaves the choice of zone to the provider.
+	err = s.mysql.SetZonePolicy(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = s.mysql.ZonePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.IsNil)
+
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetZonePolicy(&instance.ZonePolicy{Pack: true})
+	c.Assert(err, gc.ErrorMatches, `cannot set zone policy for service "mysql": not found or not alive`)
+}
+
+func (s *ServiceSuite) TestServiceExposedInvalidCIDR(c *gc.C) {
+	err := s.mysql.SetExposed("10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `cannot set exposed flag for service "mysql" to true: CIDR "10.0.0.0" not valid`)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+}
+
 func (s *ServiceSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit()
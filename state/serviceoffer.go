@@ -0,0 +1,422 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// serviceOfferDoc represents the internal state of a service offer in
+// MongoDB. An offer makes some of a service's endpoints available for
+// services in other models on the same controller to relate to.
+type serviceOfferDoc struct {
+	DocID       string   `bson:"_id"`
+	Name        string   `bson:"name"`
+	ModelUUID   string   `bson:"model-uuid"`
+	ServiceName string   `bson:"service-name"`
+	Endpoints   []string `bson:"endpoints"`
+}
+
+// ServiceOffer represents the state of a service offer.
+type ServiceOffer struct {
+	st  *State
+	doc serviceOfferDoc
+}
+
+func newServiceOffer(st *State, doc *serviceOfferDoc) *ServiceOffer {
+	return &ServiceOffer{
+		st:  st,
+		doc: *doc,
+	}
+}
+
+// Name returns the name of the offer.
+func (o *ServiceOffer) Name() string {
+	return o.doc.Name
+}
+
+// String returns the name of the offer.
+func (o *ServiceOffer) String() string {
+	return o.doc.Name
+}
+
+// ServiceName returns the name of the offered service.
+func (o *ServiceOffer) ServiceName() string {
+	return o.doc.ServiceName
+}
+
+// EndpointNames returns the names of the offered relation endpoints.
+func (o *ServiceOffer) EndpointNames() []string {
+	return o.doc.Endpoints
+}
+
+// Endpoints returns the offered relation endpoints of the service.
+func (o *ServiceOffer) Endpoints() ([]Endpoint, error) {
+	svc, err := o.st.Service(o.doc.ServiceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	eps := make([]Endpoint, len(o.doc.Endpoints))
+	for i, name := range o.doc.Endpoints {
+		ep, err := svc.Endpoint(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		eps[i] = ep
+	}
+	return eps, nil
+}
+
+// URL returns the URL with which the offer may be consumed from other
+// models on the controller.
+func (o *ServiceOffer) URL() (string, error) {
+	env, err := o.st.Model()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	url := ServiceOfferURL{
+		User:      env.Owner().Canonical(),
+		ModelName: env.Name(),
+		OfferName: o.doc.Name,
+	}
+	return url.String(), nil
+}
+
+// AddServiceOfferArgs contains the parameters for offering a service's
+// endpoints to other models.
+type AddServiceOfferArgs struct {
+	// OfferName is the name of the offer. If empty, the service name
+	// is used.
+	OfferName string
+
+	// ServiceName is the name of the offered service.
+	ServiceName string
+
+	// Endpoints holds the names of the offered relation endpoints. If
+	// empty, all of the service's endpoints that may be related to
+	// across models are offered.
+	Endpoints []string
+}
+
+// AddServiceOffer records an offer of a service's endpoints, allowing
+// services in other models on the controller to relate to them.
+func (st *State) AddServiceOffer(args AddServiceOfferArgs) (_ *ServiceOffer, err error) {
+	if args.OfferName == "" {
+		args.OfferName = args.ServiceName
+	}
+	defer errors.DeferredAnnotatef(&err, "cannot add service offer %q", args.OfferName)
+	if !names.IsValidService(args.OfferName) {
+		return nil, errors.Errorf("invalid offer name")
+	}
+	svc, err := st.Service(args.ServiceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if svc.Life() != Alive {
+		return nil, errors.Errorf("service %q is not alive", args.ServiceName)
+	}
+	endpoints, err := offerableEndpoints(svc, args.Endpoints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	docID := st.docID(args.OfferName)
+	doc := &serviceOfferDoc{
+		DocID:       docID,
+		Name:        args.OfferName,
+		ModelUUID:   st.ModelUUID(),
+		ServiceName: args.ServiceName,
+		Endpoints:   endpoints,
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     svc.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      serviceOffersC,
+		Id:     docID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if err := svc.Refresh(); err != nil {
+			return nil, errors.Trace(err)
+		} else if svc.Life() != Alive {
+			return nil, errors.Errorf("service %q is not alive", args.ServiceName)
+		}
+		return nil, errors.Errorf("offer already exists")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newServiceOffer(st, doc), nil
+}
+
+// offerableEndpoints returns the names of the service's endpoints that
+// are to be offered, given the names requested by the user. Only
+// globally scoped, non-peer endpoints may be offered.
+func offerableEndpoints(svc *Service, requested []string) ([]string, error) {
+	eps, err := svc.Endpoints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	offerable := make(map[string]bool)
+	var all []string
+	for _, ep := range eps {
+		if ep.Role == charm.RolePeer || ep.Scope != charm.ScopeGlobal || ep.IsImplicit() {
+			continue
+		}
+		offerable[ep.Name] = true
+		all = append(all, ep.Name)
+	}
+	if len(requested) == 0 {
+		if len(all) == 0 {
+			return nil, errors.Errorf("service %q has no endpoints that can be offered", svc)
+		}
+		return all, nil
+	}
+	for _, name := range requested {
+		if !offerable[name] {
+			return nil, errors.Errorf("service %q has no %q endpoint that can be offered", svc, name)
+		}
+	}
+	return requested, nil
+}
+
+// ServiceOffer returns the service offer with the given name.
+func (st *State) ServiceOffer(name string) (*ServiceOffer, error) {
+	serviceOffers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	doc := &serviceOfferDoc{}
+	err := serviceOffers.FindId(name).One(doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("service offer %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get service offer %q", name)
+	}
+	return newServiceOffer(st, doc), nil
+}
+
+// AllServiceOffers returns all the service offers in the model.
+func (st *State) AllServiceOffers() (offers []*ServiceOffer, err error) {
+	serviceOffers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	docs := []serviceOfferDoc{}
+	if err := serviceOffers.Find(bson.D{}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all service offers")
+	}
+	for _, doc := range docs {
+		offers = append(offers, newServiceOffer(st, &doc))
+	}
+	return offers, nil
+}
+
+// RemoveServiceOffer removes the named service offer. Existing relations
+// established through the offer are not affected. It is not an error
+// to remove an offer that does not exist.
+func (st *State) RemoveServiceOffer(name string) error {
+	ops := []txn.Op{{
+		C:      serviceOffersC,
+		Id:     st.docID(name),
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove service offer %q", name)
+	}
+	return nil
+}
+
+// cleanupServiceOffers removes the offers of the named service, which
+// has been removed.
+func (st *State) cleanupServiceOffers(serviceName string) error {
+	serviceOffers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var docs []serviceOfferDoc
+	if err := serviceOffers.Find(bson.D{{"service-name", serviceName}}).All(&docs); err != nil {
+		return errors.Annotatef(err, "cannot get offers for service %q", serviceName)
+	}
+	for _, doc := range docs {
+		if err := st.RemoveServiceOffer(doc.Name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// ServiceOfferURL identifies a service offer made from a model on the
+// controller. Its string form is "<user>/<model>.<offer>".
+type ServiceOfferURL struct {
+	// User is the owner of the model hosting the offer.
+	User string
+
+	// ModelName is the name of the model hosting the offer.
+	ModelName string
+
+	// OfferName is the name of the offer.
+	OfferName string
+}
+
+// String returns the string form of the URL.
+func (u ServiceOfferURL) String() string {
+	return fmt.Sprintf("%s/%s.%s", u.User, u.ModelName, u.OfferName)
+}
+
+// IsServiceOfferURL returns whether the supplied string looks like a
+// service offer URL rather than a service or endpoint name.
+func IsServiceOfferURL(s string) bool {
+	return strings.Contains(s, "/")
+}
+
+// ParseServiceOfferURL parses a service offer URL of the form
+// "<user>/<model>.<offer>".
+func ParseServiceOfferURL(s string) (ServiceOfferURL, error) {
+	invalid := errors.NotValidf("service offer URL %q", s)
+	slash := strings.Index(s, "/")
+	dot := strings.LastIndex(s, ".")
+	if slash <= 0 || dot <= slash+1 || dot == len(s)-1 {
+		return ServiceOfferURL{}, invalid
+	}
+	url := ServiceOfferURL{
+		User:      s[:slash],
+		ModelName: s[slash+1 : dot],
+		OfferName: s[dot+1:],
+	}
+	if !names.IsValidUser(url.User) || !names.IsValidService(url.OfferName) {
+		return ServiceOfferURL{}, invalid
+	}
+	return url, nil
+}
+
+// ConsumeServiceOffer adds a remote service to the model representing
+// the service offered at the supplied URL, so that local services may
+// relate to it. The user must have access to the model hosting the
+// offer. The remote service is named after both the offer and that
+// model (see RemoteServiceName); if a remote service for the same offer
+// already exists, it is returned.
+func (st *State) ConsumeServiceOffer(offerURL string, user names.UserTag) (_ *RemoteService, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot consume service offer %q", offerURL)
+	url, err := ParseServiceOfferURL(offerURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	url.User = names.NewUserTag(url.User).Canonical()
+
+	sourceModel, err := st.modelForOfferURL(url)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if sourceModel.UUID() == st.ModelUUID() {
+		return nil, errors.Errorf("cannot consume an offer from the same model")
+	}
+	sourceSt, err := st.ForModel(sourceModel.ModelTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer sourceSt.Close()
+	if err := checkCanConsume(sourceSt, sourceModel, user); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	existing, err := st.remoteServiceForURL(url.String())
+	if err == nil {
+		return existing, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	name, err := RemoteServiceName(url.OfferName, url.ModelName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	offer, err := sourceSt.ServiceOffer(url.OfferName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	eps, err := offer.Endpoints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relations := make([]charm.Relation, len(eps))
+	for i, ep := range eps {
+		relations[i] = ep.Relation
+	}
+	return st.AddRemoteService(AddRemoteServiceArgs{
+		Name:          name,
+		URL:           url.String(),
+		SourceModel:   sourceModel.ModelTag(),
+		SourceService: offer.ServiceName(),
+		OfferName:     url.OfferName,
+		Endpoints:     relations,
+	})
+}
+
+// checkCanConsume returns an error satisfying errors.IsUnauthorized if
+// the user may not consume offers from the model; that requires read
+// or write access to the model.
+func checkCanConsume(sourceSt *State, sourceModel *Model, user names.UserTag) error {
+	if user.Canonical() == sourceModel.Owner().Canonical() {
+		return nil
+	}
+	_, err := sourceSt.ModelUser(user)
+	if errors.IsNotFound(err) {
+		return errors.Unauthorizedf("user %q does not have access to model %q", user.Canonical(), sourceModel.Name())
+	}
+	return errors.Trace(err)
+}
+
+// RemoteServiceName returns the name of the remote service representing,
+// in another model, the named service (or offer) in the named model.
+// The model name is included so that services with the same name in
+// different models do not clash with each other, or with local services.
+func RemoteServiceName(serviceName, modelName string) (string, error) {
+	name := serviceName + "-" + modelName
+	if !names.IsValidService(name) {
+		return "", errors.NotValidf("remote service name %q", name)
+	}
+	return name, nil
+}
+
+// remoteServiceForURL returns the remote service created from the offer
+// with the supplied URL.
+func (st *State) remoteServiceForURL(url string) (*RemoteService, error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	doc := &remoteServiceDoc{}
+	err := remoteServices.Find(bson.D{{"url", url}}).One(doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("remote service for offer %q", url)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get remote service for offer %q", url)
+	}
+	return newRemoteService(st, doc), nil
+}
+
+// modelForOfferURL returns the model on the controller hosting the
+// offer with the supplied URL.
+func (st *State) modelForOfferURL(url ServiceOfferURL) (*Model, error) {
+	models, err := st.AllModels()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	owner := names.NewUserTag(url.User).Canonical()
+	for _, model := range models {
+		if model.Name() == url.ModelName && model.Owner().Canonical() == owner {
+			return model, nil
+		}
+	}
+	return nil, errors.NotFoundf("model %q", url.User+"/"+url.ModelName)
+}
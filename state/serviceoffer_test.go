@@ -0,0 +1,192 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ServiceOfferSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ServiceOfferSuite{})
+
+func (s *ServiceOfferSuite) TestAddServiceOffer(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	offer, err := s.State.AddServiceOffer(state.AddServiceOfferArgs{
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.Name(), gc.Equals, "mysql")
+	c.Assert(offer.ServiceName(), gc.Equals, "mysql")
+	c.Assert(offer.EndpointNames(), jc.DeepEquals, []string{"server"})
+	url, err := offer.URL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(url, gc.Equals, "test-admin@local/testenv.mysql")
+
+	offer, err = s.State.ServiceOffer("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	eps, err := offer.Endpoints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eps, gc.HasLen, 1)
+	c.Assert(eps[0].String(), gc.Equals, "mysql:server")
+
+	offers, err := s.State.AllServiceOffers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers, gc.HasLen, 1)
+}
+
+func (s *ServiceOfferSuite) TestAddServiceOfferErrors(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := s.State.AddServiceOffer(state.AddServiceOfferArgs{
+		ServiceName: "mysql",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service offer "mysql": service "mysql" not found`)
+
+	_, err = s.State.AddServiceOffer(state.AddServiceOfferArgs{
+		ServiceName: "wordpress",
+		Endpoints:   []string{"logging-dir"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service offer "wordpress": service "wordpress" has no "logging-dir" endpoint that can be offered`)
+
+	_, err = s.State.AddServiceOffer(state.AddServiceOfferArgs{
+		OfferName:   "blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddServiceOffer(state.AddServiceOfferArgs{
+		OfferName:   "blog",
+		ServiceName: "wordpress",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service offer "blog": offer already exists`)
+}
+
+func (s *ServiceOfferSuite) TestRemoveServiceOffer(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err := s.State.AddServiceOffer(state.AddServiceOfferArgs{
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveServiceOffer("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ServiceOffer("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ServiceOfferSuite) TestOffersRemovedWithService(c *gc.C) {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err := s.State.AddServiceOffer(state.AddServiceOfferArgs{
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ServiceOffer("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ServiceOfferSuite) TestParseServiceOfferURL(c *gc.C) {
+	url, err := state.ParseServiceOfferURL("bob@local/prod.mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(url, jc.DeepEquals, state.ServiceOfferURL{
+		User:      "bob@local",
+		ModelName: "prod",
+		OfferName: "mysql",
+	})
+	c.Assert(url.String(), gc.Equals, "bob@local/prod.mysql")
+
+	for _, invalid := range []string{
+		"mysql", "bob/mysql", "/prod.mysql", "bob/.mysql", "bob/prod.", "bob/prod.my_sql",
+	} {
+		_, err := state.ParseServiceOfferURL(invalid)
+		c.Check(err, jc.Satisfies, errors.IsNotValid, gc.Commentf("%q", invalid))
+	}
+}
+
+func (s *ServiceOfferSuite) TestConsumeServiceOffer(c *gc.C) {
+	otherSt := s.NewStateForModelNamed(c, "prod")
+	ch := state.AddTestingCharm(c, otherSt, "mysql")
+	state.AddTestingService(c, otherSt, "mysql", ch, s.Owner)
+	_, err := otherSt.AddServiceOffer(state.AddServiceOfferArgs{
+		OfferName:   "db",
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	remote, err := s.State.ConsumeServiceOffer("test-admin/prod.db", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remote.Name(), gc.Equals, "db-prod")
+	c.Assert(remote.URL(), gc.Equals, "test-admin@local/prod.db")
+	c.Assert(remote.SourceModel(), gc.Equals, otherSt.ModelTag())
+	eps, err := remote.Endpoints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eps, gc.HasLen, 1)
+	c.Assert(eps[0].String(), gc.Equals, "db:server")
+
+	// Consuming the same offer again returns the same remote service.
+	again, err := s.State.ConsumeServiceOffer("test-admin@local/prod.db", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again.Name(), gc.Equals, "db-prod")
+
+	_, err = s.State.ConsumeServiceOffer("test-admin/nonexistent.db", s.Owner)
+	c.Assert(err, gc.ErrorMatches, `cannot consume service offer "test-admin/nonexistent.db": model "test-admin@local/nonexistent" not found`)
+}
+
+func (s *ServiceOfferSuite) TestConsumeServiceOfferNameClash(c *gc.C) {
+	otherSt := s.NewStateForModelNamed(c, "prod")
+	ch := state.AddTestingCharm(c, otherSt, "mysql")
+	state.AddTestingService(c, otherSt, "mysql", ch, s.Owner)
+	_, err := otherSt.AddServiceOffer(state.AddServiceOfferArgs{
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A local service named after the offer does not clash with it...
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	remote, err := s.State.ConsumeServiceOffer("test-admin/prod.mysql", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remote.Name(), gc.Equals, "mysql-prod")
+
+	// ...but one with the remote service's name does.
+	err = remote.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddTestingService(c, "mysql-prod", s.AddTestingCharm(c, "mysql"))
+	_, err = s.State.ConsumeServiceOffer("test-admin/prod.mysql", s.Owner)
+	c.Assert(err, gc.ErrorMatches, `cannot consume service offer "test-admin/prod.mysql": cannot add remote service "mysql-prod": service already exists`)
+}
+
+func (s *ServiceOfferSuite) TestConsumeServiceOfferUnauthorized(c *gc.C) {
+	otherSt := s.NewStateForModelNamed(c, "prod")
+	ch := state.AddTestingCharm(c, otherSt, "mysql")
+	state.AddTestingService(c, otherSt, "mysql", ch, s.Owner)
+	_, err := otherSt.AddServiceOffer(state.AddServiceOfferArgs{
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, err = s.State.ConsumeServiceOffer("test-admin/prod.mysql", bob.UserTag())
+	c.Assert(err, gc.ErrorMatches, `cannot consume service offer "test-admin/prod.mysql": user "bob@local" does not have access to model "prod"`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+
+	// Read-only access to the offering model is enough.
+	_, err = otherSt.AddModelUser(state.ModelUserSpec{
+		User:      bob.UserTag(),
+		CreatedBy: s.Owner,
+		ReadOnly:  true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	remote, err := s.State.ConsumeServiceOffer("test-admin/prod.mysql", bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remote.Name(), gc.Equals, "mysql-prod")
+}
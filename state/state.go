@@ -85,11 +85,13 @@ type State struct {
 	// for managing this state's environment.
 	singularManager *lease.Manager
 
-	// mu guards allManager, allModelManager & allModelWatcherBacking
+	// mu guards allManager, allModelManager, allModelWatcherBacking
+	// & secretsKey
 	mu                     sync.Mutex
 	allManager             *storeManager
 	allModelManager        *storeManager
 	allModelWatcherBacking Backing
+	secretsKey             []byte
 
 	// TODO(anastasiamac 2015-07-16) As state gets broken up, remove this.
 	CloudImageMetadataStorage cloudimagemetadata.Storage
@@ -178,6 +180,7 @@ func (st *State) ForModel(env names.ModelTag) (*State, error) {
 	if err := newState.start(st.controllerTag); err != nil {
 		return nil, errors.Trace(err)
 	}
+	newState.SetSecretsKey(st.SecretsKey())
 	return newState, nil
 }
 
@@ -1150,6 +1153,15 @@ type AddServiceArgs struct {
 	Placement        []*instance.Placement
 	Constraints      constraints.Value
 	Resources        map[string]string
+
+	// CloudInitUserData holds operator-supplied cloud-init user data
+	// to be given to the machines created for the service's units.
+	CloudInitUserData string
+
+	// RootDiskSource, if not empty, names the source from which the
+	// root disks of the machines created for the service's units are
+	// created.
+	RootDiskSource string
 }
 
 // AddService creates a new service, running the supplied charm, with the
@@ -1168,10 +1180,8 @@ func (st *State) AddService(args AddServiceArgs) (service *Service, err error) {
 	if args.Charm == nil {
 		return nil, errors.Errorf("charm is nil")
 	}
-	if exists, err := isNotDead(st, servicesC, args.Name); err != nil {
+	if err := checkServiceNameFree(st, args.Name); err != nil {
 		return nil, errors.Trace(err)
-	} else if exists {
-		return nil, errors.Errorf("service already exists")
 	}
 	env, err := st.Model()
 	if err != nil {
@@ -1279,6 +1289,9 @@ func (st *State) AddService(args AddServiceArgs) (service *Service, err error) {
 		RelationCount: len(peers),
 		Life:          Alive,
 		OwnerTag:      args.Owner,
+
+		CloudInitUserData: args.CloudInitUserData,
+		RootDiskSource:    args.RootDiskSource,
 	}
 
 	svc := newService(st, svcDoc)
@@ -1311,6 +1324,11 @@ func (st *State) AddService(args AddServiceArgs) (service *Service, err error) {
 		[]txn.Op{
 			env.assertAliveOp(),
 			endpointBindingsOp,
+			{
+				C:      remoteServicesC,
+				Id:     serviceID,
+				Assert: txn.DocMissing,
+			},
 		},
 		addServiceOps(st, addServiceOpsArgs{
 			serviceDoc:       svcDoc,
@@ -1344,9 +1362,24 @@ func (st *State) AddService(args AddServiceArgs) (service *Service, err error) {
 		ops = append(ops, resOps...)
 	}
 
+	// Collect shared storage creation operations. The shared storage
+	// instances are owned by the service, and attached to each unit.
+	sharedStorageOps, sharedStorage, err := createSharedStorageOps(
+		st, svc.ServiceTag(), args.Charm.Meta(), args.Charm.URL(),
+		args.Storage, args.NumUnits,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, sharedStorageOps...)
+
 	// Collect unit-adding operations.
 	for x := 0; x < args.NumUnits; x++ {
-		unitName, unitOps, err := svc.addServiceUnitOps(serviceAddUnitOpsArgs{cons: args.Constraints, storageCons: args.Storage})
+		unitName, unitOps, err := svc.addServiceUnitOps(serviceAddUnitOpsArgs{
+			cons:          args.Constraints,
+			storageCons:   args.Storage,
+			sharedStorage: &sharedStorage,
+		})
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -1360,12 +1393,27 @@ func (st *State) AddService(args AddServiceArgs) (service *Service, err error) {
 	// At the last moment before inserting the service, prime status history.
 	probablyUpdateStatusHistory(st, svc.globalKey(), statusDoc)
 
-	if err := st.runTransaction(ops); err == txn.ErrAborted {
-		if err := checkModeLife(st); err != nil {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := checkModeLife(st); err != nil {
+				return nil, errors.Trace(err)
+			}
+			// The transaction may have been aborted because
+			// storage was created concurrently in a pool with
+			// capacity limits; if not, the service exists.
+			if _, err := st.Service(args.Name); err == nil {
+				return nil, errors.Errorf("service already exists")
+			} else if !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+		}
+		limitOps, err := storagePoolLimitsOps(st, args.Storage, args.NumUnits)
+		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		return nil, errors.Errorf("service already exists")
-	} else if err != nil {
+		return append(ops[:len(ops):len(ops)], limitOps...), nil
+	}
+	if err := st.run(buildTxn); err != nil {
 		return nil, errors.Trace(err)
 	}
 	// Refresh to pick the txn-revno.
@@ -1953,7 +2001,19 @@ func (st *State) endpoints(name string, filter func(ep Endpoint) bool) ([]Endpoi
 	} else {
 		return nil, errors.Errorf("invalid endpoint %q", name)
 	}
+	// Remote services in the model share the service namespace, so
+	// if there is no such service, look for a remote service instead.
+	var svc serviceEndpoints
 	svc, err := st.Service(svcName)
+	if errors.IsNotFound(err) {
+		var remoteErr error
+		svc, remoteErr = st.RemoteService(svcName)
+		if remoteErr == nil {
+			err = nil
+		} else if !errors.IsNotFound(remoteErr) {
+			err = remoteErr
+		}
+	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -1979,6 +2039,12 @@ func (st *State) endpoints(name string, filter func(ep Endpoint) bool) ([]Endpoi
 	return final, nil
 }
 
+// serviceEndpoints is implemented by both Service and RemoteService.
+type serviceEndpoints interface {
+	Endpoint(relationName string) (Endpoint, error)
+	Endpoints() ([]Endpoint, error)
+}
+
 // AddRelation creates a new relation with the given endpoints.
 func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 	key := relationKey(eps)
@@ -2018,12 +2084,20 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 		}
 		// Collect per-service operations, checking sanity as we go.
 		var ops []txn.Op
-		var subordinateCount int
+		var subordinateCount, remoteCount int
 		series := map[string]bool{}
 		for _, ep := range eps {
 			svc, err := st.Service(ep.ServiceName)
 			if errors.IsNotFound(err) {
-				return nil, errors.Errorf("service %q does not exist", ep.ServiceName)
+				remoteOps, remoteErr := st.addRemoteRelationOps(ep)
+				if errors.IsNotFound(remoteErr) {
+					return nil, errors.Errorf("service %q does not exist", ep.ServiceName)
+				} else if remoteErr != nil {
+					return nil, errors.Trace(remoteErr)
+				}
+				remoteCount++
+				ops = append(ops, remoteOps...)
+				continue
 			} else if err != nil {
 				return nil, errors.Trace(err)
 			} else if svc.doc.Life != Alive {
@@ -2047,6 +2121,9 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 				Update: bson.D{{"$inc", bson.D{{"relationcount", 1}}}},
 			})
 		}
+		if remoteCount > 1 {
+			return nil, errors.Errorf("cannot relate two remote services")
+		}
 		if matchSeries && len(series) != 1 {
 			return nil, errors.Errorf("principal and subordinate services' series must match")
 		}
@@ -2085,6 +2162,31 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 	return nil, errors.Trace(err)
 }
 
+// addRemoteRelationOps returns the operations necessary to add a
+// relation to the supplied endpoint of a remote service. If the remote
+// service does not exist, an error satisfying errors.IsNotFound is
+// returned.
+func (st *State) addRemoteRelationOps(ep Endpoint) ([]txn.Op, error) {
+	svc, err := st.RemoteService(ep.ServiceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	} else if svc.doc.Life != Alive {
+		return nil, errors.Errorf("remote service %q is not alive", ep.ServiceName)
+	}
+	if _, err := svc.Endpoint(ep.Name); err != nil {
+		return nil, errors.Errorf("%q does not implement %q", ep.ServiceName, ep)
+	}
+	if ep.Scope != charm.ScopeGlobal {
+		return nil, errors.Errorf("remote service %q cannot join container scoped relations", ep.ServiceName)
+	}
+	return []txn.Op{{
+		C:      remoteServicesC,
+		Id:     st.docID(ep.ServiceName),
+		Assert: isAliveDoc,
+		Update: bson.D{{"$inc", bson.D{{"relationcount", 1}}}},
+	}}, nil
+}
+
 // EndpointsRelation returns the existing relation with the given endpoints.
 func (st *State) EndpointsRelation(endpoints ...Endpoint) (*Relation, error) {
 	return st.KeyRelation(relationKey(endpoints))
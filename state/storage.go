@@ -128,6 +128,12 @@ type storageInstanceDoc struct {
 	StorageName     string      `bson:"storagename"`
 	AttachmentCount int         `bson:"attachmentcount"`
 	CharmURL        *charm.URL  `bson:"charmurl"`
+
+	// Pool and Size record the pool and size (in MiB) that the
+	// storage instance was created with, so that it is counted
+	// against the pool's limits before it has been provisioned.
+	Pool string `bson:"pool,omitempty"`
+	Size uint64 `bson:"size,omitempty"`
 }
 
 type storageAttachment struct {
@@ -316,15 +322,16 @@ func removeStorageInstanceOps(
 }
 
 // createStorageOps returns txn.Ops for creating storage instances
-// and attachments for the newly created unit or service.
+// and attachments for the newly created unit.
 //
-// The entity tag identifies the entity that owns the storage instance
-// either a unit or a service. Shared storage instances are owned by a
-// service, and non-shared storage instances are owned by a unit.
+// The entity tag identifies the unit that owns the storage instances.
+// Shared storage instances are owned by a service, and created along
+// with it by createSharedStorageOps; the unit is attached to each of
+// the specified shared storage instances instead.
 //
-// The charm metadata corresponds to the charm that the owner (service/unit)
-// is or will be running, and is used to extract storage constraints,
-// default values, etc.
+// The charm metadata corresponds to the charm that the unit is or will
+// be running, and is used to extract storage constraints, default
+// values, etc.
 //
 // The supplied storage constraints are constraints for the storage
 // instances to be created, keyed on the storage name. These constraints
@@ -338,6 +345,7 @@ func createStorageOps(
 	cons map[string]StorageConstraints,
 	series string,
 	machineOpsNeeded bool,
+	sharedStorage sharedStorageInstances,
 ) (ops []txn.Op, numStorageAttachments int, err error) {
 
 	type template struct {
@@ -346,13 +354,9 @@ func createStorageOps(
 		cons        StorageConstraints
 	}
 
-	createdShared := false
-	switch entity := entity.(type) {
-	case names.ServiceTag:
-		createdShared = true
-	case names.UnitTag:
-	default:
-		return nil, -1, errors.Errorf("expected service or unit tag, got %T", entity)
+	unit, ok := entity.(names.UnitTag)
+	if !ok {
+		return nil, -1, errors.Errorf("expected unit tag, got %T", entity)
 	}
 
 	// Create storage instances in order of name, to simplify testing.
@@ -368,8 +372,7 @@ func createStorageOps(
 		if !ok {
 			return nil, -1, errors.NotFoundf("charm storage %q", store)
 		}
-		if createdShared != charmStorage.Shared {
-			// services only get shared storage instances,
+		if charmStorage.Shared {
 			// units only get non-shared storage instances.
 			continue
 		}
@@ -399,18 +402,18 @@ func createStorageOps(
 				return nil, -1, errors.Annotate(err, "cannot generate storage instance name")
 			}
 			doc := &storageInstanceDoc{
-				Id:          id,
-				Kind:        kind,
-				Owner:       owner,
-				StorageName: t.storageName,
-				CharmURL:    curl,
-			}
-			if unit, ok := entity.(names.UnitTag); ok {
-				doc.AttachmentCount = 1
-				storage := names.NewStorageTag(id)
-				ops = append(ops, createStorageAttachmentOp(storage, unit))
-				numStorageAttachments++
+				Id:              id,
+				Kind:            kind,
+				Owner:           owner,
+				StorageName:     t.storageName,
+				CharmURL:        curl,
+				Pool:            t.cons.Pool,
+				Size:            t.cons.Size,
+				AttachmentCount: 1,
 			}
+			storage := names.NewStorageTag(id)
+			ops = append(ops, createStorageAttachmentOp(storage, unit))
+			numStorageAttachments++
 			ops = append(ops, txn.Op{
 				C:      storageInstancesC,
 				Id:     id,
@@ -433,16 +436,137 @@ func createStorageOps(
 		}
 	}
 
-	// TODO(axw) create storage attachments for each shared storage
-	// instance owned by the service.
-	//
-	// TODO(axw) prevent creation of shared storage after service
-	// creation, because the only sane time to add storage attachments
-	// is when units are added to said service.
+	// Attach the unit to each shared storage instance owned by
+	// the service. Machine storage for the shared instances is
+	// attached when the unit is assigned to a machine.
+	for _, storage := range sharedStorage.tags {
+		ops = append(ops, createStorageAttachmentOp(storage, unit))
+		numStorageAttachments++
+		if sharedStorage.created {
+			// The storage instances are being created along
+			// with the unit, and their attachment counts
+			// already account for it.
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      storageInstancesC,
+			Id:     storage.Id(),
+			Assert: isAliveDoc,
+			Update: bson.D{{"$inc", bson.D{{"attachmentcount", 1}}}},
+		})
+	}
 
 	return ops, numStorageAttachments, nil
 }
 
+// sharedStorageInstances identifies the shared storage instances
+// that a new unit is to be attached to.
+type sharedStorageInstances struct {
+	// tags holds the tags of the shared storage instances.
+	tags []names.StorageTag
+
+	// created records whether or not the storage instances are
+	// being created in the same transaction as the unit.
+	created bool
+}
+
+// serviceSharedStorage returns the shared storage instances owned by the
+// specified service, for attaching to a unit being added to it.
+func serviceSharedStorage(st *State, service names.ServiceTag) (sharedStorageInstances, error) {
+	coll, closer := st.getCollection(storageInstancesC)
+	defer closer()
+
+	var docs []storageInstanceDoc
+	err := coll.Find(bson.D{{"owner", service.String()}}).Select(bson.D{{"id", true}}).All(&docs)
+	if err != nil {
+		return sharedStorageInstances{}, errors.Annotatef(err, "cannot get storage instances for %s", service)
+	}
+	tags := make([]names.StorageTag, len(docs))
+	for i, doc := range docs {
+		tags[i] = names.NewStorageTag(doc.Id)
+	}
+	return sharedStorageInstances{tags: tags}, nil
+}
+
+// createSharedStorageOps returns txn.Ops for creating the shared storage
+// instances owned by the newly created service, along with a filesystem
+// for each of them. Each of the service's units is attached to the shared
+// storage instances, and each machine the units are assigned to is
+// attached to their filesystems, so all units share the same data.
+//
+// numUnits is the number of units being created along with the service,
+// which will be attached to the shared storage instances in the same
+// transaction.
+func createSharedStorageOps(
+	st *State,
+	service names.ServiceTag,
+	charmMeta *charm.Meta,
+	curl *charm.URL,
+	cons map[string]StorageConstraints,
+	numUnits int,
+) ([]txn.Op, sharedStorageInstances, error) {
+	// Create storage instances in order of name, to simplify testing.
+	storageNames := set.NewStrings()
+	for name := range cons {
+		storageNames.Add(name)
+	}
+
+	var ops []txn.Op
+	shared := sharedStorageInstances{created: true}
+	for _, store := range storageNames.SortedValues() {
+		cons := cons[store]
+		charmStorage, ok := charmMeta.Storage[store]
+		if !ok {
+			return nil, sharedStorageInstances{}, errors.NotFoundf("charm storage %q", store)
+		}
+		if !charmStorage.Shared {
+			// services only get shared storage instances.
+			continue
+		}
+		// Shared storage is validated to be filesystem
+		// storage; see validateSharedStoragePool.
+		for i := uint64(0); i < cons.Count; i++ {
+			id, err := newStorageInstanceId(st, store)
+			if err != nil {
+				return nil, sharedStorageInstances{}, errors.Annotate(err, "cannot generate storage instance name")
+			}
+			storage := names.NewStorageTag(id)
+			ops = append(ops, txn.Op{
+				C:      storageInstancesC,
+				Id:     id,
+				Assert: txn.DocMissing,
+				Insert: &storageInstanceDoc{
+					Id:              id,
+					Kind:            StorageKindFilesystem,
+					Owner:           service.String(),
+					StorageName:     store,
+					CharmURL:        curl,
+					Pool:            cons.Pool,
+					Size:            cons.Size,
+					AttachmentCount: numUnits,
+				},
+			})
+			// The filesystem is not created for any one
+			// machine, and is attached to machines as the
+			// service's units are assigned to them.
+			filesystemOps, _, _, err := st.addFilesystemOps(FilesystemParams{
+				storage: storage,
+				binding: storage,
+				Pool:    cons.Pool,
+				Size:    cons.Size,
+			}, "")
+			if err != nil {
+				return nil, sharedStorageInstances{}, errors.Annotatef(
+					err, "creating filesystem for storage %s", id,
+				)
+			}
+			ops = append(ops, filesystemOps...)
+			shared.tags = append(shared.tags, storage)
+		}
+	}
+	return ops, shared, nil
+}
+
 // unitAssignedMachineStorageOps returns ops for creating volumes, filesystems
 // and their attachments to the machine that the specified unit is assigned to,
 // corresponding to the specified storage instance.
@@ -669,6 +793,19 @@ func removeStorageAttachmentOps(
 		Assert: txn.DocExists,
 		Update: bson.D{{"$inc", bson.D{{"storageattachmentcount", -1}}}},
 	}}
+	if si.doc.Life == Alive && si.doc.Kind == StorageKindFilesystem &&
+		si.doc.Owner != names.NewUnitTag(s.doc.Unit).String() {
+		// The storage instance is shared by the service's units;
+		// detach its filesystem from the unit's machine if no
+		// other unit there is attached to it. If the storage
+		// instance is dying, its filesystem is being destroyed,
+		// and will be detached from all machines.
+		detachOps, err := detachSharedFilesystemOps(st, si, s.doc.Unit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, detachOps...)
+	}
 	if si.doc.AttachmentCount == 1 {
 		var hasLastRef bson.D
 		if si.doc.Life == Dying {
@@ -715,6 +852,68 @@ func removeStorageAttachmentOps(
 	return ops, nil
 }
 
+// detachSharedFilesystemOps returns txn.Ops to detach the filesystem of the
+// specified shared storage instance from the machine that the specified unit
+// is assigned to, if no other unit assigned to the machine is attached to
+// the storage instance.
+func detachSharedFilesystemOps(st *State, si *storageInstance, unitName string) ([]txn.Op, error) {
+	unit, err := st.Unit(unitName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineId, err := unit.AssignedMachineId()
+	if errors.IsNotAssigned(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	coll, closer := st.getCollection(storageAttachmentsC)
+	defer closer()
+	var docs []storageAttachmentDoc
+	if err := coll.Find(bson.D{
+		{"storageid", si.doc.Id},
+		{"unitid", bson.D{{"$ne", unitName}}},
+	}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get storage attachments for %s", si.StorageTag().Id())
+	}
+	for _, doc := range docs {
+		other, err := st.Unit(doc.Unit)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		otherMachineId, err := other.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if otherMachineId == machineId {
+			return nil, nil
+		}
+	}
+
+	filesystem, err := st.storageInstanceFilesystem(si.StorageTag())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine := names.NewMachineTag(machineId)
+	attachment, err := st.FilesystemAttachment(machine, filesystem.FilesystemTag())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if attachment.Life() != Alive {
+		return nil, nil
+	}
+	return detachFilesystemOps(machine, filesystem.FilesystemTag()), nil
+}
+
 // removeStorageInstancesOps returns the transaction operations to remove all
 // storage instances owned by the specified entity.
 func removeStorageInstancesOps(st *State, owner names.Tag) ([]txn.Op, error) {
@@ -829,11 +1028,9 @@ func validateStorageConstraintsAgainstCharm(
 			return errors.Errorf("charm %q has no store called %q", charmMeta.Name, name)
 		}
 		if charmStorage.Shared {
-			// TODO(axw) implement shared storage support.
-			return errors.Errorf(
-				"charm %q store %q: shared storage support not implemented",
-				charmMeta.Name, name,
-			)
+			if err := validateSharedStoragePool(st, cons.Pool, charmStorage); err != nil {
+				return errors.Annotatef(err, "charm %q store %q", charmMeta.Name, name)
+			}
 		}
 		if cons.Count < uint64(charmStorage.CountMin) {
 			return errors.Errorf(
@@ -928,6 +1125,26 @@ func validateStoragePool(
 	return nil
 }
 
+// validateSharedStoragePool validates that the storage pool may be used
+// for shared charm storage. Shared storage is only supported for
+// filesystems, using a pool whose storage provider supports attaching
+// the same filesystem to multiple machines.
+func validateSharedStoragePool(st *State, poolName string, charmStorage charm.Storage) error {
+	if charmStorage.Type != charm.StorageFilesystem {
+		return errors.Errorf("shared storage of type %q not supported", charmStorage.Type)
+	}
+	providerType, provider, err := poolStorageProvider(st, poolName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !storage.SupportsSharedFilesystems(provider) {
+		return errors.Errorf(
+			"%q provider does not support shared filesystems", providerType,
+		)
+	}
+	return nil
+}
+
 func poolStorageProvider(st *State, poolName string) (storage.ProviderType, storage.Provider, error) {
 	poolManager := poolmanager.New(NewStateSettings(st))
 	pool, err := poolManager.Get(poolName)
@@ -941,6 +1158,11 @@ func poolStorageProvider(st *State, poolName string) (storage.ProviderType, stor
 			// so return the original "pool not found" error.
 			return "", nil, errors.Trace(err)
 		}
+		if storage.RequiresConfiguredPool(provider) {
+			return "", nil, errors.Errorf(
+				"storage provider %q requires a configured pool", providerType,
+			)
+		}
 		return providerType, provider, nil
 	} else if err != nil {
 		return "", nil, errors.Trace(err)
@@ -1101,6 +1323,11 @@ func (st *State) addStorageForUnit(
 	if !exists {
 		return errors.NotFoundf("charm storage %q", name)
 	}
+	if ch.Meta().Storage[name].Shared {
+		// Shared storage instances are owned by the service,
+		// and created along with it.
+		return errors.NotSupportedf("adding shared storage %q to a unit", name)
+	}
 
 	// Populate missing configuration parameters with default values.
 	conf, err := st.ModelConfig()
@@ -1136,11 +1363,15 @@ func (st *State) addStorageForUnit(
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		limitOps, err := storagePoolLimitsOps(st, map[string]StorageConstraints{name: completeCons}, 1)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 		ops, err := st.constructAddUnitStorageOps(ch, u, name, completeCons)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		return ops, nil
+		return append(ops, limitOps...), nil
 	}
 	if err := st.run(buildTxn); err != nil {
 		return errors.Annotatef(err, "adding storage to unit %s", u)
@@ -1183,6 +1414,7 @@ func (st *State) constructAddUnitStorageOps(
 		map[string]StorageConstraints{name: cons},
 		u.Series(),
 		true, // create machine storage
+		sharedStorageInstances{},
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -566,6 +566,225 @@ func (s *StorageStateSuite) assertStorageUnitsAdded(c *gc.C) {
 	}
 }
 
+func (s *StorageStateSuite) TestAddUnitSharedStorage(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("nfs-pool", provider.NFSProviderType, map[string]interface{}{
+		"export": "10.0.0.1:/srv/data",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.createStorageCharm(c, "storage-filesystem", charm.Storage{
+		Name:     "data",
+		Type:     charm.StorageFilesystem,
+		Shared:   true,
+		CountMin: 1,
+		CountMax: 1,
+	})
+	service := s.AddTestingServiceWithStorage(c, "storage-filesystem", ch, map[string]state.StorageConstraints{
+		"data": makeStorageCons("nfs-pool", 1024, 1),
+	})
+
+	// The shared storage instance is owned by the service, and has
+	// a single model-scoped filesystem.
+	all, err := s.State.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	storageInstance := all[0]
+	c.Assert(storageInstance.Owner(), gc.Equals, service.Tag())
+	c.Assert(storageInstance.StorageName(), gc.Equals, "data")
+	c.Assert(storageInstance.Kind(), gc.Equals, state.StorageKindFilesystem)
+	filesystem, err := s.State.StorageInstanceFilesystem(storageInstance.StorageTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filesystem.FilesystemTag(), gc.Equals, names.NewFilesystemTag("0"))
+
+	// Each unit is attached to the shared storage instance, and each
+	// machine the units are assigned to is attached to its filesystem.
+	for i := 0; i < 2; i++ {
+		u, err := service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		storageAttachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(storageAttachments, gc.HasLen, 1)
+		c.Assert(storageAttachments[0].StorageInstance(), gc.Equals, storageInstance.StorageTag())
+
+		err = s.State.AssignUnit(u, state.AssignNew)
+		c.Assert(err, jc.ErrorIsNil)
+		machineId, err := u.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = s.State.FilesystemAttachment(names.NewMachineTag(machineId), filesystem.FilesystemTag())
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	all, err = s.State.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	filesystems, err := s.State.AllFilesystems()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filesystems, gc.HasLen, 1)
+}
+
+func (s *StorageStateSuite) TestAddStorageForUnitSharedStorage(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("nfs-pool", provider.NFSProviderType, map[string]interface{}{
+		"export": "10.0.0.1:/srv/data",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.createStorageCharm(c, "storage-filesystem", charm.Storage{
+		Name:     "data",
+		Type:     charm.StorageFilesystem,
+		Shared:   true,
+		CountMin: 1,
+		CountMax: 2,
+	})
+	service := s.AddTestingServiceWithStorage(c, "storage-filesystem", ch, map[string]state.StorageConstraints{
+		"data": makeStorageCons("nfs-pool", 1024, 1),
+	})
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddStorageForUnit(u.UnitTag(), "data", makeStorageCons("nfs-pool", 1024, 1))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *StorageStateSuite) TestAddServiceSharedStorageUnsupportedPool(c *gc.C) {
+	ch := s.createStorageCharm(c, "storage-filesystem", charm.Storage{
+		Name:     "data",
+		Type:     charm.StorageFilesystem,
+		Shared:   true,
+		CountMin: 1,
+		CountMax: 1,
+	})
+	_, err := s.State.AddService(state.AddServiceArgs{
+		Name: "storage-filesystem", Owner: "user-test-admin@local", Charm: ch,
+		Storage: map[string]state.StorageConstraints{
+			"data": makeStorageCons("rootfs", 1024, 1),
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "storage-filesystem": charm "storage-filesystem" store "data": "rootfs" provider does not support shared filesystems`)
+}
+
+func (s *StorageStateSuite) TestAddServiceSharedStorageUnconfiguredPool(c *gc.C) {
+	ch := s.createStorageCharm(c, "storage-filesystem", charm.Storage{
+		Name:     "data",
+		Type:     charm.StorageFilesystem,
+		Shared:   true,
+		CountMin: 1,
+		CountMax: 1,
+	})
+	_, err := s.State.AddService(state.AddServiceArgs{
+		Name: "storage-filesystem", Owner: "user-test-admin@local", Charm: ch,
+		Storage: map[string]state.StorageConstraints{
+			"data": makeStorageCons("nfs", 1024, 1),
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "storage-filesystem": .*storage provider "nfs" requires a configured pool`)
+}
+
+func (s *StorageStateSuite) TestAddServiceSharedStorageBlock(c *gc.C) {
+	ch := s.createStorageCharm(c, "storage-block", charm.Storage{
+		Name:     "data",
+		Type:     charm.StorageBlock,
+		Shared:   true,
+		CountMin: 1,
+		CountMax: 1,
+	})
+	_, err := s.State.AddService(state.AddServiceArgs{
+		Name: "storage-block", Owner: "user-test-admin@local", Charm: ch,
+		Storage: map[string]state.StorageConstraints{
+			"data": makeStorageCons("loop-pool", 1024, 1),
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "storage-block": charm "storage-block" store "data": shared storage of type "block" not supported`)
+}
+
+func (s *StorageStateSuite) TestAddUnitStoragePoolCountLimit(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("limited", provider.LoopProviderType, map[string]interface{}{
+		"max-count": 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "storage-block")
+	service := s.AddTestingServiceWithStorage(c, "storage-block", ch, map[string]state.StorageConstraints{
+		"data": makeStorageCons("limited", 1024, 1),
+	})
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := s.State.StoragePoolUsage("limited")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, gc.Equals, state.StoragePoolUsage{Count: 1, Size: 1024})
+
+	_, err = service.AddUnit()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "storage-block": pool "limited" limited to 1 volumes or filesystems, 1 in use, 1 requested`)
+}
+
+func (s *StorageStateSuite) TestAddServiceStoragePoolSizeLimit(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("limited", provider.LoopProviderType, map[string]interface{}{
+		"max-size": "1G",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "storage-block")
+	_, err = s.State.AddService(state.AddServiceArgs{
+		Name: "storage-block", Owner: "user-test-admin@local", Charm: ch,
+		Storage: map[string]state.StorageConstraints{
+			"data": makeStorageCons("limited", 2048, 1),
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "storage-block": pool "limited" limited to 1.0 GiB, 0 B in use, 2.0 GiB requested`)
+}
+
+func (s *StorageStateSuite) TestAddServiceStoragePoolCountLimitNumUnits(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("limited", provider.LoopProviderType, map[string]interface{}{
+		"max-count": 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "storage-block")
+	_, err = s.State.AddService(state.AddServiceArgs{
+		Name: "storage-block", Owner: "user-test-admin@local", Charm: ch,
+		NumUnits: 3,
+		Storage: map[string]state.StorageConstraints{
+			"data": makeStorageCons("limited", 1024, 1),
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "storage-block": pool "limited" limited to 2 volumes or filesystems, 0 in use, 3 requested`)
+}
+
+func (s *StorageStateSuite) TestAddUnitStoragePoolCountLimitPending(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("limited", provider.LoopProviderType, map[string]interface{}{
+		"max-count": 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "storage-block")
+	service := s.AddTestingServiceWithStorage(c, "storage-block", ch, map[string]state.StorageConstraints{
+		"data": makeStorageCons("limited", 1024, 1),
+	})
+	// The unit is not assigned to a machine, so its storage
+	// has no volume yet; it must still count against the pool.
+	_, err = service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := s.State.StoragePoolUsage("limited")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, gc.Equals, state.StoragePoolUsage{Count: 1, Size: 1024})
+
+	_, err = service.AddUnit()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "storage-block": pool "limited" limited to 1 volumes or filesystems, 1 in use, 1 requested`)
+}
+
+func (s *StorageStateSuite) TestStoragePoolUsageUnused(c *gc.C) {
+	usage, err := s.State.StoragePoolUsage("loop-pool")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, gc.Equals, state.StoragePoolUsage{})
+}
+
 func (s *StorageStateSuite) TestAllStorageInstances(c *gc.C) {
 	s.assertStorageUnitsAdded(c)
 
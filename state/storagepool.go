@@ -0,0 +1,218 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/dustin/go-humanize"
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/storage/poolmanager"
+)
+
+// StoragePoolUsage describes the storage in the model that has been
+// created from a storage pool.
+type StoragePoolUsage struct {
+	// Count is the number of volumes and filesystems created
+	// from the pool, including those of storage instances that
+	// have not yet been provisioned. Filesystems backed by
+	// volumes are counted once, by their volume.
+	Count uint64
+
+	// Size is the total size of the volumes and filesystems
+	// created from the pool, in MiB. The requested size is used
+	// for storage that has not yet been provisioned.
+	Size uint64
+}
+
+// StoragePoolUsage returns the usage of the named storage pool by the
+// storage in the model that is not Dead. Storage instances that have
+// not yet been provisioned, such as those of units that have not been
+// assigned to machines, are counted using their requested size.
+func (st *State) StoragePoolUsage(poolName string) (StoragePoolUsage, error) {
+	var usage StoragePoolUsage
+	notDead := bson.DocElem{"life", bson.D{{"$ne", Dead}}}
+	poolQuery := func(extra ...bson.DocElem) bson.D {
+		return append(bson.D{
+			notDead,
+			{"$or", []bson.D{
+				{{"info.pool", poolName}},
+				{{"params.pool", poolName}},
+			}},
+		}, extra...)
+	}
+
+	// Storage instances with a volume or filesystem
+	// are counted by their volume or filesystem.
+	provisioned := set.NewStrings()
+
+	volumes, err := st.volumes(poolQuery())
+	if err != nil {
+		return StoragePoolUsage{}, errors.Annotatef(err, "getting volumes for pool %q", poolName)
+	}
+	for _, v := range volumes {
+		usage.Count++
+		if v.doc.Info != nil {
+			usage.Size += v.doc.Info.Size
+		} else if v.doc.Params != nil {
+			usage.Size += v.doc.Params.Size
+		}
+		if v.doc.StorageId != "" {
+			provisioned.Add(v.doc.StorageId)
+		}
+	}
+
+	// Only count filesystems that are not backed by volumes,
+	// as the volumes have been counted above.
+	filesystems, err := st.filesystems(poolQuery(
+		bson.DocElem{"volumeid", bson.D{{"$exists", false}}},
+	))
+	if err != nil {
+		return StoragePoolUsage{}, errors.Annotatef(err, "getting filesystems for pool %q", poolName)
+	}
+	for _, f := range filesystems {
+		usage.Count++
+		if f.doc.Info != nil {
+			usage.Size += f.doc.Info.Size
+		} else if f.doc.Params != nil {
+			usage.Size += f.doc.Params.Size
+		}
+		if f.doc.StorageId != "" {
+			provisioned.Add(f.doc.StorageId)
+		}
+	}
+
+	storageInstances, closer := st.getCollection(storageInstancesC)
+	defer closer()
+	var docs []storageInstanceDoc
+	err = storageInstances.Find(bson.D{{"pool", poolName}, notDead}).All(&docs)
+	if err != nil {
+		return StoragePoolUsage{}, errors.Annotatef(err, "getting storage instances for pool %q", poolName)
+	}
+	for _, doc := range docs {
+		if provisioned.Contains(doc.Id) {
+			continue
+		}
+		usage.Count++
+		usage.Size += doc.Size
+	}
+	return usage, nil
+}
+
+// storagePoolUsageDoc is used to serialise the creation of storage from
+// a storage pool with capacity limits. Each transaction that creates
+// storage in such a pool asserts and increments the pool's revision, so
+// that concurrent transactions are forced to recheck the pool's usage.
+type storagePoolUsageDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	Pool      string `bson:"pool"`
+	Revision  int64  `bson:"revision"`
+}
+
+// storagePoolLimitsOps checks that creating storage instances with the
+// specified constraints, for each of numUnits units, would not exceed
+// the capacity limits of the storage pools they use. It returns txn.Ops
+// that abort the transaction if any other storage is created in those
+// pools concurrently.
+func storagePoolLimitsOps(st *State, allCons map[string]StorageConstraints, numUnits int) ([]txn.Op, error) {
+	type demand struct {
+		count uint64
+		size  uint64
+	}
+	demands := make(map[string]demand)
+	poolNames := set.NewStrings()
+	for _, cons := range allCons {
+		if cons.Count == 0 || numUnits <= 0 {
+			continue
+		}
+		count := cons.Count * uint64(numUnits)
+		d := demands[cons.Pool]
+		d.count += count
+		d.size += count * cons.Size
+		demands[cons.Pool] = d
+		poolNames.Add(cons.Pool)
+	}
+
+	poolManager := poolmanager.New(NewStateSettings(st))
+	var ops []txn.Op
+	for _, poolName := range poolNames.SortedValues() {
+		pool, err := poolManager.Get(poolName)
+		if errors.IsNotFound(err) {
+			// The pool name refers to a storage provider
+			// type, which has no limits.
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		maxCount, countLimited := pool.MaxCount()
+		maxSize, sizeLimited := pool.MaxSize()
+		if !countLimited && !sizeLimited {
+			continue
+		}
+		op, err := storagePoolUsageOp(st, poolName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		usage, err := st.StoragePoolUsage(poolName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		d := demands[poolName]
+		if countLimited && usage.Count+d.count > maxCount {
+			return nil, errors.Errorf(
+				"pool %q limited to %d volumes or filesystems, %d in use, %d requested",
+				poolName, maxCount, usage.Count, d.count,
+			)
+		}
+		if sizeLimited && usage.Size+d.size > maxSize {
+			return nil, errors.Errorf(
+				"pool %q limited to %s, %s in use, %s requested",
+				poolName,
+				humanize.IBytes(maxSize*humanize.MiByte),
+				humanize.IBytes(usage.Size*humanize.MiByte),
+				humanize.IBytes(d.size*humanize.MiByte),
+			)
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// storagePoolUsageOp returns a txn.Op that asserts that the revision of
+// the pool's usage document is unchanged since it was read, and then
+// increments it. The document is read before the pool's usage is
+// computed, so that any storage created in the meantime causes the
+// transaction to be aborted.
+func storagePoolUsageOp(st *State, poolName string) (txn.Op, error) {
+	usageDocs, closer := st.getCollection(storagePoolUsageC)
+	defer closer()
+
+	var doc storagePoolUsageDoc
+	err := usageDocs.FindId(poolName).One(&doc)
+	if err == mgo.ErrNotFound {
+		return txn.Op{
+			C:      storagePoolUsageC,
+			Id:     st.docID(poolName),
+			Assert: txn.DocMissing,
+			Insert: &storagePoolUsageDoc{
+				DocID:     st.docID(poolName),
+				ModelUUID: st.ModelUUID(),
+				Pool:      poolName,
+				Revision:  1,
+			},
+		}, nil
+	} else if err != nil {
+		return txn.Op{}, errors.Annotatef(err, "getting usage revision of pool %q", poolName)
+	}
+	return txn.Op{
+		C:      storagePoolUsageC,
+		Id:     st.docID(poolName),
+		Assert: bson.D{{"revision", doc.Revision}},
+		Update: bson.D{{"$inc", bson.D{{"revision", 1}}}},
+	}, nil
+}
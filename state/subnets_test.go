@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 )
 
 type SubnetSuite struct {
@@ -458,3 +459,27 @@ func (s *SubnetSuite) TestPickNewAddressRace(c *gc.C) {
 	expected := []string{"192.168.1.0", "192.168.1.1"}
 	c.Assert(ipAddresses, jc.DeepEquals, expected)
 }
+
+func (s *SubnetSuite) TestWatchSubnets(c *gc.C) {
+	w := s.State.WatchSubnets()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	subnet, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "192.168.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	_, err = s.State.AddSpace("foo", "", []string{"192.168.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = subnet.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = subnet.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
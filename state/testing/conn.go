@@ -27,6 +27,7 @@ func Initialize(c *gc.C, owner names.UserTag, cfg *config.Config, policy state.P
 
 	st, err := state.Initialize(owner, mgoInfo, cfg, dialOpts, policy)
 	c.Assert(err, jc.ErrorIsNil)
+	st.SetSecretsKey(testing.SecretsKey)
 	return st
 }
 
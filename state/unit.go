@@ -95,7 +95,9 @@ type unitDoc struct {
 	StorageAttachmentCount int `bson:"storageattachmentcount"`
 	MachineId              string
 	Resolved               ResolvedMode
-	Tools                  *tools.Tools `bson:",omitempty"`
+	TriggeredHook          *TriggeredHook `bson:"triggeredhook,omitempty"`
+	Tools                  *tools.Tools   `bson:",omitempty"`
+	WorkloadVersion        string         `bson:",omitempty"`
 	Life                   Life
 	TxnRevno               int64 `bson:"txn-revno"`
 	PasswordHash           string
@@ -228,6 +230,29 @@ func (u *Unit) SetAgentVersion(v version.Binary) (err error) {
 	return nil
 }
 
+// WorkloadVersion returns the version of the workload software, as
+// reported by the unit's charm. It is empty if no version has been set.
+func (u *Unit) WorkloadVersion() string {
+	return u.doc.WorkloadVersion
+}
+
+// SetWorkloadVersion records the version of the workload software
+// running on the unit.
+func (u *Unit) SetWorkloadVersion(version string) error {
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"workloadversion", version}}}},
+	}}
+	if err := u.st.runTransaction(ops); err != nil {
+		err = onAbort(err, ErrDead)
+		return errors.Annotatef(err, "cannot set workload version for unit %q", u)
+	}
+	u.doc.WorkloadVersion = version
+	return nil
+}
+
 // SetPassword sets the password for the machine's agent.
 func (u *Unit) SetPassword(password string) error {
 	if len(password) < utils.MinAgentPasswordLength {
@@ -643,7 +668,13 @@ func (u *Unit) Remove() (err error) {
 		}
 		return nil, jujutxn.ErrNoOperations
 	}
-	return unit.st.run(buildTxn)
+	if err := unit.st.run(buildTxn); err != nil {
+		return err
+	}
+	if err := unit.eraseHookHistory(); err != nil {
+		logger.Errorf("cannot delete hook history for unit %q: %v", unit, err)
+	}
+	return nil
 }
 
 // Resolved returns the resolved mode for the unit.
@@ -1387,6 +1418,18 @@ func (u *Unit) AssignToMachine(m *Machine) (err error) {
 func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, containerType instance.ContainerType) error {
 	template.principals = []string{u.doc.Name}
 	template.Dirty = true
+	if template.CloudInitUserData == "" || template.RootDiskSource == "" {
+		svc, err := u.Service()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if template.CloudInitUserData == "" {
+			template.CloudInitUserData = svc.CloudInitUserData()
+		}
+		if template.RootDiskSource == "" {
+			template.RootDiskSource = svc.RootDiskSource()
+		}
+	}
 
 	var (
 		mdoc *machineDoc
@@ -2194,6 +2237,90 @@ func (u *Unit) ClearResolved() error {
 	return nil
 }
 
+// TriggeredHook describes a hook that a unit is asked to run once,
+// outside its normal workflow, so that it can be debugged.
+type TriggeredHook struct {
+	// Name is the name of the hook, as it is named in the charm.
+	Name string `bson:"name"`
+
+	// RelationId is the id of the relation the hook runs in the
+	// context of, or -1 if it is not a relation hook.
+	RelationId int `bson:"relationid"`
+
+	// RemoteUnit is the name of the remote unit the hook runs in
+	// the context of, if any.
+	RemoteUnit string `bson:"remoteunit,omitempty"`
+}
+
+// Validate returns an error if the triggered hook is not valid.
+func (h TriggeredHook) Validate() error {
+	if h.Name == "" {
+		return errors.NotValidf("missing hook name")
+	}
+	if h.RelationId < -1 {
+		return errors.NotValidf("relation id %d", h.RelationId)
+	}
+	if h.RemoteUnit != "" {
+		if h.RelationId == -1 {
+			return errors.NotValidf("remote unit without relation")
+		}
+		if !names.IsValidUnit(h.RemoteUnit) {
+			return errors.NotValidf("remote unit name %q", h.RemoteUnit)
+		}
+	}
+	return nil
+}
+
+// TriggeredHook returns the hook the unit has been asked to run by
+// TriggerHook, if any.
+func (u *Unit) TriggeredHook() (TriggeredHook, bool) {
+	if u.doc.TriggeredHook == nil {
+		return TriggeredHook{}, false
+	}
+	return *u.doc.TriggeredHook, true
+}
+
+// TriggerHook asks the unit to run the given hook once, as soon as it
+// can, outside its normal workflow; the hook's completion does not
+// change the unit's state. Any hook previously triggered that the unit
+// has not yet run is replaced.
+func (u *Unit) TriggerHook(hook TriggeredHook) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot trigger hook for unit %q", u)
+	if err := hook.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"triggeredhook", hook}}}},
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return ErrDead
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	u.doc.TriggeredHook = &hook
+	return nil
+}
+
+// ClearTriggeredHook removes any hook triggered by TriggerHook.
+func (u *Unit) ClearTriggeredHook() error {
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{{"triggeredhook", nil}}}},
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("unit %q", u)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot clear triggered hook for unit %q", u)
+	}
+	u.doc.TriggeredHook = nil
+	return nil
+}
+
 // StorageConstraints returns the unit's storage constraints.
 func (u *Unit) StorageConstraints() (map[string]StorageConstraints, error) {
 	// TODO(axw) eventually we should be able to override service
@@ -43,6 +43,20 @@ func (s *UnitSuite) SetUpTest(c *gc.C) {
 	c.Assert(s.unit.Series(), gc.Equals, "quantal")
 }
 
+func (s *UnitSuite) TestUnitAgentStatuses(c *gc.C) {
+	unit1, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit1.SetAgentStatus(state.StatusError, "boom", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	statuses, err := s.State.UnitAgentStatuses([]string{s.unit.Name(), unit1.Name(), "wordpress/42"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, jc.DeepEquals, map[string]state.Status{
+		s.unit.Name(): state.StatusAllocating,
+		unit1.Name():  state.StatusIdle,
+	})
+}
+
 func (s *UnitSuite) TestUnitNotFound(c *gc.C) {
 	_, err := s.State.Unit("subway/0")
 	c.Assert(err, gc.ErrorMatches, `unit "subway/0" not found`)
@@ -955,6 +969,77 @@ func (s *UnitSuite) TestGetSetClearResolved(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot set resolved mode for unit "wordpress/0": invalid error resolution mode: "foo"`)
 }
 
+func (s *UnitSuite) TestTriggerHook(c *gc.C) {
+	_, ok := s.unit.TriggeredHook()
+	c.Assert(ok, jc.IsFalse)
+
+	hook := state.TriggeredHook{Name: "db-relation-changed", RelationId: 0, RemoteUnit: "mysql/0"}
+	err := s.unit.TriggerHook(hook)
+	c.Assert(err, jc.ErrorIsNil)
+	triggered, ok := s.unit.TriggeredHook()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(triggered, jc.DeepEquals, hook)
+
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	triggered, ok = s.unit.TriggeredHook()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(triggered, jc.DeepEquals, hook)
+
+	hook = state.TriggeredHook{Name: "config-changed", RelationId: -1}
+	err = s.unit.TriggerHook(hook)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	triggered, ok = s.unit.TriggeredHook()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(triggered, jc.DeepEquals, hook)
+
+	err = s.unit.ClearTriggeredHook()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.unit.TriggeredHook()
+	c.Assert(ok, jc.IsFalse)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.unit.TriggeredHook()
+	c.Assert(ok, jc.IsFalse)
+	err = s.unit.ClearTriggeredHook()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UnitSuite) TestTriggerHookInvalid(c *gc.C) {
+	for i, test := range []struct {
+		hook state.TriggeredHook
+		err  string
+	}{{
+		hook: state.TriggeredHook{RelationId: -1},
+		err:  "missing hook name not valid",
+	}, {
+		hook: state.TriggeredHook{Name: "db-relation-changed", RelationId: -2},
+		err:  "relation id -2 not valid",
+	}, {
+		hook: state.TriggeredHook{Name: "install", RelationId: -1, RemoteUnit: "mysql/0"},
+		err:  "remote unit without relation not valid",
+	}, {
+		hook: state.TriggeredHook{Name: "db-relation-changed", RelationId: 0, RemoteUnit: "mysql"},
+		err:  `remote unit name "mysql" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := s.unit.TriggerHook(test.hook)
+		c.Check(err, gc.ErrorMatches, `cannot trigger hook for unit "wordpress/0": `+test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+	_, ok := s.unit.TriggeredHook()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *UnitSuite) TestTriggerHookDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.TriggerHook(state.TriggeredHook{Name: "install", RelationId: -1})
+	c.Assert(err, gc.ErrorMatches, `cannot trigger hook for unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestOpenedPorts(c *gc.C) {
 	// Verify ports can be opened and closed only when the unit has
 	// assigned machine.
@@ -1564,6 +1649,25 @@ func (s *UnitSuite) TestUnitAgentTools(c *gc.C) {
 	testAgentTools(c, s.unit, `unit "wordpress/0"`)
 }
 
+func (s *UnitSuite) TestWorkloadVersion(c *gc.C) {
+	c.Assert(s.unit.WorkloadVersion(), gc.Equals, "")
+
+	err := s.unit.SetWorkloadVersion("3.14")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.WorkloadVersion(), gc.Equals, "3.14")
+
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.WorkloadVersion(), gc.Equals, "3.14")
+}
+
+func (s *UnitSuite) TestSetWorkloadVersionDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetWorkloadVersion("3.14")
+	c.Assert(err, gc.ErrorMatches, `cannot set workload version for unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestActionSpecs(c *gc.C) {
 	basicActions := `
 snapshot:
@@ -4,8 +4,11 @@
 package state
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // UnitAgent represents the state of a service's unit agent.
@@ -95,3 +98,35 @@ func (u *UnitAgent) globalKey() string {
 func (u *UnitAgent) Tag() names.Tag {
 	return u.tag
 }
+
+// UnitAgentStatuses returns the statuses of the agents of the named
+// units, as reported by UnitAgent.Status, reading them all at once.
+// Units whose agents have no status are omitted.
+func (st *State) UnitAgentStatuses(unitNames []string) (map[string]Status, error) {
+	statuses, closer := st.getCollection(statusesC)
+	defer closer()
+
+	keys := make([]string, len(unitNames))
+	for i, name := range unitNames {
+		keys[i] = unitAgentGlobalKey(name)
+	}
+	var docs []struct {
+		DocID  string `bson:"_id"`
+		Status Status `bson:"status"`
+	}
+	err := statuses.Find(bson.D{{"_id", bson.D{{"$in", keys}}}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get unit agent statuses")
+	}
+	result := make(map[string]Status, len(docs))
+	for _, doc := range docs {
+		status := doc.Status
+		if status == StatusError {
+			// See UnitAgent.Status.
+			status = StatusIdle
+		}
+		name := strings.TrimPrefix(st.localID(doc.DocID), unitAgentGlobalKey(""))
+		result[name] = status
+	}
+	return result, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// unitStateDoc holds the state a unit's agent keeps about the hooks
+// and operations it has run, so that the unit can be recovered on a
+// fresh machine. The state is held as the contents of the agent's
+// local state files, which are stored as a list rather than a map
+// because their paths are not valid field names.
+type unitStateDoc struct {
+	DocID     string             `bson:"_id"`
+	ModelUUID string             `bson:"model-uuid"`
+	Files     []unitStateFileDoc `bson:"files"`
+}
+
+type unitStateFileDoc struct {
+	Path    string `bson:"path"`
+	Content string `bson:"content"`
+}
+
+// AgentState returns the state last saved by the unit's agent, as the
+// contents of its state files keyed by path. If no state has been
+// saved, it returns an empty map.
+func (u *Unit) AgentState() (map[string]string, error) {
+	states, closer := u.st.getCollection(unitStatesC)
+	defer closer()
+
+	var doc unitStateDoc
+	err := states.FindId(u.globalKey()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get agent state for unit %q", u)
+	}
+	files := make(map[string]string, len(doc.Files))
+	for _, file := range doc.Files {
+		files[file.Path] = file.Content
+	}
+	return files, nil
+}
+
+// SetAgentState replaces the state saved by the unit's agent with the
+// given contents of its state files, keyed by path.
+func (u *Unit) SetAgentState(files map[string]string) error {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	fileDocs := make([]unitStateFileDoc, len(paths))
+	for i, path := range paths {
+		fileDocs[i] = unitStateFileDoc{Path: path, Content: files[path]}
+	}
+
+	states, closer := u.st.getCollection(unitStatesC)
+	defer closer()
+	unit := &Unit{st: u.st, doc: u.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := unit.Refresh(); errors.IsNotFound(err) {
+				return nil, ErrDead
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if unit.Life() == Dead {
+			return nil, ErrDead
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+		count, err := states.FindId(unit.globalKey()).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count == 0 {
+			ops = append(ops, txn.Op{
+				C:      unitStatesC,
+				Id:     unit.st.docID(unit.globalKey()),
+				Assert: txn.DocMissing,
+				Insert: &unitStateDoc{
+					DocID:     unit.st.docID(unit.globalKey()),
+					ModelUUID: unit.st.ModelUUID(),
+					Files:     fileDocs,
+				},
+			})
+		} else {
+			ops = append(ops, txn.Op{
+				C:      unitStatesC,
+				Id:     unit.st.docID(unit.globalKey()),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"files", fileDocs}}}},
+			})
+		}
+		return ops, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set agent state for unit %q", u)
+	}
+	return nil
+}
+
+// removeUnitStateOp returns the operation needed to remove the agent
+// state saved for the unit with the given global key, if any.
+func removeUnitStateOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      unitStatesC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
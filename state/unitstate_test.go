@@ -0,0 +1,83 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type UnitStateSuite struct {
+	statetesting.StateSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&UnitStateSuite{})
+
+func (s *UnitStateSuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, nil)
+}
+
+func (s *UnitStateSuite) TestAgentStateEmpty(c *gc.C) {
+	files, err := s.unit.AgentState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
+
+func (s *UnitStateSuite) TestSetAgentState(c *gc.C) {
+	err := s.unit.SetAgentState(map[string]string{
+		"uniter":              "op: continue\n",
+		"relations/0/mysql-0": "change-version: 1\n",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	files, err := s.unit.AgentState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, jc.DeepEquals, map[string]string{
+		"uniter":              "op: continue\n",
+		"relations/0/mysql-0": "change-version: 1\n",
+	})
+
+	// Saved state is replaced wholesale.
+	err = s.unit.SetAgentState(map[string]string{
+		"uniter": "op: run-hook\n",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	files, err = s.unit.AgentState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, jc.DeepEquals, map[string]string{
+		"uniter": "op: run-hook\n",
+	})
+}
+
+func (s *UnitStateSuite) TestSetAgentStateIsPerUnit(c *gc.C) {
+	other := s.Factory.MakeUnit(c, nil)
+	err := s.unit.SetAgentState(map[string]string{"uniter": "op: continue\n"})
+	c.Assert(err, jc.ErrorIsNil)
+	files, err := other.AgentState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
+
+func (s *UnitStateSuite) TestSetAgentStateDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetAgentState(map[string]string{"uniter": "op: continue\n"})
+	c.Assert(err, gc.ErrorMatches, `cannot set agent state for unit ".*": not found or dead`)
+}
+
+func (s *UnitStateSuite) TestRemoveUnitRemovesAgentState(c *gc.C) {
+	err := s.unit.SetAgentState(map[string]string{"uniter": "op: continue\n"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	files, err := s.unit.AgentState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
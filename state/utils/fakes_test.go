@@ -8,14 +8,13 @@ import (
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
-	"github.com/juju/juju/provider/common"
 )
 
 // fakeZonedEnv wraps an Environ (e.g. dummy) and implements ZonedEnviron.
 type fakeZonedEnv struct {
 	environs.Environ
 
-	zones     []common.AvailabilityZone
+	zones     []instance.AvailabilityZone
 	instZones []string
 	err       error
 
@@ -24,7 +23,7 @@ type fakeZonedEnv struct {
 }
 
 // AvailabilityZones implements ZonedEnviron.
-func (e *fakeZonedEnv) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *fakeZonedEnv) AvailabilityZones() ([]instance.AvailabilityZone, error) {
 	e.calls = append(e.calls, "AvailabilityZones")
 	return e.zones, errors.Trace(e.err)
 }
@@ -285,9 +285,25 @@ func (st *State) WatchMachineVolumeAttachments(m names.MachineTag) StringsWatche
 
 // WatchMachineFilesystemAttachments returns a StringsWatcher that notifies of
 // changes to the lifecycles of all filesystem attachments related to the specified
-// machine, for filesystems scoped to the machine.
+// machine, for filesystems scoped to the machine and for model-scoped filesystems.
+// Model-scoped filesystems that may be shared between machines are attached by
+// the machine's storage provisioner.
 func (st *State) WatchMachineFilesystemAttachments(m names.MachineTag) StringsWatcher {
-	return st.watchMachineStorageAttachments(m, filesystemAttachmentsC)
+	pattern := fmt.Sprintf("^%s:", st.docID(m.Id()))
+	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
+	prefix := m.Id() + ":"
+	machineScoped := prefix + m.Id() + "/"
+	filter := func(id interface{}) bool {
+		k, err := st.strictLocalID(id.(string))
+		if err != nil {
+			return false
+		}
+		if !strings.HasPrefix(k, prefix) {
+			return false
+		}
+		return strings.HasPrefix(k, machineScoped) || !strings.Contains(k[len(prefix):], "/")
+	}
+	return newLifecycleWatcher(st, filesystemAttachmentsC, members, filter, nil)
 }
 
 func (st *State) watchMachineStorageAttachments(m names.MachineTag, collection string) StringsWatcher {
@@ -1878,6 +1894,232 @@ func (w *cleanupWatcher) loop() (err error) {
 	}
 }
 
+// subnetsWatcher notifies of changes in the subnets and spaces
+// collections.
+type subnetsWatcher struct {
+	commonWatcher
+	out chan struct{}
+}
+
+var _ Watcher = (*subnetsWatcher)(nil)
+
+// WatchSubnets returns a NotifyWatcher that notifies of changes to the
+// subnets and spaces of the model.
+func (st *State) WatchSubnets() NotifyWatcher {
+	return newSubnetsWatcher(st)
+}
+
+func newSubnetsWatcher(st *State) NotifyWatcher {
+	w := &subnetsWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *subnetsWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *subnetsWatcher) loop() error {
+	in := make(chan watcher.Change)
+	for _, coll := range []string{subnetsC, spacesC} {
+		w.st.watcher.WatchCollectionWithFilter(coll, in, w.st.isForStateEnv)
+		defer w.st.watcher.UnwatchCollection(coll, in)
+	}
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// dnsRecordsWatcher notifies of changes in the collections that the
+// internal DNS records of a model are derived from.
+type dnsRecordsWatcher struct {
+	commonWatcher
+	out chan struct{}
+}
+
+var _ Watcher = (*dnsRecordsWatcher)(nil)
+
+// WatchDNSRecords returns a NotifyWatcher that notifies of changes to
+// the machines, units and services of the model, any of which may
+// change the records returned by DNSRecords.
+func (st *State) WatchDNSRecords() NotifyWatcher {
+	return newDNSRecordsWatcher(st)
+}
+
+func newDNSRecordsWatcher(st *State) NotifyWatcher {
+	w := &dnsRecordsWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *dnsRecordsWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *dnsRecordsWatcher) loop() error {
+	in := make(chan watcher.Change)
+	for _, coll := range []string{machinesC, unitsC, servicesC} {
+		w.st.watcher.WatchCollectionWithFilter(coll, in, w.st.isForStateEnv)
+		defer w.st.watcher.UnwatchCollection(coll, in)
+	}
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// remoteRelationsWatcher notifies of changes that may require the
+// cross-model relations of a model to be synchronised.
+type remoteRelationsWatcher struct {
+	commonWatcher
+	out chan struct{}
+}
+
+var _ Watcher = (*remoteRelationsWatcher)(nil)
+
+// remoteRelationsCollections holds the collections whose documents
+// determine the state of cross-model relations. Relation settings are
+// held in settingsC, which is handled separately.
+var remoteRelationsCollections = []string{
+	remoteServicesC,
+	serviceOffersC,
+	relationsC,
+	relationScopesC,
+}
+
+// WatchRemoteRelations returns a NotifyWatcher that notifies of changes
+// to the remote services, service offers, relations, relation scopes and
+// relation settings of the model, and of the models hosting the services
+// represented by its remote services.
+func (st *State) WatchRemoteRelations() NotifyWatcher {
+	return newRemoteRelationsWatcher(st)
+}
+
+func newRemoteRelationsWatcher(st *State) NotifyWatcher {
+	w := &remoteRelationsWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *remoteRelationsWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *remoteRelationsWatcher) loop() error {
+	in := make(chan watcher.Change)
+	for _, coll := range remoteRelationsCollections {
+		w.st.watcher.WatchCollection(coll, in)
+		defer w.st.watcher.UnwatchCollection(coll, in)
+	}
+	w.st.watcher.WatchCollectionWithFilter(settingsC, in, isRelationSettingsKey)
+	defer w.st.watcher.UnwatchCollection(settingsC, in)
+
+	models, err := w.models()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			ids, ok := collect(ch, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			// The set of models depends on the model's remote
+			// services, so it is recomputed on any change.
+			if models, err = w.models(); err != nil {
+				return errors.Trace(err)
+			}
+			for id := range ids {
+				modelUUID, _, ok := splitDocID(id.(string))
+				if ok && models.Contains(modelUUID) {
+					out = w.out
+					break
+				}
+			}
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// models returns the UUIDs of the model and of the models hosting the
+// services represented by its remote services.
+func (w *remoteRelationsWatcher) models() (set.Strings, error) {
+	remoteServices, err := w.st.AllRemoteServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	models := set.NewStrings(w.st.ModelUUID())
+	for _, rs := range remoteServices {
+		models.Add(rs.SourceModel().Id())
+	}
+	return models, nil
+}
+
+// isRelationSettingsKey returns whether the settings document with the
+// supplied id holds the settings of a unit in a relation.
+func isRelationSettingsKey(id interface{}) bool {
+	_, key, ok := splitDocID(id.(string))
+	return ok && strings.HasPrefix(key, "r#")
+}
+
 // actionStatusWatcher is a StringsWatcher that filters notifications
 // to Action Id's that match the ActionReceiver and ActionStatus set
 // provided.
@@ -6,6 +6,7 @@ package storage
 import (
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"github.com/juju/utils"
 )
 
 const (
@@ -19,6 +20,17 @@ const (
 	// should not be relied upon until a storage source is
 	// constructed.
 	ConfigStorageDir = "storage-dir"
+
+	// ConfigMaxSize is the maximum total size of all volumes and
+	// filesystems that may be created from a storage pool in a
+	// model. The value may be specified with a unit suffix, e.g.
+	// "100G"; values without a suffix are in MiB.
+	ConfigMaxSize = "max-size"
+
+	// ConfigMaxCount is the maximum number of volumes and
+	// filesystems that may be created from a storage pool in
+	// a model.
+	ConfigMaxCount = "max-count"
 )
 
 // Config defines the configuration for a storage source.
@@ -26,28 +38,77 @@ type Config struct {
 	name     string
 	provider ProviderType
 	attrs    map[string]interface{}
+
+	// maxSize and maxCount are the pool's capacity limits;
+	// zero means unlimited.
+	maxSize  uint64
+	maxCount uint64
 }
 
-var fields = schema.Fields{}
+var fields = schema.Fields{
+	ConfigMaxSize:  schema.OneOf(schema.ForceInt(), schema.String()),
+	ConfigMaxCount: schema.ForceInt(),
+}
 
 var configChecker = schema.FieldMap(
 	fields,
-	schema.Defaults{},
+	schema.Defaults{
+		ConfigMaxSize:  schema.Omit,
+		ConfigMaxCount: schema.Omit,
+	},
 )
 
 // NewConfig creates a new Config for instantiating a storage source.
 func NewConfig(name string, provider ProviderType, attrs map[string]interface{}) (*Config, error) {
-	_, err := configChecker.Coerce(attrs, nil)
+	out, err := configChecker.Coerce(attrs, nil)
 	if err != nil {
 		return nil, errors.Annotate(err, "validating common storage config")
 	}
+	coerced := out.(map[string]interface{})
+	maxSize, err := parseMaxSize(coerced[ConfigMaxSize])
+	if err != nil {
+		return nil, errors.Annotate(err, "validating common storage config")
+	}
+	var maxCount uint64
+	if v, ok := coerced[ConfigMaxCount].(int); ok {
+		if v < 0 {
+			return nil, errors.Errorf(
+				"validating common storage config: %s must not be negative, got %d",
+				ConfigMaxCount, v,
+			)
+		}
+		maxCount = uint64(v)
+	}
 	return &Config{
 		name:     name,
 		provider: provider,
 		attrs:    attrs,
+		maxSize:  maxSize,
+		maxCount: maxCount,
 	}, nil
 }
 
+// parseMaxSize parses the coerced value of the max-size attribute,
+// returning the size in MiB.
+func parseMaxSize(v interface{}) (uint64, error) {
+	switch v := v.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		if v < 0 {
+			return 0, errors.Errorf("%s must not be negative, got %d", ConfigMaxSize, v)
+		}
+		return uint64(v), nil
+	case string:
+		size, err := utils.ParseSize(v)
+		if err != nil {
+			return 0, errors.Annotatef(err, "parsing %s", ConfigMaxSize)
+		}
+		return size, nil
+	}
+	return 0, errors.Errorf("unexpected %s type %T", ConfigMaxSize, v)
+}
+
 // Name returns the name of a storage source. This is not necessarily unique,
 // and should only be used for informational purposes.
 func (c *Config) Name() string {
@@ -71,6 +132,20 @@ func (c *Config) Attrs() map[string]interface{} {
 	return attrs
 }
 
+// MaxSize returns the maximum total size, in MiB, of storage that may
+// be created from the pool in a model, and whether or not a limit has
+// been set.
+func (c *Config) MaxSize() (uint64, bool) {
+	return c.maxSize, c.maxSize > 0
+}
+
+// MaxCount returns the maximum number of volumes and filesystems that
+// may be created from the pool in a model, and whether or not a limit
+// has been set.
+func (c *Config) MaxCount() (uint64, bool) {
+	return c.maxCount, c.maxCount > 0
+}
+
 // ValueString returns the named config attribute as a string.
 func (c *Config) ValueString(name string) (string, bool) {
 	v, ok := c.attrs[name].(string)
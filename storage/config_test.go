@@ -0,0 +1,64 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+)
+
+type ConfigSuite struct{}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestNoLimits(c *gc.C) {
+	cfg, err := storage.NewConfig("pool", "loop", map[string]interface{}{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := cfg.MaxSize()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = cfg.MaxCount()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.Attrs(), jc.DeepEquals, map[string]interface{}{"foo": "bar"})
+}
+
+func (s *ConfigSuite) TestLimits(c *gc.C) {
+	cfg, err := storage.NewConfig("pool", "loop", map[string]interface{}{
+		"max-size":  "10G",
+		"max-count": "5",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := cfg.MaxSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(10*1024))
+	count, ok := cfg.MaxCount()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(count, gc.Equals, uint64(5))
+}
+
+func (s *ConfigSuite) TestMaxSizeMiB(c *gc.C) {
+	cfg, err := storage.NewConfig("pool", "loop", map[string]interface{}{
+		"max-size": 2048,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := cfg.MaxSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(2048))
+}
+
+func (s *ConfigSuite) TestInvalidLimits(c *gc.C) {
+	_, err := storage.NewConfig("pool", "loop", map[string]interface{}{
+		"max-size": "lots",
+	})
+	c.Assert(err, gc.ErrorMatches, `validating common storage config: parsing max-size: .*`)
+	_, err = storage.NewConfig("pool", "loop", map[string]interface{}{
+		"max-count": -1,
+	})
+	c.Assert(err, gc.ErrorMatches, `validating common storage config: max-count must not be negative, got -1`)
+	_, err = storage.NewConfig("pool", "loop", map[string]interface{}{
+		"max-count": "many",
+	})
+	c.Assert(err, gc.ErrorMatches, `validating common storage config: max-count: .*`)
+}
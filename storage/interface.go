@@ -62,6 +62,48 @@ type Provider interface {
 	ValidateConfig(*Config) error
 }
 
+// SharedFilesystemProvider is an optional interface that a Provider may
+// implement if the filesystems it manages may be attached to multiple
+// machines concurrently, such as network filesystems. Filesystems from
+// such providers may be used for charm storage declared as shared.
+//
+// Shared filesystems are model-scoped: they are created and destroyed
+// by the model's storage provisioner, but attached and detached by the
+// storage provisioner of each machine they are attached to.
+type SharedFilesystemProvider interface {
+	Provider
+
+	// SharedFilesystems reports whether or not filesystems managed
+	// by the provider may be attached to multiple machines at once.
+	SharedFilesystems() bool
+}
+
+// SupportsSharedFilesystems reports whether or not the specified provider
+// manages filesystems that may be attached to multiple machines at once.
+func SupportsSharedFilesystems(p Provider) bool {
+	shared, ok := p.(SharedFilesystemProvider)
+	return ok && shared.SharedFilesystems()
+}
+
+// ConfiguredPoolProvider is an optional interface that a Provider may
+// implement if it has no usable default configuration, and so can only
+// be used through a storage pool that supplies its configuration.
+type ConfiguredPoolProvider interface {
+	Provider
+
+	// RequiresConfiguredPool reports whether or not the provider
+	// can only be used through a configured storage pool.
+	RequiresConfiguredPool() bool
+}
+
+// RequiresConfiguredPool reports whether or not the specified provider
+// can only be used through a storage pool that supplies its configuration.
+// The types of such providers cannot be used in place of a pool name.
+func RequiresConfiguredPool(p Provider) bool {
+	configured, ok := p.(ConfiguredPoolProvider)
+	return ok && configured.RequiresConfiguredPool()
+}
+
 // VolumeSource provides an interface for creating, destroying, describing,
 // attaching and detaching volumes in the environment. A VolumeSource is
 // configured in a particular way, and corresponds to a storage "pool".
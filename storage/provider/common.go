@@ -15,6 +15,7 @@ var errNoMountPoint = errors.New("filesystem mount point not specified")
 func CommonProviders() map[storage.ProviderType]storage.Provider {
 	return map[storage.ProviderType]storage.Provider{
 		LoopProviderType:   &loopProvider{logAndExec},
+		NFSProviderType:    &nfsProvider{logAndExec},
 		RootfsProviderType: &rootfsProvider{logAndExec},
 		TmpfsProviderType:  &tmpfsProvider{logAndExec},
 	}
@@ -0,0 +1,328 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	NFSProviderType = storage.ProviderType("nfs")
+
+	// NFSExport is the pool configuration attribute that specifies
+	// the NFS export to mount, in the form "host:/path".
+	NFSExport = "export"
+)
+
+// nfsProvider creates storage sources which manage filesystems on a
+// pre-existing NFS export. Each filesystem is a subdirectory of the
+// pool's export, so the data is isolated from that of other
+// filesystems, and shared by all machines the filesystem is attached
+// to.
+type nfsProvider struct {
+	// run is a function type used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var (
+	_ storage.Provider                 = (*nfsProvider)(nil)
+	_ storage.SharedFilesystemProvider = (*nfsProvider)(nil)
+	_ storage.ConfiguredPoolProvider   = (*nfsProvider)(nil)
+)
+
+// ValidateConfig is defined on the Provider interface.
+func (p *nfsProvider) ValidateConfig(cfg *storage.Config) error {
+	export, ok := cfg.ValueString(NFSExport)
+	if !ok || export == "" {
+		return errors.Errorf("%q must be specified", NFSExport)
+	}
+	if err := validateNFSExport(export); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// validateNFSExport checks that the export is of the form "host:/path".
+func validateNFSExport(export string) error {
+	fields := strings.SplitN(export, ":", 2)
+	if len(fields) != 2 || fields[0] == "" || !path.IsAbs(fields[1]) {
+		return errors.NotValidf("NFS export %q (expected host:/path)", export)
+	}
+	return nil
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *nfsProvider) VolumeSource(environConfig *config.Config, providerConfig *storage.Config) (storage.VolumeSource, error) {
+	return nil, errors.NotSupportedf("volumes")
+}
+
+// FilesystemSource is defined on the Provider interface.
+//
+// The source is not tied to any one export: the export is taken from
+// the pool attributes in the parameters of each filesystem created,
+// and recorded in the filesystem's ID for attaching and destroying it.
+func (p *nfsProvider) FilesystemSource(environConfig *config.Config, sourceConfig *storage.Config) (storage.FilesystemSource, error) {
+	return &nfsFilesystemSource{
+		&osDirFuncs{p.run},
+		p.run,
+		os.TempDir(),
+	}, nil
+}
+
+// Supports is defined on the Provider interface.
+func (*nfsProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindFilesystem
+}
+
+// Scope is defined on the Provider interface.
+//
+// NFS filesystems are created and destroyed by the model's storage
+// provisioner, and attached by the storage provisioner of each machine
+// they are attached to; see storage.SharedFilesystemProvider.
+func (*nfsProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is defined on the Provider interface.
+func (*nfsProvider) Dynamic() bool {
+	return true
+}
+
+// SharedFilesystems is defined on the SharedFilesystemProvider interface.
+func (*nfsProvider) SharedFilesystems() bool {
+	return true
+}
+
+// RequiresConfiguredPool is defined on the ConfiguredPoolProvider interface.
+//
+// There is no default export, so the provider can only be used through
+// a pool that specifies one.
+func (*nfsProvider) RequiresConfiguredPool() bool {
+	return true
+}
+
+type nfsFilesystemSource struct {
+	dirFuncs dirFuncs
+	run      runCommandFunc
+	// tempDir is the directory in which exports are temporarily
+	// mounted, to create and remove filesystem subdirectories.
+	tempDir string
+}
+
+var _ storage.FilesystemSource = (*nfsFilesystemSource)(nil)
+
+// ValidateFilesystemParams is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
+	// The size of an NFS export is managed by the NFS server,
+	// so there is only the export itself to check here.
+	_, err := nfsFilesystemExport(params)
+	return errors.Trace(err)
+}
+
+// nfsFilesystemExport returns the NFS export specified in the pool
+// attributes of the filesystem parameters.
+func nfsFilesystemExport(params storage.FilesystemParams) (string, error) {
+	export, _ := params.Attributes[NFSExport].(string)
+	if export == "" {
+		return "", errors.Errorf("%q must be specified", NFSExport)
+	}
+	if err := validateNFSExport(export); err != nil {
+		return "", errors.Trace(err)
+	}
+	return export, nil
+}
+
+// nfsSubdirectory returns the name of the subdirectory of the export
+// that holds the data of the filesystem with the specified parameters.
+// The subdirectory is named for the model as well as the filesystem,
+// so that models may share an export.
+func nfsSubdirectory(params storage.FilesystemParams) string {
+	name := "juju-" + params.Tag.String()
+	if modelUUID := params.ResourceTags[tags.JujuModel]; modelUUID != "" {
+		name = "juju-" + modelUUID + "-" + params.Tag.String()
+	}
+	return name
+}
+
+// splitNFSFilesystemId splits the ID of a filesystem created by an
+// nfsFilesystemSource into the export and the filesystem's subdirectory
+// of it.
+func splitNFSFilesystemId(filesystemId string) (export, subdirectory string, _ error) {
+	if err := validateNFSExport(filesystemId); err != nil {
+		return "", "", errors.Trace(err)
+	}
+	fields := strings.SplitN(filesystemId, ":", 2)
+	dir, subdirectory := path.Split(fields[1])
+	if subdirectory == "" || dir == "" {
+		return "", "", errors.NotValidf("NFS filesystem ID %q", filesystemId)
+	}
+	return fields[0] + ":" + path.Clean(dir), subdirectory, nil
+}
+
+// CreateFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) CreateFilesystems(args []storage.FilesystemParams) ([]storage.CreateFilesystemsResult, error) {
+	results := make([]storage.CreateFilesystemsResult, len(args))
+	for i, arg := range args {
+		filesystem, err := s.createFilesystem(arg)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].Filesystem = filesystem
+	}
+	return results, nil
+}
+
+func (s *nfsFilesystemSource) createFilesystem(arg storage.FilesystemParams) (*storage.Filesystem, error) {
+	export, err := nfsFilesystemExport(arg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subdirectory := nfsSubdirectory(arg)
+	if err := s.withExportMounted(export, subdirectory, func(mountPoint string) error {
+		return s.dirFuncs.mkDirAll(filepath.Join(mountPoint, subdirectory), 0755)
+	}); err != nil {
+		return nil, errors.Annotatef(err, "creating %s", names.ReadableString(arg.Tag))
+	}
+	// The filesystem ID is the subdirectory of the export, in
+	// mountable form. We record the requested size, since the
+	// actual capacity of the export is not known until it is
+	// mounted.
+	fields := strings.SplitN(export, ":", 2)
+	return &storage.Filesystem{
+		arg.Tag,
+		arg.Volume,
+		storage.FilesystemInfo{
+			FilesystemId: fields[0] + ":" + path.Join(fields[1], subdirectory),
+			Size:         arg.Size,
+		},
+	}, nil
+}
+
+// withExportMounted mounts the export in a temporary directory named
+// for the subdirectory being operated on, calls f with the directory,
+// and then unmounts and removes it.
+func (s *nfsFilesystemSource) withExportMounted(export, subdirectory string, f func(string) error) (err error) {
+	mountPoint := filepath.Join(s.tempDir, subdirectory)
+	if err := ensureDir(s.dirFuncs, mountPoint); err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(mountPoint)
+	if _, err := s.run("mount", "-t", "nfs", export, mountPoint); err != nil {
+		return errors.Annotatef(err, "cannot mount NFS export %q", export)
+	}
+	defer func() {
+		if _, unmountErr := s.run("umount", mountPoint); unmountErr != nil && err == nil {
+			err = errors.Annotatef(unmountErr, "cannot unmount NFS export %q", export)
+		}
+	}()
+	return f(mountPoint)
+}
+
+// DestroyFilesystems is defined on the FilesystemSource interface.
+//
+// Destroying a filesystem removes its subdirectory, and all of the
+// data in it, from the export.
+func (s *nfsFilesystemSource) DestroyFilesystems(filesystemIds []string) ([]error, error) {
+	results := make([]error, len(filesystemIds))
+	for i, filesystemId := range filesystemIds {
+		results[i] = s.destroyFilesystem(filesystemId)
+	}
+	return results, nil
+}
+
+func (s *nfsFilesystemSource) destroyFilesystem(filesystemId string) error {
+	export, subdirectory, err := splitNFSFilesystemId(filesystemId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := s.withExportMounted(export, subdirectory, func(mountPoint string) error {
+		return os.RemoveAll(filepath.Join(mountPoint, subdirectory))
+	}); err != nil {
+		return errors.Annotatef(err, "destroying filesystem %q", filesystemId)
+	}
+	return nil
+}
+
+// AttachFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) AttachFilesystems(args []storage.FilesystemAttachmentParams) ([]storage.AttachFilesystemsResult, error) {
+	results := make([]storage.AttachFilesystemsResult, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachFilesystem(arg)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].FilesystemAttachment = attachment
+	}
+	return results, nil
+}
+
+func (s *nfsFilesystemSource) attachFilesystem(arg storage.FilesystemAttachmentParams) (*storage.FilesystemAttachment, error) {
+	path := arg.Path
+	if path == "" {
+		return nil, errNoMountPoint
+	}
+	// The filesystem ID is the subdirectory of the export
+	// that holds the filesystem's data, in mountable form.
+	source := arg.FilesystemId
+	if source == "" {
+		return nil, errors.NotProvisionedf("%s", names.ReadableString(arg.Filesystem))
+	}
+	if _, _, err := splitNFSFilesystemId(source); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := ensureDir(s.dirFuncs, path); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Check if the mount already exists.
+	mountSource, err := s.dirFuncs.mountPointSource(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if mountSource != source {
+		if err := ensureEmptyDir(s.dirFuncs, path); err != nil {
+			return nil, err
+		}
+		args := []string{"-t", "nfs", source, path}
+		if arg.ReadOnly {
+			args = append(args, "-o", "ro")
+		}
+		if _, err := s.run("mount", args...); err != nil {
+			os.Remove(path)
+			return nil, errors.Annotatef(err, "cannot mount NFS filesystem %q", source)
+		}
+	}
+
+	return &storage.FilesystemAttachment{
+		arg.Filesystem,
+		arg.Machine,
+		storage.FilesystemAttachmentInfo{
+			Path:     path,
+			ReadOnly: arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) DetachFilesystems(args []storage.FilesystemAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		if err := maybeUnmount(s.run, s.dirFuncs, arg.Path); err != nil {
+			results[i] = err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,290 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&nfsSuite{})
+
+type nfsSuite struct {
+	testing.BaseSuite
+	commands *mockRunCommand
+}
+
+func (s *nfsSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("Tests relevant only on *nix systems")
+	}
+	s.BaseSuite.SetUpTest(c)
+}
+
+func (s *nfsSuite) TearDownTest(c *gc.C) {
+	if s.commands != nil {
+		s.commands.assertDrained()
+	}
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *nfsSuite) nfsProvider(c *gc.C) storage.Provider {
+	s.commands = &mockRunCommand{c: c}
+	return provider.NFSProvider(s.commands.run)
+}
+
+func (s *nfsSuite) nfsFilesystemSource(c *gc.C) (storage.FilesystemSource, *provider.MockDirFuncs, string) {
+	s.commands = &mockRunCommand{c: c}
+	tempDir := c.MkDir()
+	source, dirFuncs := provider.NFSFilesystemSource(tempDir, s.commands.run)
+	return source, dirFuncs, tempDir
+}
+
+func (s *nfsSuite) TestFilesystemSource(c *gc.C) {
+	p := s.nfsProvider(c)
+	cfg, err := storage.NewConfig("name", provider.NFSProviderType, map[string]interface{}{
+		"storage-dir": c.MkDir(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = p.FilesystemSource(nil, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *nfsSuite) TestValidateConfig(c *gc.C) {
+	p := s.nfsProvider(c)
+	assertErr := func(attrs map[string]interface{}, expect string) {
+		cfg, err := storage.NewConfig("name", provider.NFSProviderType, attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		c.Assert(err, gc.ErrorMatches, expect)
+	}
+	assertErr(map[string]interface{}{}, `"export" must be specified`)
+	assertErr(map[string]interface{}{"export": "/srv/data"}, `NFS export "/srv/data" \(expected host:/path\) not valid`)
+	assertErr(map[string]interface{}{"export": ":/srv/data"}, `NFS export ":/srv/data" \(expected host:/path\) not valid`)
+	assertErr(map[string]interface{}{"export": "host:srv"}, `NFS export "host:srv" \(expected host:/path\) not valid`)
+
+	cfg, err := storage.NewConfig("name", provider.NFSProviderType, map[string]interface{}{
+		"export": "host:/srv",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *nfsSuite) TestSupports(c *gc.C) {
+	p := s.nfsProvider(c)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsFalse)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsTrue)
+}
+
+func (s *nfsSuite) TestScope(c *gc.C) {
+	p := s.nfsProvider(c)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeEnviron)
+}
+
+func (s *nfsSuite) TestSharedFilesystems(c *gc.C) {
+	p := s.nfsProvider(c)
+	c.Assert(storage.SupportsSharedFilesystems(p), jc.IsTrue)
+	c.Assert(storage.SupportsSharedFilesystems(provider.TmpfsProvider(nil)), jc.IsFalse)
+}
+
+func (s *nfsSuite) TestRequiresConfiguredPool(c *gc.C) {
+	p := s.nfsProvider(c)
+	c.Assert(storage.RequiresConfiguredPool(p), jc.IsTrue)
+	c.Assert(storage.RequiresConfiguredPool(provider.TmpfsProvider(nil)), jc.IsFalse)
+}
+
+func (s *nfsSuite) TestValidateFilesystemParams(c *gc.C) {
+	source, _, _ := s.nfsFilesystemSource(c)
+	err := source.ValidateFilesystemParams(storage.FilesystemParams{
+		Tag: names.NewFilesystemTag("1"),
+	})
+	c.Assert(err, gc.ErrorMatches, `"export" must be specified`)
+	err = source.ValidateFilesystemParams(storage.FilesystemParams{
+		Tag:        names.NewFilesystemTag("1"),
+		Attributes: map[string]interface{}{"export": "10.0.0.1:/srv/data"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *nfsSuite) TestCreateFilesystems(c *gc.C) {
+	source, dirFuncs, tempDir := s.nfsFilesystemSource(c)
+	mountPoint := filepath.Join(tempDir, "juju-deadbeef-filesystem-1")
+	s.commands.expect("mount", "-t", "nfs", "10.0.0.1:/srv/data", mountPoint)
+	s.commands.expect("umount", mountPoint)
+	mountPoint2 := filepath.Join(tempDir, "juju-filesystem-2")
+	s.commands.expect("mount", "-t", "nfs", "10.0.0.1:/srv/data/", mountPoint2)
+	s.commands.expect("umount", mountPoint2)
+
+	results, err := source.CreateFilesystems([]storage.FilesystemParams{{
+		Tag:          names.NewFilesystemTag("1"),
+		Size:         1024,
+		Attributes:   map[string]interface{}{"export": "10.0.0.1:/srv/data"},
+		ResourceTags: map[string]string{"juju-model-uuid": "deadbeef"},
+	}, {
+		Tag:        names.NewFilesystemTag("2"),
+		Size:       2048,
+		Attributes: map[string]interface{}{"export": "10.0.0.1:/srv/data/"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateFilesystemsResult{{
+		Filesystem: &storage.Filesystem{
+			Tag: names.NewFilesystemTag("1"),
+			FilesystemInfo: storage.FilesystemInfo{
+				FilesystemId: "10.0.0.1:/srv/data/juju-deadbeef-filesystem-1",
+				Size:         1024,
+			},
+		},
+	}, {
+		Filesystem: &storage.Filesystem{
+			Tag: names.NewFilesystemTag("2"),
+			FilesystemInfo: storage.FilesystemInfo{
+				FilesystemId: "10.0.0.1:/srv/data/juju-filesystem-2",
+				Size:         2048,
+			},
+		},
+	}})
+	c.Assert(dirFuncs.Dirs.Contains(filepath.Join(mountPoint, "juju-deadbeef-filesystem-1")), jc.IsTrue)
+	c.Assert(dirFuncs.Dirs.Contains(filepath.Join(mountPoint2, "juju-filesystem-2")), jc.IsTrue)
+}
+
+func (s *nfsSuite) TestCreateFilesystemsMountFails(c *gc.C) {
+	source, _, tempDir := s.nfsFilesystemSource(c)
+	mountPoint := filepath.Join(tempDir, "juju-filesystem-1")
+	cmd := s.commands.expect("mount", "-t", "nfs", "10.0.0.1:/srv/data", mountPoint)
+	cmd.respond("", errors.New("mount failed"))
+
+	results, err := source.CreateFilesystems([]storage.FilesystemParams{{
+		Tag:        names.NewFilesystemTag("1"),
+		Size:       1024,
+		Attributes: map[string]interface{}{"export": "10.0.0.1:/srv/data"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, `creating filesystem 1: cannot mount NFS export "10.0.0.1:/srv/data": mount failed`)
+}
+
+func (s *nfsSuite) TestDestroyFilesystems(c *gc.C) {
+	source, _, tempDir := s.nfsFilesystemSource(c)
+	mountPoint := filepath.Join(tempDir, "juju-filesystem-1")
+	data := filepath.Join(mountPoint, "juju-filesystem-1", "data")
+	err := os.MkdirAll(data, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.commands.expect("mount", "-t", "nfs", "10.0.0.1:/srv/data", mountPoint)
+	s.commands.expect("umount", mountPoint)
+
+	results, err := source.DestroyFilesystems([]string{
+		"10.0.0.1:/srv/data/juju-filesystem-1",
+		"10.0.0.1:/srv/data",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.ErrorIsNil)
+	c.Assert(results[1], gc.ErrorMatches, `NFS filesystem ID "10.0.0.1:/srv/data" not valid`)
+	_, err = os.Stat(filepath.Join(mountPoint, "juju-filesystem-1"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *nfsSuite) TestAttachFilesystems(c *gc.C) {
+	source, _, _ := s.nfsFilesystemSource(c)
+	cmd := s.commands.expect("df", "--output=source", "/srv/shared")
+	cmd.respond("header\n/dev/sda1", nil)
+	s.commands.expect("mount", "-t", "nfs", "10.0.0.1:/srv/data/juju-filesystem-1", "/srv/shared", "-o", "ro")
+
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("1"),
+		FilesystemId: "10.0.0.1:/srv/data/juju-filesystem-1",
+		Path:         "/srv/shared",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachFilesystemsResult{{
+		FilesystemAttachment: &storage.FilesystemAttachment{
+			Filesystem: names.NewFilesystemTag("1"),
+			Machine:    names.NewMachineTag("0"),
+			FilesystemAttachmentInfo: storage.FilesystemAttachmentInfo{
+				Path:     "/srv/shared",
+				ReadOnly: true,
+			},
+		},
+	}})
+}
+
+func (s *nfsSuite) TestAttachFilesystemsAlreadyMounted(c *gc.C) {
+	source, _, _ := s.nfsFilesystemSource(c)
+	cmd := s.commands.expect("df", "--output=source", "exists")
+	cmd.respond("header\n10.0.0.1:/srv/data/juju-filesystem-1", nil)
+
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("1"),
+		FilesystemId: "10.0.0.1:/srv/data/juju-filesystem-1",
+		Path:         "exists",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachFilesystemsResult{{
+		FilesystemAttachment: &storage.FilesystemAttachment{
+			Filesystem: names.NewFilesystemTag("1"),
+			FilesystemAttachmentInfo: storage.FilesystemAttachmentInfo{
+				Path: "exists",
+			},
+		},
+	}})
+}
+
+func (s *nfsSuite) TestAttachFilesystemsMountFails(c *gc.C) {
+	source, _, _ := s.nfsFilesystemSource(c)
+	cmd := s.commands.expect("df", "--output=source", "/srv/shared")
+	cmd.respond("header\n/dev/sda1", nil)
+	cmd = s.commands.expect("mount", "-t", "nfs", "10.0.0.1:/srv/data/juju-filesystem-1", "/srv/shared")
+	cmd.respond("", errors.New("mount failed"))
+
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("1"),
+		FilesystemId: "10.0.0.1:/srv/data/juju-filesystem-1",
+		Path:         "/srv/shared",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, `cannot mount NFS filesystem "10.0.0.1:/srv/data/juju-filesystem-1": mount failed`)
+}
+
+func (s *nfsSuite) TestAttachFilesystemsNotProvisioned(c *gc.C) {
+	source, _, _ := s.nfsFilesystemSource(c)
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem: names.NewFilesystemTag("1"),
+		Path:       "/srv/shared",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, `filesystem 1 not provisioned`)
+}
+
+func (s *nfsSuite) TestAttachFilesystemsNoPathSpecified(c *gc.C) {
+	source, _, _ := s.nfsFilesystemSource(c)
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem: names.NewFilesystemTag("1"),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, "filesystem mount point not specified")
+}
+
+func (s *nfsSuite) TestDetachFilesystems(c *gc.C) {
+	source, _, _ := s.nfsFilesystemSource(c)
+	testDetachFilesystems(c, s.commands, source, true)
+}
+
+func (s *nfsSuite) TestDetachFilesystemsUnattached(c *gc.C) {
+	source, _, _ := s.nfsFilesystemSource(c)
+	testDetachFilesystems(c, s.commands, source, false)
+}
@@ -37,6 +37,9 @@ var (
 
 	// Other valid test certs different from the default.
 	OtherCACert, OtherCAKey = mustNewCA()
+
+	// SecretsKey holds a key with which to encrypt secrets.
+	SecretsKey = []byte("0123456789abcdef0123456789abcdef")
 )
 
 func verifyCertificates() error {
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/gorilla/websocket"
@@ -29,6 +30,7 @@ type rawInstanceClient interface {
 	Action(name string, action shared.ContainerAction, timeout int, force bool) (*lxd.Response, error)
 	Exec(name string, cmd []string, env map[string]string, stdin io.ReadCloser, stdout io.WriteCloser, stderr io.WriteCloser, controlHandler func(*lxd.Client, *websocket.Conn)) (int, error)
 	Delete(name string) (*lxd.Response, error)
+	ContainerDeviceAdd(container, devname, devtype string, props []string) (*lxd.Response, error)
 
 	WaitForSuccess(waitURL string) error
 	ContainerState(name string) (*shared.ContainerState, error)
@@ -73,6 +75,48 @@ func (client *instanceClient) addInstance(spec InstanceSpec) error {
 		return errors.Trace(err)
 	}
 
+	if err := client.addDevices(spec); err != nil {
+		if err := client.removeInstance(spec.Name); err != nil {
+			logger.Errorf("could not remove container %q after adding devices failed", spec.Name)
+		}
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+// addDevices adds the devices in the spec to the newly initialised
+// container.
+func (client *instanceClient) addDevices(spec InstanceSpec) error {
+	devNames := make([]string, 0, len(spec.Devices))
+	for name := range spec.Devices {
+		devNames = append(devNames, name)
+	}
+	sort.Strings(devNames)
+
+	for _, name := range devNames {
+		var devType string
+		var props []string
+		for key, value := range spec.Devices[name] {
+			if key == "type" {
+				devType = value
+				continue
+			}
+			props = append(props, key+"="+value)
+		}
+		if devType == "" {
+			return errors.Errorf("device %q has no type", name)
+		}
+		sort.Strings(props)
+
+		resp, err := client.raw.ContainerDeviceAdd(spec.Name, name, devType, props)
+		if err != nil {
+			return errors.Annotatef(err, "cannot add device %q", name)
+		}
+		if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+			return errors.Annotatef(err, "cannot add device %q", name)
+		}
+	}
 	return nil
 }
 
@@ -344,11 +388,17 @@ func (client *instanceClient) Addresses(name string) ([]network.Address, error)
 			// aware. That information at best would need to be
 			// passed in. (only the DB really knows what spaces are
 			// available, and lxdclient doesn't talk to the DB.)
-			addrs = append(addrs, network.Address{
-				Value:       addr.Address,
-				Type:        type_,
-				NetworkName: name,
-			})
+			address := network.NewAddress(addr.Address)
+			if address.Type != type_ {
+				logger.Warningf("derived address type %v, LXD reports %v", address.Type, type_)
+			}
+			if address.Scope == network.ScopeLinkLocal {
+				// Link-local IPv6 addresses are present on every
+				// interface, and are no use to other machines.
+				continue
+			}
+			address.NetworkName = name
+			addrs = append(addrs, address)
 		}
 	}
 
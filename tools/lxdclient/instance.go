@@ -74,6 +74,12 @@ type InstanceSpec struct {
 	// Metadata is the instance metadata.
 	Metadata map[string]string
 
+	// Devices holds the devices to add to the container, keyed by
+	// device name. Each device is described by its properties, which
+	// must include "type". A device with the same name as one in the
+	// container's profiles overrides it.
+	Devices map[string]map[string]string
+
 	// TODO(ericsnow) Other possible fields:
 	// Disks
 	// Networks
@@ -10,6 +10,11 @@ var (
 	StateUpgradeOperations = &stateUpgradeOperations
 )
 
+var (
+	EnsureSecretsKey = ensureSecretsKey
+	FetchSecretsKey  = fetchSecretsKey
+)
+
 type ModelConfigUpdater environConfigUpdater
 type ModelConfigReader environConfigReader
 
@@ -0,0 +1,90 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/agent"
+	apiagent "github.com/juju/juju/api/agent"
+	"github.com/juju/juju/state"
+)
+
+// stepsFor20 returns upgrade steps for Juju 2.0.
+func stepsFor20() []Step {
+	return []Step{
+		&upgradeStep{
+			description: "fetch secrets key",
+			targets:     []Target{Controller},
+			run: func(context Context) error {
+				return fetchSecretsKey(context.AgentConfig(), apiagent.NewState(context.APIState()))
+			},
+		},
+	}
+}
+
+// stateStepsFor20 returns upgrade steps for Juju 2.0 that manipulate state directly.
+func stateStepsFor20() []Step {
+	return []Step{
+		&upgradeStep{
+			description: "generate secrets key",
+			targets:     []Target{DatabaseMaster},
+			run: func(context Context) error {
+				return ensureSecretsKey(context.AgentConfig(), context.State())
+			},
+		},
+	}
+}
+
+// secretsKeySetter is the part of *state.State used by ensureSecretsKey.
+type secretsKeySetter interface {
+	SetSecretsKey(key []byte)
+}
+
+// ensureSecretsKey generates the key with which controllers encrypt
+// secrets, if the agent configuration of the database master does not
+// already hold one, and writes it there. Controllers bootstrapped
+// before secrets were added have no key, and so cannot store secrets.
+func ensureSecretsKey(config agent.ConfigSetter, st secretsKeySetter) error {
+	key, err := agent.SecretsKeyValue(config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if key == nil {
+		key, err = state.NewSecretsKey()
+		if err != nil {
+			return errors.Annotate(err, "cannot generate secrets key")
+		}
+		agent.SetSecretsKeyValue(config, key)
+	}
+	st.SetSecretsKey(key)
+	return nil
+}
+
+// secretsKeySource is the part of *apiagent.State used by
+// fetchSecretsKey.
+type secretsKeySource interface {
+	SecretsKey() ([]byte, error)
+}
+
+// fetchSecretsKey copies the key with which controllers encrypt
+// secrets from the controller into the agent configuration, if it
+// does not already hold one. The database master generates the key
+// before the other controllers run this step, so a controller that
+// has none yet returns an error, and the step is retried.
+func fetchSecretsKey(config agent.ConfigSetter, source secretsKeySource) error {
+	key, err := agent.SecretsKeyValue(config)
+	if err != nil || key != nil {
+		return errors.Trace(err)
+	}
+	key, err = source.SecretsKey()
+	if err != nil {
+		return errors.Annotate(err, "cannot get secrets key")
+	}
+	if len(key) == 0 {
+		return errors.New("secrets key not yet available")
+	}
+	agent.SetSecretsKeyValue(config, key)
+	return nil
+}
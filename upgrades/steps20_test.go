@@ -0,0 +1,106 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+	"github.com/juju/juju/version"
+)
+
+type steps20Suite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&steps20Suite{})
+
+func (s *steps20Suite) TestStepsFor20(c *gc.C) {
+	expected := []string{
+		"fetch secrets key",
+	}
+	assertSteps(c, version.MustParse("2.0.0"), expected)
+}
+
+func (s *steps20Suite) TestStateStepsFor20(c *gc.C) {
+	expected := []string{
+		"generate secrets key",
+	}
+	assertStateSteps(c, version.MustParse("2.0.0"), expected)
+}
+
+type fakeSecretsKeyState struct {
+	key []byte
+	err error
+}
+
+func (st *fakeSecretsKeyState) SetSecretsKey(key []byte) {
+	st.key = key
+}
+
+func (st *fakeSecretsKeyState) SecretsKey() ([]byte, error) {
+	return st.key, st.err
+}
+
+func (s *steps20Suite) TestEnsureSecretsKeyGenerates(c *gc.C) {
+	config := &mockAgentConfig{}
+	st := &fakeSecretsKeyState{}
+	err := upgrades.EnsureSecretsKey(config, st)
+	c.Assert(err, jc.ErrorIsNil)
+
+	key, err := agent.SecretsKeyValue(config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, gc.HasLen, state.SecretsKeySize)
+	c.Assert(st.key, jc.DeepEquals, key)
+}
+
+func (s *steps20Suite) TestEnsureSecretsKeyKeepsExisting(c *gc.C) {
+	config := &mockAgentConfig{}
+	existing := testing.SecretsKey
+	agent.SetSecretsKeyValue(config, existing)
+	st := &fakeSecretsKeyState{}
+	err := upgrades.EnsureSecretsKey(config, st)
+	c.Assert(err, jc.ErrorIsNil)
+
+	key, err := agent.SecretsKeyValue(config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, jc.DeepEquals, existing)
+	c.Assert(st.key, jc.DeepEquals, existing)
+}
+
+func (s *steps20Suite) TestFetchSecretsKey(c *gc.C) {
+	config := &mockAgentConfig{}
+	source := &fakeSecretsKeyState{key: testing.SecretsKey}
+	err := upgrades.FetchSecretsKey(config, source)
+	c.Assert(err, jc.ErrorIsNil)
+
+	key, err := agent.SecretsKeyValue(config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, jc.DeepEquals, testing.SecretsKey)
+}
+
+func (s *steps20Suite) TestFetchSecretsKeyKeepsExisting(c *gc.C) {
+	config := &mockAgentConfig{}
+	agent.SetSecretsKeyValue(config, testing.SecretsKey)
+	source := &fakeSecretsKeyState{err: errors.New("should not be called")}
+	err := upgrades.FetchSecretsKey(config, source)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *steps20Suite) TestFetchSecretsKeyNotYetAvailable(c *gc.C) {
+	config := &mockAgentConfig{}
+	err := upgrades.FetchSecretsKey(config, &fakeSecretsKeyState{})
+	c.Assert(err, gc.ErrorMatches, "secrets key not yet available")
+	c.Assert(config.Value(agent.SecretsKey), gc.Equals, "")
+
+	source := &fakeSecretsKeyState{err: errors.New("boom")}
+	err = upgrades.FetchSecretsKey(config, source)
+	c.Assert(err, gc.ErrorMatches, "cannot get secrets key: boom")
+}
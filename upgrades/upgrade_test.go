@@ -191,6 +191,13 @@ func (mock *mockAgentConfig) Value(name string) string {
 	return mock.values[name]
 }
 
+func (mock *mockAgentConfig) SetValue(name, value string) {
+	if mock.values == nil {
+		mock.values = make(map[string]string)
+	}
+	mock.values[name] = value
+}
+
 func (mock *mockAgentConfig) MongoInfo() (*mongo.MongoInfo, bool) {
 	return mock.mongoInfo, true
 }
@@ -670,6 +677,7 @@ func (s *upgradeSuite) TestStateUpgradeOperationsVersions(c *gc.C) {
 	c.Assert(versions, gc.DeepEquals, []string{
 		// TODO(axw) change to 2.0 when we update version
 		"1.26.0",
+		"2.0.0",
 	})
 }
 
@@ -678,6 +686,7 @@ func (s *upgradeSuite) TestUpgradeOperationsVersions(c *gc.C) {
 	c.Assert(versions, gc.DeepEquals, []string{
 		// TODO(axw) change to 2.0 when we update version
 		"1.26.0",
+		"2.0.0",
 	})
 }
 
@@ -4,6 +4,9 @@
 package firewaller
 
 import (
+	"fmt"
+	"net"
+	"sort"
 	"strings"
 
 	"github.com/juju/errors"
@@ -33,14 +36,35 @@ type Firewaller struct {
 	modelWatcher    watcher.NotifyWatcher
 	machinesWatcher watcher.StringsWatcher
 	portsWatcher    watcher.StringsWatcher
+	subnetsWatcher  watcher.NotifyWatcher
 	machineds       map[names.MachineTag]*machineData
 	unitsChange     chan *unitsChange
 	unitds          map[names.UnitTag]*unitData
 	serviceds       map[names.ServiceTag]*serviceData
 	exposedChange   chan *exposedChange
 	globalMode      bool
-	globalPortRef   map[network.PortRange]int
+	globalRuleRef   map[string]int
 	machinePorts    map[names.MachineTag]machineRanges
+
+	// supportsIngressRules records whether the environ's firewall
+	// can restrict access to ports by source address.
+	supportsIngressRules bool
+
+	// supportsSubnetIngressRules records whether the environ's
+	// instances can restrict access to ports to their interfaces
+	// on particular subnets. If they cannot, rules restricted to
+	// particular subnets are only applied to instances with an
+	// address in one of them.
+	supportsSubnetIngressRules bool
+
+	// egressPolicy holds the egress policy last applied to the
+	// environ's firewall.
+	egressPolicy network.EgressPolicy
+
+	// loadBalancers is used to manage the load balancers of services
+	// exposed through one, or nil if the environ does not support
+	// load balancers.
+	loadBalancers environs.LoadBalancerManager
 }
 
 // NewFirewaller returns a new Firewaller or a new FirewallerV0,
@@ -88,13 +112,26 @@ func (fw *Firewaller) setUp() error {
 	switch fw.environ.Config().FirewallMode() {
 	case config.FwGlobal:
 		fw.globalMode = true
-		fw.globalPortRef = make(map[network.PortRange]int)
+		fw.globalRuleRef = make(map[string]int)
 	case config.FwNone:
 		logger.Warningf("stopping firewaller - firewall-mode is %q", config.FwNone)
 		// XXX(fwereade): shouldn't this be nil? Nothing wrong, nothing to do,
 		// now that we've logged there's no further reason to complain or retry.
 		return errors.Errorf("firewaller is disabled when firewall-mode is %q", config.FwNone)
 	}
+	_, fw.supportsIngressRules = fw.environ.(environs.IngressRuleFirewaller)
+	if env, ok := fw.environ.(environs.SubnetIngressRuleFirewaller); ok {
+		fw.supportsSubnetIngressRules = env.SupportsSubnetIngressRules()
+	}
+	fw.loadBalancers, _ = fw.environ.(environs.LoadBalancerManager)
+	if env, ok := fw.environ.(environs.EgressFirewaller); ok {
+		if fw.egressPolicy, err = env.EgressPolicy(); err != nil {
+			return errors.Annotate(err, "cannot get egress policy")
+		}
+	}
+	if err := fw.applyEgressPolicy(fw.environ.Config()); err != nil {
+		return errors.Trace(err)
+	}
 
 	fw.machinesWatcher, err = fw.st.WatchModelMachines()
 	if err != nil {
@@ -113,6 +150,15 @@ func (fw *Firewaller) setUp() error {
 	}
 
 	logger.Debugf("started watching opened port ranges for the environment")
+
+	fw.subnetsWatcher, err = fw.st.WatchSubnets()
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not watching subnets: %v", err)
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to start subnets watcher")
+	} else if err := fw.catacomb.Add(fw.subnetsWatcher); err != nil {
+		return errors.Trace(err)
+	}
 	return nil
 }
 
@@ -122,6 +168,10 @@ func (fw *Firewaller) loop() error {
 	}
 	var reconciled bool
 	portsChange := fw.portsWatcher.Changes()
+	var subnetsChange <-chan struct{}
+	if fw.subnetsWatcher != nil {
+		subnetsChange = fw.subnetsWatcher.Changes()
+	}
 	for {
 		select {
 		case <-fw.catacomb.Dying():
@@ -139,6 +189,8 @@ func (fw *Firewaller) loop() error {
 				// XXX(fwereade): surely this is an error? probably moot, will
 				// hopefully be replaced with EnvironObserver.
 				logger.Errorf("loaded invalid environment configuration: %v", err)
+			} else if err := fw.applyEgressPolicy(config); err != nil {
+				return errors.Trace(err)
 			}
 		case change, ok := <-fw.machinesWatcher.Changes():
 			if !ok {
@@ -160,6 +212,9 @@ func (fw *Firewaller) loop() error {
 				if err != nil {
 					return errors.Trace(err)
 				}
+				if err := fw.reconcileLoadBalancers(); err != nil {
+					return errors.Trace(err)
+				}
 			}
 		case change, ok := <-portsChange:
 			if !ok {
@@ -174,12 +229,22 @@ func (fw *Firewaller) loop() error {
 					return errors.Trace(err)
 				}
 			}
+		case _, ok := <-subnetsChange:
+			if !ok {
+				return errors.New("subnets watcher closed")
+			}
+			if err := fw.subnetsChanged(); err != nil {
+				return errors.Trace(err)
+			}
 		case change := <-fw.unitsChange:
 			if err := fw.unitsChanged(change); err != nil {
 				return errors.Trace(err)
 			}
 		case change := <-fw.exposedChange:
 			change.serviced.exposed = change.exposed
+			change.serviced.loadBalanced = change.loadBalanced
+			change.serviced.exposedCIDRs = change.exposedCIDRs
+			change.serviced.boundSubnetCIDRs = change.boundSubnetCIDRs
 			unitds := []*unitData{}
 			for _, unitd := range change.serviced.unitds {
 				unitds = append(unitds, unitd)
@@ -198,7 +263,7 @@ func (fw *Firewaller) startMachine(tag names.MachineTag) error {
 		fw:           fw,
 		tag:          tag,
 		unitds:       make(map[names.UnitTag]*unitData),
-		openedPorts:  make([]network.PortRange, 0),
+		ingressRules: make([]network.IngressRule, 0),
 		definedPorts: make(map[network.PortRange]names.UnitTag),
 	}
 	m, err := machined.machine()
@@ -309,16 +374,36 @@ func (fw *Firewaller) startService(service *firewaller.Service) error {
 	if err != nil {
 		return err
 	}
+	loadBalanced, err := service.IsLoadBalanced()
+	if err != nil {
+		return err
+	}
+	exposedCIDRs, err := service.ExposedCIDRs()
+	if err != nil {
+		return err
+	}
+	boundSubnetCIDRs, err := service.BoundSubnetCIDRs()
+	if err != nil {
+		return err
+	}
+	loadBalancerAddress, err := service.LoadBalancerAddress()
+	if err != nil {
+		return err
+	}
 	serviced := &serviceData{
-		fw:      fw,
-		service: service,
-		exposed: exposed,
-		unitds:  make(map[names.UnitTag]*unitData),
+		fw:                  fw,
+		service:             service,
+		exposed:             exposed,
+		loadBalanced:        loadBalanced,
+		exposedCIDRs:        exposedCIDRs,
+		boundSubnetCIDRs:    boundSubnetCIDRs,
+		loadBalancerAddress: loadBalancerAddress,
+		unitds:              make(map[names.UnitTag]*unitData),
 	}
 	err = catacomb.Invoke(catacomb.Plan{
 		Site: &serviced.catacomb,
 		Work: func() error {
-			return serviced.watchLoop(exposed)
+			return serviced.watchLoop(exposed, loadBalanced, exposedCIDRs, boundSubnetCIDRs)
 		},
 	})
 	if err != nil {
@@ -335,43 +420,37 @@ func (fw *Firewaller) startService(service *firewaller.Service) error {
 // units and services with the opened and closed ports globally and
 // opens and closes the appropriate ports for the whole environment.
 func (fw *Firewaller) reconcileGlobal() error {
-	initialPortRanges, err := fw.environ.Ports()
+	initialRules, err := fw.environIngressRules()
 	if err != nil {
 		return err
 	}
-	collector := make(map[network.PortRange]bool)
+	collector := make(map[string]network.IngressRule)
 	for _, machined := range fw.machineds {
-		for portRange, unitTag := range machined.definedPorts {
-			unitd, known := machined.unitds[unitTag]
-			if !known {
-				delete(machined.unitds, unitTag)
-				continue
-			}
-			if unitd.serviced.exposed {
-				collector[portRange] = true
-			}
+		for _, rule := range splitIngressRules(fw.wantedIngressRules(machined)) {
+			collector[rule.String()] = rule
 		}
 	}
-	wantedPorts := []network.PortRange{}
-	for port := range collector {
-		wantedPorts = append(wantedPorts, port)
+	wantedRules := []network.IngressRule{}
+	for _, rule := range collector {
+		wantedRules = append(wantedRules, rule)
 	}
-	// Check which ports to open or to close.
-	toOpen := diffRanges(wantedPorts, initialPortRanges)
-	toClose := diffRanges(initialPortRanges, wantedPorts)
-	if len(toOpen) > 0 {
-		logger.Infof("opening global ports %v", toOpen)
-		if err := fw.environ.OpenPorts(toOpen); err != nil {
+	initialRules = splitIngressRules(initialRules)
+	// Check which rules to open or to close.
+	toOpen := diffRules(wantedRules, initialRules)
+	toClose := diffRules(initialRules, wantedRules)
+	if len(toClose) > 0 {
+		logger.Infof("closing global ingress rules %v", toClose)
+		if err := fw.closeEnvironIngressRules(toClose); err != nil {
 			return err
 		}
-		network.SortPortRanges(toOpen)
+		network.SortIngressRules(toClose)
 	}
-	if len(toClose) > 0 {
-		logger.Infof("closing global ports %v", toClose)
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+	if len(toOpen) > 0 {
+		logger.Infof("opening global ingress rules %v", toOpen)
+		if err := fw.openEnvironIngressRules(toOpen); err != nil {
 			return err
 		}
-		network.SortPortRanges(toClose)
+		network.SortIngressRules(toOpen)
 	}
 	return nil
 }
@@ -381,16 +460,13 @@ func (fw *Firewaller) reconcileGlobal() error {
 // opens and closes the appropriate ports for each instance.
 func (fw *Firewaller) reconcileInstances() error {
 	for _, machined := range fw.machineds {
-		m, err := machined.machine()
+		instanceId, err := machined.getInstanceId()
 		if params.IsCodeNotFound(err) {
 			if err := fw.forgetMachine(machined); err != nil {
 				return err
 			}
 			continue
-		} else if err != nil {
-			return err
 		}
-		instanceId, err := m.InstanceId()
 		if errors.IsNotProvisioned(err) {
 			logger.Warningf("Machine not yet provisioned: %v", err)
 			continue
@@ -405,32 +481,75 @@ func (fw *Firewaller) reconcileInstances() error {
 			return err
 		}
 		machineId := machined.tag.Id()
-		initialPortRanges, err := instances[0].Ports(machineId)
+		initialRules, err := instanceIngressRules(instances[0], machineId)
 		if err != nil {
 			return err
 		}
-
-		// Check which ports to open or to close.
-		toOpen := diffRanges(machined.openedPorts, initialPortRanges)
-		toClose := diffRanges(initialPortRanges, machined.openedPorts)
-		if len(toOpen) > 0 {
-			logger.Infof("opening instance port ranges %v for %q",
-				toOpen, machined.tag)
-			if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
-				// TODO(mue) Add local retry logic.
+		initialRules = splitIngressRules(initialRules)
+		wantedRules := machined.ingressRules
+		if !fw.supportsSubnetIngressRules {
+			wantedRules, err = instanceSubnetIngressRules(instances[0], wantedRules)
+			if err != nil {
 				return err
 			}
-			network.SortPortRanges(toOpen)
 		}
+
+		// Check which rules to open or to close.
+		toOpen := diffRules(wantedRules, initialRules)
+		toClose := diffRules(initialRules, wantedRules)
 		if len(toClose) > 0 {
-			logger.Infof("closing instance port ranges %v for %q",
+			logger.Infof("closing instance ingress rules %v for %q",
 				toClose, machined.tag)
-			if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+			if err := closeInstanceIngressRules(instances[0], machineId, toClose); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
-			network.SortPortRanges(toClose)
+			network.SortIngressRules(toClose)
+		}
+		if len(toOpen) > 0 {
+			logger.Infof("opening instance ingress rules %v for %q",
+				toOpen, machined.tag)
+			if err := openInstanceIngressRules(instances[0], machineId, toOpen); err != nil {
+				// TODO(mue) Add local retry logic.
+				return err
+			}
+			network.SortIngressRules(toOpen)
+		}
+	}
+	return nil
+}
+
+// reconcileLoadBalancers compares the load balancers of the model with
+// those wanted by the services of the initially started watchers, and
+// creates, updates and removes load balancers accordingly. Load
+// balancers left behind by services that have since been removed are
+// removed too.
+func (fw *Firewaller) reconcileLoadBalancers() error {
+	if fw.loadBalancers == nil {
+		return nil
+	}
+	wanted := make(map[string]bool)
+	for _, serviced := range fw.serviceds {
+		if err := fw.flushLoadBalancer(serviced); err != nil {
+			return errors.Annotatef(err, "cannot update load balancer for %q", serviced.service.Tag())
+		}
+		if serviced.loadBalancer != nil {
+			wanted[fw.loadBalancerName(serviced)] = true
+		}
+	}
+	names, err := fw.loadBalancers.LoadBalancerNames()
+	if err != nil {
+		return errors.Annotate(err, "cannot list load balancers")
+	}
+	prefix := fw.loadBalancerPrefix()
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || wanted[name] {
+			continue
 		}
+		if err := fw.loadBalancers.RemoveLoadBalancer(name); err != nil {
+			return errors.Trace(err)
+		}
+		logger.Infof("removed orphaned load balancer %q", name)
 	}
 	return nil
 }
@@ -476,6 +595,32 @@ func (fw *Firewaller) unitsChanged(change *unitsChange) error {
 	return nil
 }
 
+// subnetsChanged refreshes the CIDRs of the subnets that the endpoints
+// of each service are bound to, and opens and closes ports for the
+// units of the services whose CIDRs changed.
+func (fw *Firewaller) subnetsChanged() error {
+	unitds := []*unitData{}
+	for _, serviced := range fw.serviceds {
+		boundSubnetCIDRs, err := serviced.service.BoundSubnetCIDRs()
+		if params.IsCodeNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if stringsEqual(boundSubnetCIDRs, serviced.boundSubnetCIDRs) {
+			continue
+		}
+		serviced.boundSubnetCIDRs = boundSubnetCIDRs
+		for _, unitd := range serviced.unitds {
+			unitds = append(unitds, unitd)
+		}
+	}
+	if err := fw.flushUnits(unitds); err != nil {
+		return errors.Annotate(err, "cannot change firewall ports")
+	}
+	return nil
+}
+
 // openedPortsChanged handles port change notifications
 func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, networkTag names.NetworkTag) error {
 
@@ -534,7 +679,8 @@ func portMapsEqual(a, b map[network.PortRange]names.UnitTag) bool {
 	return true
 }
 
-// flushUnits opens and closes ports for the passed unit data.
+// flushUnits opens and closes ports for the passed unit data, and
+// updates the load balancers of their services.
 func (fw *Firewaller) flushUnits(unitds []*unitData) error {
 	machineds := map[names.MachineTag]*machineData{}
 	for _, unitd := range unitds {
@@ -545,73 +691,243 @@ func (fw *Firewaller) flushUnits(unitds []*unitData) error {
 			return err
 		}
 	}
-	return nil
+	return fw.flushLoadBalancers(unitds)
 }
 
-// flushMachine opens and closes ports for the passed machine.
+// flushMachine opens and closes ports for the passed machine, and
+// updates the load balancers of the services of its units.
 func (fw *Firewaller) flushMachine(machined *machineData) error {
-	// Gather ports to open and close.
-	want := []network.PortRange{}
+	want := splitIngressRules(fw.wantedIngressRules(machined))
+	toOpen := diffRules(want, machined.ingressRules)
+	toClose := diffRules(machined.ingressRules, want)
+	machined.ingressRules = want
+	var err error
+	if fw.globalMode {
+		err = fw.flushGlobalRules(toOpen, toClose)
+	} else {
+		err = fw.flushInstanceRules(machined, toOpen, toClose)
+	}
+	if err != nil {
+		return err
+	}
+	unitds := []*unitData{}
+	for _, unitd := range machined.unitds {
+		unitds = append(unitds, unitd)
+	}
+	return fw.flushLoadBalancers(unitds)
+}
+
+// flushLoadBalancers updates the load balancers of the services of
+// the passed unit data.
+func (fw *Firewaller) flushLoadBalancers(unitds []*unitData) error {
+	serviceds := map[names.ServiceTag]*serviceData{}
+	for _, unitd := range unitds {
+		serviceds[unitd.serviced.service.Tag()] = unitd.serviced
+	}
+	for _, serviced := range serviceds {
+		if err := fw.flushLoadBalancer(serviced); err != nil {
+			return errors.Annotatef(err, "cannot update load balancer for %q", serviced.service.Tag())
+		}
+	}
+	return nil
+}
+
+// flushLoadBalancer creates, updates or removes the load balancer of
+// the passed service, so that a service exposed through a load
+// balancer has one forwarding the ports opened by its units to the
+// instances they are deployed to. A load balancer is only kept while
+// at least one of the service's units is on a provisioned machine.
+func (fw *Firewaller) flushLoadBalancer(serviced *serviceData) error {
+	var want *loadBalancerSpec
+	if serviced.exposed && serviced.loadBalanced {
+		if fw.loadBalancers == nil {
+			if !serviced.loadBalancerWarned {
+				logger.Warningf("not creating load balancer for %q: provider does not support load balancers", serviced.service.Tag())
+				serviced.loadBalancerWarned = true
+			}
+			return nil
+		}
+		spec, err := fw.loadBalancerSpec(serviced)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(spec.instanceIds) > 0 {
+			want = spec
+		}
+	}
+	name := fw.loadBalancerName(serviced)
+	if want == nil {
+		if serviced.loadBalancerAddress == "" {
+			return nil
+		}
+		if fw.loadBalancers != nil {
+			if err := fw.loadBalancers.RemoveLoadBalancer(name); err != nil {
+				return errors.Trace(err)
+			}
+			logger.Infof("removed load balancer %q", name)
+		}
+		err := serviced.service.SetLoadBalancerAddress("")
+		if err != nil && !params.IsCodeNotFound(err) {
+			return errors.Trace(err)
+		}
+		serviced.loadBalancerAddress = ""
+		serviced.loadBalancer = nil
+		return nil
+	}
+	if serviced.loadBalancer != nil && serviced.loadBalancer.equal(want) {
+		return nil
+	}
+	address, err := fw.loadBalancers.EnsureLoadBalancer(name, want.ports, want.sourceCIDRs, want.instanceIds)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("load balancer %q at %v forwards %v to %v", name, address, want.ports, want.instanceIds)
+	serviced.loadBalancer = want
+	if address.Value != serviced.loadBalancerAddress {
+		if err := serviced.service.SetLoadBalancerAddress(address.Value); err != nil {
+			return errors.Trace(err)
+		}
+		serviced.loadBalancerAddress = address.Value
+	}
+	return nil
+}
+
+// loadBalancerName returns the name of the load balancer of the passed
+// service, which is unique to the model.
+func (fw *Firewaller) loadBalancerName(serviced *serviceData) string {
+	return fw.loadBalancerPrefix() + serviced.service.Name()
+}
+
+// loadBalancerPrefix returns the prefix of the names of the model's
+// load balancers.
+func (fw *Firewaller) loadBalancerPrefix() string {
+	uuid, _ := fw.environ.Config().UUID()
+	return fmt.Sprintf("juju-%s-", uuid)
+}
+
+// loadBalancerSpec returns the ports opened by the units of the passed
+// service and the ids of the instances they are deployed to, sorted.
+func (fw *Firewaller) loadBalancerSpec(serviced *serviceData) (*loadBalancerSpec, error) {
+	spec := &loadBalancerSpec{}
+	ports := make(map[network.PortRange]bool)
+	instanceIds := make(map[instance.Id]bool)
+	for unitTag, unitd := range serviced.unitds {
+		for portRange, portUnitTag := range unitd.machined.definedPorts {
+			if portUnitTag == unitTag {
+				ports[portRange] = true
+			}
+		}
+		instanceId, err := unitd.machined.getInstanceId()
+		if params.IsCodeNotFound(err) || params.IsCodeNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		instanceIds[instanceId] = true
+	}
+	for portRange := range ports {
+		spec.ports = append(spec.ports, portRange)
+	}
+	network.SortPortRanges(spec.ports)
+	for instanceId := range instanceIds {
+		spec.instanceIds = append(spec.instanceIds, instanceId)
+	}
+	sort.Sort(instanceIdSlice(spec.instanceIds))
+	spec.sourceCIDRs = serviced.exposedCIDRs
+	return spec, nil
+}
+
+// wantedIngressRules returns the ingress rules that should be open
+// for the passed machine: one for each port range opened by a unit
+// of an exposed service, restricted to the CIDRs that the service
+// is exposed to and, unless the firewall is global, to the subnets
+// of the spaces the service's endpoints are bound to.
+func (fw *Firewaller) wantedIngressRules(machined *machineData) []network.IngressRule {
+	want := []network.IngressRule{}
 	for portRange, unitTag := range machined.definedPorts {
 		unitd, known := machined.unitds[unitTag]
 		if !known {
 			delete(machined.unitds, unitTag)
 			continue
 		}
-		if unitd.serviced.exposed {
-			want = append(want, portRange)
+		serviced := unitd.serviced
+		if !serviced.exposed {
+			continue
 		}
+		if len(serviced.exposedCIDRs) > 0 && !fw.supportsIngressRules {
+			// Opening the ports to all addresses would defeat the
+			// purpose of restricting the service, so leave them
+			// closed instead.
+			logger.Warningf(
+				"not opening port range %v for %q: firewall does not support restricting access to %v",
+				portRange, unitTag, serviced.exposedCIDRs,
+			)
+			continue
+		}
+		rule := network.IngressRule{
+			PortRange:   portRange,
+			SourceCIDRs: serviced.exposedCIDRs,
+		}
+		if len(serviced.boundSubnetCIDRs) > 0 {
+			if fw.globalMode {
+				logger.Warningf(
+					"opening port range %v for %q on all subnets: global firewall does not support restricting access to %v",
+					portRange, unitTag, serviced.boundSubnetCIDRs,
+				)
+			} else {
+				rule.DestinationCIDRs = serviced.boundSubnetCIDRs
+			}
+		}
+		want = append(want, rule)
 	}
-	toOpen := diffRanges(want, machined.openedPorts)
-	toClose := diffRanges(machined.openedPorts, want)
-	machined.openedPorts = want
-	if fw.globalMode {
-		return fw.flushGlobalPorts(toOpen, toClose)
-	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	return want
 }
 
-// flushGlobalPorts opens and closes global ports in the environment.
-// It keeps a reference count for ports so that only 0-to-1 and 1-to-0 events
-// modify the environment.
-func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) error {
-	// Filter which ports are really to open or close.
-	var toOpen, toClose []network.PortRange
-	for _, portRange := range rawOpen {
-		if fw.globalPortRef[portRange] == 0 {
-			toOpen = append(toOpen, portRange)
+// flushGlobalRules opens and closes global ingress rules in the
+// environment. It keeps a reference count for rules so that only
+// 0-to-1 and 1-to-0 events modify the environment.
+func (fw *Firewaller) flushGlobalRules(rawOpen, rawClose []network.IngressRule) error {
+	// Filter which rules are really to open or close.
+	var toOpen, toClose []network.IngressRule
+	rawOpen, rawClose = splitIngressRules(rawOpen), splitIngressRules(rawClose)
+	for _, rule := range rawOpen {
+		key := rule.String()
+		if fw.globalRuleRef[key] == 0 {
+			toOpen = append(toOpen, rule)
 		}
-		fw.globalPortRef[portRange]++
-	}
-	for _, portRange := range rawClose {
-		fw.globalPortRef[portRange]--
-		if fw.globalPortRef[portRange] == 0 {
-			toClose = append(toClose, portRange)
-			delete(fw.globalPortRef, portRange)
+		fw.globalRuleRef[key]++
+	}
+	for _, rule := range rawClose {
+		key := rule.String()
+		fw.globalRuleRef[key]--
+		if fw.globalRuleRef[key] == 0 {
+			toClose = append(toClose, rule)
+			delete(fw.globalRuleRef, key)
 		}
 	}
-	// Open and close the ports.
-	if len(toOpen) > 0 {
-		if err := fw.environ.OpenPorts(toOpen); err != nil {
+	// Close and open the rules. Rules are closed first, so that a
+	// rule whose source CIDRs change is never duplicated.
+	if len(toClose) > 0 {
+		if err := fw.closeEnvironIngressRules(toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toOpen)
-		logger.Infof("opened port ranges %v in environment", toOpen)
+		network.SortIngressRules(toClose)
+		logger.Infof("closed ingress rules %v in environment", toClose)
 	}
-	if len(toClose) > 0 {
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+	if len(toOpen) > 0 {
+		if err := fw.openEnvironIngressRules(toOpen); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
-		logger.Infof("closed port ranges %v in environment", toClose)
+		network.SortIngressRules(toOpen)
+		logger.Infof("opened ingress rules %v in environment", toOpen)
 	}
 	return nil
 }
 
-// flushInstancePorts opens and closes ports global on the machine.
-func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []network.PortRange) error {
+// flushInstanceRules opens and closes ingress rules on the machine.
+func (fw *Firewaller) flushInstanceRules(machined *machineData, toOpen, toClose []network.IngressRule) error {
 	// If there's nothing to do, do nothing.
 	// This is important because when a machine is first created,
 	// it will have no instance id but also no open ports -
@@ -619,7 +935,7 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 	if len(toOpen) == 0 && len(toClose) == 0 {
 		return nil
 	}
-	m, err := machined.machine()
+	instanceId, err := machined.getInstanceId()
 	if params.IsCodeNotFound(err) {
 		return nil
 	}
@@ -627,34 +943,168 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 		return err
 	}
 	machineId := machined.tag.Id()
-	instanceId, err := m.InstanceId()
-	if err != nil {
-		return err
-	}
 	instances, err := fw.environ.Instances([]instance.Id{instanceId})
 	if err != nil {
 		return err
 	}
-	// Open and close the ports.
-	if len(toOpen) > 0 {
-		if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
-			// TODO(mue) Add local retry logic.
+	if !fw.supportsSubnetIngressRules {
+		if toOpen, err = instanceSubnetIngressRules(instances[0], toOpen); err != nil {
 			return err
 		}
-		network.SortPortRanges(toOpen)
-		logger.Infof("opened port ranges %v on %q", toOpen, machined.tag)
+		if toClose, err = instanceSubnetIngressRules(instances[0], toClose); err != nil {
+			return err
+		}
+		// A rule whose subnets change may be applied unchanged.
+		toOpen, toClose = diffRules(toOpen, toClose), diffRules(toClose, toOpen)
 	}
+	// Close and open the rules. Rules are closed first, so that a
+	// rule whose source CIDRs change is never duplicated.
 	if len(toClose) > 0 {
-		if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+		if err := closeInstanceIngressRules(instances[0], machineId, toClose); err != nil {
+			// TODO(mue) Add local retry logic.
+			return err
+		}
+		network.SortIngressRules(toClose)
+		logger.Infof("closed ingress rules %v on %q", toClose, machined.tag)
+	}
+	if len(toOpen) > 0 {
+		if err := openInstanceIngressRules(instances[0], machineId, toOpen); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
-		logger.Infof("closed port ranges %v on %q", toClose, machined.tag)
+		network.SortIngressRules(toOpen)
+		logger.Infof("opened ingress rules %v on %q", toOpen, machined.tag)
 	}
 	return nil
 }
 
+// applyEgressPolicy applies the egress policy defined by the model
+// config to the environ's firewall, if it has changed.
+func (fw *Firewaller) applyEgressPolicy(cfg *config.Config) error {
+	policy := cfg.EgressPolicy()
+	if policy.Equal(fw.egressPolicy) {
+		return nil
+	}
+	env, ok := fw.environ.(environs.EgressFirewaller)
+	if !ok {
+		logger.Warningf("egress policy %q cannot be enforced: provider does not support egress firewalling", policy)
+		fw.egressPolicy = policy
+		return nil
+	}
+	if err := env.SetEgressPolicy(policy); err != nil {
+		return errors.Annotate(err, "cannot set egress policy")
+	}
+	logger.Infof("set egress policy %q", policy)
+	fw.egressPolicy = policy
+	return nil
+}
+
+// environIngressRules returns the ingress rules opened for the whole
+// environment. If the environ does not support ingress rules, a rule
+// allowing access from anywhere is returned for each open port range.
+func (fw *Firewaller) environIngressRules() ([]network.IngressRule, error) {
+	if env, ok := fw.environ.(environs.IngressRuleFirewaller); ok {
+		return env.IngressRules()
+	}
+	portRanges, err := fw.environ.Ports()
+	if err != nil {
+		return nil, err
+	}
+	return network.IngressRulesFromPortRanges(portRanges), nil
+}
+
+func (fw *Firewaller) openEnvironIngressRules(rules []network.IngressRule) error {
+	if env, ok := fw.environ.(environs.IngressRuleFirewaller); ok {
+		return env.OpenIngressRules(rules)
+	}
+	return fw.environ.OpenPorts(network.IngressRulePortRanges(rules))
+}
+
+func (fw *Firewaller) closeEnvironIngressRules(rules []network.IngressRule) error {
+	if env, ok := fw.environ.(environs.IngressRuleFirewaller); ok {
+		return env.CloseIngressRules(rules)
+	}
+	return fw.environ.ClosePorts(network.IngressRulePortRanges(rules))
+}
+
+// instanceIngressRules returns the ingress rules opened on the
+// instance. If the instance does not support ingress rules, a rule
+// allowing access from anywhere is returned for each open port range.
+func instanceIngressRules(inst instance.Instance, machineId string) ([]network.IngressRule, error) {
+	if fwInst, ok := inst.(instance.IngressRuleFirewaller); ok {
+		return fwInst.IngressRules(machineId)
+	}
+	portRanges, err := inst.Ports(machineId)
+	if err != nil {
+		return nil, err
+	}
+	return network.IngressRulesFromPortRanges(portRanges), nil
+}
+
+// instanceSubnetIngressRules returns the passed rules as they are
+// applied to an instance that cannot restrict ingress rules to its
+// interfaces on particular subnets. A rule restricted to particular
+// subnets is applied to all of the instance's interfaces if it has an
+// address in one of the subnets, and is not applied otherwise, so that
+// ports are never opened on instances outside the bound subnets.
+func instanceSubnetIngressRules(inst instance.Instance, rules []network.IngressRule) ([]network.IngressRule, error) {
+	var result []network.IngressRule
+	var addresses []network.Address
+	var haveAddresses bool
+	for _, rule := range rules {
+		if len(rule.DestinationCIDRs) == 0 {
+			result = append(result, rule)
+			continue
+		}
+		if !haveAddresses {
+			var err error
+			if addresses, err = inst.Addresses(); err != nil {
+				return nil, errors.Annotatef(err, "cannot get addresses of instance %q", inst.Id())
+			}
+			haveAddresses = true
+		}
+		if !addressInCIDRs(addresses, rule.DestinationCIDRs) {
+			continue
+		}
+		result = append(result, network.IngressRule{
+			PortRange:   rule.PortRange,
+			SourceCIDRs: rule.SourceCIDRs,
+		})
+	}
+	return result, nil
+}
+
+// addressInCIDRs reports whether any of the addresses is in any of
+// the CIDRs.
+func addressInCIDRs(addresses []network.Address, cidrs []string) bool {
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		for _, address := range addresses {
+			if ip := net.ParseIP(address.Value); ip != nil && ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func openInstanceIngressRules(inst instance.Instance, machineId string, rules []network.IngressRule) error {
+	if fwInst, ok := inst.(instance.IngressRuleFirewaller); ok {
+		return fwInst.OpenIngressRules(machineId, rules)
+	}
+	return inst.OpenPorts(machineId, network.IngressRulePortRanges(rules))
+}
+
+func closeInstanceIngressRules(inst instance.Instance, machineId string, rules []network.IngressRule) error {
+	if fwInst, ok := inst.(instance.IngressRuleFirewaller); ok {
+		return fwInst.CloseIngressRules(machineId, rules)
+	}
+	return inst.ClosePorts(machineId, network.IngressRulePortRanges(rules))
+}
+
 // machineLifeChanged starts watching new machines when the firewaller
 // is starting, or when new machines come to life, and stops watching
 // machines that are dying.
@@ -681,12 +1131,17 @@ func (fw *Firewaller) machineLifeChanged(tag names.MachineTag) error {
 
 // forgetMachine cleans the machine data after the machine is removed.
 func (fw *Firewaller) forgetMachine(machined *machineData) error {
+	unitds := []*unitData{}
 	for _, unitd := range machined.unitds {
 		fw.forgetUnit(unitd)
+		unitds = append(unitds, unitd)
 	}
 	if err := fw.flushMachine(machined); err != nil {
 		return errors.Trace(err)
 	}
+	if err := fw.flushLoadBalancers(unitds); err != nil {
+		return errors.Trace(err)
+	}
 
 	// Unusually, it's fine to ignore this error, because we know the machined
 	// is being tracked in fw.catacomb. But we do still want to wait until the
@@ -745,19 +1200,42 @@ type unitsChange struct {
 
 // machineData holds machine details and watches units added or removed.
 type machineData struct {
-	catacomb    catacomb.Catacomb
-	fw          *Firewaller
-	tag         names.MachineTag
-	unitds      map[names.UnitTag]*unitData
-	openedPorts []network.PortRange
+	catacomb catacomb.Catacomb
+	fw       *Firewaller
+	tag      names.MachineTag
+	unitds   map[names.UnitTag]*unitData
+	// ingress rules opened for this machine
+	ingressRules []network.IngressRule
 	// ports defined by units on this machine
 	definedPorts map[network.PortRange]names.UnitTag
+	// instanceId holds the id of the machine's instance once it is
+	// known; it does not change after the machine is provisioned.
+	instanceId instance.Id
 }
 
 func (md *machineData) machine() (*firewaller.Machine, error) {
 	return md.fw.st.Machine(md.tag)
 }
 
+// getInstanceId returns the id of the machine's instance. The id is
+// only requested from the API until the machine is provisioned, and
+// is cached from then on.
+func (md *machineData) getInstanceId() (instance.Id, error) {
+	if md.instanceId != "" {
+		return md.instanceId, nil
+	}
+	m, err := md.machine()
+	if err != nil {
+		return "", err
+	}
+	instanceId, err := m.InstanceId()
+	if err != nil {
+		return "", err
+	}
+	md.instanceId = instanceId
+	return instanceId, nil
+}
+
 // watchLoop watches the machine for units added or removed.
 func (md *machineData) watchLoop(unitw watcher.StringsWatcher) error {
 	if err := md.catacomb.Add(unitw); err != nil {
@@ -799,23 +1277,73 @@ type unitData struct {
 	machined *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific service.
+// exposedChange contains the changed exposed and load balanced flags
+// and CIDRs for one specific service.
 type exposedChange struct {
-	serviced *serviceData
-	exposed  bool
+	serviced         *serviceData
+	exposed          bool
+	loadBalanced     bool
+	exposedCIDRs     []string
+	boundSubnetCIDRs []string
 }
 
 // serviceData holds service details and watches exposure changes.
 type serviceData struct {
-	catacomb catacomb.Catacomb
-	fw       *Firewaller
-	service  *firewaller.Service
-	exposed  bool
-	unitds   map[names.UnitTag]*unitData
+	catacomb         catacomb.Catacomb
+	fw               *Firewaller
+	service          *firewaller.Service
+	exposed          bool
+	loadBalanced     bool
+	exposedCIDRs     []string
+	boundSubnetCIDRs []string
+	unitds           map[names.UnitTag]*unitData
+
+	// loadBalancerAddress holds the recorded address of the service's
+	// load balancer, if it has one.
+	loadBalancerAddress string
+	// loadBalancer holds the configuration last applied to the
+	// service's load balancer by this firewaller.
+	loadBalancer *loadBalancerSpec
+	// loadBalancerWarned records whether it has been logged that the
+	// provider cannot create the service's load balancer.
+	loadBalancerWarned bool
+}
+
+// loadBalancerSpec holds the configuration of a service's load
+// balancer.
+type loadBalancerSpec struct {
+	ports       []network.PortRange
+	sourceCIDRs []string
+	instanceIds []instance.Id
+}
+
+func (spec *loadBalancerSpec) equal(other *loadBalancerSpec) bool {
+	if len(spec.ports) != len(other.ports) || len(spec.instanceIds) != len(other.instanceIds) {
+		return false
+	}
+	for i, portRange := range spec.ports {
+		if portRange != other.ports[i] {
+			return false
+		}
+	}
+	for i, instanceId := range spec.instanceIds {
+		if instanceId != other.instanceIds[i] {
+			return false
+		}
+	}
+	return stringsEqual(spec.sourceCIDRs, other.sourceCIDRs)
 }
 
-// watchLoop watches the service's exposed flag for changes.
-func (sd *serviceData) watchLoop(exposed bool) error {
+type instanceIdSlice []instance.Id
+
+func (s instanceIdSlice) Len() int           { return len(s) }
+func (s instanceIdSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s instanceIdSlice) Less(i, j int) bool { return s[i] < s[j] }
+
+// watchLoop watches the service's exposed and load balanced flags and
+// CIDRs, and the subnets of the spaces its endpoints are bound to, for
+// changes.
+func (sd *serviceData) watchLoop(exposed, loadBalanced bool, exposedCIDRs, boundSubnetCIDRs []string) error {
 	serviceWatcher, err := sd.service.Watch()
 	if err != nil {
 		return errors.Trace(err)
@@ -841,13 +1369,30 @@ func (sd *serviceData) watchLoop(exposed bool) error {
 			if err != nil {
 				return errors.Trace(err)
 			}
-			if change == exposed {
+			changeLoadBalanced, err := sd.service.IsLoadBalanced()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			changeCIDRs, err := sd.service.ExposedCIDRs()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			changeBoundCIDRs, err := sd.service.BoundSubnetCIDRs()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if change == exposed && changeLoadBalanced == loadBalanced &&
+				stringsEqual(changeCIDRs, exposedCIDRs) &&
+				stringsEqual(changeBoundCIDRs, boundSubnetCIDRs) {
 				continue
 			}
 
 			exposed = change
+			loadBalanced = changeLoadBalanced
+			exposedCIDRs = changeCIDRs
+			boundSubnetCIDRs = changeBoundCIDRs
 			select {
-			case sd.fw.exposedChange <- &exposedChange{sd, change}:
+			case sd.fw.exposedChange <- &exposedChange{sd, change, changeLoadBalanced, changeCIDRs, changeBoundCIDRs}:
 			case <-sd.catacomb.Dying():
 				return sd.catacomb.ErrDying()
 			}
@@ -865,12 +1410,12 @@ func (sd *serviceData) Wait() error {
 	return sd.catacomb.Wait()
 }
 
-// diffRanges returns all the port rangess that exist in A but not B.
-func diffRanges(A, B []network.PortRange) (missing []network.PortRange) {
+// diffRules returns all the ingress rules that exist in A but not B.
+func diffRules(A, B []network.IngressRule) (missing []network.IngressRule) {
 next:
 	for _, a := range A {
 		for _, b := range B {
-			if a == b {
+			if a.Equal(b) {
 				continue next
 			}
 		}
@@ -879,6 +1424,43 @@ next:
 	return
 }
 
+// splitIngressRules returns the given rules split so that each has at
+// most one source CIDR. Global firewall rules are shared by services,
+// and so must be reference counted by source CIDR; providers such as
+// EC2 also reject a rule that overlaps an existing one, so changing a
+// service's CIDRs must only close and open the CIDRs that changed.
+func splitIngressRules(rules []network.IngressRule) []network.IngressRule {
+	var result []network.IngressRule
+	for _, rule := range rules {
+		if len(rule.SourceCIDRs) == 0 {
+			result = append(result, rule)
+			continue
+		}
+		for _, cidr := range rule.SourceCIDRs {
+			result = append(result, network.IngressRule{
+				PortRange:        rule.PortRange,
+				SourceCIDRs:      []string{cidr},
+				DestinationCIDRs: rule.DestinationCIDRs,
+			})
+		}
+	}
+	return result
+}
+
+// stringsEqual reports whether the two slices hold the same strings
+// in the same order.
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // parsePortsKey parses a ports document global key coming from the
 // ports watcher (e.g. "42:juju-public") and returns the machine and
 // network tags from its components (in the last example "machine-42"
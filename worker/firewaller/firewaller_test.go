@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/api"
 	apifirewaller "github.com/juju/juju/api/firewaller"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
@@ -88,6 +89,79 @@ func (s *firewallerBaseSuite) assertPorts(c *gc.C, inst instance.Instance, machi
 	}
 }
 
+// assertIngressRules retrieves the ingress rules of the instance and
+// compares them to the expected.
+func (s *firewallerBaseSuite) assertIngressRules(c *gc.C, inst instance.Instance, machineId string, expected []network.IngressRule) {
+	s.BackingState.StartSync()
+	start := time.Now()
+	for {
+		got, err := inst.(instance.IngressRuleFirewaller).IngressRules(machineId)
+		if err != nil {
+			c.Fatal(err)
+			return
+		}
+		network.SortIngressRules(got)
+		network.SortIngressRules(expected)
+		if reflect.DeepEqual(got, expected) {
+			c.Succeed()
+			return
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %q; got %q", expected, got)
+			return
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
+// assertEnvironIngressRules retrieves the ingress rules of the
+// environment and compares them to the expected.
+func (s *firewallerBaseSuite) assertEnvironIngressRules(c *gc.C, expected []network.IngressRule) {
+	s.BackingState.StartSync()
+	start := time.Now()
+	for {
+		got, err := s.Environ.(environs.IngressRuleFirewaller).IngressRules()
+		if err != nil {
+			c.Fatal(err)
+			return
+		}
+		network.SortIngressRules(got)
+		network.SortIngressRules(expected)
+		if reflect.DeepEqual(got, expected) {
+			c.Succeed()
+			return
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %q; got %q", expected, got)
+			return
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
+// assertEgressPolicy retrieves the egress policy applied to the
+// environment and compares it to the expected.
+func (s *firewallerBaseSuite) assertEgressPolicy(c *gc.C, expected network.EgressPolicy) {
+	s.BackingState.StartSync()
+	start := time.Now()
+	for {
+		got, err := s.Environ.(environs.EgressFirewaller).EgressPolicy()
+		if err != nil {
+			c.Fatal(err)
+			return
+		}
+		if got.Equal(expected) {
+			c.Succeed()
+			return
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %q; got %q", expected, got)
+			return
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
 // assertEnvironPorts retrieves the open ports of environment and compares them
 // to the expected.
 func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.PortRange) {
@@ -113,6 +187,41 @@ func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.Por
 	}
 }
 
+// assertLoadBalancer retrieves the load balancer of the named service
+// from the environment and compares it to the expected; a nil expected
+// value means the service should have no load balancer.
+func (s *firewallerBaseSuite) assertLoadBalancer(c *gc.C, serviceName string, expected *dummy.LoadBalancer) {
+	uuid, _ := s.Environ.Config().UUID()
+	name := "juju-" + uuid + "-" + serviceName
+	s.BackingState.StartSync()
+	start := time.Now()
+	for {
+		lbs, err := dummy.LoadBalancers(s.Environ)
+		if err != nil {
+			c.Fatal(err)
+			return
+		}
+		got, ok := lbs[name]
+		if expected == nil && !ok {
+			c.Succeed()
+			return
+		}
+		if expected != nil && ok {
+			expected.Name = name
+			expected.Address = got.Address
+			if reflect.DeepEqual(&got, expected) {
+				c.Succeed()
+				return
+			}
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %+v; got %+v", expected, lbs)
+			return
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
 func (s *firewallerBaseSuite) addUnit(c *gc.C, svc *state.Service) (*state.Unit, *state.Machine) {
 	units, err := juju.AddUnits(s.State, svc, 1, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -402,6 +511,275 @@ func (s *InstanceModeSuite) TestSetClearExposedService(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestExposedServiceCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposed("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"),
+	})
+
+	// Changing the CIDRs replaces the rules, one per CIDR.
+	err = svc.SetExposed("10.0.0.0/8", "192.168.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"),
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "192.168.1.0/24"),
+	})
+
+	// Exposing without CIDRs opens the ports to all.
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}),
+	})
+
+	err = svc.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestExposedServiceCIDRsChanged(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposed("10.0.0.0/8", "172.16.0.0/12")
+	c.Assert(err, jc.ErrorIsNil)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"),
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "172.16.0.0/12"),
+	})
+
+	opc := make(chan dummy.Operation, 100)
+	dummy.Listen(opc)
+	err = svc.SetExposed("10.0.0.0/8", "192.168.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"),
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "192.168.1.0/24"),
+	})
+	dummy.Listen(nil)
+
+	// Only the CIDR that changed is closed and opened, and it is
+	// closed first, so that no rule overlaps an existing one.
+	var ops []dummy.Operation
+	for op := range opc {
+		switch op.(type) {
+		case dummy.OpOpenPorts, dummy.OpClosePorts:
+			ops = append(ops, op)
+		}
+	}
+	c.Assert(ops, gc.HasLen, 2)
+	closeOp, ok := ops[0].(dummy.OpClosePorts)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(closeOp.Rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "172.16.0.0/12"),
+	})
+	openOp, ok := ops[1].(dummy.OpOpenPorts)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(openOp.Rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "192.168.1.0/24"),
+	})
+}
+
+func (s *InstanceModeSuite) TestBoundServiceSubnets(c *gc.C) {
+	for _, cidr := range []string{"10.0.0.0/24", "192.0.2.0/24"} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := s.State.AddSpace("internal", "", []string{"10.0.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("public", "", []string{"192.0.2.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	// All the endpoints of the service are bound to the internal
+	// space, so its ports are only opened on that space's subnets.
+	svc := s.AddTestingServiceWithBindings(c, "wordpress", s.AddTestingCharm(c, "wordpress"), map[string]string{
+		"url":             "internal",
+		"db":              "internal",
+		"cache":           "internal",
+		"logging-dir":     "internal",
+		"monitoring-port": "internal",
+	})
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{{
+		PortRange:        network.PortRange{80, 80, "tcp"},
+		DestinationCIDRs: []string{"10.0.0.0/24"},
+	}})
+
+	// Adding a subnet to the space opens the ports on it too.
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24", SpaceName: "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{{
+		PortRange:        network.PortRange{80, 80, "tcp"},
+		DestinationCIDRs: []string{"10.0.0.0/24", "10.0.1.0/24"},
+	}})
+
+	// A service with unbound endpoints has its ports opened on all
+	// subnets.
+	svc2 := s.AddTestingServiceWithBindings(c, "mysql", s.AddTestingCharm(c, "wordpress"), map[string]string{
+		"url": "public",
+	})
+	err = svc2.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u2, m2 := s.addUnit(c, svc2)
+	inst2 := s.startInstance(c, m2)
+	err = u2.OpenPort("tcp", 3306)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertIngressRules(c, inst2, m2.Id(), []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{3306, 3306, "tcp"}),
+	})
+}
+
+func (s *InstanceModeSuite) TestLoadBalancedService(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposedLoadBalanced("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	u1, m1 := s.addUnit(c, svc)
+	inst1 := s.startInstance(c, m1)
+	err = u1.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertLoadBalancer(c, "wordpress", &dummy.LoadBalancer{
+		Ports:       []network.PortRange{{80, 80, "tcp"}},
+		SourceCIDRs: []string{"10.0.0.0/8"},
+		InstanceIds: []instance.Id{inst1.Id()},
+	})
+
+	// The load balancer's address is recorded against the service.
+	lbs, err := dummy.LoadBalancers(s.Environ)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lbs, gc.HasLen, 1)
+	var address string
+	for _, lb := range lbs {
+		address = lb.Address.Value
+	}
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := svc.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		if svc.LoadBalancerAddress() == address {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("load balancer address not recorded: expected %q, got %q", address, svc.LoadBalancerAddress())
+		}
+	}
+
+	// New units become members of the load balancer.
+	u2, m2 := s.addUnit(c, svc)
+	inst2 := s.startInstance(c, m2)
+	err = u2.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	expectedIds := []instance.Id{inst1.Id(), inst2.Id()}
+	if expectedIds[0] > expectedIds[1] {
+		expectedIds[0], expectedIds[1] = expectedIds[1], expectedIds[0]
+	}
+	s.assertLoadBalancer(c, "wordpress", &dummy.LoadBalancer{
+		Ports:       []network.PortRange{{80, 80, "tcp"}},
+		SourceCIDRs: []string{"10.0.0.0/8"},
+		InstanceIds: expectedIds,
+	})
+
+	// Unexposing the service removes the load balancer.
+	err = svc.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertLoadBalancer(c, "wordpress", nil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := svc.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		if svc.LoadBalancerAddress() == "" {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("load balancer address not cleared")
+		}
+	}
+}
+
+func (s *InstanceModeSuite) TestOrphanedLoadBalancersRemoved(c *gc.C) {
+	// The model's load balancer of a service that no longer exists is
+	// removed at startup; load balancers of other models are kept.
+	lbManager := s.Environ.(environs.LoadBalancerManager)
+	uuid, _ := s.Environ.Config().UUID()
+	ports := []network.PortRange{{3306, 3306, "tcp"}}
+	_, err := lbManager.EnsureLoadBalancer("juju-"+uuid+"-mysql", ports, nil, []instance.Id{"inst-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = lbManager.EnsureLoadBalancer("other-mysql", ports, nil, []instance.Id{"inst-0"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	s.assertLoadBalancer(c, "mysql", nil)
+	lbs, err := dummy.LoadBalancers(s.Environ)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lbs, gc.HasLen, 1)
+	_, ok := lbs["other-mysql"]
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *InstanceModeSuite) TestEgressPolicy(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+	s.assertEgressPolicy(c, network.EgressPolicy{})
+
+	err = s.State.UpdateModelConfig(map[string]interface{}{
+		"egress-policy":  "deny",
+		"egress-allowed": "10.0.0.0/8,0.0.0.0/0:443/tcp",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEgressPolicy(c, network.EgressPolicy{
+		DefaultDeny: true,
+		Rules: []network.EgressRule{{
+			DestinationCIDR: "10.0.0.0/8",
+		}, {
+			DestinationCIDR: "0.0.0.0/0",
+			PortRange:       network.MustParsePortRange("443/tcp"),
+		}},
+	})
+
+	err = s.State.UpdateModelConfig(map[string]interface{}{
+		"egress-policy": "allow",
+	}, []string{"egress-allowed"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEgressPolicy(c, network.EgressPolicy{})
+}
+
 func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, jc.ErrorIsNil)
@@ -583,7 +961,7 @@ func (s *InstanceModeSuite) TestStartWithStateOpenPortsBroken(c *gc.C) {
 
 	// Nothing open without firewaller.
 	s.assertPorts(c, inst, m.Id(), nil)
-	dummy.SetInstanceBroken(inst, "OpenPorts")
+	dummy.SetInstanceBroken(inst, "OpenIngressRules")
 
 	// Starting the firewaller should attempt to open the ports,
 	// and fail due to the method being broken.
@@ -596,7 +974,7 @@ func (s *InstanceModeSuite) TestStartWithStateOpenPortsBroken(c *gc.C) {
 	select {
 	case err := <-errc:
 		c.Assert(err, gc.ErrorMatches,
-			`cannot respond to units changes for "machine-1": dummyInstance.OpenPorts is broken`)
+			`cannot respond to units changes for "machine-1": dummyInstance.OpenIngressRules is broken`)
 	case <-time.After(coretesting.LongWait):
 		fw.Kill()
 		fw.Wait()
@@ -669,6 +1047,39 @@ func (s *GlobalModeSuite) TestGlobalMode(c *gc.C) {
 	s.assertEnvironPorts(c, nil)
 }
 
+func (s *GlobalModeSuite) TestGlobalModeCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc1 := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc1.SetExposed("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	u1, m1 := s.addUnit(c, svc1)
+	s.startInstance(c, m1)
+	err = u1.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc2 := s.AddTestingService(c, "moinmoin", s.charm)
+	err = svc2.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u2, m2 := s.addUnit(c, svc2)
+	s.startInstance(c, m2)
+	err = u2.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertEnvironIngressRules(c, []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}),
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"),
+	})
+
+	err = svc1.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironIngressRules(c, []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}),
+	})
+}
+
 func (s *GlobalModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,6 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internaldns
+
+var MachineListenAddrs = &machineListenAddrs
@@ -0,0 +1,141 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internaldns
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// ListenAddrs returns the addresses on which the DNS server of a
+// machine with the given addresses should listen: those that are
+// neither loopback nor link-local, which keeps it clear of any
+// resolver listening locally on the machine.
+func ListenAddrs(addrs []network.Address) []string {
+	var result []string
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr.Value)
+		if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+			continue
+		}
+		result = append(result, net.JoinHostPort(ip.String(), strconv.Itoa(DefaultPort)))
+	}
+	return result
+}
+
+// machineListenAddrs is called to determine the addresses the server
+// of a machine listens on; it is a variable so tests can listen on
+// addresses they are able to.
+var machineListenAddrs = ListenAddrs
+
+// MachineServer is a worker that runs a Server on the addresses of a
+// controller machine, restarting it on the new addresses whenever
+// they change.
+type MachineServer struct {
+	catacomb catacomb.Catacomb
+	machine  *state.Machine
+	zones    *Zones
+
+	mu     sync.Mutex
+	server *Server
+}
+
+// NewMachineServer returns a worker that answers DNS queries, received
+// on the addresses of the given machine, from the given zones.
+func NewMachineServer(machine *state.Machine, zones *Zones) (*MachineServer, error) {
+	ms := &MachineServer{
+		machine: machine,
+		zones:   zones,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &ms.catacomb,
+		Work: ms.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ms, nil
+}
+
+// Addrs returns the UDP addresses the server is currently listening
+// on.
+func (ms *MachineServer) Addrs() []net.Addr {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.server == nil {
+		return nil
+	}
+	return ms.server.Addrs()
+}
+
+func (ms *MachineServer) loop() error {
+	w := ms.machine.WatchAddresses()
+	if err := ms.catacomb.Add(w); err != nil {
+		return errors.Trace(err)
+	}
+	var addrs []string
+	for {
+		select {
+		case <-ms.catacomb.Dying():
+			return ms.catacomb.ErrDying()
+		case _, ok := <-w.Changes():
+			if !ok {
+				return errors.New("machine addresses watcher closed")
+			}
+		}
+		if err := ms.machine.Refresh(); err != nil {
+			return errors.Annotate(err, "cannot refresh machine")
+		}
+		newAddrs := machineListenAddrs(ms.machine.Addresses())
+		if ms.server != nil && reflect.DeepEqual(newAddrs, addrs) {
+			continue
+		}
+		logger.Infof("serving internal DNS on %v", newAddrs)
+		if err := ms.rebind(newAddrs); err != nil {
+			return errors.Trace(err)
+		}
+		addrs = newAddrs
+	}
+}
+
+// rebind stops the current server, if any, and starts a new one on the
+// given addresses.
+func (ms *MachineServer) rebind(addrs []string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.server != nil {
+		if err := worker.Stop(ms.server); err != nil {
+			return errors.Annotate(err, "cannot stop DNS server")
+		}
+		ms.server = nil
+	}
+	server, err := NewServer(addrs, ms.zones)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := ms.catacomb.Add(server); err != nil {
+		return errors.Trace(err)
+	}
+	ms.server = server
+	return nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (ms *MachineServer) Kill() {
+	ms.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (ms *MachineServer) Wait() error {
+	return ms.catacomb.Wait()
+}
@@ -0,0 +1,74 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internaldns_test
+
+import (
+	"net"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/internaldns"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type MachineServerSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&MachineServerSuite{})
+
+func (s *MachineServerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	// Listen on loopback addresses standing in for the machine's.
+	s.PatchValue(internaldns.MachineListenAddrs, func(addrs []network.Address) []string {
+		var result []string
+		for _, addr := range addrs {
+			result = append(result, strings.Replace(addr.Value, "10.", "127.", 1)+":0")
+		}
+		return result
+	})
+}
+
+func (s *MachineServerSuite) waitForAddrs(c *gc.C, server *internaldns.MachineServer, expect ...string) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		var hosts []string
+		for _, addr := range server.Addrs() {
+			host, _, err := net.SplitHostPort(addr.String())
+			c.Assert(err, jc.ErrorIsNil)
+			hosts = append(hosts, host)
+		}
+		if len(hosts) == len(expect) && (len(hosts) == 0 || hosts[0] == expect[0]) {
+			return
+		}
+	}
+	c.Fatalf("timed out waiting for server to listen on %v", expect)
+}
+
+func (s *MachineServerSuite) TestRebindsOnAddressChange(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProviderAddresses(network.NewAddress("10.0.0.1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The server is given its own copy of the machine, which it
+	// refreshes.
+	serverMachine, err := s.State.Machine(m.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	server, err := internaldns.NewMachineServer(serverMachine, internaldns.NewZones())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, server)
+	s.waitForAddrs(c, server, "127.0.0.1")
+
+	err = m.SetProviderAddresses(network.NewAddress("10.0.0.2"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitForAddrs(c, server, "127.0.0.2")
+
+	workertest.CleanKill(c, server)
+}
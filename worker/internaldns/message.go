@@ -0,0 +1,179 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internaldns
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+)
+
+// The subset of RFC 1035 needed to answer A and AAAA queries.
+const (
+	headerLen = 12
+
+	flagQR = 1 << 15
+	flagAA = 1 << 10
+	flagTC = 1 << 9
+	flagRD = 1 << 8
+
+	opcodeMask  = 0xf << 11
+	opcodeQuery = 0
+
+	rcodeFormErr  = 1
+	rcodeNXDomain = 3
+	rcodeNotImp   = 4
+	rcodeRefused  = 5
+
+	typeA    = 1
+	typeAAAA = 28
+	classIN  = 1
+
+	// maxUDPLen is the largest response sent over UDP. Responses
+	// that would be longer carry only as many answers as fit, and
+	// are marked as truncated so that clients retry over TCP.
+	maxUDPLen = 512
+
+	// maxTCPLen is the largest message sent or received over TCP,
+	// which prefixes each message with its 16-bit length.
+	maxTCPLen = 65535
+
+	// recordTTL is the time to live, in seconds, of the answers
+	// given. It is kept short, so that clients notice units coming
+	// and going.
+	recordTTL = 30
+)
+
+// question holds the single question of a DNS query.
+type question struct {
+	name   string
+	qtype  uint16
+	qclass uint16
+	// raw holds the question as it appears in the query.
+	raw []byte
+}
+
+// parseQuery parses the header and question of a DNS query. It
+// returns an error if the query is malformed.
+func parseQuery(msg []byte) (id, flags uint16, q question, err error) {
+	if len(msg) < headerLen {
+		return 0, 0, q, errors.New("message too short")
+	}
+	id = binary.BigEndian.Uint16(msg[0:])
+	flags = binary.BigEndian.Uint16(msg[2:])
+	if qdcount := binary.BigEndian.Uint16(msg[4:]); qdcount != 1 {
+		return id, flags, q, errors.Errorf("expected 1 question, got %d", qdcount)
+	}
+	var labels []string
+	offset := headerLen
+	for {
+		if offset >= len(msg) {
+			return id, flags, q, errors.New("question truncated")
+		}
+		length := int(msg[offset])
+		offset++
+		if length == 0 {
+			break
+		}
+		if length > 63 {
+			// Queries have no reason to use compression.
+			return id, flags, q, errors.New("compressed or invalid label")
+		}
+		if offset+length > len(msg) {
+			return id, flags, q, errors.New("question truncated")
+		}
+		labels = append(labels, string(msg[offset:offset+length]))
+		offset += length
+	}
+	if offset+4 > len(msg) {
+		return id, flags, q, errors.New("question truncated")
+	}
+	q.name = strings.ToLower(strings.Join(labels, "."))
+	q.qtype = binary.BigEndian.Uint16(msg[offset:])
+	q.qclass = binary.BigEndian.Uint16(msg[offset+2:])
+	q.raw = msg[headerLen : offset+4]
+	return id, flags, q, nil
+}
+
+// answer returns the response, no longer than maxLen, to the given DNS
+// query, answering it from the given zones, or nil if the message
+// should be dropped.
+func answer(msg []byte, zones *Zones, maxLen int) []byte {
+	id, flags, q, err := parseQuery(msg)
+	if len(msg) < headerLen || flags&flagQR != 0 {
+		// Not a query, or too short to answer at all.
+		return nil
+	}
+	respFlags := uint16(flagQR) | flags&flagRD
+	if err != nil {
+		logger.Debugf("malformed DNS query: %v", err)
+		return response(id, respFlags|rcodeFormErr, nil, nil, maxLen)
+	}
+	if flags&opcodeMask != opcodeQuery {
+		return response(id, respFlags|rcodeNotImp, q.raw, nil, maxLen)
+	}
+	if q.name != network.InternalDNSDomain && !strings.HasSuffix(q.name, "."+network.InternalDNSDomain) {
+		// Refusing lets the client's resolver go on to the next
+		// nameserver it is configured with.
+		return response(id, respFlags|rcodeRefused, q.raw, nil, maxLen)
+	}
+	respFlags |= flagAA
+	addrs, ok := zones.Lookup(q.name)
+	if !ok {
+		return response(id, respFlags|rcodeNXDomain, q.raw, nil, maxLen)
+	}
+	var answers [][]byte
+	if q.qclass == classIN {
+		for _, addr := range addrs {
+			if ip4 := addr.To4(); ip4 != nil && q.qtype == typeA {
+				answers = append(answers, resourceRecord(typeA, ip4))
+			} else if ip4 == nil && q.qtype == typeAAAA {
+				answers = append(answers, resourceRecord(typeAAAA, addr.To16()))
+			}
+		}
+	}
+	return response(id, respFlags, q.raw, answers, maxLen)
+}
+
+// resourceRecord returns an answer resource record, whose name refers
+// to the name of the question, with the given type and data.
+func resourceRecord(rtype uint16, data net.IP) []byte {
+	rr := make([]byte, 12+len(data))
+	// A pointer to the question name, which follows the header.
+	binary.BigEndian.PutUint16(rr[0:], 0xc000|headerLen)
+	binary.BigEndian.PutUint16(rr[2:], rtype)
+	binary.BigEndian.PutUint16(rr[4:], classIN)
+	binary.BigEndian.PutUint32(rr[6:], recordTTL)
+	binary.BigEndian.PutUint16(rr[10:], uint16(len(data)))
+	copy(rr[12:], data)
+	return rr
+}
+
+// response returns a DNS response with the given id, flags, question
+// and as many of the given answers as fit in maxLen bytes. If not all
+// of them fit, the response is marked as truncated.
+func response(id, flags uint16, rawQuestion []byte, answers [][]byte, maxLen int) []byte {
+	resp := make([]byte, headerLen, maxLen)
+	binary.BigEndian.PutUint16(resp[0:], id)
+	if rawQuestion != nil {
+		binary.BigEndian.PutUint16(resp[4:], 1)
+		resp = append(resp, rawQuestion...)
+	}
+	var ancount uint16
+	for _, rr := range answers {
+		if len(resp)+len(rr) > maxLen {
+			flags |= flagTC
+			break
+		}
+		resp = append(resp, rr...)
+		ancount++
+	}
+	binary.BigEndian.PutUint16(resp[2:], flags)
+	binary.BigEndian.PutUint16(resp[6:], ancount)
+	return resp
+}
@@ -0,0 +1,208 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package internaldns provides the workers that maintain the internal
+// DNS names of the units, services and machines in a model, as
+// "<unit>.<service>.<model>.<model-uuid>.juju",
+// "<service>.<model>.<model-uuid>.juju" and
+// "machine-<id>.<model>.<model-uuid>.juju".
+//
+// The updater, one per model, keeps the model's records in a Zones
+// shared by the models on a controller. The server, one per
+// controller machine, answers queries for the records over UDP and
+// TCP, on the machine's addresses as they change.
+package internaldns
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.internaldns")
+
+// DefaultPort is the port the DNS server listens on by default.
+const DefaultPort = 53
+
+// tcpIdleTimeout is how long a TCP connection may stay idle before
+// the server closes it.
+const tcpIdleTimeout = 10 * time.Second
+
+// Server is a worker that answers DNS queries for internal names from
+// the records held in a Zones.
+type Server struct {
+	catacomb  catacomb.Catacomb
+	zones     *Zones
+	conns     []net.PacketConn
+	listeners []net.Listener
+}
+
+// NewServer returns a worker that answers DNS queries, received on UDP
+// and TCP at each of the given addresses, from the given zones. A
+// server with no addresses serves nothing, but runs until it is
+// killed.
+func NewServer(addrs []string, zones *Zones) (*Server, error) {
+	s := &Server{zones: zones}
+	for _, addr := range addrs {
+		conn, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			s.closeConns()
+			return nil, errors.Annotatef(err, "cannot listen on %q", addr)
+		}
+		s.conns = append(s.conns, conn)
+		// Listen on the same port over TCP, for clients retrying
+		// truncated responses.
+		listener, err := net.Listen("tcp", conn.LocalAddr().String())
+		if err != nil {
+			s.closeConns()
+			return nil, errors.Annotatef(err, "cannot listen on %q", addr)
+		}
+		s.listeners = append(s.listeners, listener)
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &s.catacomb,
+		Work: s.loop,
+	})
+	if err != nil {
+		s.closeConns()
+		return nil, errors.Trace(err)
+	}
+	return s, nil
+}
+
+// Addrs returns the UDP addresses the server is listening on. It
+// listens on the same addresses over TCP.
+func (s *Server) Addrs() []net.Addr {
+	addrs := make([]net.Addr, len(s.conns))
+	for i, conn := range s.conns {
+		addrs[i] = conn.LocalAddr()
+	}
+	return addrs
+}
+
+func (s *Server) loop() error {
+	defer s.closeConns()
+	errs := make(chan error, len(s.conns)+len(s.listeners))
+	for _, conn := range s.conns {
+		go func(conn net.PacketConn) {
+			errs <- s.serveUDP(conn)
+		}(conn)
+	}
+	for _, listener := range s.listeners {
+		go func(listener net.Listener) {
+			errs <- s.serveTCP(listener)
+		}(listener)
+	}
+	select {
+	case <-s.catacomb.Dying():
+		return s.catacomb.ErrDying()
+	case err := <-errs:
+		return errors.Trace(err)
+	}
+}
+
+// serveUDP answers the queries received on the given connection until
+// it is closed.
+func (s *Server) serveUDP(conn net.PacketConn) error {
+	buf := make([]byte, maxUDPLen)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-s.catacomb.Dying():
+				return nil
+			default:
+				return errors.Annotate(err, "cannot read DNS query")
+			}
+		}
+		resp := answer(buf[:n], s.zones, maxUDPLen)
+		if resp == nil {
+			continue
+		}
+		if _, err := conn.WriteTo(resp, addr); err != nil {
+			logger.Debugf("cannot send DNS response to %v: %v", addr, err)
+		}
+	}
+}
+
+// serveTCP accepts connections on the given listener, and answers the
+// queries received on them, until it is closed.
+func (s *Server) serveTCP(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-s.catacomb.Dying():
+				return nil
+			default:
+				return errors.Annotate(err, "cannot accept DNS connection")
+			}
+		}
+		go s.serveTCPConn(conn)
+	}
+}
+
+// serveTCPConn answers the queries received on the given connection,
+// each prefixed with its length, until the client closes it, it is
+// idle for too long, or the server is killed.
+func (s *Server) serveTCPConn(conn net.Conn) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-s.catacomb.Dying():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	defer conn.Close()
+
+	buf := make([]byte, 2+maxTCPLen)
+	for {
+		if err := conn.SetDeadline(time.Now().Add(tcpIdleTimeout)); err != nil {
+			return
+		}
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return
+		}
+		n := int(binary.BigEndian.Uint16(buf))
+		if _, err := io.ReadFull(conn, buf[:n]); err != nil {
+			return
+		}
+		resp := answer(buf[:n], s.zones, maxTCPLen)
+		if resp == nil {
+			return
+		}
+		msg := make([]byte, 2, 2+len(resp))
+		binary.BigEndian.PutUint16(msg, uint16(len(resp)))
+		if _, err := conn.Write(append(msg, resp...)); err != nil {
+			logger.Debugf("cannot send DNS response to %v: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+func (s *Server) closeConns() {
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	for _, listener := range s.listeners {
+		listener.Close()
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (s *Server) Kill() {
+	s.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (s *Server) Wait() error {
+	return s.catacomb.Wait()
+}
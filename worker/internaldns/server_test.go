@@ -0,0 +1,216 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internaldns_test
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/internaldns"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type ServerSuite struct {
+	coretesting.BaseSuite
+	zones  *internaldns.Zones
+	server *internaldns.Server
+}
+
+var _ = gc.Suite(&ServerSuite{})
+
+func (s *ServerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.zones = internaldns.NewZones()
+	s.zones.SetRecords("uuid", []network.DNSRecord{
+		{Name: "wordpress.admin.uuid.juju", Addresses: []string{"10.0.0.1", "10.0.0.2", "fd00::1"}},
+		{Name: "machine-0.admin.uuid.juju", Addresses: []string{"10.0.0.1"}},
+	})
+	server, err := internaldns.NewServer([]string{"127.0.0.1:0"}, s.zones)
+	c.Assert(err, jc.ErrorIsNil)
+	s.server = server
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, server) })
+}
+
+// queryMsg returns a query for the given name and type.
+func queryMsg(name string, qtype uint16) []byte {
+	msg := []byte{0x12, 0x34, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0}
+	for _, label := range splitLabels(name) {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	return append(msg, 0, byte(qtype>>8), byte(qtype), 0, 1)
+}
+
+// query sends a query for the given name and type to the server over
+// UDP, and returns the response code and the answered addresses.
+func (s *ServerSuite) query(c *gc.C, name string, qtype uint16) (int, []net.IP) {
+	rcode, truncated, addrs := s.queryUDP(c, name, qtype)
+	c.Assert(truncated, jc.IsFalse)
+	return rcode, addrs
+}
+
+// queryUDP sends a query for the given name and type to the server
+// over UDP, and returns the response code, whether the response was
+// truncated, and the answered addresses.
+func (s *ServerSuite) queryUDP(c *gc.C, name string, qtype uint16) (int, bool, []net.IP) {
+	msg := queryMsg(name, qtype)
+	conn, err := net.Dial("udp", s.server.Addrs()[0].String())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	_, err = conn.Write(msg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = conn.SetReadDeadline(time.Now().Add(coretesting.LongWait))
+	c.Assert(err, jc.ErrorIsNil)
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	c.Assert(err, jc.ErrorIsNil)
+	return parseResponse(c, msg, buf[:n])
+}
+
+// queryTCP sends a query for the given name and type to the server
+// over TCP, and returns the response code, whether the response was
+// truncated, and the answered addresses.
+func (s *ServerSuite) queryTCP(c *gc.C, name string, qtype uint16) (int, bool, []net.IP) {
+	msg := queryMsg(name, qtype)
+	conn, err := net.Dial("tcp", s.server.Addrs()[0].String())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	_, err = conn.Write(append([]byte{byte(len(msg) >> 8), byte(len(msg))}, msg...))
+	c.Assert(err, jc.ErrorIsNil)
+	err = conn.SetReadDeadline(time.Now().Add(coretesting.LongWait))
+	c.Assert(err, jc.ErrorIsNil)
+	var length [2]byte
+	_, err = io.ReadFull(conn, length[:])
+	c.Assert(err, jc.ErrorIsNil)
+	resp := make([]byte, int(length[0])<<8|int(length[1]))
+	_, err = io.ReadFull(conn, resp)
+	c.Assert(err, jc.ErrorIsNil)
+	return parseResponse(c, msg, resp)
+}
+
+func parseResponse(c *gc.C, msg, resp []byte) (int, bool, []net.IP) {
+	c.Assert(resp[0:2], jc.DeepEquals, msg[0:2])
+	c.Assert(resp[2]&0x80, gc.Not(gc.Equals), byte(0))
+	truncated := resp[2]&0x02 != 0
+	rcode := int(resp[3] & 0xf)
+	ancount := int(resp[6])<<8 | int(resp[7])
+	offset := len(msg)
+	var addrs []net.IP
+	for i := 0; i < ancount; i++ {
+		rdlen := int(resp[offset+10])<<8 | int(resp[offset+11])
+		addrs = append(addrs, net.IP(resp[offset+12:offset+12+rdlen]))
+		offset += 12 + rdlen
+	}
+	return rcode, truncated, addrs
+}
+
+func splitLabels(name string) []string {
+	var labels []string
+	start := 0
+	for i := 0; i <= len(name); i++ {
+		if i == len(name) || name[i] == '.' {
+			labels = append(labels, name[start:i])
+			start = i + 1
+		}
+	}
+	return labels
+}
+
+func (s *ServerSuite) TestQueryA(c *gc.C) {
+	rcode, addrs := s.query(c, "wordpress.admin.uuid.juju", 1)
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(addrs, gc.HasLen, 2)
+	c.Assert(addrs[0].Equal(net.ParseIP("10.0.0.1")), jc.IsTrue)
+	c.Assert(addrs[1].Equal(net.ParseIP("10.0.0.2")), jc.IsTrue)
+}
+
+func (s *ServerSuite) TestQueryAAAA(c *gc.C) {
+	rcode, addrs := s.query(c, "WordPress.Admin.UUID.Juju", 28)
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(addrs, gc.HasLen, 1)
+	c.Assert(addrs[0].Equal(net.ParseIP("fd00::1")), jc.IsTrue)
+}
+
+func (s *ServerSuite) TestQueryNoAddressesOfType(c *gc.C) {
+	rcode, addrs := s.query(c, "machine-0.admin.uuid.juju", 28)
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(addrs, gc.HasLen, 0)
+}
+
+func (s *ServerSuite) TestQueryUnknownName(c *gc.C) {
+	rcode, addrs := s.query(c, "mysql.admin.uuid.juju", 1)
+	c.Assert(rcode, gc.Equals, 3)
+	c.Assert(addrs, gc.HasLen, 0)
+}
+
+func (s *ServerSuite) TestQueryExternalName(c *gc.C) {
+	rcode, addrs := s.query(c, "example.com", 1)
+	c.Assert(rcode, gc.Equals, 5)
+	c.Assert(addrs, gc.HasLen, 0)
+}
+
+func (s *ServerSuite) TestRemoveRecords(c *gc.C) {
+	s.zones.RemoveRecords("uuid")
+	rcode, _ := s.query(c, "wordpress.admin.uuid.juju", 1)
+	c.Assert(rcode, gc.Equals, 3)
+}
+
+func (s *ServerSuite) TestQueryOtherModel(c *gc.C) {
+	s.zones.SetRecords("uuid2", []network.DNSRecord{
+		{Name: "wordpress.admin.uuid2.juju", Addresses: []string{"10.0.1.1"}},
+		// Records outside the model's domain are ignored.
+		{Name: "mysql.admin.uuid.juju", Addresses: []string{"10.0.1.2"}},
+	})
+	rcode, addrs := s.query(c, "wordpress.admin.uuid2.juju", 1)
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(addrs, gc.HasLen, 1)
+	c.Assert(addrs[0].Equal(net.ParseIP("10.0.1.1")), jc.IsTrue)
+
+	rcode, addrs = s.query(c, "wordpress.admin.uuid.juju", 1)
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(addrs, gc.HasLen, 2)
+
+	rcode, _ = s.query(c, "mysql.admin.uuid.juju", 1)
+	c.Assert(rcode, gc.Equals, 3)
+}
+
+func (s *ServerSuite) TestQueryTCP(c *gc.C) {
+	rcode, truncated, addrs := s.queryTCP(c, "wordpress.admin.uuid.juju", 1)
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(truncated, jc.IsFalse)
+	c.Assert(addrs, gc.HasLen, 2)
+}
+
+func (s *ServerSuite) TestQueryTruncated(c *gc.C) {
+	var values []string
+	for i := 1; i <= 50; i++ {
+		values = append(values, fmt.Sprintf("10.0.2.%d", i))
+	}
+	s.zones.SetRecords("uuid", []network.DNSRecord{
+		{Name: "big.admin.uuid.juju", Addresses: values},
+	})
+
+	// The UDP response carries as many answers as fit, and is marked
+	// as truncated.
+	rcode, truncated, addrs := s.queryUDP(c, "big.admin.uuid.juju", 1)
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(truncated, jc.IsTrue)
+	c.Assert(len(addrs) > 0 && len(addrs) < len(values), jc.IsTrue)
+	for i, addr := range addrs {
+		c.Assert(addr.String(), gc.Equals, values[i])
+	}
+
+	// The TCP response carries them all.
+	rcode, truncated, addrs = s.queryTCP(c, "big.admin.uuid.juju", 1)
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(truncated, jc.IsFalse)
+	c.Assert(addrs, gc.HasLen, len(values))
+}
@@ -0,0 +1,95 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internaldns
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// Updater is a worker that updates the internal DNS records of a
+// model whenever its machines, units, services or config change, while
+// the model's internal-dns setting is enabled. The records are removed
+// from the zones when it is disabled or the worker stops.
+type Updater struct {
+	catacomb catacomb.Catacomb
+	st       *state.State
+	zones    *Zones
+}
+
+// NewUpdater returns a worker that keeps the internal DNS records of
+// the supplied model in the given zones.
+func NewUpdater(st *state.State, zones *Zones) (*Updater, error) {
+	u := &Updater{
+		st:    st,
+		zones: zones,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &u.catacomb,
+		Work: u.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return u, nil
+}
+
+func (u *Updater) loop() error {
+	defer u.zones.RemoveRecords(u.st.ModelUUID())
+	recordsWatcher := u.st.WatchDNSRecords()
+	if err := u.catacomb.Add(recordsWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	configWatcher := u.st.WatchForModelConfigChanges()
+	if err := u.catacomb.Add(configWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-u.catacomb.Dying():
+			return u.catacomb.ErrDying()
+		case _, ok := <-recordsWatcher.Changes():
+			if !ok {
+				return errors.New("DNS records watcher closed")
+			}
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return errors.New("model config watcher closed")
+			}
+		}
+		if err := u.update(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// update updates the model's records once.
+func (u *Updater) update() error {
+	cfg, err := u.st.ModelConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot read model config")
+	}
+	if !cfg.InternalDNS() {
+		u.zones.RemoveRecords(u.st.ModelUUID())
+		return nil
+	}
+	records, err := u.st.DNSRecords()
+	if err != nil {
+		return errors.Annotate(err, "cannot get DNS records")
+	}
+	u.zones.SetRecords(u.st.ModelUUID(), records)
+	return nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (u *Updater) Kill() {
+	u.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (u *Updater) Wait() error {
+	return u.catacomb.Wait()
+}
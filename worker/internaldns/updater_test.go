@@ -0,0 +1,86 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internaldns_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/internaldns"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type UpdaterSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&UpdaterSuite{})
+
+func (s *UpdaterSuite) setInternalDNS(c *gc.C, enabled bool) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"internal-dns": enabled,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpdaterSuite) modelDNSDomain() string {
+	cfg := s.Environ.Config()
+	return network.ModelDNSDomain(cfg.Name(), cfg.UUID())
+}
+
+func (s *UpdaterSuite) waitForRecords(c *gc.C, zones *internaldns.Zones, name string, found bool) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if _, ok := zones.Lookup(name); ok == found {
+			return
+		}
+	}
+	c.Fatalf("timed out waiting for record %q (found: %v)", name, found)
+}
+
+func (s *UpdaterSuite) TestUpdater(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProviderAddresses(network.NewAddress("10.0.0.1"))
+	c.Assert(err, jc.ErrorIsNil)
+	name := network.MachineDNSName(m.Id(), s.modelDNSDomain())
+	s.setInternalDNS(c, true)
+
+	zones := internaldns.NewZones()
+	updater, err := internaldns.NewUpdater(s.State, zones)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, updater)
+	s.waitForRecords(c, zones, name, true)
+
+	addrs, _ := zones.Lookup(name)
+	c.Assert(addrs, gc.HasLen, 1)
+	c.Assert(addrs[0].String(), gc.Equals, "10.0.0.1")
+
+	// A change of address is picked up by the records watcher.
+	err = m.SetProviderAddresses(network.NewAddress("10.0.0.2"))
+	c.Assert(err, jc.ErrorIsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		addrs, _ = zones.Lookup(name)
+		if len(addrs) == 1 && addrs[0].String() == "10.0.0.2" {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out waiting for address change")
+		}
+	}
+
+	// Disabling internal DNS withdraws the records.
+	s.setInternalDNS(c, false)
+	s.waitForRecords(c, zones, name, false)
+
+	// Stopping the updater removes the model's records.
+	s.setInternalDNS(c, true)
+	s.waitForRecords(c, zones, name, true)
+	workertest.CleanKill(c, updater)
+	_, found := zones.Lookup(name)
+	c.Assert(found, jc.IsFalse)
+}
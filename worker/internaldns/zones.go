@@ -0,0 +1,73 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package internaldns
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/juju/juju/network"
+)
+
+// Zones holds the internal DNS records of the models on a controller,
+// as served by the DNS server. It is safe for concurrent use.
+//
+// Each model's records are in its own domain, which includes the
+// model's UUID, so a name is only ever looked up in the records of a
+// single model.
+type Zones struct {
+	mu     sync.RWMutex
+	models map[string]map[string][]net.IP
+}
+
+// NewZones returns a new, empty, Zones.
+func NewZones() *Zones {
+	return &Zones{models: make(map[string]map[string][]net.IP)}
+}
+
+// SetRecords replaces the records of the model with the given UUID.
+// Records outside the model's domain, and addresses that are not
+// valid IP addresses, are ignored.
+func (z *Zones) SetRecords(modelUUID string, records []network.DNSRecord) {
+	names := make(map[string][]net.IP, len(records))
+	for _, record := range records {
+		name := strings.ToLower(record.Name)
+		if network.ModelUUIDFromDNSName(name) != modelUUID {
+			logger.Warningf("ignoring DNS record %q outside the domain of model %q", name, modelUUID)
+			continue
+		}
+		var addrs []net.IP
+		for _, value := range record.Addresses {
+			if ip := net.ParseIP(value); ip != nil {
+				addrs = append(addrs, ip)
+			}
+		}
+		names[name] = addrs
+	}
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.models[modelUUID] = names
+}
+
+// RemoveRecords removes the records of the model with the given UUID.
+func (z *Zones) RemoveRecords(modelUUID string) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	delete(z.models, modelUUID)
+}
+
+// Lookup returns the addresses the given name resolves to, and whether
+// there is a record with that name at all.
+func (z *Zones) Lookup(name string) ([]net.IP, bool) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	modelUUID := network.ModelUUIDFromDNSName(name)
+	if modelUUID == "" {
+		return nil, false
+	}
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+	addrs, ok := z.models[modelUUID][name]
+	return addrs, ok
+}
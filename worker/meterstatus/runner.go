@@ -13,6 +13,7 @@ import (
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/agent"
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/worker/uniter"
 	"github.com/juju/juju/worker/uniter/runner"
 )
@@ -54,6 +55,12 @@ func (w *hookRunner) acquireExecutionLock(interrupt <-chan struct{}) (func() err
 	if err := w.machineLock.LockWithFunc(message, checkTomb); err != nil {
 		return nil, err
 	}
+	// The uniter may be running the unit's other hooks without the
+	// machine lock; wait for them to finish.
+	if err := cmdutil.WaitUnitHookExecutions(w.config.DataDir(), cmdutil.DefaultUnitHookExecutionsTimeout, checkTomb); err != nil {
+		w.machineLock.Unlock()
+		return nil, err
+	}
 	return func() error {
 		logger.Tracef("unlock: %v", message)
 		return w.machineLock.Unlock()
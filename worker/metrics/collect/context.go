@@ -80,6 +80,9 @@ func (ctx *hookContext) HasExecutionSetUnitStatus() bool { return false }
 // ResetExecutionSetUnitStatus implements runner.Context.
 func (ctx *hookContext) ResetExecutionSetUnitStatus() {}
 
+// HookTimeout implements runner.Context.
+func (ctx *hookContext) HookTimeout() time.Duration { return 0 }
+
 // Id implements runner.Context.
 func (ctx *hookContext) Id() string { return ctx.id }
 
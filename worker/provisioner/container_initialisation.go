@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/agent"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/apiserver/params"
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
@@ -97,7 +98,7 @@ func (cs *ContainerSetup) SetUp() (watcher watcher.StringsWatcher, err error) {
 // Handle is called whenever containers change on the machine being watched.
 // Machines start out with no containers so the first time Handle is called,
 // it will be because a container has been added.
-func (cs *ContainerSetup) Handle(_ <-chan struct{}, containerIds []string) (resultError error) {
+func (cs *ContainerSetup) Handle(abort <-chan struct{}, containerIds []string) (resultError error) {
 	// Consume the initial watcher event.
 	if len(containerIds) == 0 {
 		return nil
@@ -110,7 +111,7 @@ func (cs *ContainerSetup) Handle(_ <-chan struct{}, containerIds []string) (resu
 		if atomic.LoadInt32(cs.setupDone[containerType]) != 0 {
 			continue
 		}
-		if err := cs.initialiseAndStartProvisioner(containerType); err != nil {
+		if err := cs.initialiseAndStartProvisioner(abort, containerType); err != nil {
 			logger.Errorf("starting container provisioner for %v: %v", containerType, err)
 			// Just because dealing with one type of container fails, we won't exit the entire
 			// function because we still want to try and start other container types. So we
@@ -123,7 +124,7 @@ func (cs *ContainerSetup) Handle(_ <-chan struct{}, containerIds []string) (resu
 	return resultError
 }
 
-func (cs *ContainerSetup) initialiseAndStartProvisioner(containerType instance.ContainerType) (resultError error) {
+func (cs *ContainerSetup) initialiseAndStartProvisioner(abort <-chan struct{}, containerType instance.ContainerType) (resultError error) {
 	// Flag that this container type has been handled.
 	atomic.StoreInt32(cs.setupDone[containerType], 1)
 
@@ -148,7 +149,7 @@ func (cs *ContainerSetup) initialiseAndStartProvisioner(containerType instance.C
 	if err != nil {
 		return errors.Annotate(err, "initialising container infrastructure on host machine")
 	}
-	if err := cs.runInitialiser(containerType, initialiser); err != nil {
+	if err := cs.runInitialiser(abort, containerType, initialiser); err != nil {
 		return errors.Annotate(err, "setting up container dependencies on host machine")
 	}
 	return StartProvisioner(cs.runner, containerType, cs.provisioner, cs.config, broker, toolsFinder)
@@ -185,13 +186,25 @@ func maybeOverrideDefaultLXCNet(containerType instance.ContainerType, addressabl
 	return nil
 }
 
-// runInitialiser runs the container initialiser with the initialisation hook held.
-func (cs *ContainerSetup) runInitialiser(containerType instance.ContainerType, initialiser container.Initialiser) error {
+// runInitialiser runs the container initialiser with the initialisation hook held,
+// once any hooks of units on the machine running concurrently have finished.
+func (cs *ContainerSetup) runInitialiser(abort <-chan struct{}, containerType instance.ContainerType, initialiser container.Initialiser) error {
 	logger.Debugf("running initialiser for %s containers", containerType)
 	if err := cs.initLock.Lock(fmt.Sprintf("initialise-%s", containerType)); err != nil {
 		return errors.Annotate(err, "failed to acquire initialization lock")
 	}
 	defer cs.initLock.Unlock()
+	checkAbort := func() error {
+		select {
+		case <-abort:
+			return errors.New("container setup aborted")
+		default:
+			return nil
+		}
+	}
+	if err := cmdutil.WaitUnitHookExecutions(cs.config.DataDir(), cmdutil.DefaultUnitHookExecutionsTimeout, checkAbort); err != nil {
+		return errors.Annotate(err, "failed to wait for unit hooks")
+	}
 
 	// Only tweak default LXC network config when address allocation
 	// feature flag is enabled.
@@ -6,13 +6,16 @@ package provisioner
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 )
 
 var lxdLogger = loggo.GetLogger("juju.provisioner.lxd")
@@ -48,35 +51,24 @@ type lxdBroker struct {
 	enableNAT   bool
 }
 
-func (broker *lxdBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+func (broker *lxdBroker) StartInstance(args environs.StartInstanceParams) (_ *environs.StartInstanceResult, err error) {
 	if args.InstanceConfig.HasNetworks() {
 		return nil, errors.New("starting lxd containers with networks is not supported yet")
 	}
 	machineId := args.InstanceConfig.MachineId
-	bridgeDevice := broker.agentConfig.Value(agent.LxcBridge)
-	if bridgeDevice == "" {
-		bridgeDevice = lxd.DefaultLxdBridge
-	}
-
-	preparedInfo, err := prepareOrGetContainerInterfaceInfo(
-		broker.api,
-		machineId,
-		bridgeDevice,
-		true, // allocate if possible, do not maintain existing.
-		broker.enableNAT,
-		args.NetworkInfo,
-		lxdLogger,
-	)
-	if err != nil {
-		// It's not fatal (yet) if we couldn't pre-allocate addresses for the
-		// container.
-		logger.Warningf("failed to prepare container %q network config: %v", machineId, err)
+	network := broker.spaceNetworkConfig(machineId)
+	if network != nil {
+		// Don't keep the container's space addresses if it cannot
+		// be started; they are allocated afresh on the next attempt.
+		defer func() {
+			if err != nil {
+				broker.releaseSpaceAddresses(machineId)
+			}
+		}()
 	} else {
-		args.NetworkInfo = preparedInfo
+		network = broker.bridgeNetworkConfig(machineId, args.NetworkInfo)
 	}
 
-	network := container.BridgeNetworkConfig(bridgeDevice, 0, args.NetworkInfo)
-
 	series := args.Tools.OneSeries()
 	args.InstanceConfig.MachineContainerType = instance.LXD
 	args.InstanceConfig.Tools = args.Tools[0]
@@ -116,6 +108,64 @@ func (broker *lxdBroker) StartInstance(args environs.StartInstanceParams) (*envi
 	}, nil
 }
 
+// spaceNetworkConfig returns the configuration attaching the container
+// directly to the host's network devices on the spaces it needs access
+// to, with static addresses allocated by the controller, or nil if the
+// container should use the host's bridge instead. Failing to prepare
+// the space interfaces is not fatal: the container falls back to the
+// bridge. Note that, with macvlan, the container cannot reach the host
+// itself through those devices.
+func (broker *lxdBroker) spaceNetworkConfig(machineId string) *container.NetworkConfig {
+	interfaces, err := broker.api.PrepareContainerSpaceInterfaces(names.NewMachineTag(machineId))
+	switch {
+	case errors.IsNotSupported(err) || params.IsCodeNotSupported(err):
+		lxdLogger.Debugf("not attaching container %q to its spaces: %v", machineId, err)
+		return nil
+	case err != nil:
+		lxdLogger.Warningf("cannot attach container %q to its spaces, using the bridge: %v", machineId, err)
+		return nil
+	case len(interfaces) == 0:
+		return nil
+	}
+	return container.MacvlanNetworkConfig(0, interfaces)
+}
+
+// releaseSpaceAddresses releases the space addresses allocated to a
+// container that could not be started.
+func (broker *lxdBroker) releaseSpaceAddresses(machineId string) {
+	if err := broker.api.ReleaseContainerAddresses(names.NewMachineTag(machineId)); err != nil {
+		lxdLogger.Warningf("cannot release addresses of container %q: %v", machineId, err)
+	}
+}
+
+// bridgeNetworkConfig returns the configuration connecting the
+// container to the host's bridge.
+func (broker *lxdBroker) bridgeNetworkConfig(machineId string, networkInfo []network.InterfaceInfo) *container.NetworkConfig {
+	bridgeDevice := broker.agentConfig.Value(agent.LxcBridge)
+	if bridgeDevice == "" {
+		bridgeDevice = lxd.DefaultLxdBridge
+	}
+
+	preparedInfo, err := prepareOrGetContainerInterfaceInfo(
+		broker.api,
+		machineId,
+		bridgeDevice,
+		true, // allocate if possible, do not maintain existing.
+		broker.enableNAT,
+		networkInfo,
+		lxdLogger,
+	)
+	if err != nil {
+		// It's not fatal (yet) if we couldn't pre-allocate addresses for the
+		// container.
+		logger.Warningf("failed to prepare container %q network config: %v", machineId, err)
+	} else {
+		networkInfo = preparedInfo
+	}
+
+	return container.BridgeNetworkConfig(bridgeDevice, 0, networkInfo)
+}
+
 func (broker *lxdBroker) StopInstances(ids ...instance.Id) error {
 	// TODO: potentially parallelise.
 	for _, id := range ids {
@@ -159,6 +159,7 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 		envCfg.ImageStream(),
 		secureServerConnection,
 		RetryStrategy{retryDelay: retryStrategyDelay, retryCount: retryStrategyCount},
+		envCfg.ProvisionerParallelism(),
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -235,6 +236,7 @@ func (p *environProvisioner) loop() error {
 				return errors.Annotate(err, "loaded invalid model configuration")
 			}
 			task.SetHarvestMode(environConfig.ProvisionerHarvestMode())
+			task.SetParallelism(environConfig.ProvisionerParallelism())
 		}
 	}
 }
@@ -327,6 +329,7 @@ func (p *containerProvisioner) loop() error {
 			}
 			p.configObserver.notify(modelConfig)
 			task.SetHarvestMode(modelConfig.ProvisionerHarvestMode())
+			task.SetParallelism(modelConfig.ProvisionerParallelism())
 		}
 	}
 }
@@ -6,6 +6,8 @@ package provisioner
 import (
 	"fmt"
 	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -39,6 +41,10 @@ type ProvisionerTask interface {
 	// should harvest machines. See config.HarvestMode for
 	// documentation of behavior.
 	SetHarvestMode(mode config.HarvestMode)
+
+	// SetParallelism sets the maximum number of instances the
+	// provisioner task will start concurrently.
+	SetParallelism(n int)
 }
 
 type MachineGetter interface {
@@ -58,6 +64,16 @@ type ToolsFinder interface {
 var _ MachineGetter = (*apiprovisioner.State)(nil)
 var _ ToolsFinder = (*apiprovisioner.State)(nil)
 
+// zonedBroker is implemented by brokers that can start instances in a
+// chosen availability zone, using a "zone=<name>" placement directive.
+type zonedBroker interface {
+	AvailabilityZones() ([]instance.AvailabilityZone, error)
+}
+
+// zoneFailureTimeout is how long the provisioner avoids placing
+// instances in an availability zone in which one failed to start.
+var zoneFailureTimeout = 10 * time.Minute
+
 func NewProvisionerTask(
 	machineTag names.MachineTag,
 	harvestMode config.HarvestMode,
@@ -70,6 +86,7 @@ func NewProvisionerTask(
 	imageStream string,
 	secureServerConnection bool,
 	retryStartInstanceStrategy RetryStrategy,
+	parallelism int,
 ) (ProvisionerTask, error) {
 	machineChanges := machineWatcher.Changes()
 	workers := []worker.Worker{machineWatcher}
@@ -92,6 +109,13 @@ func NewProvisionerTask(
 		imageStream:                imageStream,
 		secureServerConnection:     secureServerConnection,
 		retryStartInstanceStrategy: retryStartInstanceStrategy,
+		parallelism:                parallelism,
+		parallelismChan:            make(chan int, 1),
+		failedZones:                make(map[string]time.Time),
+		pendingZones:               make(map[string]map[string]int),
+		starting:                   make(map[string]bool),
+		startDone:                  make(chan startResult),
+		abandonStarts:              make(chan struct{}),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &task.catacomb,
@@ -118,6 +142,32 @@ type provisionerTask struct {
 	harvestMode                config.HarvestMode
 	harvestModeChan            chan config.HarvestMode
 	retryStartInstanceStrategy RetryStrategy
+	parallelism                int
+	parallelismChan            chan int
+	// zonesMu guards failedZones, which records when instances last
+	// failed to start in each zone, and pendingZones, which counts the
+	// instances started in each zone for each service while starts
+	// are in flight.
+	zonesMu      sync.Mutex
+	failedZones  map[string]time.Time
+	pendingZones map[string]map[string]int
+	// starting records the machines, by id, that are queued to be
+	// started or being started; startQueue holds the queued ones, and
+	// running counts the ones being started. They are only accessed
+	// by the loop goroutine.
+	starting   map[string]bool
+	startQueue []*apiprovisioner.Machine
+	running    int
+	// unknownDeferred records that unknown instances were left
+	// running because instances were being started; they are
+	// looked for again once the starts have finished.
+	unknownDeferred bool
+	// startDone receives the result of each start, and abandonStarts
+	// is closed when the loop exits so that the remaining starts do
+	// not block reporting their results.
+	startDone     chan startResult
+	abandonStarts chan struct{}
+	startsWG      sync.WaitGroup
 	// instance id -> instance
 	instances map[instance.Id]instance.Instance
 	// machine id -> machine
@@ -142,6 +192,13 @@ func (task *provisionerTask) loop() error {
 	// as unknown.
 	var harvestModeChan chan config.HarvestMode
 
+	// Wait for the instances already being started, so that none are
+	// left without their instance info recorded.
+	defer func() {
+		close(task.abandonStarts)
+		task.startsWG.Wait()
+	}()
+
 	// When the watcher is started, it will have the initial changes be all
 	// the machines that are relevant. Also, since this is available straight
 	// away, we know there will be some changes right off the bat.
@@ -173,6 +230,25 @@ func (task *provisionerTask) loop() error {
 					return errors.Annotate(err, "failed to process machines after safe mode disabled")
 				}
 			}
+		case parallelism := <-task.parallelismChan:
+			if parallelism != task.parallelism {
+				logger.Infof("provisioner parallelism changed to %d", parallelism)
+				task.parallelism = parallelism
+				task.launchStarts()
+			}
+		case result := <-task.startDone:
+			task.running--
+			delete(task.starting, result.machineId)
+			if result.err != nil {
+				return errors.Annotate(result.err, "failed to start machine")
+			}
+			task.launchStarts()
+			if task.running == 0 && task.unknownDeferred {
+				task.unknownDeferred = false
+				if err := task.processMachines(nil); err != nil {
+					return errors.Annotate(err, "failed to process machines after starting instances")
+				}
+			}
 		case <-task.retryChanges:
 			if err := task.processMachinesWithTransientErrors(); err != nil {
 				return errors.Annotate(err, "failed to process machines with transient errors")
@@ -189,6 +265,14 @@ func (task *provisionerTask) SetHarvestMode(mode config.HarvestMode) {
 	}
 }
 
+// SetParallelism implements ProvisionerTask.SetParallelism().
+func (task *provisionerTask) SetParallelism(n int) {
+	select {
+	case task.parallelismChan <- n:
+	case <-task.catacomb.Dying():
+	}
+}
+
 func (task *provisionerTask) processMachinesWithTransientErrors() error {
 	machines, statusResults, err := task.machineGetter.MachinesWithTransientErrors()
 	if err != nil {
@@ -202,6 +286,9 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 			continue
 		}
 		machine := machines[i]
+		if task.starting[machine.Id()] {
+			continue
+		}
 		if err := machine.SetStatus(params.StatusPending, "", nil); err != nil {
 			logger.Errorf("cannot reset status of machine %q: %v", status.Id, err)
 			continue
@@ -242,6 +329,16 @@ func (task *provisionerTask) processMachines(ids []string) error {
 			instanceIds(unknown),
 		)
 		unknown = nil
+	} else if task.running > 0 && len(unknown) > 0 {
+		// An instance that is being started may exist before its
+		// machine records it, so we cannot tell which instances are
+		// really unknown until the starts have finished.
+		logger.Infof(
+			"not stopping unknown instances %v while instances are being started",
+			instanceIds(unknown),
+		)
+		unknown = nil
+		task.unknownDeferred = true
 	}
 	if task.harvestMode.HarvestNone() || !task.harvestMode.HarvestDestroyed() {
 		logger.Infof(
@@ -502,7 +599,7 @@ func (task *provisionerTask) constructInstanceConfig(
 		return nil, err
 	}
 	nonce := fmt.Sprintf("%s:%s", task.machineTag, uuid)
-	return instancecfg.NewInstanceConfig(
+	instanceConfig, err := instancecfg.NewInstanceConfig(
 		machine.Id(),
 		nonce,
 		task.imageStream,
@@ -513,6 +610,11 @@ func (task *provisionerTask) constructInstanceConfig(
 		stateInfo,
 		apiInfo,
 	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	instanceConfig.CloudInitUserData = pInfo.CloudInitUserData
+	return instanceConfig, nil
 }
 
 func constructStartInstanceParams(
@@ -597,6 +699,7 @@ func constructStartInstanceParams(
 		SubnetsToZones:    subnetsToZones,
 		EndpointBindings:  endpointBindings,
 		ImageMetadata:     possibleImageMetadata,
+		RootDiskSource:    provisioningInfo.RootDiskSource,
 	}, nil
 }
 
@@ -613,55 +716,120 @@ func (task *provisionerTask) maintainMachines(machines []*apiprovisioner.Machine
 	return nil
 }
 
+// startResult holds the outcome of starting a machine's instance.
+type startResult struct {
+	machineId string
+	err       error
+}
+
+// startMachines queues the supplied machines to have their instances
+// started, skipping those already queued or being started, and starts
+// as many as task.parallelism allows. It does not wait for them: the
+// loop is told of each outcome on task.startDone. A failure to start
+// one machine is recorded in that machine's status, and does not
+// prevent the others from being started.
 func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) error {
 	for _, m := range machines {
-
-		pInfo, err := m.ProvisioningInfo()
-		if err != nil {
-			return task.setErrorStatus("fetching provisioning info for machine %q: %v", m, err)
+		if task.starting[m.Id()] {
+			logger.Debugf("machine %q is already being started", m)
+			continue
 		}
+		task.starting[m.Id()] = true
+		task.startQueue = append(task.startQueue, m)
+	}
+	task.launchStarts()
+	return nil
+}
 
-		instanceCfg, err := task.constructInstanceConfig(m, task.auth, pInfo)
-		if err != nil {
-			return task.setErrorStatus("creating instance config for machine %q: %v", m, err)
-		}
+// launchStarts starts instances for queued machines until
+// task.parallelism of them are being started at once.
+func (task *provisionerTask) launchStarts() {
+	workers := task.parallelism
+	if workers < 1 {
+		workers = 1
+	}
+	for task.running < workers && len(task.startQueue) > 0 {
+		m := task.startQueue[0]
+		task.startQueue = task.startQueue[1:]
+		task.running++
+		task.startsWG.Add(1)
+		go func() {
+			defer task.startsWG.Done()
+			err := task.provisionMachine(m)
+			select {
+			case task.startDone <- startResult{m.Id(), err}:
+			case <-task.abandonStarts:
+			}
+		}()
+	}
+	if task.running == 0 {
+		// Nothing is being started, so no zone counts are pending.
+		task.zonesMu.Lock()
+		task.pendingZones = make(map[string]map[string]int)
+		task.zonesMu.Unlock()
+	}
+}
 
-		assocProvInfoAndMachCfg(pInfo, instanceCfg)
+// provisionMachine gathers the information needed to start an instance
+// for the supplied machine, and starts it.
+func (task *provisionerTask) provisionMachine(m *apiprovisioner.Machine) error {
+	pInfo, err := m.ProvisioningInfo()
+	if params.IsCodeNotFound(err) {
+		logger.Infof("machine %q removed before being provisioned", m)
+		return nil
+	} else if err != nil {
+		return task.setErrorStatus("fetching provisioning info for machine %q: %v", m, err)
+	}
 
-		var arch string
-		if pInfo.Constraints.Arch != nil {
-			arch = *pInfo.Constraints.Arch
-		}
+	instanceCfg, err := task.constructInstanceConfig(m, task.auth, pInfo)
+	if err != nil {
+		return task.setErrorStatus("creating instance config for machine %q: %v", m, err)
+	}
 
-		possibleTools, err := task.toolsFinder.FindTools(
-			version.Current,
-			pInfo.Series,
-			arch,
-		)
-		if err != nil {
-			return task.setErrorStatus("cannot find tools for machine %q: %v", m, err)
-		}
+	assocProvInfoAndMachCfg(pInfo, instanceCfg)
 
-		startInstanceParams, err := constructStartInstanceParams(
-			m,
-			instanceCfg,
-			pInfo,
-			possibleTools,
-		)
-		if err != nil {
-			return task.setErrorStatus("cannot construct params for machine %q: %v", m, err)
-		}
+	var arch string
+	if pInfo.Constraints.Arch != nil {
+		arch = *pInfo.Constraints.Arch
+	}
+
+	possibleTools, err := task.toolsFinder.FindTools(
+		version.Current,
+		pInfo.Series,
+		arch,
+	)
+	if err != nil {
+		return task.setErrorStatus("cannot find tools for machine %q: %v", m, err)
+	}
 
-		if err := task.startMachine(m, pInfo, startInstanceParams); err != nil {
-			return errors.Annotatef(err, "cannot start machine %v", m)
+	startInstanceParams, err := constructStartInstanceParams(
+		m,
+		instanceCfg,
+		pInfo,
+		possibleTools,
+	)
+	if err != nil {
+		return task.setErrorStatus("cannot construct params for machine %q: %v", m, err)
+	}
+	if source := startInstanceParams.RootDiskSource; source != "" {
+		if err := environs.ValidateRootDiskSource(task.broker, source); err != nil {
+			return task.setErrorStatus("cannot start instance for machine %q: %v", m, err)
 		}
 	}
+
+	if err := task.startMachine(m, pInfo, startInstanceParams); err != nil {
+		return errors.Annotatef(err, "cannot start machine %v", m)
+	}
 	return nil
 }
 
 func (task *provisionerTask) setErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
 	logger.Errorf(message, machine, err)
-	if err1 := machine.SetStatus(params.StatusError, err.Error(), nil); err1 != nil {
+	if err1 := machine.SetStatus(params.StatusError, err.Error(), nil); params.IsCodeNotFound(err1) {
+		// The machine was removed while we were provisioning it;
+		// there is nothing left to report the error against.
+		logger.Infof("machine %q removed while being provisioned", machine)
+	} else if err1 != nil {
 		// Something is wrong with this machine, better report it back.
 		return errors.Annotatef(err1, "cannot set error status for machine %q", machine)
 	}
@@ -720,7 +888,7 @@ func (task *provisionerTask) startMachine(
 	startInstanceParams environs.StartInstanceParams,
 ) error {
 
-	result, err := task.broker.StartInstance(startInstanceParams)
+	result, err := task.startInstance(provisioningInfo, startInstanceParams)
 	if err != nil {
 		if !instance.IsRetryableCreationError(errors.Cause(err)) {
 			// Set the state to error, so the machine will be skipped next
@@ -738,7 +906,7 @@ func (task *provisionerTask) startMachine(
 				}
 
 			}
-			result, err = task.broker.StartInstance(startInstanceParams)
+			result, err = task.startInstance(provisioningInfo, startInstanceParams)
 			if err == nil {
 				break
 			}
@@ -777,7 +945,13 @@ func (task *provisionerTask) startMachine(
 		return nil
 	}
 	// We need to stop the instance right away here, set error status and go on.
-	task.setErrorStatus("cannot register instance for machine %v: %v", machine, err)
+	// If the machine was removed or destroyed while its instance was being
+	// started, the failure is expected and there is no status to set.
+	if machineGone(machine) {
+		logger.Infof("machine %v removed while being provisioned; stopping instance %q", machine, inst.Id())
+	} else {
+		task.setErrorStatus("cannot register instance for machine %v: %v", machine, err)
+	}
 	if err := task.broker.StopInstances(inst.Id()); err != nil {
 		// We cannot even stop the instance, log the error and quit.
 		return errors.Annotatef(err, "cannot stop instance %q for machine %v", inst.Id(), machine)
@@ -785,6 +959,156 @@ func (task *provisionerTask) startMachine(
 	return nil
 }
 
+// startInstance starts an instance with the supplied parameters. If
+// the machine is being provisioned for a service with a zone policy,
+// the instance is started in the zone chosen by the policy. A zone in
+// which an instance fails to start is avoided for a while, so that
+// retries are directed elsewhere.
+func (task *provisionerTask) startInstance(
+	provisioningInfo *params.ProvisioningInfo,
+	startInstanceParams environs.StartInstanceParams,
+) (*environs.StartInstanceResult, error) {
+	zone, err := task.chooseZone(provisioningInfo)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot choose availability zone")
+	}
+	if zone != "" {
+		logger.Infof("starting instance for machine %s in zone %q", startInstanceParams.InstanceConfig.MachineId, zone)
+		startInstanceParams.Placement = "zone=" + zone
+	}
+	result, err := task.broker.StartInstance(startInstanceParams)
+	if err != nil && zone != "" {
+		task.zonesMu.Lock()
+		task.failedZones[zone] = time.Now()
+		task.zonesMu.Unlock()
+		task.releaseZone(provisioningInfo.ZonePolicyService, zone)
+	}
+	return result, err
+}
+
+// chooseZone returns the availability zone in which an instance should
+// be started according to the zone policy in the supplied provisioning
+// info, or an empty string if there is no policy to apply. The choice
+// accounts for instances of the same service already being started,
+// and avoids zones in which instances recently failed to start.
+//
+// A policy that cannot be applied, because the machine has an explicit
+// placement elsewhere or the broker does not know its availability
+// zones, is ignored with a warning, as is the failure of every zone; a
+// strict policy instead causes an error to be returned.
+func (task *provisionerTask) chooseZone(provisioningInfo *params.ProvisioningInfo) (string, error) {
+	if provisioningInfo.ZonePolicy == "" {
+		return "", nil
+	}
+	policy, err := instance.ParseZonePolicy(provisioningInfo.ZonePolicy)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	service := provisioningInfo.ZonePolicyService
+	relax := func(format string, args ...interface{}) error {
+		reason := fmt.Sprintf(format, args...)
+		if policy.Strict() {
+			return errors.Errorf("cannot apply zone policy %q of service %q: %s", policy, service, reason)
+		}
+		logger.Warningf("not applying zone policy %q of service %q: %s", policy, service, reason)
+		return nil
+	}
+	if placement := provisioningInfo.Placement; placement != "" {
+		if zone := strings.TrimPrefix(placement, "zone="); zone != placement && policy.Permits(zone) {
+			return "", nil
+		}
+		return "", relax("machine has placement %q", placement)
+	}
+	broker, ok := task.broker.(zonedBroker)
+	if !ok {
+		return "", relax("provider does not support availability zones")
+	}
+	zones, err := broker.AvailabilityZones()
+	if errors.IsNotImplemented(err) {
+		return "", relax("provider does not support availability zones")
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(zones) == 0 {
+		return "", relax("no availability zones are known")
+	}
+
+	task.zonesMu.Lock()
+	defer task.zonesMu.Unlock()
+	population := make(map[string]int)
+	for zone, n := range provisioningInfo.ZonePopulation {
+		population[zone] = n
+	}
+	for zone, n := range task.pendingZones[service] {
+		population[zone] += n
+	}
+	var available, healthy []string
+	for _, zone := range zones {
+		if !zone.Available() {
+			continue
+		}
+		available = append(available, zone.Name())
+		if failed, ok := task.failedZones[zone.Name()]; !ok || time.Since(failed) > zoneFailureTimeout {
+			healthy = append(healthy, zone.Name())
+		}
+	}
+	zone, err := policy.ChooseZone(healthy, population)
+	if errors.IsNotFound(err) {
+		zone, err = policy.ChooseZone(available, population)
+		if err == nil {
+			if policy.Strict() {
+				return "", errors.Errorf(
+					"cannot apply zone policy %q of service %q: all permitted availability zones recently failed",
+					policy, service,
+				)
+			}
+			logger.Warningf(
+				"all availability zones recently failed; starting instance of service %q in %q anyway",
+				service, zone,
+			)
+		}
+	}
+	if errors.IsNotFound(err) {
+		return "", relax("no permitted availability zone is available")
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	if task.pendingZones[service] == nil {
+		task.pendingZones[service] = make(map[string]int)
+	}
+	task.pendingZones[service][zone]++
+	return zone, nil
+}
+
+// releaseZone stops counting an instance of the specified service
+// against the zone chosen for it by chooseZone, because it failed to
+// start. Instances that do start are counted until no more are being
+// started, by which time their zones are known to the controller.
+func (task *provisionerTask) releaseZone(service, zone string) {
+	if zone == "" {
+		return
+	}
+	task.zonesMu.Lock()
+	defer task.zonesMu.Unlock()
+	if pending := task.pendingZones[service]; pending != nil {
+		if pending[zone]--; pending[zone] <= 0 {
+			delete(pending, zone)
+		}
+		if len(pending) == 0 {
+			delete(task.pendingZones, service)
+		}
+	}
+}
+
+// machineGone reports whether the machine has been removed, or is no
+// longer alive, and so should not be given an instance.
+func machineGone(machine *apiprovisioner.Machine) bool {
+	if err := machine.Refresh(); err != nil {
+		return params.IsCodeNotFound(err)
+	}
+	return machine.Life() != params.Alive
+}
+
 type provisioningInfo struct {
 	Constraints    constraints.Value
 	Series         string
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -297,6 +298,39 @@ func (s *CommonProvisionerSuite) checkStartInstanceCustom(
 	}
 }
 
+// checkStartInstances checks that instances are started, in any order,
+// for all of the supplied machines, with the machines' jobs.
+func (s *CommonProvisionerSuite) checkStartInstances(c *gc.C, machines ...*state.Machine) {
+	s.BackingState.StartSync()
+	pending := make(map[string]*state.Machine)
+	for _, m := range machines {
+		pending[m.Id()] = m
+	}
+	for len(pending) > 0 {
+		select {
+		case o := <-s.op:
+			switch o := o.(type) {
+			case dummy.OpStartInstance:
+				m, ok := pending[o.MachineId]
+				c.Assert(ok, jc.IsTrue, gc.Commentf("unexpected start of machine %s", o.MachineId))
+				delete(pending, o.MachineId)
+				s.waitInstanceId(c, m, o.Instance.Id())
+
+				var jobs []multiwatcher.MachineJob
+				for _, job := range m.Jobs() {
+					jobs = append(jobs, job.ToParams())
+				}
+				c.Assert(o.Jobs, jc.SameContents, jobs)
+			default:
+				c.Logf("ignoring unexpected operation %#v", o)
+			}
+		case <-time.After(2 * time.Second):
+			c.Fatalf("provisioner did not start instances for all machines")
+			return
+		}
+	}
+}
+
 // checkNoOperations checks that the environ was not operated upon.
 func (s *CommonProvisionerSuite) checkNoOperations(c *gc.C) {
 	s.BackingState.StartSync()
@@ -1224,6 +1258,24 @@ func (s *ProvisionerSuite) newProvisionerTask(
 	machineGetter provisioner.MachineGetter,
 	toolsFinder provisioner.ToolsFinder,
 ) provisioner.ProvisionerTask {
+	return s.newProvisionerTaskWithParallelism(
+		c,
+		harvestingMethod,
+		broker,
+		machineGetter,
+		toolsFinder,
+		config.DefaultProvisionerParallelism,
+	)
+}
+
+func (s *ProvisionerSuite) newProvisionerTaskWithParallelism(
+	c *gc.C,
+	harvestingMethod config.HarvestMode,
+	broker environs.InstanceBroker,
+	machineGetter provisioner.MachineGetter,
+	toolsFinder provisioner.ToolsFinder,
+	parallelism int,
+) provisioner.ProvisionerTask {
 
 	machineWatcher, err := s.provisioner.WatchModelMachines()
 	c.Assert(err, jc.ErrorIsNil)
@@ -1246,6 +1298,7 @@ func (s *ProvisionerSuite) newProvisionerTask(
 		imagemetadata.ReleasedStream,
 		true,
 		retryStrategy,
+		parallelism,
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	return w
@@ -1400,19 +1453,309 @@ func (s *ProvisionerSuite) TestProvisionerObservesMachineJobs(c *gc.C) {
 
 	added := s.enableHA(c, 3)
 	c.Assert(added, gc.HasLen, 2)
-	byId := make(map[string]*state.Machine)
-	for _, m := range added {
-		byId[m.Id()] = m
+	s.checkStartInstances(c, added...)
+}
+
+func (s *ProvisionerSuite) TestProvisionerStartsInstancesConcurrently(c *gc.C) {
+	// Add the machines before starting the task, so that they are
+	// all reported in the watcher's initial event.
+	var machines []*state.Machine
+	for i := 0; i < 3; i++ {
+		m, err := s.addMachine()
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, m)
+	}
+	broker := newBlockingBroker(s.Environ)
+	task := s.newProvisionerTaskWithParallelism(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{}, 2)
+	defer stop(c, task)
+
+	// Two instances are started at once, but no more.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-broker.started:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for instance %d to start", i)
+		}
+	}
+	select {
+	case id := <-broker.started:
+		c.Fatalf("machine %s started beyond the parallelism limit", id)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	close(broker.release)
+	select {
+	case <-broker.started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for last instance to start")
+	}
+	s.checkStartInstances(c, machines...)
+}
+
+func (s *ProvisionerSuite) TestProvisionerDoesNotHarvestInstancesBeingStarted(c *gc.C) {
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	broker := newBlockingBroker(s.Environ)
+	broker.blockAfterStart = true
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	// The instance exists, but has not yet been recorded against
+	// the machine.
+	var inst0 instance.Instance
+	select {
+	case o := <-s.op:
+		start, ok := o.(dummy.OpStartInstance)
+		c.Assert(ok, jc.IsTrue, gc.Commentf("unexpected operation %#v", o))
+		c.Assert(start.MachineId, gc.Equals, m0.Id())
+		inst0 = start.Instance
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to start")
+	}
+	select {
+	case <-broker.started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to start")
+	}
+
+	// A machine change while the start is blocked must not stop the
+	// instance as unknown.
+	m1, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	var inst1 instance.Instance
+	select {
+	case o := <-s.op:
+		start, ok := o.(dummy.OpStartInstance)
+		c.Assert(ok, jc.IsTrue, gc.Commentf("unexpected operation %#v", o))
+		c.Assert(start.MachineId, gc.Equals, m1.Id())
+		inst1 = start.Instance
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for second instance to start")
+	}
+
+	close(broker.release)
+	s.waitInstanceId(c, m0, inst0.Id())
+	s.waitInstanceId(c, m1, inst1.Id())
+	s.checkNoOperations(c)
+}
+
+func (s *ProvisionerSuite) TestProvisionerKeepsRunningWhileStartingInstances(c *gc.C) {
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	broker := newBlockingBroker(s.Environ)
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	select {
+	case id := <-broker.started:
+		c.Assert(id, gc.Equals, m0.Id())
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to start")
+	}
+
+	// A machine added while the first instance is still being
+	// started is started without waiting for it.
+	m1, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case id := <-broker.started:
+		c.Assert(id, gc.Equals, m1.Id())
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for second instance to start")
 	}
-	for _, id := range broker.ids {
-		s.checkStartInstance(c, byId[id])
+
+	// Neither machine is started twice.
+	close(broker.release)
+	s.checkStartInstances(c, m0, m1)
+	select {
+	case id := <-broker.started:
+		c.Fatalf("machine %s started twice", id)
+	case <-time.After(coretesting.ShortWait):
 	}
 }
 
+func (s *ProvisionerSuite) TestProvisionerStopsInstanceOfRemovedMachine(c *gc.C) {
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	broker := newBlockingBroker(s.Environ)
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	select {
+	case <-broker.started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to start")
+	}
+
+	// Remove the machine while its instance is being started; the
+	// instance is stopped once it cannot be recorded against it.
+	c.Assert(m0.EnsureDead(), jc.ErrorIsNil)
+	c.Assert(m0.Remove(), jc.ErrorIsNil)
+	close(broker.release)
+
+	var inst instance.Instance
+	select {
+	case o := <-s.op:
+		start, ok := o.(dummy.OpStartInstance)
+		c.Assert(ok, jc.IsTrue, gc.Commentf("unexpected operation %#v", o))
+		c.Assert(start.MachineId, gc.Equals, m0.Id())
+		inst = start.Instance
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to start")
+	}
+	s.checkStopInstances(c, inst)
+}
+
+func (s *ProvisionerSuite) addUnitsOnNewMachines(c *gc.C, svc *state.Service, n int) {
+	for i := 0; i < n; i++ {
+		unit, err := svc.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToNewMachine()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *ProvisionerSuite) waitStartedZones(c *gc.C, broker *mockZonedBroker, n int) []string {
+	var zones []string
+	for len(zones) < n {
+		select {
+		case zone := <-broker.started:
+			zones = append(zones, zone)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for instances to start; got zones %v", zones)
+		}
+	}
+	return zones
+}
+
+func (s *ProvisionerSuite) TestProvisionerSpreadsUnitsAcrossZones(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := wordpress.SetZonePolicy(&instance.ZonePolicy{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.addUnitsOnNewMachines(c, wordpress, 3)
+
+	broker := newMockZonedBroker(s.Environ, "a", "b", "c")
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	// The instances are started concurrently, but each is placed in a
+	// different zone.
+	zones := s.waitStartedZones(c, broker, 3)
+	c.Assert(zones, jc.SameContents, []string{"a", "b", "c"})
+}
+
+func (s *ProvisionerSuite) TestProvisionerRestrictsUnitsToZones(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := wordpress.SetZonePolicy(&instance.ZonePolicy{Zones: []string{"b", "c"}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.addUnitsOnNewMachines(c, wordpress, 4)
+
+	broker := newMockZonedBroker(s.Environ, "a", "b", "c")
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	zones := s.waitStartedZones(c, broker, 4)
+	c.Assert(zones, jc.SameContents, []string{"b", "b", "c", "c"})
+}
+
+func (s *ProvisionerSuite) TestProvisionerAvoidsFailedZones(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := wordpress.SetZonePolicy(&instance.ZonePolicy{})
+	c.Assert(err, jc.ErrorIsNil)
+	broker := newMockZonedBroker(s.Environ, "a", "b")
+	broker.failZone = "a"
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	// The first instance fails to start in zone "a"...
+	s.addUnitsOnNewMachines(c, wordpress, 1)
+	s.BackingState.StartSync()
+	c.Assert(s.waitStartedZones(c, broker, 1), jc.DeepEquals, []string{"a"})
+
+	// ...so the next is started in zone "b", although neither zone
+	// holds any of the service's units.
+	s.addUnitsOnNewMachines(c, wordpress, 1)
+	s.BackingState.StartSync()
+	c.Assert(s.waitStartedZones(c, broker, 1), jc.DeepEquals, []string{"b"})
+}
+
+func (s *ProvisionerSuite) TestProvisionerFailsStrictZonePolicyWithoutZones(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := wordpress.SetZonePolicy(&instance.ZonePolicy{Zones: []string{"b"}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.addUnitsOnNewMachines(c, wordpress, 1)
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	m := machines[len(machines)-1]
+
+	// mockBroker does not report availability zones, so the strict
+	// policy cannot be honoured and the machine is not started.
+	broker := &mockBroker{Environ: s.Environ, retryCount: make(map[string]int)}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	s.waitMachine(c, m, func() bool {
+		statusInfo, err := m.Status()
+		c.Assert(err, jc.ErrorIsNil)
+		return statusInfo.Status == state.StatusError
+	})
+	statusInfo, err := m.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Message, gc.Matches, `.*cannot apply zone policy "zones=b" of service "wordpress": provider does not support availability zones`)
+	_, err = m.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *ProvisionerSuite) TestProvisionerStartsInstanceWithRootDiskSource(c *gc.C) {
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:         coretesting.FakeDefaultSeries,
+		Jobs:           []state.MachineJob{state.JobHostUnits},
+		RootDiskSource: "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	task := s.newProvisionerTask(c, config.HarvestAll, s.Environ, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	select {
+	case o := <-s.op:
+		start, ok := o.(dummy.OpStartInstance)
+		c.Assert(ok, jc.IsTrue, gc.Commentf("unexpected operation %#v", o))
+		c.Assert(start.MachineId, gc.Equals, m.Id())
+		c.Assert(start.RootDiskSource, gc.Equals, "ssd")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to start")
+	}
+}
+
+func (s *ProvisionerSuite) TestProvisionerRejectsUnsupportedRootDiskSource(c *gc.C) {
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:         coretesting.FakeDefaultSeries,
+		Jobs:           []state.MachineJob{state.JobHostUnits},
+		RootDiskSource: "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// mockBroker cannot choose the source of root disks.
+	broker := &mockBroker{Environ: s.Environ, retryCount: make(map[string]int)}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	s.waitMachine(c, m, func() bool {
+		statusInfo, err := m.Status()
+		c.Assert(err, jc.ErrorIsNil)
+		return statusInfo.Status == state.StatusError
+	})
+	statusInfo, err := m.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Message, gc.Equals, `root-disk source "ssd" not supported`)
+	s.checkNoOperations(c)
+}
+
 type mockBroker struct {
 	environs.Environ
+
+	mu         sync.Mutex
 	retryCount map[string]int
-	ids        []string
 }
 
 func (b *mockBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
@@ -1420,17 +1763,93 @@ func (b *mockBroker) StartInstance(args environs.StartInstanceParams) (*environs
 	// Machines 3 is provisioned after some attempts have been made.
 	// Machine 4 is never provisioned.
 	id := args.InstanceConfig.MachineId
-	// record ids so we can call checkStartInstance in the appropriate order.
-	b.ids = append(b.ids, id)
+	b.mu.Lock()
 	retries := b.retryCount[id]
 	if (id != "3" && id != "4") || retries > 2 {
+		b.mu.Unlock()
 		return b.Environ.StartInstance(args)
-	} else {
-		b.retryCount[id] = retries + 1
 	}
+	b.retryCount[id] = retries + 1
+	b.mu.Unlock()
 	return nil, fmt.Errorf("error: some error")
 }
 
+// mockZonedBroker starts instances in the zones named by their placement
+// directives, reporting the zones used, and fails to start any in its
+// failZone.
+type mockZonedBroker struct {
+	environs.Environ
+	zones    []string
+	failZone string
+	started  chan string
+}
+
+func newMockZonedBroker(env environs.Environ, zones ...string) *mockZonedBroker {
+	return &mockZonedBroker{
+		Environ: env,
+		zones:   zones,
+		started: make(chan string, 10),
+	}
+}
+
+func (b *mockZonedBroker) AvailabilityZones() ([]instance.AvailabilityZone, error) {
+	zones := make([]instance.AvailabilityZone, len(b.zones))
+	for i, name := range b.zones {
+		zones[i] = mockZone(name)
+	}
+	return zones, nil
+}
+
+func (b *mockZonedBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	zone := strings.TrimPrefix(args.Placement, "zone=")
+	b.started <- zone
+	if zone == b.failZone {
+		return nil, errors.Errorf("zone %q has insufficient capacity", zone)
+	}
+	return b.Environ.StartInstance(args)
+}
+
+type mockZone string
+
+func (z mockZone) Name() string {
+	return string(z)
+}
+
+func (z mockZone) Available() bool {
+	return true
+}
+
+// blockingBroker reports the ids of the machines it is asked to start
+// instances for, and blocks starting them until released. If
+// blockAfterStart is set, the instances are started before blocking,
+// so that they exist before their machines record them.
+type blockingBroker struct {
+	environs.Environ
+	started         chan string
+	release         chan struct{}
+	blockAfterStart bool
+}
+
+func newBlockingBroker(env environs.Environ) *blockingBroker {
+	return &blockingBroker{
+		Environ: env,
+		started: make(chan string, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (b *blockingBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	if b.blockAfterStart {
+		result, err := b.Environ.StartInstance(args)
+		b.started <- args.InstanceConfig.MachineId
+		<-b.release
+		return result, err
+	}
+	b.started <- args.InstanceConfig.MachineId
+	<-b.release
+	return b.Environ.StartInstance(args)
+}
+
 type mockToolsFinder struct {
 }
 
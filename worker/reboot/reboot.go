@@ -10,6 +10,7 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/reboot"
 	"github.com/juju/juju/apiserver/params"
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker"
 )
@@ -27,6 +28,7 @@ type Reboot struct {
 	tomb        tomb.Tomb
 	st          reboot.State
 	tag         names.MachineTag
+	dataDir     string
 	machineLock *fslock.Lock
 }
 
@@ -38,6 +40,7 @@ func NewReboot(st reboot.State, agentConfig agent.Config, machineLock *fslock.Lo
 	r := &Reboot{
 		st:          st,
 		tag:         tag,
+		dataDir:     agentConfig.DataDir(),
 		machineLock: machineLock,
 	}
 	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
@@ -78,7 +81,7 @@ func (r *Reboot) SetUp() (watcher.NotifyWatcher, error) {
 	return watcher, nil
 }
 
-func (r *Reboot) Handle(_ <-chan struct{}) error {
+func (r *Reboot) Handle(abort <-chan struct{}) error {
 	rAction, err := r.st.GetRebootAction()
 	if err != nil {
 		return errors.Trace(err)
@@ -86,15 +89,41 @@ func (r *Reboot) Handle(_ <-chan struct{}) error {
 	logger.Debugf("Reboot worker got action: %v", rAction)
 	switch rAction {
 	case params.ShouldReboot:
-		r.machineLock.Lock(RebootMessage)
+		if err := r.lockMachine(abort); err != nil {
+			return errors.Trace(err)
+		}
 		return worker.ErrRebootMachine
 	case params.ShouldShutdown:
-		r.machineLock.Lock(RebootMessage)
+		if err := r.lockMachine(abort); err != nil {
+			return errors.Trace(err)
+		}
 		return worker.ErrShutdownMachine
 	}
 	return nil
 }
 
+// lockMachine acquires the machine lock, and waits for any hooks of
+// units on the machine that run concurrently to finish. The lock is
+// released again if the wait is aborted or fails.
+func (r *Reboot) lockMachine(abort <-chan struct{}) error {
+	checkAbort := func() error {
+		select {
+		case <-abort:
+			return tomb.ErrDying
+		default:
+			return nil
+		}
+	}
+	if err := r.machineLock.LockWithFunc(RebootMessage, checkAbort); err != nil {
+		return errors.Trace(err)
+	}
+	if err := cmdutil.WaitUnitHookExecutions(r.dataDir, cmdutil.DefaultUnitHookExecutionsTimeout, checkAbort); err != nil {
+		r.machineLock.Unlock()
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 func (r *Reboot) TearDown() error {
 	// nothing to teardown.
 	return nil
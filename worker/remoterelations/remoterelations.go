@@ -0,0 +1,57 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package remoterelations provides the worker that keeps the
+// cross-model relations of a model synchronised with their
+// counterparts in other models on the same controller. The
+// synchronisation itself is done by the controller, through the
+// RemoteRelations API facade; see apiserver/remoterelations.
+package remoterelations
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker"
+)
+
+// Facade exposes the controller capabilities required by the worker.
+type Facade interface {
+	SyncRemoteRelations() error
+	WatchRemoteRelations() (watcher.NotifyWatcher, error)
+}
+
+// New returns a worker that asks the controller to synchronise the
+// cross-model relations of the model whenever they, or their
+// counterparts in other models, change.
+func New(facade Facade) (worker.Worker, error) {
+	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: &handler{facade: facade},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// handler implements watcher.NotifyHandler.
+type handler struct {
+	facade Facade
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (h *handler) SetUp() (watcher.NotifyWatcher, error) {
+	return h.facade.WatchRemoteRelations()
+}
+
+// Handle is part of the watcher.NotifyHandler interface. Failure to
+// synchronise stops the worker, so that it is restarted and the
+// synchronisation retried.
+func (h *handler) Handle(_ <-chan struct{}) error {
+	return errors.Annotate(h.facade.SyncRemoteRelations(), "cannot synchronise remote relations")
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (h *handler) TearDown() error {
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations_test
+
+import (
+	"errors"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/tomb"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/remoterelations"
+)
+
+type remoteRelationsSuite struct {
+	coretesting.BaseSuite
+	facade *mockFacade
+}
+
+var _ = gc.Suite(&remoteRelationsSuite{})
+
+func (s *remoteRelationsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &mockFacade{
+		calls:   make(chan string, 10),
+		watcher: newMockNotifyWatcher(),
+	}
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(s.facade.watcher), jc.ErrorIsNil)
+	})
+}
+
+func (s *remoteRelationsSuite) assertCalled(c *gc.C, expect string) {
+	select {
+	case call := <-s.facade.calls:
+		c.Assert(call, gc.Equals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for %s", expect)
+	}
+}
+
+func (s *remoteRelationsSuite) TestSyncsOnChange(c *gc.C) {
+	w, err := remoterelations.New(s.facade)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.assertCalled(c, "WatchRemoteRelations")
+	s.assertCalled(c, "SyncRemoteRelations")
+
+	s.facade.watcher.changes <- struct{}{}
+	s.assertCalled(c, "SyncRemoteRelations")
+}
+
+func (s *remoteRelationsSuite) TestSyncError(c *gc.C) {
+	s.facade.syncErr = errors.New("boom")
+	w, err := remoterelations.New(s.facade)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertCalled(c, "WatchRemoteRelations")
+	s.assertCalled(c, "SyncRemoteRelations")
+	err = w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot synchronise remote relations: boom")
+}
+
+type mockFacade struct {
+	watcher *mockNotifyWatcher
+	calls   chan string
+	syncErr error
+}
+
+func (f *mockFacade) SyncRemoteRelations() error {
+	f.calls <- "SyncRemoteRelations"
+	return f.syncErr
+}
+
+func (f *mockFacade) WatchRemoteRelations() (watcher.NotifyWatcher, error) {
+	f.calls <- "WatchRemoteRelations"
+	return f.watcher, nil
+}
+
+type mockNotifyWatcher struct {
+	tomb    tomb.Tomb
+	changes chan struct{}
+}
+
+func newMockNotifyWatcher() *mockNotifyWatcher {
+	w := &mockNotifyWatcher{changes: make(chan struct{}, 1)}
+	w.changes <- struct{}{}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return w.changes
+}
@@ -119,6 +119,17 @@ func setStatus(ctx *context, statuses []params.EntityStatusArgs) {
 	}
 }
 
+// isSharedFilesystemProvider reports whether or not the storage provider
+// with the specified type supports shared filesystems. Unknown providers
+// are reported as not supporting shared filesystems.
+func isSharedFilesystemProvider(providerType storage.ProviderType) bool {
+	provider, err := registry.StorageProvider(providerType)
+	if err != nil {
+		return false
+	}
+	return storage.SupportsSharedFilesystems(provider)
+}
+
 var errNonDynamic = errors.New("non-dynamic storage provider")
 
 // volumeSource returns a volume source given a name, provider type,
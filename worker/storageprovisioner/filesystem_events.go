@@ -79,6 +79,12 @@ func filesystemAttachmentsChanged(ctx *context, watcherIds []watcher.MachineStor
 		// attachments go directly from Dying to removed.
 		logger.Warningf("unexpected dead filesystem attachments: %v", dead)
 	}
+	if alive, err = filterFilesystemAttachments(ctx, alive); err != nil {
+		return errors.Trace(err)
+	}
+	if dying, err = filterFilesystemAttachments(ctx, dying); err != nil {
+		return errors.Trace(err)
+	}
 	if len(alive)+len(dying) == 0 {
 		return nil
 	}
@@ -106,6 +112,57 @@ func filesystemAttachmentsChanged(ctx *context, watcherIds []watcher.MachineStor
 	return nil
 }
 
+// filterFilesystemAttachments returns the filesystem attachments, out of
+// those specified, that are attached and detached by this storage
+// provisioner. Shared filesystems are model-scoped, but they are
+// attached and detached by the storage provisioner of each machine
+// they are attached to, rather than by the model's.
+func filterFilesystemAttachments(ctx *context, ids []params.MachineStorageId) ([]params.MachineStorageId, error) {
+	var modelScoped []params.MachineStorageId
+	for _, id := range ids {
+		filesystemTag, err := names.ParseFilesystemTag(id.AttachmentTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, ok := names.FilesystemMachine(filesystemTag); !ok {
+			modelScoped = append(modelScoped, id)
+		}
+	}
+	if len(modelScoped) == 0 {
+		return ids, nil
+	}
+	attachmentParams, err := filesystemAttachmentParams(ctx, modelScoped)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	_, machineScope := ctx.config.Scope.(names.MachineTag)
+	skip := make(map[params.MachineStorageId]bool)
+	for i, params := range attachmentParams {
+		if isSharedFilesystemProvider(params.Provider) != machineScope {
+			skip[modelScoped[i]] = true
+		}
+	}
+	filtered := make([]params.MachineStorageId, 0, len(ids))
+	for _, id := range ids {
+		if !skip[id] {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered, nil
+}
+
+// attachesSharedFilesystem reports whether or not the filesystem with the
+// specified tag is a shared filesystem attached by this storage provisioner.
+// Shared filesystems are created by the model's storage provisioner, so
+// a machine's storage provisioner does not learn of them being provisioned.
+func attachesSharedFilesystem(ctx *context, tag names.FilesystemTag) bool {
+	if _, ok := ctx.config.Scope.(names.MachineTag); !ok {
+		return false
+	}
+	_, machineScoped := names.FilesystemMachine(tag)
+	return !machineScoped
+}
+
 // processDyingFilesystems processes the FilesystemResults for Dying filesystems,
 // removing them from provisioning-pending as necessary.
 func processDyingFilesystems(ctx *context, tags []names.FilesystemTag, filesystemResults []params.FilesystemResult) error {
@@ -158,9 +215,13 @@ func updatePendingFilesystemAttachment(
 	params storage.FilesystemAttachmentParams,
 ) {
 	var incomplete bool
+	// Shared filesystems are not in ctx.filesystems; the attachment
+	// is scheduled even if the filesystem has not been provisioned
+	// yet, and attachFilesystems waits for it to be.
+	shared := attachesSharedFilesystem(ctx, params.Filesystem)
 	filesystem, ok := ctx.filesystems[params.Filesystem]
 	if !ok {
-		incomplete = true
+		incomplete = !shared
 	} else {
 		params.FilesystemId = filesystem.FilesystemId
 		if filesystem.Volume != (names.VolumeTag{}) {
@@ -177,7 +238,7 @@ func updatePendingFilesystemAttachment(
 		watchMachine(ctx, params.Machine)
 		incomplete = true
 	}
-	if params.FilesystemId == "" {
+	if params.FilesystemId == "" && !shared {
 		incomplete = true
 	}
 	if incomplete {
@@ -114,6 +114,36 @@ func createFilesystems(ctx *context, ops map[names.FilesystemTag]*createFilesyst
 
 // attachFilesystems creates filesystem attachments with the specified parameters.
 func attachFilesystems(ctx *context, ops map[params.MachineStorageId]*attachFilesystemOp) error {
+	// Shared filesystems may be scheduled for attachment before
+	// they have been provisioned; refresh their parameters, and
+	// reschedule any that are still not provisioned.
+	var reschedule []scheduleOp
+	var unprovisioned []params.MachineStorageId
+	for id, op := range ops {
+		if op.args.FilesystemId == "" {
+			unprovisioned = append(unprovisioned, id)
+		}
+	}
+	if len(unprovisioned) > 0 {
+		attachmentParams, err := filesystemAttachmentParams(ctx, unprovisioned)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for i, p := range attachmentParams {
+			id := unprovisioned[i]
+			if p.FilesystemId == "" {
+				logger.Debugf(
+					"%s is not provisioned yet, rescheduling attachment to %s",
+					names.ReadableString(p.Filesystem),
+					names.ReadableString(p.Machine),
+				)
+				reschedule = append(reschedule, ops[id])
+				delete(ops, id)
+				continue
+			}
+			ops[id].args = p
+		}
+	}
 	filesystemAttachmentParams := make([]storage.FilesystemAttachmentParams, 0, len(ops))
 	for _, op := range ops {
 		args := op.args
@@ -132,7 +162,6 @@ func attachFilesystems(ctx *context, ops map[params.MachineStorageId]*attachFile
 	if err != nil {
 		return errors.Trace(err)
 	}
-	var reschedule []scheduleOp
 	var filesystemAttachments []storage.FilesystemAttachment
 	var statuses []params.EntityStatusArgs
 	for sourceName, filesystemAttachmentParams := range paramsBySource {
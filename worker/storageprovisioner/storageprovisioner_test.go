@@ -1074,6 +1074,71 @@ func (s *storageProvisionerSuite) TestAttachVolumeBackedFilesystem(c *gc.C) {
 	}})
 }
 
+func (s *storageProvisionerSuite) TestAttachSharedFilesystem(c *gc.C) {
+	s.provider.shared = true
+	infoSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.setFilesystemAttachmentInfo = func(attachments []params.FilesystemAttachment) ([]params.ErrorResult, error) {
+		infoSet <- attachments
+		return nil, nil
+	}
+
+	args := &workerArgs{
+		scope:       names.NewMachineTag("0"),
+		filesystems: filesystemAccessor,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// The shared filesystem is model-scoped, and provisioned by the
+	// model's storage provisioner; the machine's storage provisioner
+	// attaches it using the filesystem ID in the attachment params.
+	filesystemAccessor.provisionFilesystem(names.NewFilesystemTag("1"))
+	filesystemAccessor.provisionedMachines["machine-0"] = instance.Id("already-provisioned-0")
+	args.environ.watcher.changes <- struct{}{}
+	filesystemAccessor.attachmentsWatcher.changes <- []watcher.MachineStorageId{{
+		MachineTag:    "machine-0",
+		AttachmentTag: "filesystem-1",
+	}}
+
+	info := waitChannel(
+		c, infoSet, "waiting for filesystem attachment info to be set",
+	).([]params.FilesystemAttachment)
+	c.Assert(info, jc.DeepEquals, []params.FilesystemAttachment{{
+		FilesystemTag: "filesystem-1",
+		MachineTag:    "machine-0",
+		Info: params.FilesystemAttachmentInfo{
+			MountPoint: "/srv/vol-1",
+		},
+	}})
+}
+
+func (s *storageProvisionerSuite) TestModelStorageProvisionerSkipsSharedFilesystemAttachments(c *gc.C) {
+	s.provider.shared = true
+	infoSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.setFilesystemAttachmentInfo = func(attachments []params.FilesystemAttachment) ([]params.ErrorResult, error) {
+		infoSet <- attachments
+		return nil, nil
+	}
+
+	args := &workerArgs{filesystems: filesystemAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	filesystemAccessor.provisionFilesystem(names.NewFilesystemTag("1"))
+	filesystemAccessor.provisionedMachines["machine-1"] = instance.Id("already-provisioned-1")
+	args.environ.watcher.changes <- struct{}{}
+	filesystemAccessor.filesystemsWatcher.changes <- []string{"1"}
+	filesystemAccessor.attachmentsWatcher.changes <- []watcher.MachineStorageId{{
+		MachineTag:    "machine-1",
+		AttachmentTag: "filesystem-1",
+	}}
+	assertNoEvent(c, infoSet, "filesystem attachment info set")
+}
+
 func (s *storageProvisionerSuite) TestUpdateModelConfig(c *gc.C) {
 	volumeAccessor := newMockVolumeAccessor()
 	volumeAccessor.provisionedMachines["machine-1"] = instance.Id("already-provisioned-1")
@@ -0,0 +1,242 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	corecharm "gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/worker/uniter/operation"
+)
+
+// agentStateUnit is the part of the unit API used to save and restore
+// the state of the unit's agent.
+type agentStateUnit interface {
+	AgentState() (map[string]string, error)
+	SetAgentState(map[string]string) error
+}
+
+// agentState keeps a copy of the uniter's local state files -- its
+// operation state, and its relation and storage state directories --
+// in the controller, so that the unit can be recovered on a fresh
+// machine. The local files remain the uniter's working copy: they are
+// saved to the controller after each operation, and restored from it
+// only when the operation state file is missing. Controllers that
+// cannot store agent state leave the local files as the only copy.
+type agentState struct {
+	unit  agentStateUnit
+	paths StatePaths
+
+	// saved holds the files last saved to or restored from the
+	// controller, keyed by their slash-separated paths relative
+	// to the state directory.
+	saved map[string]string
+
+	// unsupported is set when the controller cannot store agent
+	// state, after which nothing more is saved.
+	unsupported bool
+}
+
+func newAgentState(unit agentStateUnit, paths StatePaths) *agentState {
+	return &agentState{unit: unit, paths: paths}
+}
+
+// stateDir returns the directory holding the uniter's state files.
+func (s *agentState) stateDir() string {
+	return filepath.Dir(s.paths.OperationsFile)
+}
+
+// relPath returns the path, relative to the state directory, under
+// which the given local file is saved.
+func (s *agentState) relPath(path string) (string, error) {
+	rel, err := filepath.Rel(s.stateDir(), path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// read returns the contents of the local state files.
+func (s *agentState) read() (map[string]string, error) {
+	files := make(map[string]string)
+	add := func(path string) error {
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		rel, err := s.relPath(path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		files[rel] = string(data)
+		return nil
+	}
+	if err := add(s.paths.OperationsFile); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, dir := range []string{s.paths.RelationsDir, s.paths.StorageDir} {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			return add(path)
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return files, nil
+}
+
+// save saves the local state files to the controller, if they have
+// changed since they were last saved or restored.
+func (s *agentState) save() error {
+	if s.unsupported {
+		return nil
+	}
+	files, err := s.read()
+	if err != nil {
+		return errors.Annotate(err, "cannot read agent state")
+	}
+	if reflect.DeepEqual(files, s.saved) {
+		return nil
+	}
+	if err := s.unit.SetAgentState(files); errors.IsNotSupported(err) {
+		logger.Warningf("not saving agent state: %v", err)
+		s.unsupported = true
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot save agent state")
+	}
+	s.saved = files
+	return nil
+}
+
+// restore fetches the state saved in the controller and, if the local
+// operation state file is missing, writes the saved files to the state
+// directory. If the charm directory is missing too, as it is on a fresh
+// machine, the restored operation state is replaced with a queued
+// upgrade to the charm identified by getCharmURL, so that the charm is
+// deployed again without rerunning the install hook. A controller that
+// cannot store agent state is not an error.
+func (s *agentState) restore(getCharmURL func() (*corecharm.URL, error)) error {
+	saved, err := s.unit.AgentState()
+	if errors.IsNotSupported(err) {
+		logger.Warningf("not restoring agent state: %v", err)
+		s.unsupported = true
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot get saved agent state")
+	}
+	s.saved = saved
+	if _, err := os.Stat(s.paths.OperationsFile); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	opsPath, err := s.relPath(s.paths.OperationsFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := saved[opsPath]; !ok {
+		// The unit has not yet saved any operation state.
+		return nil
+	}
+
+	stateDir := s.stateDir()
+	for path, content := range saved {
+		local := filepath.Join(stateDir, filepath.FromSlash(path))
+		if !strings.HasPrefix(local, stateDir+string(filepath.Separator)) {
+			return errors.Errorf("invalid saved agent state path %q", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+			return errors.Trace(err)
+		}
+		if err := utils.AtomicWriteFile(local, []byte(content), 0644); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	logger.Infof("restored agent state from the controller")
+
+	if _, err := os.Stat(s.paths.CharmDir); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	return s.queueRedeploy(getCharmURL)
+}
+
+// queueRedeploy replaces the local operation state with a queued
+// upgrade to the charm identified by getCharmURL, unless the charm
+// has yet to be installed. A hook that was queued or running is run
+// once the charm is deployed, in place of the upgrade-charm hook.
+func (s *agentState) queueRedeploy(getCharmURL func() (*corecharm.URL, error)) error {
+	file := operation.NewStateFile(s.paths.OperationsFile)
+	st, err := file.Read()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if st.Kind == operation.Install {
+		return nil
+	}
+	charmURL, err := getCharmURL()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if st.Kind != operation.RunHook {
+		st.Hook = nil
+	}
+	st.Kind = operation.Upgrade
+	st.Step = operation.Queued
+	st.CharmURL = charmURL
+	st.ActionId = nil
+	st.HookTimedOut = false
+	logger.Infof("charm directory missing; queueing redeployment of %s", charmURL)
+	return errors.Trace(file.Write(st))
+}
+
+// agentStateExecutor wraps an operation.Executor, saving the unit
+// agent's state to the controller after each operation it runs or
+// skips.
+type agentStateExecutor struct {
+	operation.Executor
+	state *agentState
+}
+
+// Run is part of the operation.Executor interface.
+func (x *agentStateExecutor) Run(op operation.Operation) error {
+	return x.saveAfter(x.Executor.Run(op))
+}
+
+// Skip is part of the operation.Executor interface.
+func (x *agentStateExecutor) Skip(op operation.Operation) error {
+	return x.saveAfter(x.Executor.Skip(op))
+}
+
+// saveAfter saves the agent state, and returns opErr, which must be
+// returned unchanged for the resolver to interpret, if it is not nil.
+func (x *agentStateExecutor) saveAfter(opErr error) error {
+	if err := x.state.save(); err != nil {
+		if opErr != nil {
+			logger.Errorf("%v", err)
+			return opErr
+		}
+		return errors.Trace(err)
+	}
+	return opErr
+}
@@ -0,0 +1,253 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/charm.v6-unstable/hooks"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter"
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+)
+
+type agentStateSuite struct {
+	testing.BaseSuite
+	paths uniter.StatePaths
+	unit  *fakeAgentStateUnit
+}
+
+var _ = gc.Suite(&agentStateSuite{})
+
+func (s *agentStateSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.paths = uniter.NewPaths(c.MkDir(), names.NewUnitTag("mysql/0")).State
+	s.unit = &fakeAgentStateUnit{saved: map[string]string{}}
+}
+
+func (s *agentStateSuite) writeFile(c *gc.C, path, content string) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *agentStateSuite) assertFile(c *gc.C, path, content string) {
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, content)
+}
+
+func (s *agentStateSuite) TestSave(c *gc.C) {
+	s.writeFile(c, s.paths.OperationsFile, "op: continue\n")
+	s.writeFile(c, filepath.Join(s.paths.RelationsDir, "0", "wordpress-0"), "change-version: 1\n")
+	s.writeFile(c, filepath.Join(s.paths.StorageDir, "data-0"), "attached: true\n")
+	s.writeFile(c, filepath.Join(s.paths.DeployerDir, "current"), "not saved")
+
+	state := uniter.NewAgentState(s.unit, s.paths)
+	err := state.Save()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.saved, jc.DeepEquals, map[string]string{
+		"uniter":                  "op: continue\n",
+		"relations/0/wordpress-0": "change-version: 1\n",
+		"storage/data-0":          "attached: true\n",
+	})
+	c.Assert(s.unit.setCalls, gc.Equals, 1)
+
+	// Unchanged state is not saved again.
+	err = state.Save()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.setCalls, gc.Equals, 1)
+
+	err = os.RemoveAll(filepath.Join(s.paths.RelationsDir, "0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = state.Save()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.setCalls, gc.Equals, 2)
+	c.Assert(s.unit.saved, jc.DeepEquals, map[string]string{
+		"uniter":         "op: continue\n",
+		"storage/data-0": "attached: true\n",
+	})
+}
+
+func (s *agentStateSuite) TestRestoreKeepsLocalState(c *gc.C) {
+	s.unit.saved = map[string]string{"uniter": "op: run-hook\n"}
+	s.writeFile(c, s.paths.OperationsFile, "op: continue\n")
+
+	state := uniter.NewAgentState(s.unit, s.paths)
+	err := state.Restore(failGetCharmURL)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFile(c, s.paths.OperationsFile, "op: continue\n")
+
+	// The local state replaces that in the controller.
+	err = state.Save()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.saved, jc.DeepEquals, map[string]string{"uniter": "op: continue\n"})
+}
+
+func (s *agentStateSuite) TestSaveNotSupported(c *gc.C) {
+	s.unit.err = errors.NotSupportedf("agent state")
+	s.writeFile(c, s.paths.OperationsFile, "op: continue\n")
+
+	state := uniter.NewAgentState(s.unit, s.paths)
+	err := state.Save()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.setCalls, gc.Equals, 1)
+
+	// The controller is not asked again.
+	s.writeFile(c, s.paths.OperationsFile, "op: run-hook\n")
+	err = state.Save()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.setCalls, gc.Equals, 1)
+}
+
+func (s *agentStateSuite) TestRestoreNotSupported(c *gc.C) {
+	s.unit.err = errors.NotSupportedf("agent state")
+
+	state := uniter.NewAgentState(s.unit, s.paths)
+	err := state.Restore(failGetCharmURL)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(s.paths.OperationsFile)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+
+	s.writeFile(c, s.paths.OperationsFile, "op: continue\n")
+	err = state.Save()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.setCalls, gc.Equals, 0)
+}
+
+func (s *agentStateSuite) TestRestoreNothingSaved(c *gc.C) {
+	state := uniter.NewAgentState(s.unit, s.paths)
+	err := state.Restore(failGetCharmURL)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(s.paths.OperationsFile)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *agentStateSuite) TestRestore(c *gc.C) {
+	err := os.MkdirAll(s.paths.CharmDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit.saved = map[string]string{
+		"uniter":                  "op: continue\nopstep: pending\ninstalled: true\n",
+		"relations/0/wordpress-0": "change-version: 1\n",
+	}
+
+	state := uniter.NewAgentState(s.unit, s.paths)
+	err = state.Restore(failGetCharmURL)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFile(c, s.paths.OperationsFile, "op: continue\nopstep: pending\ninstalled: true\n")
+	s.assertFile(c, filepath.Join(s.paths.RelationsDir, "0", "wordpress-0"), "change-version: 1\n")
+
+	// The restored state is already saved.
+	err = state.Save()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.setCalls, gc.Equals, 0)
+}
+
+func (s *agentStateSuite) TestRestoreInvalidPath(c *gc.C) {
+	s.unit.saved = map[string]string{
+		"uniter":    "op: continue\nopstep: pending\n",
+		"../escape": "boom",
+	}
+	state := uniter.NewAgentState(s.unit, s.paths)
+	err := state.Restore(failGetCharmURL)
+	c.Assert(err, gc.ErrorMatches, `invalid saved agent state path "../escape"`)
+}
+
+func (s *agentStateSuite) restoreWithoutCharm(c *gc.C, saved *operation.State) *operation.State {
+	s.writeFile(c, s.paths.OperationsFile, "")
+	err := operation.NewStateFile(s.paths.OperationsFile).Write(saved)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(s.paths.OperationsFile)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Remove(s.paths.OperationsFile)
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit.saved = map[string]string{"uniter": string(data)}
+
+	state := uniter.NewAgentState(s.unit, s.paths)
+	err = state.Restore(func() (*charm.URL, error) {
+		return charm.MustParseURL("cs:quantal/mysql-2"), nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	restored, err := operation.NewStateFile(s.paths.OperationsFile).Read()
+	c.Assert(err, jc.ErrorIsNil)
+	return restored
+}
+
+func (s *agentStateSuite) TestRestoreWithoutCharmQueuesRedeploy(c *gc.C) {
+	restored := s.restoreWithoutCharm(c, &operation.State{
+		Kind:      operation.Continue,
+		Step:      operation.Pending,
+		Installed: true,
+		Started:   true,
+	})
+	c.Assert(restored, jc.DeepEquals, &operation.State{
+		Kind:      operation.Upgrade,
+		Step:      operation.Queued,
+		CharmURL:  charm.MustParseURL("cs:quantal/mysql-2"),
+		Installed: true,
+		Started:   true,
+	})
+}
+
+func (s *agentStateSuite) TestRestoreWithoutCharmKeepsHook(c *gc.C) {
+	restored := s.restoreWithoutCharm(c, &operation.State{
+		Kind:      operation.RunHook,
+		Step:      operation.Pending,
+		Hook:      &hook.Info{Kind: hooks.ConfigChanged},
+		Installed: true,
+	})
+	c.Assert(restored, jc.DeepEquals, &operation.State{
+		Kind:      operation.Upgrade,
+		Step:      operation.Queued,
+		Hook:      &hook.Info{Kind: hooks.ConfigChanged},
+		CharmURL:  charm.MustParseURL("cs:quantal/mysql-2"),
+		Installed: true,
+	})
+}
+
+func (s *agentStateSuite) TestRestoreWithoutCharmInstalling(c *gc.C) {
+	installing := &operation.State{
+		Kind:     operation.Install,
+		Step:     operation.Pending,
+		CharmURL: charm.MustParseURL("cs:quantal/mysql-1"),
+	}
+	restored := s.restoreWithoutCharm(c, installing)
+	c.Assert(restored, jc.DeepEquals, installing)
+}
+
+func failGetCharmURL() (*charm.URL, error) {
+	panic("unexpected charm URL request")
+}
+
+type fakeAgentStateUnit struct {
+	saved    map[string]string
+	setCalls int
+	err      error
+}
+
+func (u *fakeAgentStateUnit) AgentState() (map[string]string, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	return u.saved, nil
+}
+
+func (u *fakeAgentStateUnit) SetAgentState(files map[string]string) error {
+	u.setCalls++
+	if u.err != nil {
+		return u.err
+	}
+	u.saved = files
+	return nil
+}
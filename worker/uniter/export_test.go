@@ -0,0 +1,37 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	corecharm "gopkg.in/juju/charm.v6-unstable"
+)
+
+// AgentState exposes agentState for testing.
+type AgentState struct {
+	state *agentState
+}
+
+func NewAgentState(unit interface {
+	AgentState() (map[string]string, error)
+	SetAgentState(map[string]string) error
+}, paths StatePaths) AgentState {
+	return AgentState{newAgentState(unit, paths)}
+}
+
+func (s AgentState) Save() error {
+	return s.state.save()
+}
+
+func (s AgentState) Restore(getCharmURL func() (*corecharm.URL, error)) error {
+	return s.state.restore(getCharmURL)
+}
+
+// HookLockSetting exposes hookLockSetting for testing.
+type HookLockSetting struct {
+	*hookLockSetting
+}
+
+func NewHookLockSetting(st modelConfigGetter) HookLockSetting {
+	return HookLockSetting{&hookLockSetting{st: st}}
+}
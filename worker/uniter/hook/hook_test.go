@@ -50,6 +50,10 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.StorageAttached}, `invalid storage ID ""`},
 	{hook.Info{Kind: hooks.StorageAttached, StorageId: "data/0"}, ""},
 	{hook.Info{Kind: hooks.StorageDetaching, StorageId: "data/0"}, ""},
+	{hook.Info{Kind: hook.SecretRotate}, `"secret-rotate" hook requires a secret ID`},
+	{hook.Info{Kind: hook.SecretRotate, SecretId: "1"}, ""},
+	{hook.Info{Kind: hook.PayloadUnhealthy}, `"payload-unhealthy" hook requires a payload ID`},
+	{hook.Info{Kind: hook.PayloadUnhealthy, PayloadId: "spam/id0"}, ""},
 }
 
 func (s *InfoSuite) TestValidate(c *gc.C) {
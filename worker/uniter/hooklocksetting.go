@@ -0,0 +1,60 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/watcher"
+)
+
+// modelConfigGetter is the part of the uniter facade used to track the
+// model's per-unit-hook-lock setting.
+type modelConfigGetter interface {
+	ModelConfig() (*config.Config, error)
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+}
+
+// hookLockSetting caches the model's per-unit-hook-lock setting, and
+// refreshes it whenever the model config changes, so that the setting
+// need not be read from the API while the machine lock is held. It
+// implements watcher.NotifyHandler.
+type hookLockSetting struct {
+	st modelConfigGetter
+
+	mu      sync.Mutex
+	perUnit bool
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (s *hookLockSetting) SetUp() (watcher.NotifyWatcher, error) {
+	return s.st.WatchForModelConfigChanges()
+}
+
+// Handle is part of the watcher.NotifyHandler interface.
+func (s *hookLockSetting) Handle(_ <-chan struct{}) error {
+	cfg, err := s.st.ModelConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot read model config")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.perUnit = cfg.PerUnitHookLock()
+	return nil
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (s *hookLockSetting) TearDown() error {
+	return nil
+}
+
+// PerUnit returns the last seen value of the setting.
+func (s *hookLockSetting) PerUnit() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.perUnit
+}
@@ -0,0 +1,68 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/uniter"
+)
+
+type hookLockSettingSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&hookLockSettingSuite{})
+
+type fakeModelConfigGetter struct {
+	cfg *config.Config
+	err error
+}
+
+func (f *fakeModelConfigGetter) ModelConfig() (*config.Config, error) {
+	return f.cfg, f.err
+}
+
+func (f *fakeModelConfigGetter) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	return nil, errors.NotImplementedf("WatchForModelConfigChanges")
+}
+
+func (s *hookLockSettingSuite) TestHandleRefreshesSetting(c *gc.C) {
+	st := &fakeModelConfigGetter{cfg: testing.ModelConfig(c)}
+	setting := uniter.NewHookLockSetting(st)
+	c.Assert(setting.PerUnit(), jc.IsFalse)
+
+	err := setting.Handle(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(setting.PerUnit(), jc.IsFalse)
+
+	st.cfg, err = st.cfg.Apply(map[string]interface{}{
+		config.PerUnitHookLockKey: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = setting.Handle(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(setting.PerUnit(), jc.IsTrue)
+}
+
+func (s *hookLockSettingSuite) TestHandleKeepsSettingOnError(c *gc.C) {
+	cfg, err := testing.ModelConfig(c).Apply(map[string]interface{}{
+		config.PerUnitHookLockKey: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	st := &fakeModelConfigGetter{cfg: cfg}
+	setting := uniter.NewHookLockSetting(st)
+	err = setting.Handle(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	st.err = errors.New("boom")
+	err = setting.Handle(nil)
+	c.Assert(err, gc.ErrorMatches, "cannot read model config: boom")
+	c.Assert(setting.PerUnit(), jc.IsTrue)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/payload/health"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/fortress"
@@ -85,6 +86,15 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				UpdateStatusSignal:   NewUpdateStatusTimer(),
 				NewOperationExecutor: operation.NewExecutor,
 				Clock:                clock.WallClock,
+				NewPayloadMonitor: func(unhealthy chan<- string) (worker.Worker, error) {
+					return health.NewMonitor(health.MonitorConfig{
+						Client:          health.NewAPIClient(apiCaller),
+						Clock:           clock.WallClock,
+						RefreshInterval: health.DefaultRefreshInterval,
+						Check:           health.RunCheck,
+						Unhealthy:       unhealthy,
+					})
+				},
 			})
 			if err != nil {
 				return nil, errors.Trace(err)
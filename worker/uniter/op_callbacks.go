@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner"
 )
 
@@ -90,6 +91,29 @@ func (opc *operationCallbacks) NotifyHookFailed(hook string, ctx runner.Context)
 	}
 }
 
+// RecordExecution is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) RecordExecution(execution operation.Execution) {
+	err := opc.u.unit.RecordHookExecution(params.HookExecution{
+		Kind:       execution.Kind,
+		Name:       execution.Name,
+		RelationId: execution.RelationId,
+		RemoteUnit: execution.RemoteUnit,
+		Started:    execution.Started,
+		Finished:   execution.Finished,
+		Result:     execution.Result,
+		ExitCode:   execution.ExitCode,
+		StderrTail: execution.StderrTail,
+	})
+	if errors.IsNotSupported(err) {
+		// Older controllers keep no history.
+		logger.Debugf("not recording %s execution: %v", execution.Kind, err)
+	} else if err != nil {
+		// The history is only informational, so failing to add to
+		// it should not stop the unit from running.
+		logger.Warningf("cannot record %s execution: %v", execution.Kind, err)
+	}
+}
+
 // FailAction is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) FailAction(actionId, message string) error {
 	if !names.IsValidAction(actionId) {
@@ -0,0 +1,70 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"time"
+
+	"github.com/juju/juju/worker/uniter/runner"
+)
+
+// Execution kinds, as reported in Execution.Kind.
+const (
+	ExecutionHook     = "hook"
+	ExecutionAction   = "action"
+	ExecutionCommands = "commands"
+)
+
+// Execution results, as reported in Execution.Result.
+const (
+	ExecutionCompleted = "completed"
+	ExecutionFailed    = "failed"
+	ExecutionTimedOut  = "timed-out"
+)
+
+// Execution describes a hook, action or set of commands run by an
+// operation, as recorded in the unit's hook history.
+type Execution struct {
+	// Kind is one of ExecutionHook, ExecutionAction or
+	// ExecutionCommands.
+	Kind string
+
+	// Name holds the name of the hook or action run.
+	Name string
+
+	// RelationId and RemoteUnit hold the relation and remote unit
+	// the execution ran in the context of; RelationId is -1 if there
+	// was none.
+	RelationId int
+	RemoteUnit string
+
+	// Started and Finished hold the times the execution began and
+	// ended.
+	Started  time.Time
+	Finished time.Time
+
+	// Result is one of ExecutionCompleted, ExecutionFailed or
+	// ExecutionTimedOut.
+	Result string
+
+	// ExitCode and StderrTail describe how the process run ended.
+	ExitCode   int
+	StderrTail string
+}
+
+// newExecution returns an Execution, finishing now, describing the
+// process last run by the given runner.
+func newExecution(kind, name string, started time.Time, rnr runner.Runner, result string) Execution {
+	processResult := rnr.ProcessResult()
+	return Execution{
+		Kind:       kind,
+		Name:       name,
+		RelationId: -1,
+		Started:    started,
+		Finished:   time.Now(),
+		Result:     result,
+		ExitCode:   processResult.ExitCode,
+		StderrTail: processResult.StderrTail,
+	}
+}
@@ -26,16 +26,19 @@ var (
 )
 
 type executor struct {
-	file               *StateFile
-	state              *State
-	acquireMachineLock func(string) (func() error, error)
+	file        *StateFile
+	state       *State
+	acquireLock func(string, bool) (func() error, error)
 }
 
 // NewExecutor returns an Executor which takes its starting state from the
 // supplied path, and records state changes there. If no state file exists,
 // the executor's starting state will include a queued Install hook, for
-// the charm identified by the supplied func.
-func NewExecutor(stateFilePath string, getInstallCharm func() (*corecharm.URL, error), acquireLock func(string) (func() error, error)) (Executor, error) {
+// the charm identified by the supplied func. Before running an operation that
+// needs the machine lock, the executor calls acquireLock, passing whether the
+// operation needs the lock exclusively, and it calls the returned func once
+// the operation is done.
+func NewExecutor(stateFilePath string, getInstallCharm func() (*corecharm.URL, error), acquireLock func(string, bool) (func() error, error)) (Executor, error) {
 	file := NewStateFile(stateFilePath)
 	state, err := file.Read()
 	if err == ErrNoStateFile {
@@ -52,9 +55,9 @@ func NewExecutor(stateFilePath string, getInstallCharm func() (*corecharm.URL, e
 		return nil, err
 	}
 	return &executor{
-		file:               file,
-		state:              state,
-		acquireMachineLock: acquireLock,
+		file:        file,
+		state:       state,
+		acquireLock: acquireLock,
 	}, nil
 }
 
@@ -68,7 +71,8 @@ func (x *executor) Run(op Operation) (runErr error) {
 	logger.Debugf("running operation %v", op)
 
 	if op.NeedsGlobalMachineLock() {
-		unlock, err := x.acquireMachineLock(fmt.Sprintf("executing operation: %s", op.String()))
+		message := fmt.Sprintf("executing operation: %s", op.String())
+		unlock, err := x.acquireLock(message, op.NeedsExclusiveMachineLock())
 		if err != nil {
 			return errors.Annotate(err, "could not acquire lock")
 		}
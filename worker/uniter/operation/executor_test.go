@@ -29,7 +29,7 @@ func failGetInstallCharm() (*corecharm.URL, error) {
 	return nil, errors.New("lol!")
 }
 
-func failAcquireLock(string) (func() error, error) {
+func failAcquireLock(string, bool) (func() error, error) {
 	return nil, errors.New("wat")
 }
 
@@ -325,7 +325,7 @@ func (s *ExecutorSuite) TestFailCommitWithStateChange(c *gc.C) {
 	c.Assert(executor.State(), gc.DeepEquals, *op.commit.newState)
 }
 
-func (s *ExecutorSuite) initLockTest(c *gc.C, lockFunc func(string) (func() error, error)) operation.Executor {
+func (s *ExecutorSuite) initLockTest(c *gc.C, lockFunc func(string, bool) (func() error, error)) operation.Executor {
 
 	initialState := justInstalledState()
 	statePath := filepath.Join(c.MkDir(), "state")
@@ -358,6 +358,27 @@ func (s *ExecutorSuite) TestLockSucceedsStepsCalled(c *gc.C) {
 
 	expectedStepsOnUnlock := []bool{true, true, true}
 	c.Assert(mockLock.stepsCalledOnUnlock, gc.DeepEquals, expectedStepsOnUnlock)
+	c.Assert(mockLock.calledExclusive, jc.IsFalse)
+}
+
+func (s *ExecutorSuite) TestLockExclusive(c *gc.C) {
+	op := &mockOperation{
+		needsLock:      true,
+		needsExclusive: true,
+		prepare:        newStep(nil, nil),
+		execute:        newStep(nil, nil),
+		commit:         newStep(nil, nil),
+	}
+
+	mockLock := &mockLockFunc{op: op}
+	lockFunc := mockLock.newSucceedingLockUnlockSucceeds()
+	executor := s.initLockTest(c, lockFunc)
+
+	err := executor.Run(op)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mockLock.calledLock, jc.IsTrue)
+	c.Assert(mockLock.calledExclusive, jc.IsTrue)
+	c.Assert(mockLock.calledUnlock, jc.IsTrue)
 }
 
 func (s *ExecutorSuite) TestLockSucceedsStepsCalledUnlockFails(c *gc.C) {
@@ -496,12 +517,13 @@ type mockLockFunc struct {
 	noStepsCalledOnLock bool
 	stepsCalledOnUnlock []bool
 	calledLock          bool
+	calledExclusive     bool
 	calledUnlock        bool
 	op                  *mockOperation
 }
 
-func (mock *mockLockFunc) newFailingLock() func(string) (func() error, error) {
-	return func(string) (func() error, error) {
+func (mock *mockLockFunc) newFailingLock() func(string, bool) (func() error, error) {
+	return func(string, bool) (func() error, error) {
 		mock.noStepsCalledOnLock = mock.op.prepare.called == false &&
 			mock.op.commit.called == false
 		return nil, errors.New("wat")
@@ -509,9 +531,10 @@ func (mock *mockLockFunc) newFailingLock() func(string) (func() error, error) {
 
 }
 
-func (mock *mockLockFunc) newSucceedingLock(unlockFails bool) func(string) (func() error, error) {
-	return func(string) (func() error, error) {
+func (mock *mockLockFunc) newSucceedingLock(unlockFails bool) func(string, bool) (func() error, error) {
+	return func(_ string, exclusive bool) (func() error, error) {
 		mock.calledLock = true
+		mock.calledExclusive = exclusive
 		// Ensure that when we lock no operation has been called
 		mock.noStepsCalledOnLock = mock.op.prepare.called == false &&
 			mock.op.commit.called == false
@@ -529,11 +552,11 @@ func (mock *mockLockFunc) newSucceedingLock(unlockFails bool) func(string) (func
 	}
 }
 
-func (mock *mockLockFunc) newSucceedingLockUnlockFails() func(string) (func() error, error) {
+func (mock *mockLockFunc) newSucceedingLockUnlockFails() func(string, bool) (func() error, error) {
 	return mock.newSucceedingLock(true)
 }
 
-func (mock *mockLockFunc) newSucceedingLockUnlockSucceeds() func(string) (func() error, error) {
+func (mock *mockLockFunc) newSucceedingLockUnlockSucceeds() func(string, bool) (func() error, error) {
 	return mock.newSucceedingLock(false)
 }
 
@@ -555,10 +578,11 @@ func (step *mockStep) run(state operation.State) (*operation.State, error) {
 }
 
 type mockOperation struct {
-	needsLock bool
-	prepare   *mockStep
-	execute   *mockStep
-	commit    *mockStep
+	needsLock      bool
+	needsExclusive bool
+	prepare        *mockStep
+	execute        *mockStep
+	commit         *mockStep
 }
 
 func (op *mockOperation) String() string {
@@ -569,6 +593,10 @@ func (op *mockOperation) NeedsGlobalMachineLock() bool {
 	return op.needsLock
 }
 
+func (op *mockOperation) NeedsExclusiveMachineLock() bool {
+	return op.needsExclusive
+}
+
 func (op *mockOperation) Prepare(state operation.State) (*operation.State, error) {
 	return op.prepare.run(state)
 }
@@ -21,6 +21,7 @@ type FactoryParams struct {
 	StorageUpdater StorageUpdater
 	Abort          <-chan struct{}
 	MetricSpoolDir string
+	CharmDir       string
 }
 
 // NewFactory returns a Factory that creates Operations backed by the supplied
@@ -82,6 +83,7 @@ func (f *factory) NewRunHook(hookInfo hook.Info) (Operation, error) {
 		info:          hookInfo,
 		callbacks:     f.config.Callbacks,
 		runnerFactory: f.config.RunnerFactory,
+		charmDir:      f.config.CharmDir,
 	}, nil
 }
 
@@ -94,6 +96,21 @@ func (f *factory) NewSkipHook(hookInfo hook.Info) (Operation, error) {
 	return &skipOperation{hookOp}, nil
 }
 
+// NewTriggeredHook is part of the Factory interface.
+func (f *factory) NewTriggeredHook(name string, relationId int, remoteUnit string) (Operation, error) {
+	info, err := triggeredHookInfo(name, relationId, remoteUnit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &triggeredHook{
+		info:          info,
+		name:          name,
+		callbacks:     f.config.Callbacks,
+		runnerFactory: f.config.RunnerFactory,
+		charmDir:      f.config.CharmDir,
+	}, nil
+}
+
 // NewAction is part of the Factory interface.
 func (f *factory) NewAction(actionId string) (Operation, error) {
 	if !names.IsValidAction(actionId) {
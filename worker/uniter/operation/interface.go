@@ -28,6 +28,12 @@ type Operation interface {
 	// NeedsGlobalMachineLock returns a bool expressing whether we need to lock the machine.
 	NeedsGlobalMachineLock() bool
 
+	// NeedsExclusiveMachineLock returns a bool expressing whether, when
+	// the hooks of different units on the machine may run concurrently,
+	// the operation must still exclude all other hook execution on the
+	// machine. It is only consulted if NeedsGlobalMachineLock is true.
+	NeedsExclusiveMachineLock() bool
+
 	// Prepare ensures that the operation is valid and ready to be executed.
 	// If it returns a non-nil state, that state will be validated and recorded.
 	// If it returns ErrSkipExecute, it indicates that the operation can be
@@ -87,6 +93,11 @@ type Factory interface {
 	// completed successfully, without executing the hook.
 	NewSkipHook(hookInfo hook.Info) (Operation, error)
 
+	// NewTriggeredHook creates an operation to run the named hook, in
+	// the context of the given relation and remote unit, for a
+	// debug-code session, without changing the unit's state.
+	NewTriggeredHook(name string, relationId int, remoteUnit string) (Operation, error)
+
 	// NewAction creates an operation to execute the supplied action.
 	NewAction(actionId string) (Operation, error)
 
@@ -142,6 +153,11 @@ type Callbacks interface {
 	NotifyHookCompleted(string, runner.Context)
 	NotifyHookFailed(string, runner.Context)
 
+	// RecordExecution adds the supplied execution to the unit's hook
+	// history. It's used by RunHook, RunAction and RunCommands
+	// operations; failure to record is not fatal to the operation.
+	RecordExecution(Execution)
+
 	// The following methods exist primarily to allow us to test operation code
 	// without using a live api connection.
 
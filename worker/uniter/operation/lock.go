@@ -4,6 +4,15 @@
 
 package operation
 
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v2"
+)
+
 // DoesNotRequireMachineLock is embedded in the various operations to express whether
 // they need a global machine lock or not.
 type RequiresMachineLock struct{}
@@ -12,6 +21,10 @@ type RequiresMachineLock struct{}
 // It is embedded in the various operations.
 func (RequiresMachineLock) NeedsGlobalMachineLock() bool { return true }
 
+// NeedsExclusiveMachineLock is part of the Operation interface.
+// It is embedded in the various operations.
+func (RequiresMachineLock) NeedsExclusiveMachineLock() bool { return true }
+
 // DoesNotRequireMachineLock is embedded in the various operations to express whether
 // they need a global machine lock or not.
 type DoesNotRequireMachineLock struct{}
@@ -19,3 +32,40 @@ type DoesNotRequireMachineLock struct{}
 // NeedsGlobalMachineLock is part of the Operation interface.
 // It is embedded in the various operations.
 func (DoesNotRequireMachineLock) NeedsGlobalMachineLock() bool { return false }
+
+// NeedsExclusiveMachineLock is part of the Operation interface.
+// It is embedded in the various operations.
+func (DoesNotRequireMachineLock) NeedsExclusiveMachineLock() bool { return false }
+
+// MachineLockHooksFile is the name of the optional file, in the charm
+// directory, in which a charm declares the kinds of hook that must not
+// run concurrently with the hooks of other units on the machine, such
+// as those that install packages. It holds a list of hook kinds, for
+// example:
+//
+//     - install
+//     - upgrade-charm
+//
+// Relation and storage hooks are named by kind alone, such as
+// relation-changed, and so apply to every relation or store.
+const MachineLockHooksFile = "machine-lock-hooks.yaml"
+
+// readMachineLockHooks returns the kinds of hook that the charm in the
+// given directory declares need the whole machine.
+func readMachineLockHooks(charmDir string) (map[string]bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(charmDir, MachineLockHooksFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var kinds []string
+	if err := goyaml.Unmarshal(data, &kinds); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse %s", MachineLockHooksFile)
+	}
+	result := make(map[string]bool)
+	for _, kind := range kinds {
+		result[kind] = true
+	}
+	return result, nil
+}
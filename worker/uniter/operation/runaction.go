@@ -5,6 +5,7 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
@@ -28,6 +29,13 @@ func (ra *runAction) String() string {
 	return fmt.Sprintf("run action %s", ra.actionId)
 }
 
+// NeedsExclusiveMachineLock is part of the Operation interface. Actions
+// run under their unit's lock when the hooks of different units may run
+// concurrently.
+func (ra *runAction) NeedsExclusiveMachineLock() bool {
+	return false
+}
+
 // Prepare ensures that the action is valid and can be executed. If not, it
 // will return ErrSkipExecute. It preserves any hook recorded in the supplied
 // state.
@@ -72,12 +80,18 @@ func (ra *runAction) Execute(state State) (*State, error) {
 		return nil, err
 	}
 
+	started := time.Now()
 	err := ra.runner.RunAction(ra.name)
 	if err != nil {
 		// This indicates an actual error -- an action merely failing should
 		// be handled inside the Runner, and returned as nil.
 		return nil, errors.Annotatef(err, "running action %q", ra.name)
 	}
+	result := ExecutionCompleted
+	if actionData, err := ra.runner.Context().ActionData(); err == nil && actionData.Failed {
+		result = ExecutionFailed
+	}
+	ra.callbacks.RecordExecution(newExecution(ExecutionAction, ra.name, started, ra.runner, result))
 	return stateChange{
 		Kind:     RunAction,
 		Step:     Done,
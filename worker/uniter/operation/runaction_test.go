@@ -224,6 +224,10 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 		c.Assert(newState, jc.DeepEquals, &test.after)
 		c.Assert(callbacks.executingMessage, gc.Equals, "running action some-action-name")
 		c.Assert(*runnerFactory.MockNewActionRunner.runner.MockRunAction.gotName, gc.Equals, "some-action-name")
+		c.Assert(callbacks.executions, gc.HasLen, 1)
+		c.Assert(callbacks.executions[0].Kind, gc.Equals, operation.ExecutionAction)
+		c.Assert(callbacks.executions[0].Name, gc.Equals, "some-action-name")
+		c.Assert(callbacks.executions[0].Result, gc.Equals, operation.ExecutionCompleted)
 	}
 }
 
@@ -286,4 +290,5 @@ func (s *RunActionSuite) TestNeedsGlobalMachineLock(c *gc.C) {
 	op, err := factory.NewAction(someActionId)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(op.NeedsGlobalMachineLock(), jc.IsTrue)
+	c.Assert(op.NeedsExclusiveMachineLock(), jc.IsFalse)
 }
@@ -5,8 +5,10 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
+	utilexec "github.com/juju/utils/exec"
 
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
@@ -65,7 +67,9 @@ func (rc *runCommands) Execute(state State) (*State, error) {
 		return nil, errors.Trace(err)
 	}
 
+	started := time.Now()
 	response, err := rc.runner.RunCommands(rc.args.Commands)
+	rc.recordExecution(started, response, err)
 	switch err {
 	case context.ErrRequeueAndReboot:
 		logger.Warningf("cannot requeue external commands")
@@ -79,6 +83,24 @@ func (rc *runCommands) Execute(state State) (*State, error) {
 	return nil, err
 }
 
+// recordExecution records the commands' execution in the unit's hook
+// history.
+func (rc *runCommands) recordExecution(started time.Time, response *utilexec.ExecResponse, err error) {
+	result := ExecutionCompleted
+	switch err {
+	case nil, context.ErrReboot, context.ErrRequeueAndReboot:
+		if response == nil || response.Code != 0 {
+			result = ExecutionFailed
+		}
+	default:
+		result = ExecutionFailed
+	}
+	execution := newExecution(ExecutionCommands, "", started, rc.runner, result)
+	execution.RelationId = rc.args.RelationId
+	execution.RemoteUnit = rc.args.RemoteUnitName
+	rc.callbacks.RecordExecution(execution)
+}
+
 // Commit does nothing.
 // Commit is part of the Operation interface.
 func (rc *runCommands) Commit(state State) (*State, error) {
@@ -137,6 +137,8 @@ func (s *RunCommandsSuite) TestExecuteOtherError(c *gc.C) {
 	c.Assert(*runnerFactory.MockNewCommandRunner.runner.MockRunCommands.gotCommands, gc.Equals, "do something")
 	c.Assert(*sendResponse.gotResponse, gc.IsNil)
 	c.Assert(*sendResponse.gotErr, gc.ErrorMatches, "sneh")
+	c.Assert(callbacks.executions, gc.HasLen, 1)
+	c.Assert(callbacks.executions[0].Result, gc.Equals, operation.ExecutionFailed)
 }
 
 func (s *RunCommandsSuite) TestExecuteSuccess(c *gc.C) {
@@ -160,6 +162,12 @@ func (s *RunCommandsSuite) TestExecuteSuccess(c *gc.C) {
 	c.Assert(*runnerFactory.MockNewCommandRunner.runner.MockRunCommands.gotCommands, gc.Equals, "do something")
 	c.Assert(*sendResponse.gotResponse, gc.DeepEquals, &utilexec.ExecResponse{Code: 222})
 	c.Assert(*sendResponse.gotErr, jc.ErrorIsNil)
+	c.Assert(callbacks.executions, gc.HasLen, 1)
+	execution := callbacks.executions[0]
+	c.Assert(execution.Kind, gc.Equals, operation.ExecutionCommands)
+	c.Assert(execution.RelationId, gc.Equals, someCommandArgs.RelationId)
+	c.Assert(execution.RemoteUnit, gc.Equals, someCommandArgs.RemoteUnitName)
+	c.Assert(execution.Result, gc.Equals, operation.ExecutionFailed)
 }
 
 func (s *RunCommandsSuite) TestCommit(c *gc.C) {
@@ -178,4 +186,5 @@ func (s *RunCommandsSuite) TestNeedsGlobalMachineLock(c *gc.C) {
 	op, err := factory.NewCommands(someCommandArgs, sendResponse.Call)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(op.NeedsGlobalMachineLock(), jc.IsTrue)
+	c.Assert(op.NeedsExclusiveMachineLock(), jc.IsTrue)
 }
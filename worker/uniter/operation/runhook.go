@@ -5,6 +5,7 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
@@ -21,6 +22,7 @@ type runHook struct {
 
 	callbacks     Callbacks
 	runnerFactory runner.Factory
+	charmDir      string
 
 	name   string
 	runner runner.Runner
@@ -44,6 +46,18 @@ func (rh *runHook) String() string {
 	return fmt.Sprintf("run %s%s hook", rh.info.Kind, suffix)
 }
 
+// NeedsExclusiveMachineLock returns whether the charm declares that
+// hooks of this kind need the whole machine.
+// NeedsExclusiveMachineLock is part of the Operation interface.
+func (rh *runHook) NeedsExclusiveMachineLock() bool {
+	kinds, err := readMachineLockHooks(rh.charmDir)
+	if err != nil {
+		logger.Warningf("assuming %s hook needs the machine lock: %v", rh.info.Kind, err)
+		return true
+	}
+	return kinds[string(rh.info.Kind)]
+}
+
 // Prepare ensures the hook can be executed.
 // Prepare is part of the Operation interface.
 func (rh *runHook) Prepare(state State) (*State, error) {
@@ -91,6 +105,7 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	ranHook := true
 	step := Done
 
+	started := time.Now()
 	err := rh.runner.RunHook(rh.name)
 	cause := errors.Cause(err)
 	switch {
@@ -103,14 +118,26 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	case cause == context.ErrReboot:
 		err = ErrNeedsReboot
 	case err == nil:
+	case runner.IsHookTimeoutError(cause):
+		logger.Errorf("hook %q timed out: %v", rh.name, err)
+		rh.recordExecution(started, ExecutionTimedOut)
+		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
+		return stateChange{
+			Kind:         RunHook,
+			Step:         Pending,
+			Hook:         &rh.info,
+			HookTimedOut: true,
+		}.apply(state), ErrHookFailed
 	default:
 		logger.Errorf("hook %q failed: %v", rh.name, err)
+		rh.recordExecution(started, ExecutionFailed)
 		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
 		return nil, ErrHookFailed
 	}
 
 	if ranHook {
 		logger.Infof("ran %q hook", rh.name)
+		rh.recordExecution(started, ExecutionCompleted)
 		rh.callbacks.NotifyHookCompleted(rh.name, rh.runner.Context())
 	} else {
 		logger.Infof("skipped %q hook (missing)", rh.name)
@@ -129,6 +156,16 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	}.apply(state), err
 }
 
+// recordExecution records the hook's execution in the unit's hook history.
+func (rh *runHook) recordExecution(started time.Time, result string) {
+	execution := newExecution(ExecutionHook, rh.name, started, rh.runner, result)
+	if rh.info.Kind.IsRelation() {
+		execution.RelationId = rh.info.RelationId
+		execution.RemoteUnit = rh.info.RemoteUnit
+	}
+	rh.callbacks.RecordExecution(execution)
+}
+
 func (rh *runHook) beforeHook() error {
 	var err error
 	switch rh.info.Kind {
@@ -4,6 +4,10 @@
 package operation_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -12,6 +16,7 @@ import (
 
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -212,6 +217,7 @@ func (s *RunHookSuite) TestExecuteMissingHookError(c *gc.C) {
 		c.Assert(*runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "some-hook-name")
 		c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
 		c.Assert(callbacks.MockNotifyHookFailed.gotName, gc.IsNil)
+		c.Assert(callbacks.executions, gc.HasLen, 0)
 
 		status, err := runnerFactory.MockNewHookRunner.runner.Context().UnitStatus()
 		c.Assert(err, jc.ErrorIsNil)
@@ -260,6 +266,10 @@ func (s *RunHookSuite) TestExecuteRebootError(c *gc.C) {
 func (s *RunHookSuite) TestExecuteOtherError(c *gc.C) {
 	runErr := errors.New("graaargh")
 	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.ConfigChanged, runErr)
+	runnerFactory.MockNewHookRunner.runner.processResult = runner.ProcessResult{
+		ExitCode:   1,
+		StderrTail: "graaargh\n",
+	}
 	_, err := op.Prepare(operation.State{})
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -270,6 +280,39 @@ func (s *RunHookSuite) TestExecuteOtherError(c *gc.C) {
 	c.Assert(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "some-hook-name")
 	c.Assert(*callbacks.MockNotifyHookFailed.gotContext, gc.Equals, runnerFactory.MockNewHookRunner.runner.context)
 	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
+	c.Assert(callbacks.executions, gc.HasLen, 1)
+	execution := callbacks.executions[0]
+	c.Assert(execution.Finished.Before(execution.Started), jc.IsFalse)
+	execution.Started, execution.Finished = time.Time{}, time.Time{}
+	c.Assert(execution, jc.DeepEquals, operation.Execution{
+		Kind:       operation.ExecutionHook,
+		Name:       "some-hook-name",
+		RelationId: -1,
+		Result:     operation.ExecutionFailed,
+		ExitCode:   1,
+		StderrTail: "graaargh\n",
+	})
+}
+
+func (s *RunHookSuite) TestExecuteTimeoutError(c *gc.C) {
+	runErr := runner.NewHookTimeoutError("some-hook-name", time.Minute)
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.ConfigChanged, runErr)
+	_, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(operation.State{})
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, gc.DeepEquals, &operation.State{
+		Kind:         operation.RunHook,
+		Step:         operation.Pending,
+		Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+		HookTimedOut: true,
+	})
+	c.Assert(callbacks.executions, gc.HasLen, 1)
+	c.Assert(callbacks.executions[0].Result, gc.Equals, operation.ExecutionTimedOut)
+	c.Assert(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "some-hook-name")
+	c.Assert(*callbacks.MockNotifyHookFailed.gotContext, gc.Equals, runnerFactory.MockNewHookRunner.runner.context)
+	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
 }
 
 func (s *RunHookSuite) testExecuteSuccess(
@@ -285,6 +328,10 @@ func (s *RunHookSuite) testExecuteSuccess(
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(newState, gc.DeepEquals, &after)
 	c.Check(callbacks.executingMessage, gc.Equals, "running some-hook-name hook")
+	c.Assert(callbacks.executions, gc.HasLen, 1)
+	c.Check(callbacks.executions[0].Kind, gc.Equals, operation.ExecutionHook)
+	c.Check(callbacks.executions[0].Name, gc.Equals, "some-hook-name")
+	c.Check(callbacks.executions[0].Result, gc.Equals, operation.ExecutionCompleted)
 }
 
 func (s *RunHookSuite) TestExecuteSuccess_BlankSlate(c *gc.C) {
@@ -724,3 +771,41 @@ func (s *RunHookSuite) TestNeedsGlobalMachineLock_Run(c *gc.C) {
 func (s *RunHookSuite) TestNeedsGlobalMachineLock_Skip(c *gc.C) {
 	s.testNeedsGlobalMachineLock(c, (operation.Factory).NewSkipHook, false)
 }
+
+func (s *RunHookSuite) TestNeedsExclusiveMachineLock(c *gc.C) {
+	charmDir := c.MkDir()
+	factory := operation.NewFactory(operation.FactoryParams{CharmDir: charmDir})
+	needsExclusive := func(kind hooks.Kind) bool {
+		info := hook.Info{Kind: kind}
+		if kind.IsRelation() {
+			info.RelationId = 1
+			info.RemoteUnit = "mysql/0"
+		}
+		op, err := factory.NewRunHook(info)
+		c.Assert(err, jc.ErrorIsNil)
+		return op.NeedsExclusiveMachineLock()
+	}
+	c.Assert(needsExclusive(hooks.Install), jc.IsFalse)
+
+	err := ioutil.WriteFile(
+		filepath.Join(charmDir, operation.MachineLockHooksFile),
+		[]byte("- install\n- relation-joined\n"), 0644,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(needsExclusive(hooks.Install), jc.IsTrue)
+	c.Assert(needsExclusive(hooks.RelationJoined), jc.IsTrue)
+	c.Assert(needsExclusive(hooks.ConfigChanged), jc.IsFalse)
+
+	skip, err := factory.NewSkipHook(hook.Info{Kind: hooks.Install})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(skip.NeedsExclusiveMachineLock(), jc.IsFalse)
+
+	// A file that cannot be read is taken to mean the hook needs the
+	// whole machine.
+	err = ioutil.WriteFile(
+		filepath.Join(charmDir, operation.MachineLockHooksFile),
+		[]byte("install: true\n"), 0644,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(needsExclusive(hooks.ConfigChanged), jc.IsTrue)
+}
@@ -21,6 +21,11 @@ func (op *skipOperation) NeedsGlobalMachineLock() bool {
 	return false
 }
 
+// NeedsExclusiveMachineLock is part of the Operation interface.
+func (op *skipOperation) NeedsExclusiveMachineLock() bool {
+	return false
+}
+
 // Prepare is part of the Operation interface.
 func (op *skipOperation) Prepare(state State) (*State, error) {
 	return nil, ErrSkipExecute
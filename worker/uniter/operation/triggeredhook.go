@@ -0,0 +1,157 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable/hooks"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/runner"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+// triggeredHook runs a hook that the unit has been asked to run for a
+// debug-code session. Unlike runHook, it leaves the unit's local state
+// untouched, and its failure does not put the unit in an error state.
+type triggeredHook struct {
+	info hook.Info
+	name string
+
+	callbacks     Callbacks
+	runnerFactory runner.Factory
+	charmDir      string
+
+	runner runner.Runner
+
+	RequiresMachineLock
+}
+
+// triggeredHookInfo returns the hook.Info describing the named hook,
+// run in the context of the given relation and remote unit.
+func triggeredHookInfo(name string, relationId int, remoteUnit string) (hook.Info, error) {
+	for _, kind := range hooks.RelationHooks() {
+		if !strings.HasSuffix(name, "-"+string(kind)) {
+			continue
+		}
+		if relationId == -1 {
+			return hook.Info{}, errors.Errorf("%q hook requires a relation", name)
+		}
+		info := hook.Info{
+			Kind:       kind,
+			RelationId: relationId,
+			RemoteUnit: remoteUnit,
+		}
+		if err := info.Validate(); err != nil {
+			return hook.Info{}, errors.Trace(err)
+		}
+		return info, nil
+	}
+	if relationId != -1 || remoteUnit != "" {
+		return hook.Info{}, errors.Errorf("%q hook does not run in a relation context", name)
+	}
+	for _, kind := range hooks.UnitHooks() {
+		if string(kind) == name {
+			return hook.Info{Kind: kind}, nil
+		}
+	}
+	return hook.Info{}, errors.Errorf("cannot trigger %q hook", name)
+}
+
+// String is part of the Operation interface.
+func (th *triggeredHook) String() string {
+	suffix := ""
+	if th.info.Kind.IsRelation() {
+		if th.info.RemoteUnit == "" {
+			suffix = fmt.Sprintf(" (%d)", th.info.RelationId)
+		} else {
+			suffix = fmt.Sprintf(" (%d; %s)", th.info.RelationId, th.info.RemoteUnit)
+		}
+	}
+	return fmt.Sprintf("run triggered %s%s hook", th.name, suffix)
+}
+
+// NeedsExclusiveMachineLock returns whether the charm declares that
+// hooks of this kind need the whole machine.
+// NeedsExclusiveMachineLock is part of the Operation interface.
+func (th *triggeredHook) NeedsExclusiveMachineLock() bool {
+	kinds, err := readMachineLockHooks(th.charmDir)
+	if err != nil {
+		logger.Warningf("assuming %s hook needs the machine lock: %v", th.info.Kind, err)
+		return true
+	}
+	return kinds[string(th.info.Kind)]
+}
+
+// Prepare creates the hook's runner. A hook that cannot be run, for
+// example because its relation no longer exists, is logged and then
+// skipped rather than failing the operation. It never returns a state
+// change.
+// Prepare is part of the Operation interface.
+func (th *triggeredHook) Prepare(state State) (*State, error) {
+	rnr, err := th.runnerFactory.NewHookRunner(th.info)
+	if err == nil {
+		err = rnr.Context().Prepare()
+	}
+	if err != nil {
+		logger.Errorf("cannot run triggered %q hook: %v", th.name, err)
+		return nil, nil
+	}
+	th.runner = rnr
+	return nil, nil
+}
+
+// Execute runs the hook, recording it in the unit's hook history. It
+// never returns a state change, and a failed hook is not an error.
+// Execute is part of the Operation interface.
+func (th *triggeredHook) Execute(state State) (*State, error) {
+	if th.runner == nil {
+		return nil, nil
+	}
+	if err := th.callbacks.SetExecutingStatus(RunningHookMessage(th.name)); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	started := time.Now()
+	err := th.runner.RunHook(th.name)
+	cause := errors.Cause(err)
+	switch {
+	case context.IsMissingHookError(cause):
+		logger.Infof("skipped triggered %q hook (missing)", th.name)
+		return nil, nil
+	case cause == context.ErrReboot, cause == context.ErrRequeueAndReboot:
+		logger.Warningf("ignoring reboot requested by triggered %q hook", th.name)
+		th.recordExecution(started, ExecutionCompleted)
+	case err == nil:
+		logger.Infof("ran triggered %q hook", th.name)
+		th.recordExecution(started, ExecutionCompleted)
+	case runner.IsHookTimeoutError(cause):
+		logger.Errorf("triggered hook %q timed out: %v", th.name, err)
+		th.recordExecution(started, ExecutionTimedOut)
+	default:
+		logger.Errorf("triggered hook %q failed: %v", th.name, err)
+		th.recordExecution(started, ExecutionFailed)
+	}
+	return nil, nil
+}
+
+// recordExecution records the hook's execution in the unit's hook history.
+func (th *triggeredHook) recordExecution(started time.Time, result string) {
+	execution := newExecution(ExecutionHook, th.name, started, th.runner, result)
+	if th.info.Kind.IsRelation() {
+		execution.RelationId = th.info.RelationId
+		execution.RemoteUnit = th.info.RemoteUnit
+	}
+	th.callbacks.RecordExecution(execution)
+}
+
+// Commit does nothing.
+// Commit is part of the Operation interface.
+func (th *triggeredHook) Commit(state State) (*State, error) {
+	return nil, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable/hooks"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+type TriggeredHookSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&TriggeredHookSuite{})
+
+func (s *TriggeredHookSuite) TestNewTriggeredHookInfo(c *gc.C) {
+	for i, test := range []struct {
+		name       string
+		relationId int
+		remoteUnit string
+		info       hook.Info
+		err        string
+	}{{
+		name:       "config-changed",
+		relationId: -1,
+		info:       hook.Info{Kind: hooks.ConfigChanged},
+	}, {
+		name:       "db-relation-changed",
+		relationId: 3,
+		remoteUnit: "wordpress/0",
+		info:       hook.Info{Kind: hooks.RelationChanged, RelationId: 3, RemoteUnit: "wordpress/0"},
+	}, {
+		name:       "db-relation-broken",
+		relationId: 3,
+		info:       hook.Info{Kind: hooks.RelationBroken, RelationId: 3},
+	}, {
+		name:       "db-relation-changed",
+		relationId: -1,
+		err:        `"db-relation-changed" hook requires a relation`,
+	}, {
+		name:       "db-relation-joined",
+		relationId: 3,
+		err:        `"relation-joined" hook requires a remote unit`,
+	}, {
+		name:       "install",
+		relationId: 3,
+		err:        `"install" hook does not run in a relation context`,
+	}, {
+		name:       "data-storage-attached",
+		relationId: -1,
+		err:        `cannot trigger "data-storage-attached" hook`,
+	}, {
+		name:       "secret-rotate",
+		relationId: -1,
+		err:        `cannot trigger "secret-rotate" hook`,
+	}} {
+		c.Logf("test %d: %s", i, test.name)
+		runnerFactory := NewRunHookRunnerFactory(nil)
+		factory := operation.NewFactory(operation.FactoryParams{
+			RunnerFactory: runnerFactory,
+			Callbacks:     &RunCommandsCallbacks{},
+		})
+		op, err := factory.NewTriggeredHook(test.name, test.relationId, test.remoteUnit)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = op.Prepare(operation.State{})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(*runnerFactory.MockNewHookRunner.gotHook, jc.DeepEquals, test.info)
+	}
+}
+
+func (s *TriggeredHookSuite) TestString(c *gc.C) {
+	factory := operation.NewFactory(operation.FactoryParams{})
+	op, err := factory.NewTriggeredHook("install", -1, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(op.String(), gc.Equals, "run triggered install hook")
+	op, err = factory.NewTriggeredHook("db-relation-changed", 3, "wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(op.String(), gc.Equals, "run triggered db-relation-changed (3; wordpress/0) hook")
+}
+
+func (s *TriggeredHookSuite) TestPrepareErrorSkipsHook(c *gc.C) {
+	runnerFactory := &MockRunnerFactory{
+		MockNewHookRunner: &MockNewHookRunner{err: errors.New("no such relation")},
+	}
+	callbacks := &RunCommandsCallbacks{}
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: runnerFactory,
+		Callbacks:     callbacks,
+	})
+	op, err := factory.NewTriggeredHook("db-relation-changed", 3, "wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, gc.IsNil)
+	newState, err = op.Execute(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, gc.IsNil)
+	c.Assert(callbacks.executions, gc.HasLen, 0)
+}
+
+func (s *TriggeredHookSuite) TestExecute(c *gc.C) {
+	for i, test := range []struct {
+		runErr error
+		result string
+	}{{
+		result: operation.ExecutionCompleted,
+	}, {
+		runErr: errors.New("splat"),
+		result: operation.ExecutionFailed,
+	}, {
+		runErr: context.ErrReboot,
+		result: operation.ExecutionCompleted,
+	}} {
+		c.Logf("test %d", i)
+		runnerFactory := NewRunHookRunnerFactory(test.runErr)
+		callbacks := &RunCommandsCallbacks{}
+		factory := operation.NewFactory(operation.FactoryParams{
+			RunnerFactory: runnerFactory,
+			Callbacks:     callbacks,
+		})
+		op, err := factory.NewTriggeredHook("db-relation-changed", 3, "wordpress/0")
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = op.Prepare(operation.State{})
+		c.Assert(err, jc.ErrorIsNil)
+
+		// The unit's state is never changed, and a failed hook does
+		// not fail the operation.
+		newState, err := op.Execute(operation.State{})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(newState, gc.IsNil)
+		newState, err = op.Commit(operation.State{})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(newState, gc.IsNil)
+
+		c.Assert(*runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "db-relation-changed")
+		c.Assert(callbacks.executingMessage, gc.Equals, "running db-relation-changed hook")
+		c.Assert(callbacks.executions, gc.HasLen, 1)
+		execution := callbacks.executions[0]
+		c.Check(execution.Kind, gc.Equals, operation.ExecutionHook)
+		c.Check(execution.Name, gc.Equals, "db-relation-changed")
+		c.Check(execution.RelationId, gc.Equals, 3)
+		c.Check(execution.RemoteUnit, gc.Equals, "wordpress/0")
+		c.Check(execution.Result, gc.Equals, test.result)
+	}
+}
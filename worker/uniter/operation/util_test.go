@@ -124,6 +124,7 @@ type RunActionCallbacks struct {
 	operation.Callbacks
 	*MockFailAction
 	executingMessage string
+	executions       []operation.Execution
 }
 
 func (cb *RunActionCallbacks) FailAction(actionId, message string) error {
@@ -135,9 +136,14 @@ func (cb *RunActionCallbacks) SetExecutingStatus(message string) error {
 	return nil
 }
 
+func (cb *RunActionCallbacks) RecordExecution(execution operation.Execution) {
+	cb.executions = append(cb.executions, execution)
+}
+
 type RunCommandsCallbacks struct {
 	operation.Callbacks
 	executingMessage string
+	executions       []operation.Execution
 }
 
 func (cb *RunCommandsCallbacks) SetExecutingStatus(message string) error {
@@ -145,6 +151,10 @@ func (cb *RunCommandsCallbacks) SetExecutingStatus(message string) error {
 	return nil
 }
 
+func (cb *RunCommandsCallbacks) RecordExecution(execution operation.Execution) {
+	cb.executions = append(cb.executions, execution)
+}
+
 type MockPrepareHook struct {
 	gotHook *hook.Info
 	name    string
@@ -185,6 +195,7 @@ type ExecuteHookCallbacks struct {
 	*PrepareHookCallbacks
 	MockNotifyHookCompleted *MockNotify
 	MockNotifyHookFailed    *MockNotify
+	executions              []operation.Execution
 }
 
 func (cb *ExecuteHookCallbacks) NotifyHookCompleted(hookName string, ctx runner.Context) {
@@ -195,6 +206,10 @@ func (cb *ExecuteHookCallbacks) NotifyHookFailed(hookName string, ctx runner.Con
 	cb.MockNotifyHookFailed.Call(hookName, ctx)
 }
 
+func (cb *ExecuteHookCallbacks) RecordExecution(execution operation.Execution) {
+	cb.executions = append(cb.executions, execution)
+}
+
 type MockCommitHook struct {
 	gotHook *hook.Info
 	err     error
@@ -362,7 +377,8 @@ type MockRunner struct {
 	*MockRunAction
 	*MockRunCommands
 	*MockRunHook
-	context runner.Context
+	context       runner.Context
+	processResult runner.ProcessResult
 }
 
 func (r *MockRunner) Context() runner.Context {
@@ -377,6 +393,10 @@ func (r *MockRunner) RunCommands(commands string) (*utilexec.ExecResponse, error
 	return r.MockRunCommands.Call(commands)
 }
 
+func (r *MockRunner) ProcessResult() runner.ProcessResult {
+	return r.processResult
+}
+
 func (r *MockRunner) RunHook(hookName string) error {
 	r.Context().(*MockContext).setStatusCalled = r.MockRunHook.setStatusCalled
 	return r.MockRunHook.Call(hookName)
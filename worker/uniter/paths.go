@@ -25,6 +25,11 @@ type Paths struct {
 	// /var/lib/juju/agents/$UNIT_TAG/ )
 	ToolsDir string
 
+	// SharedDir is the directory holding data shared by all the units
+	// on the machine (e.g. the machine-level resource cache). Like
+	// ToolsDir, it does not point inside the unit agent's directory.
+	SharedDir string
+
 	// Runtime represents the set of paths that are relevant at runtime.
 	Runtime RuntimePaths
 
@@ -59,6 +64,13 @@ func (paths Paths) ComponentDir(name string) string {
 	return filepath.Join(paths.State.BaseDir, name)
 }
 
+// SharedComponentDir returns the filesystem path to the directory
+// containing data files for a component that are shared by all the
+// units on the machine.
+func (paths Paths) SharedComponentDir(name string) string {
+	return filepath.Join(paths.SharedDir, name)
+}
+
 // RuntimePaths represents the set of paths that are relevant at runtime.
 type RuntimePaths struct {
 
@@ -139,7 +151,8 @@ func NewWorkerPaths(dataDir string, unitTag names.UnitTag, worker string) Paths
 
 	toolsDir := tools.ToolsDir(dataDir, unitTag.String())
 	return Paths{
-		ToolsDir: filepath.FromSlash(toolsDir),
+		ToolsDir:  filepath.FromSlash(toolsDir),
+		SharedDir: join(dataDir, "shared"),
 		Runtime: RuntimePaths{
 			JujuRunSocket:     socket("run", false),
 			JujucServerSocket: socket("agent", true),
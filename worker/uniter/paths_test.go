@@ -38,7 +38,8 @@ func (s *PathsSuite) TestWindows(c *gc.C) {
 	relData := relPathFunc(dataDir)
 	relAgent := relPathFunc(relData("agents", "unit-some-service-323"))
 	c.Assert(paths, jc.DeepEquals, uniter.Paths{
-		ToolsDir: relData("tools/unit-some-service-323"),
+		ToolsDir:  relData("tools/unit-some-service-323"),
+		SharedDir: relData("shared"),
 		Runtime: uniter.RuntimePaths{
 			JujuRunSocket:     `\\.\pipe\unit-some-service-323-run`,
 			JujucServerSocket: `\\.\pipe\unit-some-service-323-agent`,
@@ -67,7 +68,8 @@ func (s *PathsSuite) TestWorkerPathsWindows(c *gc.C) {
 	relData := relPathFunc(dataDir)
 	relAgent := relPathFunc(relData("agents", "unit-some-service-323"))
 	c.Assert(paths, jc.DeepEquals, uniter.Paths{
-		ToolsDir: relData("tools/unit-some-service-323"),
+		ToolsDir:  relData("tools/unit-some-service-323"),
+		SharedDir: relData("shared"),
 		Runtime: uniter.RuntimePaths{
 			JujuRunSocket:     `\\.\pipe\unit-some-service-323-some-worker-run`,
 			JujucServerSocket: `\\.\pipe\unit-some-service-323-some-worker-agent`,
@@ -95,7 +97,8 @@ func (s *PathsSuite) TestOther(c *gc.C) {
 	relData := relPathFunc(dataDir)
 	relAgent := relPathFunc(relData("agents", "unit-some-service-323"))
 	c.Assert(paths, jc.DeepEquals, uniter.Paths{
-		ToolsDir: relData("tools/unit-some-service-323"),
+		ToolsDir:  relData("tools/unit-some-service-323"),
+		SharedDir: relData("shared"),
 		Runtime: uniter.RuntimePaths{
 			JujuRunSocket:     relAgent("run.socket"),
 			JujucServerSocket: "@" + relAgent("agent.socket"),
@@ -124,7 +127,8 @@ func (s *PathsSuite) TestWorkerPaths(c *gc.C) {
 	relData := relPathFunc(dataDir)
 	relAgent := relPathFunc(relData("agents", "unit-some-service-323"))
 	c.Assert(paths, jc.DeepEquals, uniter.Paths{
-		ToolsDir: relData("tools/unit-some-service-323"),
+		ToolsDir:  relData("tools/unit-some-service-323"),
+		SharedDir: relData("shared"),
 		Runtime: uniter.RuntimePaths{
 			JujuRunSocket:     relAgent(worker + "-run.socket"),
 			JujucServerSocket: "@" + relAgent(worker+"-agent.socket"),
@@ -144,7 +148,8 @@ func (s *PathsSuite) TestWorkerPaths(c *gc.C) {
 
 func (s *PathsSuite) TestContextInterface(c *gc.C) {
 	paths := uniter.Paths{
-		ToolsDir: "/path/to/tools",
+		ToolsDir:  "/path/to/tools",
+		SharedDir: "/path/to/shared",
 		Runtime: uniter.RuntimePaths{
 			JujucServerSocket: "/path/to/socket",
 		},
@@ -157,4 +162,5 @@ func (s *PathsSuite) TestContextInterface(c *gc.C) {
 	c.Assert(paths.GetCharmDir(), gc.Equals, "/path/to/charm")
 	c.Assert(paths.GetJujucSocket(), gc.Equals, "/path/to/socket")
 	c.Assert(paths.GetMetricsSpoolDir(), gc.Equals, "/path/to/spool/metrics")
+	c.Assert(paths.SharedComponentDir("spam"), gc.Equals, filepath.Join("/path/to/shared", "spam"))
 }
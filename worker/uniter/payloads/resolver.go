@@ -0,0 +1,73 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package payloads provides the resolver that runs the payload-unhealthy
+// hook for the unit's payloads whose health checks are failing.
+package payloads
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/remotestate"
+	"github.com/juju/juju/worker/uniter/resolver"
+)
+
+// payloadsResolver is a Resolver that returns operations to run the
+// payload-unhealthy hook for payloads that have become unhealthy. When
+// the hook is committed, the "payloadHandled" callback is invoked.
+type payloadsResolver struct {
+	payloadHandled func(id string)
+}
+
+// NewResolver returns a new Resolver that returns operations to run the
+// payload-unhealthy hook.
+//
+// The returned resolver's NextOp method will return an operation to run
+// the payload-unhealthy hook, for the first ID in the remote state's
+// "UnhealthyPayloads", while no other operation is in progress. When the
+// hook operation is committed, the full ID of the payload is passed to
+// the "payloadHandled" callback.
+func NewResolver(payloadHandled func(string)) resolver.Resolver {
+	return &payloadsResolver{payloadHandled}
+}
+
+// NextOp is part of the resolver.Resolver interface.
+func (s *payloadsResolver) NextOp(
+	localState resolver.LocalState,
+	remoteState remotestate.Snapshot,
+	opFactory operation.Factory,
+) (operation.Operation, error) {
+	if len(remoteState.UnhealthyPayloads) == 0 {
+		return nil, resolver.ErrNoOperation
+	}
+	if localState.Kind != operation.Continue {
+		return nil, resolver.ErrNoOperation
+	}
+	id := remoteState.UnhealthyPayloads[0]
+	op, err := opFactory.NewRunHook(hook.Info{
+		Kind:      hook.PayloadUnhealthy,
+		PayloadId: id,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	payloadHandled := func() {
+		s.payloadHandled(id)
+	}
+	return &unhealthyCommitter{op, payloadHandled}, nil
+}
+
+type unhealthyCommitter struct {
+	operation.Operation
+	payloadHandled func()
+}
+
+func (c *unhealthyCommitter) Commit(st operation.State) (*operation.State, error) {
+	result, err := c.Operation.Commit(st)
+	if err == nil {
+		c.payloadHandled()
+	}
+	return result, err
+}
@@ -0,0 +1,98 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package payloads_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/payloads"
+	"github.com/juju/juju/worker/uniter/remotestate"
+	"github.com/juju/juju/worker/uniter/resolver"
+)
+
+type resolverSuite struct {
+	opFactory *mockOpFactory
+	handled   []string
+	resolver  resolver.Resolver
+}
+
+var _ = gc.Suite(&resolverSuite{})
+
+func (s *resolverSuite) SetUpTest(c *gc.C) {
+	s.opFactory = &mockOpFactory{}
+	s.handled = nil
+	s.resolver = payloads.NewResolver(func(id string) {
+		s.handled = append(s.handled, id)
+	})
+}
+
+var continueState = resolver.LocalState{
+	State: operation.State{Kind: operation.Continue},
+}
+
+func (s *resolverSuite) TestNoUnhealthyPayloads(c *gc.C) {
+	_, err := s.resolver.NextOp(continueState, remotestate.Snapshot{}, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *resolverSuite) TestOperationInProgress(c *gc.C) {
+	localState := resolver.LocalState{
+		State: operation.State{Kind: operation.RunHook, Step: operation.Pending},
+	}
+	_, err := s.resolver.NextOp(localState, remotestate.Snapshot{
+		UnhealthyPayloads: []string{"spam/id0"},
+	}, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *resolverSuite) TestPayloadUnhealthy(c *gc.C) {
+	op, err := s.resolver.NextOp(continueState, remotestate.Snapshot{
+		UnhealthyPayloads: []string{"spam/id0", "eggs/id1"},
+	}, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.opFactory.hookInfo, jc.DeepEquals, []hook.Info{
+		{Kind: hook.PayloadUnhealthy, PayloadId: "spam/id0"},
+	})
+	c.Assert(s.handled, gc.HasLen, 0)
+
+	_, err = op.Commit(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.handled, jc.DeepEquals, []string{"spam/id0"})
+}
+
+func (s *resolverSuite) TestCommitErrorNotHandled(c *gc.C) {
+	s.opFactory.commitErr = errors.New("Commit failed")
+	op, err := s.resolver.NextOp(continueState, remotestate.Snapshot{
+		UnhealthyPayloads: []string{"spam/id0"},
+	}, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = op.Commit(operation.State{})
+	c.Assert(err, gc.ErrorMatches, "Commit failed")
+	c.Assert(s.handled, gc.HasLen, 0)
+}
+
+type mockOpFactory struct {
+	operation.Factory
+	hookInfo  []hook.Info
+	commitErr error
+}
+
+func (f *mockOpFactory) NewRunHook(hookInfo hook.Info) (operation.Operation, error) {
+	f.hookInfo = append(f.hookInfo, hookInfo)
+	return &mockOp{commitErr: f.commitErr}, nil
+}
+
+type mockOp struct {
+	operation.Operation
+	commitErr error
+}
+
+func (op *mockOp) Commit(operation.State) (*operation.State, error) {
+	return nil, op.commitErr
+}
@@ -191,6 +191,10 @@ type mockUnit struct {
 	configSettingsWatcher *mockNotifyWatcher
 	storageWatcher        *mockStringsWatcher
 	actionWatcher         *mockStringsWatcher
+	secretsToRotate       []string
+	secretsToRotateErr    error
+	triggeredHook         *params.TriggeredHook
+	triggeredHookErr      error
 }
 
 func (u *mockUnit) Life() params.Life {
@@ -205,6 +209,14 @@ func (u *mockUnit) Resolved() (params.ResolvedMode, error) {
 	return u.resolved, nil
 }
 
+func (u *mockUnit) SecretsToRotate() ([]string, error) {
+	return u.secretsToRotate, u.secretsToRotateErr
+}
+
+func (u *mockUnit) TriggeredHook() (*params.TriggeredHook, error) {
+	return u.triggeredHook, u.triggeredHookErr
+}
+
 func (u *mockUnit) Service() (remotestate.Service, error) {
 	return &u.service, nil
 }
@@ -64,6 +64,20 @@ type Snapshot struct {
 	// Commands is the list of IDs of commands to be
 	// executed by this unit.
 	Commands []string
+
+	// SecretsToRotate is the list of IDs of secrets, owned
+	// by the service, that are due to be rotated. It is
+	// only populated while the unit is the leader.
+	SecretsToRotate []string
+
+	// TriggeredHook is the hook the unit has been asked to run
+	// for a debug-code session, if any.
+	TriggeredHook *params.TriggeredHook
+
+	// UnhealthyPayloads is the list of full IDs of payloads that
+	// have become unhealthy and for which the payload-unhealthy
+	// hook has yet to be run.
+	UnhealthyPayloads []string
 }
 
 type RelationSnapshot struct {
@@ -25,7 +25,9 @@ type Unit interface {
 	Life() params.Life
 	Refresh() error
 	Resolved() (params.ResolvedMode, error)
+	SecretsToRotate() ([]string, error)
 	Service() (Service, error)
+	TriggeredHook() (*params.TriggeredHook, error)
 	Tag() names.UnitTag
 	Watch() (watcher.NotifyWatcher, error)
 	WatchAddresses() (watcher.NotifyWatcher, error)
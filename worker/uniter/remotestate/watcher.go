@@ -35,6 +35,8 @@ type RemoteStateWatcher struct {
 	updateStatusChannel       func() <-chan time.Time
 	commandChannel            <-chan string
 	retryHookChannel          <-chan struct{}
+	payloadUnhealthyChannel   <-chan string
+	triggeredHookPollChannel  func() <-chan time.Time
 
 	catacomb catacomb.Catacomb
 
@@ -46,12 +48,18 @@ type RemoteStateWatcher struct {
 // WatcherConfig holds configuration parameters for the
 // remote state watcher.
 type WatcherConfig struct {
-	State               State
-	LeadershipTracker   leadership.Tracker
-	UpdateStatusChannel func() <-chan time.Time
-	CommandChannel      <-chan string
-	RetryHookChannel    <-chan struct{}
-	UnitTag             names.UnitTag
+	State                   State
+	LeadershipTracker       leadership.Tracker
+	UpdateStatusChannel     func() <-chan time.Time
+	CommandChannel          <-chan string
+	RetryHookChannel        <-chan struct{}
+	PayloadUnhealthyChannel <-chan string
+	UnitTag                 names.UnitTag
+
+	// TriggeredHookPollChannel, if set, is used to generate signals
+	// while a triggered hook is pending, so that the hook is run
+	// once a debug session intercepting it has started.
+	TriggeredHookPollChannel func() <-chan time.Time
 }
 
 // NewWatcher returns a RemoteStateWatcher that handles state changes pertaining to the
@@ -67,6 +75,8 @@ func NewWatcher(config WatcherConfig) (*RemoteStateWatcher, error) {
 		updateStatusChannel:       config.UpdateStatusChannel,
 		commandChannel:            config.CommandChannel,
 		retryHookChannel:          config.RetryHookChannel,
+		payloadUnhealthyChannel:   config.PayloadUnhealthyChannel,
+		triggeredHookPollChannel:  config.TriggeredHookPollChannel,
 		// Note: it is important that the out channel be buffered!
 		// The remote state watcher will perform a non-blocking send
 		// on the channel to wake up the observer. It is non-blocking
@@ -119,6 +129,10 @@ func (w *RemoteStateWatcher) Snapshot() Snapshot {
 	copy(snapshot.Actions, w.current.Actions)
 	snapshot.Commands = make([]string, len(w.current.Commands))
 	copy(snapshot.Commands, w.current.Commands)
+	snapshot.SecretsToRotate = make([]string, len(w.current.SecretsToRotate))
+	copy(snapshot.SecretsToRotate, w.current.SecretsToRotate)
+	snapshot.UnhealthyPayloads = make([]string, len(w.current.UnhealthyPayloads))
+	copy(snapshot.UnhealthyPayloads, w.current.UnhealthyPayloads)
 	return snapshot
 }
 
@@ -128,6 +142,14 @@ func (w *RemoteStateWatcher) ClearResolvedMode() {
 	w.mu.Unlock()
 }
 
+// TriggeredHookRun removes the triggered hook from the snapshot, once
+// it has been run and cleared.
+func (w *RemoteStateWatcher) TriggeredHookRun() {
+	w.mu.Lock()
+	w.current.TriggeredHook = nil
+	w.mu.Unlock()
+}
+
 func (w *RemoteStateWatcher) CommandCompleted(completed string) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -143,6 +165,41 @@ func (w *RemoteStateWatcher) CommandCompleted(completed string) {
 	}
 }
 
+// SecretRotated removes the secret with the given ID from the list of
+// secrets to rotate, once the secret-rotate hook has been run for it.
+func (w *RemoteStateWatcher) SecretRotated(rotated string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, id := range w.current.SecretsToRotate {
+		if id != rotated {
+			continue
+		}
+		w.current.SecretsToRotate = append(
+			w.current.SecretsToRotate[:i],
+			w.current.SecretsToRotate[i+1:]...,
+		)
+		break
+	}
+}
+
+// PayloadUnhealthyHandled removes the payload with the given full ID
+// from the list of unhealthy payloads, once the payload-unhealthy hook
+// has been run for it.
+func (w *RemoteStateWatcher) PayloadUnhealthyHandled(handled string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, id := range w.current.UnhealthyPayloads {
+		if id != handled {
+			continue
+		}
+		w.current.UnhealthyPayloads = append(
+			w.current.UnhealthyPayloads[:i],
+			w.current.UnhealthyPayloads[i+1:]...,
+		)
+		break
+	}
+}
+
 func (w *RemoteStateWatcher) setUp(unitTag names.UnitTag) (err error) {
 	// TODO(dfc) named return value is a time bomb
 	// TODO(axw) move this logic.
@@ -283,7 +340,9 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 		return w.catacomb.ErrDying()
 	case <-claimLeader.Ready():
 		isLeader := claimLeader.Wait()
-		w.leadershipChanged(isLeader)
+		if err := w.leadershipChanged(isLeader); err != nil {
+			return errors.Trace(err)
+		}
 		if isLeader {
 			waitMinion = w.leadershipTracker.WaitMinion().Ready()
 		} else {
@@ -292,6 +351,11 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 		observedEvent(&seenLeadershipChange)
 	}
 
+	// While a triggered hook is pending, the remote state change is
+	// signalled periodically so that the hook runs once a debug
+	// session intercepting it has started.
+	var triggeredHookPoll <-chan time.Time
+
 	for {
 		select {
 		case <-w.catacomb.Dying():
@@ -422,6 +486,20 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 			if err := w.retryHookTimerTriggered(); err != nil {
 				return err
 			}
+
+		case id := <-w.payloadUnhealthyChannel:
+			logger.Debugf("payload unhealthy: %v", id)
+			if err := w.payloadUnhealthy(id); err != nil {
+				return err
+			}
+
+		case <-triggeredHookPoll:
+			logger.Tracef("triggered hook poll timer triggered")
+			triggeredHookPoll = nil
+		}
+
+		if triggeredHookPoll == nil && w.triggeredHookPollChannel != nil && w.triggeredHookPending() {
+			triggeredHookPoll = w.triggeredHookPollChannel()
 		}
 
 		// Something changed.
@@ -433,6 +511,29 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 func (w *RemoteStateWatcher) updateStatusChanged() error {
 	w.mu.Lock()
 	w.current.UpdateStatusVersion++
+	isLeader := w.current.Leader
+	w.mu.Unlock()
+	if isLeader {
+		// Secrets are due for rotation as time passes, rather than
+		// as a result of any change, so the leader checks for them
+		// periodically.
+		return w.secretsToRotateChanged()
+	}
+	return nil
+}
+
+// secretsToRotateChanged refreshes the list of secrets, owned by the
+// service, that are due to be rotated.
+func (w *RemoteStateWatcher) secretsToRotateChanged() error {
+	ids, err := w.unit.SecretsToRotate()
+	if errors.IsNotSupported(err) {
+		// The controller has no secrets to rotate.
+		ids = nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	w.mu.Lock()
+	w.current.SecretsToRotate = ids
 	w.mu.Unlock()
 	return nil
 }
@@ -445,6 +546,20 @@ func (w *RemoteStateWatcher) commandsChanged(id string) error {
 	return nil
 }
 
+// payloadUnhealthy is called when a payload's health check has
+// failed enough times for the payload to be considered unhealthy.
+func (w *RemoteStateWatcher) payloadUnhealthy(id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, existing := range w.current.UnhealthyPayloads {
+		if existing == id {
+			return nil
+		}
+	}
+	w.current.UnhealthyPayloads = append(w.current.UnhealthyPayloads, id)
+	return nil
+}
+
 // retryHookTimerTriggered is called when the retry hook timer expires.
 func (w *RemoteStateWatcher) retryHookTimerTriggered() error {
 	w.mu.Lock()
@@ -453,6 +568,14 @@ func (w *RemoteStateWatcher) retryHookTimerTriggered() error {
 	return nil
 }
 
+// triggeredHookPending reports whether the unit has been asked to run
+// a hook that it has not yet run.
+func (w *RemoteStateWatcher) triggeredHookPending() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current.TriggeredHook != nil
+}
+
 // unitChanged responds to changes in the unit.
 func (w *RemoteStateWatcher) unitChanged() error {
 	if err := w.unit.Refresh(); err != nil {
@@ -462,10 +585,18 @@ func (w *RemoteStateWatcher) unitChanged() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	triggeredHook, err := w.unit.TriggeredHook()
+	if errors.IsNotSupported(err) {
+		// The controller cannot trigger hooks.
+		triggeredHook = nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.current.Life = w.unit.Life()
 	w.current.ResolvedMode = resolved
+	w.current.TriggeredHook = triggeredHook
 	return nil
 }
 
@@ -509,7 +640,13 @@ func (w *RemoteStateWatcher) leaderSettingsChanged() error {
 func (w *RemoteStateWatcher) leadershipChanged(isLeader bool) error {
 	w.mu.Lock()
 	w.current.Leader = isLeader
+	if !isLeader {
+		w.current.SecretsToRotate = nil
+	}
 	w.mu.Unlock()
+	if isLeader {
+		return w.secretsToRotateChanged()
+	}
 	return nil
 }
 
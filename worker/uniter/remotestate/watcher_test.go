@@ -6,6 +6,7 @@ package remotestate_test
 import (
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -24,6 +25,9 @@ type WatcherSuite struct {
 	leadership *mockLeadershipTracker
 	watcher    *remotestate.RemoteStateWatcher
 	clock      *testing.Clock
+
+	payloadUnhealthy  chan string
+	triggeredHookPoll chan time.Time
 }
 
 // Duration is arbitrary, we'll trigger the ticker
@@ -69,11 +73,18 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 		return s.clock.After(statusTickDuration)
 	}
 
+	s.payloadUnhealthy = make(chan string)
+	s.triggeredHookPoll = make(chan time.Time)
+
 	w, err := remotestate.NewWatcher(remotestate.WatcherConfig{
-		State:               s.st,
-		LeadershipTracker:   s.leadership,
-		UnitTag:             s.st.unit.tag,
-		UpdateStatusChannel: statusTicker,
+		State:                   s.st,
+		LeadershipTracker:       s.leadership,
+		UnitTag:                 s.st.unit.tag,
+		UpdateStatusChannel:     statusTicker,
+		PayloadUnhealthyChannel: s.payloadUnhealthy,
+		TriggeredHookPollChannel: func() <-chan time.Time {
+			return s.triggeredHookPoll
+		},
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.watcher = w
@@ -206,6 +217,29 @@ func (s *WatcherSuite) TestClearResolvedMode(c *gc.C) {
 	c.Assert(snap.ResolvedMode, gc.Equals, params.ResolvedNone)
 }
 
+func (s *WatcherSuite) TestTriggeredHook(c *gc.C) {
+	hook := &params.TriggeredHook{Name: "db-relation-changed", RelationId: 3, RemoteUnit: "wordpress/0"}
+	s.st.unit.triggeredHook = hook
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().TriggeredHook, jc.DeepEquals, hook)
+
+	// While the hook is pending, the change is signalled periodically.
+	s.triggeredHookPoll <- time.Now()
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().TriggeredHook, jc.DeepEquals, hook)
+
+	s.watcher.TriggeredHookRun()
+	c.Assert(s.watcher.Snapshot().TriggeredHook, gc.IsNil)
+}
+
+func (s *WatcherSuite) TestTriggeredHookNotSupported(c *gc.C) {
+	s.st.unit.triggeredHookErr = errors.NotSupportedf("triggered hooks")
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().TriggeredHook, gc.IsNil)
+}
+
 func (s *WatcherSuite) TestLeadershipChanged(c *gc.C) {
 	s.leadership.claimTicket.result = false
 	signalAll(s.st, s.leadership)
@@ -221,6 +255,58 @@ func (s *WatcherSuite) TestLeadershipChanged(c *gc.C) {
 	c.Assert(s.watcher.Snapshot().Leader, jc.IsFalse)
 }
 
+func (s *WatcherSuite) TestSecretsToRotate(c *gc.C) {
+	s.st.unit.secretsToRotate = []string{"1"}
+	s.leadership.claimTicket.result = false
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().SecretsToRotate, gc.HasLen, 0)
+
+	// Secrets are only rotated by the leader.
+	s.leadership.leaderTicket.ch <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().SecretsToRotate, jc.DeepEquals, []string{"1"})
+
+	s.watcher.SecretRotated("1")
+	c.Assert(s.watcher.Snapshot().SecretsToRotate, gc.HasLen, 0)
+
+	// The leader checks for secrets due to be rotated periodically.
+	s.st.unit.secretsToRotate = []string{"1", "2"}
+	s.clock.Advance(statusTickDuration + 1)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().SecretsToRotate, jc.DeepEquals, []string{"1", "2"})
+
+	s.leadership.minionTicket.ch <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().SecretsToRotate, gc.HasLen, 0)
+}
+
+func (s *WatcherSuite) TestSecretsToRotateNotSupported(c *gc.C) {
+	s.st.unit.secretsToRotateErr = errors.NotSupportedf("secrets")
+	s.leadership.claimTicket.result = false
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	s.leadership.leaderTicket.ch <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().SecretsToRotate, gc.HasLen, 0)
+}
+
+func (s *WatcherSuite) TestUnhealthyPayloads(c *gc.C) {
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().UnhealthyPayloads, gc.HasLen, 0)
+
+	s.payloadUnhealthy <- "spam/id0"
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	s.payloadUnhealthy <- "spam/id0"
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().UnhealthyPayloads, jc.DeepEquals, []string{"spam/id0"})
+
+	s.watcher.PayloadUnhealthyHandled("spam/id0")
+	c.Assert(s.watcher.Snapshot().UnhealthyPayloads, gc.HasLen, 0)
+}
+
 func (s *WatcherSuite) TestLeadershipMinionUnchanged(c *gc.C) {
 	s.leadership.claimTicket.result = false
 	signalAll(s.st, s.leadership)
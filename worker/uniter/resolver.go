@@ -4,6 +4,8 @@
 package uniter
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
@@ -26,6 +28,14 @@ type ResolverConfig struct {
 	Relations           resolver.Resolver
 	Storage             resolver.Resolver
 	Commands            resolver.Resolver
+	Secrets             resolver.Resolver
+	Payloads            resolver.Resolver
+
+	// TriggeredHookIntercepted reports whether a debug session is
+	// intercepting the named hook; a triggered hook is only run once
+	// one is. ClearTriggeredHook clears the hook once it has run.
+	TriggeredHookIntercepted func(hookName string) bool
+	ClearTriggeredHook       func() error
 }
 
 type uniterResolver struct {
@@ -95,6 +105,11 @@ func (s *uniterResolver) NextOp(
 		return op, err
 	}
 
+	op, err = s.nextOpTriggeredHook(localState, remoteState, opFactory)
+	if errors.Cause(err) != resolver.ErrNoOperation {
+		return op, err
+	}
+
 	op, err = s.config.Storage.NextOp(localState, remoteState, opFactory)
 	if errors.Cause(err) != resolver.ErrNoOperation {
 		return op, err
@@ -212,6 +227,75 @@ func (s *uniterResolver) nextOpHookError(
 	}
 }
 
+// nextOpTriggeredHook returns an operation to run the hook the unit
+// has been asked to run for a debug-code session, once a session is
+// intercepting it. If the unit is in an error state because that very
+// hook failed, the hook is retried as if the error were resolved with
+// retry; otherwise it is run without changing the unit's state.
+func (s *uniterResolver) nextOpTriggeredHook(
+	localState resolver.LocalState,
+	remoteState remotestate.Snapshot,
+	opFactory operation.Factory,
+) (operation.Operation, error) {
+	triggered := remoteState.TriggeredHook
+	if triggered == nil || !s.config.TriggeredHookIntercepted(triggered.Name) {
+		return nil, resolver.ErrNoOperation
+	}
+	hookError := localState.Kind == operation.RunHook && localState.Step == operation.Pending
+	if hookError && triggeredHookFailed(*triggered, *localState.Hook) {
+		s.config.StopRetryHookTimer()
+		s.retryHookTimerStarted = false
+		if err := s.config.ClearTriggeredHook(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return opFactory.NewRunHook(*localState.Hook)
+	}
+	if !hookError && localState.Kind != operation.Continue {
+		return nil, resolver.ErrNoOperation
+	}
+	op, err := opFactory.NewTriggeredHook(triggered.Name, triggered.RelationId, triggered.RemoteUnit)
+	if err != nil {
+		// The hook can never be run, so there is no point in
+		// waiting for it.
+		logger.Errorf("cannot run triggered hook: %v", err)
+		if err := s.config.ClearTriggeredHook(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, resolver.ErrNoOperation
+	}
+	return &triggeredHookCommitter{op, s.config.ClearTriggeredHook}, nil
+}
+
+// triggeredHookFailed reports whether the triggered hook is the failed
+// hook described by info.
+func triggeredHookFailed(triggered params.TriggeredHook, info hook.Info) bool {
+	if !info.Kind.IsRelation() {
+		return triggered.Name == string(info.Kind) && triggered.RelationId == -1
+	}
+	return strings.HasSuffix(triggered.Name, "-"+string(info.Kind)) &&
+		triggered.RelationId == info.RelationId &&
+		triggered.RemoteUnit == info.RemoteUnit
+}
+
+// triggeredHookCommitter clears the triggered hook when the operation
+// running it is committed.
+type triggeredHookCommitter struct {
+	operation.Operation
+	clearTriggeredHook func() error
+}
+
+// Commit is part of the operation.Operation interface.
+func (c *triggeredHookCommitter) Commit(st operation.State) (*operation.State, error) {
+	result, err := c.Operation.Commit(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := c.clearTriggeredHook(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result, nil
+}
+
 func (s *uniterResolver) nextOp(
 	localState resolver.LocalState,
 	remoteState remotestate.Snapshot,
@@ -266,6 +350,16 @@ func (s *uniterResolver) nextOp(
 		return op, err
 	}
 
+	op, err = s.config.Secrets.NextOp(localState, remoteState, opFactory)
+	if errors.Cause(err) != resolver.ErrNoOperation {
+		return op, err
+	}
+
+	op, err = s.config.Payloads.NextOp(localState, remoteState, opFactory)
+	if errors.Cause(err) != resolver.ErrNoOperation {
+		return op, err
+	}
+
 	// UpdateStatus hook runs if nothing else needs to.
 	if localState.UpdateStatusVersion != remoteState.UpdateStatusVersion {
 		return opFactory.NewRunHook(hook.Info{Kind: hooks.UpdateStatus})
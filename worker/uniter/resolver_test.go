@@ -33,6 +33,7 @@ type resolverSuite struct {
 
 	clearResolved   func() error
 	reportHookError func(hook.Info) error
+	intercepted     bool
 }
 
 var _ = gc.Suite(&resolverSuite{})
@@ -67,9 +68,116 @@ func (s *resolverSuite) SetUpTest(c *gc.C) {
 		Relations:           relation.NewRelationsResolver(&dummyRelations{}),
 		Storage:             storage.NewResolver(attachments),
 		Commands:            nopResolver{},
+		Secrets:             nopResolver{},
+		Payloads:            nopResolver{},
+		TriggeredHookIntercepted: func(hookName string) bool {
+			s.stub.AddCall("TriggeredHookIntercepted", hookName)
+			return s.intercepted
+		},
+		ClearTriggeredHook: func() error {
+			s.stub.AddCall("ClearTriggeredHook")
+			return nil
+		},
 	})
 }
 
+func (s *resolverSuite) TestTriggeredHookNotIntercepted(c *gc.C) {
+	localState := resolver.LocalState{
+		CharmURL: s.charmURL,
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+		},
+	}
+	s.remoteState.TriggeredHook = &params.TriggeredHook{Name: "config-changed", RelationId: -1}
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckCallNames(c, "TriggeredHookIntercepted")
+}
+
+func (s *resolverSuite) TestTriggeredHook(c *gc.C) {
+	s.intercepted = true
+	localState := resolver.LocalState{
+		CharmURL: s.charmURL,
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+		},
+	}
+	s.remoteState.TriggeredHook = &params.TriggeredHook{Name: "db-relation-changed", RelationId: 3, RemoteUnit: "wordpress/0"}
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run triggered db-relation-changed (3; wordpress/0) hook")
+	s.stub.CheckCall(c, 0, "TriggeredHookIntercepted", "db-relation-changed")
+
+	// The trigger is cleared when the operation is committed.
+	_, err = op.Commit(localState.State)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCallNames(c, "TriggeredHookIntercepted", "ClearTriggeredHook")
+}
+
+func (s *resolverSuite) TestTriggeredHookInvalid(c *gc.C) {
+	s.intercepted = true
+	localState := resolver.LocalState{
+		CharmURL: s.charmURL,
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+		},
+	}
+	s.remoteState.TriggeredHook = &params.TriggeredHook{Name: "data-storage-attached", RelationId: -1}
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckCallNames(c, "TriggeredHookIntercepted", "ClearTriggeredHook")
+}
+
+func (s *resolverSuite) TestTriggeredHookRetriesFailedHook(c *gc.C) {
+	s.intercepted = true
+	localState := resolver.LocalState{
+		CharmURL: s.charmURL,
+		State: operation.State{
+			Kind:      operation.RunHook,
+			Step:      operation.Pending,
+			Installed: true,
+			Started:   true,
+			Hook: &hook.Info{
+				Kind:       hooks.RelationChanged,
+				RelationId: 3,
+				RemoteUnit: "wordpress/0",
+			},
+		},
+	}
+	s.remoteState.TriggeredHook = &params.TriggeredHook{Name: "db-relation-changed", RelationId: 3, RemoteUnit: "wordpress/0"}
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run relation-changed (3; wordpress/0) hook")
+	s.stub.CheckCallNames(c, "TriggeredHookIntercepted", "StopRetryHookTimer", "ClearTriggeredHook")
+}
+
+func (s *resolverSuite) TestTriggeredHookInHookError(c *gc.C) {
+	s.intercepted = true
+	localState := resolver.LocalState{
+		CharmURL: s.charmURL,
+		State: operation.State{
+			Kind:      operation.RunHook,
+			Step:      operation.Pending,
+			Installed: true,
+			Started:   true,
+			Hook:      &hook.Info{Kind: hooks.ConfigChanged},
+		},
+	}
+	// A hook other than the failed one is run without resolving
+	// the error.
+	s.remoteState.TriggeredHook = &params.TriggeredHook{Name: "start", RelationId: -1}
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run triggered start hook")
+	s.stub.CheckCallNames(c, "TriggeredHookIntercepted")
+}
+
 // TestStartedNotInstalled tests whether the Started flag overrides the
 // Installed flag being unset, in the event of an unexpected inconsistency in
 // local state.
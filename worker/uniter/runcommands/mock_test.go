@@ -34,6 +34,10 @@ func (r *mockRunner) RunCommands(commands string) (*exec.ExecResponse, error) {
 	return r.runCommands(commands)
 }
 
+func (r *mockRunner) ProcessResult() runner.ProcessResult {
+	return runner.ProcessResult{ExitCode: -1}
+}
+
 type mockRunnerContext struct {
 	runner.Context
 }
@@ -51,3 +55,7 @@ func (c *mockCallbacks) SetExecutingStatus(status string) error {
 	c.MethodCall(c, "SetExecutingStatus", status)
 	return c.NextErr()
 }
+
+func (c *mockCallbacks) RecordExecution(execution operation.Execution) {
+	c.MethodCall(c, "RecordExecution", execution)
+}
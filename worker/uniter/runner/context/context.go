@@ -48,6 +48,11 @@ type Paths interface {
 	// ComponentDir returns the filesystem path to the directory
 	// containing all data files for a component.
 	ComponentDir(name string) string
+
+	// SharedComponentDir returns the filesystem path to the directory
+	// containing data files for a component that are shared by all
+	// the units on the machine.
+	SharedComponentDir(name string) string
 }
 
 var logger = loggo.GetLogger("juju.worker.uniter.context")
@@ -61,6 +66,9 @@ type ComponentConfig struct {
 	UnitName string
 	// DataDir is the component's data directory.
 	DataDir string
+	// SharedDataDir is the component's machine-level data directory,
+	// shared by all the units on the machine.
+	SharedDataDir string
 	// APICaller is the API caller the component may use.
 	APICaller base.APICaller
 }
@@ -158,6 +166,10 @@ type HookContext struct {
 	// proxySettings are the current proxy settings that the uniter knows about.
 	proxySettings proxy.Settings
 
+	// hookTimeout is the model's default limit on how long a hook may
+	// run, or 0 if there is none.
+	hookTimeout time.Duration
+
 	// meterStatus is the status of the unit's metering.
 	meterStatus *meterStatus
 
@@ -188,6 +200,12 @@ type HookContext struct {
 	// storageId is the tag of the storage instance associated with the running hook.
 	storageTag names.StorageTag
 
+	// secretId is the ID of the secret associated with the running hook.
+	secretId string
+
+	// payloadId is the full ID of the payload associated with the running hook.
+	payloadId string
+
 	// hasRunSetStatus is true if a call to the status-set was made during the
 	// invocation of a hook.
 	// This attribute is persisted to local uniter state at the end of the hook
@@ -204,8 +222,9 @@ type HookContext struct {
 	// clock is used for any time operations.
 	clock clock.Clock
 
-	componentDir   func(string) string
-	componentFuncs map[string]ComponentFunc
+	componentDir       func(string) string
+	sharedComponentDir func(string) string
+	componentFuncs     map[string]ComponentFunc
 }
 
 // Component implements jujuc.Context.
@@ -217,9 +236,10 @@ func (ctx *HookContext) Component(name string) (jujuc.ContextComponent, error) {
 
 	facade := ctx.state.Facade()
 	config := ComponentConfig{
-		UnitName:  ctx.unit.Name(),
-		DataDir:   ctx.componentDir(name),
-		APICaller: facade.RawAPICaller(),
+		UnitName:      ctx.unit.Name(),
+		DataDir:       ctx.componentDir(name),
+		SharedDataDir: ctx.sharedComponentDir(name),
+		APICaller:     facade.RawAPICaller(),
 	}
 	compCtx, err := compCtxFunc(config)
 	if err != nil {
@@ -383,6 +403,12 @@ func (ctx *HookContext) ResetExecutionSetUnitStatus() {
 	ctx.hasRunStatusSet = false
 }
 
+// HookTimeout returns the model's default limit on how long a hook may
+// run, or 0 if there is none.
+func (ctx *HookContext) HookTimeout() time.Duration {
+	return ctx.hookTimeout
+}
+
 func (ctx *HookContext) PublicAddress() (string, error) {
 	if ctx.publicAddress == "" {
 		return "", errors.NotFoundf("public address")
@@ -474,6 +500,55 @@ func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	return result, nil
 }
 
+// SetUnitWorkloadVersion records the version of the workload software
+// running on the unit.
+func (ctx *HookContext) SetUnitWorkloadVersion(version string) error {
+	return ctx.unit.SetWorkloadVersion(version)
+}
+
+// GoalState returns the units expected for the unit's service, and for
+// each service related to it, with their status.
+func (ctx *HookContext) GoalState() (*params.GoalState, error) {
+	return ctx.unit.GoalState()
+}
+
+// AddSecret creates a secret owned by the unit's service and returns
+// its ID.
+func (ctx *HookContext) AddSecret(description string, data map[string]string, rotateInterval time.Duration) (string, error) {
+	return ctx.unit.CreateSecret(description, data, rotateInterval)
+}
+
+// SecretValue returns the content of the given revision of the secret
+// with the given ID, or of its current revision if revision is zero.
+func (ctx *HookContext) SecretValue(id string, revision int) (map[string]string, error) {
+	data, _, err := ctx.unit.SecretValue(id, revision)
+	return data, err
+}
+
+// UpdateSecret replaces the content of the secret with the given ID.
+func (ctx *HookContext) UpdateSecret(id string, data map[string]string) error {
+	return ctx.unit.UpdateSecret(id, data)
+}
+
+// GrantSecret gives the named unit access to the secret with the given
+// ID.
+func (ctx *HookContext) GrantSecret(id string, unitName string) error {
+	if !names.IsValidUnit(unitName) {
+		return errors.NotValidf("unit name %q", unitName)
+	}
+	return ctx.unit.GrantSecret(id, names.NewUnitTag(unitName))
+}
+
+// GrantSecretToRelation gives the units of the services in the relation
+// with the given id access to the secret with the given ID.
+func (ctx *HookContext) GrantSecretToRelation(id string, relationId int) error {
+	r, found := ctx.relations[relationId]
+	if !found {
+		return errors.NotFoundf("relation %d", relationId)
+	}
+	return ctx.unit.GrantSecret(id, r.ru.Relation().Tag())
+}
+
 // ActionName returns the name of the action.
 func (ctx *HookContext) ActionName() (string, error) {
 	if ctx.actionData == nil {
@@ -590,6 +665,12 @@ func (context *HookContext) HookVars(paths Paths) ([]string, error) {
 	} else if !errors.IsNotFound(err) {
 		return nil, errors.Trace(err)
 	}
+	if context.secretId != "" {
+		vars = append(vars, "JUJU_SECRET_ID="+context.secretId)
+	}
+	if context.payloadId != "" {
+		vars = append(vars, "JUJU_PAYLOAD_ID="+context.payloadId)
+	}
 	if context.actionData != nil {
 		vars = append(vars,
 			"JUJU_ACTION_NAME="+context.actionData.Name,
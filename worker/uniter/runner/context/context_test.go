@@ -125,6 +125,15 @@ func (s *InterfaceSuite) TestSetUnitStatusUpdatesFlag(c *gc.C) {
 	c.Assert(ctx.(runner.Context).HasExecutionSetUnitStatus(), jc.IsTrue)
 }
 
+func (s *InterfaceSuite) TestSetUnitWorkloadVersion(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	err := ctx.SetUnitWorkloadVersion("4.5.1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.WorkloadVersion(), gc.Equals, "4.5.1")
+}
+
 func (s *InterfaceSuite) TestUnitStatusCaching(c *gc.C) {
 	ctx := s.GetContext(c, -1, "")
 	status, err := ctx.UnitStatus()
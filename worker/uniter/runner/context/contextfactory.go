@@ -150,6 +150,7 @@ func (f *contextFactory) coreContext() (*HookContext, error) {
 		storage:            f.storage,
 		clock:              f.clock,
 		componentDir:       f.paths.ComponentDir,
+		sharedComponentDir: f.paths.SharedComponentDir,
 		componentFuncs:     registeredComponentFuncs,
 	}
 	if err := f.updateContext(ctx); err != nil {
@@ -205,6 +206,12 @@ func (f *contextFactory) HookContext(hookInfo hook.Info) (*HookContext, error) {
 		}
 		hookName = fmt.Sprintf("%s-%s", storageName, hookName)
 	}
+	if hookInfo.Kind == hook.SecretRotate {
+		ctx.secretId = hookInfo.SecretId
+	}
+	if hookInfo.Kind == hook.PayloadUnhealthy {
+		ctx.payloadId = hookInfo.PayloadId
+	}
 	ctx.id = f.newId(hookName)
 	return ctx, nil
 }
@@ -282,6 +289,7 @@ func (f *contextFactory) updateContext(ctx *HookContext) (err error) {
 		return err
 	}
 	ctx.proxySettings = environConfig.ProxySettings()
+	ctx.hookTimeout = environConfig.HookTimeout()
 
 	// Calling these last, because there's a potential race: they're not guaranteed
 	// to be set in time to be needed for a hook. If they're not, we just leave them
@@ -147,3 +147,37 @@ func (s *EnvSuite) TestEnvUbuntu(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.assertVars(c, actualVars, contextVars, pathsVars, ubuntuVars, relationVars)
 }
+
+func (s *EnvSuite) TestEnvSecret(c *gc.C) {
+	s.PatchValue(&jujuos.HostOS, func() jujuos.OSType { return jujuos.Ubuntu })
+	os.Setenv("PATH", "foo:bar")
+	ubuntuVars := []string{
+		"PATH=path-to-tools:foo:bar",
+		"APT_LISTCHANGES_FRONTEND=none",
+		"DEBIAN_FRONTEND=noninteractive",
+	}
+
+	ctx, contextVars := s.getContext()
+	paths, pathsVars := s.getPaths()
+	context.SetEnvironmentHookContextSecret(ctx, "42")
+	actualVars, err := ctx.HookVars(paths)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVars(c, actualVars, contextVars, pathsVars, ubuntuVars, []string{"JUJU_SECRET_ID=42"})
+}
+
+func (s *EnvSuite) TestEnvPayload(c *gc.C) {
+	s.PatchValue(&jujuos.HostOS, func() jujuos.OSType { return jujuos.Ubuntu })
+	os.Setenv("PATH", "foo:bar")
+	ubuntuVars := []string{
+		"PATH=path-to-tools:foo:bar",
+		"APT_LISTCHANGES_FRONTEND=none",
+		"DEBIAN_FRONTEND=noninteractive",
+	}
+
+	ctx, contextVars := s.getContext()
+	paths, pathsVars := s.getPaths()
+	context.SetEnvironmentHookContextPayload(ctx, "spam/id0")
+	actualVars, err := ctx.HookVars(paths)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVars(c, actualVars, contextVars, pathsVars, ubuntuVars, []string{"JUJU_PAYLOAD_ID=spam/id0"})
+}
@@ -103,6 +103,16 @@ func SetEnvironmentHookContextRelation(
 	}
 }
 
+// SetEnvironmentHookContextSecret exists purely to set the fields used in hookVars.
+func SetEnvironmentHookContextSecret(context *HookContext, secretId string) {
+	context.secretId = secretId
+}
+
+// SetEnvironmentHookContextPayload exists purely to set the fields used in hookVars.
+func SetEnvironmentHookContextPayload(context *HookContext, payloadId string) {
+	context.payloadId = payloadId
+}
+
 func PatchCachedStatus(ctx jujuc.Context, status, info string, data map[string]interface{}) func() {
 	hctx := ctx.(*HookContext)
 	oldStatus := hctx.status
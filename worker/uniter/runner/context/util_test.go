@@ -394,3 +394,7 @@ func (MockEnvPaths) GetMetricsSpoolDir() string {
 func (MockEnvPaths) ComponentDir(name string) string {
 	return filepath.Join("path-to-base-dir", name)
 }
+
+func (MockEnvPaths) SharedComponentDir(name string) string {
+	return filepath.Join("path-to-shared-dir", name)
+}
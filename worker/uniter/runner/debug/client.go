@@ -11,12 +11,25 @@ import (
 )
 
 type hookArgs struct {
-	Hooks []string `yaml:"hooks,omitempty"`
+	Hooks   []string `yaml:"hooks,omitempty"`
+	DebugAt string   `yaml:"debug-at,omitempty"`
 }
 
 // ClientScript returns a bash script suitable for executing
 // on the unit system to intercept hooks via tmux shell.
 func ClientScript(c *HooksContext, hooks []string) string {
+	return clientScript(c, hooks, "")
+}
+
+// DebugCodeClientScript returns a bash script suitable for executing
+// on the unit system to run the charm's own code for the intercepted
+// hooks in a tmux window, with JUJU_DEBUG_AT set to debugAt so that
+// the code can stop at its breakpoints.
+func DebugCodeClientScript(c *HooksContext, hooks []string, debugAt string) string {
+	return clientScript(c, hooks, debugAt)
+}
+
+func clientScript(c *HooksContext, hooks []string, debugAt string) string {
 	// If any hook is "*", then the client is interested in all.
 	for _, hook := range hooks {
 		if hook == "*" {
@@ -30,15 +43,15 @@ func ClientScript(c *HooksContext, hooks []string) string {
 	s = strings.Replace(s, "{entry_flock}", c.ClientFileLock(), -1)
 	s = strings.Replace(s, "{exit_flock}", c.ClientExitFileLock(), -1)
 
-	yamlArgs := encodeArgs(hooks)
+	yamlArgs := encodeArgs(hooks, debugAt)
 	base64Args := base64.StdEncoding.EncodeToString(yamlArgs)
 	s = strings.Replace(s, "{hook_args}", base64Args, 1)
 	return s
 }
 
-func encodeArgs(hooks []string) []byte {
+func encodeArgs(hooks []string, debugAt string) []byte {
 	// Marshal to YAML, then encode in base64 to avoid shell escapes.
-	yamlArgs, err := goyaml.Marshal(hookArgs{Hooks: hooks})
+	yamlArgs, err := goyaml.Marshal(hookArgs{Hooks: hooks, DebugAt: debugAt})
 	if err != nil {
 		// This should not happen: we're in full control.
 		panic(err)
@@ -48,3 +48,18 @@ func (*DebugHooksClientSuite) TestClientScript(c *gc.C) {
 	)
 	c.Assert(debug.ClientScript(ctx, []string{"something somethingelse"}), gc.Matches, expected)
 }
+
+func (*DebugHooksClientSuite) TestDebugCodeClientScript(c *gc.C) {
+	ctx := debug.NewHooksContext("foo/8")
+
+	// Without breakpoints, the script is that of debug-hooks.
+	c.Assert(debug.DebugCodeClientScript(ctx, nil, ""), gc.Equals, debug.ClientScript(ctx, nil))
+
+	// The breakpoints are passed to the server along with the hooks.
+	result := debug.DebugCodeClientScript(ctx, []string{"start"}, "all")
+	expected := fmt.Sprintf(
+		`(.|\n)*echo "aG9va3M6Ci0gc3RhcnQKZGVidWctYXQ6IGFsbAo=" | base64 -d > %s(.|\n)*`,
+		regexp.QuoteMeta(ctx.ClientFileLock()),
+	)
+	c.Assert(result, gc.Matches, expected)
+}
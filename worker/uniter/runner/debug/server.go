@@ -9,15 +9,24 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/juju/utils/set"
 	goyaml "gopkg.in/yaml.v2"
 )
 
-// ServerSession represents a "juju debug-hooks" session.
+// ServerSession represents a "juju debug-hooks" or "juju debug-code"
+// session.
 type ServerSession struct {
 	*HooksContext
-	hooks set.Strings
+	hooks   set.Strings
+	debugAt string
+}
+
+// DebugAt returns the breakpoints requested by a debug-code client,
+// or "" if the session was started by debug-hooks.
+func (s *ServerSession) DebugAt() string {
+	return s.debugAt
 }
 
 // MatchHook returns true if the specified hook name matches
@@ -34,12 +43,19 @@ var waitClientExit = func(s *ServerSession) {
 }
 
 // RunHook "runs" the hook with the specified name via debug-hooks.
+// For a debug-code session, the charm's own hook is run in the tmux
+// window with JUJU_DEBUG_AT set, rather than an interactive shell.
 func (s *ServerSession) RunHook(hookName, charmDir string, env []string) error {
 	env = append(env, "JUJU_HOOK_NAME="+hookName)
+	script := debugHooksServerScript
+	if s.debugAt != "" {
+		env = append(env, "JUJU_DEBUG_AT="+s.debugAt)
+		script = debugCodeServerScript
+	}
 	cmd := exec.Command("/bin/bash", "-s")
 	cmd.Env = env
 	cmd.Dir = charmDir
-	cmd.Stdin = bytes.NewBufferString(debugHooksServerScript)
+	cmd.Stdin = bytes.NewBufferString(script)
 	if err := cmd.Start(); err != nil {
 		return err
 	}
@@ -76,11 +92,35 @@ func (c *HooksContext) FindSession() (*ServerSession, error) {
 		return nil, err
 	}
 	hooks := set.NewStrings(args.Hooks...)
-	session := &ServerSession{c, hooks}
+	session := &ServerSession{c, hooks, args.DebugAt}
 	return session, nil
 }
 
-const debugHooksServerScript = `set -e
+var (
+	debugHooksServerScript = strings.Replace(serverScript, "{hook_command}", debugHooksCommand, 1)
+	debugCodeServerScript  = strings.Replace(serverScript, "{hook_command}", debugCodeCommand, 1)
+)
+
+// debugHooksCommand starts an interactive shell in the hook
+// environment, in which the user runs the hook manually.
+const debugHooksCommand = `exec /bin/bash --noprofile --init-file $JUJU_DEBUG/init.sh`
+
+// debugCodeCommand runs the charm's own hook, recording its exit
+// status, and leaves the output on screen until the user continues.
+const debugCodeCommand = `echo "Running \$JUJU_HOOK_NAME with JUJU_DEBUG_AT=\$JUJU_DEBUG_AT"
+if [ -x "\$CHARM_DIR/hooks/\$JUJU_HOOK_NAME" ]; then
+    "\$CHARM_DIR/hooks/\$JUJU_HOOK_NAME"
+    status=\$?
+else
+    echo "The charm does not implement \$JUJU_HOOK_NAME."
+    status=0
+fi
+echo \$status > $JUJU_DEBUG/hook_exit_status
+echo "\$JUJU_HOOK_NAME exited with status \$status; press enter to continue."
+read
+exit \$status`
+
+const serverScript = `set -e
 export JUJU_DEBUG=$(mktemp -d)
 exec > $JUJU_DEBUG/debug.log >&1
 
@@ -115,7 +155,7 @@ cat > $JUJU_DEBUG/hook.sh <<END
 #!/bin/bash
 . $JUJU_DEBUG/env.sh
 echo \$\$ > $JUJU_DEBUG/hook.pid
-exec /bin/bash --noprofile --init-file $JUJU_DEBUG/init.sh
+{hook_command}
 END
 chmod +x $JUJU_DEBUG/hook.sh
 
@@ -93,6 +93,15 @@ func (s *DebugHooksServerSuite) TestFindSession(c *gc.C) {
 	c.Assert(session.MatchHook("bar"), jc.IsTrue)
 	c.Assert(session.MatchHook("baz"), jc.IsTrue)
 	c.Assert(session.MatchHook("foo bar baz"), jc.IsFalse)
+	c.Assert(session.DebugAt(), gc.Equals, "")
+
+	// A debug-code client also records its breakpoints.
+	err = ioutil.WriteFile(s.ctx.ClientFileLock(), []byte(`{hooks: [foo], debug-at: "all"}`), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	session, err = s.ctx.FindSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.MatchHook("foo"), jc.IsTrue)
+	c.Assert(session.DebugAt(), gc.Equals, "all")
 }
 
 func (s *DebugHooksServerSuite) TestRunHookExceptional(c *gc.C) {
@@ -159,12 +168,67 @@ func (s *DebugHooksServerSuite) TestRunHook(c *gc.C) {
 		ch <- session.RunHook(hookName, s.tmpdir, os.Environ())
 	}()
 
+	debugdir := s.waitForDebugDir(c, ch)
+	envsh := filepath.Join(s.tmpdir, debugdir.Name(), "env.sh")
+	s.verifyEnvshFile(c, envsh, hookName)
+
+	hookpid := filepath.Join(s.tmpdir, debugdir.Name(), "hook.pid")
+	err = ioutil.WriteFile(hookpid, []byte("not a pid"), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// RunHook should complete without waiting to be
+	// killed, and despite the exit lock being held.
+	err = <-ch
+	c.Assert(err, jc.ErrorIsNil)
+	cmd.Process.Kill() // kill flock
+}
+
+func (s *DebugHooksServerSuite) TestRunHookDebugCode(c *gc.C) {
+	err := ioutil.WriteFile(s.ctx.ClientFileLock(), []byte(`debug-at: all`), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	session, err := s.ctx.FindSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.DebugAt(), gc.Equals, "all")
+
+	const hookName = "myhook"
+
+	cmd := exec.Command("flock", s.ctx.ClientExitFileLock(), "-c", "sleep 5s")
+	c.Assert(cmd.Start(), gc.IsNil)
+	ch := make(chan error)
+	go func() {
+		ch <- session.RunHook(hookName, s.tmpdir, os.Environ())
+	}()
+	debugdir := s.waitForDebugDir(c, ch)
+
+	// The hook environment carries the breakpoints, and the hook
+	// window runs the charm's own hook rather than a shell.
+	envsh := filepath.Join(s.tmpdir, debugdir.Name(), "env.sh")
+	s.verifyEnvshFile(c, envsh, hookName)
+	data, err := ioutil.ReadFile(envsh)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, `JUJU_DEBUG_AT="all"`)
+	data, err = ioutil.ReadFile(filepath.Join(s.tmpdir, debugdir.Name(), "hook.sh"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, `"$CHARM_DIR/hooks/$JUJU_HOOK_NAME"`)
+	c.Assert(string(data), gc.Not(jc.Contains), "--init-file")
+
+	hookpid := filepath.Join(s.tmpdir, debugdir.Name(), "hook.pid")
+	err = ioutil.WriteFile(hookpid, []byte("not a pid"), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	err = <-ch
+	c.Assert(err, jc.ErrorIsNil)
+	cmd.Process.Kill() // kill flock
+}
+
+// waitForDebugDir waits until either the debug dir created by a
+// running hook is found, or the hook completes.
+func (s *DebugHooksServerSuite) waitForDebugDir(c *gc.C, ch <-chan error) os.FileInfo {
 	// Wait until either we find the debug dir, or the flock is released.
 	ticker := time.Tick(10 * time.Millisecond)
 	var debugdir os.FileInfo
 	for debugdir == nil {
 		select {
-		case err = <-ch:
+		case <-ch:
 			// flock was released before we found the debug dir.
 			c.Error("could not find hook.sh")
 
@@ -193,19 +257,7 @@ func (s *DebugHooksServerSuite) TestRunHook(c *gc.C) {
 			time.Sleep(10 * time.Millisecond)
 		}
 	}
-
-	envsh := filepath.Join(s.tmpdir, debugdir.Name(), "env.sh")
-	s.verifyEnvshFile(c, envsh, hookName)
-
-	hookpid := filepath.Join(s.tmpdir, debugdir.Name(), "hook.pid")
-	err = ioutil.WriteFile(hookpid, []byte("not a pid"), 0777)
-	c.Assert(err, jc.ErrorIsNil)
-
-	// RunHook should complete without waiting to be
-	// killed, and despite the exit lock being held.
-	err = <-ch
-	c.Assert(err, jc.ErrorIsNil)
-	cmd.Process.Kill() // kill flock
+	return debugdir
 }
 
 func (s *DebugHooksServerSuite) verifyEnvshFile(c *gc.C, envshPath string, hookName string) {
@@ -5,6 +5,7 @@ package runner
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 )
@@ -28,3 +29,25 @@ func IsBadActionError(err error) bool {
 func NewBadActionError(actionName, problem string) error {
 	return &badActionError{actionName, problem}
 }
+
+type hookTimeoutError struct {
+	hookName string
+	timeout  time.Duration
+}
+
+func (e *hookTimeoutError) Error() string {
+	return fmt.Sprintf("hook %q timed out after %v", e.hookName, e.timeout)
+}
+
+// IsHookTimeoutError returns true if the error indicates that a hook
+// was killed for running longer than it was allowed to.
+func IsHookTimeoutError(err error) bool {
+	_, ok := err.(*hookTimeoutError)
+	return ok
+}
+
+// NewHookTimeoutError returns an error indicating that the named hook
+// was killed after running for the given time.
+func NewHookTimeoutError(hookName string, timeout time.Duration) error {
+	return &hookTimeoutError{hookName, timeout}
+}
@@ -0,0 +1,53 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// applicationVersionSetCommand implements the application-version-set
+// command.
+type applicationVersionSetCommand struct {
+	cmd.CommandBase
+	ctx     Context
+	version string
+}
+
+// NewApplicationVersionSetCommand returns a new
+// applicationVersionSetCommand with the given context.
+func NewApplicationVersionSetCommand(ctx Context) (cmd.Command, error) {
+	return &applicationVersionSetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *applicationVersionSetCommand) Info() *cmd.Info {
+	doc := `
+application-version-set records the version of the workload software
+running on the unit, such as the version of the database server a charm
+deploys. The version is shown for the unit, and for its service, in the
+output of juju status. Pass an empty string to clear the version.
+`
+	return &cmd.Info{
+		Name:    "application-version-set",
+		Args:    "<new-version>",
+		Purpose: "specify which version of the workload is deployed",
+		Doc:     doc,
+	}
+}
+
+// Init is part of the cmd.Command interface.
+func (c *applicationVersionSetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no version specified")
+	}
+	c.version = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run is part of the cmd.Command interface.
+func (c *applicationVersionSetCommand) Run(ctx *cmd.Context) error {
+	return errors.Annotate(c.ctx.SetUnitWorkloadVersion(c.version), "cannot set workload version")
+}
@@ -0,0 +1,62 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type ApplicationVersionSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ApplicationVersionSetSuite{})
+
+func (s *ApplicationVersionSetSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"1.2.3"}, ""},
+		{[]string{""}, ""},
+		{nil, "no version specified"},
+		{[]string{"1.2.3", "extra"}, `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("application-version-set"))
+		c.Assert(err, jc.ErrorIsNil)
+		testing.TestInit(c, com, t.args, t.err)
+	}
+}
+
+func (s *ApplicationVersionSetSuite) TestSetVersion(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("application-version-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"5.7.12"})
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.Unit.WorkloadVersion, gc.Equals, "5.7.12")
+	s.Stub.CheckCall(c, 0, "SetUnitWorkloadVersion", "5.7.12")
+}
+
+func (s *ApplicationVersionSetSuite) TestError(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	s.Stub.SetErrors(errors.New("boom"))
+	com, err := jujuc.NewCommand(hctx, cmdString("application-version-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"5.7.12"})
+	c.Assert(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot set workload version: boom\n")
+}
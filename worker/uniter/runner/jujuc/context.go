@@ -52,6 +52,7 @@ type HookContext interface {
 	ContextStorage
 	ContextComponents
 	ContextRelations
+	ContextSecrets
 }
 
 // UnitHookContext is the context for a unit hook.
@@ -105,6 +106,14 @@ type ContextUnit interface {
 
 	// Config returns the current service configuration of the executing unit.
 	ConfigSettings() (charm.Settings, error)
+
+	// GoalState returns the units expected for the executing unit's
+	// service, and for each service related to it, with their status.
+	GoalState() (*params.GoalState, error)
+
+	// SetUnitWorkloadVersion records the version of the workload
+	// software running on the executing unit.
+	SetUnitWorkloadVersion(version string) error
 }
 
 // ContextStatus is the part of a hook context related to the unit's status.
@@ -203,6 +212,34 @@ type ContextStorage interface {
 	AddUnitStorage(map[string]params.StorageConstraints) error
 }
 
+// ContextSecrets is the part of a hook context related to secrets.
+type ContextSecrets interface {
+	// AddSecret creates a secret, owned by the executing unit's service,
+	// with the given content and returns its ID. If rotateInterval is
+	// non-zero, the secret-rotate hook will be run on the service's
+	// leader at that interval.
+	AddSecret(description string, data map[string]string, rotateInterval time.Duration) (string, error)
+
+	// SecretValue returns the content of the secret with the given ID,
+	// if the executing unit has access to it. If revision is non-zero,
+	// the content of that revision is returned instead of the current
+	// one.
+	SecretValue(id string, revision int) (map[string]string, error)
+
+	// UpdateSecret replaces the content of the secret with the given ID,
+	// which must be owned by the executing unit's service.
+	UpdateSecret(id string, data map[string]string) error
+
+	// GrantSecret gives the named unit access to the secret with the
+	// given ID, which must be owned by the executing unit's service.
+	GrantSecret(id string, unitName string) error
+
+	// GrantSecretToRelation gives the units of the services in the
+	// relation with the given id access to the secret with the given ID,
+	// which must be owned by the executing unit's service.
+	GrantSecretToRelation(id string, relationId int) error
+}
+
 // ContextComponents exposes modular Juju components as they relate to
 // the unit in the context of the hook.
 type ContextComponents interface {
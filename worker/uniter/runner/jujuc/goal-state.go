@@ -0,0 +1,92 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// GoalStateCommand implements the goal-state command.
+type GoalStateCommand struct {
+	cmd.CommandBase
+	ctx Context
+	out cmd.Output
+}
+
+// NewGoalStateCommand returns a new GoalStateCommand with the given context.
+func NewGoalStateCommand(ctx Context) (cmd.Command, error) {
+	return &GoalStateCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *GoalStateCommand) Info() *cmd.Info {
+	doc := `
+goal-state prints the units expected for the unit's service, and for each
+service related to it, keyed by the name of the endpoint they are related on.
+Unlike relation-list, it includes units that have not yet joined, so that a
+charm can tell when all the units it is waiting for are present.
+
+The status of each unit is one of:
+    waiting  the unit's agent has not yet started
+    joining  the related unit's agent has started, but it has not yet
+             joined the relation
+    active   the unit's agent has started, and it has joined the
+             relation if it is a related unit
+    dying    the unit, or its relation with this unit, is going away
+`
+	return &cmd.Info{
+		Name:    "goal-state",
+		Purpose: "print the units expected for the service and its relations",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *GoalStateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init is part of the cmd.Command interface.
+func (c *GoalStateCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+type unitGoalState struct {
+	Status string `yaml:"status" json:"status"`
+}
+
+type unitsGoalState map[string]unitGoalState
+
+type goalState struct {
+	Units     unitsGoalState            `yaml:"units" json:"units"`
+	Relations map[string]unitsGoalState `yaml:"relations" json:"relations"`
+}
+
+func formatUnitsGoalState(units params.UnitsGoalState) unitsGoalState {
+	result := make(unitsGoalState)
+	for name, unit := range units {
+		result[name] = unitGoalState{Status: unit.Status}
+	}
+	return result
+}
+
+// Run is part of the cmd.Command interface.
+func (c *GoalStateCommand) Run(ctx *cmd.Context) error {
+	gs, err := c.ctx.GoalState()
+	if err != nil {
+		return errors.Annotate(err, "cannot get goal state")
+	}
+	result := goalState{
+		Units:     formatUnitsGoalState(gs.Units),
+		Relations: make(map[string]unitsGoalState),
+	}
+	for endpoint, units := range gs.Relations {
+		result.Relations[endpoint] = formatUnitsGoalState(units)
+	}
+	return c.out.Write(ctx, result)
+}
@@ -0,0 +1,89 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type GoalStateSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&GoalStateSuite{})
+
+var goalStateTestGoalState = &params.GoalState{
+	Units: params.UnitsGoalState{
+		"wordpress/0": {Status: params.GoalStateActive},
+		"wordpress/1": {Status: params.GoalStateWaiting},
+	},
+	Relations: map[string]params.UnitsGoalState{
+		"db": {
+			"mysql/0": {Status: params.GoalStateJoining},
+		},
+	},
+}
+
+var goalStateTestOutput = map[string]interface{}{
+	"units": map[string]interface{}{
+		"wordpress/0": map[string]interface{}{"status": "active"},
+		"wordpress/1": map[string]interface{}{"status": "waiting"},
+	},
+	"relations": map[string]interface{}{
+		"db": map[string]interface{}{
+			"mysql/0": map[string]interface{}{"status": "joining"},
+		},
+	},
+}
+
+func (s *GoalStateSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range []struct {
+		args    []string
+		checker gc.Checker
+	}{
+		{nil, jc.YAMLEquals},
+		{[]string{"--format", "yaml"}, jc.YAMLEquals},
+		{[]string{"--format", "json"}, jc.JSONEquals},
+	} {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.info.Unit.GoalState = goalStateTestGoalState
+		com, err := jujuc.NewCommand(hctx, cmdString("goal-state"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), t.checker, goalStateTestOutput)
+	}
+}
+
+func (s *GoalStateSuite) TestError(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	s.Stub.SetErrors(errors.New("boom"))
+	com, err := jujuc.NewCommand(hctx, cmdString("goal-state"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot get goal state: boom\n")
+	s.Stub.CheckCallNames(c, "GoalState")
+}
+
+func (s *GoalStateSuite) TestUnexpectedArgs(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("goal-state"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"db"})
+	c.Assert(code, gc.Equals, 2)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: unrecognized args: [\"db\"]\n")
+}
@@ -36,11 +36,14 @@ func NewNetworkGetCommand(ctx Context) (cmd.Command, error) {
 
 // Info is part of the cmd.Command interface.
 func (c *NetworkGetCommand) Info() *cmd.Info {
-	args := "--primary-address"
+	args := "[--primary-address]"
 	doc := `
-network-get returns the network config for a relation. The only supported
-flag for now is --primary-address, which is required and returns the IP
-address the local unit should advertise as its endpoint to its peers.
+network-get returns the network config of the local unit for a relation.
+If the relation's endpoint is bound to a space, every address the unit has
+in that space is listed, along with the CIDR of its subnet and the name of
+the interface it is on, where known. With --primary-address, only the IP
+address the local unit should advertise as its endpoint to its peers is
+returned.
 `
 	return &cmd.Info{
 		Name:    "network-get",
@@ -65,10 +68,6 @@ func (c *NetworkGetCommand) Init(args []string) error {
 		return fmt.Errorf("no relation id specified")
 	}
 
-	if !c.primaryAddress {
-		return fmt.Errorf("--primary-address is currently required")
-	}
-
 	return cmd.CheckEmpty(args)
 }
 
@@ -89,5 +88,21 @@ func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
 	if c.primaryAddress {
 		return c.out.Write(ctx, netconfig[0].Address)
 	}
-	return c.out.Write(ctx, nil)
+	addresses := make([]networkAddress, len(netconfig))
+	for i, config := range netconfig {
+		addresses[i] = networkAddress{
+			Address:       config.Address,
+			CIDR:          config.CIDR,
+			InterfaceName: config.InterfaceName,
+		}
+	}
+	return c.out.Write(ctx, addresses)
+}
+
+// networkAddress holds the details of an address of the local unit
+// reported by network-get.
+type networkAddress struct {
+	Address       string `yaml:"address" json:"address"`
+	CIDR          string `yaml:"cidr,omitempty" json:"cidr,omitempty"`
+	InterfaceName string `yaml:"interface-name,omitempty" json:"interface-name,omitempty"`
 }
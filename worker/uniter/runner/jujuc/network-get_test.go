@@ -25,7 +25,7 @@ var _ = gc.Suite(&NetworkGetSuite{})
 func (s *NetworkGetSuite) newHookContext(relid int) (jujuc.Context, *relationInfo) {
 	netConfig := []params.NetworkConfig{
 		{Address: "8.8.8.8"},
-		{Address: "10.0.0.1"},
+		{Address: "10.0.0.1", CIDR: "10.0.0.0/24", InterfaceName: "eth1"},
 	}
 
 	hctx, info := s.relationSuite.newHookContext(relid, "remote")
@@ -55,16 +55,19 @@ func (s *NetworkGetSuite) TestNetworkGet(c *gc.C) {
 		args:    []string{"-r", "burble:123"},
 		out:     `invalid value "burble:123" for flag -r: relation not found`,
 	}, {
-		summary: "default relation, no --primary-address given",
+		summary: "default relation, all addresses",
 		relid:   1,
-		code:    2,
-		out:     `--primary-address is currently required`,
+		args:    []string{"--format", "json"},
+		out:     `[{"address":"8.8.8.8"},{"address":"10.0.0.1","cidr":"10.0.0.0/24","interface-name":"eth1"}]`,
 	}, {
-		summary: "explicit relation, no --primary-address given",
+		summary: "explicit relation, all addresses",
 		relid:   -1,
-		code:    2,
-		args:    []string{"-r", "burble:1"},
-		out:     `--primary-address is currently required`,
+		args:    []string{"-r", "burble:1", "--format", "yaml"},
+		out: `
+- address: 8.8.8.8
+- address: 10.0.0.1
+  cidr: 10.0.0.0/24
+  interface-name: eth1`[1:],
 	}, {
 		summary: "explicit relation with --primary-address",
 		relid:   1,
@@ -101,7 +104,7 @@ func (s *NetworkGetSuite) TestNetworkGet(c *gc.C) {
 func (s *NetworkGetSuite) TestHelp(c *gc.C) {
 
 	var helpTemplate = `
-usage: network-get [options] --primary-address
+usage: network-get [options] [--primary-address]
 purpose: get network config
 
 options:
@@ -114,9 +117,12 @@ options:
 -r, --relation  (= %s)
     specify a relation by id
 
-network-get returns the network config for a relation. The only supported
-flag for now is --primary-address, which is required and returns the IP
-address the local unit should advertise as its endpoint to its peers.
+network-get returns the network config of the local unit for a relation.
+If the relation's endpoint is bound to a space, every address the unit has
+in that space is listed, along with the CIDR of its subnet and the name of
+the interface it is on, where known. With --primary-address, only the IP
+address the local unit should advertise as its endpoint to its peers is
+returned.
 `[1:]
 
 	for i, t := range []struct {
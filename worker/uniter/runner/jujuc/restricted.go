@@ -25,6 +25,12 @@ type RestrictedContext struct{}
 // ConfigSettings implements jujuc.Context.
 func (*RestrictedContext) ConfigSettings() (charm.Settings, error) { return nil, ErrRestrictedContext }
 
+// GoalState implements jujuc.Context.
+func (*RestrictedContext) GoalState() (*params.GoalState, error) { return nil, ErrRestrictedContext }
+
+// SetUnitWorkloadVersion implements jujuc.Context.
+func (*RestrictedContext) SetUnitWorkloadVersion(string) error { return ErrRestrictedContext }
+
 // UnitStatus implements jujuc.Context.
 func (*RestrictedContext) UnitStatus() (*StatusInfo, error) { return nil, ErrRestrictedContext }
 
@@ -132,3 +138,24 @@ func (*RestrictedContext) SetActionFailed() error { return ErrRestrictedContext
 func (*RestrictedContext) Component(string) (ContextComponent, error) {
 	return nil, ErrRestrictedContext
 }
+
+// AddSecret implements jujuc.Context.
+func (*RestrictedContext) AddSecret(string, map[string]string, time.Duration) (string, error) {
+	return "", ErrRestrictedContext
+}
+
+// SecretValue implements jujuc.Context.
+func (*RestrictedContext) SecretValue(string, int) (map[string]string, error) {
+	return nil, ErrRestrictedContext
+}
+
+// UpdateSecret implements jujuc.Context.
+func (*RestrictedContext) UpdateSecret(string, map[string]string) error {
+	return ErrRestrictedContext
+}
+
+// GrantSecret implements jujuc.Context.
+func (*RestrictedContext) GrantSecret(string, string) error { return ErrRestrictedContext }
+
+// GrantSecretToRelation implements jujuc.Context.
+func (*RestrictedContext) GrantSecretToRelation(string, int) error { return ErrRestrictedContext }
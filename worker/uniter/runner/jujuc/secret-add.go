@@ -0,0 +1,83 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+	"launchpad.net/gnuflag"
+)
+
+// SecretAddCommand implements the secret-add command.
+type SecretAddCommand struct {
+	cmd.CommandBase
+	ctx            Context
+	description    string
+	rotate         string
+	rotateInterval time.Duration
+	data           map[string]string
+}
+
+// NewSecretAddCommand returns a new SecretAddCommand with the given context.
+func NewSecretAddCommand(ctx Context) (cmd.Command, error) {
+	return &SecretAddCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *SecretAddCommand) Info() *cmd.Info {
+	doc := `
+secret-add stores the supplied key/value pairs as a new secret, owned by the
+unit's service, and prints the secret's ID. The content of the secret is
+encrypted by the controller, and is only readable by units of the service and
+by units or relations it has been granted to with secret-grant.
+
+If --rotate is given, the secret-rotate hook will be run on the service's
+leader, with JUJU_SECRET_ID set, each time the interval elapses.
+`
+	return &cmd.Info{
+		Name:    "secret-add",
+		Args:    "<key>=<value> [...]",
+		Purpose: "add a new secret",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *SecretAddCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.description, "description", "", "the description of the secret")
+	f.StringVar(&c.rotate, "rotate", "", "the interval at which to rotate the secret, e.g. 24h")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *SecretAddCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no secret content specified")
+	}
+	if c.rotate != "" {
+		interval, err := time.ParseDuration(c.rotate)
+		if err != nil {
+			return errors.Annotate(err, "invalid rotate interval")
+		}
+		if interval <= 0 {
+			return errors.Errorf("rotate interval %v not valid", interval)
+		}
+		c.rotateInterval = interval
+	}
+	var err error
+	c.data, err = keyvalues.Parse(args, true)
+	return err
+}
+
+// Run is part of the cmd.Command interface.
+func (c *SecretAddCommand) Run(ctx *cmd.Context) error {
+	id, err := c.ctx.AddSecret(c.description, c.data, c.rotateInterval)
+	if err != nil {
+		return errors.Annotate(err, "cannot add secret")
+	}
+	_, err = ctx.Stdout.Write([]byte(id + "\n"))
+	return err
+}
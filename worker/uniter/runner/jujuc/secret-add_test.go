@@ -0,0 +1,74 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type SecretAddSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&SecretAddSuite{})
+
+func (s *SecretAddSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no secret content specified"},
+		{[]string{"nonsense"}, `expected "key=value", got "nonsense"`},
+		{[]string{"--rotate", "soon", "foo=bar"}, `invalid rotate interval: time: invalid duration soon`},
+		{[]string{"--rotate", "-1h", "foo=bar"}, `rotate interval -1h0m0s not valid`},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("secret-add"))
+		c.Assert(err, jc.ErrorIsNil)
+		err = testing.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SecretAddSuite) TestAddSecret(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-add"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{
+		"--description", "db password", "--rotate", "24h", "password=sekrit",
+	})
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "1\n")
+	c.Check(hctx.info.Secrets.Secrets, jc.DeepEquals, map[string]*jujuctesting.Secret{
+		"1": {
+			Description:    "db password",
+			Data:           map[string]string{"password": "sekrit"},
+			RotateInterval: 24 * time.Hour,
+		},
+	})
+}
+
+func (s *SecretAddSuite) TestAddSecretError(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	s.Stub.SetErrors(errors.New("boom"))
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-add"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"password=sekrit"})
+	c.Assert(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot add secret: boom\n")
+	s.Stub.CheckCallNames(c, "AddSecret")
+}
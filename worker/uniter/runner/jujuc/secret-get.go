@@ -0,0 +1,81 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// SecretGetCommand implements the secret-get command.
+type SecretGetCommand struct {
+	cmd.CommandBase
+	ctx      Context
+	id       string
+	key      string
+	revision int
+	out      cmd.Output
+}
+
+// NewSecretGetCommand returns a new SecretGetCommand with the given context.
+func NewSecretGetCommand(ctx Context) (cmd.Command, error) {
+	return &SecretGetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *SecretGetCommand) Info() *cmd.Info {
+	doc := `
+secret-get prints the value of the given key in the secret with the given ID.
+If no key is given, all keys and values will be printed. The secret must be
+owned by the unit's service, or have been granted to the unit or to one of
+its relations.
+
+The current revision of the secret is printed unless --revision is given.
+Only the last few revisions replaced by secret-set can still be read.
+`
+	return &cmd.Info{
+		Name:    "secret-get",
+		Args:    "<id> [<key>]",
+		Purpose: "print the content of a secret",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *SecretGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.IntVar(&c.revision, "revision", 0, "print this revision of the secret instead of the current one")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *SecretGetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no secret ID specified")
+	}
+	if c.revision < 0 {
+		return errors.Errorf("invalid revision %d", c.revision)
+	}
+	c.id = args[0]
+	if len(args) > 1 {
+		c.key = args[1]
+		return cmd.CheckEmpty(args[2:])
+	}
+	return nil
+}
+
+// Run is part of the cmd.Command interface.
+func (c *SecretGetCommand) Run(ctx *cmd.Context) error {
+	data, err := c.ctx.SecretValue(c.id, c.revision)
+	if err != nil {
+		return errors.Annotatef(err, "cannot read secret %q", c.id)
+	}
+	if c.key == "" {
+		return c.out.Write(ctx, data)
+	}
+	if value, ok := data[c.key]; ok {
+		return c.out.Write(ctx, value)
+	}
+	return c.out.Write(ctx, nil)
+}
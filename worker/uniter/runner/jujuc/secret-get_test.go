@@ -0,0 +1,90 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type SecretGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&SecretGetSuite{})
+
+func (s *SecretGetSuite) TestInitErrors(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, nil)
+	c.Check(err, gc.ErrorMatches, "no secret ID specified")
+
+	com, err = jujuc.NewCommand(hctx, cmdString("secret-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"1", "foo", "bar"})
+	c.Check(err, gc.ErrorMatches, `unrecognized args: \["bar"\]`)
+
+	com, err = jujuc.NewCommand(hctx, cmdString("secret-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"--revision", "-1", "1"})
+	c.Check(err, gc.ErrorMatches, "invalid revision -1")
+}
+
+func (s *SecretGetSuite) TestSecretGet(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		out  string
+	}{
+		{[]string{"1"}, "password: sekrit\nuser: admin\n"},
+		{[]string{"1", "password"}, "sekrit\n"},
+		{[]string{"1", "missing"}, ""},
+		{[]string{"--format", "json", "1"}, `{"password":"sekrit","user":"admin"}` + "\n"},
+		{[]string{"--revision", "1", "1", "password"}, "hunter2\n"},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.info.Secrets.SetSecret("1", &jujuctesting.Secret{
+			Data: map[string]string{"user": "admin", "password": "sekrit"},
+			Revisions: map[int]map[string]string{
+				1: {"user": "admin", "password": "hunter2"},
+			},
+		})
+		com, err := jujuc.NewCommand(hctx, cmdString("secret-get"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *SecretGetSuite) TestSecretGetNotFound(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"42"})
+	c.Assert(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, `error: cannot read secret "42": secret "42" not found`+"\n")
+}
+
+func (s *SecretGetSuite) TestSecretGetRevisionNotFound(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.Secrets.SetSecret("1", &jujuctesting.Secret{
+		Data: map[string]string{"password": "sekrit"},
+	})
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--revision", "3", "1"})
+	c.Assert(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, `error: cannot read secret "1": revision 3 of secret "1" not found`+"\n")
+}
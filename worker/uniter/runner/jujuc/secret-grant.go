@@ -0,0 +1,74 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// SecretGrantCommand implements the secret-grant command.
+type SecretGrantCommand struct {
+	cmd.CommandBase
+	ctx        Context
+	id         string
+	unitName   string
+	relationId int
+}
+
+// NewSecretGrantCommand returns a new SecretGrantCommand with the given
+// context.
+func NewSecretGrantCommand(ctx Context) (cmd.Command, error) {
+	return &SecretGrantCommand{ctx: ctx, relationId: -1}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *SecretGrantCommand) Info() *cmd.Info {
+	doc := `
+secret-grant gives a unit, or the units of the services in a relation, access
+to the secret with the given ID. The secret must be owned by the unit's
+service, and the relation must be one the service takes part in.
+`
+	return &cmd.Info{
+		Name:    "secret-grant",
+		Args:    "<id> (--unit <unit> | -r <relation id>)",
+		Purpose: "grant access to a secret",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *SecretGrantCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.unitName, "unit", "", "the unit to grant access to")
+	rV := &relationIdValue{result: &c.relationId, ctx: c.ctx}
+	f.Var(rV, "r", "the relation to grant access to")
+	f.Var(rV, "relation", "")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *SecretGrantCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no secret ID specified")
+	}
+	c.id = args[0]
+	if c.unitName == "" && c.relationId == -1 {
+		return errors.New("either --unit or a relation must be specified")
+	}
+	if c.unitName != "" && c.relationId != -1 {
+		return errors.New("only one of --unit or a relation may be specified")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run is part of the cmd.Command interface.
+func (c *SecretGrantCommand) Run(_ *cmd.Context) error {
+	var err error
+	if c.unitName != "" {
+		err = c.ctx.GrantSecret(c.id, c.unitName)
+	} else {
+		err = c.ctx.GrantSecretToRelation(c.id, c.relationId)
+	}
+	return errors.Annotatef(err, "cannot grant secret %q", c.id)
+}
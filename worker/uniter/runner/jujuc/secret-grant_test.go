@@ -0,0 +1,74 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type SecretGrantSuite struct {
+	relationSuite
+}
+
+var _ = gc.Suite(&SecretGrantSuite{})
+
+func (s *SecretGrantSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no secret ID specified"},
+		{[]string{"1"}, "either --unit or a relation must be specified"},
+		{[]string{"1", "--unit", "mysql/0", "-r", "1"}, "only one of --unit or a relation may be specified"},
+		{[]string{"1", "-r", "42"}, `invalid value "42" for flag -r: relation not found`},
+		{[]string{"1", "--unit", "mysql/0", "2"}, `unrecognized args: \["2"\]`},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx, _ := s.newHookContext(-1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("secret-grant"))
+		c.Assert(err, jc.ErrorIsNil)
+		err = testing.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SecretGrantSuite) TestSecretGrant(c *gc.C) {
+	for i, t := range []struct {
+		args   []string
+		grants []string
+	}{
+		{[]string{"1", "--unit", "mysql/0"}, []string{"mysql/0"}},
+		{[]string{"1", "-r", "1"}, []string{"relation:1"}},
+		{[]string{"1", "--relation", "peer0:0"}, []string{"relation:0"}},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx, info := s.newHookContext(1, "")
+		info.Secrets.SetSecret("1", &jujuctesting.Secret{
+			Data: map[string]string{"password": "sekrit"},
+		})
+		com, err := jujuc.NewCommand(hctx, cmdString("secret-grant"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(info.Secrets.Secrets["1"].Grants, jc.DeepEquals, t.grants)
+	}
+}
+
+func (s *SecretGrantSuite) TestSecretGrantNotFound(c *gc.C) {
+	hctx, _ := s.newHookContext(-1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-grant"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"42", "--unit", "mysql/0"})
+	c.Assert(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, `error: cannot grant secret "42": secret "42" not found`+"\n")
+}
@@ -0,0 +1,58 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+)
+
+// SecretSetCommand implements the secret-set command.
+type SecretSetCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	id   string
+	data map[string]string
+}
+
+// NewSecretSetCommand returns a new SecretSetCommand with the given context.
+func NewSecretSetCommand(ctx Context) (cmd.Command, error) {
+	return &SecretSetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *SecretSetCommand) Info() *cmd.Info {
+	doc := `
+secret-set replaces the content of the secret with the given ID with the
+supplied key/value pairs, creating a new revision of the secret. The secret
+must be owned by the unit's service.
+`
+	return &cmd.Info{
+		Name:    "secret-set",
+		Args:    "<id> <key>=<value> [...]",
+		Purpose: "update the content of a secret",
+		Doc:     doc,
+	}
+}
+
+// Init is part of the cmd.Command interface.
+func (c *SecretSetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no secret ID specified")
+	}
+	c.id = args[0]
+	if len(args) == 1 {
+		return errors.New("no secret content specified")
+	}
+	var err error
+	c.data, err = keyvalues.Parse(args[1:], true)
+	return err
+}
+
+// Run is part of the cmd.Command interface.
+func (c *SecretSetCommand) Run(_ *cmd.Context) error {
+	err := c.ctx.UpdateSecret(c.id, c.data)
+	return errors.Annotatef(err, "cannot update secret %q", c.id)
+}
@@ -0,0 +1,65 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type SecretSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&SecretSetSuite{})
+
+func (s *SecretSetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no secret ID specified"},
+		{[]string{"1"}, "no secret content specified"},
+		{[]string{"1", "nonsense"}, `expected "key=value", got "nonsense"`},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("secret-set"))
+		c.Assert(err, jc.ErrorIsNil)
+		err = testing.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SecretSetSuite) TestSecretSet(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.Secrets.SetSecret("1", &jujuctesting.Secret{
+		Data: map[string]string{"password": "sekrit"},
+	})
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"1", "password=geheim"})
+	c.Assert(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Check(hctx.info.Secrets.Secrets["1"].Data, jc.DeepEquals, map[string]string{
+		"password": "geheim",
+	})
+}
+
+func (s *SecretSetSuite) TestSecretSetNotFound(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"42", "password=geheim"})
+	c.Assert(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, `error: cannot update secret "42": secret "42" not found`+"\n")
+}
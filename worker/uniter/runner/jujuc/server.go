@@ -43,24 +43,26 @@ func RegisterCommand(name string, f creator) {
 
 // baseCommands maps Command names to creators.
 var baseCommands = map[string]creator{
-	"close-port" + cmdSuffix:    NewClosePortCommand,
-	"config-get" + cmdSuffix:    NewConfigGetCommand,
-	"juju-log" + cmdSuffix:      NewJujuLogCommand,
-	"open-port" + cmdSuffix:     NewOpenPortCommand,
-	"opened-ports" + cmdSuffix:  NewOpenedPortsCommand,
-	"relation-get" + cmdSuffix:  NewRelationGetCommand,
-	"action-get" + cmdSuffix:    NewActionGetCommand,
-	"action-set" + cmdSuffix:    NewActionSetCommand,
-	"action-fail" + cmdSuffix:   NewActionFailCommand,
-	"relation-ids" + cmdSuffix:  NewRelationIdsCommand,
-	"relation-list" + cmdSuffix: NewRelationListCommand,
-	"relation-set" + cmdSuffix:  NewRelationSetCommand,
-	"unit-get" + cmdSuffix:      NewUnitGetCommand,
-	"add-metric" + cmdSuffix:    NewAddMetricCommand,
-	"juju-reboot" + cmdSuffix:   NewJujuRebootCommand,
-	"status-get" + cmdSuffix:    NewStatusGetCommand,
-	"status-set" + cmdSuffix:    NewStatusSetCommand,
-	"network-get" + cmdSuffix:   NewNetworkGetCommand,
+	"close-port" + cmdSuffix:              NewClosePortCommand,
+	"config-get" + cmdSuffix:              NewConfigGetCommand,
+	"juju-log" + cmdSuffix:                NewJujuLogCommand,
+	"open-port" + cmdSuffix:               NewOpenPortCommand,
+	"opened-ports" + cmdSuffix:            NewOpenedPortsCommand,
+	"relation-get" + cmdSuffix:            NewRelationGetCommand,
+	"action-get" + cmdSuffix:              NewActionGetCommand,
+	"action-set" + cmdSuffix:              NewActionSetCommand,
+	"action-fail" + cmdSuffix:             NewActionFailCommand,
+	"relation-ids" + cmdSuffix:            NewRelationIdsCommand,
+	"relation-list" + cmdSuffix:           NewRelationListCommand,
+	"relation-set" + cmdSuffix:            NewRelationSetCommand,
+	"unit-get" + cmdSuffix:                NewUnitGetCommand,
+	"add-metric" + cmdSuffix:              NewAddMetricCommand,
+	"juju-reboot" + cmdSuffix:             NewJujuRebootCommand,
+	"status-get" + cmdSuffix:              NewStatusGetCommand,
+	"status-set" + cmdSuffix:              NewStatusSetCommand,
+	"network-get" + cmdSuffix:             NewNetworkGetCommand,
+	"goal-state" + cmdSuffix:              NewGoalStateCommand,
+	"application-version-set" + cmdSuffix: NewApplicationVersionSetCommand,
 }
 
 var storageCommands = map[string]creator{
@@ -75,6 +77,13 @@ var leaderCommands = map[string]creator{
 	"leader-set" + cmdSuffix: NewLeaderSetCommand,
 }
 
+var secretCommands = map[string]creator{
+	"secret-add" + cmdSuffix:   NewSecretAddCommand,
+	"secret-get" + cmdSuffix:   NewSecretGetCommand,
+	"secret-grant" + cmdSuffix: NewSecretGrantCommand,
+	"secret-set" + cmdSuffix:   NewSecretSetCommand,
+}
+
 func allEnabledCommands() map[string]creator {
 	all := map[string]creator{}
 	add := func(m map[string]creator) {
@@ -85,6 +94,7 @@ func allEnabledCommands() map[string]creator {
 	add(baseCommands)
 	add(storageCommands)
 	add(leaderCommands)
+	add(secretCommands)
 	add(registeredCommands)
 	return all
 }
@@ -251,8 +251,10 @@ var newCommandTests = []struct {
 	name string
 	err  string
 }{
+	{"application-version-set", ""},
 	{"close-port", ""},
 	{"config-get", ""},
+	{"goal-state", ""},
 	{"juju-log", ""},
 	{"open-port", ""},
 	{"opened-ports", ""},
@@ -260,6 +262,10 @@ var newCommandTests = []struct {
 	{"relation-ids", ""},
 	{"relation-list", ""},
 	{"relation-set", ""},
+	{"secret-add", ""},
+	{"secret-get", ""},
+	{"secret-grant", ""},
+	{"secret-set", ""},
 	{"unit-get", ""},
 	{"storage-add", ""},
 	{"storage-get", ""},
@@ -20,6 +20,7 @@ type ContextInfo struct {
 	Storage
 	Components
 	Relations
+	Secrets
 	RelationHook
 	ActionHook
 }
@@ -59,6 +60,7 @@ type Context struct {
 	ContextStorage
 	ContextComponents
 	ContextRelations
+	ContextSecrets
 	ContextRelationHook
 	ContextActionHook
 }
@@ -84,6 +86,8 @@ func NewContext(stub *testing.Stub, info *ContextInfo) *Context {
 	ctx.ContextComponents.info = &info.Components
 	ctx.ContextRelations.stub = stub
 	ctx.ContextRelations.info = &info.Relations
+	ctx.ContextSecrets.stub = stub
+	ctx.ContextSecrets.info = &info.Secrets
 	ctx.ContextRelationHook.stub = stub
 	ctx.ContextRelationHook.info = &info.RelationHook
 	ctx.ContextActionHook.stub = stub
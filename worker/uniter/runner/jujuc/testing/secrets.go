@@ -0,0 +1,133 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Secret holds the values for a secret in the hook context.
+type Secret struct {
+	Description    string
+	Data           map[string]string
+	RotateInterval time.Duration
+	Grants         []string
+
+	// Revisions holds the content of previous revisions of the
+	// secret, keyed by revision.
+	Revisions map[int]map[string]string
+}
+
+// Secrets holds the values for the hook context.
+type Secrets struct {
+	Secrets map[string]*Secret
+}
+
+// SetSecret adds or replaces the secret with the given ID.
+func (s *Secrets) SetSecret(id string, secret *Secret) {
+	if s.Secrets == nil {
+		s.Secrets = make(map[string]*Secret)
+	}
+	s.Secrets[id] = secret
+}
+
+func (s *Secrets) secret(id string) (*Secret, error) {
+	secret, ok := s.Secrets[id]
+	if !ok {
+		return nil, errors.NotFoundf("secret %q", id)
+	}
+	return secret, nil
+}
+
+// ContextSecrets is a test double for jujuc.ContextSecrets.
+type ContextSecrets struct {
+	contextBase
+	info *Secrets
+}
+
+// AddSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) AddSecret(description string, data map[string]string, rotateInterval time.Duration) (string, error) {
+	c.stub.AddCall("AddSecret", description, data, rotateInterval)
+	if err := c.stub.NextErr(); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	id := strconv.Itoa(len(c.info.Secrets) + 1)
+	c.info.SetSecret(id, &Secret{
+		Description:    description,
+		Data:           data,
+		RotateInterval: rotateInterval,
+	})
+	return id, nil
+}
+
+// SecretValue implements jujuc.ContextSecrets.
+func (c *ContextSecrets) SecretValue(id string, revision int) (map[string]string, error) {
+	c.stub.AddCall("SecretValue", id, revision)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	secret, err := c.info.secret(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if revision == 0 {
+		return secret.Data, nil
+	}
+	data, ok := secret.Revisions[revision]
+	if !ok {
+		return nil, errors.NotFoundf("revision %d of secret %q", revision, id)
+	}
+	return data, nil
+}
+
+// UpdateSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) UpdateSecret(id string, data map[string]string) error {
+	c.stub.AddCall("UpdateSecret", id, data)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	secret, err := c.info.secret(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	secret.Data = data
+	return nil
+}
+
+// GrantSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) GrantSecret(id string, unitName string) error {
+	c.stub.AddCall("GrantSecret", id, unitName)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	secret, err := c.info.secret(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	secret.Grants = append(secret.Grants, unitName)
+	return nil
+}
+
+// GrantSecretToRelation implements jujuc.ContextSecrets.
+func (c *ContextSecrets) GrantSecretToRelation(id string, relationId int) error {
+	c.stub.AddCall("GrantSecretToRelation", id, relationId)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	secret, err := c.info.secret(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	secret.Grants = append(secret.Grants, fmt.Sprintf("relation:%d", relationId))
+	return nil
+}
@@ -12,6 +12,10 @@ import (
 	"github.com/juju/loggo"
 )
 
+// maxTailSize is the number of bytes kept from the end of a hook's
+// standard error output.
+const maxTailSize = 4096
+
 type hookLogger struct {
 	r       io.ReadCloser
 	done    chan struct{}
@@ -58,3 +62,73 @@ func (l *hookLogger) stop() {
 	l.stopped = true
 	l.mu.Unlock()
 }
+
+// stderrRelay copies a hook's standard error output to the pipe its
+// standard output is logged from, keeping the end of it. Both are
+// thus logged as a single stream, as they were before the tail was
+// kept.
+type stderrRelay struct {
+	r    io.ReadCloser
+	w    io.WriteCloser
+	done chan struct{}
+	mu   sync.Mutex
+	kept []byte
+}
+
+func (r *stderrRelay) run() {
+	defer close(r.done)
+	defer r.w.Close()
+	defer r.r.Close()
+	br := bufio.NewReaderSize(r.r, 4096)
+	for {
+		// Whole lines are relayed where possible, so that they
+		// are not split by output written to standard output.
+		chunk, err := br.ReadSlice('\n')
+		if len(chunk) > 0 {
+			r.mu.Lock()
+			r.kept = append(r.kept, chunk...)
+			if len(r.kept) > maxTailSize {
+				r.kept = r.kept[len(r.kept)-maxTailSize:]
+			}
+			r.mu.Unlock()
+			if _, err := r.w.Write(chunk); err != nil {
+				// The logger has stopped, so stop reading as
+				// it would have done.
+				return
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		} else if err != nil {
+			if err != io.EOF {
+				logger.Errorf("cannot read hook output: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// stop allows a moment for the output buffered in the pipe to be
+// relayed, as hookLogger.stop does.
+func (r *stderrRelay) stop() {
+	select {
+	case <-r.done:
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// tail returns the end of the output relayed.
+func (r *stderrRelay) tail() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return string(r.kept)
+}
+
+// stderrTail returns the end of the given output, as kept by a
+// stderrRelay.
+func stderrTail(output []byte) string {
+	if len(output) > maxTailSize {
+		output = output[len(output)-maxTailSize:]
+	}
+	return string(output)
+}
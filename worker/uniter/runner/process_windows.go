@@ -0,0 +1,19 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing on windows, where processes have no
+// process group to run in.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the given process. Any processes it started
+// are left running.
+func killProcessGroup(p *os.Process) error {
+	return p.Kill()
+}
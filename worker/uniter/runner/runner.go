@@ -8,6 +8,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -36,6 +38,20 @@ type Runner interface {
 
 	// RunCommands executes the supplied script.
 	RunCommands(commands string) (*utilexec.ExecResponse, error)
+
+	// ProcessResult describes how the process last run by the runner
+	// ended.
+	ProcessResult() ProcessResult
+}
+
+// ProcessResult describes how a process run by a Runner ended.
+type ProcessResult struct {
+	// ExitCode holds the exit code of the process, or -1 if it did
+	// not exit normally or was never started.
+	ExitCode int
+
+	// StderrTail holds the end of the process's standard error output.
+	StderrTail string
 }
 
 // Context exposes jujuc.Context, and additional methods needed by Runner.
@@ -47,6 +63,7 @@ type Context interface {
 	SetProcess(process context.HookProcess)
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
+	HookTimeout() time.Duration
 
 	Prepare() error
 	Flush(badge string, failure error) error
@@ -54,19 +71,29 @@ type Context interface {
 
 // NewRunner returns a Runner backed by the supplied context and paths.
 func NewRunner(context Context, paths context.Paths) Runner {
-	return &runner{context, paths}
+	return &runner{
+		context: context,
+		paths:   paths,
+		result:  ProcessResult{ExitCode: -1},
+	}
 }
 
 // runner implements Runner.
 type runner struct {
 	context Context
 	paths   context.Paths
+	result  ProcessResult
 }
 
 func (runner *runner) Context() Context {
 	return runner.context
 }
 
+// ProcessResult exists to satisfy the Runner interface.
+func (runner *runner) ProcessResult() ProcessResult {
+	return runner.result
+}
+
 // RunCommands exists to satisfy the Runner interface.
 func (runner *runner) RunCommands(commands string) (*utilexec.ExecResponse, error) {
 	srv, err := runner.startJujucServer()
@@ -93,6 +120,12 @@ func (runner *runner) RunCommands(commands string) (*utilexec.ExecResponse, erro
 
 	// Block and wait for process to finish
 	result, err := command.Wait()
+	if result != nil {
+		runner.result = ProcessResult{
+			ExitCode:   result.Code,
+			StderrTail: stderrTail(result.Stderr),
+		}
+	}
 	return result, runner.context.Flush("run commands", err)
 }
 
@@ -137,40 +170,119 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) e
 	return runner.context.Flush(hookName, err)
 }
 
+// hookTimeout returns how long the named hook may run: the limit the
+// charm declares for it, if any, or else the model's default.
+func (runner *runner) hookTimeout(hookName string) (time.Duration, error) {
+	timeouts, err := readHookTimeouts(runner.paths.GetCharmDir())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if timeout, ok := timeouts[hookName]; ok {
+		return timeout, nil
+	}
+	return runner.context.HookTimeout(), nil
+}
+
+// runCharmHook runs the named hook or action. Hooks that run for longer
+// than they are allowed to are killed, along with any processes they
+// started.
 func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string) error {
 	charmDir := runner.paths.GetCharmDir()
 	hook, err := searchHook(charmDir, filepath.Join(charmLocation, hookName))
 	if err != nil {
 		return err
 	}
+	var timeout time.Duration
+	if charmLocation == "hooks" {
+		if timeout, err = runner.hookTimeout(hookName); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	hookCmd := hookCommand(hook)
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
 	ps.Env = env
 	ps.Dir = charmDir
+	setProcessGroup(ps)
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
 		return errors.Errorf("cannot make logging pipe: %v", err)
 	}
+	errReader, errWriter, err := os.Pipe()
+	if err != nil {
+		outReader.Close()
+		outWriter.Close()
+		return errors.Errorf("cannot make logging pipe: %v", err)
+	}
 	ps.Stdout = outWriter
-	ps.Stderr = outWriter
+	ps.Stderr = errWriter
 	hookLogger := &hookLogger{
 		r:      outReader,
 		done:   make(chan struct{}),
 		logger: runner.getLogger(hookName),
 	}
+	// The hook's standard error output is relayed to the logging
+	// pipe, so that the end of it can be reported along with its
+	// result. The relay closes the logging pipe's writer once the
+	// hook's standard error is closed.
+	relay := &stderrRelay{
+		r:    errReader,
+		w:    outWriter,
+		done: make(chan struct{}),
+	}
 	go hookLogger.run()
+	go relay.run()
 	err = ps.Start()
-	outWriter.Close()
+	errWriter.Close()
 	if err == nil {
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(hookProcess{ps.Process})
 		// Block until execution finishes
-		err = ps.Wait()
+		err = waitHook(ps, hookName, timeout)
+		runner.result.ExitCode = exitCode(ps)
 	}
+	relay.stop()
 	hookLogger.stop()
+	runner.result.StderrTail = relay.tail()
 	return errors.Trace(err)
 }
 
+// exitCode returns the exit code of the given process, which has been
+// waited for, or -1 if it did not exit normally.
+func exitCode(ps *exec.Cmd) int {
+	if ps.ProcessState == nil {
+		return -1
+	}
+	status, ok := ps.ProcessState.Sys().(syscall.WaitStatus)
+	if !ok {
+		return -1
+	}
+	return status.ExitStatus()
+}
+
+// waitHook waits for the hook process to exit. If it has not exited
+// by the time the timeout expires, the hook's process group is killed
+// and a hook timeout error is returned.
+func waitHook(ps *exec.Cmd, hookName string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return ps.Wait()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- ps.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+	}
+	logger.Warningf("hook %q timed out after %v; killing it", hookName, timeout)
+	if err := killProcessGroup(ps.Process); err != nil {
+		logger.Errorf("cannot kill hook %q: %v", hookName, err)
+	}
+	<-done
+	return NewHookTimeoutError(hookName, timeout)
+}
+
 func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 	// Prepare server.
 	getCmd := func(ctxId, cmdName string) (cmd.Command, error) {
@@ -6,12 +6,14 @@ package runner_test
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	envtesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/proxy"
@@ -56,6 +58,8 @@ exit 42
 	c.Assert(strings.TrimRight(string(result.Stdout), "\r\n"), gc.Equals, paths.GetCharmDir())
 	c.Assert(strings.TrimRight(string(result.Stderr), "\r\n"), gc.Equals, "this is standard err")
 	c.Assert(ctx.GetProcess(), gc.NotNil)
+	c.Assert(runner.ProcessResult().ExitCode, gc.Equals, 42)
+	c.Assert(strings.TrimRight(runner.ProcessResult().StderrTail, "\r\n"), gc.Equals, "this is standard err")
 }
 
 type RunHookSuite struct {
@@ -151,6 +155,7 @@ type MockContext struct {
 	flushBadge   string
 	flushFailure error
 	flushResult  error
+	hookTimeout  time.Duration
 }
 
 func (ctx *MockContext) UnitName() string {
@@ -172,6 +177,10 @@ func (ctx *MockContext) SetProcess(process context.HookProcess) {
 	ctx.expectPid = process.Pid()
 }
 
+func (ctx *MockContext) HookTimeout() time.Duration {
+	return ctx.hookTimeout
+}
+
 func (ctx *MockContext) Prepare() error {
 	return nil
 }
@@ -237,6 +246,124 @@ func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunHookProcessResult(c *gc.C) {
+	ctx := &MockContext{}
+	makeCharm(c, hookSpec{
+		dir:    "hooks",
+		name:   hookName,
+		perm:   0700,
+		code:   123,
+		stdout: "not kept",
+		stderr: "kept",
+	}, s.paths.GetCharmDir())
+	rnr := runner.NewRunner(ctx, s.paths)
+	c.Assert(rnr.ProcessResult().ExitCode, gc.Equals, -1)
+	err := rnr.RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "exit status 123")
+	result := rnr.ProcessResult()
+	c.Assert(result.ExitCode, gc.Equals, 123)
+	c.Assert(strings.TrimRight(result.StderrTail, "\r\n"), gc.Equals, "kept")
+}
+
+func (s *RunMockContextSuite) TestRunHookLogsOutputInOrder(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("the hook is a bash script")
+	}
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("hook-output-tester", &tw, loggo.INFO), gc.IsNil)
+	defer loggo.RemoveWriter("hook-output-tester")
+	unitLogger := loggo.GetLogger("unit")
+	defer unitLogger.SetLogLevel(unitLogger.LogLevel())
+	unitLogger.SetLogLevel(loggo.INFO)
+
+	hooksDir := filepath.Join(s.paths.GetCharmDir(), "hooks")
+	err := os.Mkdir(hooksDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	script := "#!/bin/bash\necho one >&2\nsleep 0.1\necho two\nsleep 0.1\necho three >&2\n"
+	err = ioutil.WriteFile(filepath.Join(hooksDir, hookName), []byte(script), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := &MockContext{}
+	rnr := runner.NewRunner(ctx, s.paths)
+	err = rnr.RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushFailure, jc.ErrorIsNil)
+
+	// Standard output and standard error are logged as one stream,
+	// while only standard error is kept.
+	var logged []string
+	for _, entry := range tw.Log() {
+		if entry.Module == "unit.some-unit/999.something-happened" {
+			logged = append(logged, entry.Message)
+		}
+	}
+	c.Assert(logged, jc.DeepEquals, []string{"one", "two", "three"})
+	c.Assert(rnr.ProcessResult().StderrTail, gc.Equals, "one\nthree\n")
+}
+
+func (s *RunMockContextSuite) TestRunHookTimeout(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("the hook sleeps with a bash command")
+	}
+	ctx := &MockContext{hookTimeout: 100 * time.Millisecond}
+	makeCharm(c, hookSpec{
+		dir:   "hooks",
+		name:  hookName,
+		perm:  0700,
+		sleep: 10,
+	}, s.paths.GetCharmDir())
+	t0 := time.Now()
+	err := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(time.Since(t0) < 5*time.Second, jc.IsTrue)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" timed out after 100ms`)
+	c.Assert(runner.IsHookTimeoutError(errors.Cause(ctx.flushFailure)), jc.IsTrue)
+	s.assertRecordedPid(c, ctx.expectPid)
+	c.Assert(processExists(ctx.expectPid), jc.IsFalse)
+}
+
+func (s *RunMockContextSuite) TestRunHookCharmTimeout(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("the hook sleeps with a bash command")
+	}
+	// The charm's limit overrides the model's.
+	ctx := &MockContext{hookTimeout: time.Hour}
+	makeCharm(c, hookSpec{
+		dir:   "hooks",
+		name:  hookName,
+		perm:  0700,
+		sleep: 10,
+	}, s.paths.GetCharmDir())
+	err := ioutil.WriteFile(
+		filepath.Join(s.paths.GetCharmDir(), runner.HookTimeoutsFile),
+		[]byte("something-happened: 1\n"), 0644,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	t0 := time.Now()
+	err = runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(time.Since(t0) < 5*time.Second, jc.IsTrue)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" timed out after 1s`)
+}
+
+func (s *RunMockContextSuite) TestRunHookBadTimeouts(c *gc.C) {
+	ctx := &MockContext{}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	err := ioutil.WriteFile(
+		filepath.Join(s.paths.GetCharmDir(), runner.HookTimeoutsFile),
+		[]byte("something-happened: -1\n"), 0644,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook-timeouts.yaml: negative timeout -1 for hook "something-happened"`)
+}
+
 func (s *RunMockContextSuite) TestRunActionFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
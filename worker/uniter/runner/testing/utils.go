@@ -29,6 +29,7 @@ type RealPaths struct {
 	socket        string
 	metricsspool  string
 	componentDirs map[string]string
+	sharedDir     string
 	fops          fops
 }
 
@@ -47,6 +48,7 @@ func NewRealPaths(c *gc.C) RealPaths {
 		socket:        osDependentSockPath(c),
 		metricsspool:  c.MkDir(),
 		componentDirs: make(map[string]string),
+		sharedDir:     c.MkDir(),
 		fops:          c,
 	}
 }
@@ -75,6 +77,10 @@ func (p RealPaths) ComponentDir(name string) string {
 	return p.componentDirs[name]
 }
 
+func (p RealPaths) SharedComponentDir(name string) string {
+	return filepath.Join(p.sharedDir, name)
+}
+
 type StorageContextAccessor struct {
 	CStorage map[names.StorageTag]*ContextStorage
 }
@@ -0,0 +1,49 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v2"
+)
+
+// HookTimeoutsFile is the name of the optional file, in the charm
+// directory, in which a charm declares how long its hooks may run. It
+// maps hook names to a number of seconds, of which 0 means the hook
+// may run indefinitely, for example:
+//
+//     install: 1800
+//     db-relation-changed: 120
+//
+// Limits declared by the charm take precedence over the model's
+// hook-timeout setting.
+const HookTimeoutsFile = "hook-timeouts.yaml"
+
+// readHookTimeouts returns the hook timeouts declared by the charm in
+// the given directory.
+func readHookTimeouts(charmDir string) (map[string]time.Duration, error) {
+	data, err := ioutil.ReadFile(filepath.Join(charmDir, HookTimeoutsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var seconds map[string]int
+	if err := goyaml.Unmarshal(data, &seconds); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse %s", HookTimeoutsFile)
+	}
+	timeouts := make(map[string]time.Duration)
+	for hookName, v := range seconds {
+		if v < 0 {
+			return nil, errors.Errorf("%s: negative timeout %d for hook %q", HookTimeoutsFile, v, hookName)
+		}
+		timeouts[hookName] = time.Duration(v) * time.Second
+	}
+	return timeouts, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets provides the resolver that runs the secret-rotate
+// hook for the secrets owned by the unit's service.
+package secrets
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/remotestate"
+	"github.com/juju/juju/worker/uniter/resolver"
+)
+
+// secretsResolver is a Resolver that returns operations to run the
+// secret-rotate hook for secrets that are due to be rotated. When the
+// hook is committed, the "secretRotated" callback is invoked to record
+// the rotation.
+type secretsResolver struct {
+	secretRotated func(id string) error
+}
+
+// NewResolver returns a new Resolver that returns operations to run the
+// secret-rotate hook.
+//
+// The returned resolver's NextOp method will return an operation to run
+// the secret-rotate hook, for the first ID in the remote state's
+// "SecretsToRotate", while the unit is the leader and no other operation
+// is in progress. When the hook operation is committed, the ID of the
+// secret is passed to the "secretRotated" callback.
+func NewResolver(secretRotated func(string) error) resolver.Resolver {
+	return &secretsResolver{secretRotated}
+}
+
+// NextOp is part of the resolver.Resolver interface.
+func (s *secretsResolver) NextOp(
+	localState resolver.LocalState,
+	remoteState remotestate.Snapshot,
+	opFactory operation.Factory,
+) (operation.Operation, error) {
+	if !remoteState.Leader || len(remoteState.SecretsToRotate) == 0 {
+		return nil, resolver.ErrNoOperation
+	}
+	if localState.Kind != operation.Continue {
+		return nil, resolver.ErrNoOperation
+	}
+	id := remoteState.SecretsToRotate[0]
+	op, err := opFactory.NewRunHook(hook.Info{
+		Kind:     hook.SecretRotate,
+		SecretId: id,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	secretRotated := func() error {
+		return s.secretRotated(id)
+	}
+	return &rotateCommitter{op, secretRotated}, nil
+}
+
+type rotateCommitter struct {
+	operation.Operation
+	secretRotated func() error
+}
+
+func (c *rotateCommitter) Commit(st operation.State) (*operation.State, error) {
+	result, err := c.Operation.Commit(st)
+	if err == nil {
+		err = c.secretRotated()
+	}
+	return result, err
+}
@@ -0,0 +1,111 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/remotestate"
+	"github.com/juju/juju/worker/uniter/resolver"
+	"github.com/juju/juju/worker/uniter/secrets"
+)
+
+type resolverSuite struct {
+	opFactory *mockOpFactory
+	rotated   []string
+	resolver  resolver.Resolver
+}
+
+var _ = gc.Suite(&resolverSuite{})
+
+func (s *resolverSuite) SetUpTest(c *gc.C) {
+	s.opFactory = &mockOpFactory{}
+	s.rotated = nil
+	s.resolver = secrets.NewResolver(func(id string) error {
+		s.rotated = append(s.rotated, id)
+		return nil
+	})
+}
+
+var continueState = resolver.LocalState{
+	State: operation.State{Kind: operation.Continue},
+}
+
+func (s *resolverSuite) TestNoSecretsToRotate(c *gc.C) {
+	_, err := s.resolver.NextOp(continueState, remotestate.Snapshot{
+		Leader: true,
+	}, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *resolverSuite) TestNotLeader(c *gc.C) {
+	_, err := s.resolver.NextOp(continueState, remotestate.Snapshot{
+		SecretsToRotate: []string{"1"},
+	}, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *resolverSuite) TestOperationInProgress(c *gc.C) {
+	localState := resolver.LocalState{
+		State: operation.State{Kind: operation.RunHook, Step: operation.Pending},
+	}
+	_, err := s.resolver.NextOp(localState, remotestate.Snapshot{
+		Leader:          true,
+		SecretsToRotate: []string{"1"},
+	}, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *resolverSuite) TestRotateSecret(c *gc.C) {
+	op, err := s.resolver.NextOp(continueState, remotestate.Snapshot{
+		Leader:          true,
+		SecretsToRotate: []string{"1", "2"},
+	}, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.opFactory.hookInfo, jc.DeepEquals, []hook.Info{
+		{Kind: hook.SecretRotate, SecretId: "1"},
+	})
+	c.Assert(s.rotated, gc.HasLen, 0)
+
+	_, err = op.Commit(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.rotated, jc.DeepEquals, []string{"1"})
+}
+
+func (s *resolverSuite) TestCommitErrorNotRotated(c *gc.C) {
+	s.opFactory.commitErr = errors.New("Commit failed")
+	op, err := s.resolver.NextOp(continueState, remotestate.Snapshot{
+		Leader:          true,
+		SecretsToRotate: []string{"1"},
+	}, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = op.Commit(operation.State{})
+	c.Assert(err, gc.ErrorMatches, "Commit failed")
+	c.Assert(s.rotated, gc.HasLen, 0)
+}
+
+type mockOpFactory struct {
+	operation.Factory
+	hookInfo  []hook.Info
+	commitErr error
+}
+
+func (f *mockOpFactory) NewRunHook(hookInfo hook.Info) (operation.Operation, error) {
+	f.hookInfo = append(f.hookInfo, hookInfo)
+	return &mockOp{commitErr: f.commitErr}, nil
+}
+
+type mockOp struct {
+	operation.Operation
+	commitErr error
+}
+
+func (op *mockOp) Commit(operation.State) (*operation.State, error) {
+	return nil, op.commitErr
+}
@@ -21,7 +21,9 @@ import (
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/catacomb"
 	"github.com/juju/juju/worker/fortress"
@@ -30,13 +32,16 @@ import (
 	"github.com/juju/juju/worker/uniter/hook"
 	uniterleadership "github.com/juju/juju/worker/uniter/leadership"
 	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/payloads"
 	"github.com/juju/juju/worker/uniter/relation"
 	"github.com/juju/juju/worker/uniter/remotestate"
 	"github.com/juju/juju/worker/uniter/resolver"
 	"github.com/juju/juju/worker/uniter/runcommands"
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
+	"github.com/juju/juju/worker/uniter/runner/debug"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/secrets"
 	"github.com/juju/juju/worker/uniter/storage"
 	jujuos "github.com/juju/utils/os"
 )
@@ -48,6 +53,10 @@ const (
 	retryTimeMax    = 5 * time.Minute
 	retryTimeJitter = true
 	retryTimeFactor = 2
+
+	// triggeredHookPollInterval is how often the uniter checks for a
+	// debug session intercepting a triggered hook.
+	triggeredHookPollInterval = time.Second
 )
 
 // A UniterExecutionObserver gets the appropriate methods called when a hook
@@ -85,8 +94,17 @@ type Uniter struct {
 	leadershipTracker leadership.Tracker
 	charmDirGuard     fortress.Guard
 
+	dataDir  string
 	hookLock *fslock.Lock
 
+	// unitLock is held instead of hookLock while running hooks that
+	// do not need exclusive use of the machine, when the model's
+	// per-unit-hook-lock setting is enabled.
+	unitLock *fslock.Lock
+
+	// hookLockSetting tracks the model's per-unit-hook-lock setting.
+	hookLockSetting *hookLockSetting
+
 	// TODO(axw) move the runListener and run-command code outside of the
 	// uniter, and introduce a separate worker. Each worker would feed
 	// operations to a single, synchronized runner to execute.
@@ -101,6 +119,12 @@ type Uniter struct {
 	// updateStatusAt defines a function that will be used to generate signals for
 	// the update-status hook
 	updateStatusAt func() <-chan time.Time
+
+	// newPayloadMonitor starts the worker that runs the payloads'
+	// health checks; the full IDs of payloads that become unhealthy
+	// are sent on payloadUnhealthy.
+	newPayloadMonitor NewPayloadMonitorFunc
+	payloadUnhealthy  chan string
 }
 
 // UniterParams hold all the necessary parameters for a new Uniter.
@@ -114,13 +138,21 @@ type UniterParams struct {
 	UpdateStatusSignal   func() <-chan time.Time
 	NewOperationExecutor NewExecutorFunc
 	Clock                clock.Clock
+	// NewPayloadMonitor, if set, is used to start the worker that runs
+	// the health checks of the unit's payloads.
+	NewPayloadMonitor NewPayloadMonitorFunc
 	// TODO (mattyw, wallyworld, fwereade) Having the observer here make this approach a bit more legitimate, but it isn't.
 	// the observer is only a stop gap to be used in tests. A better approach would be to have the uniter tests start hooks
 	// that write to files, and have the tests watch the output to know that hooks have finished.
 	Observer UniterExecutionObserver
 }
 
-type NewExecutorFunc func(string, func() (*corecharm.URL, error), func(string) (func() error, error)) (operation.Executor, error)
+type NewExecutorFunc func(string, func() (*corecharm.URL, error), func(string, bool) (func() error, error)) (operation.Executor, error)
+
+// NewPayloadMonitorFunc returns a worker that runs the health checks of
+// the unit's payloads, sending the full ID of each payload that becomes
+// unhealthy on the supplied channel.
+type NewPayloadMonitorFunc func(unhealthy chan<- string) (worker.Worker, error)
 
 // NewUniter creates a new Uniter which will install, run, and upgrade
 // a charm on behalf of the unit with the given unitTag, by executing
@@ -129,6 +161,7 @@ func NewUniter(uniterParams *UniterParams) (*Uniter, error) {
 	u := &Uniter{
 		st:                   uniterParams.UniterFacade,
 		paths:                NewPaths(uniterParams.DataDir, uniterParams.UnitTag),
+		dataDir:              uniterParams.DataDir,
 		hookLock:             uniterParams.MachineLock,
 		leadershipTracker:    uniterParams.LeadershipTracker,
 		charmDirGuard:        uniterParams.CharmDirGuard,
@@ -136,6 +169,8 @@ func NewUniter(uniterParams *UniterParams) (*Uniter, error) {
 		newOperationExecutor: uniterParams.NewOperationExecutor,
 		observer:             uniterParams.Observer,
 		clock:                uniterParams.Clock,
+		newPayloadMonitor:    uniterParams.NewPayloadMonitor,
+		payloadUnhealthy:     make(chan string),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &u.catacomb,
@@ -221,12 +256,16 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		var err error
 		watcher, err = remotestate.NewWatcher(
 			remotestate.WatcherConfig{
-				State:               remotestate.NewAPIState(u.st),
-				LeadershipTracker:   u.leadershipTracker,
-				UnitTag:             unitTag,
-				UpdateStatusChannel: u.updateStatusAt,
-				CommandChannel:      u.commandChannel,
-				RetryHookChannel:    retryHookChan,
+				State:                   remotestate.NewAPIState(u.st),
+				LeadershipTracker:       u.leadershipTracker,
+				UnitTag:                 unitTag,
+				UpdateStatusChannel:     u.updateStatusAt,
+				CommandChannel:          u.commandChannel,
+				RetryHookChannel:        retryHookChan,
+				PayloadUnhealthyChannel: u.payloadUnhealthy,
+				TriggeredHookPollChannel: func() <-chan time.Time {
+					return u.clock.After(triggeredHookPollInterval)
+				},
 			})
 		if err != nil {
 			return errors.Trace(err)
@@ -257,6 +296,42 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		return nil
 	}
 
+	secretRotated := func(id string) error {
+		if err := u.unit.SecretRotated(id); err != nil {
+			return errors.Trace(err)
+		}
+		watcher.SecretRotated(id)
+		return nil
+	}
+
+	payloadUnhealthyHandled := func(id string) {
+		watcher.PayloadUnhealthyHandled(id)
+	}
+
+	debugctx := debug.NewHooksContext(u.unit.Name())
+	triggeredHookIntercepted := func(hookName string) bool {
+		session, _ := debugctx.FindSession()
+		return session != nil && session.MatchHook(hookName)
+	}
+
+	clearTriggeredHook := func() error {
+		if err := u.unit.ClearTriggeredHook(); err != nil {
+			return errors.Trace(err)
+		}
+		watcher.TriggeredHookRun()
+		return nil
+	}
+
+	if u.newPayloadMonitor != nil {
+		monitor, err := u.newPayloadMonitor(u.payloadUnhealthy)
+		if err != nil {
+			return errors.Annotate(err, "starting payload monitor")
+		}
+		if err := u.catacomb.Add(monitor); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	for {
 		if err = restartWatcher(); err != nil {
 			err = errors.Annotate(err, "(re)starting watcher")
@@ -276,6 +351,10 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 			Commands: runcommands.NewCommandsResolver(
 				u.commands, watcher.CommandCompleted,
 			),
+			Secrets:                  secrets.NewResolver(secretRotated),
+			Payloads:                 payloads.NewResolver(payloadUnhealthyHandled),
+			TriggeredHookIntercepted: triggeredHookIntercepted,
+			ClearTriggeredHook:       clearTriggeredHook,
 		})
 
 		// We should not do anything until there has been a change
@@ -381,6 +460,17 @@ func (u *Uniter) setupLocks() (err error) {
 			}
 		}
 	}
+	u.unitLock, err = cmdutil.UnitHookExecutionLock(u.dataDir, u.unit.Tag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Nothing but this unit's agent takes the unit's lock, so if it is
+	// held we must have died while holding it.
+	if u.unitLock.IsLocked() {
+		if err := u.unitLock.BreakLock(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -399,12 +489,33 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 	if err = u.setupLocks(); err != nil {
 		return err
 	}
+	// The per-unit-hook-lock setting is read before any operation can
+	// take the machine lock, and kept up to date from then on.
+	u.hookLockSetting = &hookLockSetting{st: u.st}
+	if err := u.hookLockSetting.Handle(nil); err != nil {
+		return errors.Trace(err)
+	}
+	hookLockSettingWorker, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: u.hookLockSetting,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := u.catacomb.Add(hookLockSettingWorker); err != nil {
+		return errors.Trace(err)
+	}
 	if err := jujuc.EnsureSymlinks(u.paths.ToolsDir); err != nil {
 		return err
 	}
 	if err := os.MkdirAll(u.paths.State.RelationsDir, 0755); err != nil {
 		return errors.Trace(err)
 	}
+	// The local state files must be restored, if they are to be, before
+	// the relations, storage and operation state are read from them.
+	agentState := newAgentState(u.unit, u.paths.State)
+	if err := agentState.restore(u.unit.CharmURL); err != nil {
+		return errors.Annotate(err, "cannot restore agent state")
+	}
 	relations, err := relation.NewRelations(
 		u.st, unitTag, u.paths.State.CharmDir,
 		u.paths.State.RelationsDir, u.catacomb.Dying(),
@@ -451,13 +562,17 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		StorageUpdater: u.storage,
 		Abort:          u.catacomb.Dying(),
 		MetricSpoolDir: u.paths.GetMetricsSpoolDir(),
+		CharmDir:       u.paths.GetCharmDir(),
 	})
 
 	operationExecutor, err := u.newOperationExecutor(u.paths.State.OperationsFile, u.getServiceCharmURL, u.acquireExecutionLock)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	u.operationExecutor = operationExecutor
+	u.operationExecutor = &agentStateExecutor{operationExecutor, agentState}
+	if err := agentState.save(); err != nil {
+		return errors.Trace(err)
+	}
 
 	logger.Debugf("starting juju-run listener on unix:%s", u.paths.Runtime.JujuRunSocket)
 	commandRunner, err := NewChannelCommandRunner(ChannelCommandRunnerConfig{
@@ -518,7 +633,12 @@ func (u *Uniter) RunCommands(args RunCommandsArgs) (results *exec.ExecResponse,
 // acquireExecutionLock acquires the machine-level execution lock, and
 // returns a func that must be called to unlock it. It's used by operation.Executor
 // when running operations that execute external code.
-func (u *Uniter) acquireExecutionLock(message string) (func() error, error) {
+//
+// When the model's per-unit-hook-lock setting is enabled, operations that
+// do not need exclusive use of the machine hold only the unit's own lock
+// while they run, so that the hooks of different units on the machine can
+// run concurrently; exclusive operations wait for those to finish.
+func (u *Uniter) acquireExecutionLock(message string, exclusive bool) (func() error, error) {
 	logger.Debugf("lock: %v", message)
 	// We want to make sure we don't block forever when locking, but take the
 	// Uniter's catacomb into account.
@@ -534,6 +654,28 @@ func (u *Uniter) acquireExecutionLock(message string) (func() error, error) {
 	if err := u.hookLock.LockWithFunc(message, checkCatacomb); err != nil {
 		return nil, err
 	}
+	if !exclusive && u.hookLockSetting.PerUnit() {
+		// Holding the machine lock guarantees that no exclusive
+		// operation is running, and none can start until it is
+		// released; once we hold the unit's lock, they will wait
+		// for us to finish.
+		if err := u.unitLock.Lock(message); err != nil {
+			u.hookLock.Unlock()
+			return nil, errors.Trace(err)
+		}
+		if err := u.hookLock.Unlock(); err != nil {
+			u.unitLock.Unlock()
+			return nil, errors.Trace(err)
+		}
+		return func() error {
+			logger.Debugf("unlock: %v", message)
+			return u.unitLock.Unlock()
+		}, nil
+	}
+	if err := cmdutil.WaitUnitHookExecutions(u.dataDir, cmdutil.DefaultUnitHookExecutionsTimeout, checkCatacomb); err != nil {
+		u.hookLock.Unlock()
+		return nil, err
+	}
 	return func() error {
 		logger.Debugf("unlock: %v", message)
 		return u.hookLock.Unlock()
@@ -559,5 +701,9 @@ func (u *Uniter) reportHookError(hookInfo hook.Info) error {
 	}
 	statusData["hook"] = hookName
 	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
+	if u.operationExecutor.State().HookTimedOut {
+		statusData["timed-out"] = true
+		statusMessage = fmt.Sprintf("hook timed out: %q", hookName)
+	}
 	return setAgentStatus(u, params.StatusError, statusMessage, statusData)
 }
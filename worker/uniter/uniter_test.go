@@ -218,6 +218,54 @@ func (s *UniterSuite) TestUniterInstallHook(c *gc.C) {
 	})
 }
 
+func (s *UniterSuite) TestUniterHookTimeout(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("the slow hook is a bash script")
+	}
+	slowInstall := func(c *gc.C, ctx *context, path string) {
+		ctx.writeExplicitHook(c, filepath.Join(path, "hooks", "install"), `
+#!/bin/bash --norc
+juju-log $JUJU_MODEL_UUID slow-install $JUJU_REMOTE_UNIT
+sleep 60
+`[1:])
+		err := ioutil.WriteFile(filepath.Join(path, "hook-timeouts.yaml"), []byte("install: 1\n"), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.runUniterTests(c, []uniterTest{
+		ut(
+			"install hook timeout and retry",
+			createCharm{customize: slowInstall},
+			serveCharm{},
+			createUniter{},
+			waitUnitAgent{
+				statusGetter: unitStatusGetter,
+				status:       params.StatusError,
+				info:         `hook timed out: "install"`,
+				data: map[string]interface{}{
+					"hook":      "install",
+					"timed-out": true,
+				},
+			},
+			waitHooks{"slow-install"},
+			verifyWaiting{},
+
+			fixHook{"install"},
+			resolveError{state.ResolvedRetryHooks},
+			waitUnitAgent{
+				status: params.StatusIdle,
+			},
+			waitHooks{"install", "leader-elected", "config-changed", "start"},
+			waitHookHistory{
+				{Name: "start", Result: "completed"},
+				{Name: "config-changed", Result: "completed"},
+				{Name: "leader-elected", Result: "completed"},
+				{Name: "install", Result: "completed"},
+				{Name: "install", Result: "timed-out"},
+			},
+		),
+	})
+}
+
 func (s *UniterSuite) TestUniterUpdateStatusHook(c *gc.C) {
 	s.runUniterTests(c, []uniterTest{
 		ut(
@@ -455,6 +503,23 @@ func (s *UniterSuite) TestUniterHookSynchronisation(c *gc.C) {
 			releaseHookSyncLock,
 			waitUnitAgent{status: params.StatusIdle},
 			waitHooks{"install", "leader-elected", "config-changed", "start"},
+		), ut(
+			"verify hooks run while another unit's lock is held, with per-unit locks",
+			setPerUnitHookLock(true),
+			quickStart{},
+			acquireUnitHookSyncLock{"u/1"},
+			changeConfig{"blog-title": "Goodness Gracious Me"},
+			waitHooks{"config-changed"},
+			verifyUnitHookSyncLockUnlocked{"u/0"},
+			releaseUnitHookSyncLock{"u/1"},
+		), ut(
+			"verify config change hook not run while another unit's lock is held",
+			quickStart{},
+			acquireUnitHookSyncLock{"u/1"},
+			changeConfig{"blog-title": "Goodness Gracious Me"},
+			waitHooks{},
+			releaseUnitHookSyncLock{"u/1"},
+			waitHooks{"config-changed"},
 		),
 	})
 }
@@ -2074,7 +2139,7 @@ func (m *mockExecutor) Run(op operation.Operation) error {
 }
 
 func (s *UniterSuite) TestOperationErrorReported(c *gc.C) {
-	executorFunc := func(stateFilePath string, getInstallCharm func() (*corecharm.URL, error), acquireLock func(string) (func() error, error)) (operation.Executor, error) {
+	executorFunc := func(stateFilePath string, getInstallCharm func() (*corecharm.URL, error), acquireLock func(string, bool) (func() error, error)) (operation.Executor, error) {
 		e, err := operation.NewExecutor(stateFilePath, getInstallCharm, acquireLock)
 		c.Assert(err, jc.ErrorIsNil)
 		return &mockExecutor{e}, nil
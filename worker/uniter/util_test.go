@@ -33,8 +33,10 @@ import (
 
 	apiuniter "github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/core/leadership"
 	coreleadership "github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/sockets"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
@@ -1263,6 +1265,34 @@ func (s chmod) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+// waitHookHistory waits until the unit's hook history holds hooks with
+// the given names and results, most recent first.
+type waitHookHistory []state.HookExecution
+
+func (s waitHookHistory) step(c *gc.C, ctx *context) {
+	timeout := time.After(worstCase)
+	for {
+		executions, err := ctx.unit.HookHistory(state.HookHistoryFilter{
+			Kind:  "hook",
+			Limit: len(s),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		got := make(waitHookHistory, len(executions))
+		for i, execution := range executions {
+			got[i] = state.HookExecution{Name: execution.Name, Result: execution.Result}
+		}
+		if reflect.DeepEqual(got, s) {
+			return
+		}
+		select {
+		case <-time.After(coretesting.ShortWait):
+			c.Logf("want hook history %v, got %v; still waiting", s, got)
+		case <-timeout:
+			c.Fatalf("never got expected hook history %v", s)
+		}
+	}
+}
+
 type custom struct {
 	f func(*gc.C, *context)
 }
@@ -1367,6 +1397,53 @@ var verifyHookSyncLockLocked = custom{func(c *gc.C, ctx *context) {
 	c.Assert(lock.IsLocked(), jc.IsTrue)
 }}
 
+func createUnitHookLock(c *gc.C, dataDir, unitName string) *fslock.Lock {
+	lock, err := cmdutil.UnitHookExecutionLock(dataDir, names.NewUnitTag(unitName))
+	c.Assert(err, jc.ErrorIsNil)
+	return lock
+}
+
+type acquireUnitHookSyncLock struct {
+	unitName string
+}
+
+func (s acquireUnitHookSyncLock) step(c *gc.C, ctx *context) {
+	lock := createUnitHookLock(c, ctx.dataDir, s.unitName)
+	c.Assert(lock.IsLocked(), jc.IsFalse)
+	err := lock.Lock("fake")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type releaseUnitHookSyncLock struct {
+	unitName string
+}
+
+func (s releaseUnitHookSyncLock) step(c *gc.C, ctx *context) {
+	lock := createUnitHookLock(c, ctx.dataDir, s.unitName)
+	// Force the release.
+	err := lock.BreakLock()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type verifyUnitHookSyncLockUnlocked struct {
+	unitName string
+}
+
+func (s verifyUnitHookSyncLockUnlocked) step(c *gc.C, ctx *context) {
+	lock := createUnitHookLock(c, ctx.dataDir, s.unitName)
+	c.Assert(lock.IsLocked(), jc.IsFalse)
+}
+
+type setPerUnitHookLock bool
+
+func (s setPerUnitHookLock) step(c *gc.C, ctx *context) {
+	attrs := map[string]interface{}{
+		config.PerUnitHookLockKey: bool(s),
+	}
+	err := ctx.st.UpdateModelConfig(attrs, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type setProxySettings proxy.Settings
 
 func (s setProxySettings) step(c *gc.C, ctx *context) {